@@ -1,17 +1,113 @@
 package main
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/coverage"
+	"github.com/lambdaclass/cairo-vm.go/pkg/debugger"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 	"github.com/urfave/cli/v2"
 )
 
-func handleCommands(ctx *cli.Context) error {
-	programPath := ctx.Args().First()
+// Path standing for stdin as the program to run & for stdout as the file an artifact is written to
+const stdioPath = "-"
+
+// Runs the program at path, reading it from stdin if the path is stdioPath
+func runProgramPath(path string, config cairo_run.CairoRunConfig) (*runners.CairoRunner, error) {
+	if path != stdioPath {
+		return cairo_run.CairoRun(path, config)
+	}
+	programJson, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		return nil, err
+	}
+	return cairo_run.CairoRunBytes(programJson, config)
+}
+
+// File an artifact (ie: the trace) is written to, writes are buffered until it's closed
+type artifactFile struct {
+	*bufio.Writer
+	file *os.File
+}
+
+// Creates the file at path, or writes to stdout if the path is stdioPath
+// Pipes can be used as paths too
+func createArtifact(path string) (*artifactFile, error) {
+	if path == stdioPath {
+		return &artifactFile{Writer: bufio.NewWriter(os.Stdout), file: os.Stdout}, nil
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	return &artifactFile{Writer: bufio.NewWriter(file), file: file}, nil
+}
 
+// Flushes the buffered writes, closing the file unless it's stdout
+func (a *artifactFile) Close() error {
+	err := a.Flush()
+	if a.file != os.Stdout {
+		if closeErr := a.file.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// Creates the artifact at path & writes it with write, nothing is written if the path is empty
+func writeArtifact(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return nil
+	}
+	artifact, err := createArtifact(path)
+	if err != nil {
+		return err
+	}
+	if err := write(artifact); err != nil {
+		artifact.Close()
+		return err
+	}
+	return artifact.Close()
+}
+
+// Returns the path of an artifact of the run, from its flag or next to the program file
+// Programs read from stdin have no default path, so their artifacts are only written if requested
+func artifactPath(ctx *cli.Context, flag string, extension string) string {
+	if path := ctx.String(flag); path != "" {
+		return path
+	}
+	if programPath := ctx.Args().First(); programPath != stdioPath {
+		return strings.Replace(programPath, ".json", extension, 1)
+	}
+	return ""
+}
+
+// Builds the run configuration from the run flags present in the context
+func runConfig(ctx *cli.Context) cairo_run.CairoRunConfig {
 	layout := ctx.String("layout")
 	if layout == "" {
 		layout = "plain"
@@ -24,62 +120,958 @@ func handleCommands(ctx *cli.Context) error {
 		secureRun = true
 	}
 
-	cairoRunConfig := cairo_run.CairoRunConfig{DisableTracePadding: false, ProofMode: proofMode, Layout: layout, SecureRun: secureRun}
+	config := cairo_run.CairoRunConfig{DisableTracePadding: false, ProofMode: proofMode, Layout: layout, SecureRun: secureRun, StreamTrace: ctx.Bool("stream_trace"), RelocationWorkers: ctx.Int("relocation_workers"), Entrypoint: ctx.String("entrypoint"), CompatErrors: ctx.Bool("compat_errors")}
+	config.Timeout = ctx.Duration("timeout")
+	if cacheDir := ctx.String("program_cache_dir"); cacheDir != "" {
+		config.ProgramCache = vm.NewProgramCache(cacheDir)
+	}
+	if ctx.Bool("hint_stats") {
+		config.HintStats = hints.NewHintStats()
+	}
+	if ctx.Bool("report_unknown_hints") {
+		config.UnknownHints = hints.NewUnknownHintReport()
+	}
+	return config
+}
+
+// Runs the program given as first argument using the run flags present in the context
+// If requested, the execution metrics are written to stderr once the run is over
+func runProgram(ctx *cli.Context) (*runners.CairoRunner, error) {
+	return runProgramWithConfig(ctx, runConfig(ctx))
+}
+
+// Same as runProgram, with a config based on the run flags (ie: with observers added to it)
+func runProgramWithConfig(ctx *cli.Context, config cairo_run.CairoRunConfig) (*runners.CairoRunner, error) {
+	var eventsTracer *vm.JsonlTracer
+	var eventsFile *artifactFile
+	if eventsFilePath := ctx.String("events_file"); eventsFilePath != "" {
+		var err error
+		eventsFile, err = createArtifact(eventsFilePath)
+		if err != nil {
+			return nil, err
+		}
+		eventsTracer = vm.NewJsonlTracer(eventsFile)
+		config.Tracer = eventsTracer
+	}
+
+	start := time.Now()
+	cairoRunner, err := runProgramPath(ctx.Args().First(), config)
+	if eventsTracer != nil {
+		if flushErr := eventsTracer.Flush(); flushErr != nil && err == nil {
+			err = flushErr
+		}
+		if closeErr := eventsFile.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}
+	if ctx.Bool("metrics") && cairoRunner != nil {
+		writeRunMetrics(os.Stderr, cairoRunner.Vm.CurrentStep, time.Since(start), cairoRunner.Vm.Temporaries.Stats())
+	}
+	if ctx.Bool("summary") && err == nil {
+		summary, summaryErr := cairoRunner.Summary()
+		if summaryErr != nil {
+			return cairoRunner, summaryErr
+		}
+		fmt.Fprint(os.Stderr, summary)
+	}
+	if config.HintStats != nil {
+		writeHintStats(os.Stderr, config.HintStats)
+	}
+	if config.UnknownHints != nil && config.UnknownHints.Len() != 0 {
+		var program *vm.Program
+		if cairoRunner != nil {
+			program = &cairoRunner.Program
+		}
+		writeUnknownHints(os.Stderr, config.UnknownHints, program)
+		// The skipped hints had no effect, so the run can't be trusted even if it succeeded
+		if err == nil {
+			err = fmt.Errorf("%d unknown hint(s) were skipped, the results of the run are not valid", config.UnknownHints.Len())
+		}
+	}
+	return cairoRunner, err
+}
+
+func writeRunMetrics(dest io.Writer, steps uint, duration time.Duration, poolStats vm.ValuePoolStats) {
+	stepRate := float64(steps) / duration.Seconds()
+	fmt.Fprintf(dest, "steps: %d\ntime: %s\nstep rate: %.0f steps/s\n", steps, duration.Round(time.Microsecond), stepRate)
+	fmt.Fprintf(dest, "temporary values: %d (peak per step: %d, pool capacity: %d)\n", poolStats.Allocations, poolStats.PeakStepAllocations, poolStats.Capacity)
+}
+
+// Writes the hint statistics as a table of hint, executions & time, sorted by decreasing time
+// Only the first line of each hint's code is written
+func writeHintStats(dest io.Writer, hintStats *hints.HintStats) {
+	writer := tabwriter.NewWriter(dest, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "hint\texecutions\ttime")
+	for _, stat := range hintStats.Stats() {
+		code, _, multiline := strings.Cut(stat.Code, "\n")
+		if multiline {
+			code += " ..."
+		}
+		if stat.Unknown {
+			code = "(unknown) " + code
+		}
+		fmt.Fprintf(writer, "%s\t%d\t%s\n", code, stat.Count, stat.Duration.Round(time.Microsecond))
+	}
+	writer.Flush()
+	if unknownHints := hintStats.UnknownHints(); len(unknownHints) != 0 {
+		fmt.Fprintf(dest, "%d unknown hint(s) encountered\n", len(unknownHints))
+	}
+}
+
+// Returns the source location of the instruction at pc (ie: main.cairo:12), or an empty string if it's unknown
+func instructionLocation(program *vm.Program, pc memory.Relocatable) string {
+	if program == nil || pc.SegmentIndex != 0 {
+		return ""
+	}
+	location, ok := program.InstructionLocations[pc.Offset]
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", location.Inst.InputFile["filename"], location.Inst.StartLine)
+}
+
+// Writes the unknown hints found during the run, with the pcs (and source locations, if known) they were found at,
+// the number of times they were reached & their code
+func writeUnknownHints(dest io.Writer, report *hints.UnknownHintReport, program *vm.Program) {
+	unknownHints := report.Hints()
+	fmt.Fprintf(dest, "%d unknown hint(s) found:\n", len(unknownHints))
+	for _, hint := range unknownHints {
+		positions := make([]string, 0, len(hint.Pcs))
+		for _, pc := range hint.Pcs {
+			position := pc.ToString()
+			if location := instructionLocation(program, pc); location != "" {
+				position += " (" + location + ")"
+			}
+			positions = append(positions, position)
+		}
+		fmt.Fprintf(dest, "\npc %s, reached %d time(s):\n", strings.Join(positions, ", "), hint.Count)
+		for _, line := range strings.Split(hint.Code, "\n") {
+			fmt.Fprintf(dest, "    %s\n", line)
+		}
+	}
+}
+
+// Collects the Go profiles requested through the profiling flags while a command runs
+type profiler struct {
+	cpuProfile   *os.File
+	runtimeTrace *os.File
+}
+
+// Sets up the logger used by the vm & starts the profiling requested through the flags
+func setup(ctx *cli.Context, p *profiler) error {
+	logLevel, err := logging.ParseLevel(ctx.String("log_level"))
+	if err != nil {
+		return err
+	}
+	logging.SetDefault(logging.NewLogger(os.Stderr, logLevel))
+	return p.start(ctx)
+}
+
+func (p *profiler) start(ctx *cli.Context) error {
+	if path := ctx.String("cpuprofile"); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := pprof.StartCPUProfile(file); err != nil {
+			file.Close()
+			return err
+		}
+		p.cpuProfile = file
+	}
+	if path := ctx.String("trace"); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err := trace.Start(file); err != nil {
+			file.Close()
+			return err
+		}
+		p.runtimeTrace = file
+	}
+	return nil
+}
+
+func (p *profiler) stop(ctx *cli.Context) error {
+	if p.cpuProfile != nil {
+		pprof.StopCPUProfile()
+		p.cpuProfile.Close()
+	}
+	if p.runtimeTrace != nil {
+		trace.Stop()
+		p.runtimeTrace.Close()
+	}
+	if path := ctx.String("memprofile"); path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		runtime.GC()
+		return pprof.WriteHeapProfile(file)
+	}
+	return nil
+}
+
+// Structured result of a run, emitted when the output format is json
+type jsonRunResult struct {
+	Success            bool                    `json:"success"`
+	Error              *jsonRunError           `json:"error,omitempty"`
+	ExecutionResources *jsonExecutionResources `json:"execution_resources,omitempty"`
+	Output             []string                `json:"output"`
+	TraceFile          string                  `json:"trace_file,omitempty"`
+	MemoryFile         string                  `json:"memory_file,omitempty"`
+	// Only present if hint statistics were collected
+	HintStats    []jsonHintStat `json:"hint_stats,omitempty"`
+	UnknownHints []string       `json:"unknown_hints,omitempty"`
+	// Only present if unknown hints were reported instead of failing the run
+	UnknownHintReport []jsonUnknownHint `json:"unknown_hint_report,omitempty"`
+}
+
+type jsonUnknownHint struct {
+	Code      string   `json:"code"`
+	Pcs       []string `json:"pcs"`
+	Locations []string `json:"locations,omitempty"`
+	Count     uint     `json:"count"`
+}
+
+// Adds the unknown hints to the json result, if they were reported
+func (result *jsonRunResult) addUnknownHints(report *hints.UnknownHintReport, cairoRunner *runners.CairoRunner) {
+	if report == nil {
+		return
+	}
+	var program *vm.Program
+	if cairoRunner != nil {
+		program = &cairoRunner.Program
+	}
+	result.UnknownHintReport = []jsonUnknownHint{}
+	for _, hint := range report.Hints() {
+		encoded := jsonUnknownHint{Code: hint.Code, Pcs: make([]string, 0, len(hint.Pcs)), Count: hint.Count}
+		for _, pc := range hint.Pcs {
+			encoded.Pcs = append(encoded.Pcs, pc.ToString())
+			if location := instructionLocation(program, pc); location != "" {
+				encoded.Locations = append(encoded.Locations, location)
+			}
+		}
+		result.UnknownHintReport = append(result.UnknownHintReport, encoded)
+	}
+}
+
+type jsonHintStat struct {
+	Code    string `json:"code"`
+	Count   uint   `json:"count"`
+	TimeNs  int64  `json:"time_ns"`
+	Unknown bool   `json:"unknown,omitempty"`
+}
 
-	cairoRunner, err := cairo_run.CairoRun(programPath, cairoRunConfig)
+// Adds the hint statistics to the json result, if they were collected
+func (result *jsonRunResult) addHintStats(hintStats *hints.HintStats) {
+	if hintStats == nil {
+		return
+	}
+	result.HintStats = []jsonHintStat{}
+	for _, stat := range hintStats.Stats() {
+		result.HintStats = append(result.HintStats, jsonHintStat{Code: stat.Code, Count: stat.Count, TimeNs: stat.Duration.Nanoseconds(), Unknown: stat.Unknown})
+	}
+	result.UnknownHints = hintStats.UnknownHints()
+}
+
+type jsonRunError struct {
+	// One of load_error, initialization_error, execution_error or finalization_error, depending on how far the run got
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Pc at which the execution failed, only present for execution errors
+	Pc string `json:"pc,omitempty"`
+}
+
+type jsonExecutionResources struct {
+	NSteps                  uint            `json:"n_steps"`
+	NMemoryHoles            uint            `json:"n_memory_holes"`
+	BuiltinsInstanceCounter map[string]uint `json:"builtin_instance_counter"`
+}
+
+// Builds the json result for a run that failed with runErr
+// The runner may be nil if the run failed before it was created
+func jsonRunFailure(cairoRunner *runners.CairoRunner, runErr error) jsonRunResult {
+	runError := jsonRunError{Code: "load_error", Message: runErr.Error()}
+	if cairoRunner != nil {
+		if !cairoRunner.VmInitialized {
+			runError.Code = "initialization_error"
+		} else if cairoRunner.RunEnded {
+			runError.Code = "finalization_error"
+		} else {
+			runError.Code = "execution_error"
+			runError.Pc = cairoRunner.Vm.RunContext.Pc.ToString()
+		}
+	}
+	return jsonRunResult{Success: false, Error: &runError, Output: []string{}}
+}
+
+// Builds the json result for a successful run
+func jsonRunSuccess(cairoRunner *runners.CairoRunner, traceFilePath string, memoryFilePath string) (jsonRunResult, error) {
+	resources, err := cairoRunner.GetExecutionResources()
+	if err != nil {
+		return jsonRunResult{}, err
+	}
+	var outputBuffer bytes.Buffer
+	if err := cairoRunner.WriteOutput(&outputBuffer); err != nil {
+		return jsonRunResult{}, err
+	}
+	output := []string{}
+	if outputBuffer.Len() != 0 {
+		output = strings.Split(strings.TrimSuffix(outputBuffer.String(), "\n"), "\n")
+	}
+	return jsonRunResult{
+		Success: true,
+		ExecutionResources: &jsonExecutionResources{
+			NSteps:                  resources.NSteps,
+			NMemoryHoles:            resources.NMemoryHoles,
+			BuiltinsInstanceCounter: resources.BuiltinsInstanceCounter,
+		},
+		Output:     output,
+		TraceFile:  traceFilePath,
+		MemoryFile: memoryFilePath,
+	}, nil
+}
+
+func writeJsonRunResult(dest io.Writer, result jsonRunResult) error {
+	encoder := json.NewEncoder(dest)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(result)
+}
+
+func handleCommands(ctx *cli.Context) error {
+	outputFormat := ctx.String("output_format")
+	if outputFormat != "text" && outputFormat != "json" {
+		return fmt.Errorf("Invalid output format %s, expected one of: text, json", outputFormat)
+	}
+	encoding, err := cairo_run.ParseEncodingOptions(ctx.String("byte_order"))
 	if err != nil {
 		return err
 	}
+	traceFilePath := artifactPath(ctx, "trace_file", ".go.trace")
+	memoryFilePath := artifactPath(ctx, "memory_file", ".go.memory")
+	stdoutArtifacts := 0
+	for _, path := range []string{traceFilePath, memoryFilePath, ctx.String("events_file")} {
+		if path == stdioPath {
+			stdoutArtifacts++
+		}
+	}
+	if stdoutArtifacts > 1 || (stdoutArtifacts == 1 && (outputFormat == "json" || ctx.Bool("print_output"))) {
+		return errors.New("Only one of the artifacts, the json result & the program output can be written to stdout")
+	}
 
-	traceFilePath := ctx.String("trace_file")
-	if traceFilePath == "" {
-		traceFilePath = strings.Replace(programPath, ".json", ".go.trace", 1)
+	config := runConfig(ctx)
+	cairoRunner, err := runProgramWithConfig(ctx, config)
+	if cairoRunner != nil && cairoRunner.Vm.StreamedTrace != nil {
+		defer cairoRunner.Vm.StreamedTrace.Close()
+	}
+	if err != nil {
+		if outputFormat == "json" {
+			result := jsonRunFailure(cairoRunner, err)
+			result.addHintStats(config.HintStats)
+			result.addUnknownHints(config.UnknownHints, cairoRunner)
+			if jsonErr := writeJsonRunResult(os.Stdout, result); jsonErr != nil {
+				return jsonErr
+			}
+		}
+		return err
 	}
-	traceFile, err := os.OpenFile(traceFilePath, os.O_RDWR|os.O_CREATE, 0644)
-	defer traceFile.Close()
 
-	memoryFilePath := ctx.String("memory_file")
-	if memoryFilePath == "" {
-		memoryFilePath = strings.Replace(programPath, ".json", ".go.memory", 1)
+	err = writeArtifact(traceFilePath, func(dest io.Writer) error {
+		return cairo_run.WriteVmEncodedTraceWithOptions(&cairoRunner.Vm, dest, encoding)
+	})
+	if err != nil {
+		return err
+	}
+	err = writeArtifact(memoryFilePath, func(dest io.Writer) error {
+		return cairo_run.WriteEncodedMemoryWithOptions(cairoRunner.Vm.RelocatedMemory, dest, encoding)
+	})
+	if err != nil {
+		return err
 	}
-	memoryFile, err := os.OpenFile(memoryFilePath, os.O_RDWR|os.O_CREATE, 0644)
-	defer memoryFile.Close()
 
-	cairo_run.WriteEncodedTrace(cairoRunner.Vm.RelocatedTrace, traceFile)
-	cairo_run.WriteEncodedMemory(cairoRunner.Vm.RelocatedMemory, memoryFile)
+	if outputFormat == "json" {
+		result, err := jsonRunSuccess(cairoRunner, traceFilePath, memoryFilePath)
+		if err != nil {
+			return err
+		}
+		result.addHintStats(config.HintStats)
+		result.addUnknownHints(config.UnknownHints, cairoRunner)
+		return writeJsonRunResult(os.Stdout, result)
+	}
+	if ctx.Bool("print_output") {
+		fmt.Println("Program Output:")
+		return cairoRunner.WriteOutput(os.Stdout)
+	}
+	return nil
+}
+
+// Parses a range of the form "start:end", where both bounds are optional
+// The start is inclusive and the end exclusive, a nil end means there is no upper bound
+func parseRange(rangeStr string) (uint, *uint, error) {
+	if rangeStr == "" {
+		return 0, nil, nil
+	}
+	startStr, endStr, found := strings.Cut(rangeStr, ":")
+	if !found {
+		return 0, nil, fmt.Errorf("Invalid range %s, expected format: <start>:<end>", rangeStr)
+	}
+	var start uint
+	var end *uint
+	if startStr != "" {
+		value, err := strconv.ParseUint(startStr, 10, 0)
+		if err != nil {
+			return 0, nil, fmt.Errorf("Invalid range start %s: %s", startStr, err)
+		}
+		start = uint(value)
+	}
+	if endStr != "" {
+		value, err := strconv.ParseUint(endStr, 10, 0)
+		if err != nil {
+			return 0, nil, fmt.Errorf("Invalid range end %s: %s", endStr, err)
+		}
+		end = new(uint)
+		*end = uint(value)
+	}
+	return start, end, nil
+}
+
+func handleTraceCommand(ctx *cli.Context) error {
+	startStep, endStep, err := parseRange(ctx.String("range"))
+	if err != nil {
+		return err
+	}
+	config := vm.TracePrinterConfig{DecodeInstructions: ctx.Bool("decode"), StartStep: startStep, EndStep: endStep}
+
+	cairoRunner, err := runProgram(ctx)
+	if err != nil {
+		return err
+	}
+
+	if ctx.Bool("unrelocated") {
+		return cairoRunner.Vm.PrintTrace(os.Stdout, config)
+	}
+	return cairoRunner.Vm.PrintRelocatedTrace(os.Stdout, config)
+}
+
+func handleMemoryCommand(ctx *cli.Context) error {
+	startOffset, endOffset, err := parseRange(ctx.String("offsets"))
+	if err != nil {
+		return err
+	}
+	config := memory.MemoryPrinterConfig{Hex: ctx.Bool("hex"), StartOffset: startOffset, EndOffset: endOffset}
+	if ctx.IsSet("segment") {
+		config.Segment = new(int)
+		*config.Segment = ctx.Int("segment")
+	}
+
+	// The memory is dumped even if the run failed, so that it can be inspected
+	cairoRunner, runErr := runProgram(ctx)
+	if cairoRunner == nil {
+		return runErr
+	}
+	if err := cairoRunner.Vm.Segments.PrintMemory(os.Stdout, config); err != nil {
+		return err
+	}
+	if ctx.Bool("instances") {
+		for _, builtin := range cairoRunner.Vm.BuiltinRunners {
+			if _, err := fmt.Printf("Builtin %s (segment %d)\n", builtin.Name(), builtin.Base().SegmentIndex); err != nil {
+				return err
+			}
+			if err := builtins.NewSegmentView(builtin, &cairoRunner.Vm.Segments.Memory).Print(os.Stdout, config.Hex); err != nil {
+				return err
+			}
+		}
+	}
+	return runErr
+}
+
+// Returns the format of an exported table, from the format flag or the extension of the path
+// Tables written to stdout default to csv
+func exportFormat(ctx *cli.Context, path string) (cairo_run.ExportFormat, error) {
+	if ctx.IsSet("format") {
+		return cairo_run.ParseExportFormat(ctx.String("format"))
+	}
+	if path == stdioPath {
+		return cairo_run.ExportCsv, nil
+	}
+	return cairo_run.ExportFormatFromPath(path)
+}
+
+func handleExportCommand(ctx *cli.Context) error {
+	tracePath, memoryPath := ctx.String("trace"), ctx.String("memory")
+	if tracePath == "" && memoryPath == "" {
+		return errors.New("Nothing to export, expected at least one of --trace & --memory")
+	}
+	if tracePath == stdioPath && memoryPath == stdioPath {
+		return errors.New("Only one of the trace & the memory can be written to stdout")
+	}
+	var traceFormat, memoryFormat cairo_run.ExportFormat
+	var err error
+	if tracePath != "" {
+		if traceFormat, err = exportFormat(ctx, tracePath); err != nil {
+			return err
+		}
+	}
+	if memoryPath != "" {
+		if memoryFormat, err = exportFormat(ctx, memoryPath); err != nil {
+			return err
+		}
+	}
+
+	cairoRunner, err := runProgram(ctx)
+	if cairoRunner != nil && cairoRunner.Vm.StreamedTrace != nil {
+		defer cairoRunner.Vm.StreamedTrace.Close()
+	}
+	if err != nil {
+		return err
+	}
+	err = writeArtifact(tracePath, func(dest io.Writer) error {
+		return cairo_run.ExportTrace(&cairoRunner.Vm, dest, traceFormat)
+	})
+	if err != nil {
+		return err
+	}
+	return writeArtifact(memoryPath, func(dest io.Writer) error {
+		return cairo_run.ExportMemory(cairoRunner.Vm.RelocatedMemory, dest, memoryFormat)
+	})
+}
+
+func handleCoverageCommand(ctx *cli.Context) error {
+	collector := coverage.NewCollector()
+	config := runConfig(ctx)
+	config.StepObservers = append(config.StepObservers, collector)
+
+	// The coverage is reported even if the run failed, as it shows how far the execution went
+	cairoRunner, runErr := runProgramWithConfig(ctx, config)
+	if cairoRunner == nil {
+		return runErr
+	}
+	report := collector.Report(&cairoRunner.Program)
+	if err := report.Write(os.Stdout); err != nil {
+		return err
+	}
+	return runErr
+}
+
+func handleDebugCommand(ctx *cli.Context) error {
+	if ctx.Args().First() == stdioPath {
+		return errors.New("The debugger reads its commands from stdin, the program can't be read from it")
+	}
+	compiledProgram, err := parser.Parse(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	program := vm.DeserializeProgramJson(compiledProgram)
+	programDebugger := debugger.NewDebugger(&program, os.Stdin, os.Stdout)
+
+	config := runConfig(ctx)
+	config.Hooks = programDebugger.Hooks()
+	config.Tracer = programDebugger.Tracer()
+	runner, err := cairo_run.NewRunner(program, config.Options()...)
+	if err != nil {
+		return err
+	}
+	programDebugger.SetExecutionScopes(runner.InspectExecutionScopes)
+	programDebugger.SetDicts(runner.DumpDicts)
+	err = runner.Run()
+	if errors.Is(err, debugger.ErrDebuggerQuit) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Println("Program finished")
+	return nil
+}
+
+// Result of running a single program in batch mode
+type batchResult struct {
+	programPath string
+	duration    time.Duration
+	err         error
+}
+
+// Returns the compiled programs matched by the given path
+// A directory matches every .json file directly inside it, anything else is treated as a glob pattern
+func batchProgramPaths(path string) ([]string, error) {
+	pattern := path
+	if info, err := os.Stat(path); err == nil && info.IsDir() {
+		pattern = filepath.Join(path, "*.json")
+	}
+	programPaths, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(programPaths) == 0 {
+		return nil, fmt.Errorf("No programs found in %s", path)
+	}
+	sort.Strings(programPaths)
+	return programPaths, nil
+}
+
+// Runs every program concurrently using a pool of workers
+// The results are returned in the same order as the program paths
+func runBatch(programPaths []string, config cairo_run.CairoRunConfig, workers int) []batchResult {
+	results := make([]batchResult, len(programPaths))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				start := time.Now()
+				cairoRunner, err := cairo_run.CairoRun(programPaths[idx], config)
+				if cairoRunner != nil && cairoRunner.Vm.StreamedTrace != nil {
+					cairoRunner.Vm.StreamedTrace.Close()
+				}
+				results[idx] = batchResult{programPath: programPaths[idx], duration: time.Since(start), err: err}
+			}
+		}()
+	}
+	for idx := range programPaths {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// Writes the batch results as a table of program, status, time & error
+func writeBatchSummary(dest io.Writer, results []batchResult, total time.Duration) {
+	writer := tabwriter.NewWriter(dest, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "program\tstatus\ttime\terror")
+	failed := 0
+	for _, result := range results {
+		status, errMsg := "PASS", ""
+		if result.err != nil {
+			status, errMsg = "FAIL", result.err.Error()
+			failed++
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", result.programPath, status, result.duration.Round(time.Microsecond), errMsg)
+	}
+	writer.Flush()
+	fmt.Fprintf(dest, "\n%d passed, %d failed, %d total in %s\n", len(results)-failed, failed, len(results), total.Round(time.Millisecond))
+}
+
+func handleBatchCommand(ctx *cli.Context) error {
+	programPaths, err := batchProgramPaths(ctx.Args().First())
+	if err != nil {
+		return err
+	}
+	workers := ctx.Int("workers")
+	if workers < 1 {
+		return fmt.Errorf("Invalid number of workers: %d", workers)
+	}
+
+	config := runConfig(ctx)
+	start := time.Now()
+	results := runBatch(programPaths, config, workers)
+	writeBatchSummary(os.Stdout, results, time.Since(start))
+	if config.HintStats != nil {
+		fmt.Println()
+		writeHintStats(os.Stdout, config.HintStats)
+	}
+	// The report is shared by every program, so only the pcs of the hints are known
+	if config.UnknownHints != nil && config.UnknownHints.Len() != 0 {
+		fmt.Println()
+		writeUnknownHints(os.Stdout, config.UnknownHints, nil)
+		return errors.New("Some programs have unknown hints")
+	}
+
+	for _, result := range results {
+		if result.err != nil {
+			return errors.New("Some programs failed to run")
+		}
+	}
+	return nil
+}
+
+// Reads both files passed as arguments with the given decoder
+func readComparedFiles[T any](ctx *cli.Context, read func(io.Reader, cairo_run.EncodingOptions) (T, error)) (T, T, error) {
+	var decoded [2]T
+	if ctx.NArg() != 2 {
+		return decoded[0], decoded[1], errors.New("Expected two files to compare")
+	}
+	encoding, err := cairo_run.ParseEncodingOptions(ctx.String("byte_order"))
+	if err != nil {
+		return decoded[0], decoded[1], err
+	}
+	for i, path := range ctx.Args().Slice() {
+		file, err := os.Open(path)
+		if err != nil {
+			return decoded[0], decoded[1], err
+		}
+		decoded[i], err = read(file, encoding)
+		file.Close()
+		if err != nil {
+			return decoded[0], decoded[1], fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return decoded[0], decoded[1], nil
+}
+
+func handleCompareTraceCommand(ctx *cli.Context) error {
+	a, b, err := readComparedFiles(ctx, cairo_run.ReadEncodedTraceWithOptions)
+	if err != nil {
+		return err
+	}
+	if diff := cairo_run.CompareTrace(a, b); diff != nil {
+		fmt.Print(diff)
+		return errors.New("Traces differ")
+	}
+	fmt.Printf("Traces match (%d steps)\n", len(a))
+	return nil
+}
+
+func handleCompareMemoryCommand(ctx *cli.Context) error {
+	a, b, err := readComparedFiles(ctx, cairo_run.ReadEncodedMemoryWithOptions)
+	if err != nil {
+		return err
+	}
+	if diff := cairo_run.CompareMemory(a, b); diff != nil {
+		fmt.Print(diff)
+		return errors.New("Memories differ")
+	}
+	fmt.Printf("Memories match (%d cells)\n", a.NumCells())
 	return nil
 }
 
 func main() {
+	runFlags := []cli.Flag{
+		&cli.BoolFlag{
+			Name:    "proof_mode",
+			Aliases: []string{"p"},
+			Usage:   "Run in proof mode",
+		},
+		&cli.BoolFlag{
+			Name:    "secure_run",
+			Aliases: []string{"s"},
+			Usage:   "Run security checks. Default: true unless proof_mode is true",
+		},
+		&cli.StringFlag{
+			Name:    "layout",
+			Aliases: []string{"l"},
+			Usage:   "Default: plain",
+		},
+		&cli.StringFlag{
+			Name:  "entrypoint",
+			Usage: "Function of the __main__ module the run starts from, ignored in proof mode. Default: main",
+		},
+		&cli.IntFlag{
+			Name:  "relocation_workers",
+			Usage: "Number of workers used to relocate the trace & memory. Default: one per cpu",
+		},
+		&cli.BoolFlag{
+			Name:  "hint_stats",
+			Usage: "Collect the number of executions & the time spent in each hint, written to stderr and to the json result",
+		},
+		&cli.BoolFlag{
+			Name:  "report_unknown_hints",
+			Usage: "Skip the hints the vm doesn't implement and report them once the run is over, instead of failing at the first one",
+		},
+		&cli.BoolFlag{
+			Name:  "compat_errors",
+			Usage: "Report the errors of the run with the messages the Rust vm emits for them",
+		},
+		&cli.DurationFlag{
+			Name:  "timeout",
+			Usage: "Abort the execution once it has run for longer than this duration (ie: 30s, 5m). Default: no limit",
+		},
+		&cli.StringFlag{
+			Name:  "program_cache_dir",
+			Usage: "Store the loaded programs in the directory, keyed by the hash of their file, so that later runs of the same program skip parsing it",
+		},
+		&cli.StringFlag{
+			Name:  "events_file",
+			Usage: "Write the events of the execution (steps, memory writes, segment additions, hints & builtin deductions) to the file as JSON lines, - for stdout",
+		},
+	}
+
+	profilingFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "cpuprofile",
+			Usage: "Write a cpu profile of the execution to the file",
+		},
+		&cli.StringFlag{
+			Name:  "memprofile",
+			Usage: "Write a heap profile to the file once the execution is over",
+		},
+		&cli.StringFlag{
+			Name:  "trace",
+			Usage: "Write a Go runtime execution trace to the file",
+		},
+		&cli.StringFlag{
+			Name:  "log_level",
+			Usage: "Minimum level of the logs written to stderr, one of: debug, info, warn, error. debug logs every step",
+			Value: "warn",
+		},
+		&cli.BoolFlag{
+			Name:  "metrics",
+			Usage: "Print the number of steps, the execution time, the step rate & the temporary value pool usage to stderr",
+		},
+		&cli.BoolFlag{
+			Name:  "summary",
+			Usage: "Print the summary of the run (segment sizes, builtin usage, memory holes, hints & elapsed time) to stderr",
+		},
+	}
+	byteOrderFlag := &cli.StringFlag{
+		Name:  "byte_order",
+		Usage: "Byte order of the binary trace & memory files, one of: le, be",
+		Value: "le",
+	}
+
+	var runProfiler profiler
+
 	app := &cli.App{
-		Flags: []cli.Flag{
-			&cli.BoolFlag{
-				Name:    "proof_mode",
-				Aliases: []string{"p"},
-				Usage:   "Run in proof mode",
-			},
-			&cli.BoolFlag{
-				Name:    "secure_run",
-				Aliases: []string{"s"},
-				Usage:   "Run security checks. Default: true unless proof_mode is true",
-			},
-			&cli.StringFlag{
-				Name:    "layout",
-				Aliases: []string{"l"},
-				Usage:   "Default: plain",
-			},
+		Before: func(ctx *cli.Context) error { return setup(ctx, &runProfiler) },
+		After:  runProfiler.stop,
+		Flags: append(append(runFlags, profilingFlags...),
 			&cli.StringFlag{
 				Name:    "trace_file",
 				Aliases: []string{"t"},
-				Usage:   "--trace_file <TRACE_FILE>",
+				Usage:   "--trace_file <TRACE_FILE>, - for stdout. Default: next to the program, not written for programs read from stdin",
 			},
 			&cli.StringFlag{
 				Name:    "memory_file",
 				Aliases: []string{"m"},
-				Usage:   "--memory_file <MEMORY_FILE>",
+				Usage:   "--memory_file <MEMORY_FILE>, - for stdout. Default: next to the program, not written for programs read from stdin",
+			},
+			&cli.BoolFlag{
+				Name:  "stream_trace",
+				Usage: "Stream the trace to a temporary file instead of holding it in memory, for very long runs",
+			},
+			&cli.StringFlag{
+				Name:    "output_format",
+				Aliases: []string{"output-format"},
+				Usage:   "Format of the run result printed to stdout, one of: text, json",
+				Value:   "text",
+			},
+			&cli.BoolFlag{
+				Name:  "print_output",
+				Usage: "Print the values written to the output builtin's segment once the run is over",
+			},
+			byteOrderFlag,
+		),
+		ArgsUsage: "<PROGRAM_PATH>, - to read the program from stdin",
+		Action:    handleCommands,
+		Commands: []*cli.Command{
+			{
+				Name:      "trace",
+				Usage:     "Runs a program and prints its trace as a table of step, pc, ap & fp",
+				ArgsUsage: "<PROGRAM_PATH>",
+				Flags: append(runFlags,
+					&cli.StringFlag{
+						Name:  "range",
+						Usage: "Only print the steps in <start>:<end> (start inclusive, end exclusive, both optional)",
+					},
+					&cli.BoolFlag{
+						Name:  "decode",
+						Usage: "Decode the instruction executed at each step",
+					},
+					&cli.BoolFlag{
+						Name:  "unrelocated",
+						Usage: "Print the trace before relocation (segment:offset registers)",
+					},
+				),
+				Action: handleTraceCommand,
+			},
+			{
+				Name:      "export",
+				Usage:     "Runs a program and exports its relocated trace and/or memory as CSV or Parquet tables, to study them with data-analysis tools (ie: pandas, DuckDB)",
+				ArgsUsage: "<PROGRAM_PATH>",
+				Flags: append(runFlags,
+					&cli.StringFlag{
+						Name:  "trace",
+						Usage: "Write the trace as a table of step, pc, ap & fp to this path, - for stdout",
+					},
+					&cli.StringFlag{
+						Name:  "memory",
+						Usage: "Write the memory as a table of address & value (hex) to this path, - for stdout",
+					},
+					&cli.StringFlag{
+						Name:  "format",
+						Usage: "Format of the tables, one of: csv, parquet. Default: from the extension of each path, csv for stdout",
+					},
+				),
+				Action: handleExportCommand,
+			},
+			{
+				Name:      "coverage",
+				Usage:     "Runs a program and reports which of its instructions were executed, by function & by source line. Functions & lines marked with ! have instructions that were never executed",
+				ArgsUsage: "<PROGRAM_PATH>",
+				Flags:     runFlags,
+				Action:    handleCoverageCommand,
+			},
+			{
+				Name:      "debug",
+				Usage:     "Runs a program in an interactive debugger, type help once started for a list of commands",
+				ArgsUsage: "<PROGRAM_PATH>",
+				Flags:     runFlags,
+				Action:    handleDebugCommand,
+			},
+			{
+				Name:      "batch",
+				Usage:     "Runs every compiled program in a directory (or matching a glob) concurrently and prints a summary table",
+				ArgsUsage: "<DIRECTORY_OR_GLOB>",
+				Flags: append(runFlags,
+					&cli.IntFlag{
+						Name:    "workers",
+						Aliases: []string{"w"},
+						Usage:   "Number of programs run concurrently",
+						Value:   runtime.NumCPU(),
+					},
+				),
+				Action: handleBatchCommand,
+			},
+			{
+				Name:      "memory",
+				Usage:     "Runs a program and dumps its memory grouped by segment, including holes. The memory is dumped even if the run fails",
+				ArgsUsage: "<PROGRAM_PATH>",
+				Flags: append(runFlags,
+					&cli.IntFlag{
+						Name:  "segment",
+						Usage: "Only dump the segment with this index",
+					},
+					&cli.StringFlag{
+						Name:  "offsets",
+						Usage: "Only dump the offsets in <start>:<end> of each segment (start inclusive, end exclusive, both optional)",
+					},
+					&cli.BoolFlag{
+						Name:  "hex",
+						Usage: "Render felts in hexadecimal instead of decimal",
+					},
+					&cli.BoolFlag{
+						Name:  "instances",
+						Usage: "Also dump the builtin segments as instances, one per line with their inputs & outputs",
+					},
+				),
+				Action: handleMemoryCommand,
+			},
+			{
+				Name:  "compare",
+				Usage: "Compares two binary trace or memory files (ie: the ones written by this vm & by the Rust vm) and prints the first difference",
+				Subcommands: []*cli.Command{
+					{
+						Name:      "trace",
+						Usage:     "Compares two trace files",
+						ArgsUsage: "<TRACE_FILE> <TRACE_FILE>",
+						Flags:     []cli.Flag{byteOrderFlag},
+						Action:    handleCompareTraceCommand,
+					},
+					{
+						Name:      "memory",
+						Usage:     "Compares two memory files",
+						ArgsUsage: "<MEMORY_FILE> <MEMORY_FILE>",
+						Flags:     []cli.Flag{byteOrderFlag},
+						Action:    handleCompareMemoryCommand,
+					},
+				},
 			},
 		},
-		Action: handleCommands,
 	}
 
 	if err := app.Run(os.Args); err != nil {
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/urfave/cli/v2"
+)
+
+// HTTP service that runs compiled Cairo programs
+//
+// POST /run takes a runRequest as its json body and responds with a runResponse
+// Failed runs are reported with success=false in the response. Non 200 status codes are only used for invalid requests
+// (400 & 405) and for requests that time out before a worker is free to run them (503)
+// GET /health responds with 200 once the server is up
+//
+// The server only speaks HTTP with json bodies, there is no gRPC endpoint. Programs are run from their main function
+// with no program input, as the vm has no support for it
+
+type runRequest struct {
+	// Compiled program, as written by cairo-compile
+	Program   parser.CompiledJson `json:"program"`
+	Layout    string              `json:"layout"`
+	ProofMode bool                `json:"proof_mode"`
+	// Defaults to true unless proof_mode is set
+	SecureRun *bool `json:"secure_run"`
+	// Maximum number of steps, zero (or a value above the server's limit) uses the server's limit
+	MaxSteps uint `json:"max_steps"`
+	// Maximum duration of the run, zero (or a value above the server's limit) uses the server's limit
+	TimeoutMs uint `json:"timeout_ms"`
+	// Maximum size of the relocated memory, zero (or a value above the server's limit) uses the server's limit
+	MaxMemorySize uint `json:"max_memory_size"`
+	// Include the relocated trace and/or memory in the response
+	Trace  bool `json:"trace"`
+	Memory bool `json:"memory"`
+}
+
+type runResponse struct {
+	Success            bool                `json:"success"`
+	Error              *runError           `json:"error,omitempty"`
+	ExecutionResources *executionResources `json:"execution_resources,omitempty"`
+	Output             []string            `json:"output"`
+	// Binary trace & memory, in the same format as the files written by the cli (base64 encoded in the json body)
+	Trace  []byte `json:"trace,omitempty"`
+	Memory []byte `json:"memory,omitempty"`
+}
+
+type runError struct {
+	// One of invalid_request, load_error, initialization_error, execution_error, finalization_error, step_limit,
+	// memory_limit or timeout
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	// Pc at which the execution failed, only present for execution errors
+	Pc string `json:"pc,omitempty"`
+}
+
+type executionResources struct {
+	NSteps                  uint            `json:"n_steps"`
+	NMemoryHoles            uint            `json:"n_memory_holes"`
+	BuiltinsInstanceCounter map[string]uint `json:"builtin_instance_counter"`
+}
+
+type server struct {
+	maxSteps      uint
+	maxMemorySize uint
+	timeout       time.Duration
+	maxBodyBytes  int64
+	// Limits the number of programs run concurrently
+	slots chan struct{}
+}
+
+func (s *server) handleRun(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		writeResponse(w, http.StatusMethodNotAllowed, failedResponse("invalid_request", "Expected a POST request"))
+		return
+	}
+	var request runRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, s.maxBodyBytes))
+	if err := decoder.Decode(&request); err != nil {
+		writeResponse(w, http.StatusBadRequest, failedResponse("invalid_request", fmt.Sprintf("Invalid request body: %s", err)))
+		return
+	}
+
+	timeout := s.timeout
+	if requestTimeout := time.Duration(request.TimeoutMs) * time.Millisecond; requestTimeout != 0 && requestTimeout < timeout {
+		timeout = requestTimeout
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		writeResponse(w, http.StatusServiceUnavailable, failedResponse("timeout", "Timed out waiting for a free worker"))
+		return
+	}
+
+	start := time.Now()
+	response := s.run(ctx, request)
+	logging.Default().Info("Handled run request", logging.F("success", response.Success), logging.F("duration", time.Since(start)))
+	writeResponse(w, http.StatusOK, response)
+}
+
+func (s *server) run(ctx context.Context, request runRequest) runResponse {
+	maxSteps := s.maxSteps
+	if request.MaxSteps != 0 && request.MaxSteps < maxSteps {
+		maxSteps = request.MaxSteps
+	}
+	maxMemorySize := s.maxMemorySize
+	if request.MaxMemorySize != 0 && request.MaxMemorySize < maxMemorySize {
+		maxMemorySize = request.MaxMemorySize
+	}
+	layout := request.Layout
+	if layout == "" {
+		layout = "plain"
+	}
+	secureRun := !request.ProofMode
+	if request.SecureRun != nil {
+		secureRun = *request.SecureRun
+	}
+	config := cairo_run.CairoRunConfig{
+		Layout:    layout,
+		ProofMode: request.ProofMode,
+		SecureRun: secureRun,
+		MaxSteps:  maxSteps,
+		// A single write at a huge offset would otherwise make the relocation allocate the memory up to it
+		MaxMemorySize: maxMemorySize,
+		// Aborts the run once the request times out or the client goes away
+		Context: ctx,
+	}
+
+	cairoRunner, err := cairo_run.CairoRunProgram(vm.DeserializeProgramJson(request.Program), config)
+	if err != nil {
+		return runFailure(cairoRunner, err)
+	}
+
+	response, err := runSuccess(cairoRunner, request.Trace, request.Memory)
+	if err != nil {
+		return failedResponse("finalization_error", err.Error())
+	}
+	return response
+}
+
+func failedResponse(code string, message string) runResponse {
+	return runResponse{Success: false, Error: &runError{Code: code, Message: message}, Output: []string{}}
+}
+
+// Builds the response for a run that failed with runErr
+// The runner may be nil if the run failed before it was created
+func runFailure(cairoRunner *runners.CairoRunner, runErr error) runResponse {
+	response := failedResponse("load_error", runErr.Error())
+	switch {
+	case errors.Is(runErr, context.DeadlineExceeded) || errors.Is(runErr, context.Canceled):
+		response.Error.Code = "timeout"
+	case errors.Is(runErr, runners.ErrUnfinishedExecution):
+		response.Error.Code = "step_limit"
+	case errors.Is(runErr, vm.ErrMemoryLimitExceeded):
+		response.Error.Code = "memory_limit"
+	case cairoRunner != nil && !cairoRunner.VmInitialized:
+		response.Error.Code = "initialization_error"
+	case cairoRunner != nil && cairoRunner.RunEnded:
+		response.Error.Code = "finalization_error"
+	case cairoRunner != nil:
+		response.Error.Code = "execution_error"
+		response.Error.Pc = cairoRunner.Vm.RunContext.Pc.ToString()
+	}
+	return response
+}
+
+// Builds the response for a successful run
+func runSuccess(cairoRunner *runners.CairoRunner, includeTrace bool, includeMemory bool) (runResponse, error) {
+	resources, err := cairoRunner.GetExecutionResources()
+	if err != nil {
+		return runResponse{}, err
+	}
+	var outputBuffer bytes.Buffer
+	if err := cairoRunner.WriteOutput(&outputBuffer); err != nil {
+		return runResponse{}, err
+	}
+	output := []string{}
+	if outputBuffer.Len() != 0 {
+		output = strings.Split(strings.TrimSuffix(outputBuffer.String(), "\n"), "\n")
+	}
+	response := runResponse{
+		Success: true,
+		ExecutionResources: &executionResources{
+			NSteps:                  resources.NSteps,
+			NMemoryHoles:            resources.NMemoryHoles,
+			BuiltinsInstanceCounter: resources.BuiltinsInstanceCounter,
+		},
+		Output: output,
+	}
+	if includeTrace {
+		var trace bytes.Buffer
+		if err := cairo_run.WriteVmEncodedTrace(&cairoRunner.Vm, &trace); err != nil {
+			return runResponse{}, err
+		}
+		response.Trace = trace.Bytes()
+	}
+	if includeMemory {
+		var memory bytes.Buffer
+		if err := cairo_run.WriteEncodedMemory(cairoRunner.Vm.RelocatedMemory, &memory); err != nil {
+			return runResponse{}, err
+		}
+		response.Memory = memory.Bytes()
+	}
+	return response, nil
+}
+
+func writeResponse(w http.ResponseWriter, status int, response runResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logging.Default().Warn("Failed to write response", logging.F("error", err))
+	}
+}
+
+func handleHealth(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func serve(ctx *cli.Context) error {
+	logLevel, err := logging.ParseLevel(ctx.String("log_level"))
+	if err != nil {
+		return err
+	}
+	logging.SetDefault(logging.NewLogger(os.Stderr, logLevel))
+
+	workers := ctx.Int("workers")
+	if workers < 1 {
+		return fmt.Errorf("Invalid number of workers: %d", workers)
+	}
+	s := &server{
+		maxSteps:      ctx.Uint("max_steps"),
+		maxMemorySize: ctx.Uint("max_memory_size"),
+		timeout:       ctx.Duration("timeout"),
+		maxBodyBytes:  ctx.Int64("max_body_bytes"),
+		slots:         make(chan struct{}, workers),
+	}
+	// The response is only written once the run is over, which takes up to the run timeout (waiting for a worker
+	// included) plus the finalization & relocation of the run
+	writeTimeout := ctx.Duration("write_timeout")
+	if writeTimeout <= s.timeout {
+		return fmt.Errorf("The write timeout (%s) must be longer than the run timeout (%s)", writeTimeout, s.timeout)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/run", s.handleRun)
+	mux.HandleFunc("/health", handleHealth)
+
+	httpServer := &http.Server{
+		Addr:              ctx.String("addr"),
+		Handler:           mux,
+		ReadHeaderTimeout: ctx.Duration("read_header_timeout"),
+		ReadTimeout:       ctx.Duration("read_timeout"),
+		WriteTimeout:      writeTimeout,
+	}
+	logging.Default().Info("Listening", logging.F("addr", httpServer.Addr))
+	return httpServer.ListenAndServe()
+}
+
+func main() {
+	app := &cli.App{
+		Name:  "cairo-vm-server",
+		Usage: "Runs compiled Cairo programs submitted over HTTP",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "addr",
+				Usage: "Address the server listens on",
+				Value: ":8080",
+			},
+			&cli.UintFlag{
+				Name:  "max_steps",
+				Usage: "Maximum number of steps of a run, requests can only lower it",
+				Value: 10_000_000,
+			},
+			&cli.UintFlag{
+				Name:  "max_memory_size",
+				Usage: "Maximum size of the relocated memory of a run, requests can only lower it",
+				Value: 1 << 26,
+			},
+			&cli.DurationFlag{
+				Name:  "timeout",
+				Usage: "Maximum duration of a run, requests can only lower it",
+				Value: time.Minute,
+			},
+			&cli.DurationFlag{
+				Name:  "read_header_timeout",
+				Usage: "Maximum duration of reading the headers of a request",
+				Value: 10 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "read_timeout",
+				Usage: "Maximum duration of reading a whole request, body included",
+				Value: 30 * time.Second,
+			},
+			&cli.DurationFlag{
+				Name:  "write_timeout",
+				Usage: "Maximum duration of handling a request once its headers are read, must be longer than timeout",
+				Value: 2 * time.Minute,
+			},
+			&cli.Int64Flag{
+				Name:  "max_body_bytes",
+				Usage: "Maximum size of a request body",
+				Value: 64 << 20,
+			},
+			&cli.IntFlag{
+				Name:  "workers",
+				Usage: "Number of programs run concurrently",
+				Value: runtime.NumCPU(),
+			},
+			&cli.StringFlag{
+				Name:  "log_level",
+				Usage: "Minimum level of the logs written to stderr, one of: debug, info, warn, error",
+				Value: "info",
+			},
+		},
+		Action: serve,
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		log.Fatal(err)
+	}
+}
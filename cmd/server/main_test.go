@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// Compiled programs, as sent by clients
+// main{output_ptr}: [ap] = 42, ap++; [ap - 1] = [[fp - 3]]; [ap] = [fp - 3] + 1, ap++; ret
+const outputProgram = `{
+	"builtins": ["output"],
+	"data": ["0x480680017fff8000", "0x2a", "0x400280007ffd7fff", "0x482680017ffd8000", "0x1", "0x208b7fff7fff7ffe"],
+	"identifiers": {"__main__.main": {"pc": 0, "type": "function"}}
+}`
+
+// main: ap += 1; jmp main
+const endlessProgram = `{
+	"data": ["0x40780017fff7fff", "0x1", "0x10780017fff7fff", "0x800000000000010ffffffffffffffffffffffffffffffffffffffffffffffff"],
+	"identifiers": {"__main__.main": {"pc": 0, "type": "function"}}
+}`
+
+func newTestServer(workers int) *server {
+	return &server{
+		maxSteps:      1_000_000_000,
+		maxMemorySize: 1 << 20,
+		timeout:       time.Second,
+		maxBodyBytes:  1 << 20,
+		slots:         make(chan struct{}, workers),
+	}
+}
+
+// Sends a request for program with the given fields to the handler of s, returning the status code & decoded response
+func postRun(t *testing.T, s *server, program string, fields map[string]any) (int, runResponse) {
+	t.Helper()
+	request := map[string]any{"program": json.RawMessage(program)}
+	for name, value := range fields {
+		request[name] = value
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return serveRun(t, s, httptest.NewRequest(http.MethodPost, "/run", bytes.NewReader(body)))
+}
+
+func serveRun(t *testing.T, s *server, request *http.Request) (int, runResponse) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	s.handleRun(recorder, request)
+	var response runResponse
+	if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode the response: %s", err)
+	}
+	return recorder.Code, response
+}
+
+func TestHandleRun(t *testing.T) {
+	status, response := postRun(t, newTestServer(1), outputProgram, map[string]any{"layout": "small", "trace": true, "memory": true})
+	if status != http.StatusOK || !response.Success || response.Error != nil {
+		t.Fatalf("Expected a successful run, got %d: %+v", status, response.Error)
+	}
+	if !reflect.DeepEqual(response.Output, []string{"42"}) {
+		t.Errorf("Wrong output: %v", response.Output)
+	}
+	resources := response.ExecutionResources
+	if resources == nil || resources.NSteps != 4 || resources.BuiltinsInstanceCounter["output"] != 1 {
+		t.Errorf("Wrong execution resources: %+v", resources)
+	}
+	if len(response.Trace) != 4*3*8 || len(response.Memory) == 0 {
+		t.Errorf("Expected the trace & memory, got %d & %d bytes", len(response.Trace), len(response.Memory))
+	}
+}
+
+func TestHandleRunInvalidRequests(t *testing.T) {
+	s := newTestServer(1)
+	status, response := serveRun(t, s, httptest.NewRequest(http.MethodGet, "/run", nil))
+	if status != http.StatusMethodNotAllowed || response.Error == nil || response.Error.Code != "invalid_request" {
+		t.Errorf("Expected a 405 invalid_request, got %d: %+v", status, response.Error)
+	}
+	status, response = serveRun(t, s, httptest.NewRequest(http.MethodPost, "/run", bytes.NewReader([]byte("{"))))
+	if status != http.StatusBadRequest || response.Error == nil || response.Error.Code != "invalid_request" {
+		t.Errorf("Expected a 400 invalid_request, got %d: %+v", status, response.Error)
+	}
+}
+
+func TestHandleRunFailures(t *testing.T) {
+	cases := []struct {
+		program string
+		fields  map[string]any
+		code    string
+	}{
+		{endlessProgram, map[string]any{"max_steps": 10}, "step_limit"},
+		{outputProgram, map[string]any{"layout": "small", "max_memory_size": 2}, "memory_limit"},
+		{endlessProgram, map[string]any{"timeout_ms": 20}, "timeout"},
+	}
+	for _, c := range cases {
+		status, response := postRun(t, newTestServer(1), c.program, c.fields)
+		if status != http.StatusOK || response.Success || response.Error == nil || response.Error.Code != c.code {
+			t.Errorf("Expected a failed run with code %s, got %d: %+v", c.code, status, response.Error)
+		}
+	}
+}
+
+func TestHandleRunNoFreeWorker(t *testing.T) {
+	s := newTestServer(1)
+	// Takes the only worker, as a run in progress would
+	s.slots <- struct{}{}
+	status, response := postRun(t, s, outputProgram, map[string]any{"layout": "small", "timeout_ms": 10})
+	if status != http.StatusServiceUnavailable || response.Error == nil || response.Error.Code != "timeout" {
+		t.Errorf("Expected a 503 timeout, got %d: %+v", status, response.Error)
+	}
+}
@@ -0,0 +1,3 @@
+// Runnable examples of embedding the vm in Go programs, exercised by go test so that the public API they use keeps
+// working. The programs they run are in testdata, hand-assembled so that they don't depend on cairo-lang
+package examples
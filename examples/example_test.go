@@ -0,0 +1,158 @@
+package examples_test
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Runs the main function of a compiled program
+func Example_runProgram() {
+	runner, err := cairo_run.CairoRun("testdata/output.json", cairo_run.CairoRunConfig{Layout: "small", SecureRun: true})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	resources, _ := runner.GetExecutionResources()
+	fmt.Println("steps:", resources.NSteps)
+	fmt.Println("output cells:", resources.BuiltinsInstanceCounter[builtins.OUTPUT_BUILTIN_NAME])
+	// Output:
+	// steps: 6
+	// output cells: 2
+}
+
+// Reads the values the program wrote to the output builtin
+func Example_readOutputs() {
+	runner, err := cairo_run.CairoRun("testdata/output.json", cairo_run.CairoRunConfig{Layout: "small"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	outputs, err := runner.GetOutputs()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for _, value := range outputs {
+		fmt.Println(value.ToSignedFeltString())
+	}
+	// Output:
+	// 42
+	// 7
+}
+
+// Calls a function of the program with arguments, instead of running its main function
+func Example_runEntrypoint() {
+	compiledProgram, err := parser.Parse("testdata/double.json")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	program := vm.DeserializeProgramJson(compiledProgram)
+	entrypoint, err := program.GetLabelPc("__main__.double")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := runner.InitializeBuiltins(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	runner.InitializeSegments()
+	args := []any{*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(21))}
+	if err := runner.RunFromEntrypoint(entrypoint, args, &hints.CairoVmHintProcessor{}, nil, true, nil); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	returnValues, err := runner.Vm.GetReturnValues(1)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	result, _ := returnValues[0].GetFelt()
+	fmt.Println("double(21) =", result.ToSignedFeltString())
+	// Output:
+	// double(21) = 42
+}
+
+// Implements a hint the vm doesn't know about, which writes a value at ap
+func Example_customHint() {
+	secret := func(ctx *hints.HintContext) error {
+		return ctx.Write(ctx.Ap(), *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1234)))
+	}
+	runner, err := cairo_run.CairoRun("testdata/custom_hint.json", cairo_run.CairoRunConfig{
+		Layout:      "small",
+		CustomHints: map[string]hints.CustomHint{"memory[ap] = secret()": secret},
+	})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	outputs, err := runner.GetOutputs()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("output:", outputs[0].ToSignedFeltString())
+	// Output:
+	// output: 1234
+}
+
+// Runs a program in proof mode and writes the files the prover takes as input
+func Example_proverInputs() {
+	compiledProgram, err := parser.Parse("testdata/proof_mode.json")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	runner, err := cairo_run.NewRunner(vm.DeserializeProgramJson(compiledProgram), cairo_run.WithProofMode())
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	if err := runner.Run(); err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	dir, err := os.MkdirTemp("", "prover_inputs")
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+	if err := runner.BuildProverInputs(dir); err != nil {
+		fmt.Println(err)
+		return
+	}
+	entries, _ := os.ReadDir(dir)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Println(name)
+	}
+	// Output:
+	// air_private_input.json
+	// air_public_input.json
+	// memory.bin
+	// trace.bin
+}
@@ -0,0 +1,179 @@
+package builtins
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Data of a builtin that isn't stored in memory (ie: the signatures of the ecdsa builtin), needed to export a run
+// (ie: to a Cairo PIE) & to resume it. Its JSON encoding matches cairo-lang's
+// Builtins without additional data return nil
+type BuiltinAdditionalData interface {
+	// Name of the builtin the data belongs to
+	BuiltinName() string
+}
+
+var ErrInvalidAdditionalData = errors.New("Invalid additional data")
+
+func InvalidAdditionalDataError(builtinName string, reason string) error {
+	return errors.Wrapf(ErrInvalidAdditionalData, "%s builtin: %s", builtinName, reason)
+}
+
+// Decodes the JSON encoded additional data of the builtin with the given name
+// Returns nil for the builtins without additional data
+func UnmarshalAdditionalData(builtinName string, data []byte) (BuiltinAdditionalData, error) {
+	switch builtinName {
+	case PEDERSEN_BUILTIN_NAME:
+		return unmarshalAdditionalData[HashAdditionalData](builtinName, data)
+	case SIGNATURE_BUILTIN_NAME:
+		return unmarshalAdditionalData[SignatureAdditionalData](builtinName, data)
+	case OUTPUT_BUILTIN_NAME:
+		return unmarshalAdditionalData[OutputAdditionalData](builtinName, data)
+	}
+	return nil, nil
+}
+
+func unmarshalAdditionalData[T BuiltinAdditionalData](builtinName string, data []byte) (BuiltinAdditionalData, error) {
+	var additionalData T
+	if err := json.Unmarshal(data, &additionalData); err != nil {
+		return nil, InvalidAdditionalDataError(builtinName, err.Error())
+	}
+	return additionalData, nil
+}
+
+// Restores the additional data of a builtin that has none, which only accepts nil
+func noAdditionalData(builtin BuiltinRunner, data BuiltinAdditionalData) error {
+	if data != nil {
+		return InvalidAdditionalDataError(builtin.Name(), "the builtin has no additional data")
+	}
+	return nil
+}
+
+func unexpectedAdditionalDataError(builtin BuiltinRunner, data BuiltinAdditionalData) error {
+	return InvalidAdditionalDataError(builtin.Name(), fmt.Sprintf("unexpected additional data of type %T", data))
+}
+
+func sortRelocatables(addresses []memory.Relocatable) {
+	sort.Slice(addresses, func(i, j int) bool {
+		if addresses[i].SegmentIndex != addresses[j].SegmentIndex {
+			return addresses[i].SegmentIndex < addresses[j].SegmentIndex
+		}
+		return addresses[i].Offset < addresses[j].Offset
+	})
+}
+
+// Addresses verified by the pedersen builtin, sorted
+type HashAdditionalData []memory.Relocatable
+
+func (HashAdditionalData) BuiltinName() string {
+	return PEDERSEN_BUILTIN_NAME
+}
+
+func (d HashAdditionalData) MarshalJSON() ([]byte, error) {
+	addresses := make([]memory.JsonAddress, 0, len(d))
+	for _, addr := range d {
+		addresses = append(addresses, memory.ToJsonAddress(addr))
+	}
+	return json.Marshal(addresses)
+}
+
+func (d *HashAdditionalData) UnmarshalJSON(data []byte) error {
+	var addresses []memory.JsonAddress
+	if err := json.Unmarshal(data, &addresses); err != nil {
+		return err
+	}
+	*d = make(HashAdditionalData, 0, len(addresses))
+	for _, address := range addresses {
+		addr, err := address.Relocatable()
+		if err != nil {
+			return err
+		}
+		*d = append(*d, addr)
+	}
+	return nil
+}
+
+// Signatures added to the ecdsa builtin, indexed by the address of their public key
+type SignatureAdditionalData map[memory.Relocatable]Signature
+
+func (SignatureAdditionalData) BuiltinName() string {
+	return SIGNATURE_BUILTIN_NAME
+}
+
+// Encoded as a list of [[segment_index, offset], [r, s]], sorted by address
+func (d SignatureAdditionalData) MarshalJSON() ([]byte, error) {
+	addresses := make([]memory.Relocatable, 0, len(d))
+	for addr := range d {
+		addresses = append(addresses, addr)
+	}
+	sortRelocatables(addresses)
+	signatures := make([][2]any, 0, len(d))
+	for _, addr := range addresses {
+		signature := d[addr]
+		signatures = append(signatures, [2]any{memory.ToJsonAddress(addr), [2]*big.Int{signature.R.ToBigInt(), signature.S.ToBigInt()}})
+	}
+	return json.Marshal(signatures)
+}
+
+func (d *SignatureAdditionalData) UnmarshalJSON(data []byte) error {
+	var signatures [][2]json.RawMessage
+	if err := json.Unmarshal(data, &signatures); err != nil {
+		return err
+	}
+	*d = make(SignatureAdditionalData, len(signatures))
+	for _, entry := range signatures {
+		var address memory.JsonAddress
+		var rs [2]*big.Int
+		if err := json.Unmarshal(entry[0], &address); err != nil {
+			return err
+		}
+		if err := json.Unmarshal(entry[1], &rs); err != nil {
+			return err
+		}
+		if rs[0] == nil || rs[1] == nil {
+			return errors.Errorf("missing signature at address %v", address)
+		}
+		addr, err := address.Relocatable()
+		if err != nil {
+			return err
+		}
+		(*d)[addr] = Signature{R: lambdaworks.FeltFromBigInt(rs[0]), S: lambdaworks.FeltFromBigInt(rs[1])}
+	}
+	return nil
+}
+
+// Page of the output builtin, encoded as [start, size]
+type OutputPage struct {
+	// Offset of the page's first cell in the output segment
+	Start uint
+	Size  uint
+}
+
+func (p OutputPage) MarshalJSON() ([]byte, error) {
+	return json.Marshal([2]uint{p.Start, p.Size})
+}
+
+func (p *OutputPage) UnmarshalJSON(data []byte) error {
+	var page [2]uint
+	if err := json.Unmarshal(data, &page); err != nil {
+		return err
+	}
+	p.Start, p.Size = page[0], page[1]
+	return nil
+}
+
+// Pages & attributes of the output builtin
+type OutputAdditionalData struct {
+	Pages      map[uint]OutputPage `json:"pages"`
+	Attributes map[string][]uint   `json:"attributes"`
+}
+
+func (OutputAdditionalData) BuiltinName() string {
+	return OUTPUT_BUILTIN_NAME
+}
@@ -0,0 +1,95 @@
+package builtins_test
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Marshals the builtin's additional data, checks its encoding & restores it into a new builtin
+func checkAdditionalDataRoundTrip(t *testing.T, builtin builtins.BuiltinRunner, restored builtins.BuiltinRunner, expectedJson string) {
+	encoded, err := json.Marshal(builtin.GetAdditionalData())
+	if err != nil {
+		t.Fatalf("Marshal failed with error: %s", err)
+	}
+	if string(encoded) != expectedJson {
+		t.Errorf("Wrong encoding, expected %s, got %s", expectedJson, encoded)
+	}
+	decoded, err := builtins.UnmarshalAdditionalData(builtin.Name(), encoded)
+	if err != nil {
+		t.Fatalf("UnmarshalAdditionalData failed with error: %s", err)
+	}
+	if err := restored.SetAdditionalData(decoded); err != nil {
+		t.Fatalf("SetAdditionalData failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(restored.GetAdditionalData(), builtin.GetAdditionalData()) {
+		t.Errorf("Wrong restored data, expected %v, got %v", builtin.GetAdditionalData(), restored.GetAdditionalData())
+	}
+}
+
+func TestSignatureAdditionalData(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	signature := builtins.NewSignatureBuiltinRunner(512)
+	signature.InitializeSegments(&segments)
+	signature.AddSignature(memory.NewRelocatable(1, 2), builtins.Signature{R: lambdaworks.FeltFromUint64(5), S: lambdaworks.FeltFromUint64(6)})
+	signature.AddSignature(memory.NewRelocatable(1, 0), builtins.Signature{R: lambdaworks.FeltFromUint64(3), S: lambdaworks.FeltFromUint64(4)})
+
+	restoredSegments := memory.NewMemorySegmentManager()
+	restoredSegments.AddSegment()
+	restored := builtins.NewSignatureBuiltinRunner(512)
+	restored.InitializeSegments(&restoredSegments)
+	checkAdditionalDataRoundTrip(t, signature, restored, "[[[1,0],[3,4]],[[1,2],[5,6]]]")
+}
+
+func TestPedersenAdditionalData(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	pedersen := builtins.NewPedersenBuiltinRunner(8)
+	pedersen.InitializeSegments(&segments)
+	verified := builtins.HashAdditionalData{memory.NewRelocatable(0, 2), memory.NewRelocatable(0, 5)}
+	if err := pedersen.SetAdditionalData(verified); err != nil {
+		t.Fatalf("SetAdditionalData failed with error: %s", err)
+	}
+	if !pedersen.CheckVerifiedAddresses(memory.NewRelocatable(0, 5)) || pedersen.CheckVerifiedAddresses(memory.NewRelocatable(0, 3)) {
+		t.Errorf("Wrong verified addresses")
+	}
+
+	restoredSegments := memory.NewMemorySegmentManager()
+	restored := builtins.NewPedersenBuiltinRunner(8)
+	restored.InitializeSegments(&restoredSegments)
+	checkAdditionalDataRoundTrip(t, pedersen, restored, "[[0,2],[0,5]]")
+}
+
+func TestOutputAdditionalData(t *testing.T) {
+	output := builtins.NewOutputBuiltinRunner()
+	output.AddPage(1, 0, 3)
+	output.AddAttribute("gps_fact_topology", []uint{1, 2})
+	checkAdditionalDataRoundTrip(t, output, builtins.NewOutputBuiltinRunner(), `{"pages":{"1":[0,3]},"attributes":{"gps_fact_topology":[1,2]}}`)
+}
+
+func TestSetInvalidAdditionalData(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	pedersen := builtins.NewPedersenBuiltinRunner(8)
+	pedersen.InitializeSegments(&segments)
+
+	err := pedersen.SetAdditionalData(builtins.HashAdditionalData{memory.NewRelocatable(3, 0)})
+	if !errors.Is(err, builtins.ErrInvalidAdditionalData) {
+		t.Errorf("Expected ErrInvalidAdditionalData for an address out of the segment, got %v", err)
+	}
+	err = pedersen.SetAdditionalData(builtins.OutputAdditionalData{})
+	if !errors.Is(err, builtins.ErrInvalidAdditionalData) {
+		t.Errorf("Expected ErrInvalidAdditionalData for the data of another builtin, got %v", err)
+	}
+	err = builtins.NewBitwiseBuiltinRunner(256).SetAdditionalData(builtins.HashAdditionalData{})
+	if !errors.Is(err, builtins.ErrInvalidAdditionalData) {
+		t.Errorf("Expected ErrInvalidAdditionalData for a builtin without additional data, got %v", err)
+	}
+	if _, err := builtins.UnmarshalAdditionalData(builtins.SIGNATURE_BUILTIN_NAME, []byte(`{"pages":{}}`)); !errors.Is(err, builtins.ErrInvalidAdditionalData) {
+		t.Errorf("Expected ErrInvalidAdditionalData for malformed JSON, got %v", err)
+	}
+}
@@ -0,0 +1,169 @@
+package builtins
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Entries of the private input of the builtins, as expected by the prover (felts are written as hex strings)
+
+type PrivateInputValue struct {
+	Index uint   `json:"index"`
+	Value string `json:"value"`
+}
+
+type PrivateInputPair struct {
+	Index uint   `json:"index"`
+	X     string `json:"x"`
+	Y     string `json:"y"`
+}
+
+type PrivateInputEcOp struct {
+	Index uint   `json:"index"`
+	PX    string `json:"p_x"`
+	PY    string `json:"p_y"`
+	M     string `json:"m"`
+	QX    string `json:"q_x"`
+	QY    string `json:"q_y"`
+}
+
+type PrivateInputPoseidonState struct {
+	Index   uint   `json:"index"`
+	InputS0 string `json:"input_s0"`
+	InputS1 string `json:"input_s1"`
+	InputS2 string `json:"input_s2"`
+}
+
+type PrivateInputKeccakState struct {
+	Index   uint   `json:"index"`
+	InputS0 string `json:"input_s0"`
+	InputS1 string `json:"input_s1"`
+	InputS2 string `json:"input_s2"`
+	InputS3 string `json:"input_s3"`
+	InputS4 string `json:"input_s4"`
+	InputS5 string `json:"input_s5"`
+	InputS6 string `json:"input_s6"`
+	InputS7 string `json:"input_s7"`
+}
+
+type PrivateInputSignature struct {
+	Index          uint           `json:"index"`
+	PubKey         string         `json:"pubkey"`
+	Msg            string         `json:"msg"`
+	SignatureInput SignatureInput `json:"signature_input"`
+}
+
+// Signature (r, s) of an ecdsa instance, where w is the inverse of s modulo the order of the curve
+type SignatureInput struct {
+	R string `json:"r"`
+	W string `json:"w"`
+}
+
+// Order of the STARK curve
+var ecOrder, _ = new(big.Int).SetString("800000000000010ffffffffffffffffb781126dcae7b2321e66a241adc64d2f", 16)
+
+// Returns the private input of the builtin, one entry per instance whose input cells are all set
+// Returns nil for the builtins that have no private input (ie: output)
+func AirPrivateInput(builtin BuiltinRunner, segments *memory.MemorySegmentManager) ([]any, error) {
+	if signatureRunner, ok := builtin.(*SignatureBuiltinRunner); ok {
+		return signaturePrivateInput(signatureRunner, &segments.Memory), nil
+	}
+
+	var entry func(index uint, inputs []lambdaworks.Felt) any
+	switch builtin.Name() {
+	case RANGE_CHECK_BUILTIN_NAME:
+		entry = func(index uint, inputs []lambdaworks.Felt) any {
+			return PrivateInputValue{Index: index, Value: inputs[0].ToHexString()}
+		}
+	case PEDERSEN_BUILTIN_NAME, BITWISE_BUILTIN_NAME:
+		entry = func(index uint, inputs []lambdaworks.Felt) any {
+			return PrivateInputPair{Index: index, X: inputs[0].ToHexString(), Y: inputs[1].ToHexString()}
+		}
+	case EC_OP_BUILTIN_NAME:
+		// Input cells: p_x, p_y, q_x, q_y, m
+		entry = func(index uint, inputs []lambdaworks.Felt) any {
+			return PrivateInputEcOp{
+				Index: index,
+				PX:    inputs[0].ToHexString(),
+				PY:    inputs[1].ToHexString(),
+				M:     inputs[4].ToHexString(),
+				QX:    inputs[2].ToHexString(),
+				QY:    inputs[3].ToHexString(),
+			}
+		}
+	case POSEIDON_BUILTIN_NAME:
+		entry = func(index uint, inputs []lambdaworks.Felt) any {
+			return PrivateInputPoseidonState{
+				Index:   index,
+				InputS0: inputs[0].ToHexString(),
+				InputS1: inputs[1].ToHexString(),
+				InputS2: inputs[2].ToHexString(),
+			}
+		}
+	case KECCAK_BUILTIN_NAME:
+		entry = func(index uint, inputs []lambdaworks.Felt) any {
+			return PrivateInputKeccakState{
+				Index:   index,
+				InputS0: inputs[0].ToHexString(),
+				InputS1: inputs[1].ToHexString(),
+				InputS2: inputs[2].ToHexString(),
+				InputS3: inputs[3].ToHexString(),
+				InputS4: inputs[4].ToHexString(),
+				InputS5: inputs[5].ToHexString(),
+				InputS6: inputs[6].ToHexString(),
+				InputS7: inputs[7].ToHexString(),
+			}
+		}
+	default:
+		return nil, nil
+	}
+
+	segmentSize, err := segments.GetSegmentUsedSize(uint(builtin.Base().SegmentIndex))
+	if err != nil {
+		return nil, err
+	}
+	view := NewSegmentView(builtin, &segments.Memory)
+	privateInput := make([]any, 0)
+	for index := uint(0); index*builtin.CellsPerInstance() < segmentSize; index++ {
+		if inputs, ok := view.Inputs(index); ok {
+			privateInput = append(privateInput, entry(index, inputs))
+		}
+	}
+	return privateInput, nil
+}
+
+func signaturePrivateInput(runner *SignatureBuiltinRunner, mem *memory.Memory) []any {
+	signatures := make([]PrivateInputSignature, 0, len(runner.signatures))
+	for addr, signature := range runner.signatures {
+		pubKey, err := mem.GetFelt(addr)
+		if err != nil {
+			continue
+		}
+		msg, err := mem.GetFelt(memory.NewRelocatable(addr.SegmentIndex, addr.Offset+1))
+		if err != nil {
+			continue
+		}
+		w := new(big.Int).ModInverse(signature.S.ToBigInt(), ecOrder)
+		if w == nil {
+			continue
+		}
+		signatures = append(signatures, PrivateInputSignature{
+			Index:  addr.Offset / SIGNATURE_CELLS_PER_INSTANCE,
+			PubKey: pubKey.ToHexString(),
+			Msg:    msg.ToHexString(),
+			SignatureInput: SignatureInput{
+				R: signature.R.ToHexString(),
+				W: lambdaworks.FeltFromBigInt(w).ToHexString(),
+			},
+		})
+	}
+	sort.Slice(signatures, func(i, j int) bool { return signatures[i].Index < signatures[j].Index })
+	privateInput := make([]any, 0, len(signatures))
+	for _, signature := range signatures {
+		privateInput = append(privateInput, signature)
+	}
+	return privateInput
+}
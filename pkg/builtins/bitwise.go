@@ -27,7 +27,7 @@ func BitwiseError(err error) error {
 }
 
 func ErrFeltBiggerThanPowerOfTwo(felt lambdaworks.Felt) error {
-	return BitwiseError(errors.Errorf("Expected felt %d to be smaller than  2**%d", felt, BITWISE_TOTAL_N_BITS))
+	return BitwiseError(errors.Errorf("Expected felt %s to be smaller than 2**%d", felt.ToSignedFeltString(), BITWISE_TOTAL_N_BITS))
 }
 
 func NewBitwiseBuiltinRunner(ratio uint) *BitwiseBuiltinRunner {
@@ -166,7 +166,7 @@ func (runner *BitwiseBuiltinRunner) GetRangeCheckUsage(memory *memory.Memory) (*
 	return nil, nil
 }
 
-func (runner *BitwiseBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+func (runner *BitwiseBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	return 0, nil
 }
 
@@ -264,3 +264,21 @@ func (b *BitwiseBuiltinRunner) GetMemorySegmentAddresses() (memory.Relocatable,
 	}
 	return b.base, memory.NewRelocatable(b.base.SegmentIndex, *b.StopPtr), nil
 }
+
+func (b *BitwiseBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+func (b *BitwiseBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	return nil
+}
+
+func (b *BitwiseBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	return noAdditionalData(b, data)
+}
+
+func (b *BitwiseBuiltinRunner) Clone() BuiltinRunner {
+	clone := *b
+	clone.StopPtr = cloneStopPtr(b.StopPtr)
+	return &clone
+}
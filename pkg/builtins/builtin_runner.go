@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 	"github.com/pkg/errors"
 )
@@ -17,13 +18,26 @@ func NewErrNoStopPointer(builtinName string) error {
 }
 
 func NewErrInvalidStopPointerIndex(builtinName string, stopPtr memory.Relocatable, base memory.Relocatable) error {
-	return fmt.Errorf("%w builtin: %s stopPtr: (%d, %d) base: (%d, %d)", ErrInvalidStopPointerIndex, builtinName, stopPtr.SegmentIndex, stopPtr.Offset, base.SegmentIndex, base.Offset)
+	return fmt.Errorf("%w builtin: %s stopPtr: %s base: %s", ErrInvalidStopPointerIndex, builtinName, stopPtr, base)
 }
 
 func NewErrInvalidStopPointer(builtinName string, used uint, stopPtr memory.Relocatable) error {
-	return fmt.Errorf("%w builtin: %s used: (%d, %d) stopPtr: (%d, %d)", ErrInvalidStopPointer, builtinName, stopPtr.SegmentIndex, used, stopPtr.SegmentIndex, stopPtr.Offset)
+	usedPtr := memory.NewRelocatable(stopPtr.SegmentIndex, used)
+	return fmt.Errorf("%w builtin: %s used: %s stopPtr: %s", ErrInvalidStopPointer, builtinName, usedPtr, stopPtr)
 }
 
+// Values the secure run allows in a builtin's segment
+type ValuePolicy int
+
+const (
+	// Only felts can be written to the builtin's segment
+	FeltValuesOnly ValuePolicy = iota
+	// Relocatable values can be written to the builtin's segment as long as they point into the segment itself
+	RelocatableValuesWithinSegment
+	// Any value can be written to the builtin's segment
+	AnyValues
+)
+
 type BuiltinRunner interface {
 	// Returns the first address of the builtin's memory segment
 	Base() memory.Relocatable
@@ -52,7 +66,7 @@ type BuiltinRunner interface {
 	// Returns the list of memory addresses used by the builtin
 	GetMemoryAccesses(*memory.MemorySegmentManager) ([]memory.Relocatable, error)
 	GetRangeCheckUsage(*memory.Memory) (*uint, *uint)
-	GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error)
+	GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error)
 	GetUsedDilutedCheckUnits(dilutedSpacing uint, dilutedNBits uint) uint
 	GetUsedCellsAndAllocatedSizes(segments *memory.MemorySegmentManager, currentStep uint) (uint, uint, error)
 	FinalStack(segments *memory.MemorySegmentManager, pointer memory.Relocatable) (memory.Relocatable, error)
@@ -60,6 +74,23 @@ type BuiltinRunner interface {
 	GetMemorySegmentAddresses() (memory.Relocatable, memory.Relocatable, error)
 	// Amount of builtin instances used
 	GetUsedInstances(*memory.MemorySegmentManager) (uint, error)
+	// Returns the values the secure run allows in the builtin's segment
+	ValuePolicy() ValuePolicy
+	// Returns the builtin's data that isn't stored in memory, nil if it has none
+	GetAdditionalData() BuiltinAdditionalData
+	// Restores the builtin's data that isn't stored in memory (ie: from a Cairo PIE), extending the current one
+	SetAdditionalData(BuiltinAdditionalData) error
+	// Returns an independent copy of the builtin, its stop pointer & the data it holds (ie: signatures) aren't shared
+	Clone() BuiltinRunner
+}
+
+// Returns a copy of a builtin's stop pointer, so that clones don't share it
+func cloneStopPtr(stopPtr *uint) *uint {
+	if stopPtr == nil {
+		return nil
+	}
+	clone := *stopPtr
+	return &clone
 }
 
 func RunSecurityChecksForBuiltin(builtin BuiltinRunner, segments *memory.MemorySegmentManager) error {
@@ -89,6 +120,7 @@ func RunSecurityChecksForBuiltin(builtin BuiltinRunner, segments *memory.MemoryS
 	maxOffset := offsets[len(offsets)-1]
 
 	n := (maxOffset / int(cellsPerInstance)) + 1
+	logging.Default().Debug("Running builtin security checks", logging.F("builtin", builtin.Name()), logging.F("instances", n))
 	//Verify that n is not too large to make sure the expectedOffsets list that is constructed below is not too large.
 	if n > len(offsets)/int(nInputCells) {
 		return errors.Errorf("Missing memory cells for %s", builtin.Name())
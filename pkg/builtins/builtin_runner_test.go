@@ -103,3 +103,34 @@ func TestRunSecurityChecksValidateOutputCellsNotDeducedErr(t *testing.T) {
 		t.Errorf("RunSecurityChecks should have failed")
 	}
 }
+
+func TestCloneDoesNotShareStopPtr(t *testing.T) {
+	for _, name := range builtins.BuiltinNames() {
+		builtin, err := builtins.NewBuiltinRunner(name, 8)
+		if err != nil {
+			t.Fatal(err)
+		}
+		segments := memory.NewMemorySegmentManager()
+		segments.AddSegment()
+		builtin.InitializeSegments(&segments)
+		builtin.Include(true)
+		segments.SegmentUsedSizes = map[uint]uint{uint(builtin.Base().SegmentIndex): 0}
+
+		clone := builtin.Clone()
+		if clone == builtin || clone.Name() != name || clone.Base() != builtin.Base() {
+			t.Errorf("Wrong clone of %s: %+v", name, clone)
+		}
+		// Sets the clone's stop pointer
+		stack := segments.AddSegment()
+		segments.Memory.Insert(stack, memory.NewMaybeRelocatableRelocatable(builtin.Base()))
+		if _, err := clone.FinalStack(&segments, stack.AddUint(1)); err != nil {
+			t.Fatalf("FinalStack of the %s clone failed with error: %s", name, err)
+		}
+		if _, _, err := clone.GetMemorySegmentAddresses(); err != nil {
+			t.Errorf("The %s clone should have a stop pointer: %s", name, err)
+		}
+		if _, _, err := builtin.GetMemorySegmentAddresses(); err == nil {
+			t.Errorf("The stop pointer of %s shouldn't be shared with its clone", name)
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package builtins_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Behaviour every builtin runner has to conform to, checked for each builtin of the registry
+// New builtins are covered as soon as they are registered in NewBuiltinRunner
+func TestBuiltinConformance(t *testing.T) {
+	for _, name := range builtins.BuiltinNames() {
+		name := name
+		newBuiltin := func(t *testing.T) builtins.BuiltinRunner {
+			builtin, err := builtins.NewBuiltinRunner(name, 16)
+			if err != nil {
+				t.Fatalf("NewBuiltinRunner failed with error: %s", err)
+			}
+			return builtin
+		}
+		t.Run(name, func(t *testing.T) {
+			t.Run("SegmentInit", func(t *testing.T) { checkSegmentInit(t, newBuiltin(t)) })
+			t.Run("InitialStack", func(t *testing.T) { checkInitialStack(t, newBuiltin(t)) })
+			t.Run("DeductionDeterminism", func(t *testing.T) { checkDeductionDeterminism(t, newBuiltin(t), newBuiltin(t)) })
+			t.Run("FinalStackIncluded", func(t *testing.T) { checkFinalStackIncluded(t, newBuiltin(t)) })
+			t.Run("FinalStackExcluded", func(t *testing.T) { checkFinalStackExcluded(t, newBuiltin(t)) })
+			t.Run("UsedCells", func(t *testing.T) { checkUsedCells(t, newBuiltin(t)) })
+		})
+	}
+}
+
+func checkSegmentInit(t *testing.T, builtin builtins.BuiltinRunner) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	segments.AddSegment()
+	builtin.InitializeSegments(&segments)
+	if builtin.Base() != memory.NewRelocatable(2, 0) {
+		t.Errorf("The builtin's base should be the start of the new segment (2,0), got %s", builtin.Base())
+	}
+	if segments.Memory.NumSegments() != 3 {
+		t.Errorf("InitializeSegments should add exactly one segment, got %d segments", segments.Memory.NumSegments())
+	}
+	if builtin.InputCellsPerInstance() == 0 || builtin.InputCellsPerInstance() > builtin.CellsPerInstance() {
+		t.Errorf("Invalid cells per instance: %d inputs out of %d", builtin.InputCellsPerInstance(), builtin.CellsPerInstance())
+	}
+}
+
+func checkInitialStack(t *testing.T, builtin builtins.BuiltinRunner) {
+	segments := memory.NewMemorySegmentManager()
+	builtin.InitializeSegments(&segments)
+	builtin.Include(true)
+	expected := []memory.MaybeRelocatable{*memory.NewMaybeRelocatableRelocatable(builtin.Base())}
+	if stack := builtin.InitialStack(); !reflect.DeepEqual(stack, expected) {
+		t.Errorf("The initial stack of an included builtin should be its base. Expected %v, got %v", expected, stack)
+	}
+	builtin.Include(false)
+	if stack := builtin.InitialStack(); len(stack) != 0 {
+		t.Errorf("The initial stack of an excluded builtin should be empty, got %v", stack)
+	}
+}
+
+// Writes the input cells of the builtin's first nInstances instances, deducing & writing their output cells
+// Returns the number of cells of the segment written
+func writeInstances(t *testing.T, builtin builtins.BuiltinRunner, segments *memory.MemorySegmentManager, nInstances uint) uint {
+	base := builtin.Base()
+	written := uint(0)
+	for i := uint(0); i < nInstances; i++ {
+		for j := uint(0); j < builtin.CellsPerInstance(); j++ {
+			addr := memory.NewRelocatable(base.SegmentIndex, i*builtin.CellsPerInstance()+j)
+			value := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(uint64(addr.Offset + 1)))
+			if j >= builtin.InputCellsPerInstance() {
+				// Some builtins reject these inputs (ie: points outside the curve), their outputs are left unset
+				if value, _ = builtin.DeduceMemoryCell(addr, &segments.Memory); value == nil {
+					continue
+				}
+			}
+			if err := segments.Memory.Insert(addr, value); err != nil {
+				t.Fatalf("Insert failed with error: %s", err)
+			}
+			written = addr.Offset + 1
+		}
+	}
+	return written
+}
+
+// Both builtins have to deduce the same values, and the first one to either deduce them again or skip them (ie: the
+// pedersen runner skips the cells it already verified)
+func checkDeductionDeterminism(t *testing.T, builtin builtins.BuiltinRunner, other builtins.BuiltinRunner) {
+	deduceAll := func(builtin builtins.BuiltinRunner) ([]*memory.MaybeRelocatable, []error) {
+		segments := memory.NewMemorySegmentManager()
+		builtin.InitializeSegments(&segments)
+		for i := uint(0); i < 2*builtin.CellsPerInstance(); i++ {
+			addr := memory.NewRelocatable(builtin.Base().SegmentIndex, i)
+			if i%builtin.CellsPerInstance() < builtin.InputCellsPerInstance() {
+				if err := segments.Memory.Insert(addr, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(uint64(i+1)))); err != nil {
+					t.Fatalf("Insert failed with error: %s", err)
+				}
+			}
+		}
+		// The third instance has no inputs
+		var values []*memory.MaybeRelocatable
+		var errs []error
+		for i := uint(0); i < 3*builtin.CellsPerInstance(); i++ {
+			value, err := builtin.DeduceMemoryCell(memory.NewRelocatable(builtin.Base().SegmentIndex, i), &segments.Memory)
+			if i%builtin.CellsPerInstance() < builtin.InputCellsPerInstance() && value != nil {
+				t.Errorf("Input cell %d shouldn't be deduced, got %v", i, value)
+			}
+			if i >= 2*builtin.CellsPerInstance() && value != nil {
+				t.Errorf("Cell %d of an instance without inputs shouldn't be deduced, got %v", i, value)
+			}
+			values = append(values, value)
+			errs = append(errs, err)
+		}
+		return values, errs
+	}
+	values, errs := deduceAll(builtin)
+	otherValues, otherErrs := deduceAll(other)
+	if !reflect.DeepEqual(values, otherValues) {
+		t.Errorf("Deductions differ between runners: %v and %v", values, otherValues)
+	}
+	againValues, _ := deduceAll(builtin)
+	for i := range againValues {
+		if againValues[i] != nil && !reflect.DeepEqual(againValues[i], values[i]) {
+			t.Errorf("Deduction of cell %d changed when repeated: %v and %v", i, values[i], againValues[i])
+		}
+	}
+	for i := range errs {
+		if (errs[i] == nil) != (otherErrs[i] == nil) || (errs[i] != nil && errs[i].Error() != otherErrs[i].Error()) {
+			t.Errorf("Deduction errors of cell %d differ between runs: %v and %v", i, errs[i], otherErrs[i])
+		}
+	}
+}
+
+func checkFinalStackIncluded(t *testing.T, builtin builtins.BuiltinRunner) {
+	segments := memory.NewMemorySegmentManager()
+	stack := segments.AddSegment()
+	builtin.InitializeSegments(&segments)
+	builtin.Include(true)
+	writeInstances(t, builtin, &segments, 2)
+	segments.ComputeEffectiveSizes()
+	used := 2 * builtin.CellsPerInstance()
+	stopPtr := memory.NewRelocatable(builtin.Base().SegmentIndex, used)
+
+	if _, err := builtin.FinalStack(&segments, stack); !errors.Is(err, builtins.ErrNoStopPointer) {
+		t.Errorf("Expected ErrNoStopPointer for an empty stack, got %v", err)
+	}
+	for _, invalid := range []struct {
+		value    memory.Relocatable
+		expected error
+	}{
+		{memory.NewRelocatable(stack.SegmentIndex, used), builtins.ErrInvalidStopPointerIndex},
+		{memory.NewRelocatable(builtin.Base().SegmentIndex, used-1), builtins.ErrInvalidStopPointer},
+	} {
+		invalidSegments := segments.Clone()
+		if err := invalidSegments.Memory.Insert(stack, memory.NewMaybeRelocatableRelocatable(invalid.value)); err != nil {
+			t.Fatalf("Insert failed with error: %s", err)
+		}
+		if _, err := builtin.FinalStack(&invalidSegments, stack.AddUint(1)); !errors.Is(err, invalid.expected) {
+			t.Errorf("Expected %v for stop pointer %s, got %v", invalid.expected, invalid.value, err)
+		}
+	}
+
+	if err := segments.Memory.Insert(stack, memory.NewMaybeRelocatableRelocatable(stopPtr)); err != nil {
+		t.Fatalf("Insert failed with error: %s", err)
+	}
+	pointer, err := builtin.FinalStack(&segments, stack.AddUint(1))
+	if err != nil {
+		t.Fatalf("FinalStack failed with error: %s", err)
+	}
+	if pointer != stack {
+		t.Errorf("FinalStack should pop the stop pointer. Expected %s, got %s", stack, pointer)
+	}
+	base, stop, err := builtin.GetMemorySegmentAddresses()
+	if err != nil || base != builtin.Base() || stop != stopPtr {
+		t.Errorf("Wrong segment addresses. Expected (%s, %s), got (%s, %s, %v)", builtin.Base(), stopPtr, base, stop, err)
+	}
+}
+
+func checkFinalStackExcluded(t *testing.T, builtin builtins.BuiltinRunner) {
+	segments := memory.NewMemorySegmentManager()
+	stack := segments.AddSegment()
+	builtin.InitializeSegments(&segments)
+	builtin.Include(false)
+	if _, _, err := builtin.GetMemorySegmentAddresses(); !errors.Is(err, builtins.ErrNoStopPointer) {
+		t.Errorf("Expected ErrNoStopPointer before FinalStack, got %v", err)
+	}
+	pointer, err := builtin.FinalStack(&segments, stack.AddUint(3))
+	if err != nil || pointer != stack.AddUint(3) {
+		t.Errorf("FinalStack of an excluded builtin should leave the stack untouched, got %s, %v", pointer, err)
+	}
+	base, stop, err := builtin.GetMemorySegmentAddresses()
+	if err != nil || stop != base {
+		t.Errorf("An excluded builtin should have an empty segment, got (%s, %s, %v)", base, stop, err)
+	}
+}
+
+func checkUsedCells(t *testing.T, builtin builtins.BuiltinRunner) {
+	segments := memory.NewMemorySegmentManager()
+	builtin.InitializeSegments(&segments)
+	written := writeInstances(t, builtin, &segments, 3)
+	segments.ComputeEffectiveSizes()
+
+	instances, err := builtin.GetUsedInstances(&segments)
+	if err != nil || instances != 3 {
+		t.Errorf("Expected 3 used instances, got %d, %v", instances, err)
+	}
+	used, allocated, err := builtin.GetUsedCellsAndAllocatedSizes(&segments, 4096)
+	if err != nil {
+		t.Fatalf("GetUsedCellsAndAllocatedSizes failed with error: %s", err)
+	}
+	if used != written {
+		t.Errorf("Expected %d used cells, got %d", written, used)
+	}
+	if allocated < used {
+		t.Errorf("The allocated size (%d) should fit the used cells (%d)", allocated, used)
+	}
+}
@@ -13,7 +13,7 @@ import (
 type EcOpBuiltinRunner struct {
 	included              bool
 	base                  memory.Relocatable
-	cache                 map[memory.Relocatable]lambdaworks.Felt
+	cache                 memory.RelocatableMap[lambdaworks.Felt]
 	scalar_height         uint32
 	instancesPerComponent uint
 	ratio                 uint
@@ -57,7 +57,7 @@ func PrimeError(value big.Int) error {
 
 func NewEcOpBuiltinRunner(ratio uint) *EcOpBuiltinRunner {
 	return &EcOpBuiltinRunner{
-		cache:                 make(map[memory.Relocatable]lambdaworks.Felt),
+		cache:                 memory.NewRelocatableMap[lambdaworks.Felt](),
 		scalar_height:         256,
 		instancesPerComponent: 1,
 		ratio:                 ratio,
@@ -139,7 +139,7 @@ func (runner *EcOpBuiltinRunner) GetRangeCheckUsage(memory *memory.Memory) (*uin
 	return nil, nil
 }
 
-func (runner *EcOpBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+func (runner *EcOpBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	return 0, nil
 }
 
@@ -198,7 +198,7 @@ func (ec *EcOpBuiltinRunner) DeduceMemoryCell(address memory.Relocatable, mem *m
 		return nil, err
 	}
 
-	number, is_cached := ec.cache[address]
+	number, is_cached := ec.cache.Get(address)
 
 	if is_cached {
 		return memory.NewMaybeRelocatableFelt(number), nil
@@ -245,8 +245,8 @@ func (ec *EcOpBuiltinRunner) DeduceMemoryCell(address memory.Relocatable, mem *m
 	felt_result_x := lambdaworks.FeltFromBeBytes((*[32]byte)(result.X.Bytes()))
 	felt_result_y := lambdaworks.FeltFromBeBytes((*[32]byte)(result.Y.Bytes()))
 
-	ec.cache[x_addr] = felt_result_x
-	ec.cache[x_addr.AddUint(1)] = felt_result_y
+	ec.cache.Set(x_addr, felt_result_x)
+	ec.cache.Set(x_addr.AddUint(1), felt_result_y)
 
 	if index-uint(INPUT_CELLS_PER_EC_OP) == 0 {
 		return memory.NewMaybeRelocatableFelt(felt_result_x), nil
@@ -416,3 +416,22 @@ func (b *EcOpBuiltinRunner) GetMemorySegmentAddresses() (memory.Relocatable, mem
 	}
 	return b.base, memory.NewRelocatable(b.base.SegmentIndex, *b.StopPtr), nil
 }
+
+func (r *EcOpBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+func (r *EcOpBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	return nil
+}
+
+func (r *EcOpBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	return noAdditionalData(r, data)
+}
+
+func (r *EcOpBuiltinRunner) Clone() BuiltinRunner {
+	clone := *r
+	clone.cache = r.cache.Clone()
+	clone.StopPtr = cloneStopPtr(r.StopPtr)
+	return &clone
+}
@@ -0,0 +1,84 @@
+package builtins
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Attribute of the output builtin holding the tree structure of the fact topology, set by the bootloader
+const GPS_FACT_TOPOLOGY = "gps_fact_topology"
+
+// Structure of the program output used by SHARP to build the facts of aggregated proofs (ie: gps_fact_topology)
+type FactTopology struct {
+	// Pairs of (number of pages, number of nodes) describing the merkle tree of the output pages
+	TreeStructure []uint `json:"tree_structure"`
+	// Sizes of the output pages, page 0 being the output cells that precede page 1
+	PageSizes []uint `json:"page_sizes"`
+}
+
+var ErrInvalidFactTopology = errors.New("Invalid fact topology")
+
+func InvalidFactTopologyError(format string, args ...any) error {
+	return errors.Wrapf(ErrInvalidFactTopology, format, args...)
+}
+
+// Returns the fact topology of the output, built from the pages & the GPS_FACT_TOPOLOGY attribute of the builtin
+// The pages must be numbered from 1 & cover the end of the output without gaps
+// Without the attribute the output is a single page, with a tree structure of [1, 0]
+// The stop pointer has to be known, so the run has to be ended
+func (r *OutputBuiltinRunner) GetFactTopology() (FactTopology, error) {
+	if r.StopPtr == nil {
+		return FactTopology{}, NewErrNoStopPointer(r.Name())
+	}
+	treeStructure, ok := r.attributes[GPS_FACT_TOPOLOGY]
+	if !ok {
+		if len(r.pages) != 0 {
+			return FactTopology{}, InvalidFactTopologyError("the output has pages but no %s attribute", GPS_FACT_TOPOLOGY)
+		}
+		treeStructure = []uint{1, 0}
+	}
+	if len(treeStructure) == 0 || len(treeStructure)%2 != 0 {
+		return FactTopology{}, InvalidFactTopologyError("the tree structure must have an even, non zero length, got %v", treeStructure)
+	}
+	pageSizes, err := r.pageSizes(*r.StopPtr)
+	if err != nil {
+		return FactTopology{}, err
+	}
+	return FactTopology{TreeStructure: append([]uint(nil), treeStructure...), PageSizes: pageSizes}, nil
+}
+
+// Returns the sizes of the pages, preceded by the size of page 0 (ie: get_page_sizes_from_page_dict)
+func (r *OutputBuiltinRunner) pageSizes(outputSize uint) ([]uint, error) {
+	ids := make([]uint, 0, len(r.pages))
+	for id := range r.pages {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	pageSizes := []uint{outputSize}
+	var expectedStart uint
+	for i, id := range ids {
+		page := r.pages[id]
+		if id != uint(i+1) {
+			return nil, InvalidFactTopologyError("expected page id %d, found %d", i+1, id)
+		}
+		if id == 1 {
+			if page.Start > outputSize {
+				return nil, InvalidFactTopologyError("invalid start %d of page 1", page.Start)
+			}
+			pageSizes[0] = page.Start
+		} else if page.Start != expectedStart {
+			return nil, InvalidFactTopologyError("expected page %d to start at %d, found %d", id, expectedStart, page.Start)
+		}
+		if page.Size > outputSize {
+			return nil, InvalidFactTopologyError("invalid size %d of page %d", page.Size, id)
+		}
+		expectedStart = page.Start + page.Size
+		pageSizes = append(pageSizes, page.Size)
+	}
+	if len(ids) != 0 && expectedStart != outputSize {
+		return nil, InvalidFactTopologyError("the pages must cover the end of the output")
+	}
+	return pageSizes, nil
+}
@@ -0,0 +1,68 @@
+package builtins_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+)
+
+func outputWithSize(size uint) *builtins.OutputBuiltinRunner {
+	output := builtins.NewOutputBuiltinRunner()
+	output.StopPtr = &size
+	return output
+}
+
+func TestGetFactTopologyNoPages(t *testing.T) {
+	topology, err := outputWithSize(5).GetFactTopology()
+	expected := builtins.FactTopology{TreeStructure: []uint{1, 0}, PageSizes: []uint{5}}
+	if err != nil || !reflect.DeepEqual(topology, expected) {
+		t.Errorf("Wrong fact topology. Expected %v, got %v, %v", expected, topology, err)
+	}
+}
+
+func TestGetFactTopologyPages(t *testing.T) {
+	output := outputWithSize(10)
+	output.AddPage(2, 6, 4)
+	output.AddPage(1, 2, 4)
+	output.AddAttribute(builtins.GPS_FACT_TOPOLOGY, []uint{2, 1, 0, 2})
+	topology, err := output.GetFactTopology()
+	expected := builtins.FactTopology{TreeStructure: []uint{2, 1, 0, 2}, PageSizes: []uint{2, 4, 4}}
+	if err != nil || !reflect.DeepEqual(topology, expected) {
+		t.Errorf("Wrong fact topology. Expected %v, got %v, %v", expected, topology, err)
+	}
+}
+
+func TestGetFactTopologyInvalid(t *testing.T) {
+	cases := map[string]func(output *builtins.OutputBuiltinRunner){
+		"PagesWithoutTopology": func(output *builtins.OutputBuiltinRunner) { output.AddPage(1, 0, 10) },
+		"OddTreeStructure": func(output *builtins.OutputBuiltinRunner) {
+			output.AddAttribute(builtins.GPS_FACT_TOPOLOGY, []uint{1})
+		},
+		"FirstPageNotOne": func(output *builtins.OutputBuiltinRunner) {
+			output.AddPage(2, 0, 10)
+			output.AddAttribute(builtins.GPS_FACT_TOPOLOGY, []uint{1, 0})
+		},
+		"Gap": func(output *builtins.OutputBuiltinRunner) {
+			output.AddPage(1, 0, 4)
+			output.AddPage(2, 5, 5)
+			output.AddAttribute(builtins.GPS_FACT_TOPOLOGY, []uint{2, 0})
+		},
+		"OutputNotCovered": func(output *builtins.OutputBuiltinRunner) {
+			output.AddPage(1, 0, 4)
+			output.AddAttribute(builtins.GPS_FACT_TOPOLOGY, []uint{1, 0})
+		},
+	}
+	for name, setup := range cases {
+		output := outputWithSize(10)
+		setup(output)
+		if _, err := output.GetFactTopology(); !errors.Is(err, builtins.ErrInvalidFactTopology) {
+			t.Errorf("%s: expected ErrInvalidFactTopology, got %v", name, err)
+		}
+	}
+
+	if _, err := builtins.NewOutputBuiltinRunner().GetFactTopology(); !errors.Is(err, builtins.ErrNoStopPointer) {
+		t.Errorf("Expected ErrNoStopPointer without a stop pointer, got %v", err)
+	}
+}
@@ -1,11 +1,11 @@
 package builtins
 
 import (
-	"encoding/binary"
 	"math/bits"
 
 	"github.com/pkg/errors"
 
+	"github.com/lambdaclass/cairo-vm.go/pkg/keccak_utils"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	"github.com/lambdaclass/cairo-vm.go/pkg/utils"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
@@ -20,14 +20,14 @@ const KECCAK_INPUT_BYTES_LENTGH = 25
 type KeccakBuiltinRunner struct {
 	base                  Relocatable
 	included              bool
-	cache                 map[Relocatable]Felt
+	cache                 RelocatableMap[Felt]
 	ratio                 uint
 	instancesPerComponent uint
 	StopPtr               *uint
 }
 
 func NewKeccakBuiltinRunner(ratio uint) *KeccakBuiltinRunner {
-	return &KeccakBuiltinRunner{ratio: ratio, cache: make(map[Relocatable]Felt), instancesPerComponent: 16}
+	return &KeccakBuiltinRunner{ratio: ratio, cache: NewRelocatableMap[Felt](), instancesPerComponent: 16}
 }
 
 func DefaultKeccakBuiltinRunner() *KeccakBuiltinRunner {
@@ -64,7 +64,7 @@ func (k *KeccakBuiltinRunner) DeduceMemoryCell(address Relocatable, mem *Memory)
 		return nil, nil
 	}
 
-	value, ok := k.cache[address]
+	value, ok := k.cache.Get(address)
 	if ok {
 		return NewMaybeRelocatableFelt(value), nil
 	}
@@ -72,53 +72,35 @@ func (k *KeccakBuiltinRunner) DeduceMemoryCell(address Relocatable, mem *Memory)
 	input_start_addr, _ := address.SubUint(index)
 	output_start_address := input_start_addr.AddUint(KECCAK_INPUT_CELLS_PER_INSTANCE)
 
-	input_message := make([]byte, 0, 25*KECCAK_INPUT_CELLS_PER_INSTANCE)
-
-	// This for block creates an input message of 200 bytes. The slice is created
-	// from 8 chunks of 25 bytes which are felts. To make sure nothing breaks, the
-	// numbers are checked to need at most 25 bytes for their representation, if this
+	// The state is made of the 8 input felts, each one taking 25 bytes. To make sure nothing breaks,
+	// the numbers are checked to need at most 25 bytes for their representation, if this
 	// doesn't hold, an error will be returned.
-	for i := uint(0); i < KECCAK_INPUT_CELLS_PER_INSTANCE; i++ {
-		felt, err := mem.GetFelt(input_start_addr.AddUint(i))
-		if err != nil {
-			return nil, err
-		}
-
-		if !(felt.Bits() <= 200) {
+	var inputMessage [keccak_utils.KECCAK_STATE_SIZE_BYTES]byte
+	inputs, err := mem.GetFeltRange(input_start_addr, KECCAK_INPUT_CELLS_PER_INSTANCE)
+	if err != nil {
+		return nil, err
+	}
+	for i, felt := range inputs {
+		if !(felt.Bits() <= KECCAK_INPUT_BIT_LENTGH) {
 			return nil, errors.New("Expected integer to be smaller than 2^200")
 		}
-		// Padding should already occur
 		le_bytes := felt.ToLeBytes()
-		input_message = append(input_message, le_bytes[:25]...)
+		copy(inputMessage[KECCAK_INPUT_BYTES_LENTGH*i:], le_bytes[:KECCAK_INPUT_BYTES_LENTGH])
 	}
 
-	output_message_bytes := append(make([]byte, 0, 200), input_message...)
-	// Make sure output message is padded
-	output_message_bytes = output_message_bytes[:cap(output_message_bytes)]
-
-	// Type conversions are needed to use keccakF1600
-	var output_message_u64 [25]uint64
-	for i := 0; i < 25; i++ {
-		output_message_u64[i] = binary.LittleEndian.Uint64(output_message_bytes[8*i : 8*i+8])
-	}
-	KeccakF1600(&output_message_u64)
-
-	// Convert back to bytes
-	output_message := make([]byte, 0, 200)
-	for _, num := range output_message_u64 {
-		var chunk []byte
-		chunk = binary.LittleEndian.AppendUint64(chunk, num)
-		output_message = append(output_message, chunk...)
-	}
+	state := keccak_utils.StateFromLeBytes(&inputMessage)
+	KeccakF1600(&state)
+	output_message := keccak_utils.StateToLeBytes(&state)
 
 	for i := uint(0); i < KECCAK_INPUT_CELLS_PER_INSTANCE; i++ {
-		bytes := output_message[25*i : 25*i+25]
+		bytes := output_message[KECCAK_INPUT_BYTES_LENTGH*i : KECCAK_INPUT_BYTES_LENTGH*(i+1)]
 		var padded_bytes [32]byte
 		copy(padded_bytes[:], bytes)
 		felt := FeltFromLeBytes(&padded_bytes)
-		k.cache[output_start_address.AddUint(i)] = felt
+		k.cache.Set(output_start_address.AddUint(i), felt)
 	}
-	return NewMaybeRelocatableFelt(k.cache[address]), nil
+	value, _ = k.cache.Get(address)
+	return NewMaybeRelocatableFelt(value), nil
 }
 
 // The following code was copied from https://github.com/golang/crypto/blob/a3485e174077e5296d3d4a43ca31d2d21b40be2c/sha3/keccakf.go
@@ -152,7 +134,7 @@ var rc = [24]uint64{
 
 // keccakF1600 applies the Keccak permutation to a 1600b-wide
 // state represented as a slice of 25 uint64s.
-func KeccakF1600(a *[25]uint64) {
+func KeccakF1600(a *[keccak_utils.KECCAK_STATE_SIZE_FELTS]uint64) {
 	// Implementation translated from Keccak-inplace.c
 	// in the keccak reference code.
 	var t, bc0, bc1, bc2, bc3, bc4, d0, d1, d2, d3, d4 uint64
@@ -594,7 +576,7 @@ func (runner *KeccakBuiltinRunner) GetRangeCheckUsage(memory *memory.Memory) (*u
 	return nil, nil
 }
 
-func (runner *KeccakBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+func (runner *KeccakBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	return 0, nil
 }
 
@@ -687,3 +669,22 @@ func (b *KeccakBuiltinRunner) GetMemorySegmentAddresses() (memory.Relocatable, m
 	}
 	return b.base, memory.NewRelocatable(b.base.SegmentIndex, *b.StopPtr), nil
 }
+
+func (k *KeccakBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+func (k *KeccakBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	return nil
+}
+
+func (k *KeccakBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	return noAdditionalData(k, data)
+}
+
+func (k *KeccakBuiltinRunner) Clone() BuiltinRunner {
+	clone := *k
+	clone.cache = k.cache.Clone()
+	clone.StopPtr = cloneStopPtr(k.StopPtr)
+	return &clone
+}
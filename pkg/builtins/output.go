@@ -8,9 +8,11 @@ const OUTPUT_BUILTIN_NAME = "output"
 const OUTPUT_CELLS_PER_INSTANCE = 1
 
 type OutputBuiltinRunner struct {
-	base     memory.Relocatable
-	included bool
-	StopPtr  *uint
+	base       memory.Relocatable
+	included   bool
+	StopPtr    *uint
+	pages      map[uint]OutputPage
+	attributes map[string][]uint
 }
 
 func NewOutputBuiltinRunner() *OutputBuiltinRunner {
@@ -66,7 +68,7 @@ func (runner *OutputBuiltinRunner) GetRangeCheckUsage(memory *memory.Memory) (*u
 	return nil, nil
 }
 
-func (runner *OutputBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+func (runner *OutputBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	return 0, nil
 }
 
@@ -149,3 +151,70 @@ func (r *OutputBuiltinRunner) CellsPerInstance() uint {
 func (b *OutputBuiltinRunner) InputCellsPerInstance() uint {
 	return OUTPUT_CELLS_PER_INSTANCE
 }
+
+func (r *OutputBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+// Adds a page of the given size starting at the offset of the output segment
+func (r *OutputBuiltinRunner) AddPage(pageId uint, start uint, size uint) {
+	if r.pages == nil {
+		r.pages = make(map[uint]OutputPage)
+	}
+	r.pages[pageId] = OutputPage{Start: start, Size: size}
+}
+
+func (r *OutputBuiltinRunner) AddAttribute(name string, value []uint) {
+	if r.attributes == nil {
+		r.attributes = make(map[string][]uint)
+	}
+	r.attributes[name] = value
+}
+
+func (r *OutputBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	data := OutputAdditionalData{Pages: make(map[uint]OutputPage, len(r.pages)), Attributes: make(map[string][]uint, len(r.attributes))}
+	for id, page := range r.pages {
+		data.Pages[id] = page
+	}
+	for name, value := range r.attributes {
+		data.Attributes[name] = append([]uint(nil), value...)
+	}
+	return data
+}
+
+// Replaces the builtin's pages & attributes
+func (r *OutputBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	if data == nil {
+		return nil
+	}
+	outputData, ok := data.(OutputAdditionalData)
+	if !ok {
+		return unexpectedAdditionalDataError(r, data)
+	}
+	r.pages, r.attributes = nil, nil
+	for id, page := range outputData.Pages {
+		r.AddPage(id, page.Start, page.Size)
+	}
+	for name, value := range outputData.Attributes {
+		r.AddAttribute(name, append([]uint(nil), value...))
+	}
+	return nil
+}
+
+func (r *OutputBuiltinRunner) Clone() BuiltinRunner {
+	clone := *r
+	clone.StopPtr = cloneStopPtr(r.StopPtr)
+	if r.pages != nil {
+		clone.pages = make(map[uint]OutputPage, len(r.pages))
+		for id, page := range r.pages {
+			clone.pages[id] = page
+		}
+	}
+	if r.attributes != nil {
+		clone.attributes = make(map[string][]uint, len(r.attributes))
+		for name, value := range r.attributes {
+			clone.attributes[name] = append([]uint(nil), value...)
+		}
+	}
+	return &clone
+}
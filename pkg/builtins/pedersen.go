@@ -60,23 +60,20 @@ func (p *PedersenBuiltinRunner) InitialStack() []memory.MaybeRelocatable {
 }
 
 func (p *PedersenBuiltinRunner) DeduceMemoryCell(address memory.Relocatable, mem *memory.Memory) (*memory.MaybeRelocatable, error) {
-	if address.Offset%PEDERSEN_CELLS_PER_INSTANCE != PEDERSEN_INPUT_CELLS_PER_INSTANCE || p.CheckVerifiedAddresses(address) {
+	view := NewPedersenSegment(p, mem)
+	index, _, _ := view.Locate(address)
+	if !view.IsOutputCell(address) || p.CheckVerifiedAddresses(address) {
 		return nil, nil
 	}
 
-	feltA, err := mem.GetFelt(memory.Relocatable{SegmentIndex: address.SegmentIndex, Offset: address.Offset - 1})
-	if err != nil {
-		return nil, nil
-	}
-
-	feltB, err := mem.GetFelt(memory.Relocatable{SegmentIndex: address.SegmentIndex, Offset: address.Offset - 2})
-	if err != nil {
+	instance, ok := view.Instance(index)
+	if !ok {
 		return nil, nil
 	}
 
 	p.ResizeVerifiedAddresses(address)
 
-	hash := starknet_crypto.PedersenHash(feltB, feltA)
+	hash := starknet_crypto.PedersenHash(instance.A, instance.B)
 
 	return memory.NewMaybeRelocatableFelt(hash), nil
 }
@@ -85,7 +82,7 @@ func (p *PedersenBuiltinRunner) AddValidationRule(*memory.Memory) {
 }
 
 func (p *PedersenBuiltinRunner) CheckVerifiedAddresses(address memory.Relocatable) bool {
-	if len(p.verified_addresses) < int(address.Offset) {
+	if len(p.verified_addresses) <= int(address.Offset) {
 		return false
 	}
 
@@ -166,7 +163,7 @@ func (runner *PedersenBuiltinRunner) GetUsedDilutedCheckUnits(dilutedSpacing uin
 	return 0
 }
 
-func (runner *PedersenBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+func (runner *PedersenBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	return 0, nil
 }
 
@@ -239,3 +236,47 @@ func (b *PedersenBuiltinRunner) GetMemorySegmentAddresses() (memory.Relocatable,
 	}
 	return b.base, memory.NewRelocatable(b.base.SegmentIndex, *b.StopPtr), nil
 }
+
+func (p *PedersenBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+func (p *PedersenBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	data := make(HashAdditionalData, 0)
+	for offset, verified := range p.verified_addresses {
+		if verified {
+			data = append(data, memory.NewRelocatable(p.base.SegmentIndex, uint(offset)))
+		}
+	}
+	return data
+}
+
+// Marks the addresses as verified, which must belong to the builtin's segment
+func (p *PedersenBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	if data == nil {
+		return nil
+	}
+	hashData, ok := data.(HashAdditionalData)
+	if !ok {
+		return unexpectedAdditionalDataError(p, data)
+	}
+	for _, addr := range hashData {
+		if addr.SegmentIndex != p.base.SegmentIndex {
+			return InvalidAdditionalDataError(p.Name(), "address "+addr.ToString()+" is not in the builtin's segment")
+		}
+	}
+	for _, addr := range hashData {
+		for uint(len(p.verified_addresses)) <= addr.Offset {
+			p.verified_addresses = append(p.verified_addresses, false)
+		}
+		p.verified_addresses[addr.Offset] = true
+	}
+	return nil
+}
+
+func (p *PedersenBuiltinRunner) Clone() BuiltinRunner {
+	clone := *p
+	clone.verified_addresses = append([]bool(nil), p.verified_addresses...)
+	clone.StopPtr = cloneStopPtr(p.StopPtr)
+	return &clone
+}
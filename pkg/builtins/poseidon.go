@@ -15,14 +15,14 @@ const POSEIDON_INPUT_CELLS_PER_INSTANCE = 3
 type PoseidonBuiltinRunner struct {
 	base                  memory.Relocatable
 	included              bool
-	cache                 map[memory.Relocatable]lambdaworks.Felt
+	cache                 memory.RelocatableMap[lambdaworks.Felt]
 	ratio                 uint
 	instancesPerComponent uint
 	StopPtr               *uint
 }
 
 func NewPoseidonBuiltinRunner(ratio uint) *PoseidonBuiltinRunner {
-	return &PoseidonBuiltinRunner{cache: make(map[memory.Relocatable]lambdaworks.Felt), instancesPerComponent: 1, ratio: ratio}
+	return &PoseidonBuiltinRunner{cache: memory.NewRelocatableMap[lambdaworks.Felt](), instancesPerComponent: 1, ratio: ratio}
 }
 
 func (p *PoseidonBuiltinRunner) Base() memory.Relocatable {
@@ -52,7 +52,7 @@ func (p *PoseidonBuiltinRunner) DeduceMemoryCell(address memory.Relocatable, mem
 		return nil, nil
 	}
 
-	value, ok := p.cache[address]
+	value, ok := p.cache.Get(address)
 	if ok {
 		return memory.NewMaybeRelocatableFelt(value), nil
 	}
@@ -63,22 +63,21 @@ func (p *PoseidonBuiltinRunner) DeduceMemoryCell(address memory.Relocatable, mem
 	// Build the initial poseidon state
 	var poseidon_state [POSEIDON_INPUT_CELLS_PER_INSTANCE]lambdaworks.Felt
 
-	for i := uint(0); i < POSEIDON_INPUT_CELLS_PER_INSTANCE; i++ {
-		felt, err := mem.GetFelt(input_start_addr.AddUint(i))
-		if err != nil {
-			return nil, err
-		}
-		poseidon_state[i] = felt
+	inputs, err := mem.GetFeltRange(input_start_addr, POSEIDON_INPUT_CELLS_PER_INSTANCE)
+	if err != nil {
+		return nil, err
 	}
+	copy(poseidon_state[:], inputs)
 
 	// Run the poseidon permutation
 	starknet_crypto.PoseidonPermuteComp(&poseidon_state)
 
 	// Insert the new state into the corresponding output cells in the cache
 	for i, elem := range poseidon_state {
-		p.cache[output_start_address.AddUint(uint(i))] = elem
+		p.cache.Set(output_start_address.AddUint(uint(i)), elem)
 	}
-	return memory.NewMaybeRelocatableFelt(p.cache[address]), nil
+	value, _ = p.cache.Get(address)
+	return memory.NewMaybeRelocatableFelt(value), nil
 }
 
 func (p *PoseidonBuiltinRunner) AddValidationRule(*memory.Memory) {
@@ -151,7 +150,7 @@ func (runner *PoseidonBuiltinRunner) GetRangeCheckUsage(memory *memory.Memory) (
 	return nil, nil
 }
 
-func (runner *PoseidonBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+func (runner *PoseidonBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	return 0, nil
 }
 
@@ -228,3 +227,22 @@ func (b *PoseidonBuiltinRunner) GetMemorySegmentAddresses() (memory.Relocatable,
 	}
 	return b.base, memory.NewRelocatable(b.base.SegmentIndex, *b.StopPtr), nil
 }
+
+func (p *PoseidonBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+func (p *PoseidonBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	return nil
+}
+
+func (p *PoseidonBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	return noAdditionalData(p, data)
+}
+
+func (p *PoseidonBuiltinRunner) Clone() BuiltinRunner {
+	clone := *p
+	clone.cache = p.cache.Clone()
+	clone.StopPtr = cloneStopPtr(p.StopPtr)
+	return &clone
+}
@@ -1,8 +1,11 @@
 package builtins
 
 import (
+	"fmt"
 	"math"
+	"math/big"
 
+	"github.com/lambdaclass/cairo-vm.go/pkg/compat"
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	"github.com/lambdaclass/cairo-vm.go/pkg/utils"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
@@ -22,12 +25,17 @@ func RangeCheckError(err error) error {
 }
 
 func OutsideBoundsError(felt lambdaworks.Felt) error {
-	return RangeCheckError(errors.Errorf("Value %d is out of bounds [0, 2^128]", felt))
+	return compat.WithRustMessage(
+		RangeCheckError(errors.Errorf("Value %s is out of bounds [0, 2^128]", felt.ToSignedFeltString())),
+		fmt.Sprintf("Range-check validation failed, number %s is out of valid range [0, %s]", felt.ToBigInt(), new(big.Int).Lsh(big.NewInt(1), 128)),
+	)
 }
 
 func NotAFeltError(addr memory.Relocatable, val memory.MaybeRelocatable) error {
-	rel, _ := val.GetRelocatable()
-	return RangeCheckError(errors.Errorf("Value %d found in %d is not a field element", rel, addr))
+	return compat.WithRustMessage(
+		RangeCheckError(errors.Errorf("Value %s found in %s is not a field element", val, addr)),
+		fmt.Sprintf("Range-check validation failed, encountered non-int value at address %s", addr.RustString()),
+	)
 }
 
 type RangeCheckBuiltinRunner struct {
@@ -165,42 +173,25 @@ func (r *RangeCheckBuiltinRunner) GetUsedCellsAndAllocatedSizes(segments *memory
 	return used, size, nil
 }
 
+// Returns the min & max of the INNER_RC_BOUND parts the values of the builtin's segment are decomposed into
+// Returns nil if the segment holds no felts
 func (runner *RangeCheckBuiltinRunner) GetRangeCheckUsage(memory *memory.Memory) (*uint, *uint) {
-	rangeCheckSegment := memory.GetSegment(runner.base.SegmentIndex)
+	var rcMin, rcMax *uint
 
-	if rangeCheckSegment == nil {
-		return nil, nil
-	}
-
-	var rcMin = new(uint)
-	var rcMax = new(uint)
-
-	for _, value := range rangeCheckSegment {
+	for _, value := range memory.GetSegment(runner.base.SegmentIndex) {
 		feltValue, isFelt := value.GetFelt()
-
 		if !isFelt {
-			// Is this correct? Feels a little weird, like we should return an error
-			return nil, nil
+			continue
 		}
 
 		feltDigits := feltValue.ToLeBytes()
-		for i := 0; i < 32; i += 2 {
-			var tempValue = (uint16(feltDigits[i+1]) << 8) | uint16((feltDigits[i]))
-
-			if rcMin == nil {
-				*rcMin = uint(tempValue)
-			}
-
-			if rcMax == nil {
-				*rcMax = uint(tempValue)
-			}
-
-			if uint(tempValue) < *rcMin {
-				*rcMin = uint(tempValue)
+		for i := 0; i < RANGE_CHECK_N_PARTS; i++ {
+			part := uint(feltDigits[2*i+1])<<8 | uint(feltDigits[2*i])
+			if rcMin == nil || part < *rcMin {
+				rcMin = &part
 			}
-
-			if uint(tempValue) > *rcMax {
-				*rcMax = uint(tempValue)
+			if rcMax == nil || part > *rcMax {
+				rcMax = &part
 			}
 		}
 	}
@@ -208,7 +199,8 @@ func (runner *RangeCheckBuiltinRunner) GetRangeCheckUsage(memory *memory.Memory)
 	return rcMin, rcMax
 }
 
-func (runner *RangeCheckBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+// Returns the number of range check units used by the builtin, one per part of each used cell
+func (runner *RangeCheckBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	usedCells, _, err := runner.GetUsedCellsAndAllocatedSizes(segments, currentStep)
 
 	if err != nil {
@@ -291,3 +283,21 @@ func (b *RangeCheckBuiltinRunner) GetMemorySegmentAddresses() (memory.Relocatabl
 	}
 	return b.base, memory.NewRelocatable(b.base.SegmentIndex, *b.StopPtr), nil
 }
+
+func (r *RangeCheckBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+func (r *RangeCheckBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	return nil
+}
+
+func (r *RangeCheckBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	return noAdditionalData(r, data)
+}
+
+func (r *RangeCheckBuiltinRunner) Clone() BuiltinRunner {
+	clone := *r
+	clone.StopPtr = cloneStopPtr(r.StopPtr)
+	return &clone
+}
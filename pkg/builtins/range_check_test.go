@@ -1,6 +1,7 @@
 package builtins_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
@@ -44,6 +45,18 @@ func TestDeduceMemoryCellRangeCheck(t *testing.T) {
 	}
 }
 
+func TestDeduceMemoryCellRangeCheckNotAFelt(t *testing.T) {
+	rangeCheck := builtins.DefaultRangeCheckBuiltinRunner()
+	segments := memory.NewMemorySegmentManager()
+	rangeCheck.InitializeSegments(&segments)
+	rangeCheck.AddValidationRule(&segments.Memory)
+
+	err := segments.Memory.Insert(rangeCheck.Base(), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 2)))
+	if err == nil || !strings.Contains(err.Error(), "Value {1:2} found in {0:0} is not a field element") {
+		t.Errorf("Expected a readable not a field element error, got: %v", err)
+	}
+}
+
 func TestGetAllocatedMemoryUnitsRangeCheck(t *testing.T) {
 	range_check := builtins.DefaultRangeCheckBuiltinRunner()
 	vm := vm.NewVirtualMachine()
@@ -149,6 +162,46 @@ func TestGetRangeCheckUsageEmptyMemory(t *testing.T) {
 	}
 }
 
+func TestGetRangeCheckUsageMinOfAllParts(t *testing.T) {
+	var builtin = builtins.DefaultRangeCheckBuiltinRunner()
+	builtin.Include(true)
+	builtin.SetBase(memory.NewRelocatable(0, 0))
+
+	var memoryManager = memory.NewMemorySegmentManager()
+	memoryManager.AddSegment()
+
+	memoryManager.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x00050006000700080009000a000b000c")))
+	memoryManager.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 0)))
+	memoryManager.Memory.Insert(memory.NewRelocatable(0, 2), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x00030004000500060007000800090020")))
+
+	var resultMin, resultMax = builtin.GetRangeCheckUsage(&memoryManager.Memory)
+
+	if resultMin == nil || *resultMin != 3 {
+		t.Errorf("rcMin should return 3, got %v", resultMin)
+	}
+
+	if resultMax == nil || *resultMax != 32 {
+		t.Errorf("rcMax should return 32, got %v", resultMax)
+	}
+}
+
+func TestGetUsedPermRangeCheckUnits(t *testing.T) {
+	var builtin = builtins.DefaultRangeCheckBuiltinRunner()
+	builtin.Include(true)
+
+	var memoryManager = memory.NewMemorySegmentManager()
+	builtin.InitializeSegments(&memoryManager)
+	memoryManager.SegmentUsedSizes[uint(builtin.Base().SegmentIndex)] = 5
+
+	units, err := builtin.GetUsedPermRangeCheckUnits(&memoryManager, 40)
+	if err != nil {
+		t.Errorf("GetUsedPermRangeCheckUnits failed with error: %s", err)
+	}
+	if units != 5*builtins.RANGE_CHECK_N_PARTS {
+		t.Errorf("Expected %d range check units, got %d", 5*builtins.RANGE_CHECK_N_PARTS, units)
+	}
+}
+
 // Range check bound is calculated via the constant RANGE_CHECK_N_PARTS.
 // If something changes and the bound is set to zero, there could be unexpected errors.
 func TestBoundIsNotZero(t *testing.T) {
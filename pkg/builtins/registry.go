@@ -0,0 +1,45 @@
+package builtins
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Constructors of the builtin runners, indexed by the builtin names used in the program json
+// Builtins without a ratio (ie: output) ignore it
+var builtinRunnerConstructors = map[string]func(ratio uint) BuiltinRunner{
+	OUTPUT_BUILTIN_NAME:      func(uint) BuiltinRunner { return NewOutputBuiltinRunner() },
+	PEDERSEN_BUILTIN_NAME:    func(ratio uint) BuiltinRunner { return NewPedersenBuiltinRunner(ratio) },
+	RANGE_CHECK_BUILTIN_NAME: func(ratio uint) BuiltinRunner { return NewRangeCheckBuiltinRunner(ratio) },
+	SIGNATURE_BUILTIN_NAME:   func(ratio uint) BuiltinRunner { return NewSignatureBuiltinRunner(ratio) },
+	BITWISE_BUILTIN_NAME:     func(ratio uint) BuiltinRunner { return NewBitwiseBuiltinRunner(ratio) },
+	EC_OP_BUILTIN_NAME:       func(ratio uint) BuiltinRunner { return NewEcOpBuiltinRunner(ratio) },
+	KECCAK_BUILTIN_NAME:      func(ratio uint) BuiltinRunner { return NewKeccakBuiltinRunner(ratio) },
+	POSEIDON_BUILTIN_NAME:    func(ratio uint) BuiltinRunner { return NewPoseidonBuiltinRunner(ratio) },
+}
+
+// Creates the builtin runner registered under the given name
+func NewBuiltinRunner(name string, ratio uint) (BuiltinRunner, error) {
+	constructor, ok := builtinRunnerConstructors[name]
+	if !ok {
+		return nil, errors.Errorf("Unknown builtin: %s", name)
+	}
+	return constructor(ratio), nil
+}
+
+// Returns the names of all the builtins that can be instantiated through NewBuiltinRunner, sorted
+func BuiltinNames() []string {
+	names := make([]string, 0, len(builtinRunnerConstructors))
+	for name := range builtinRunnerConstructors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Returns true if a builtin runner can be created for the given name through NewBuiltinRunner
+func IsRegisteredBuiltin(name string) bool {
+	_, ok := builtinRunnerConstructors[name]
+	return ok
+}
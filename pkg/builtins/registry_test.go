@@ -0,0 +1,37 @@
+package builtins_test
+
+import (
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+)
+
+func TestNewBuiltinRunnerFromRegistry(t *testing.T) {
+	for _, name := range builtins.BuiltinNames() {
+		builtin, err := builtins.NewBuiltinRunner(name, 16)
+		if err != nil {
+			t.Errorf("NewBuiltinRunner failed for %s with error: %s", name, err)
+			continue
+		}
+		if builtin.Name() != name {
+			t.Errorf("NewBuiltinRunner created the wrong builtin. Expected: %s, got: %s", name, builtin.Name())
+		}
+	}
+}
+
+func TestNewBuiltinRunnerRatio(t *testing.T) {
+	builtin, err := builtins.NewBuiltinRunner(builtins.PEDERSEN_BUILTIN_NAME, 256)
+	if err != nil {
+		t.Errorf("NewBuiltinRunner failed with error: %s", err)
+	}
+	if builtin.Ratio() != 256 {
+		t.Errorf("Wrong builtin ratio. Expected: 256, got: %d", builtin.Ratio())
+	}
+}
+
+func TestNewBuiltinRunnerUnknown(t *testing.T) {
+	_, err := builtins.NewBuiltinRunner("foo", 16)
+	if err == nil {
+		t.Errorf("NewBuiltinRunner should fail for an unknown builtin")
+	}
+}
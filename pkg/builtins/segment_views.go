@@ -0,0 +1,153 @@
+package builtins
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// View of the memory of a builtin segment as a list of instances, the first inputCells cells of each instance being
+// its inputs & the rest its outputs
+// It keeps the offset arithmetic of the instance layouts in one place
+type SegmentView struct {
+	segmentIndex     int
+	cellsPerInstance uint
+	inputCells       uint
+	mem              *memory.Memory
+}
+
+func NewSegmentView(builtin BuiltinRunner, mem *memory.Memory) SegmentView {
+	return SegmentView{
+		segmentIndex:     builtin.Base().SegmentIndex,
+		cellsPerInstance: builtin.CellsPerInstance(),
+		inputCells:       builtin.InputCellsPerInstance(),
+		mem:              mem,
+	}
+}
+
+// Returns the address of the given cell of the instance
+func (s SegmentView) CellAddr(instance uint, cell uint) memory.Relocatable {
+	return memory.NewRelocatable(s.segmentIndex, instance*s.cellsPerInstance+cell)
+}
+
+// Returns the instance the address belongs to & the position of the cell within it
+// Returns false if the address is not in the segment
+func (s SegmentView) Locate(addr memory.Relocatable) (instance uint, cell uint, ok bool) {
+	if addr.SegmentIndex != s.segmentIndex {
+		return 0, 0, false
+	}
+	return addr.Offset / s.cellsPerInstance, addr.Offset % s.cellsPerInstance, true
+}
+
+// Returns true if the address is an output cell of the segment, the only kind of cell a builtin can deduce
+func (s SegmentView) IsOutputCell(addr memory.Relocatable) bool {
+	_, cell, ok := s.Locate(addr)
+	return ok && cell >= s.inputCells
+}
+
+// Returns the input cells of the instance, false if any of them is missing or is not a felt
+func (s SegmentView) Inputs(instance uint) ([]lambdaworks.Felt, bool) {
+	inputs, err := s.mem.GetFeltRange(s.CellAddr(instance, 0), s.inputCells)
+	if err != nil {
+		return nil, false
+	}
+	return inputs, true
+}
+
+// Returns the cells of the instance, with nil for the missing ones
+func (s SegmentView) Cells(instance uint) []*memory.MaybeRelocatable {
+	cells := make([]*memory.MaybeRelocatable, s.cellsPerInstance)
+	for i := range cells {
+		if value, ok := s.mem.GetValue(s.CellAddr(instance, uint(i))); ok {
+			cells[i] = &value
+		}
+	}
+	return cells
+}
+
+// Returns the number of instances, complete or not, up to the last cell of the segment in memory
+// The memory is scanned, use the segment's used size instead when it has been computed
+func (s SegmentView) NumInstances() uint {
+	var size uint
+	for addr := range s.mem.Data {
+		if addr.SegmentIndex == s.segmentIndex && addr.Offset+1 > size {
+			size = addr.Offset + 1
+		}
+	}
+	return (size + s.cellsPerInstance - 1) / s.cellsPerInstance
+}
+
+// Writes the instances of the segment, one per line, with its inputs & outputs separated by an arrow
+// Missing cells are written as _
+func (s SegmentView) Print(dest io.Writer, hex bool) error {
+	for instance := uint(0); instance < s.NumInstances(); instance++ {
+		values := make([]string, 0, s.cellsPerInstance+1)
+		for i, cell := range s.Cells(instance) {
+			if uint(i) == s.inputCells && s.inputCells != s.cellsPerInstance {
+				values = append(values, "->")
+			}
+			values = append(values, formatCell(cell, hex))
+		}
+		if _, err := fmt.Fprintf(dest, "  %d\t%s\n", instance, strings.Join(values, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatCell(cell *memory.MaybeRelocatable, hex bool) string {
+	if cell == nil {
+		return "_"
+	}
+	if felt, ok := cell.GetFelt(); ok && hex {
+		return felt.ToHexString()
+	}
+	return cell.ToString()
+}
+
+// An instance of the pedersen builtin, Result is nil until the hash is deduced
+type PedersenInstance struct {
+	A      lambdaworks.Felt
+	B      lambdaworks.Felt
+	Result *lambdaworks.Felt
+}
+
+// View of the pedersen segment as (a, b, result) triples
+type PedersenSegment struct {
+	SegmentView
+}
+
+func NewPedersenSegment(runner *PedersenBuiltinRunner, mem *memory.Memory) PedersenSegment {
+	return PedersenSegment{NewSegmentView(runner, mem)}
+}
+
+// Returns the instance, false if its inputs are not set
+func (s PedersenSegment) Instance(index uint) (PedersenInstance, bool) {
+	inputs, ok := s.Inputs(index)
+	if !ok {
+		return PedersenInstance{}, false
+	}
+	instance := PedersenInstance{A: inputs[0], B: inputs[1]}
+	if result, err := s.mem.GetFelt(s.CellAddr(index, PEDERSEN_INPUT_CELLS_PER_INSTANCE)); err == nil {
+		instance.Result = &result
+	}
+	return instance, true
+}
+
+// View of the range check segment as a list of felts
+type RangeCheckSegment struct {
+	SegmentView
+}
+
+func NewRangeCheckSegment(runner *RangeCheckBuiltinRunner, mem *memory.Memory) RangeCheckSegment {
+	return RangeCheckSegment{NewSegmentView(runner, mem)}
+}
+
+// Returns the value of the instance, false if it is missing or is not a felt
+func (s RangeCheckSegment) Value(index uint) (lambdaworks.Felt, bool) {
+	value, err := s.mem.GetFelt(s.CellAddr(index, 0))
+	return value, err == nil
+}
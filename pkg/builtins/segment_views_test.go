@@ -0,0 +1,74 @@
+package builtins_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+func TestPedersenSegment(t *testing.T) {
+	v := vmtest.NewVMWithSegments(1)
+	pedersen := builtins.NewPedersenBuiltinRunner(8)
+	pedersen.InitializeSegments(&v.Segments)
+	vmtest.MustLoadMemory(t, v, "[ (1,0) 1, (1,1) 2, (1,2) 3, (1,3) 4, (1,5) 6 ]")
+	segment := builtins.NewPedersenSegment(pedersen, &v.Segments.Memory)
+
+	if segment.NumInstances() != 2 {
+		t.Errorf("Expected 2 instances, got %d", segment.NumInstances())
+	}
+	instance, ok := segment.Instance(0)
+	if !ok || instance.A != lambdaworks.FeltFromUint64(1) || instance.B != lambdaworks.FeltFromUint64(2) || instance.Result == nil || *instance.Result != lambdaworks.FeltFromUint64(3) {
+		t.Errorf("Wrong instance 0: %+v", instance)
+	}
+	if _, ok := segment.Instance(1); ok {
+		t.Error("Instance 1 is missing its second input")
+	}
+
+	if index, cell, ok := segment.Locate(memory.NewRelocatable(1, 5)); !ok || index != 1 || cell != 2 {
+		t.Errorf("Wrong location of (1,5): %d, %d, %t", index, cell, ok)
+	}
+	if _, _, ok := segment.Locate(memory.NewRelocatable(0, 5)); ok {
+		t.Error("(0,5) is not in the pedersen segment")
+	}
+	if !segment.IsOutputCell(memory.NewRelocatable(1, 5)) || segment.IsOutputCell(memory.NewRelocatable(1, 4)) {
+		t.Error("Only the third cell of each instance is an output")
+	}
+}
+
+func TestRangeCheckSegment(t *testing.T) {
+	v := vmtest.NewVMWithSegments(1)
+	rangeCheck := builtins.NewRangeCheckBuiltinRunner(8)
+	rangeCheck.InitializeSegments(&v.Segments)
+	vmtest.MustLoadMemory(t, v, "[ (1,0) 7, (1,1) (0,0) ]")
+	segment := builtins.NewRangeCheckSegment(rangeCheck, &v.Segments.Memory)
+
+	if value, ok := segment.Value(0); !ok || value != lambdaworks.FeltFromUint64(7) {
+		t.Errorf("Wrong value 0: %s, %t", value.ToSignedFeltString(), ok)
+	}
+	if _, ok := segment.Value(1); ok {
+		t.Error("Value 1 is not a felt")
+	}
+	if segment.IsOutputCell(memory.NewRelocatable(1, 0)) {
+		t.Error("The range check has no output cells")
+	}
+}
+
+func TestPrintSegmentView(t *testing.T) {
+	v := vmtest.NewVMWithSegments(1)
+	pedersen := builtins.NewPedersenBuiltinRunner(8)
+	pedersen.InitializeSegments(&v.Segments)
+	vmtest.MustLoadMemory(t, v, "[ (1,0) 1, (1,1) 2, (1,2) 3, (1,3) 10 ]")
+
+	var buffer bytes.Buffer
+	if err := builtins.NewSegmentView(pedersen, &v.Segments.Memory).Print(&buffer, true); err != nil {
+		t.Fatalf("Print failed with error: %s", err)
+	}
+	expected := "  0\t0x1 0x2 -> 0x3\n  1\t0xa _ -> _\n"
+	if buffer.String() != expected {
+		t.Errorf("Wrong output.\nExpected:\n%s\nGot:\n%s", expected, buffer.String())
+	}
+}
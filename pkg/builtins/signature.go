@@ -161,7 +161,7 @@ func (r *SignatureBuiltinRunner) GetUsedDilutedCheckUnits(dilutedSpacing uint, d
 	return 0
 }
 
-func (r *SignatureBuiltinRunner) GetUsedPermRangeCheckLimits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
+func (r *SignatureBuiltinRunner) GetUsedPermRangeCheckUnits(segments *memory.MemorySegmentManager, currentStep uint) (uint, error) {
 	return 0, nil
 }
 
@@ -246,3 +246,45 @@ func (b *SignatureBuiltinRunner) GetMemorySegmentAddresses() (memory.Relocatable
 	}
 	return b.base, memory.NewRelocatable(b.base.SegmentIndex, *b.StopPtr), nil
 }
+
+func (r *SignatureBuiltinRunner) ValuePolicy() ValuePolicy {
+	return FeltValuesOnly
+}
+
+func (r *SignatureBuiltinRunner) GetAdditionalData() BuiltinAdditionalData {
+	data := make(SignatureAdditionalData, len(r.signatures))
+	for addr, signature := range r.signatures {
+		data[addr] = signature
+	}
+	return data
+}
+
+// Adds the signatures, whose addresses must belong to the builtin's segment
+func (r *SignatureBuiltinRunner) SetAdditionalData(data BuiltinAdditionalData) error {
+	if data == nil {
+		return nil
+	}
+	signatures, ok := data.(SignatureAdditionalData)
+	if !ok {
+		return unexpectedAdditionalDataError(r, data)
+	}
+	for addr := range signatures {
+		if addr.SegmentIndex != r.base.SegmentIndex {
+			return InvalidAdditionalDataError(r.Name(), "address "+addr.ToString()+" is not in the builtin's segment")
+		}
+	}
+	for addr, signature := range signatures {
+		r.AddSignature(addr, signature)
+	}
+	return nil
+}
+
+func (r *SignatureBuiltinRunner) Clone() BuiltinRunner {
+	clone := *r
+	clone.signatures = make(map[memory.Relocatable]Signature, len(r.signatures))
+	for addr, signature := range r.signatures {
+		clone.signatures[addr] = signature
+	}
+	clone.StopPtr = cloneStopPtr(r.StopPtr)
+	return &clone
+}
@@ -0,0 +1,59 @@
+// Renders the errors of the vm with the messages the Rust vm (lambdaclass/cairo-vm) emits for them, so that tooling
+// that parses the error text of the Rust vm works unchanged with this one
+package compat
+
+import "errors"
+
+// Implemented by the errors that have an equivalent in the Rust vm
+type RustError interface {
+	error
+	// Message the Rust vm emits for the error
+	RustMessage() string
+}
+
+type withRustMessage struct {
+	err  error
+	rust string
+}
+
+// Returns err with the message its Rust equivalent has
+// The message of err is kept, the Rust one is only used by Message
+func WithRustMessage(err error, rustMessage string) error {
+	return &withRustMessage{err: err, rust: rustMessage}
+}
+
+func (e *withRustMessage) Error() string {
+	return e.err.Error()
+}
+
+func (e *withRustMessage) Unwrap() error {
+	return e.err
+}
+
+func (e *withRustMessage) RustMessage() string {
+	return e.rust
+}
+
+// Returns the message the Rust vm emits for err, or the message of err if it has no Rust equivalent
+// Errors without a Rust equivalent keep the message of this vm, many of them already match the Rust one
+func Message(err error) string {
+	var rustErr RustError
+	if errors.As(err, &rustErr) {
+		return rustErr.RustMessage()
+	}
+	return err.Error()
+}
+
+// Error whose message is the one the Rust vm emits for the underlying error (see Message),
+// which can still be matched through errors.Is & errors.As
+type Error struct {
+	Err error
+}
+
+func (e *Error) Error() string {
+	return Message(e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,94 @@
+package compat_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/compat"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func felt(value uint64) memory.MaybeRelocatable {
+	return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(value))
+}
+
+func relocatable(segmentIndex int, offset uint) memory.MaybeRelocatable {
+	return *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(segmentIndex, offset))
+}
+
+// Messages emitted by the Rust vm for the equivalent errors
+func TestRustMessageParity(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"DiffAssertValues", vm.DiffAssertValuesError(felt(6), felt(5)), "An ASSERT_EQ instruction failed: 5 != 6."},
+		{"DiffAssertValuesNegative", vm.DiffAssertValuesError(*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-1")), relocatable(1, 2)),
+			"An ASSERT_EQ instruction failed: 1:2 != 3618502788666131213697322783095070105623107215331596699973092056135872020480."},
+		{"CantWriteReturnPc", vm.CantWriteReturnPcError(felt(1), relocatable(0, 4)), "Call failed to write return-pc (inconsistent op0): 1 != 0:4. Did you forget to increment ap?"},
+		{"CantWriteReturnFp", vm.CantWriteReturnFpError(felt(2), relocatable(1, 3)), "Call failed to write return-fp (inconsistent dst): 2 != 1:3. Did you forget to increment ap?"},
+		{"FailedToComputeOperands", vm.FailedToComputeOperandsError("op1", memory.NewRelocatable(1, 7)), "Couldn't compute operand op1. Unknown value for memory cell 1:7"},
+		{"EndOfProgram", vm.EndOfProgramError(3), "Execution reached the end of the program. Requested remaining steps: 3."},
+		{"UnconstrainedResAssertEq", vm.ErrUnconstrainedResAssertEq, "Res.UNCONSTRAINED cannot be used with Opcode.ASSERT_EQ"},
+		{"UnconstrainedResJump", vm.ErrUnconstrainedResJump, "Res.UNCONSTRAINED cannot be used with PcUpdate.JUMP"},
+		{"UnconstrainedResJumpRel", vm.ErrUnconstrainedResJumpRel, "Res.UNCONSTRAINED cannot be used with PcUpdate.JUMP_REL"},
+		{"UnconstrainedResAdd", vm.ErrUnconstrainedResAdd, "Res.UNCONSTRAINED cannot be used with ApUpdate.ADD"},
+		{"UnknownOp0", vm.ErrUnknownOp0, "op0 must be known in double dereference"},
+		{"ImmShouldBe1", vm.ErrImmShouldBe1, "In immediate mode, off2 should be 1"},
+		{"InvalidInstructionEncoding", vm.ErrInvalidInstructionEncoding, "Instruction should be an int"},
+		{"UnallocatedSegment", memory.UnallocatedSegmentError(3, 2), "Can't insert into segment #3; memory only has 2 segment"},
+		{"RangeCheckNumOutOfBounds", builtins.OutsideBoundsError(lambdaworks.FeltFromDecString("-1")),
+			"Range-check validation failed, number 3618502788666131213697322783095070105623107215331596699973092056135872020480 is out of valid range [0, 340282366920938463463374607431768211456]"},
+		{"RangeCheckFoundNonInt", builtins.NotAFeltError(memory.NewRelocatable(2, 5), relocatable(1, 0)), "Range-check validation failed, encountered non-int value at address 2:5"},
+	}
+	for _, c := range cases {
+		if got := compat.Message(c.err); got != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestCompatErrorsRun(t *testing.T) {
+	cases := []struct {
+		name        string
+		programJson string
+		expected    string
+	}{
+		{
+			// [ap] = 5; [ap] = 6; ret
+			"DiffAssertValues",
+			`{"data": ["0x400680017fff8000", "0x5", "0x400680017fff8000", "0x6", "0x208b7fff7fff7ffe"],
+				"identifiers": {"__main__.main": {"pc": 0, "type": "function"}}, "hints": {}, "reference_manager": {"references": []}}`,
+			"Error at pc=0:2:\nAn ASSERT_EQ instruction failed: 5 != 6.\n",
+		},
+		{
+			// ret, with an unknown hint
+			"UnknownHint",
+			`{"data": ["0x208b7fff7fff7ffe"],
+				"identifiers": {"__main__.main": {"pc": 0, "type": "function"}},
+				"hints": {"0": [{"code": "print('hello')", "accessible_scopes": ["__main__", "__main__.main"], "flow_tracking_data": {"ap_tracking": {"group": 0, "offset": 0}, "reference_ids": {}}}]},
+				"reference_manager": {"references": []}}`,
+			"Error at pc=0:0:\nGot an exception while executing a hint: Unknown Hint: print('hello')\n",
+		},
+	}
+	for _, c := range cases {
+		_, err := cairo_run.CairoRunBytes([]byte(c.programJson), cairo_run.CairoRunConfig{Layout: "plain", CompatErrors: true})
+		if err == nil || err.Error() != c.expected {
+			t.Errorf("%s: expected %q, got %q", c.name, c.expected, err)
+		}
+		var stepErr *vm.StepError
+		if !errors.As(err, &stepErr) {
+			t.Errorf("%s: the compat error should still wrap the StepError", c.name)
+		}
+
+		_, err = cairo_run.CairoRunBytes([]byte(c.programJson), cairo_run.CairoRunConfig{Layout: "plain"})
+		if err == nil || err.Error() == c.expected {
+			t.Errorf("%s: errors should keep their own messages without CompatErrors, got %q", c.name, err)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Segment the runner loads the program into
+const programSegmentIndex = 0
+
+// Records the instructions of the program segment executed during a run
+// It is attached to the vm as a step observer
+type Collector struct {
+	// Number of times each pc offset was executed
+	hits map[uint]uint
+}
+
+func NewCollector() *Collector {
+	return &Collector{hits: make(map[uint]uint)}
+}
+
+func (c *Collector) BeforeStep(pc memory.Relocatable, instruction *vm.Instruction) error {
+	if pc.SegmentIndex == programSegmentIndex {
+		c.hits[pc.Offset]++
+	}
+	return nil
+}
+
+func (c *Collector) AfterStep(entry vm.TraceEntry, operands *vm.Operands) error {
+	return nil
+}
+
+// Returns the number of times the instruction at the pc offset was executed
+func (c *Collector) Hits(pc uint) uint {
+	return c.hits[pc]
+}
+
+// Number of instructions of a section of the program & how many of them were executed
+type Counts struct {
+	Instructions uint
+	Executed     uint
+}
+
+func (c *Counts) add(executed bool) {
+	c.Instructions++
+	if executed {
+		c.Executed++
+	}
+}
+
+// Percentage of the instructions that were executed, 100 if there are no instructions
+func (c Counts) Percentage() float64 {
+	if c.Instructions == 0 {
+		return 100
+	}
+	return float64(c.Executed) * 100 / float64(c.Instructions)
+}
+
+type FunctionCoverage struct {
+	Name string
+	Pc   uint
+	Counts
+}
+
+type LineCoverage struct {
+	File string
+	Line int
+	Counts
+}
+
+// Coverage of a program, by function & by source line
+type Report struct {
+	Total     Counts
+	Functions []FunctionCoverage
+	// Empty if the program has no debug info
+	Lines []LineCoverage
+	// Offsets of the instructions that were never executed
+	Missed []uint
+}
+
+// Builds the coverage report of the program from the instructions executed so far
+// Functions are delimited by the pcs of the program's function identifiers, lines are taken from its debug info
+func (c *Collector) Report(program *vm.Program) Report {
+	var report Report
+	functions := programFunctions(program)
+	lines := make(map[LineCoverage]*Counts)
+	functionIdx := -1
+	for _, pc := range instructionOffsets(program) {
+		executed := c.hits[pc] > 0
+		report.Total.add(executed)
+		if !executed {
+			report.Missed = append(report.Missed, pc)
+		}
+		for functionIdx+1 < len(functions) && functions[functionIdx+1].Pc <= pc {
+			functionIdx++
+		}
+		if functionIdx >= 0 {
+			functions[functionIdx].add(executed)
+		}
+		if location, ok := program.InstructionLocations[pc]; ok {
+			key := LineCoverage{File: location.Inst.InputFile["filename"], Line: location.Inst.StartLine}
+			if lines[key] == nil {
+				lines[key] = &Counts{}
+			}
+			lines[key].add(executed)
+		}
+	}
+
+	report.Functions = functions
+	for line, counts := range lines {
+		line.Counts = *counts
+		report.Lines = append(report.Lines, line)
+	}
+	sort.Slice(report.Lines, func(i, j int) bool {
+		if report.Lines[i].File != report.Lines[j].File {
+			return report.Lines[i].File < report.Lines[j].File
+		}
+		return report.Lines[i].Line < report.Lines[j].Line
+	})
+	return report
+}
+
+// Returns the functions of the program sorted by pc
+func programFunctions(program *vm.Program) []FunctionCoverage {
+	functions := make([]FunctionCoverage, 0)
+	for name, identifier := range program.Identifiers {
+		if identifier.Type == "function" {
+			functions = append(functions, FunctionCoverage{Name: name, Pc: uint(identifier.PC)})
+		}
+	}
+	sort.Slice(functions, func(i, j int) bool {
+		if functions[i].Pc != functions[j].Pc {
+			return functions[i].Pc < functions[j].Pc
+		}
+		return functions[i].Name < functions[j].Name
+	})
+	return functions
+}
+
+// Returns the offsets of the program's instructions, skipping their immediates
+// Words that can't be decoded as instructions are skipped
+func instructionOffsets(program *vm.Program) []uint {
+	offsets := make([]uint, 0, len(program.Data))
+	for pc := uint(0); pc < uint(len(program.Data)); {
+		size := uint(1)
+		if felt, ok := program.Data[pc].GetFelt(); ok {
+			if encoded, err := felt.ToU64(); err == nil {
+				if instruction, err := vm.DecodeInstruction(encoded); err == nil {
+					offsets = append(offsets, pc)
+					size = instruction.Size()
+				}
+			}
+		}
+		pc += size
+	}
+	return offsets
+}
+
+// Writes the report as text: the total coverage, the coverage of each function & of each source line,
+// marking the functions & lines with instructions that were never executed
+func (r *Report) Write(dest io.Writer) error {
+	if _, err := fmt.Fprintf(dest, "Coverage: %d/%d instructions (%.1f%%)\n", r.Total.Executed, r.Total.Instructions, r.Total.Percentage()); err != nil {
+		return err
+	}
+	if len(r.Functions) > 0 {
+		if _, err := fmt.Fprintln(dest, "\nFunctions:"); err != nil {
+			return err
+		}
+		for _, function := range r.Functions {
+			if _, err := fmt.Fprintf(dest, "%s %-40s pc=%-6d %d/%d (%.1f%%)\n", missedMarker(function.Counts), function.Name, function.Pc, function.Executed, function.Instructions, function.Percentage()); err != nil {
+				return err
+			}
+		}
+	}
+	if len(r.Lines) > 0 {
+		if _, err := fmt.Fprintln(dest, "\nLines:"); err != nil {
+			return err
+		}
+		for _, line := range r.Lines {
+			if _, err := fmt.Fprintf(dest, "%s %s:%d %d/%d\n", missedMarker(line.Counts), line.File, line.Line, line.Executed, line.Instructions); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func missedMarker(counts Counts) string {
+	if counts.Executed < counts.Instructions {
+		return "!"
+	}
+	return " "
+}
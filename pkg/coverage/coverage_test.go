@@ -0,0 +1,95 @@
+package coverage_test
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/coverage"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+func location(line int) parser.InstructionLocation {
+	return parser.InstructionLocation{Inst: parser.Location{InputFile: map[string]string{"filename": "main.cairo"}, StartLine: line}}
+}
+
+// main:  call foo; ret
+// foo:   [ap] = 5, ap++; ret
+// bar:   [ap] = 7, ap++; ret (never called)
+func programForCoverageTest() vm.Program {
+	program := vmtest.Program("0x1104800180018000", "0x3", "0x208b7fff7fff7ffe", "0x480680017fff8000", "0x5", "0x208b7fff7fff7ffe", "0x480680017fff8000", "0x7", "0x208b7fff7fff7ffe")
+	program.Identifiers["__main__.foo"] = vm.Identifier{PC: 3, Type: "function"}
+	program.Identifiers["__main__.bar"] = vm.Identifier{PC: 6, Type: "function"}
+	program.Identifiers["__main__.X"] = vm.Identifier{Type: "const"}
+	program.InstructionLocations = map[uint]parser.InstructionLocation{
+		0: location(2), 2: location(3), 3: location(6), 5: location(7), 6: location(10), 8: location(10),
+	}
+	return program
+}
+
+func runWithCoverage(t *testing.T) (*coverage.Collector, vm.Program) {
+	program := programForCoverageTest()
+	collector := coverage.NewCollector()
+	config := cairo_run.CairoRunConfig{Layout: "plain", StepObservers: []vm.StepObserver{collector}}
+	if _, err := cairo_run.CairoRunProgram(program, config); err != nil {
+		t.Fatalf("CairoRunProgram failed with error: %s", err)
+	}
+	return collector, program
+}
+
+func TestCoverageReport(t *testing.T) {
+	collector, program := runWithCoverage(t)
+	report := collector.Report(&program)
+
+	if report.Total != (coverage.Counts{Instructions: 6, Executed: 4}) {
+		t.Errorf("Wrong total coverage: %+v", report.Total)
+	}
+	expectedFunctions := []coverage.FunctionCoverage{
+		{Name: "__main__.main", Pc: 0, Counts: coverage.Counts{Instructions: 2, Executed: 2}},
+		{Name: "__main__.foo", Pc: 3, Counts: coverage.Counts{Instructions: 2, Executed: 2}},
+		{Name: "__main__.bar", Pc: 6, Counts: coverage.Counts{Instructions: 2, Executed: 0}},
+	}
+	if !reflect.DeepEqual(report.Functions, expectedFunctions) {
+		t.Errorf("Wrong function coverage: %+v", report.Functions)
+	}
+	expectedLines := []coverage.LineCoverage{
+		{File: "main.cairo", Line: 2, Counts: coverage.Counts{Instructions: 1, Executed: 1}},
+		{File: "main.cairo", Line: 3, Counts: coverage.Counts{Instructions: 1, Executed: 1}},
+		{File: "main.cairo", Line: 6, Counts: coverage.Counts{Instructions: 1, Executed: 1}},
+		{File: "main.cairo", Line: 7, Counts: coverage.Counts{Instructions: 1, Executed: 1}},
+		{File: "main.cairo", Line: 10, Counts: coverage.Counts{Instructions: 2, Executed: 0}},
+	}
+	if !reflect.DeepEqual(report.Lines, expectedLines) {
+		t.Errorf("Wrong line coverage: %+v", report.Lines)
+	}
+	if !reflect.DeepEqual(report.Missed, []uint{6, 8}) {
+		t.Errorf("Wrong missed instructions: %v", report.Missed)
+	}
+	if collector.Hits(3) != 1 || collector.Hits(4) != 0 {
+		t.Errorf("Wrong hits: %d, %d", collector.Hits(3), collector.Hits(4))
+	}
+}
+
+func TestCoverageReportWrite(t *testing.T) {
+	collector, program := runWithCoverage(t)
+	report := collector.Report(&program)
+	var output bytes.Buffer
+	if err := report.Write(&output); err != nil {
+		t.Fatalf("Write failed with error: %s", err)
+	}
+
+	text := output.String()
+	if !strings.HasPrefix(text, "Coverage: 4/6 instructions (66.7%)\n") {
+		t.Errorf("Wrong summary:\n%s", text)
+	}
+	if !strings.Contains(text, "! __main__.bar") || !strings.Contains(text, "  __main__.foo") {
+		t.Errorf("Functions with missed instructions should be marked:\n%s", text)
+	}
+	if !strings.Contains(text, "! main.cairo:10 0/2\n") || !strings.Contains(text, "  main.cairo:2 1/1\n") {
+		t.Errorf("Lines with missed instructions should be marked:\n%s", text)
+	}
+}
@@ -0,0 +1,476 @@
+package debugger
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints/dict_manager"
+	"github.com/lambdaclass/cairo-vm.go/pkg/types"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+var ErrDebuggerQuit = errors.New("Execution aborted by the debugger")
+
+// Number of execution events kept by the debugger's tracer
+const eventHistorySize = 1024
+
+const helpText = `Commands:
+  step (s) [n]              execute n steps (default 1)
+  next (n)                  execute one step, stepping over function calls
+  continue (c)              run until a breakpoint, a watchpoint or the end of the program
+  break (b) <pc>            set a breakpoint at a pc offset of the program segment
+  break (b) [file:]<line>   set a breakpoint at the instructions of a source line
+  delete (d) <pc>           remove the breakpoint at a pc offset
+  watch (w) <seg>:<offset>  pause when the value at the address changes
+  unwatch <seg>:<offset>    remove a watchpoint
+  breakpoints               list breakpoints & watchpoints
+  regs (r)                  print the registers
+  mem (m) <seg>:<offset> [n]  print n memory cells starting at the address (default 1)
+  hints                     list the hints at the current pc
+  scopes                    list the variables of the execution scopes
+  dicts                     print the pointer & contents of the dicts of the current scope
+  events [n]                print the last n events of the execution (default 20)
+  help (h)                  print this message
+  quit (q)                  abort the execution`
+
+// Interactive debugger that pauses the vm between steps & reads commands from an input
+// It is attached to a run through the step hooks returned by Hooks
+type Debugger struct {
+	program *vm.Program
+	input   *bufio.Scanner
+	output  io.Writer
+
+	// Returns the variables of the execution scopes, nil if they are not available
+	scopes func() [][]types.ScopeVariable
+	// Returns the state of the dicts, nil if they are not available
+	dicts func() []dict_manager.DictTrackerDump
+	// Last events of the execution, listed by the events command
+	events *vm.RingBufferTracer
+
+	breakpoints map[uint]bool
+	watchpoints map[memory.Relocatable]*memory.MaybeRelocatable
+
+	// Steps left before pausing when stepping, 0 means the debugger isn't stepping
+	stepsLeft uint
+	// Frame the execution returns to when stepping over a call
+	nextTarget *vm.RunContext
+	// Set when a watchpoint changed during the last step
+	watchpointHit bool
+}
+
+func NewDebugger(program *vm.Program, input io.Reader, output io.Writer) *Debugger {
+	return &Debugger{
+		program:     program,
+		input:       bufio.NewScanner(input),
+		output:      output,
+		breakpoints: make(map[uint]bool),
+		watchpoints: make(map[memory.Relocatable]*memory.MaybeRelocatable),
+		events:      vm.NewRingBufferTracer(eventHistorySize),
+		// Pause before the first step
+		stepsLeft: 1,
+	}
+}
+
+// Gives the debugger access to the execution scopes of the run, listed by the scopes command
+// ie: debugger.SetExecutionScopes(runner.InspectExecutionScopes)
+func (d *Debugger) SetExecutionScopes(scopes func() [][]types.ScopeVariable) {
+	d.scopes = scopes
+}
+
+// Gives the debugger access to the dicts of the run, printed by the dicts command
+// ie: debugger.SetDicts(runner.DumpDicts)
+func (d *Debugger) SetDicts(dicts func() []dict_manager.DictTrackerDump) {
+	d.dicts = dicts
+}
+
+// Returns the hooks that have to be installed in the vm to debug its execution
+func (d *Debugger) Hooks() vm.StepHooks {
+	return vm.StepHooks{PreStep: d.preStep, PostStep: d.postStep}
+}
+
+// Returns the tracer that has to be installed in the vm for the events command to list the execution's events
+func (d *Debugger) Tracer() vm.Tracer {
+	return d.events
+}
+
+func (d *Debugger) preStep(v *vm.VirtualMachine) error {
+	if !d.shouldPause(v) {
+		return nil
+	}
+	d.stepsLeft = 0
+	d.nextTarget = nil
+	d.watchpointHit = false
+	d.printLocation(v)
+	return d.repl(v)
+}
+
+func (d *Debugger) postStep(v *vm.VirtualMachine) error {
+	for addr, previous := range d.watchpoints {
+		current, _ := v.Segments.Memory.Get(addr)
+		if !maybeRelocatablesEqual(previous, current) {
+			fmt.Fprintf(d.output, "Watchpoint %s changed: %s -> %s\n", addr.ToString(), formatCell(previous), formatCell(current))
+			d.watchpoints[addr] = current
+			d.watchpointHit = true
+		}
+	}
+	return nil
+}
+
+func (d *Debugger) shouldPause(v *vm.VirtualMachine) bool {
+	if d.watchpointHit {
+		return true
+	}
+	if d.stepsLeft > 0 {
+		d.stepsLeft--
+		if d.stepsLeft == 0 {
+			return true
+		}
+	}
+	if d.nextTarget != nil && v.RunContext.Pc == d.nextTarget.Pc && v.RunContext.Fp == d.nextTarget.Fp {
+		return true
+	}
+	if d.breakpoints[v.RunContext.Pc.Offset] {
+		fmt.Fprintf(d.output, "Breakpoint at pc %d\n", v.RunContext.Pc.Offset)
+		return true
+	}
+	return false
+}
+
+// Reads & executes commands until one of them resumes the execution
+func (d *Debugger) repl(v *vm.VirtualMachine) error {
+	for {
+		fmt.Fprint(d.output, "(cairo-debug) ")
+		if !d.input.Scan() {
+			fmt.Fprintln(d.output)
+			return ErrDebuggerQuit
+		}
+		fields := strings.Fields(d.input.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		resume, err := d.execute(v, fields[0], fields[1:])
+		if err == ErrDebuggerQuit {
+			return err
+		}
+		if err != nil {
+			fmt.Fprintf(d.output, "Error: %s\n", err)
+			continue
+		}
+		if resume {
+			return nil
+		}
+	}
+}
+
+// Executes a single command, returns true if the execution should be resumed
+func (d *Debugger) execute(v *vm.VirtualMachine, command string, args []string) (bool, error) {
+	switch command {
+	case "step", "s":
+		steps := uint(1)
+		if len(args) > 0 {
+			n, err := strconv.ParseUint(args[0], 10, 0)
+			if err != nil || n == 0 {
+				return false, errors.Errorf("Invalid number of steps %s", args[0])
+			}
+			steps = uint(n)
+		}
+		d.stepsLeft = steps
+		return true, nil
+	case "next", "n":
+		return true, d.setNextTarget(v)
+	case "continue", "c":
+		return true, nil
+	case "break", "b":
+		return false, d.addBreakpoint(args)
+	case "delete", "d":
+		pc, err := parseSingleUint(args)
+		if err != nil {
+			return false, err
+		}
+		if !d.breakpoints[pc] {
+			return false, errors.Errorf("No breakpoint at pc %d", pc)
+		}
+		delete(d.breakpoints, pc)
+		return false, nil
+	case "watch", "w":
+		addr, err := parseAddress(args)
+		if err != nil {
+			return false, err
+		}
+		d.watchpoints[addr], _ = v.Segments.Memory.Get(addr)
+		fmt.Fprintf(d.output, "Watching %s\n", addr.ToString())
+		return false, nil
+	case "unwatch":
+		addr, err := parseAddress(args)
+		if err != nil {
+			return false, err
+		}
+		if _, ok := d.watchpoints[addr]; !ok {
+			return false, errors.Errorf("No watchpoint at %s", addr.ToString())
+		}
+		delete(d.watchpoints, addr)
+		return false, nil
+	case "breakpoints":
+		d.printBreakpoints()
+		return false, nil
+	case "regs", "r":
+		fmt.Fprintf(d.output, "pc=%s ap=%s fp=%s step=%d\n", v.RunContext.Pc.ToString(), v.RunContext.Ap.ToString(), v.RunContext.Fp.ToString(), v.CurrentStep)
+		return false, nil
+	case "mem", "m":
+		return false, d.printMemory(v, args)
+	case "hints":
+		d.printHints(v)
+		return false, nil
+	case "scopes":
+		if d.scopes == nil {
+			return false, errors.New("The execution scopes are not available")
+		}
+		fmt.Fprintln(d.output, types.FormatScopes(d.scopes()))
+		return false, nil
+	case "dicts":
+		return false, d.printDicts()
+	case "events":
+		return false, d.printEvents(args)
+	case "help", "h":
+		fmt.Fprintln(d.output, helpText)
+		return false, nil
+	case "quit", "q":
+		return false, ErrDebuggerQuit
+	default:
+		return false, errors.Errorf("Unknown command %s, type help for a list of commands", command)
+	}
+}
+
+// Steps over calls by pausing once the execution reaches the next instruction within the current frame
+func (d *Debugger) setNextTarget(v *vm.VirtualMachine) error {
+	encodedInstruction, err := v.Segments.Memory.GetFelt(v.RunContext.Pc)
+	if err != nil {
+		return err
+	}
+	encodedInstructionUint, err := encodedInstruction.ToU64()
+	if err != nil {
+		return err
+	}
+	instruction, err := vm.DecodeInstruction(encodedInstructionUint)
+	if err != nil {
+		return err
+	}
+	if instruction.Opcode != vm.Call {
+		d.stepsLeft = 1
+		return nil
+	}
+	d.nextTarget = &vm.RunContext{Pc: v.RunContext.Pc.AddUint(instruction.Size()), Fp: v.RunContext.Fp}
+	return nil
+}
+
+func (d *Debugger) addBreakpoint(args []string) error {
+	if len(args) != 1 {
+		return errors.New("Expected a pc or a source line")
+	}
+	if file, lineStr, found := strings.Cut(args[0], ":"); found {
+		return d.addLineBreakpoint(file, lineStr)
+	}
+	pc, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return errors.Errorf("Invalid pc %s", args[0])
+	}
+	d.breakpoints[uint(pc)] = true
+	fmt.Fprintf(d.output, "Breakpoint set at pc %d\n", pc)
+	return nil
+}
+
+// Sets a breakpoint at the first instruction of the given line
+// An empty file matches every input file
+func (d *Debugger) addLineBreakpoint(file string, lineStr string) error {
+	line, err := strconv.Atoi(lineStr)
+	if err != nil {
+		return errors.Errorf("Invalid line %s", lineStr)
+	}
+	if len(d.program.InstructionLocations) == 0 {
+		return errors.New("The program has no debug info, line breakpoints are not available")
+	}
+	pcs := make([]uint, 0)
+	for pc, location := range d.program.InstructionLocations {
+		if location.Inst.StartLine == line && strings.HasSuffix(location.Inst.InputFile["filename"], file) {
+			pcs = append(pcs, pc)
+		}
+	}
+	if len(pcs) == 0 {
+		return errors.Errorf("No instructions found at line %s", lineStr)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	d.breakpoints[pcs[0]] = true
+	fmt.Fprintf(d.output, "Breakpoint set at pc %d\n", pcs[0])
+	return nil
+}
+
+func (d *Debugger) printBreakpoints() {
+	pcs := make([]uint, 0, len(d.breakpoints))
+	for pc := range d.breakpoints {
+		pcs = append(pcs, pc)
+	}
+	sort.Slice(pcs, func(i, j int) bool { return pcs[i] < pcs[j] })
+	for _, pc := range pcs {
+		fmt.Fprintf(d.output, "breakpoint pc %d\n", pc)
+	}
+	addrs := make([]memory.Relocatable, 0, len(d.watchpoints))
+	for addr := range d.watchpoints {
+		addrs = append(addrs, addr)
+	}
+	sort.Slice(addrs, func(i, j int) bool {
+		if addrs[i].SegmentIndex != addrs[j].SegmentIndex {
+			return addrs[i].SegmentIndex < addrs[j].SegmentIndex
+		}
+		return addrs[i].Offset < addrs[j].Offset
+	})
+	for _, addr := range addrs {
+		fmt.Fprintf(d.output, "watchpoint %s\n", addr.ToString())
+	}
+}
+
+func (d *Debugger) printMemory(v *vm.VirtualMachine, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("Expected an address and an optional number of cells")
+	}
+	addr, err := parseAddress(args[:1])
+	if err != nil {
+		return err
+	}
+	count := uint64(1)
+	if len(args) == 2 {
+		count, err = strconv.ParseUint(args[1], 10, 0)
+		if err != nil {
+			return errors.Errorf("Invalid number of cells %s", args[1])
+		}
+	}
+	for i := uint(0); i < uint(count); i++ {
+		cellAddr := addr.AddUint(i)
+		value, _ := v.Segments.Memory.Get(cellAddr)
+		fmt.Fprintf(d.output, "%s\t%s\n", cellAddr.ToString(), formatCell(value))
+	}
+	return nil
+}
+
+func (d *Debugger) printHints(v *vm.VirtualMachine) {
+	hints := d.program.Hints[v.RunContext.Pc.Offset]
+	if len(hints) == 0 {
+		fmt.Fprintln(d.output, "No hints at the current pc")
+		return
+	}
+	for i, hint := range hints {
+		fmt.Fprintf(d.output, "hint %d:\n%s\n", i, hint.Code)
+	}
+}
+
+func (d *Debugger) printDicts() error {
+	if d.dicts == nil {
+		return errors.New("The dicts are not available")
+	}
+	dumps := d.dicts()
+	if len(dumps) == 0 {
+		fmt.Fprintln(d.output, "No dicts in the current scope")
+		return nil
+	}
+	for i := range dumps {
+		fmt.Fprintln(d.output, dumps[i].ToString())
+	}
+	return nil
+}
+
+func (d *Debugger) printEvents(args []string) error {
+	n := uint(20)
+	if len(args) > 0 {
+		var err error
+		if n, err = parseSingleUint(args); err != nil {
+			return err
+		}
+	}
+	events := d.events.Events()
+	if uint(len(events)) > n {
+		events = events[uint(len(events))-n:]
+	}
+	if len(events) == 0 {
+		fmt.Fprintln(d.output, "No events recorded")
+		return nil
+	}
+	for _, event := range events {
+		fmt.Fprintln(d.output, formatEvent(event))
+	}
+	return nil
+}
+
+func (d *Debugger) printLocation(v *vm.VirtualMachine) {
+	fmt.Fprintf(d.output, "Paused at step %d, pc=%s", v.CurrentStep, v.RunContext.Pc.ToString())
+	if location, ok := d.program.InstructionLocations[v.RunContext.Pc.Offset]; ok {
+		fmt.Fprintf(d.output, " (%s:%d)", location.Inst.InputFile["filename"], location.Inst.StartLine)
+	}
+	fmt.Fprintln(d.output)
+}
+
+func parseSingleUint(args []string) (uint, error) {
+	if len(args) != 1 {
+		return 0, errors.New("Expected a single argument")
+	}
+	value, err := strconv.ParseUint(args[0], 10, 0)
+	if err != nil {
+		return 0, errors.Errorf("Invalid value %s", args[0])
+	}
+	return uint(value), nil
+}
+
+// Parses an address of the form <segment>:<offset>
+func parseAddress(args []string) (memory.Relocatable, error) {
+	if len(args) != 1 {
+		return memory.Relocatable{}, errors.New("Expected an address of the form <segment>:<offset>")
+	}
+	segmentStr, offsetStr, found := strings.Cut(args[0], ":")
+	if !found {
+		return memory.Relocatable{}, errors.Errorf("Invalid address %s, expected format: <segment>:<offset>", args[0])
+	}
+	segment, err := strconv.Atoi(segmentStr)
+	if err != nil {
+		return memory.Relocatable{}, errors.Errorf("Invalid segment %s", segmentStr)
+	}
+	offset, err := strconv.ParseUint(offsetStr, 10, 0)
+	if err != nil {
+		return memory.Relocatable{}, errors.Errorf("Invalid offset %s", offsetStr)
+	}
+	return memory.NewRelocatable(segment, uint(offset)), nil
+}
+
+func maybeRelocatablesEqual(a *memory.MaybeRelocatable, b *memory.MaybeRelocatable) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.IsEqual(b)
+}
+
+func formatEvent(event vm.TraceEvent) string {
+	prefix := fmt.Sprintf("step %d: %s", event.Step, event.Kind)
+	switch event.Kind {
+	case vm.StepEvent:
+		return fmt.Sprintf("%s pc=%s ap=%s fp=%s", prefix, event.Registers.Pc.ToString(), event.Registers.Ap.ToString(), event.Registers.Fp.ToString())
+	case vm.HintExecutedEvent:
+		return fmt.Sprintf("%s pc=%s hint %d", prefix, event.Registers.Pc.ToString(), event.HintIndex)
+	case vm.MemoryWriteEvent:
+		return fmt.Sprintf("%s %s = %s", prefix, event.Address.ToString(), event.Value.ToString())
+	case vm.BuiltinDeductionEvent:
+		return fmt.Sprintf("%s %s %s = %s", prefix, event.Builtin, event.Address.ToString(), event.Value.ToString())
+	case vm.SegmentAddEvent:
+		return fmt.Sprintf("%s %s", prefix, event.Address.ToString())
+	}
+	return prefix
+}
+
+func formatCell(value *memory.MaybeRelocatable) string {
+	if value == nil {
+		return "<empty>"
+	}
+	return value.ToString()
+}
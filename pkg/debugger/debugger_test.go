@@ -0,0 +1,147 @@
+package debugger_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/debugger"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+// main:  call foo; ret
+// foo:   [ap] = 5, ap++; ret
+func programForDebuggerTest() vm.Program {
+	program := vmtest.Program("0x1104800180018000", "0x3", "0x208b7fff7fff7ffe", "0x480680017fff8000", "0x5", "0x208b7fff7fff7ffe")
+	program.Hints = map[uint][]parser.HintParams{}
+	program.InstructionLocations = map[uint]parser.InstructionLocation{}
+	return program
+}
+
+func runWithDebugger(program vm.Program, commands ...string) (string, error) {
+	var output bytes.Buffer
+	input := strings.NewReader(strings.Join(commands, "\n") + "\n")
+	programDebugger := debugger.NewDebugger(&program, input, &output)
+	config := cairo_run.CairoRunConfig{Layout: "plain", Hooks: programDebugger.Hooks()}
+	_, err := cairo_run.CairoRunProgram(program, config)
+	return output.String(), err
+}
+
+func TestDebuggerStepIntoCall(t *testing.T) {
+	output, err := runWithDebugger(programForDebuggerTest(), "step", "regs", "quit")
+	if err != debugger.ErrDebuggerQuit {
+		t.Errorf("Expected the run to be aborted, got: %v", err)
+	}
+	if !strings.Contains(output, "Paused at step 1, pc={0:3}") {
+		t.Errorf("Step should enter the called function, got:\n%s", output)
+	}
+}
+
+func TestDebuggerNextStepsOverCall(t *testing.T) {
+	output, err := runWithDebugger(programForDebuggerTest(), "next", "quit")
+	if err != debugger.ErrDebuggerQuit {
+		t.Errorf("Expected the run to be aborted, got: %v", err)
+	}
+	if !strings.Contains(output, "Paused at step 3, pc={0:2}") {
+		t.Errorf("Next should step over the call, got:\n%s", output)
+	}
+}
+
+func TestDebuggerBreakpointAndContinue(t *testing.T) {
+	output, err := runWithDebugger(programForDebuggerTest(), "break 5", "continue", "continue")
+	if err != nil {
+		t.Errorf("Run failed with error: %s", err)
+	}
+	if !strings.Contains(output, "Breakpoint at pc 5") || !strings.Contains(output, "Paused at step 2, pc={0:5}") {
+		t.Errorf("Execution should pause at the breakpoint, got:\n%s", output)
+	}
+}
+
+func TestDebuggerLineBreakpoint(t *testing.T) {
+	program := programForDebuggerTest()
+	program.InstructionLocations[3] = parser.InstructionLocation{Inst: parser.Location{StartLine: 7, InputFile: map[string]string{"filename": "src/foo.cairo"}}}
+	output, err := runWithDebugger(program, "break foo.cairo:7", "continue", "continue")
+	if err != nil {
+		t.Errorf("Run failed with error: %s", err)
+	}
+	if !strings.Contains(output, "Paused at step 1, pc={0:3} (src/foo.cairo:7)") {
+		t.Errorf("Execution should pause at the source line, got:\n%s", output)
+	}
+}
+
+func TestDebuggerWatchpoint(t *testing.T) {
+	output, err := runWithDebugger(programForDebuggerTest(), "watch 1:4", "continue", "mem 1:4", "continue")
+	if err != nil {
+		t.Errorf("Run failed with error: %s", err)
+	}
+	if !strings.Contains(output, "Watchpoint {1:4} changed: <empty> -> 5") || !strings.Contains(output, "{1:4}\t5") {
+		t.Errorf("Execution should pause when the watched cell changes, got:\n%s", output)
+	}
+}
+
+func TestDebuggerEndOfInputQuits(t *testing.T) {
+	_, err := runWithDebugger(programForDebuggerTest())
+	if err != debugger.ErrDebuggerQuit {
+		t.Errorf("Expected the run to be aborted, got: %v", err)
+	}
+}
+
+func TestDebuggerScopes(t *testing.T) {
+	var output bytes.Buffer
+	program := programForDebuggerTest()
+	programDebugger := debugger.NewDebugger(&program, strings.NewReader("scopes\nquit\n"), &output)
+	runner, err := cairo_run.NewRunner(program, cairo_run.WithVmOptions(vm.WithHooks(programDebugger.Hooks())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	programDebugger.SetExecutionScopes(runner.InspectExecutionScopes)
+	if err := runner.Run(); err != debugger.ErrDebuggerQuit {
+		t.Errorf("Expected the run to be aborted, got: %v", err)
+	}
+	if !strings.Contains(output.String(), "scope 0: <empty>") {
+		t.Errorf("Expected the main scope to be listed, got:\n%s", output.String())
+	}
+}
+
+func TestDebuggerScopesNotAvailable(t *testing.T) {
+	output, _ := runWithDebugger(programForDebuggerTest(), "scopes", "quit")
+	if !strings.Contains(output, "Error: The execution scopes are not available") {
+		t.Errorf("Expected an error, got:\n%s", output)
+	}
+}
+
+func TestDebuggerDicts(t *testing.T) {
+	var output bytes.Buffer
+	program := programForDebuggerTest()
+	programDebugger := debugger.NewDebugger(&program, strings.NewReader("dicts\nquit\n"), &output)
+	runner, err := cairo_run.NewRunner(program, cairo_run.WithVmOptions(vm.WithHooks(programDebugger.Hooks())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	programDebugger.SetDicts(runner.DumpDicts)
+	if err := runner.Run(); err != debugger.ErrDebuggerQuit {
+		t.Errorf("Expected the run to be aborted, got: %v", err)
+	}
+	if !strings.Contains(output.String(), "No dicts in the current scope") {
+		t.Errorf("Expected no dicts to be listed, got:\n%s", output.String())
+	}
+}
+
+func TestDebuggerEvents(t *testing.T) {
+	program := programForDebuggerTest()
+	var output bytes.Buffer
+	input := strings.NewReader("step 2\nevents 2\nquit\n")
+	programDebugger := debugger.NewDebugger(&program, input, &output)
+	config := cairo_run.CairoRunConfig{Layout: "plain", Hooks: programDebugger.Hooks(), Tracer: programDebugger.Tracer()}
+	_, err := cairo_run.CairoRunProgram(program, config)
+	if err != debugger.ErrDebuggerQuit {
+		t.Errorf("Expected the run to be aborted, got: %v", err)
+	}
+	expected := "step 1: memory_write {1:4} = 5\nstep 1: step pc={0:3} ap={1:4} fp={1:4}\n"
+	if !strings.Contains(output.String(), expected) {
+		t.Errorf("Wrong events, got:\n%s", output.String())
+	}
+}
@@ -81,7 +81,7 @@ func blake2sCompute(ids IdsManager, vm *VirtualMachine) error {
 	newState := Blake2sCompress([8]uint32(h), [16]uint32(message), t, 0, f, 0)
 	data := Uint32SliceToMRSlice(newState)
 
-	_, err = vm.Segments.LoadData(output, &data)
+	_, err = vm.Segments.WriteArg(output, data)
 	return err
 }
 
@@ -108,7 +108,7 @@ func blake2sAddUint256(ids IdsManager, vm *VirtualMachine) error {
 	for i := uint(0); i < 4; i++ {
 		data = append(data, *NewMaybeRelocatableFelt(low.Shr(B * i).And(mask)))
 	}
-	dataPtr, err = vm.Segments.LoadData(dataPtr, &data)
+	dataPtr, err = vm.Segments.WriteArg(dataPtr, data)
 	if err != nil {
 		return err
 	}
@@ -117,7 +117,7 @@ func blake2sAddUint256(ids IdsManager, vm *VirtualMachine) error {
 	for i := uint(0); i < 4; i++ {
 		data = append(data, *NewMaybeRelocatableFelt(high.Shr(B * i).And(mask)))
 	}
-	_, err = vm.Segments.LoadData(dataPtr, &data)
+	_, err = vm.Segments.WriteArg(dataPtr, data)
 	return err
 }
 
@@ -144,7 +144,7 @@ func blake2sAddUint256Bigend(ids IdsManager, vm *VirtualMachine) error {
 	for i := uint(0); i < 4; i++ {
 		data = append(data, *NewMaybeRelocatableFelt(high.Shr(B * (3 - i)).And(mask)))
 	}
-	dataPtr, err = vm.Segments.LoadData(dataPtr, &data)
+	dataPtr, err = vm.Segments.WriteArg(dataPtr, data)
 	if err != nil {
 		return err
 	}
@@ -153,7 +153,7 @@ func blake2sAddUint256Bigend(ids IdsManager, vm *VirtualMachine) error {
 	for i := uint(0); i < 4; i++ {
 		data = append(data, *NewMaybeRelocatableFelt(low.Shr(B * (3 - i)).And(mask)))
 	}
-	_, err = vm.Segments.LoadData(dataPtr, &data)
+	_, err = vm.Segments.WriteArg(dataPtr, data)
 	return err
 }
 
@@ -176,7 +176,7 @@ func blake2sFinalize(ids IdsManager, vm *VirtualMachine) error {
 		fullPadding = append(fullPadding, padding...)
 	}
 	data := Uint32SliceToMRSlice(fullPadding)
-	_, err = vm.Segments.LoadData(blake2sPtrEnd, &data)
+	_, err = vm.Segments.WriteArg(blake2sPtrEnd, data)
 	return err
 }
 
@@ -199,7 +199,7 @@ func blake2sFinalizeV3(ids IdsManager, vm *VirtualMachine) error {
 		fullPadding = append(fullPadding, padding...)
 	}
 	data := Uint32SliceToMRSlice(fullPadding)
-	_, err = vm.Segments.LoadData(blake2sPtrEnd, &data)
+	_, err = vm.Segments.WriteArg(blake2sPtrEnd, data)
 	return err
 }
 
@@ -230,6 +230,6 @@ func exampleBlake2sCompress(ids IdsManager, vm *VirtualMachine) error {
 	modifiedIv[0] = modifiedIv[0] ^ 0x01010020
 	outputState := Blake2sCompress(modifiedIv, [16]uint32(message), nBytes, 0, 0xffffffff, 0)
 	outputData := Uint32SliceToMRSlice(outputState)
-	_, err = vm.Segments.LoadData(output, &outputData)
+	_, err = vm.Segments.WriteArg(output, outputData)
 	return err
 }
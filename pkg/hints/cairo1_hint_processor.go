@@ -0,0 +1,362 @@
+package hints
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/types"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Hint processor for Cairo 1 programs, whose hints are the json hints of a CasmContractClass (see cairo_run.Cairo1Program)
+// Only the core hints needed by the gas checks, allocations & integer arithmetic of contracts are supported,
+// other hints fail with ErrUnknownHint when executed
+type Cairo1HintProcessor struct {
+	// Executes the syscalls requested through the SystemCall hint, which fail with ErrNoSyscallHandler if it's nil
+	SyscallHandler SyscallHandler
+}
+
+var ErrNoSyscallHandler = errors.New("No syscall handler")
+
+// Operand of a Cairo 1 hint, only one of its fields is set
+type ResOperand struct {
+	Deref       *CellRef
+	DoubleDeref *DoubleDerefOperand
+	Immediate   *Felt
+	BinOp       *BinOpOperand
+}
+
+// Memory cell at the given offset from ap or fp
+type CellRef struct {
+	Register string `json:"register"`
+	Offset   int    `json:"offset"`
+}
+
+// [[cell] + offset]
+type DoubleDerefOperand struct {
+	Cell   CellRef
+	Offset int
+}
+
+// [a] op b, where b is either a memory cell or an immediate
+type BinOpOperand struct {
+	Op string     `json:"op"`
+	A  CellRef    `json:"a"`
+	B  ResOperand `json:"b"`
+}
+
+func (o *ResOperand) UnmarshalJSON(data []byte) error {
+	var operand map[string]json.RawMessage
+	if err := json.Unmarshal(data, &operand); err != nil {
+		return err
+	}
+	for kind, value := range operand {
+		switch kind {
+		case "Deref":
+			o.Deref = &CellRef{}
+			return json.Unmarshal(value, o.Deref)
+		case "DoubleDeref":
+			var pair []json.RawMessage
+			if err := json.Unmarshal(value, &pair); err != nil {
+				return err
+			}
+			if len(pair) != 2 {
+				return errors.Errorf("Expected a [cell, offset] pair, got %d elements", len(pair))
+			}
+			o.DoubleDeref = &DoubleDerefOperand{}
+			if err := json.Unmarshal(pair[0], &o.DoubleDeref.Cell); err != nil {
+				return err
+			}
+			return json.Unmarshal(pair[1], &o.DoubleDeref.Offset)
+		case "Immediate":
+			immediate, err := parseImmediate(value)
+			if err != nil {
+				return err
+			}
+			o.Immediate = &immediate
+			return nil
+		case "BinOp":
+			o.BinOp = &BinOpOperand{}
+			return json.Unmarshal(value, o.BinOp)
+		default:
+			return errors.Errorf("Unknown operand: %s", kind)
+		}
+	}
+	return errors.New("Empty operand")
+}
+
+// Immediates are written either as (hex or decimal) strings or as numbers
+func parseImmediate(data []byte) (Felt, error) {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		var number json.Number
+		if err := json.Unmarshal(data, &number); err != nil {
+			return Felt{}, err
+		}
+		value = number.String()
+	}
+	n, ok := new(big.Int).SetString(value, 0)
+	if !ok {
+		return Felt{}, errors.Errorf("Invalid immediate: %s", value)
+	}
+	return FeltFromBigInt(n.Mod(n, Prime())), nil
+}
+
+func (c CellRef) address(vm *vm.VirtualMachine) (memory.Relocatable, error) {
+	var base memory.Relocatable
+	switch c.Register {
+	case "AP":
+		base = vm.RunContext.Ap
+	case "FP":
+		base = vm.RunContext.Fp
+	default:
+		return memory.Relocatable{}, errors.Errorf("Unknown register: %s", c.Register)
+	}
+	return base.AddFelt(feltFromInt(c.Offset))
+}
+
+func feltFromInt(n int) Felt {
+	if n < 0 {
+		return FeltZero().Sub(FeltFromUint64(uint64(-n)))
+	}
+	return FeltFromUint64(uint64(n))
+}
+
+func (c CellRef) getFelt(vm *vm.VirtualMachine) (Felt, error) {
+	addr, err := c.address(vm)
+	if err != nil {
+		return Felt{}, err
+	}
+	return vm.Segments.Memory.GetFelt(addr)
+}
+
+func (c CellRef) insert(vm *vm.VirtualMachine, value *memory.MaybeRelocatable) error {
+	addr, err := c.address(vm)
+	if err != nil {
+		return err
+	}
+	return vm.Segments.Memory.Insert(addr, value)
+}
+
+// Returns the value of an operand that evaluates to a felt
+func (o ResOperand) getFelt(vm *vm.VirtualMachine) (Felt, error) {
+	switch {
+	case o.Deref != nil:
+		return o.Deref.getFelt(vm)
+	case o.DoubleDeref != nil:
+		ptr, err := o.DoubleDeref.pointer(vm)
+		if err != nil {
+			return Felt{}, err
+		}
+		return vm.Segments.Memory.GetFelt(ptr)
+	case o.Immediate != nil:
+		return *o.Immediate, nil
+	case o.BinOp != nil:
+		a, err := o.BinOp.A.getFelt(vm)
+		if err != nil {
+			return Felt{}, err
+		}
+		b, err := o.BinOp.B.getFelt(vm)
+		if err != nil {
+			return Felt{}, err
+		}
+		switch o.BinOp.Op {
+		case "Add":
+			return a.Add(b), nil
+		case "Mul":
+			return a.Mul(b), nil
+		default:
+			return Felt{}, errors.Errorf("Unknown operation: %s", o.BinOp.Op)
+		}
+	}
+	return Felt{}, errors.New("Empty operand")
+}
+
+// Returns the value of an operand that evaluates to a pointer
+func (o ResOperand) getRelocatable(vm *vm.VirtualMachine) (memory.Relocatable, error) {
+	switch {
+	case o.Deref != nil:
+		addr, err := o.Deref.address(vm)
+		if err != nil {
+			return memory.Relocatable{}, err
+		}
+		return vm.Segments.Memory.GetRelocatable(addr)
+	case o.BinOp != nil && o.BinOp.Op == "Add":
+		addr, err := o.BinOp.A.address(vm)
+		if err != nil {
+			return memory.Relocatable{}, err
+		}
+		ptr, err := vm.Segments.Memory.GetRelocatable(addr)
+		if err != nil {
+			return memory.Relocatable{}, err
+		}
+		offset, err := o.BinOp.B.getFelt(vm)
+		if err != nil {
+			return memory.Relocatable{}, err
+		}
+		return ptr.AddFelt(offset)
+	}
+	return memory.Relocatable{}, errors.New("Operand is not a pointer")
+}
+
+func (d DoubleDerefOperand) pointer(vm *vm.VirtualMachine) (memory.Relocatable, error) {
+	addr, err := d.Cell.address(vm)
+	if err != nil {
+		return memory.Relocatable{}, err
+	}
+	ptr, err := vm.Segments.Memory.GetRelocatable(addr)
+	if err != nil {
+		return memory.Relocatable{}, err
+	}
+	return ptr.AddFelt(feltFromInt(d.Offset))
+}
+
+type cairo1Hint struct {
+	// Name of the hint, kept to report the hints that are not supported
+	name string
+
+	AllocSegment *struct {
+		Dst CellRef `json:"dst"`
+	} `json:"AllocSegment"`
+	TestLessThan        *comparisonHint `json:"TestLessThan"`
+	TestLessThanOrEqual *comparisonHint `json:"TestLessThanOrEqual"`
+	DivMod              *struct {
+		Lhs       ResOperand `json:"lhs"`
+		Rhs       ResOperand `json:"rhs"`
+		Quotient  CellRef    `json:"quotient"`
+		Remainder CellRef    `json:"remainder"`
+	} `json:"DivMod"`
+	WideMul128 *struct {
+		Lhs  ResOperand `json:"lhs"`
+		Rhs  ResOperand `json:"rhs"`
+		High CellRef    `json:"high"`
+		Low  CellRef    `json:"low"`
+	} `json:"WideMul128"`
+	SystemCall *struct {
+		System ResOperand `json:"system"`
+	} `json:"SystemCall"`
+}
+
+type comparisonHint struct {
+	Lhs ResOperand `json:"lhs"`
+	Rhs ResOperand `json:"rhs"`
+	Dst CellRef    `json:"dst"`
+}
+
+func (p *Cairo1HintProcessor) CompileHint(hintParams *parser.HintParams, referenceManager *parser.ReferenceManager) (any, error) {
+	var names map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(hintParams.Code), &names); err != nil {
+		return nil, errors.Wrapf(err, "Invalid Cairo 1 hint")
+	}
+	if len(names) != 1 {
+		return nil, errors.Errorf("Invalid Cairo 1 hint: %s", hintParams.Code)
+	}
+	hint := cairo1Hint{}
+	for name := range names {
+		hint.name = name
+	}
+	if err := json.Unmarshal([]byte(hintParams.Code), &hint); err != nil {
+		return nil, errors.Wrapf(err, "Invalid Cairo 1 hint")
+	}
+	return hint, nil
+}
+
+func (p *Cairo1HintProcessor) ExecuteHint(vm *vm.VirtualMachine, hintData *any, constants *map[string]Felt, execScopes *types.ExecutionScopes) error {
+	hint, ok := (*hintData).(cairo1Hint)
+	if !ok {
+		return ErrWrongHintData
+	}
+	if err := p.executeHint(hint, vm); err != nil {
+		return HintError(err)
+	}
+	return nil
+}
+
+func (p *Cairo1HintProcessor) executeHint(hint cairo1Hint, vm *vm.VirtualMachine) error {
+	switch {
+	case hint.AllocSegment != nil:
+		return hint.AllocSegment.Dst.insert(vm, memory.NewMaybeRelocatableRelocatable(vm.Segments.AddSegment()))
+	case hint.TestLessThan != nil:
+		return hint.TestLessThan.execute(vm, func(cmp int) bool { return cmp < 0 })
+	case hint.TestLessThanOrEqual != nil:
+		return hint.TestLessThanOrEqual.execute(vm, func(cmp int) bool { return cmp <= 0 })
+	case hint.DivMod != nil:
+		lhs, err := hint.DivMod.Lhs.getFelt(vm)
+		if err != nil {
+			return err
+		}
+		rhs, err := hint.DivMod.Rhs.getFelt(vm)
+		if err != nil {
+			return err
+		}
+		quotient, remainder, err := lhs.CheckedDivRem(rhs)
+		if err != nil {
+			return errors.Wrap(err, "DivMod")
+		}
+		if err := hint.DivMod.Quotient.insert(vm, memory.NewMaybeRelocatableFelt(quotient)); err != nil {
+			return err
+		}
+		return hint.DivMod.Remainder.insert(vm, memory.NewMaybeRelocatableFelt(remainder))
+	case hint.WideMul128 != nil:
+		lhs, err := hint.WideMul128.Lhs.getFelt(vm)
+		if err != nil {
+			return err
+		}
+		rhs, err := hint.WideMul128.Rhs.getFelt(vm)
+		if err != nil {
+			return err
+		}
+		if lhs.Bits() > 128 || rhs.Bits() > 128 {
+			return errors.New("WideMul128: operands should be smaller than 2**128")
+		}
+		product := new(big.Int).Mul(lhs.ToBigInt(), rhs.ToBigInt())
+		low := new(big.Int).And(product, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+		high := product.Rsh(product, 128)
+		if err := hint.WideMul128.High.insert(vm, memory.NewMaybeRelocatableFelt(FeltFromBigInt(high))); err != nil {
+			return err
+		}
+		return hint.WideMul128.Low.insert(vm, memory.NewMaybeRelocatableFelt(FeltFromBigInt(low)))
+	case hint.SystemCall != nil:
+		if p.SyscallHandler == nil {
+			return ErrNoSyscallHandler
+		}
+		syscallPtr, err := hint.SystemCall.System.getRelocatable(vm)
+		if err != nil {
+			return err
+		}
+		selector, err := vm.Segments.Memory.GetFelt(syscallPtr)
+		if err != nil {
+			return err
+		}
+		return p.SyscallHandler.ExecuteSyscall(vm, shortStringToString(selector), syscallPtr)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnknownHint, hint.name)
+	}
+}
+
+func (h *comparisonHint) execute(vm *vm.VirtualMachine, holds func(cmp int) bool) error {
+	lhs, err := h.Lhs.getFelt(vm)
+	if err != nil {
+		return err
+	}
+	rhs, err := h.Rhs.getFelt(vm)
+	if err != nil {
+		return err
+	}
+	result := FeltZero()
+	if holds(lhs.Cmp(rhs)) {
+		result = FeltOne()
+	}
+	return h.Dst.insert(vm, memory.NewMaybeRelocatableFelt(result))
+}
+
+// Cairo 1 syscall selectors are the short strings of the syscall names (ie: 'StorageRead')
+func shortStringToString(felt Felt) string {
+	return strings.TrimLeft(string(felt.ToBeBytes()[:]), "\x00")
+}
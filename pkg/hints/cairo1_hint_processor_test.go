@@ -0,0 +1,156 @@
+package hints_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Vm with ap = (1, 2) & fp = (1, 0), and the given values from (1, 0)
+func cairo1HintVm(values ...*MaybeRelocatable) *VirtualMachine {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.RunContext.Ap = NewRelocatable(1, 2)
+	vm.RunContext.Fp = NewRelocatable(1, 0)
+	for i, value := range values {
+		vm.Segments.Memory.Insert(NewRelocatable(1, uint(i)), value)
+	}
+	return vm
+}
+
+func executeCairo1Hint(vm *VirtualMachine, hintProcessor *Cairo1HintProcessor, code string) error {
+	hintData, err := hintProcessor.CompileHint(&parser.HintParams{Code: code}, nil)
+	if err != nil {
+		return err
+	}
+	return hintProcessor.ExecuteHint(vm, &hintData, nil, NewExecutionScopes())
+}
+
+func TestCairo1AllocSegment(t *testing.T) {
+	vm := cairo1HintVm()
+	err := executeCairo1Hint(vm, &Cairo1HintProcessor{}, `{"AllocSegment": {"dst": {"register": "AP", "offset": 0}}}`)
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	ptr, err := vm.Segments.Memory.GetRelocatable(NewRelocatable(1, 2))
+	if err != nil || ptr != NewRelocatable(2, 0) {
+		t.Errorf("Expected a new segment at [ap], got: %s, %v", ptr.ToString(), err)
+	}
+}
+
+func TestCairo1TestLessThan(t *testing.T) {
+	vm := cairo1HintVm(NewMaybeRelocatableFelt(FeltFromUint64(7)), NewMaybeRelocatableFelt(FeltFromUint64(7)))
+	hintProcessor := &Cairo1HintProcessor{}
+	// [fp] < [fp + 1]
+	err := executeCairo1Hint(vm, hintProcessor, `{"TestLessThan": {"lhs": {"Deref": {"register": "FP", "offset": 0}}, "rhs": {"Deref": {"register": "FP", "offset": 1}}, "dst": {"register": "AP", "offset": 0}}}`)
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	// [fp] <= [fp + 1]
+	err = executeCairo1Hint(vm, hintProcessor, `{"TestLessThanOrEqual": {"lhs": {"Deref": {"register": "FP", "offset": 0}}, "rhs": {"Deref": {"register": "FP", "offset": 1}}, "dst": {"register": "AP", "offset": 1}}}`)
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	lessThan, _ := vm.Segments.Memory.GetFelt(NewRelocatable(1, 2))
+	lessThanOrEqual, _ := vm.Segments.Memory.GetFelt(NewRelocatable(1, 3))
+	if !lessThan.IsZero() || !lessThanOrEqual.IsOne() {
+		t.Errorf("Wrong comparisons, got 7 < 7: %s, 7 <= 7: %s", lessThan.ToSignedFeltString(), lessThanOrEqual.ToSignedFeltString())
+	}
+}
+
+func TestCairo1DivMod(t *testing.T) {
+	vm := cairo1HintVm(NewMaybeRelocatableFelt(FeltFromUint64(17)))
+	// [fp] divmod 5
+	err := executeCairo1Hint(vm, &Cairo1HintProcessor{}, `{"DivMod": {"lhs": {"Deref": {"register": "FP", "offset": 0}}, "rhs": {"Immediate": "0x5"}, "quotient": {"register": "AP", "offset": 0}, "remainder": {"register": "AP", "offset": 1}}}`)
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	quotient, _ := vm.Segments.Memory.GetFelt(NewRelocatable(1, 2))
+	remainder, _ := vm.Segments.Memory.GetFelt(NewRelocatable(1, 3))
+	if quotient != FeltFromUint64(3) || remainder != FeltFromUint64(2) {
+		t.Errorf("Wrong result, expected 3 & 2, got %s & %s", quotient.ToSignedFeltString(), remainder.ToSignedFeltString())
+	}
+}
+
+func TestCairo1DivModByZero(t *testing.T) {
+	vm := cairo1HintVm(NewMaybeRelocatableFelt(FeltFromUint64(17)))
+	err := executeCairo1Hint(vm, &Cairo1HintProcessor{}, `{"DivMod": {"lhs": {"Deref": {"register": "FP", "offset": 0}}, "rhs": {"Immediate": 0}, "quotient": {"register": "AP", "offset": 0}, "remainder": {"register": "AP", "offset": 1}}}`)
+	if !errors.Is(err, ErrHint) || !errors.Is(err, ErrDividedByZero) {
+		t.Errorf("Expected a division by zero hint error, got: %v", err)
+	}
+}
+
+func TestCairo1WideMul128(t *testing.T) {
+	// 2**127 * 6 = 3 * 2**128
+	vm := cairo1HintVm(NewMaybeRelocatableFelt(FeltOne().Shl(127)))
+	err := executeCairo1Hint(vm, &Cairo1HintProcessor{}, `{"WideMul128": {"lhs": {"Deref": {"register": "FP", "offset": 0}}, "rhs": {"Immediate": 6}, "high": {"register": "AP", "offset": 0}, "low": {"register": "AP", "offset": 1}}}`)
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	high, _ := vm.Segments.Memory.GetFelt(NewRelocatable(1, 2))
+	low, _ := vm.Segments.Memory.GetFelt(NewRelocatable(1, 3))
+	if high != FeltFromUint64(3) || !low.IsZero() {
+		t.Errorf("Wrong result, expected 3 & 0, got %s & %s", high.ToSignedFeltString(), low.ToSignedFeltString())
+	}
+}
+
+func TestCairo1DoubleDerefAndBinOpOperands(t *testing.T) {
+	// [fp] = (1, 1), [fp + 1] = 4
+	vm := cairo1HintVm(NewMaybeRelocatableRelocatable(NewRelocatable(1, 1)), NewMaybeRelocatableFelt(FeltFromUint64(4)))
+	// [[fp] + 0] < [fp + 1] * 2
+	err := executeCairo1Hint(vm, &Cairo1HintProcessor{}, `{"TestLessThan": {"lhs": {"DoubleDeref": [{"register": "FP", "offset": 0}, 0]}, "rhs": {"BinOp": {"op": "Mul", "a": {"register": "FP", "offset": 1}, "b": {"Immediate": "0x2"}}}, "dst": {"register": "AP", "offset": 0}}}`)
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	result, _ := vm.Segments.Memory.GetFelt(NewRelocatable(1, 2))
+	if !result.IsOne() {
+		t.Errorf("Expected 4 < 8, got %s", result.ToSignedFeltString())
+	}
+}
+
+type recordingSyscallHandler struct {
+	name       string
+	syscallPtr Relocatable
+}
+
+func (h *recordingSyscallHandler) ExecuteSyscall(vm *VirtualMachine, name string, syscallPtr Relocatable) error {
+	h.name = name
+	h.syscallPtr = syscallPtr
+	return nil
+}
+
+func TestCairo1SystemCall(t *testing.T) {
+	// [fp] = (2, 0), [(2, 0)] = 'StorageRead'
+	vm := cairo1HintVm(NewMaybeRelocatableRelocatable(NewRelocatable(2, 0)))
+	vm.Segments.AddSegment()
+	selector := FeltFromBeBytes(&[32]byte{21: 'S', 't', 'o', 'r', 'a', 'g', 'e', 'R', 'e', 'a', 'd'})
+	vm.Segments.Memory.Insert(NewRelocatable(2, 0), NewMaybeRelocatableFelt(selector))
+	code := `{"SystemCall": {"system": {"Deref": {"register": "FP", "offset": 0}}}}`
+
+	if err := executeCairo1Hint(vm, &Cairo1HintProcessor{}, code); !errors.Is(err, ErrNoSyscallHandler) {
+		t.Errorf("Expected ErrNoSyscallHandler, got: %v", err)
+	}
+
+	handler := &recordingSyscallHandler{}
+	if err := executeCairo1Hint(vm, &Cairo1HintProcessor{SyscallHandler: handler}, code); err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	if handler.name != "StorageRead" || handler.syscallPtr != NewRelocatable(2, 0) {
+		t.Errorf("Wrong syscall, got %q at %s", handler.name, handler.syscallPtr.ToString())
+	}
+}
+
+func TestCairo1UnknownHint(t *testing.T) {
+	vm := cairo1HintVm()
+	err := executeCairo1Hint(vm, &Cairo1HintProcessor{}, `{"AllocFelt252Dict": {"segment_arena_ptr": {"Deref": {"register": "FP", "offset": -3}}}}`)
+	if !errors.Is(err, ErrUnknownHint) {
+		t.Errorf("Expected ErrUnknownHint, got: %v", err)
+	}
+}
@@ -20,7 +20,16 @@ func FetchDictManager(scopes *ExecutionScopes) (*DictManager, bool) {
 	return val, ok
 }
 
-func defaultDictNew(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) error {
+// Returns the tracker of the dict at dictPtr, catching up with the accesses written to the dict's segment without going
+// through the hints if the dict manager has MemoryFallback set
+func getDictTracker(dictManager *DictManager, dictPtr memory.Relocatable, vm *VirtualMachine) (*DictTracker, error) {
+	if err := dictManager.CatchUpTracker(dictPtr, &vm.Segments.Memory); err != nil {
+		return nil, err
+	}
+	return dictManager.GetTracker(dictPtr)
+}
+
+func defaultDictNew(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine, memoryFallback bool) error {
 	defaultValue, err := ids.Get("default_value", vm)
 	if err != nil {
 		return err
@@ -28,11 +37,12 @@ func defaultDictNew(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine)
 	dictManager, ok := FetchDictManager(scopes)
 	if !ok {
 		newDictManager := NewDictManager()
+		newDictManager.MemoryFallback = memoryFallback
 		dictManager = &newDictManager
 		scopes.AssignOrUpdateVariable("__dict_manager", dictManager)
 	}
 	base := dictManager.NewDefaultDictionary(defaultValue, vm)
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, memory.NewMaybeRelocatableRelocatable(base))
+	return vm.InsertAtAp(0, memory.NewMaybeRelocatableRelocatable(base))
 }
 
 func dictRead(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) error {
@@ -50,12 +60,12 @@ func dictRead(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) error
 		return err
 	}
 	// Hint Logic
-	tracker, err := dictManager.GetTracker(dict_ptr)
+	tracker, err := getDictTracker(dictManager, dict_ptr, vm)
 	if err != nil {
 		return err
 	}
 	tracker.CurrentPtr.Offset += DICT_ACCESS_SIZE
-	val, err := tracker.GetValue(key)
+	val, err := dictManager.GetValue(tracker, key, &vm.Segments.Memory)
 	if err != nil {
 		return err
 	}
@@ -90,12 +100,12 @@ func dictWrite(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) erro
 	*/
 	prev_val_addr := dict_ptr.AddUint(1)
 	// Hint Logic
-	tracker, err := dictManager.GetTracker(dict_ptr)
+	tracker, err := getDictTracker(dictManager, dict_ptr, vm)
 	if err != nil {
 		return err
 	}
 	tracker.CurrentPtr.Offset += DICT_ACCESS_SIZE
-	prev_val, err := tracker.GetValue(key)
+	prev_val, err := dictManager.GetValue(tracker, key, &vm.Segments.Memory)
 	if err != nil {
 		return err
 	}
@@ -126,16 +136,16 @@ func dictUpdate(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) err
 		return err
 	}
 	// Hint Logic
-	tracker, err := dictManager.GetTracker(dict_ptr)
+	tracker, err := getDictTracker(dictManager, dict_ptr, vm)
 	if err != nil {
 		return err
 	}
-	current_value, err := tracker.GetValue(key)
+	current_value, err := dictManager.GetValue(tracker, key, &vm.Segments.Memory)
 	if err != nil {
 		return err
 	}
 	if *prev_value != *current_value {
-		return errors.Errorf("Wrong previous value in dict. Got %v, expected %v.", *current_value, *prev_value)
+		return errors.Errorf("Wrong previous value in dict. Got %s, expected %s.", current_value, prev_value)
 	}
 	tracker.InsertValue(key, new_value)
 	tracker.CurrentPtr.Offset += DICT_ACCESS_SIZE
@@ -153,7 +163,7 @@ func dictSquashCopyDict(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMach
 		return err
 	}
 	// Hint logic
-	tracker, err := dictManager.GetTracker(dictAccessEnd)
+	tracker, err := getDictTracker(dictManager, dictAccessEnd, vm)
 	if err != nil {
 		return err
 	}
@@ -180,7 +190,7 @@ func dictSquashUpdatePtr(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMac
 		return err
 	}
 	// Hint logic
-	tracker, err := dictManager.GetTracker(squashedDictStart)
+	tracker, err := getDictTracker(dictManager, squashedDictStart, vm)
 	if err != nil {
 		return err
 	}
@@ -188,7 +198,7 @@ func dictSquashUpdatePtr(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMac
 	return nil
 }
 
-func dictNew(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) error {
+func dictNew(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine, memoryFallback bool) error {
 	// Fetch scope variables
 	initialDictAny, err := scopes.Get("initial_dict")
 	if err != nil {
@@ -202,9 +212,10 @@ func dictNew(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) error
 	dictManager, ok := FetchDictManager(scopes)
 	if !ok {
 		newDictManager := NewDictManager()
+		newDictManager.MemoryFallback = memoryFallback
 		dictManager = &newDictManager
 		scopes.AssignOrUpdateVariable("__dict_manager", dictManager)
 	}
 	dict_ptr := dictManager.NewDictionary(&initialDict, vm)
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, memory.NewMaybeRelocatableRelocatable(dict_ptr))
+	return vm.InsertAtAp(0, memory.NewMaybeRelocatableRelocatable(dict_ptr))
 }
@@ -47,6 +47,32 @@ func TestDefaultDictNewCreateManager(t *testing.T) {
 	}
 }
 
+func TestDefaultDictNewCreateManagerWithMemoryFallback(t *testing.T) {
+	vm := NewVirtualMachine()
+	scopes := types.NewExecutionScopes()
+	vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"default_value": {NewMaybeRelocatableFelt(FeltFromUint64(17))},
+		},
+		vm,
+	)
+	hintProcessor := CairoVmHintProcessor{DictMemoryFallback: true}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: DEFAULT_DICT_NEW,
+	})
+	vm.RunContext.Ap = NewRelocatable(0, 5)
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, scopes)
+	if err != nil {
+		t.Errorf("DEFAULT_DICT_NEW hint test failed with error %s", err)
+	}
+	dictManager, ok := FetchDictManager(scopes)
+	if !ok || !dictManager.MemoryFallback {
+		t.Error("DEFAULT_DICT_NEW DictManager created without the memory fallback")
+	}
+}
+
 func TestDefaultDictNewHasManager(t *testing.T) {
 	vm := NewVirtualMachine()
 	scopes := types.NewExecutionScopes()
@@ -493,6 +519,73 @@ func TestDictSqushCopyDictOkNonEmptyDict(t *testing.T) {
 	}
 }
 
+func TestDictSquashKeepsKeysOnlyRead(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	scopes := types.NewExecutionScopes()
+	hintProcessor := CairoVmHintProcessor{}
+
+	dictManager := dict_manager.NewDictManager()
+	dictPtr := dictManager.NewDefaultDictionary(NewMaybeRelocatableFelt(FeltFromUint64(17)), vm)
+	scopes.AssignOrUpdateVariable("__dict_manager", &dictManager)
+
+	// Read key 1 without ever writing it
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"key":      {NewMaybeRelocatableFelt(FeltOne())},
+			"dict_ptr": {NewMaybeRelocatableRelocatable(dictPtr)},
+			"value":    {nil},
+		},
+		vm,
+	)
+	hintData := any(HintData{Ids: idsManager, Code: DICT_READ})
+	if err := hintProcessor.ExecuteHint(vm, &hintData, nil, scopes); err != nil {
+		t.Fatalf("DICT_READ hint failed with error %s", err)
+	}
+
+	// Squash: copy the dict & create the squashed dict from the copy
+	// Each hint gets its ids at a fresh fp, as the cells of the previous ones are already written
+	vm.RunContext.Fp = NewRelocatable(0, 10)
+	idsManager = SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"dict_accesses_end": {NewMaybeRelocatableRelocatable(dictPtr.AddUint(DICT_ACCESS_SIZE))},
+		},
+		vm,
+	)
+	hintData = any(HintData{Ids: idsManager, Code: DICT_SQUASH_COPY_DICT})
+	if err := hintProcessor.ExecuteHint(vm, &hintData, nil, scopes); err != nil {
+		t.Fatalf("DICT_SQUASH_COPY_DICT hint failed with error %s", err)
+	}
+	vm.Segments.AddSegment()
+	vm.RunContext.Ap = NewRelocatable(int(vm.Segments.Memory.NumSegments())-1, 0)
+	hintData = any(HintData{Ids: IdsManager{}, Code: DICT_NEW})
+	if err := hintProcessor.ExecuteHint(vm, &hintData, nil, scopes); err != nil {
+		t.Fatalf("DICT_NEW hint failed with error %s", err)
+	}
+	squashedDictPtr, err := vm.Segments.Memory.GetRelocatable(vm.RunContext.Ap)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Read key 1 again from the squashed dict
+	vm.RunContext.Fp = NewRelocatable(0, 20)
+	idsManager = SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"key":      {NewMaybeRelocatableFelt(FeltOne())},
+			"dict_ptr": {NewMaybeRelocatableRelocatable(squashedDictPtr)},
+			"value":    {nil},
+		},
+		vm,
+	)
+	hintData = any(HintData{Ids: idsManager, Code: DICT_READ})
+	if err := hintProcessor.ExecuteHint(vm, &hintData, nil, scopes); err != nil {
+		t.Fatalf("DICT_READ hint on the squashed dict failed with error %s", err)
+	}
+	if value, err := idsManager.GetFelt("value", vm); err != nil || value != FeltFromUint64(17) {
+		t.Errorf("Wrong value read from the squashed dict: %s, %v", value.ToSignedFeltString(), err)
+	}
+}
+
 func TestDictSquashUpdatePtrOk(t *testing.T) {
 	vm := NewVirtualMachine()
 	vm.Segments.AddSegment()
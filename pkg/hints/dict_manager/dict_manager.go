@@ -1,6 +1,10 @@
 package dict_manager
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 	"github.com/pkg/errors"
@@ -10,6 +14,10 @@ import (
 // Uses the segment index to associate the corresponding go dict with the Cairo dict.
 type DictManager struct {
 	trackers map[int]*DictTracker
+	// If set, reads of keys missing from a tracker fall back to the accesses written to the dict's segment,
+	// and trackers catch up with pointers that are ahead of them by whole accesses
+	// Needed by programs that write to dict segments directly between hints
+	MemoryFallback bool
 }
 
 func NewDictManager() DictManager {
@@ -18,6 +26,22 @@ func NewDictManager() DictManager {
 	}
 }
 
+// Returns a deep copy of the manager, its trackers aren't shared with it
+func (d *DictManager) Clone() DictManager {
+	clone := DictManager{trackers: make(map[int]*DictTracker, len(d.trackers)), MemoryFallback: d.MemoryFallback}
+	for segmentIndex, tracker := range d.trackers {
+		trackerClone := tracker.Clone()
+		clone.trackers[segmentIndex] = &trackerClone
+	}
+	return clone
+}
+
+// Implements types.ScopeValueCloner, so that cloned execution scopes (ie: of a forked run) don't share the dicts
+func (d *DictManager) CloneScopeValue() interface{} {
+	clone := d.Clone()
+	return &clone
+}
+
 func (d *DictManager) NewDictionary(dict *map[MaybeRelocatable]MaybeRelocatable, vm *VirtualMachine) Relocatable {
 	base := vm.Segments.AddSegment()
 	newTracker := NewDictTrackerForDictionary(base, dict)
@@ -38,11 +62,58 @@ func (d *DictManager) GetTracker(dict_ptr Relocatable) (*DictTracker, error) {
 		return nil, errors.Errorf("Dict Error: No dict tracker found for segment %d", dict_ptr.SegmentIndex)
 	}
 	if tracker.CurrentPtr != dict_ptr {
-		return nil, errors.Errorf("Dict Error: Wrong dict pointer supplied. Got %v, expected %v", dict_ptr, tracker.CurrentPtr)
+		return nil, errors.Errorf("Dict Error: Wrong dict pointer supplied. Got %s, expected %s", dict_ptr, tracker.CurrentPtr)
 	}
 	return tracker, nil
 }
 
+// With MemoryFallback set, moves the pointer of the tracker of dict_ptr's segment up to dict_ptr if the program wrote
+// whole accesses to the dict's segment between them without going through the hints
+// Fails if any of the cells of those accesses is missing, in which case the tracker is left as is
+// Does nothing without MemoryFallback, or if dict_ptr isn't ahead of the tracker by whole accesses
+func (d *DictManager) CatchUpTracker(dict_ptr Relocatable, mem *Memory) error {
+	tracker, ok := d.trackers[dict_ptr.SegmentIndex]
+	if !d.MemoryFallback || !ok {
+		return nil
+	}
+	if dict_ptr.Offset <= tracker.CurrentPtr.Offset || (dict_ptr.Offset-tracker.CurrentPtr.Offset)%dictAccessSize != 0 {
+		return nil
+	}
+	if _, err := GetDictRange(mem, tracker.CurrentPtr, dict_ptr); err != nil {
+		return err
+	}
+	tracker.CurrentPtr = dict_ptr
+	return nil
+}
+
+// Returns the value of key in the dict tracked by tracker
+// If the tracker doesn't hold the key & MemoryFallback is set, the value is taken from the last complete
+// access to the key in the dict's segment before the tracker's current pointer
+func (d *DictManager) GetValue(tracker *DictTracker, key *MaybeRelocatable, mem *Memory) (*MaybeRelocatable, error) {
+	if val, ok := tracker.data.dict[*key]; ok {
+		return &val, nil
+	}
+	if d.MemoryFallback {
+		if val, ok := tracker.lastWrittenValue(key, mem); ok {
+			tracker.InsertValue(key, val)
+			return val, nil
+		}
+	}
+	return tracker.GetValue(key)
+}
+
+// Returns the trackers of every dict created by the manager, sorted by segment index
+func (d *DictManager) Trackers() []*DictTracker {
+	trackers := make([]*DictTracker, 0, len(d.trackers))
+	for _, tracker := range d.trackers {
+		trackers = append(trackers, tracker)
+	}
+	sort.Slice(trackers, func(i, j int) bool {
+		return trackers[i].CurrentPtr.SegmentIndex < trackers[j].CurrentPtr.SegmentIndex
+	})
+	return trackers
+}
+
 // Tracks the go dict associated with a Cairo dict.
 type DictTracker struct {
 	data Dictionary
@@ -64,14 +135,20 @@ func NewDictTrackerForDefaultDictionary(base Relocatable, defaultValue *MaybeRel
 	}
 }
 
+// Returns a copy of the dict's contents, which can be modified without affecting the tracker
 func (d *DictTracker) CopyDictionary() map[MaybeRelocatable]MaybeRelocatable {
-	return d.data.dict
+	return d.data.Clone().dict
+}
+
+// Returns a deep copy of the tracker
+func (d *DictTracker) Clone() DictTracker {
+	return DictTracker{data: d.data.Clone(), CurrentPtr: d.CurrentPtr}
 }
 
 func (d *DictTracker) GetValue(key *MaybeRelocatable) (*MaybeRelocatable, error) {
 	val := d.data.Get(key)
 	if val == nil {
-		return nil, errors.Errorf("Dict Error: No value found for key: %v", key)
+		return nil, errors.Errorf("Dict Error: No value found for key: %s", key)
 	}
 	return val, nil
 }
@@ -80,6 +157,128 @@ func (d *DictTracker) InsertValue(key *MaybeRelocatable, val *MaybeRelocatable)
 	d.data.Insert(key, val)
 }
 
+// Searches the dict's segment backwards from the current pointer for the new value of the last access to key
+// Accesses with missing cells are skipped
+func (d *DictTracker) lastWrittenValue(key *MaybeRelocatable, mem *Memory) (*MaybeRelocatable, bool) {
+	for offset := d.CurrentPtr.Offset; offset >= dictAccessSize; offset -= dictAccessSize {
+		access, err := getDictAccess(mem, NewRelocatable(d.CurrentPtr.SegmentIndex, offset-dictAccessSize))
+		if err == nil && access.Key.IsEqual(key) {
+			return &access.NewValue, true
+		}
+	}
+	return nil, false
+}
+
+// Returns the accesses written to the dict's segment, from its base to the current pointer
+func (d *DictTracker) GetDictRange(mem *Memory) ([]DictAccess, error) {
+	return GetDictRange(mem, NewRelocatable(d.CurrentPtr.SegmentIndex, 0), d.CurrentPtr)
+}
+
+// Number of cells of a DictAccess struct
+const dictAccessSize = 3
+
+// Access to a Cairo dict, as laid out in memory by the DictAccess struct
+type DictAccess struct {
+	Key       MaybeRelocatable
+	PrevValue MaybeRelocatable
+	NewValue  MaybeRelocatable
+}
+
+// Reads the accesses between start & end, fails if a cell is missing or if the range doesn't hold a whole number of accesses
+func GetDictRange(mem *Memory, start Relocatable, end Relocatable) ([]DictAccess, error) {
+	if start.SegmentIndex != end.SegmentIndex || end.Offset < start.Offset {
+		return nil, errors.Errorf("Dict Error: Invalid dict range from %s to %s", start.ToString(), end.ToString())
+	}
+	size := end.Offset - start.Offset
+	if size%dictAccessSize != 0 {
+		return nil, errors.Errorf("Dict Error: The range from %s to %s doesn't hold a whole number of dict accesses", start.ToString(), end.ToString())
+	}
+	accesses := make([]DictAccess, 0, size/dictAccessSize)
+	for offset := uint(0); offset < size; offset += dictAccessSize {
+		access, err := getDictAccess(mem, start.AddUint(offset))
+		if err != nil {
+			return nil, err
+		}
+		accesses = append(accesses, access)
+	}
+	return accesses, nil
+}
+
+func getDictAccess(mem *Memory, addr Relocatable) (DictAccess, error) {
+	var cells [dictAccessSize]MaybeRelocatable
+	for i := range cells {
+		cellAddr := addr.AddUint(uint(i))
+		cell, err := mem.Get(cellAddr)
+		if err != nil {
+			return DictAccess{}, errors.Errorf("Dict Error: Missing dict access cell at %s", cellAddr.ToString())
+		}
+		cells[i] = *cell
+	}
+	return DictAccess{Key: cells[0], PrevValue: cells[1], NewValue: cells[2]}, nil
+}
+
+// Snapshot of the state of a Cairo dict, used to debug dict errors
+type DictTrackerDump struct {
+	// Pointer the next dict hint is expected to receive
+	CurrentPtr Relocatable
+	// Nil unless the dict is a default dict
+	DefaultValue *MaybeRelocatable
+	// Sorted by key, felts first
+	Entries []DictEntry
+}
+
+type DictEntry struct {
+	Key   MaybeRelocatable
+	Value MaybeRelocatable
+}
+
+// Returns a copy of the current pointer & contents of the dict
+func (d *DictTracker) Dump() DictTrackerDump {
+	dump := DictTrackerDump{CurrentPtr: d.CurrentPtr, Entries: make([]DictEntry, 0, len(d.data.dict))}
+	if d.data.defaultValue != nil {
+		defaultValue := *d.data.defaultValue
+		dump.DefaultValue = &defaultValue
+	}
+	for key, value := range d.data.dict {
+		dump.Entries = append(dump.Entries, DictEntry{Key: key, Value: value})
+	}
+	sort.Slice(dump.Entries, func(i, j int) bool {
+		return lessMaybeRelocatable(&dump.Entries[i].Key, &dump.Entries[j].Key)
+	})
+	return dump
+}
+
+// Formats the dump as a line describing the dict followed by one line per entry
+func (d *DictTrackerDump) ToString() string {
+	var builder strings.Builder
+	base := NewRelocatable(d.CurrentPtr.SegmentIndex, 0)
+	fmt.Fprintf(&builder, "dict %s, current ptr %s", base.ToString(), d.CurrentPtr.ToString())
+	if d.DefaultValue != nil {
+		fmt.Fprintf(&builder, ", default value %s", d.DefaultValue.ToString())
+	}
+	for _, entry := range d.Entries {
+		fmt.Fprintf(&builder, "\n  %s -> %s", entry.Key.ToString(), entry.Value.ToString())
+	}
+	return builder.String()
+}
+
+func lessMaybeRelocatable(a *MaybeRelocatable, b *MaybeRelocatable) bool {
+	aFelt, aIsFelt := a.GetFelt()
+	bFelt, bIsFelt := b.GetFelt()
+	if aIsFelt != bIsFelt {
+		return aIsFelt
+	}
+	if aIsFelt {
+		return aFelt.Cmp(bFelt) < 0
+	}
+	aRel, _ := a.GetRelocatable()
+	bRel, _ := b.GetRelocatable()
+	if aRel.SegmentIndex != bRel.SegmentIndex {
+		return aRel.SegmentIndex < bRel.SegmentIndex
+	}
+	return aRel.Offset < bRel.Offset
+}
+
 type Dictionary struct {
 	dict         map[MaybeRelocatable]MaybeRelocatable
 	defaultValue *MaybeRelocatable
@@ -99,6 +298,10 @@ func NewDictionary(dict *map[MaybeRelocatable]MaybeRelocatable) Dictionary {
 	}
 }
 
+// Returns a copy of the value of key, or of the default value if the dict doesn't hold the key
+// As with python's defaultdict, reading a missing key of a default dict stores the default value, so that copies
+// of the dict (ie: the initial dict of a squashed dict) hold every key that was read
+// Returns nil if the key is missing from a dict without a default value
 func (d *Dictionary) Get(key *MaybeRelocatable) *MaybeRelocatable {
 	val, ok := d.dict[*key]
 	if ok {
@@ -106,8 +309,23 @@ func (d *Dictionary) Get(key *MaybeRelocatable) *MaybeRelocatable {
 	}
 	if d.defaultValue != nil {
 		d.dict[*key] = *d.defaultValue
+		defaultValue := *d.defaultValue
+		return &defaultValue
+	}
+	return nil
+}
+
+// Returns a deep copy of the dictionary
+func (d *Dictionary) Clone() Dictionary {
+	clone := Dictionary{dict: make(map[MaybeRelocatable]MaybeRelocatable, len(d.dict))}
+	for key, value := range d.dict {
+		clone.dict[key] = value
+	}
+	if d.defaultValue != nil {
+		defaultValue := *d.defaultValue
+		clone.defaultValue = &defaultValue
 	}
-	return d.defaultValue
+	return clone
 }
 
 func (d *Dictionary) Insert(key *MaybeRelocatable, val *MaybeRelocatable) {
@@ -110,6 +110,29 @@ func TestDictTrackerDefaultGetValueNotPresent(t *testing.T) {
 	}
 }
 
+func TestDictTrackerCopyDictionaryIsACopy(t *testing.T) {
+	dictTracker := NewDictTrackerForDefaultDictionary(
+		NewRelocatable(0, 0),
+		NewMaybeRelocatableFelt(FeltFromUint64(17)),
+	)
+	key := NewMaybeRelocatableFelt(FeltFromUint64(1))
+	dictTracker.InsertValue(key, NewMaybeRelocatableFelt(FeltFromUint64(2)))
+
+	dictCopy := dictTracker.CopyDictionary()
+	dictCopy[*key] = *NewMaybeRelocatableFelt(FeltFromUint64(3))
+	clone := dictTracker.Clone()
+	clone.InsertValue(key, NewMaybeRelocatableFelt(FeltFromUint64(4)))
+
+	val, err := dictTracker.GetValue(key)
+	if err != nil || *val != *NewMaybeRelocatableFelt(FeltFromUint64(2)) {
+		t.Errorf("The tracker was modified through its copies, got: %v, %v", val, err)
+	}
+	val, err = clone.GetValue(NewMaybeRelocatableFelt(FeltFromUint64(5)))
+	if err != nil || *val != *NewMaybeRelocatableFelt(FeltFromUint64(17)) {
+		t.Errorf("The clone should keep the default value, got: %v, %v", val, err)
+	}
+}
+
 func TestDictTrackerGetValueNotPresent(t *testing.T) {
 	initialDict := &map[MaybeRelocatable]MaybeRelocatable{
 		*NewMaybeRelocatableFelt(FeltFromUint64(1)): *NewMaybeRelocatableFelt(FeltFromUint64(2)),
@@ -209,3 +232,126 @@ func TestDefaultDictionary(t *testing.T) {
 		t.Error("Wrong value returned by Get")
 	}
 }
+
+func TestDictManagerTrackersAndDump(t *testing.T) {
+	dictManager := NewDictManager()
+	vm := vm.NewVirtualMachine()
+	initialDict := &map[MaybeRelocatable]MaybeRelocatable{
+		*NewMaybeRelocatableFelt(FeltFromUint64(7)):           *NewMaybeRelocatableFelt(FeltFromUint64(1)),
+		*NewMaybeRelocatableRelocatable(NewRelocatable(1, 0)): *NewMaybeRelocatableFelt(FeltFromUint64(3)),
+		*NewMaybeRelocatableFelt(FeltFromUint64(2)):           *NewMaybeRelocatableRelocatable(NewRelocatable(1, 4)),
+	}
+	firstBase := dictManager.NewDictionary(initialDict, vm)
+	secondBase := dictManager.NewDefaultDictionary(NewMaybeRelocatableFelt(FeltZero()), vm)
+	first, _ := dictManager.GetTracker(firstBase)
+	first.CurrentPtr = firstBase.AddUint(3)
+
+	trackers := dictManager.Trackers()
+	if len(trackers) != 2 || trackers[0].CurrentPtr.SegmentIndex != firstBase.SegmentIndex || trackers[1].CurrentPtr != secondBase {
+		t.Fatalf("Wrong trackers: %v", trackers)
+	}
+
+	dump := trackers[0].Dump()
+	expectedEntries := []DictEntry{
+		{Key: *NewMaybeRelocatableFelt(FeltFromUint64(2)), Value: *NewMaybeRelocatableRelocatable(NewRelocatable(1, 4))},
+		{Key: *NewMaybeRelocatableFelt(FeltFromUint64(7)), Value: *NewMaybeRelocatableFelt(FeltFromUint64(1))},
+		{Key: *NewMaybeRelocatableRelocatable(NewRelocatable(1, 0)), Value: *NewMaybeRelocatableFelt(FeltFromUint64(3))},
+	}
+	if dump.CurrentPtr != firstBase.AddUint(3) || dump.DefaultValue != nil || !reflect.DeepEqual(dump.Entries, expectedEntries) {
+		t.Errorf("Wrong dump: %+v", dump)
+	}
+	expected := "dict {0:0}, current ptr {0:3}\n  2 -> {1:4}\n  7 -> 1\n  {1:0} -> 3"
+	if dump.ToString() != expected {
+		t.Errorf("Wrong format, expected:\n%s\ngot:\n%s", expected, dump.ToString())
+	}
+
+	defaultDump := trackers[1].Dump()
+	if defaultDump.ToString() != "dict {1:0}, current ptr {1:0}, default value 0" {
+		t.Errorf("Wrong format for the default dict: %s", defaultDump.ToString())
+	}
+}
+
+func TestGetDictRange(t *testing.T) {
+	vm := vm.NewVirtualMachine()
+	dictManager := NewDictManager()
+	base := dictManager.NewDictionary(&map[MaybeRelocatable]MaybeRelocatable{}, vm)
+	for i, value := range []uint64{1, 0, 5, 2, 0, 7} {
+		vm.Segments.Memory.Insert(base.AddUint(uint(i)), NewMaybeRelocatableFelt(FeltFromUint64(value)))
+	}
+
+	accesses, err := GetDictRange(&vm.Segments.Memory, base, base.AddUint(6))
+	if err != nil {
+		t.Fatalf("GetDictRange failed: %s", err)
+	}
+	expected := []DictAccess{
+		{Key: *NewMaybeRelocatableFelt(FeltFromUint64(1)), PrevValue: *NewMaybeRelocatableFelt(FeltZero()), NewValue: *NewMaybeRelocatableFelt(FeltFromUint64(5))},
+		{Key: *NewMaybeRelocatableFelt(FeltFromUint64(2)), PrevValue: *NewMaybeRelocatableFelt(FeltZero()), NewValue: *NewMaybeRelocatableFelt(FeltFromUint64(7))},
+	}
+	if !reflect.DeepEqual(accesses, expected) {
+		t.Errorf("Wrong accesses: %+v", accesses)
+	}
+
+	tracker, _ := dictManager.GetTracker(base)
+	accesses, err = tracker.GetDictRange(&vm.Segments.Memory)
+	if err != nil || len(accesses) != 0 {
+		t.Errorf("Expected no accesses before the tracker's pointer, got: %v, %v", accesses, err)
+	}
+	if _, err := GetDictRange(&vm.Segments.Memory, base, base.AddUint(4)); err == nil {
+		t.Error("Expected an error for a range with a partial access")
+	}
+	if _, err := GetDictRange(&vm.Segments.Memory, base, base.AddUint(9)); err == nil {
+		t.Error("Expected an error for a range with missing cells")
+	}
+}
+
+func TestDictManagerMemoryFallback(t *testing.T) {
+	vm := vm.NewVirtualMachine()
+	dictManager := NewDictManager()
+	base := dictManager.NewDictionary(&map[MaybeRelocatable]MaybeRelocatable{}, vm)
+	// The program writes an access to key 1 without going through the dict hints
+	for i, value := range []uint64{1, 0, 5} {
+		vm.Segments.Memory.Insert(base.AddUint(uint(i)), NewMaybeRelocatableFelt(FeltFromUint64(value)))
+	}
+	key := NewMaybeRelocatableFelt(FeltOne())
+
+	if _, err := dictManager.GetTracker(base.AddUint(3)); err == nil {
+		t.Error("Expected a wrong pointer error without the memory fallback")
+	}
+
+	dictManager.MemoryFallback = true
+	// GetTracker never moves the tracker, the hints catch up explicitly
+	if _, err := dictManager.GetTracker(base.AddUint(3)); err == nil {
+		t.Error("Expected a wrong pointer error before catching up")
+	}
+	if err := dictManager.CatchUpTracker(base.AddUint(3), &vm.Segments.Memory); err != nil {
+		t.Fatalf("CatchUpTracker failed: %s", err)
+	}
+	tracker, err := dictManager.GetTracker(base.AddUint(3))
+	if err != nil {
+		t.Fatalf("GetTracker failed: %s", err)
+	}
+	value, err := dictManager.GetValue(tracker, key, &vm.Segments.Memory)
+	if err != nil || *value != *NewMaybeRelocatableFelt(FeltFromUint64(5)) {
+		t.Errorf("Expected the value written to memory, got: %v, %v", value, err)
+	}
+	if _, err := dictManager.GetValue(tracker, NewMaybeRelocatableFelt(FeltFromUint64(2)), &vm.Segments.Memory); err == nil {
+		t.Error("Expected an error for a key that was never written")
+	}
+}
+
+func TestDictManagerCatchUpTrackerMissingCells(t *testing.T) {
+	vm := vm.NewVirtualMachine()
+	dictManager := NewDictManager()
+	dictManager.MemoryFallback = true
+	base := dictManager.NewDictionary(&map[MaybeRelocatable]MaybeRelocatable{}, vm)
+	// The access written by the program is missing its new value
+	vm.Segments.Memory.Insert(base, NewMaybeRelocatableFelt(FeltOne()))
+	vm.Segments.Memory.Insert(base.AddUint(1), NewMaybeRelocatableFelt(FeltZero()))
+
+	if err := dictManager.CatchUpTracker(base.AddUint(3), &vm.Segments.Memory); err == nil {
+		t.Error("Expected an error for an access with missing cells")
+	}
+	if _, err := dictManager.GetTracker(base); err != nil {
+		t.Errorf("The tracker should have been left at its base: %s", err)
+	}
+}
@@ -0,0 +1,68 @@
+package hints_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Fails if a hint discards the error returned by a write to the memory or to an identifier
+// (ids.Insert, ids.InsertStructField, vm.Segments.Memory.Insert, ...), as a failed write must abort the hint
+func TestHintsDontDiscardInsertErrors(t *testing.T) {
+	files, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fileSet := token.NewFileSet()
+	for _, path := range files {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fileSet, path, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ast.Inspect(file, func(node ast.Node) bool {
+			var call ast.Expr
+			switch statement := node.(type) {
+			case *ast.ExprStmt:
+				call = statement.X
+			case *ast.AssignStmt:
+				// Only the error is discarded
+				if ident, ok := statement.Lhs[len(statement.Lhs)-1].(*ast.Ident); ok && ident.Name == "_" && len(statement.Rhs) == 1 {
+					call = statement.Rhs[0]
+				}
+			}
+			if name, ok := insertCallName(call); ok {
+				t.Errorf("%s: the error returned by %s is discarded", fileSet.Position(node.Pos()), name)
+			}
+			return true
+		})
+	}
+}
+
+// Returns the name of the called method if expr is a call to one of the IdsManager's Insert methods or to Memory.Insert
+func insertCallName(expr ast.Expr) (string, bool) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return "", false
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", false
+	}
+	switch receiver := selector.X.(type) {
+	case *ast.Ident:
+		if receiver.Name == "ids" && strings.HasPrefix(selector.Sel.Name, "Insert") {
+			return "ids." + selector.Sel.Name, true
+		}
+	case *ast.SelectorExpr:
+		if receiver.Sel.Name == "Memory" && selector.Sel.Name == "Insert" {
+			return "Memory.Insert", true
+		}
+	}
+	return "", false
+}
@@ -9,41 +9,98 @@ import (
 	"github.com/pkg/errors"
 )
 
-func findElement(ids IdsManager, vm *VirtualMachine, execScopes ExecutionScopes) error {
-	arrayPtr, err := ids.GetRelocatable("array_ptr", vm)
+// Scope variables used by cairo-lang to guide find_element & search_sorted_lower
+// The names without the leading underscores are also accepted, for compatibility with older versions of the vm
+const (
+	FIND_ELEMENT_INDEX    = "__find_element_index"
+	FIND_ELEMENT_MAX_SIZE = "__find_element_max_size"
+)
+
+// Returns the value of the scope variable name (or of its legacy name without underscores) as a felt
+// The bool is false if the variable is not in scope
+func getScopeVarFelt(execScopes *ExecutionScopes, name string) (Felt, string, bool, error) {
+	for _, varName := range []string{name, name[2:]} {
+		value, err := execScopes.Get(varName)
+		if err != nil {
+			continue
+		}
+		switch v := value.(type) {
+		case Felt:
+			return v, varName, true, nil
+		case uint:
+			return FeltFromUint(v), varName, true, nil
+		case uint64:
+			return FeltFromUint64(v), varName, true, nil
+		case int:
+			if v < 0 {
+				return Felt{}, varName, true, errors.Errorf("Invalid value for %s. Got: %d", varName, v)
+			}
+			return FeltFromUint(uint(v)), varName, true, nil
+		default:
+			return Felt{}, varName, true, ConversionError(value, "felt")
+		}
+	}
+	return Felt{}, "", false, nil
+}
+
+func getElmSize(ids IdsManager, vm *VirtualMachine) (Felt, uint, error) {
+	elmSizeFelt, err := ids.GetFelt("elm_size", vm)
 	if err != nil {
-		return err
+		return Felt{}, 0, err
 	}
+	elmSize, err := elmSizeFelt.ToUint()
+	if err != nil || elmSize == 0 {
+		return Felt{}, 0, errors.Errorf("Invalid value for elm_size. Got: %s", elmSizeFelt.ToSignedFeltString())
+	}
+	return elmSizeFelt, elmSize, nil
+}
 
-	key, err := ids.GetFelt("key", vm)
+// Returns n_elms, checking it against __find_element_max_size if it's in scope
+func getNElms(ids IdsManager, vm *VirtualMachine, execScopes *ExecutionScopes) (Felt, uint, error) {
+	nElms, err := ids.GetFelt("n_elms", vm)
 	if err != nil {
-		return err
+		return Felt{}, 0, err
+	}
+	nElmsIter, err := nElms.ToUint()
+	if err != nil {
+		return Felt{}, 0, errors.Errorf("Invalid value for n_elms. Got: %s", nElms.ToSignedFeltString())
+	}
+	findElementMaxSize, _, ok, err := getScopeVarFelt(execScopes, FIND_ELEMENT_MAX_SIZE)
+	if err != nil {
+		return Felt{}, 0, err
 	}
+	if ok && nElms.Cmp(findElementMaxSize) == 1 {
+		return Felt{}, 0, errors.Errorf(
+			"find_element() can only be used with n_elms <= %s.\nGot: n_elms = %s",
+			findElementMaxSize.ToSignedFeltString(),
+			nElms.ToSignedFeltString(),
+		)
+	}
+	return nElms, nElmsIter, nil
+}
 
-	elmSizeFelt, err := ids.GetFelt("elm_size", vm)
+func findElement(ids IdsManager, vm *VirtualMachine, execScopes *ExecutionScopes) error {
+	arrayPtr, err := ids.GetRelocatable("array_ptr", vm)
 	if err != nil {
 		return err
 	}
-	elmSize, err := elmSizeFelt.ToUint()
+
+	elmSizeFelt, elmSize, err := getElmSize(ids, vm)
 	if err != nil {
 		return err
 	}
 
-	nElms, err := ids.GetFelt("n_elms", vm)
+	key, err := ids.GetFelt("key", vm)
 	if err != nil {
 		return err
 	}
-	nElmsIter, err := nElms.ToUint()
+
+	// If the index is already known, only check that it holds the key
+	findElementIndex, indexVarName, ok, err := getScopeVarFelt(execScopes, FIND_ELEMENT_INDEX)
 	if err != nil {
 		return err
 	}
-
-	findElementIndexUncast, err := execScopes.Get("find_element_index")
-	if err == nil {
-		findElementIndex, ok := findElementIndexUncast.(Felt)
-		if !ok {
-			return ConversionError(findElementIndex, "felt")
-		}
+	if ok {
 		position, err := arrayPtr.AddFelt(findElementIndex.Mul(elmSizeFelt))
 		if err != nil {
 			return err
@@ -61,23 +118,14 @@ func findElement(ids IdsManager, vm *VirtualMachine, execScopes ExecutionScopes)
 				foundKey.ToSignedFeltString(),
 			)
 		}
-		execScopes.DeleteVariable("find_element_index")
+		// The index is deleted so that it isn't used by the next calls
+		execScopes.DeleteVariable(indexVarName)
 		return ids.Insert("index", NewMaybeRelocatableFelt(findElementIndex), vm)
 	}
 
-	findElementMaxSizeUncast, err := execScopes.Get("find_element_max_size")
-	if err == nil {
-		findElementMaxSize, ok := findElementMaxSizeUncast.(Felt)
-		if !ok {
-			return ConversionError(findElementMaxSize, "felt")
-		}
-		if nElms.Cmp(findElementMaxSize) == 1 {
-			return errors.Errorf(
-				"find_element() can only be used with n_elms <= %s.\nGot: n_elms = %s",
-				findElementMaxSize.ToSignedFeltString(),
-				nElms.ToSignedFeltString(),
-			)
-		}
+	_, nElmsIter, err := getNElms(ids, vm, execScopes)
+	if err != nil {
+		return err
 	}
 
 	for i := uint(0); i < nElmsIter; i++ {
@@ -90,62 +138,40 @@ func findElement(ids IdsManager, vm *VirtualMachine, execScopes ExecutionScopes)
 		}
 	}
 
-	return errors.Errorf("Key: %v was not found", key)
+	return errors.Errorf("Key: %s was not found", key.ToSignedFeltString())
 }
 
-func searchSortedLower(ids IdsManager, vm *VirtualMachine, execScopes ExecutionScopes) error {
+func searchSortedLower(ids IdsManager, vm *VirtualMachine, execScopes *ExecutionScopes) error {
 	arrayPtr, err := ids.GetRelocatable("array_ptr", vm)
 	if err != nil {
 		return err
 	}
 
-	key, err := ids.GetFelt("key", vm)
+	_, elmSize, err := getElmSize(ids, vm)
 	if err != nil {
 		return err
 	}
 
-	elmSizeFelt, err := ids.GetFelt("elm_size", vm)
-	if err != nil {
-		return err
-	}
-	elmSize, err := elmSizeFelt.ToUint()
+	nElms, nElmsIter, err := getNElms(ids, vm, execScopes)
 	if err != nil {
 		return err
 	}
 
-	nElms, err := ids.GetFelt("n_elms", vm)
-	if err != nil {
-		return err
-	}
-	nElmsIter, err := nElms.ToUint()
+	key, err := ids.GetFelt("key", vm)
 	if err != nil {
 		return err
 	}
 
-	findElementMaxSizeUncast, err := execScopes.Get("find_element_max_size")
-	if err == nil {
-		findElementMaxSize, ok := findElementMaxSizeUncast.(Felt)
-		if !ok {
-			return ConversionError(findElementMaxSize, "felt")
-		}
-		if nElms.Cmp(findElementMaxSize) == 1 {
-			return errors.Errorf(
-				"find_element() can only be used with n_elms <= %s.\nGot: n_elms = %s",
-				findElementMaxSize.ToSignedFeltString(),
-				nElms.ToSignedFeltString(),
-			)
-		}
-	}
-
 	for i := uint(0); i < nElmsIter; i++ {
 		iterKey, err := vm.Segments.Memory.GetFelt(arrayPtr.AddUint(i * elmSize))
 		if err != nil {
 			return err
 		}
-		if iterKey == key || iterKey.Cmp(key) == 1 {
+		if iterKey.Cmp(key) >= 0 {
 			return ids.Insert("index", NewMaybeRelocatableFelt(FeltFromUint(i)), vm)
 		}
 	}
 
-	return errors.Errorf("Key: %v was not found", key)
+	// Every element is lower than the key
+	return ids.Insert("index", NewMaybeRelocatableFelt(nElms), vm)
 }
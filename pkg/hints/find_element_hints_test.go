@@ -285,3 +285,128 @@ func TestSearchSortedLowerFindElementMaxSizeLessThanNeeded(t *testing.T) {
 		t.Errorf("FIND_ELEMENT hint expected to fail with find_element_max_size < n_elms")
 	}
 }
+
+func TestFindElementWithCairoLangFindElementIndex(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.Memory.Insert(NewRelocatable(1, 0), NewMaybeRelocatableFelt(FeltFromUint64(1)))
+	vm.Segments.Memory.Insert(NewRelocatable(1, 2), NewMaybeRelocatableFelt(FeltFromUint64(3)))
+	// n_elms isn't needed when the index is known
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"array_ptr": {NewMaybeRelocatableRelocatable(NewRelocatable(1, 0))},
+			"elm_size":  {NewMaybeRelocatableFelt(FeltFromUint64(2))},
+			"key":       {NewMaybeRelocatableFelt(FeltFromUint64(3))},
+			"index":     {nil},
+		},
+		vm,
+	)
+
+	execScopes := NewExecutionScopes()
+	execScopes.EnterScope(map[string]interface{}{FIND_ELEMENT_INDEX: uint64(1)})
+
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: FIND_ELEMENT,
+	})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, execScopes)
+	if err != nil {
+		t.Fatalf("FIND_ELEMENT hint test failed with error: %s", err)
+	}
+	index, err := idsManager.GetFelt("index", vm)
+	if err != nil || index != FeltOne() {
+		t.Errorf("Index was expected to be 1, got %s, %v", index.ToSignedFeltString(), err)
+	}
+	if _, err := execScopes.Get(FIND_ELEMENT_INDEX); err == nil {
+		t.Error("__find_element_index should be deleted after its use")
+	}
+}
+
+func TestFindElementCairoLangFindElementMaxSizeLessThanNeeded(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"array_ptr": {NewMaybeRelocatableRelocatable(NewRelocatable(1, 0))},
+			"elm_size":  {NewMaybeRelocatableFelt(FeltFromUint64(2))},
+			"n_elms":    {NewMaybeRelocatableFelt(FeltFromUint64(2))},
+			"key":       {NewMaybeRelocatableFelt(FeltFromUint64(3))},
+			"index":     {nil},
+		},
+		vm,
+	)
+
+	execScopes := NewExecutionScopes()
+	execScopes.EnterScope(map[string]interface{}{FIND_ELEMENT_MAX_SIZE: 1})
+
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: FIND_ELEMENT,
+	})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, execScopes)
+	if err == nil {
+		t.Errorf("FIND_ELEMENT hint expected to fail with __find_element_max_size < n_elms")
+	}
+}
+
+func TestFindElementInvalidElmSize(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"array_ptr": {NewMaybeRelocatableRelocatable(NewRelocatable(1, 0))},
+			"elm_size":  {NewMaybeRelocatableFelt(FeltZero())},
+			"n_elms":    {NewMaybeRelocatableFelt(FeltFromUint64(2))},
+			"key":       {NewMaybeRelocatableFelt(FeltFromUint64(3))},
+			"index":     {nil},
+		},
+		vm,
+	)
+
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: FIND_ELEMENT,
+	})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, NewExecutionScopes())
+	if err == nil {
+		t.Errorf("FIND_ELEMENT hint expected to fail with elm_size = 0")
+	}
+}
+
+func TestSearchSortedLowerAllElementsLower(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.Memory.Insert(NewRelocatable(1, 0), NewMaybeRelocatableFelt(FeltFromUint64(1)))
+	vm.Segments.Memory.Insert(NewRelocatable(1, 1), NewMaybeRelocatableFelt(FeltFromUint64(2)))
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"array_ptr": {NewMaybeRelocatableRelocatable(NewRelocatable(1, 0))},
+			"elm_size":  {NewMaybeRelocatableFelt(FeltFromUint64(1))},
+			"n_elms":    {NewMaybeRelocatableFelt(FeltFromUint64(2))},
+			"key":       {NewMaybeRelocatableFelt(FeltFromUint64(5))},
+			"index":     {nil},
+		},
+		vm,
+	)
+
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: SEARCH_SORTED_LOWER,
+	})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, NewExecutionScopes())
+	if err != nil {
+		t.Fatalf("SEARCH_SORTED_LOWER hint test failed with error: %s", err)
+	}
+	index, err := idsManager.GetFelt("index", vm)
+	if err != nil || index != FeltFromUint64(2) {
+		t.Errorf("Index was expected to be n_elms (2), got %s, %v", index.ToSignedFeltString(), err)
+	}
+}
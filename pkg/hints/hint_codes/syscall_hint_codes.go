@@ -0,0 +1,29 @@
+package hint_codes
+
+// Syscalls of Cairo 0 contracts (starkware/starknet/common/syscalls.cairo)
+
+const CALL_CONTRACT = "syscall_handler.call_contract(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const LIBRARY_CALL = "syscall_handler.library_call(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const LIBRARY_CALL_L1_HANDLER = "syscall_handler.library_call_l1_handler(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const DELEGATE_CALL = "syscall_handler.delegate_call(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const DELEGATE_L1_HANDLER = "syscall_handler.delegate_l1_handler(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const DEPLOY = "syscall_handler.deploy(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const EMIT_EVENT = "syscall_handler.emit_event(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const GET_BLOCK_NUMBER = "syscall_handler.get_block_number(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const GET_BLOCK_TIMESTAMP = "syscall_handler.get_block_timestamp(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const GET_CALLER_ADDRESS = "syscall_handler.get_caller_address(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const GET_CONTRACT_ADDRESS = "syscall_handler.get_contract_address(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const GET_SEQUENCER_ADDRESS = "syscall_handler.get_sequencer_address(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const GET_TX_INFO = "syscall_handler.get_tx_info(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const GET_TX_SIGNATURE = "syscall_handler.get_tx_signature(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const REPLACE_CLASS = "syscall_handler.replace_class(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const SEND_MESSAGE_TO_L1 = "syscall_handler.send_message_to_l1(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const STORAGE_READ = "syscall_handler.storage_read(segments=segments, syscall_ptr=ids.syscall_ptr)"
+const STORAGE_WRITE = "syscall_handler.storage_write(segments=segments, syscall_ptr=ids.syscall_ptr)"
+
+// Hints of the Starknet OS executing the syscalls of Cairo 0 contracts (starkware/starknet/core/os)
+
+const OS_STORAGE_READ = "# Make sure the value is cached (by reading it), to be used later on for the\n# commitment computation.\nvalue = execution_helper.storage_by_address[ids.contract_address].read(\n    key=ids.syscall_ptr.request.address\n)\nassert ids.value == value, \"Inconsistent storage value.\""
+const OS_STORAGE_WRITE = "storage = execution_helper.storage_by_address[ids.contract_address]\nids.prev_value = storage.read(key=ids.syscall_ptr.address)\nstorage.write(key=ids.syscall_ptr.address, value=ids.syscall_ptr.value)\n\n# Fetch a state_entry in this hint and validate it in the update below.\nids.state_entry = __dict_manager.get_dict(ids.contract_state_changes)[ids.contract_address]"
+const OS_DEPLOY_GET_STATE_ENTRY = "# Fetch a state_entry in this hint and validate it in the update at the end\n# of this function.\nids.state_entry = __dict_manager.get_dict(ids.contract_state_changes)[ids.contract_address]"
+const OS_EXECUTE_SYSCALLS_END = "syscall_handler.validate_and_discard_syscall_ptr(\n    syscall_ptr_end=ids.entry_point_return_values.syscall_ptr\n)\nexecution_helper.exit_call()"
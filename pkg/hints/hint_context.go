@@ -0,0 +1,147 @@
+package hints
+
+import (
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/types"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Hint implemented outside of this package, see CairoVmHintProcessor.CustomHints
+type CustomHint func(ctx *HintContext) error
+
+// State of the run a custom hint can access, so that hints implemented outside of this package only depend on it
+// instead of on the internals of the vm, which keep changing
+// A HintContext is only valid during the execution of the hint it was passed to
+type HintContext struct {
+	code       string
+	ids        IdsManager
+	vm         *vm.VirtualMachine
+	constants  *map[string]Felt
+	execScopes *types.ExecutionScopes
+}
+
+func newHintContext(data HintData, virtualMachine *vm.VirtualMachine, constants *map[string]Felt, execScopes *types.ExecutionScopes) *HintContext {
+	return &HintContext{code: data.Code, ids: data.Ids, vm: virtualMachine, constants: constants, execScopes: execScopes}
+}
+
+// Code of the hint being executed
+func (c *HintContext) Code() string {
+	return c.code
+}
+
+func (c *HintContext) Pc() memory.Relocatable {
+	return c.vm.RunContext.Pc
+}
+
+func (c *HintContext) Ap() memory.Relocatable {
+	return c.vm.RunContext.Ap
+}
+
+func (c *HintContext) Fp() memory.Relocatable {
+	return c.vm.RunContext.Fp
+}
+
+// Returns the value of the felt variable ids.name
+func (c *HintContext) IdsFelt(name string) (Felt, error) {
+	return c.ids.GetFelt(name, c.vm)
+}
+
+// Returns the value of the pointer variable ids.name
+func (c *HintContext) IdsRelocatable(name string) (memory.Relocatable, error) {
+	return c.ids.GetRelocatable(name, c.vm)
+}
+
+// Returns the address of the variable ids.name (ie: the address of a struct)
+func (c *HintContext) IdsAddr(name string) (memory.Relocatable, error) {
+	return c.ids.GetAddr(name, c.vm)
+}
+
+// Returns the value of the member of the struct variable ids.name at the given offset
+func (c *HintContext) IdsStructFieldFelt(name string, offset uint) (Felt, error) {
+	return c.ids.GetStructFieldFelt(name, offset, c.vm)
+}
+
+// Assigns value to the variable ids.name
+func (c *HintContext) SetIds(name string, value memory.MaybeRelocatable) error {
+	return c.ids.Insert(name, &value, c.vm)
+}
+
+// Assigns value to the member of the struct variable ids.name at the given offset
+func (c *HintContext) SetIdsStructField(name string, offset uint, value memory.MaybeRelocatable) error {
+	return c.ids.InsertStructField(name, offset, &value, c.vm)
+}
+
+// Returns the value of the constant name, searched from the innermost accessible scope of the hint outwards
+func (c *HintContext) Constant(name string) (Felt, error) {
+	return c.ids.GetConst(name, c.constants)
+}
+
+// Returns the value stored at addr, failing if the cell is empty
+func (c *HintContext) Read(addr memory.Relocatable) (memory.MaybeRelocatable, error) {
+	value, err := c.vm.Segments.Memory.Get(addr)
+	if err != nil {
+		return memory.MaybeRelocatable{}, err
+	}
+	return *value, nil
+}
+
+// Returns the felt stored at addr, failing if the cell is empty or holds a relocatable value
+func (c *HintContext) ReadFelt(addr memory.Relocatable) (Felt, error) {
+	return c.vm.Segments.Memory.GetFelt(addr)
+}
+
+// Returns the size values stored from addr onwards, failing if a cell is empty
+func (c *HintContext) ReadRange(addr memory.Relocatable, size uint) ([]memory.MaybeRelocatable, error) {
+	return c.vm.Segments.Memory.GetRange(addr, size)
+}
+
+// Returns the size felts stored from addr onwards, failing if a cell is empty or holds a relocatable value
+func (c *HintContext) ReadFelts(addr memory.Relocatable, size uint) ([]Felt, error) {
+	return c.vm.Segments.Memory.GetFeltRange(addr, size)
+}
+
+// Writes value at addr, failing if the cell already holds a different value
+func (c *HintContext) Write(addr memory.Relocatable, value memory.MaybeRelocatable) error {
+	return c.vm.Segments.Memory.Insert(addr, &value)
+}
+
+// Writes values from addr onwards, returning the address following the last value written
+func (c *HintContext) WriteRange(addr memory.Relocatable, values []memory.MaybeRelocatable) (memory.Relocatable, error) {
+	return c.vm.Segments.LoadData(addr, &values)
+}
+
+// Writes felts from addr onwards, returning the address following the last felt written
+func (c *HintContext) WriteFelts(addr memory.Relocatable, felts []Felt) (memory.Relocatable, error) {
+	values := make([]memory.MaybeRelocatable, 0, len(felts))
+	for _, felt := range felts {
+		values = append(values, *memory.NewMaybeRelocatableFelt(felt))
+	}
+	return c.WriteRange(addr, values)
+}
+
+// Adds a new memory segment, returning its base
+func (c *HintContext) AddSegment() memory.Relocatable {
+	return c.vm.Segments.AddSegment()
+}
+
+// Returns the value of the variable name of the current execution scope
+func (c *HintContext) ScopeVar(name string) (any, error) {
+	return c.execScopes.Get(name)
+}
+
+// Assigns value to the variable name of the current execution scope
+func (c *HintContext) SetScopeVar(name string, value any) {
+	c.execScopes.AssignOrUpdateVariable(name, value)
+}
+
+// Enters a new execution scope with the given variables (ie: vm_enter_scope)
+func (c *HintContext) EnterScope(variables map[string]any) {
+	c.execScopes.EnterScope(variables)
+}
+
+// Exits the current execution scope (ie: vm_exit_scope), failing if it is the main scope
+func (c *HintContext) ExitScope() error {
+	return c.execScopes.ExitScope()
+}
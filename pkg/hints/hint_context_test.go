@@ -0,0 +1,93 @@
+package hints_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Writes ids.a * SCALE & ids.a + 1 to a new segment, storing its base in ids.res & the scope variable res
+func scaleHint(ctx *HintContext) error {
+	a, err := ctx.IdsFelt("a")
+	if err != nil {
+		return err
+	}
+	scale, err := ctx.Constant("SCALE")
+	if err != nil {
+		return err
+	}
+	base := ctx.AddSegment()
+	end, err := ctx.WriteFelts(base, []Felt{a.Mul(scale), a.Add(FeltOne())})
+	if err != nil {
+		return err
+	}
+	if end != base.AddUint(2) {
+		return errors.New("wrong end of the written range")
+	}
+	ctx.SetScopeVar("res", base)
+	return ctx.SetIds("res", *NewMaybeRelocatableRelocatable(base))
+}
+
+func TestCustomHint(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(map[string][]*MaybeRelocatable{
+		"a":   {NewMaybeRelocatableFelt(FeltFromUint64(7))},
+		"res": {nil},
+	}, vm)
+	constants := SetupConstantsForTest(map[string]Felt{"SCALE": FeltFromUint64(3)}, &idsManager)
+	scopes := NewExecutionScopes()
+	hintProcessor := CairoVmHintProcessor{CustomHints: map[string]CustomHint{"scale()": scaleHint}}
+	hintData := any(HintData{Ids: idsManager, Code: "scale()"})
+
+	if err := hintProcessor.ExecuteHint(vm, &hintData, &constants, scopes); err != nil {
+		t.Fatalf("Custom hint failed with error: %s", err)
+	}
+	res, err := idsManager.GetRelocatable("res", vm)
+	if err != nil {
+		t.Fatalf("ids.res should have been set: %s", err)
+	}
+	values, err := vm.Segments.Memory.GetFeltRange(res, 2)
+	if err != nil || values[0] != FeltFromUint64(21) || values[1] != FeltFromUint64(8) {
+		t.Errorf("Wrong values written by the hint: %v, %v", values, err)
+	}
+	CheckScopeVar[Relocatable]("res", res, scopes, t)
+}
+
+func TestCustomHintErrorsAreHintErrors(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	hintProcessor := CairoVmHintProcessor{CustomHints: map[string]CustomHint{"scale()": scaleHint}}
+	// ids.a is not defined
+	hintData := any(HintData{Ids: SetupIdsForTest(map[string][]*MaybeRelocatable{}, vm), Code: "scale()"})
+	constants := map[string]Felt{}
+	if err := hintProcessor.ExecuteHint(vm, &hintData, &constants, NewExecutionScopes()); !errors.Is(err, ErrHint) {
+		t.Errorf("Expected a hint error, got: %v", err)
+	}
+}
+
+func TestCustomHintReplacesBuiltinHint(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	executed := false
+	hintProcessor := CairoVmHintProcessor{CustomHints: map[string]CustomHint{
+		ADD_SEGMENT: func(ctx *HintContext) error {
+			executed = ctx.Code() == ADD_SEGMENT && ctx.Ap() == vm.RunContext.Ap
+			return nil
+		},
+	}}
+	hintData := any(HintData{Code: ADD_SEGMENT})
+	if err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil); err != nil {
+		t.Fatalf("Custom hint failed with error: %s", err)
+	}
+	if !executed || vm.Segments.Memory.NumSegments() != 1 {
+		t.Errorf("The custom hint should have replaced ADD_SEGMENT")
+	}
+}
@@ -0,0 +1,70 @@
+package hints
+
+import (
+	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
+	"github.com/pkg/errors"
+)
+
+// Scope variables holding the maximum input size of the hints that check one, as in cairo-lang
+// The names without the leading underscores are also accepted, for compatibility with older versions of the vm
+const (
+	KECCAK_MAX_SIZE      = "__keccak_max_size"
+	USORT_MAX_SIZE       = "__usort_max_size"
+	SQUASH_DICT_MAX_SIZE = "__squash_dict_max_size"
+)
+
+// Limits on the input size of the hints that support them, which bound the memory & time the hints of untrusted
+// programs can use. Zero values are not enforced
+// The limits are scope variables of the main scope (see ScopeVariables), which the hints running in it read like
+// cairo-lang's globals. usort forwards its limit to the scope it enters
+type HintLimits struct {
+	// Max length (in bytes) of the input of unsafe_keccak
+	KeccakMaxSize uint64
+	// Max input_len of usort
+	UsortMaxSize uint64
+	// Max n_elms of find_element & search_sorted_lower
+	FindElementMaxSize uint64
+	// Max n_accesses of squash_dict
+	SquashDictMaxSize uint64
+}
+
+// Returns the scope variables enforcing the limits that are set
+func (l HintLimits) ScopeVariables() map[string]any {
+	variables := make(map[string]any)
+	for name, limit := range map[string]uint64{
+		KECCAK_MAX_SIZE:       l.KeccakMaxSize,
+		USORT_MAX_SIZE:        l.UsortMaxSize,
+		FIND_ELEMENT_MAX_SIZE: l.FindElementMaxSize,
+		SQUASH_DICT_MAX_SIZE:  l.SquashDictMaxSize,
+	} {
+		if limit != 0 {
+			variables[name] = limit
+		}
+	}
+	return variables
+}
+
+// Returns the limit held by the scope variable name (or by its legacy name), false if there is none
+func getMaxSize(scopes *ExecutionScopes, name string) (uint64, bool, error) {
+	value, varName, ok, err := getScopeVarFelt(scopes, name)
+	if err != nil || !ok {
+		return 0, ok, err
+	}
+	maxSize, err := value.ToU64()
+	if err != nil {
+		return 0, true, errors.Errorf("Invalid value for %s. Got: %s", varName, value.ToSignedFeltString())
+	}
+	return maxSize, true, nil
+}
+
+// Fails if size exceeds the limit held by the scope variable name, the message is formatted with the limit & size
+func checkMaxSize(scopes *ExecutionScopes, name string, size uint64, format string) error {
+	maxSize, ok, err := getMaxSize(scopes, name)
+	if err != nil {
+		return err
+	}
+	if ok && size > maxSize {
+		return errors.Errorf(format, maxSize, size)
+	}
+	return nil
+}
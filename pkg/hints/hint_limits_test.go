@@ -0,0 +1,60 @@
+package hints_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestHintLimitsScopeVariables(t *testing.T) {
+	limits := HintLimits{KeccakMaxSize: 10, SquashDictMaxSize: 3}
+	expected := map[string]any{KECCAK_MAX_SIZE: uint64(10), SQUASH_DICT_MAX_SIZE: uint64(3)}
+	if variables := limits.ScopeVariables(); !reflect.DeepEqual(variables, expected) {
+		t.Errorf("Wrong scope variables. Expected %v, got %v", expected, variables)
+	}
+}
+
+func TestHintLimitsEnforced(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	dataPtr := vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"length": {NewMaybeRelocatableFelt(FeltFromUint64(17))},
+			"data":   {NewMaybeRelocatableRelocatable(dataPtr)},
+			"high":   {nil},
+			"low":    {nil},
+		},
+		vm,
+	)
+	scopes := NewExecutionScopes()
+	for name, value := range (HintLimits{KeccakMaxSize: 16}).ScopeVariables() {
+		scopes.AssignOrUpdateVariable(name, value)
+	}
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{Ids: idsManager, Code: UNSAFE_KECCAK})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, scopes)
+	if err == nil || !strings.Contains(err.Error(), "unsafe_keccak() can only be used with length<=16. Got: length=17") {
+		t.Errorf("Expected the keccak limit to be enforced, got: %v", err)
+	}
+}
+
+func TestHintLimitsInvalidValue(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	scopes := NewExecutionScopes()
+	scopes.AssignOrUpdateVariable(USORT_MAX_SIZE, "10")
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{Ids: SetupIdsForTest(map[string][]*MaybeRelocatable{}, vm), Code: USORT_ENTER_SCOPE})
+	if err := hintProcessor.ExecuteHint(vm, &hintData, nil, scopes); err == nil {
+		t.Errorf("A limit that is not a number should be rejected")
+	}
+}
@@ -1,60 +1,107 @@
 package hints
 
 import (
-	"strings"
+	"fmt"
+	"time"
 
 	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
 	"github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
 	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
 	"github.com/lambdaclass/cairo-vm.go/pkg/types"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 	"github.com/pkg/errors"
 )
 
+// Error category of the errors raised while executing hints, which can be matched through errors.Is
+var ErrHint = errors.New("Hint error")
+
+func HintError(err error) error {
+	return &memory.CategoryError{Category: ErrHint, Err: err}
+}
+
+var ErrWrongHintData = HintError(errors.New("Wrong hint data"))
+var ErrUnknownHint = errors.New("Unknown Hint")
+
 type HintData struct {
 	Ids  IdsManager
 	Code string
 }
 
 type CairoVmHintProcessor struct {
+	// Executes the syscalls requested through the syscall hints, NoopSyscallHandler is used if it's nil
+	// It has to implement OsSyscallHandler to run the Starknet OS
+	SyscallHandler SyscallHandler
+	// Sets DictManager.MemoryFallback on the dict managers created by the dict hints
+	DictMemoryFallback bool
+	// Collects the execution statistics of the hints if set
+	Stats *HintStats
+	// If set, unknown hints are recorded in it & skipped instead of failing with ErrUnknownHint
+	// The results of a run that skipped hints are not valid, as the skipped hints had no effect
+	UnknownHints *UnknownHintReport
+	// Hints implemented outside of this package, by hint code
+	// They take precedence over the hints implemented by the processor, so they can be used to replace them
+	CustomHints map[string]CustomHint
 }
 
 func (p *CairoVmHintProcessor) CompileHint(hintParams *parser.HintParams, referenceManager *parser.ReferenceManager) (any, error) {
-	references := make(map[string]HintReference, 0)
-	for name, n := range hintParams.FlowTrackingData.ReferenceIds {
-		if int(n) >= len(referenceManager.References) {
-			return nil, errors.New("Reference not found in ReferenceManager")
-		}
-		split := strings.Split(name, ".")
-		name = split[len(split)-1]
-		references[name] = ParseHintReference(referenceManager.References[n])
+	references, err := ResolveHintReferences(hintParams.FlowTrackingData.ReferenceIds, referenceManager.References, hintParams.AccessibleScopes)
+	if err != nil {
+		return nil, err
 	}
 	ids := NewIdsManager(references, hintParams.FlowTrackingData.APTracking, hintParams.AccessibleScopes)
 	return HintData{Ids: ids, Code: hintParams.Code}, nil
 }
 
+// Executes the hint, errors raised by it belong to the ErrHint category
 func (p *CairoVmHintProcessor) ExecuteHint(vm *vm.VirtualMachine, hintData *any, constants *map[string]Felt, execScopes *types.ExecutionScopes) error {
 	data, ok := (*hintData).(HintData)
 	if !ok {
-		return errors.New("Wrong Hint Data")
+		return ErrWrongHintData
+	}
+	if logger := logging.Default(); logger.Enabled(logging.LevelDebug) {
+		logger.Debug("Executing hint", logging.F("pc", vm.RunContext.Pc.ToString()), logging.F("code", data.Code))
+	}
+	var err error
+	if p.Stats != nil {
+		start := time.Now()
+		err = p.executeHint(data, vm, constants, execScopes)
+		p.Stats.Record(data.Code, time.Since(start), err)
+	} else {
+		err = p.executeHint(data, vm, constants, execScopes)
+	}
+	if p.UnknownHints != nil && errors.Is(err, ErrUnknownHint) {
+		p.UnknownHints.Record(data.Code, vm.RunContext.Pc)
+		return nil
+	}
+	if err != nil {
+		return HintError(err)
+	}
+	return nil
+}
+
+func (p *CairoVmHintProcessor) executeHint(data HintData, vm *vm.VirtualMachine, constants *map[string]Felt, execScopes *types.ExecutionScopes) error {
+	if customHint, ok := p.CustomHints[data.Code]; ok {
+		return customHint(newHintContext(data, vm, constants, execScopes))
 	}
 	switch data.Code {
 	case ADD_SEGMENT:
 		return add_segment(vm)
 	case ASSERT_NN:
-		return assert_nn(data.Ids, vm)
+		return assert_nn(data.Ids, vm, constants)
 	case VERIFY_ECDSA_SIGNATURE:
 		return verify_ecdsa_signature(data.Ids, vm)
 	case IS_POSITIVE:
-		return is_positive(data.Ids, vm)
+		return is_positive(data.Ids, vm, constants)
 	case ASSERT_NOT_ZERO:
 		return assert_not_zero(data.Ids, vm)
 	case IS_QUAD_RESIDUE:
 		return is_quad_residue(data.Ids, vm)
 	case DEFAULT_DICT_NEW:
-		return defaultDictNew(data.Ids, execScopes, vm)
+		return defaultDictNew(data.Ids, execScopes, vm, p.DictMemoryFallback)
 	case DICT_READ:
 		return dictRead(data.Ids, execScopes, vm)
 	case DICT_WRITE:
@@ -84,7 +131,7 @@ func (p *CairoVmHintProcessor) ExecuteHint(vm *vm.VirtualMachine, hintData *any,
 	case DICT_SQUASH_UPDATE_PTR:
 		return dictSquashUpdatePtr(data.Ids, execScopes, vm)
 	case DICT_NEW:
-		return dictNew(data.Ids, execScopes, vm)
+		return dictNew(data.Ids, execScopes, vm, p.DictMemoryFallback)
 	case VM_EXIT_SCOPE:
 		return vm_exit_scope(execScopes)
 	case ASSERT_NOT_EQUAL:
@@ -122,9 +169,9 @@ func (p *CairoVmHintProcessor) ExecuteHint(vm *vm.VirtualMachine, hintData *any,
 	case SET_ADD:
 		return setAdd(data.Ids, vm)
 	case FIND_ELEMENT:
-		return findElement(data.Ids, vm, *execScopes)
+		return findElement(data.Ids, vm, execScopes)
 	case SEARCH_SORTED_LOWER:
-		return searchSortedLower(data.Ids, vm, *execScopes)
+		return searchSortedLower(data.Ids, vm, execScopes)
 	case COMPUTE_SLOPE_V1:
 		return computeSlopeAndAssingSecpP(vm, *execScopes, data.Ids, "point0", "point1", SECP_P())
 	case COMPUTE_SLOPE_V2:
@@ -157,6 +204,10 @@ func (p *CairoVmHintProcessor) ExecuteHint(vm *vm.VirtualMachine, hintData *any,
 		return signedDivRem(data.Ids, vm)
 	case ASSERT_LE_FELT:
 		return assertLeFelt(data.Ids, vm, execScopes, constants)
+	case ASSERT_LE_FELT_V_0_6:
+		return assertLeFeltV06(data.Ids, vm)
+	case ASSERT_LE_FELT_V_0_8:
+		return assertLeFeltV08(data.Ids, vm, constants)
 	case ASSERT_LE_FELT_EXCLUDED_0:
 		return assertLeFeltExcluded0(vm, execScopes)
 	case ASSERT_LE_FELT_EXCLUDED_1:
@@ -166,9 +217,9 @@ func (p *CairoVmHintProcessor) ExecuteHint(vm *vm.VirtualMachine, hintData *any,
 	case ASSERT_LT_FELT:
 		return assertLtFelt(data.Ids, vm)
 	case IS_NN:
-		return isNN(data.Ids, vm)
+		return isNN(data.Ids, vm, constants)
 	case IS_NN_OUT_OF_RANGE:
-		return isNNOutOfRange(data.Ids, vm)
+		return isNNOutOfRange(data.Ids, vm, constants)
 	case IS_LE_FELT:
 		return isLeFelt(data.Ids, vm)
 	case ASSERT_250_BITS:
@@ -255,7 +306,19 @@ func (p *CairoVmHintProcessor) ExecuteHint(vm *vm.VirtualMachine, hintData *any,
 		return sha256Input(data.Ids, vm)
 	case EXAMPLE_BLAKE2S_COMPRESS:
 		return exampleBlake2sCompress(data.Ids, vm)
+	case CALL_CONTRACT, LIBRARY_CALL, LIBRARY_CALL_L1_HANDLER, DELEGATE_CALL, DELEGATE_L1_HANDLER, DEPLOY, EMIT_EVENT,
+		GET_BLOCK_NUMBER, GET_BLOCK_TIMESTAMP, GET_CALLER_ADDRESS, GET_CONTRACT_ADDRESS, GET_SEQUENCER_ADDRESS, GET_TX_INFO,
+		GET_TX_SIGNATURE, REPLACE_CLASS, SEND_MESSAGE_TO_L1, STORAGE_READ, STORAGE_WRITE:
+		return executeDeprecatedSyscall(data.Ids, vm, data.Code, p.SyscallHandler)
+	case OS_STORAGE_READ:
+		return osStorageRead(data.Ids, vm, p.SyscallHandler)
+	case OS_STORAGE_WRITE:
+		return osStorageWrite(data.Ids, execScopes, vm, p.SyscallHandler)
+	case OS_DEPLOY_GET_STATE_ENTRY:
+		return getContractStateEntry(data.Ids, execScopes, vm)
+	case OS_EXECUTE_SYSCALLS_END:
+		return osExecuteSyscallsEnd(data.Ids, vm, p.SyscallHandler)
 	default:
-		return errors.Errorf("Unknown Hint: %s", data.Code)
+		return fmt.Errorf("%w: %s", ErrUnknownHint, data.Code)
 	}
 }
@@ -1,6 +1,7 @@
 package hints_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -102,7 +103,10 @@ func TestExecuteHintUnknownHint(t *testing.T) {
 	hintData := any(HintData{Code: "print(Hello World)"})
 	vm := vm.NewVirtualMachine()
 	err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
-	if err == nil {
-		t.Errorf("Should have failed")
+	if !errors.Is(err, ErrUnknownHint) || !errors.Is(err, ErrHint) {
+		t.Errorf("Should have failed with ErrUnknownHint, got: %v", err)
+	}
+	if err.Error() != "Unknown Hint: print(Hello World)" {
+		t.Errorf("Wrong error message: %s", err)
 	}
 }
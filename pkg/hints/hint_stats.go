@@ -0,0 +1,77 @@
+package hints
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Execution statistics of a hint code
+type HintStat struct {
+	Code  string
+	Count uint
+	// Cumulative time spent executing the hint
+	Duration time.Duration
+	// Set if the code isn't implemented by the hint processor, its executions fail with ErrUnknownHint
+	Unknown bool
+}
+
+// Collects the number of executions & the cumulative execution time of each hint code, see CairoVmHintProcessor.Stats
+// It can be shared by concurrent runs
+type HintStats struct {
+	mutex sync.Mutex
+	stats map[string]*HintStat
+}
+
+func NewHintStats() *HintStats {
+	return &HintStats{stats: make(map[string]*HintStat)}
+}
+
+// Records an execution of the hint with the given code, which failed with err if it's not nil
+func (s *HintStats) Record(code string, duration time.Duration, err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	stat, ok := s.stats[code]
+	if !ok {
+		stat = &HintStat{Code: code}
+		s.stats[code] = stat
+	}
+	stat.Count++
+	stat.Duration += duration
+	if errors.Is(err, ErrUnknownHint) {
+		stat.Unknown = true
+	}
+}
+
+// Returns the statistics of each hint code, sorted by decreasing cumulative time
+func (s *HintStats) Stats() []HintStat {
+	s.mutex.Lock()
+	stats := make([]HintStat, 0, len(s.stats))
+	for _, stat := range s.stats {
+		stats = append(stats, *stat)
+	}
+	s.mutex.Unlock()
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Duration != stats[j].Duration {
+			return stats[i].Duration > stats[j].Duration
+		}
+		return stats[i].Code < stats[j].Code
+	})
+	return stats
+}
+
+// Returns the codes of the unknown hints that were executed, sorted
+func (s *HintStats) UnknownHints() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var codes []string
+	for code, stat := range s.stats {
+		if stat.Unknown {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
@@ -0,0 +1,64 @@
+package hints_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestHintProcessorCollectsStats(t *testing.T) {
+	vm := NewVirtualMachine()
+	stats := NewHintStats()
+	hintProcessor := CairoVmHintProcessor{Stats: stats}
+	addSegment := any(HintData{Code: ADD_SEGMENT})
+	unknown := any(HintData{Code: "print('hello')"})
+
+	vm.Segments.AddSegment()
+	for i := uint(0); i < 2; i++ {
+		vm.RunContext.Ap = NewRelocatable(0, i)
+		if err := hintProcessor.ExecuteHint(vm, &addSegment, nil, nil); err != nil {
+			t.Fatalf("ADD_SEGMENT failed with error: %s", err)
+		}
+	}
+	if err := hintProcessor.ExecuteHint(vm, &unknown, nil, nil); !errors.Is(err, ErrUnknownHint) {
+		t.Fatalf("Expected ErrUnknownHint, got: %v", err)
+	}
+
+	counts := make(map[string]uint)
+	for _, stat := range stats.Stats() {
+		counts[stat.Code] = stat.Count
+		if stat.Unknown != (stat.Code == "print('hello')") {
+			t.Errorf("Wrong unknown flag for %s", stat.Code)
+		}
+	}
+	if !reflect.DeepEqual(counts, map[string]uint{ADD_SEGMENT: 2, "print('hello')": 1}) {
+		t.Errorf("Wrong execution counts: %v", counts)
+	}
+	if unknownHints := stats.UnknownHints(); !reflect.DeepEqual(unknownHints, []string{"print('hello')"}) {
+		t.Errorf("Wrong unknown hints: %v", unknownHints)
+	}
+}
+
+func TestHintStatsSortedByTime(t *testing.T) {
+	stats := NewHintStats()
+	stats.Record("fast", time.Millisecond, nil)
+	stats.Record("slow", 2*time.Millisecond, nil)
+	stats.Record("fast", 2*time.Millisecond, nil)
+
+	expected := []HintStat{
+		{Code: "fast", Count: 2, Duration: 3 * time.Millisecond},
+		{Code: "slow", Count: 1, Duration: 2 * time.Millisecond},
+	}
+	if got := stats.Stats(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Wrong stats, expected %v, got %v", expected, got)
+	}
+	if unknownHints := stats.UnknownHints(); len(unknownHints) != 0 {
+		t.Errorf("Expected no unknown hints, got %v", unknownHints)
+	}
+}
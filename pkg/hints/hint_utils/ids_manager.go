@@ -1,6 +1,8 @@
 package hint_utils
 
 import (
+	"strings"
+
 	"github.com/pkg/errors"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
@@ -25,10 +27,29 @@ func ErrUnknownIdentifier(name string) error {
 	return ErrIdsManager(errors.Errorf("Unknown identifier %s", name))
 }
 
+func ErrInvalidApTrackingGroup(name string, referenceGroup int, hintGroup int) error {
+	return ErrIdsManager(errors.Errorf("Identifier %s is ap-based and its ap tracking group (%d) differs from the hint's (%d)", name, referenceGroup, hintGroup))
+}
+
 func ErrIdentifierNotFelt(name string) error {
 	return ErrIdsManager(errors.Errorf("Identifier %s is not a Felt", name))
 }
 
+var ErrImmediateIdentifier = errors.New("Cannot write to an immediate identifier")
+
+func ErrInsertImmediate(name string) error {
+	return ErrIdsManager(errors.Wrapf(ErrImmediateIdentifier, "Identifier %s", name))
+}
+
+// Wraps the error of writing to an identifier's address, which is memory.ErrInconsistentMemory if the cell was already
+// set to a different value
+func ErrInsertIdentifier(name string, err error) error {
+	if errors.Is(err, ErrInconsistentMemory) {
+		return ErrIdsManager(errors.Wrapf(err, "Identifier %s is already set to a different value", name))
+	}
+	return ErrIdsManager(errors.Wrapf(err, "Failed to write identifier %s", name))
+}
+
 func NewIdsManager(references map[string]HintReference, hintApTracking parser.ApTrackingData, accessibleScopes []string) IdsManager {
 	return IdsManager{
 		References:       references,
@@ -37,6 +58,55 @@ func NewIdsManager(references map[string]HintReference, hintApTracking parser.Ap
 	}
 }
 
+// Builds the references available to a hint from its reference ids, keying them by their name without the scope
+// When several references share a name, the one defined in the innermost accessible scope is used (matching
+// cairo-lang's name resolution), references from other scopes are only used if no accessible scope defines the name
+func ResolveHintReferences(referenceIds map[string]uint, references []parser.Reference, accessibleScopes []string) (map[string]HintReference, error) {
+	resolved := make(map[string]HintReference, len(referenceIds))
+	resolvedFrom := make(map[string]string, len(referenceIds))
+	for fullName, n := range referenceIds {
+		if int(n) >= len(references) {
+			return nil, ErrIdsManager(errors.Errorf("Reference %s not found in ReferenceManager", fullName))
+		}
+		scope, name := splitScope(fullName)
+		if previous, ok := resolvedFrom[name]; ok && !shadows(fullName, scope, previous, accessibleScopes) {
+			continue
+		}
+		resolved[name] = ParseHintReference(references[n])
+		resolvedFrom[name] = fullName
+	}
+	return resolved, nil
+}
+
+// Returns whether the reference defined in scope takes precedence over the previously resolved one
+func shadows(fullName string, scope string, previous string, accessibleScopes []string) bool {
+	previousScope, _ := splitScope(previous)
+	rank, previousRank := scopeRank(scope, accessibleScopes), scopeRank(previousScope, accessibleScopes)
+	if rank != previousRank {
+		return rank > previousRank
+	}
+	// Neither scope is accessible, keep the choice independent of the map's iteration order
+	return fullName < previous
+}
+
+// Accessible scopes are listed from outer to inner, scopes that aren't accessible rank lowest
+func scopeRank(scope string, accessibleScopes []string) int {
+	for i := len(accessibleScopes) - 1; i >= 0; i-- {
+		if accessibleScopes[i] == scope {
+			return i
+		}
+	}
+	return -1
+}
+
+func splitScope(fullName string) (string, string) {
+	idx := strings.LastIndex(fullName, ".")
+	if idx == -1 {
+		return "", fullName
+	}
+	return fullName[:idx], fullName[idx+1:]
+}
+
 // Fetches a constant used by the hint
 // Searches inner modules first for name-matching constants
 func (ids *IdsManager) GetConst(name string, constants *map[string]lambdaworks.Felt) (lambdaworks.Felt, error) {
@@ -54,13 +124,24 @@ func (ids *IdsManager) GetConst(name string, constants *map[string]lambdaworks.F
 }
 
 // Inserts value into memory given its identifier name
+// Fails if the identifier is unknown, is an immediate, or its cell is already set to a different value
 func (ids *IdsManager) Insert(name string, value *MaybeRelocatable, vm *VirtualMachine) error {
+	return ids.insertAt(name, 0, value, vm)
+}
 
+// Writes the value at the given offset from the identifier's address
+func (ids *IdsManager) insertAt(name string, offset uint, value *MaybeRelocatable, vm *VirtualMachine) error {
+	if reference, ok := ids.References[name]; ok && reference.Offset1.ValueType == Immediate {
+		return ErrInsertImmediate(name)
+	}
 	addr, err := ids.GetAddr(name, vm)
 	if err != nil {
 		return err
 	}
-	return vm.Segments.Memory.Insert(addr, value)
+	if err := vm.Segments.Memory.Insert(addr.AddUint(offset), value); err != nil {
+		return ErrInsertIdentifier(name, err)
+	}
+	return nil
 }
 
 // Returns the value of an identifier as a Felt
@@ -113,6 +194,7 @@ func (ids *IdsManager) Get(name string, vm *VirtualMachine) (*MaybeRelocatable,
 		if ok {
 			return val, nil
 		}
+		return nil, ids.unresolvedReferenceError(name, &reference)
 	}
 	return nil, ErrUnknownIdentifier(name)
 }
@@ -125,6 +207,7 @@ func (ids *IdsManager) GetAddr(name string, vm *VirtualMachine) (Relocatable, er
 		if ok {
 			return addr, nil
 		}
+		return Relocatable{}, ids.unresolvedReferenceError(name, &reference)
 	}
 	return Relocatable{}, ErrUnknownIdentifier(name)
 }
@@ -149,6 +232,7 @@ func (ids *IdsManager) GetStructField(name string, field_off uint, vm *VirtualMa
 		if ok {
 			return val, nil
 		}
+		return nil, ids.unresolvedReferenceError(name, &reference)
 	}
 	return nil, ErrUnknownIdentifier(name)
 }
@@ -177,6 +261,7 @@ func (ids *IdsManager) GetStructFieldFelt(name string, field_off uint, vm *Virtu
 			}
 			return felt, nil
 		}
+		return lambdaworks.Felt{}, ids.unresolvedReferenceError(name, &reference)
 	}
 
 	return lambdaworks.Felt{}, ErrUnknownIdentifier(name)
@@ -206,6 +291,7 @@ func (ids *IdsManager) GetStructFieldRelocatable(name string, field_off uint, vm
 			}
 			return rel, nil
 		}
+		return Relocatable{}, ids.unresolvedReferenceError(name, &reference)
 	}
 
 	return Relocatable{}, ErrUnknownIdentifier(name)
@@ -225,25 +311,15 @@ func (ids *IdsManager) GetStructFieldRelocatable(name string, field_off uint, vm
 		ids.InsertStructField("cat", 1, vm)
 */
 func (ids *IdsManager) InsertStructField(name string, field_off uint, value *MaybeRelocatable, vm *VirtualMachine) error {
-
-	addr, err := ids.GetAddr(name, vm)
-	if err != nil {
-		return err
-	}
-	return vm.Segments.Memory.Insert(addr.AddUint(field_off), value)
+	return ids.insertAt(name, field_off, value, vm)
 }
 
 // Inserts Uint256 value into an ids field (given the identifier is a Uint256)
 func (ids *IdsManager) InsertUint256(name string, val Uint256, vm *VirtualMachine) error {
-	baseAddr, err := ids.GetAddr(name, vm)
-	if err != nil {
+	if err := ids.insertAt(name, 0, NewMaybeRelocatableFelt(val.Low), vm); err != nil {
 		return err
 	}
-	err = vm.Segments.Memory.Insert(baseAddr, NewMaybeRelocatableFelt(val.Low))
-	if err != nil {
-		return err
-	}
-	return vm.Segments.Memory.Insert(baseAddr.AddUint(1), NewMaybeRelocatableFelt(val.High))
+	return ids.insertAt(name, 1, NewMaybeRelocatableFelt(val.High), vm)
 }
 
 // Inserts value into the address of the given identifier
@@ -343,13 +419,27 @@ func getOffsetValueReference(offsetValue OffsetValue, refApTracking parser.ApTra
 	return nil
 }
 
+// Returns the value ap had when the reference was created, given its current value at the hint
+// ap advanced by (hint offset - reference offset) cells since then, which is only known if both belong to the same group
 func applyApTrackingCorrection(addr Relocatable, refApTracking parser.ApTrackingData, hintApTracking parser.ApTrackingData) (Relocatable, bool) {
-	// Reference & Hint ApTracking must belong to the same group
-	if refApTracking.Group == hintApTracking.Group {
-		addr, err := addr.SubUint(uint(hintApTracking.Offset - refApTracking.Offset))
-		if err == nil {
-			return addr, true
-		}
+	if refApTracking.Group != hintApTracking.Group {
+		return Relocatable{}, false
 	}
-	return Relocatable{}, false
+	addr, err := addr.AddInt(refApTracking.Offset - hintApTracking.Offset)
+	return addr, err == nil
+}
+
+// Returns true if any of the reference's offsets is based on ap
+func usesAp(reference *HintReference) bool {
+	return (reference.Offset1.ValueType == Reference && reference.Offset1.Register == AP) ||
+		(reference.Offset2.ValueType == Reference && reference.Offset2.Register == AP)
+}
+
+// Returns the error for a reference that couldn't be resolved, which is more specific
+// if it is ap-based & can't be corrected to the hint's ap tracking
+func (ids *IdsManager) unresolvedReferenceError(name string, reference *HintReference) error {
+	if usesAp(reference) && reference.ApTrackingData.Group != ids.HintApTracking.Group {
+		return ErrInvalidApTrackingGroup(name, reference.ApTrackingData.Group, ids.HintApTracking.Group)
+	}
+	return ErrUnknownIdentifier(name)
 }
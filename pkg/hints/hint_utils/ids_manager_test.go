@@ -1,6 +1,8 @@
 package hint_utils_test
 
 import (
+	"reflect"
+	"strings"
 	"testing"
 
 	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
@@ -8,6 +10,7 @@ import (
 	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
 )
 
 func TestIdsManagerGetAddressSimpleReference(t *testing.T) {
@@ -57,6 +60,50 @@ func TestIdsManagerGetAddressWithApTrackingCorrection(t *testing.T) {
 	}
 }
 
+func TestIdsManagerGetStructFieldWithApTrackingCorrection(t *testing.T) {
+	// [cast(ap + (-2), MyStruct*)] created at ap tracking offset 1, read by a hint placed 3 cells of ap later
+	ids := IdsManager{
+		References: map[string]HintReference{
+			"ptr": {
+				Offset1:        OffsetValue{Register: vm.AP, Value: -2, ValueType: Reference},
+				Dereference:    true,
+				ApTrackingData: parser.ApTrackingData{Group: 4, Offset: 1},
+			},
+		},
+		HintApTracking: parser.ApTrackingData{Group: 4, Offset: 4},
+	}
+	vm := vm.NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.RunContext.Ap = memory.NewRelocatable(1, 7)
+	// ap at the reference = (1, 7) - 3 = (1, 4), so the reference is [(1, 2)]
+	vm.Segments.Memory.Insert(memory.NewRelocatable(1, 2), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(17)))
+	vm.Segments.Memory.Insert(memory.NewRelocatable(1, 3), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(18)))
+
+	field, err := ids.GetStructFieldFelt("ptr", 1, vm)
+	if err != nil || field != lambdaworks.FeltFromUint64(18) {
+		t.Errorf("Wrong struct field: %s, err: %v", field.ToSignedFeltString(), err)
+	}
+}
+
+func TestIdsManagerGetApBasedReferenceFromAnotherGroup(t *testing.T) {
+	ids := IdsManager{
+		References: map[string]HintReference{
+			"val": {
+				Offset1:        OffsetValue{Register: vm.AP, ValueType: Reference},
+				ApTrackingData: parser.ApTrackingData{Group: 1, Offset: 2},
+			},
+		},
+		HintApTracking: parser.ApTrackingData{Group: 2, Offset: 0},
+	}
+	vm := vm.NewVirtualMachine()
+	vm.RunContext.Ap = memory.NewRelocatable(1, 5)
+	_, err := ids.GetAddr("val", vm)
+	if err == nil || !strings.Contains(err.Error(), "ap tracking group (1) differs from the hint's (2)") {
+		t.Errorf("Expected an ap tracking group error, got: %v", err)
+	}
+}
+
 func TestIdsManagerGetAddressUnknownIdentifier(t *testing.T) {
 	ids := IdsManager{
 		References: map[string]HintReference{
@@ -337,3 +384,85 @@ func TestIdsManagerGetConstNoMConst(t *testing.T) {
 		t.Errorf("IdsManager.GetConst should have failed")
 	}
 }
+
+func TestResolveHintReferencesPrioritizesInnerScope(t *testing.T) {
+	referenceIds := map[string]uint{
+		"starkware.cairo.common.math.a":                0,
+		"starkware.cairo.common.math.assert_250_bit.a": 1,
+		"starkware.cairo.common.math.assert_250_bit.b": 2,
+	}
+	references := []parser.Reference{
+		{Value: "[cast(fp + (-3), felt*)]"},
+		{Value: "[cast(fp + (-4), felt*)]"},
+		{Value: "[cast(fp + (-5), felt*)]"},
+	}
+	accessibleScopes := []string{"starkware.cairo.common.math", "starkware.cairo.common.math.assert_250_bit"}
+
+	resolved, err := ResolveHintReferences(referenceIds, references, accessibleScopes)
+	if err != nil {
+		t.Fatalf("ResolveHintReferences failed with error: %s", err)
+	}
+	expected := map[string]HintReference{
+		"a": ParseHintReference(references[1]),
+		"b": ParseHintReference(references[2]),
+	}
+	if !reflect.DeepEqual(resolved, expected) {
+		t.Errorf("Wrong references, expected %+v, got %+v", expected, resolved)
+	}
+}
+
+func TestResolveHintReferencesPrefersAccessibleScope(t *testing.T) {
+	referenceIds := map[string]uint{"__main__.other.x": 0, "__main__.main.x": 1, "__main__.unrelated.x": 2}
+	references := []parser.Reference{
+		{Value: "[cast(fp + (-3), felt*)]"},
+		{Value: "[cast(fp + (-4), felt*)]"},
+		{Value: "[cast(fp + (-5), felt*)]"},
+	}
+
+	resolved, err := ResolveHintReferences(referenceIds, references, []string{"__main__", "__main__.main"})
+	if err != nil {
+		t.Fatalf("ResolveHintReferences failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(resolved["x"], ParseHintReference(references[1])) {
+		t.Errorf("Wrong reference for x: %+v", resolved["x"])
+	}
+}
+
+func TestResolveHintReferencesMissingReference(t *testing.T) {
+	_, err := ResolveHintReferences(map[string]uint{"__main__.a": 1}, []parser.Reference{{}}, nil)
+	if err == nil || !strings.Contains(err.Error(), "__main__.a") {
+		t.Errorf("Expected a missing reference error, got: %v", err)
+	}
+}
+
+func TestIdsManagerInsertErrors(t *testing.T) {
+	ids := IdsManager{
+		References: map[string]HintReference{
+			"val":   {Offset1: OffsetValue{Register: vm.FP, ValueType: Reference}},
+			"const": {Offset1: OffsetValue{Immediate: lambdaworks.FeltFromUint64(3), ValueType: Immediate}},
+		},
+	}
+	vm := vm.NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.RunContext.Fp = memory.NewRelocatable(1, 0)
+
+	value := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(7))
+	if err := ids.Insert("val", value, vm); err != nil {
+		t.Fatalf("Insert failed with error: %s", err)
+	}
+	// Writing the same value again is allowed
+	if err := ids.Insert("val", value, vm); err != nil {
+		t.Errorf("Insert of the same value failed with error: %s", err)
+	}
+	err := ids.Insert("val", memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(8)), vm)
+	if !errors.Is(err, memory.ErrInconsistentMemory) || !strings.Contains(err.Error(), "Identifier val is already set") {
+		t.Errorf("Expected an already set error, got %v", err)
+	}
+	if err := ids.Insert("const", value, vm); !errors.Is(err, ErrImmediateIdentifier) {
+		t.Errorf("Expected ErrImmediateIdentifier, got %v", err)
+	}
+	if err := ids.InsertStructField("missing", 1, value, vm); err == nil || !strings.Contains(err.Error(), "Unknown identifier missing") {
+		t.Errorf("Expected an unknown identifier error, got %v", err)
+	}
+}
@@ -40,3 +40,7 @@ func ToUint256(a *big.Int) Uint256 {
 func (u *Uint256) IsEqual(other Uint256) bool {
 	return u.Low.Cmp(other.Low) == 0 && u.High.Cmp(other.High) == 0
 }
+
+func (u *Uint256) IsZero() bool {
+	return u.Low.IsZero() && u.High.IsZero()
+}
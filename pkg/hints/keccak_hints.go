@@ -6,6 +6,7 @@ import (
 	"github.com/ebfe/keccak"
 	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	"github.com/lambdaclass/cairo-vm.go/pkg/keccak_utils"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
@@ -27,15 +28,8 @@ func unsafeKeccak(ids IdsManager, vm *VirtualMachine, scopes ExecutionScopes) er
 	if err != nil {
 		return err
 	}
-	// Check __keccak_max_size if available
-	keccakMaxSizeAny, err := scopes.Get("__keccak_max_size")
-	if err == nil {
-		keccakMaxSize, ok := keccakMaxSizeAny.(uint64)
-		if ok {
-			if length > keccakMaxSize {
-				return errors.Errorf("unsafe_keccak() can only be used with length<=%d. Got: length=%d", keccakMaxSize, length)
-			}
-		}
+	if err := checkMaxSize(&scopes, KECCAK_MAX_SIZE, length, "unsafe_keccak() can only be used with length<=%d. Got: length=%d"); err != nil {
+		return err
 	}
 	keccakInput := make([]byte, 0)
 	for byteIdx, wordIdx := 0, 0; byteIdx < int(length); byteIdx, wordIdx = byteIdx+16, wordIdx+1 {
@@ -128,9 +122,9 @@ func compareBytesInWordNondet(ids IdsManager, vm *VirtualMachine, constants *map
 	}
 	bytesInWord, err := ids.GetConst("BYTES_IN_WORD", constants)
 	if nBytes.Cmp(bytesInWord) == -1 {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 	}
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+	return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 }
 
 func compareKeccakFullRateInBytesNondet(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) error {
@@ -140,18 +134,17 @@ func compareKeccakFullRateInBytesNondet(ids IdsManager, vm *VirtualMachine, cons
 	}
 	bytesInWord, err := ids.GetConst("KECCAK_FULL_RATE_IN_BYTES", constants)
 	if nBytes.Cmp(bytesInWord) != -1 {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 	}
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+	return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 }
 
 func blockPermutation(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) error {
-	const KECCAK_SIZE = 25
 	keccakStateSizeFeltsFelt, err := ids.GetConst("KECCAK_STATE_SIZE_FELTS", constants)
 	if err != nil {
 		return err
 	}
-	if keccakStateSizeFeltsFelt.Cmp(FeltFromUint64(KECCAK_SIZE)) != 0 {
+	if keccakStateSizeFeltsFelt.Cmp(FeltFromUint64(keccak_utils.KECCAK_STATE_SIZE_FELTS)) != 0 {
 		return errors.New("Assertion failed: _keccak_state_size_felts == 25")
 	}
 
@@ -159,42 +152,29 @@ func blockPermutation(ids IdsManager, vm *VirtualMachine, constants *map[string]
 	if err != nil {
 		return err
 	}
-	startPtr, err := keccakPtr.SubUint(KECCAK_SIZE)
+	startPtr, err := keccakPtr.SubUint(keccak_utils.KECCAK_STATE_SIZE_FELTS)
 	if err != nil {
 		return err
 	}
-	inputFelt, err := vm.Segments.GetFeltRange(startPtr, KECCAK_SIZE)
+	input, err := keccak_utils.U64ArrayFromMemory(&vm.Segments.Memory, startPtr, keccak_utils.KECCAK_STATE_SIZE_FELTS)
 	if err != nil {
 		return err
 	}
 
-	var inputU64 [KECCAK_SIZE]uint64
-	for i := 0; i < KECCAK_SIZE; i++ {
-		val, err := inputFelt[i].ToU64()
-		if err != nil {
-			return err
-		}
-		inputU64[i] = val
-	}
-
-	builtins.KeccakF1600(&inputU64)
+	var state [keccak_utils.KECCAK_STATE_SIZE_FELTS]uint64
+	copy(state[:], input)
+	builtins.KeccakF1600(&state)
 
-	output := make([]MaybeRelocatable, 0, KECCAK_SIZE)
-	for i := 0; i < KECCAK_SIZE; i++ {
-		output = append(output, *NewMaybeRelocatableFelt(FeltFromUint64(inputU64[i])))
-	}
-
-	_, err = vm.Segments.LoadData(keccakPtr, &output)
+	_, err = keccak_utils.U64ArrayToMemory(&vm.Segments, keccakPtr, state[:])
 	return err
 }
 
 func cairoKeccakFinalize(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt, blockSizeLimit uint64) error {
-	const KECCAK_SIZE = 25
 	keccakStateSizeFeltsFelt, err := ids.GetConst("KECCAK_STATE_SIZE_FELTS", constants)
 	if err != nil {
 		return err
 	}
-	if keccakStateSizeFeltsFelt.Cmp(FeltFromUint64(KECCAK_SIZE)) != 0 {
+	if keccakStateSizeFeltsFelt.Cmp(FeltFromUint64(keccak_utils.KECCAK_STATE_SIZE_FELTS)) != 0 {
 		return errors.New("Assertion failed: _keccak_state_size_felts == 25")
 	}
 
@@ -206,23 +186,22 @@ func cairoKeccakFinalize(ids IdsManager, vm *VirtualMachine, constants *map[stri
 		return errors.Errorf("assert 0 <= _block_size < %d", blockSizeLimit)
 	}
 	blockSize, _ := blockSizeFelt.ToU64()
-	var input [KECCAK_SIZE]uint64
-	builtins.KeccakF1600(&input)
-	padding := make([]MaybeRelocatable, 0, KECCAK_SIZE*2*blockSize)
-	for i := 0; i < KECCAK_SIZE; i++ {
-		padding = append(padding, *NewMaybeRelocatableFelt(FeltZero()))
-	}
-	for i := 0; i < KECCAK_SIZE; i++ {
-		padding = append(padding, *NewMaybeRelocatableFelt(FeltFromUint64(input[i])))
-	}
-	for i := 1; i < int(blockSize); i++ {
-		padding = append(padding, padding[:50]...)
+
+	// Each block is made of an all zero state followed by its permutation
+	var output [keccak_utils.KECCAK_STATE_SIZE_FELTS]uint64
+	builtins.KeccakF1600(&output)
+	block := make([]uint64, keccak_utils.KECCAK_STATE_SIZE_FELTS, 2*keccak_utils.KECCAK_STATE_SIZE_FELTS)
+	block = append(block, output[:]...)
+	padding := make([]uint64, 0, uint64(len(block))*blockSize)
+	for i := uint64(0); i < blockSize; i++ {
+		padding = append(padding, block...)
 	}
+
 	keccakEndPtr, err := ids.GetRelocatable("keccak_ptr_end", vm)
 	if err != nil {
 		return err
 	}
-	_, err = vm.Segments.LoadData(keccakEndPtr, &padding)
+	_, err = keccak_utils.U64ArrayToMemory(&vm.Segments, keccakEndPtr, padding)
 	return err
 }
 
@@ -248,10 +227,10 @@ func keccakWriteArgs(ids IdsManager, vm *VirtualMachine) error {
 		*NewMaybeRelocatableFelt(high.Shr(64)),
 	}
 
-	inputs, err = vm.Segments.LoadData(inputs, &low_args)
+	inputs, err = vm.Segments.WriteArg(inputs, low_args)
 	if err != nil {
 		return err
 	}
-	_, err = vm.Segments.LoadData(inputs, &high_args)
+	_, err = vm.Segments.WriteArg(inputs, high_args)
 	return err
 }
@@ -1,7 +1,6 @@
 package hints
 
 import (
-	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
@@ -9,29 +8,28 @@ import (
 )
 
 // memory[ap] = 0 if 0 <= (ids.a % PRIME) < range_check_builtin.bound else 1
-func isNN(ids IdsManager, vm *VirtualMachine) error {
+func isNN(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) error {
 	a, err := ids.GetFelt("a", vm)
 	if err != nil {
 		return err
 	}
-	if a.Bits() < builtins.RANGE_CHECK_N_PARTS*builtins.INNER_RC_BOUND_SHIFT {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+	if a.Cmp(rangeCheckBound(ids, vm, constants)) == -1 {
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 	}
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+	return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 }
 
 // memory[ap] = 0 if 0 <= ((-ids.a - 1) % PRIME) < range_check_builtin.bound else 1
-func isNNOutOfRange(ids IdsManager, vm *VirtualMachine) error {
+func isNNOutOfRange(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) error {
 	a, err := ids.GetFelt("a", vm)
 	if err != nil {
 		return err
 	}
 	op := FeltZero().Sub(a).Sub(FeltOne())
-	bound := FeltOne().Shl(16 * builtins.RANGE_CHECK_N_PARTS)
-	if op.Cmp(bound) == -1 {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+	if op.Cmp(rangeCheckBound(ids, vm, constants)) == -1 {
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 	}
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+	return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 }
 
 // memory[ap] = 0 if (ids.a % PRIME) <= (ids.b % PRIME) else 1
@@ -45,7 +43,7 @@ func isLeFelt(ids IdsManager, vm *VirtualMachine) error {
 		return err
 	}
 	if a.Cmp(b) != 1 {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 	}
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+	return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 }
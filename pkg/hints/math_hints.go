@@ -14,6 +14,21 @@ import (
 	"github.com/pkg/errors"
 )
 
+// Returns range_check_builtin.bound, the bound the hints of the common library check their inputs against
+// The program's RC_BOUND constant (ie: starkware.cairo.common.math_cmp.RC_BOUND) is used if the hint can access it,
+// otherwise the bound of the vm's range check builtin, or the standard 2**128 if the run has none
+func rangeCheckBound(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) Felt {
+	if constants != nil {
+		if bound, err := ids.GetConst("RC_BOUND", constants); err == nil {
+			return bound
+		}
+	}
+	if bound, err := vm.GetRangeCheckBound(); err == nil {
+		return bound
+	}
+	return FeltOne().Shl(builtins.RANGE_CHECK_N_PARTS * builtins.INNER_RC_BOUND_SHIFT)
+}
+
 // Implements hint:
 //
 //	%{
@@ -22,32 +37,39 @@ import (
 //	    assert 0 <= ids.a % PRIME < range_check_builtin.bound, f'a = {ids.a} is out of range.'
 //
 // %}
-func assert_nn(ids IdsManager, vm *VirtualMachine) error {
+func assert_nn(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) error {
 	a, err := ids.GetFelt("a", vm)
 	if err != nil {
 		return err
 	}
-	if a.Bits() >= builtins.RANGE_CHECK_N_PARTS*builtins.INNER_RC_BOUND_SHIFT {
+	if a.Cmp(rangeCheckBound(ids, vm, constants)) != -1 {
 		return errors.Errorf("Assertion failed, 0 <= ids.a %% PRIME < range_check_builtin.bound\n a = %s is out of range", a.ToHexString())
 	}
 	return nil
 }
 
-func is_positive(ids IdsManager, vm *VirtualMachine) error {
+// Implements hint:
+//
+//	%{
+//	    from starkware.cairo.common.math_utils import is_positive
+//	    ids.is_positive = 1 if is_positive(
+//	        value=ids.value, prime=PRIME, rc_bound=range_check_builtin.bound) else 0
+//
+// %}
+func is_positive(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) error {
 	value, err := ids.GetFelt("value", vm)
 	if err != nil {
 		return err
 	}
 	signedValue := value.ToSigned()
-	if signedValue.BitLen() >= builtins.RANGE_CHECK_N_PARTS*builtins.INNER_RC_BOUND_SHIFT {
+	if new(big.Int).Abs(signedValue).Cmp(rangeCheckBound(ids, vm, constants).ToBigInt()) != -1 {
 		return errors.Errorf("Assertion Failed: abs(val) < rc_bound, value=%s is out of the  valid range", signedValue)
 	}
 	is_positive := uint64(0)
 	if signedValue.Sign() == 1 {
 		is_positive = 1
 	}
-	ids.Insert("is_positive", NewMaybeRelocatableFelt(FeltFromUint64(is_positive)), vm)
-	return nil
+	return ids.Insert("is_positive", NewMaybeRelocatableFelt(FeltFromUint64(is_positive)), vm)
 }
 
 // Implements hint:from starkware.cairo.common.math.cairo
@@ -119,18 +141,15 @@ func is_quad_residue(ids IdsManager, vm *VirtualMachine) error {
 	if err != nil {
 		return err
 	}
-	if x.IsZero() || x.IsOne() {
-		ids.Insert("y", NewMaybeRelocatableFelt(x), vm)
-
-	} else if x.Pow(SignedFeltMaxValue()) == FeltOne() {
-		num := x.Sqrt()
-		ids.Insert("y", NewMaybeRelocatableFelt(num), vm)
-
-	} else {
-		num := (x.Div(lambdaworks.FeltFromUint64(3))).Sqrt()
-		ids.Insert("y", NewMaybeRelocatableFelt(num), vm)
+	y, ok := x.CheckedSqrt()
+	if !ok {
+		// 3 is not a quadratic residue, so x / 3 is one when x isn't
+		y, ok = x.Div(lambdaworks.FeltFromUint64(3)).CheckedSqrt()
+		if !ok {
+			return errors.Errorf("Neither %s nor %s / 3 are quadratic residues", x.ToHexString(), x.ToHexString())
+		}
 	}
-	return nil
+	return ids.Insert("y", NewMaybeRelocatableFelt(y), vm)
 }
 
 func assert_not_equal(ids IdsManager, vm *VirtualMachine) error {
@@ -147,14 +166,14 @@ func assert_not_equal(ids IdsManager, vm *VirtualMachine) error {
 	a_rel, a_is_rel := a.GetRelocatable()
 	b_rel, b_is_rel := b.GetRelocatable()
 	if !((a_is_rel && b_is_rel && a_rel.SegmentIndex == b_rel.SegmentIndex) || (!a_is_rel && !b_is_rel)) {
-		return errors.Errorf("assert_not_equal failed: non-comparable values: %v, %v.", a, b)
+		return errors.Errorf("assert_not_equal failed: non-comparable values: %s, %s.", a, b)
 	}
 	diff, err := a.Sub(*b)
 	if err != nil {
 		return err
 	}
 	if diff.IsZero() {
-		return errors.Errorf("assert_not_equal failed: %v = %v.", a, b)
+		return errors.Errorf("assert_not_equal failed: %s = %s.", a, b)
 	}
 	return nil
 }
@@ -183,8 +202,7 @@ func sqrt(ids IdsManager, vm *VirtualMachine) error {
 		return err
 	}
 	root_felt := FeltFromDecString(root_big.String())
-	ids.Insert("root", NewMaybeRelocatableFelt(root_felt), vm)
-	return nil
+	return ids.Insert("root", NewMaybeRelocatableFelt(root_felt), vm)
 }
 
 /*
@@ -316,9 +334,9 @@ func assertLeFeltExcluded0(vm *VirtualMachine, scopes *ExecutionScopes) error {
 		return errors.New("excluded not in scope")
 	}
 	if excluded == 0 {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 	}
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+	return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 }
 
 // "memory[ap] = 1 if excluded != 1 else 0"
@@ -333,9 +351,9 @@ func assertLeFeltExcluded1(vm *VirtualMachine, scopes *ExecutionScopes) error {
 		return errors.New("excluded not in scope")
 	}
 	if excluded == 1 {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 	}
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+	return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 }
 
 // "assert excluded == 2"
@@ -355,6 +373,59 @@ func assertLeFeltExcluded2(vm *VirtualMachine, scopes *ExecutionScopes) error {
 	return nil
 }
 
+// Implements hint (cairo-lang v0.6):
+//
+//	%{
+//	    from starkware.cairo.common.math_utils import assert_integer
+//	    assert_integer(ids.a)
+//	    assert_integer(ids.b)
+//	    assert (ids.a % PRIME) <= (ids.b % PRIME), \
+//	        f'a = {ids.a % PRIME} is not less than or equal to b = {ids.b % PRIME}.'
+//
+// %}
+func assertLeFeltV06(ids IdsManager, vm *VirtualMachine) error {
+	a, err := ids.GetFelt("a", vm)
+	if err != nil {
+		return err
+	}
+	b, err := ids.GetFelt("b", vm)
+	if err != nil {
+		return err
+	}
+	if a.Cmp(b) == 1 {
+		return errors.Errorf("Assertion failed, a = %s is not less than or equal to b = %s", a.ToSignedFeltString(), b.ToSignedFeltString())
+	}
+	return nil
+}
+
+// Implements hint (cairo-lang v0.8):
+//
+//	%{
+//	    from starkware.cairo.common.math_utils import assert_integer
+//	    assert_integer(ids.a)
+//	    assert_integer(ids.b)
+//	    a = ids.a % PRIME
+//	    b = ids.b % PRIME
+//	    assert a <= b, f'a = {a} is not less than or equal to b = {b}.'
+//
+//	    ids.small_inputs = int(
+//	        a < range_check_builtin.bound and (b - a) < range_check_builtin.bound)
+//
+// %}
+func assertLeFeltV08(ids IdsManager, vm *VirtualMachine, constants *map[string]Felt) error {
+	if err := assertLeFeltV06(ids, vm); err != nil {
+		return err
+	}
+	a, _ := ids.GetFelt("a", vm)
+	b, _ := ids.GetFelt("b", vm)
+	bound := rangeCheckBound(ids, vm, constants)
+	smallInputs := FeltZero()
+	if a.Cmp(bound) == -1 && b.Sub(a).Cmp(bound) == -1 {
+		smallInputs = FeltOne()
+	}
+	return ids.Insert("small_inputs", NewMaybeRelocatableFelt(smallInputs), vm)
+}
+
 func assertLtFelt(ids IdsManager, vm *VirtualMachine) error {
 	// Fetch ids variables
 	a, err := ids.GetFelt("a", vm)
@@ -485,10 +556,10 @@ func signedDivRem(ids IdsManager, vm *VirtualMachine) error {
 	biasedQFelt := lambdaworks.FeltFromBigInt(biasedQ)
 	rFelt := lambdaworks.FeltFromBigInt(r)
 
-	ids.Insert("r", NewMaybeRelocatableFelt(rFelt), vm)
-	ids.Insert("biased_q", NewMaybeRelocatableFelt(biasedQFelt), vm)
-
-	return nil
+	if err := ids.Insert("r", NewMaybeRelocatableFelt(rFelt), vm); err != nil {
+		return err
+	}
+	return ids.Insert("biased_q", NewMaybeRelocatableFelt(biasedQFelt), vm)
 }
 
 // Implements hint:
@@ -572,7 +643,10 @@ func splitInt(ids IdsManager, vm *VirtualMachine) error {
 		return err
 	}
 
-	res := value.ModFloor(base)
+	_, res, err := value.CheckedDivRem(base)
+	if err != nil {
+		return errors.Wrap(err, "split_int()")
+	}
 
 	if res.Cmp(bound) == 1 {
 		return errors.Errorf("split_int(): Limb %d is out of range", res.ToBigInt())
@@ -1138,3 +1138,222 @@ func TestSplitIntAssertRangeHintOutOfRangeError(t *testing.T) {
 		t.Errorf("SPLIT_INT_ASSERT_RANGE hint should have failed")
 	}
 }
+
+func TestIsQuadResidueHint(t *testing.T) {
+	// 9 is a quadratic residue, 3 isn't, so the hint writes the root of 3 / 3 = 1
+	cases := map[uint64]Felt{0: FeltZero(), 1: FeltOne(), 9: FeltFromUint64(3), 3: FeltOne()}
+	for x, expected := range cases {
+		vm := NewVirtualMachine()
+		vm.Segments.AddSegment()
+		idsManager := SetupIdsForTest(
+			map[string][]*MaybeRelocatable{
+				"x": {NewMaybeRelocatableFelt(FeltFromUint64(x))},
+				"y": {nil},
+			},
+			vm,
+		)
+		hintProcessor := CairoVmHintProcessor{}
+		hintData := any(HintData{
+			Ids:  idsManager,
+			Code: IS_QUAD_RESIDUE,
+		})
+		err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+		if err != nil {
+			t.Errorf("IS_QUAD_RESIDUE hint test failed with error %s", err)
+			continue
+		}
+		y, err := idsManager.GetFelt("y", vm)
+		if err != nil || y != expected {
+			t.Errorf("IS_QUAD_RESIDUE hint for x = %d: expected y = %s, got %s", x, expected.ToHexString(), y.ToHexString())
+		}
+	}
+}
+
+func TestAssertNNHintRcBoundEdge(t *testing.T) {
+	bound := FeltOne().Shl(128)
+	for _, tc := range []struct {
+		a  Felt
+		ok bool
+	}{{bound.Sub(FeltOne()), true}, {bound, false}} {
+		vm := NewVirtualMachine()
+		vm.Segments.AddSegment()
+		idsManager := SetupIdsForTest(
+			map[string][]*MaybeRelocatable{
+				"a": {NewMaybeRelocatableFelt(tc.a)},
+			},
+			vm,
+		)
+		hintProcessor := CairoVmHintProcessor{}
+		hintData := any(HintData{
+			Ids:  idsManager,
+			Code: ASSERT_NN,
+		})
+		err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+		if (err == nil) != tc.ok {
+			t.Errorf("ASSERT_NN hint with a = %s: expected ok = %t, got error %v", tc.a.ToHexString(), tc.ok, err)
+		}
+	}
+}
+
+func TestAssertNNHintProgramRcBound(t *testing.T) {
+	for _, tc := range []struct {
+		a  uint64
+		ok bool
+	}{{99, true}, {100, false}} {
+		vm := NewVirtualMachine()
+		vm.Segments.AddSegment()
+		idsManager := SetupIdsForTest(
+			map[string][]*MaybeRelocatable{
+				"a": {NewMaybeRelocatableFelt(FeltFromUint64(tc.a))},
+			},
+			vm,
+		)
+		constants := SetupConstantsForTest(map[string]Felt{"RC_BOUND": FeltFromUint64(100)}, &idsManager)
+		hintProcessor := CairoVmHintProcessor{}
+		hintData := any(HintData{
+			Ids:  idsManager,
+			Code: ASSERT_NN,
+		})
+		err := hintProcessor.ExecuteHint(vm, &hintData, &constants, nil)
+		if (err == nil) != tc.ok {
+			t.Errorf("ASSERT_NN hint with a = %d & RC_BOUND = 100: expected ok = %t, got error %v", tc.a, tc.ok, err)
+		}
+	}
+}
+
+func TestIsPositiveRcBoundEdge(t *testing.T) {
+	// abs(-(2**128 - 1)) is still below the bound
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"value":       {NewMaybeRelocatableFelt(FeltZero().Sub(FeltOne().Shl(128)).Add(FeltOne()))},
+			"is_positive": {nil},
+		},
+		vm,
+	)
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: IS_POSITIVE,
+	})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+	if err != nil {
+		t.Errorf("IS_POSITIVE hint test failed with error %s", err)
+	}
+	isPositive, err := idsManager.GetFelt("is_positive", vm)
+	if err != nil || isPositive != FeltZero() {
+		t.Errorf("Wrong/No ids.is_positive: %s, %v", isPositive.ToSignedFeltString(), err)
+	}
+}
+
+func TestIsPositiveRcBoundEdgeOutOfRange(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"value":       {NewMaybeRelocatableFelt(FeltZero().Sub(FeltOne().Shl(128)))},
+			"is_positive": {nil},
+		},
+		vm,
+	)
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: IS_POSITIVE,
+	})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+	if err == nil {
+		t.Errorf("IS_POSITIVE hint test should have failed")
+	}
+}
+
+func TestAssertLeFeltV06(t *testing.T) {
+	for _, tc := range []struct {
+		a, b Felt
+		ok   bool
+	}{
+		{FeltFromUint64(17), FeltFromUint64(17), true},
+		{FeltFromUint64(17), FeltFromDecString("-1"), true},
+		{FeltFromDecString("-1"), FeltFromUint64(17), false},
+	} {
+		vm := NewVirtualMachine()
+		vm.Segments.AddSegment()
+		idsManager := SetupIdsForTest(
+			map[string][]*MaybeRelocatable{
+				"a": {NewMaybeRelocatableFelt(tc.a)},
+				"b": {NewMaybeRelocatableFelt(tc.b)},
+			},
+			vm,
+		)
+		hintProcessor := CairoVmHintProcessor{}
+		hintData := any(HintData{
+			Ids:  idsManager,
+			Code: ASSERT_LE_FELT_V_0_6,
+		})
+		err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+		if (err == nil) != tc.ok {
+			t.Errorf("ASSERT_LE_FELT_V_0_6 hint with a = %s, b = %s: expected ok = %t, got error %v", tc.a.ToSignedFeltString(), tc.b.ToSignedFeltString(), tc.ok, err)
+		}
+	}
+}
+
+func TestAssertLeFeltV08SmallInputs(t *testing.T) {
+	bound := FeltOne().Shl(128)
+	for _, tc := range []struct {
+		a, b        Felt
+		smallInputs Felt
+	}{
+		{FeltFromUint64(1), bound, FeltOne()},
+		{FeltZero(), bound, FeltZero()},
+		{bound.Sub(FeltOne()), bound, FeltOne()},
+		{bound, bound, FeltZero()},
+	} {
+		vm := NewVirtualMachine()
+		vm.Segments.AddSegment()
+		idsManager := SetupIdsForTest(
+			map[string][]*MaybeRelocatable{
+				"a":            {NewMaybeRelocatableFelt(tc.a)},
+				"b":            {NewMaybeRelocatableFelt(tc.b)},
+				"small_inputs": {nil},
+			},
+			vm,
+		)
+		hintProcessor := CairoVmHintProcessor{}
+		hintData := any(HintData{
+			Ids:  idsManager,
+			Code: ASSERT_LE_FELT_V_0_8,
+		})
+		err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+		if err != nil {
+			t.Errorf("ASSERT_LE_FELT_V_0_8 hint test failed with error %s", err)
+		}
+		smallInputs, err := idsManager.GetFelt("small_inputs", vm)
+		if err != nil || smallInputs != tc.smallInputs {
+			t.Errorf("Wrong ids.small_inputs for a = %s, b = %s. Expected %s, got %s",
+				tc.a.ToHexString(), tc.b.ToHexString(), tc.smallInputs.ToSignedFeltString(), smallInputs.ToSignedFeltString())
+		}
+	}
+}
+
+func TestAssertLeFeltV08Fail(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"a":            {NewMaybeRelocatableFelt(FeltFromUint64(18))},
+			"b":            {NewMaybeRelocatableFelt(FeltFromUint64(17))},
+			"small_inputs": {nil},
+		},
+		vm,
+	)
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: ASSERT_LE_FELT_V_0_8,
+	})
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+	if err == nil {
+		t.Errorf("ASSERT_LE_FELT_V_0_8 hint test should have failed")
+	}
+}
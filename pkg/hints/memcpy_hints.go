@@ -11,7 +11,7 @@ import (
 // Implements hint: memory[ap] = segments.add()
 func add_segment(vm *VirtualMachine) error {
 	new_segment_base := vm.Segments.AddSegment()
-	return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableRelocatable(new_segment_base))
+	return vm.InsertAtAp(0, NewMaybeRelocatableRelocatable(new_segment_base))
 }
 
 // Implements hint:
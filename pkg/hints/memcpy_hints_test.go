@@ -76,8 +76,8 @@ func TestExitScopeHintInvalid(t *testing.T) {
 	scope["a"] = FeltOne()
 
 	err := hintProcessor.ExecuteHint(vm, &hintData, nil, executionScopes)
-	if err.Error() != ErrCannotExitMainScop.Error() {
-		t.Errorf("should fail with error %s", ErrCannotExitMainScop)
+	if err.Error() != ErrCannotExitMainScope.Error() {
+		t.Errorf("should fail with error %s", ErrCannotExitMainScope)
 	}
 
 }
@@ -269,7 +269,7 @@ func TestMemcpyContinueCopyingInsertError(t *testing.T) {
 	})
 
 	err := hintProcessor.ExecuteHint(vm, &hintData, nil, executionScopes)
-	expected := ErrMemoryWriteOnce(NewRelocatable(0, 0), *NewMaybeRelocatableFeltFromUint64(5), *NewMaybeRelocatableFeltFromUint64(0))
+	expected := ErrInsertIdentifier("continue_copying", ErrMemoryWriteOnce(NewRelocatable(0, 0), *NewMaybeRelocatableFeltFromUint64(5), *NewMaybeRelocatableFeltFromUint64(0)))
 	if err.Error() != expected.Error() {
 		t.Errorf("should fail with error %s", expected)
 	}
@@ -176,7 +176,7 @@ func TestMemsetContinueLoopInsertError(t *testing.T) {
 	})
 
 	err := hintProcessor.ExecuteHint(vm, &hintData, nil, executionScopes)
-	expected := ErrMemoryWriteOnce(NewRelocatable(0, 0), *NewMaybeRelocatableFeltFromUint64(5), *NewMaybeRelocatableFeltFromUint64(0))
+	expected := ErrInsertIdentifier("continue_loop", ErrMemoryWriteOnce(NewRelocatable(0, 0), *NewMaybeRelocatableFeltFromUint64(5), *NewMaybeRelocatableFeltFromUint64(0)))
 	if err.Error() != expected.Error() {
 		t.Errorf("should fail with error %s", expected)
 	}
@@ -0,0 +1,199 @@
+package hints
+
+import (
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// SyscallHandler used to run the Starknet OS, which executes the side of the syscalls that the OS hints delegate to its
+// execution helper (ie: the storage of the contracts)
+// The OS hints fail with ErrNoOsSyscallHandler if the CairoVmHintProcessor's SyscallHandler doesn't implement it
+type OsSyscallHandler interface {
+	SyscallHandler
+	// Returns the value at key in the storage of the contract at contractAddress
+	StorageRead(vm *VirtualMachine, contractAddress Felt, key Felt) (Felt, error)
+	// Writes value at key in the storage of the contract at contractAddress, returning the previous value
+	StorageWrite(vm *VirtualMachine, contractAddress Felt, key Felt, value Felt) (Felt, error)
+	// Checks that the syscalls requested by the current call end at syscallPtrEnd, and discards them
+	ValidateAndDiscardSyscallPtr(vm *VirtualMachine, syscallPtrEnd Relocatable) error
+	// Leaves the current call, returning to its caller
+	ExitCall(vm *VirtualMachine) error
+}
+
+var ErrNoOsSyscallHandler = errors.New("The SyscallHandler doesn't implement OsSyscallHandler")
+var ErrInconsistentStorageValue = errors.New("Inconsistent storage value")
+
+// Storages are empty, so reads & writes return zero
+func (h NoopSyscallHandler) StorageRead(vm *VirtualMachine, contractAddress Felt, key Felt) (Felt, error) {
+	return FeltZero(), nil
+}
+
+func (h NoopSyscallHandler) StorageWrite(vm *VirtualMachine, contractAddress Felt, key Felt, value Felt) (Felt, error) {
+	return FeltZero(), nil
+}
+
+func (h NoopSyscallHandler) ValidateAndDiscardSyscallPtr(vm *VirtualMachine, syscallPtrEnd Relocatable) error {
+	return nil
+}
+
+func (h NoopSyscallHandler) ExitCall(vm *VirtualMachine) error {
+	return nil
+}
+
+func fetchOsSyscallHandler(syscallHandler SyscallHandler) (OsSyscallHandler, error) {
+	if syscallHandler == nil {
+		return NoopSyscallHandler{}, nil
+	}
+	osSyscallHandler, ok := syscallHandler.(OsSyscallHandler)
+	if !ok {
+		return nil, errors.Wrapf(ErrNoOsSyscallHandler, "%T", syscallHandler)
+	}
+	return osSyscallHandler, nil
+}
+
+// Implements hint:
+//
+//	%{
+//	    # Make sure the value is cached (by reading it), to be used later on for the
+//	    # commitment computation.
+//	    value = execution_helper.storage_by_address[ids.contract_address].read(
+//	        key=ids.syscall_ptr.request.address
+//	    )
+//	    assert ids.value == value, "Inconsistent storage value."
+//	%}
+func osStorageRead(ids IdsManager, vm *VirtualMachine, syscallHandler SyscallHandler) error {
+	osSyscallHandler, err := fetchOsSyscallHandler(syscallHandler)
+	if err != nil {
+		return err
+	}
+	contractAddress, err := ids.GetFelt("contract_address", vm)
+	if err != nil {
+		return err
+	}
+	syscallPtr, err := ids.GetRelocatable("syscall_ptr", vm)
+	if err != nil {
+		return err
+	}
+	// StorageRead.request.address
+	key, err := vm.Segments.Memory.GetFelt(syscallPtr.AddUint(1))
+	if err != nil {
+		return err
+	}
+	expected, err := ids.GetFelt("value", vm)
+	if err != nil {
+		return err
+	}
+	value, err := osSyscallHandler.StorageRead(vm, contractAddress, key)
+	if err != nil {
+		return err
+	}
+	if value != expected {
+		return errors.Wrapf(ErrInconsistentStorageValue, "ids.value is %s, but the storage holds %s", expected.ToSignedFeltString(), value.ToSignedFeltString())
+	}
+	return nil
+}
+
+// Implements hint:
+//
+//	%{
+//	    storage = execution_helper.storage_by_address[ids.contract_address]
+//	    ids.prev_value = storage.read(key=ids.syscall_ptr.address)
+//	    storage.write(key=ids.syscall_ptr.address, value=ids.syscall_ptr.value)
+//
+//	    # Fetch a state_entry in this hint and validate it in the update below.
+//	    ids.state_entry = __dict_manager.get_dict(ids.contract_state_changes)[ids.contract_address]
+//	%}
+func osStorageWrite(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine, syscallHandler SyscallHandler) error {
+	osSyscallHandler, err := fetchOsSyscallHandler(syscallHandler)
+	if err != nil {
+		return err
+	}
+	contractAddress, err := ids.GetFelt("contract_address", vm)
+	if err != nil {
+		return err
+	}
+	syscallPtr, err := ids.GetRelocatable("syscall_ptr", vm)
+	if err != nil {
+		return err
+	}
+	// StorageWrite.address & StorageWrite.value
+	key, err := vm.Segments.Memory.GetFelt(syscallPtr.AddUint(1))
+	if err != nil {
+		return err
+	}
+	value, err := vm.Segments.Memory.GetFelt(syscallPtr.AddUint(2))
+	if err != nil {
+		return err
+	}
+	prevValue, err := osSyscallHandler.StorageWrite(vm, contractAddress, key, value)
+	if err != nil {
+		return err
+	}
+	if err := ids.Insert("prev_value", NewMaybeRelocatableFelt(prevValue), vm); err != nil {
+		return err
+	}
+	return getContractStateEntry(ids, scopes, vm)
+}
+
+// Implements hint:
+//
+//	%{
+//	    # Fetch a state_entry in this hint and validate it in the update at the end
+//	    # of this function.
+//	    ids.state_entry = __dict_manager.get_dict(ids.contract_state_changes)[ids.contract_address]
+//	%}
+func getContractStateEntry(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) error {
+	dictManager, ok := FetchDictManager(scopes)
+	if !ok {
+		return errors.New("Variable __dict_manager not present in current execution scope")
+	}
+	contractStateChanges, err := ids.GetRelocatable("contract_state_changes", vm)
+	if err != nil {
+		return err
+	}
+	contractAddress, err := ids.Get("contract_address", vm)
+	if err != nil {
+		return err
+	}
+	tracker, err := getDictTracker(dictManager, contractStateChanges, vm)
+	if err != nil {
+		return err
+	}
+	stateEntry, err := dictManager.GetValue(tracker, contractAddress, &vm.Segments.Memory)
+	if err != nil {
+		return err
+	}
+	return ids.Insert("state_entry", stateEntry, vm)
+}
+
+// Implements hint:
+//
+//	%{
+//	    syscall_handler.validate_and_discard_syscall_ptr(
+//	        syscall_ptr_end=ids.entry_point_return_values.syscall_ptr
+//	    )
+//	    execution_helper.exit_call()
+//	%}
+func osExecuteSyscallsEnd(ids IdsManager, vm *VirtualMachine, syscallHandler SyscallHandler) error {
+	osSyscallHandler, err := fetchOsSyscallHandler(syscallHandler)
+	if err != nil {
+		return err
+	}
+	returnValues, err := ids.GetRelocatable("entry_point_return_values", vm)
+	if err != nil {
+		return err
+	}
+	// EntryPointReturnValues.syscall_ptr
+	syscallPtrEnd, err := vm.Segments.Memory.GetRelocatable(returnValues.AddUint(1))
+	if err != nil {
+		return err
+	}
+	if err := osSyscallHandler.ValidateAndDiscardSyscallPtr(vm, syscallPtrEnd); err != nil {
+		return err
+	}
+	return osSyscallHandler.ExitCall(vm)
+}
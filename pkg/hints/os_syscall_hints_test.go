@@ -0,0 +1,164 @@
+package hints_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints/dict_manager"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+type osStorageSyscallHandler struct {
+	NoopSyscallHandler
+	storage       map[Felt]map[Felt]Felt
+	syscallPtrEnd Relocatable
+	exitedCalls   int
+}
+
+func (h *osStorageSyscallHandler) StorageRead(vm *VirtualMachine, contractAddress Felt, key Felt) (Felt, error) {
+	return h.storage[contractAddress][key], nil
+}
+
+func (h *osStorageSyscallHandler) StorageWrite(vm *VirtualMachine, contractAddress Felt, key Felt, value Felt) (Felt, error) {
+	if h.storage[contractAddress] == nil {
+		h.storage[contractAddress] = map[Felt]Felt{}
+	}
+	prevValue := h.storage[contractAddress][key]
+	h.storage[contractAddress][key] = value
+	return prevValue, nil
+}
+
+func (h *osStorageSyscallHandler) ValidateAndDiscardSyscallPtr(vm *VirtualMachine, syscallPtrEnd Relocatable) error {
+	h.syscallPtrEnd = syscallPtrEnd
+	return nil
+}
+
+func (h *osStorageSyscallHandler) ExitCall(vm *VirtualMachine) error {
+	h.exitedCalls++
+	return nil
+}
+
+// Executes the os hint with ids.contract_address = 5, ids.syscall_ptr = (2, 0) & the given ids
+// Segment 2 holds the syscall request, and the contract_state_changes dict maps the contract to the state entry (3, 0)
+func executeOsSyscallHint(t *testing.T, vm *VirtualMachine, code string, ids map[string][]*MaybeRelocatable, hintProcessor CairoVmHintProcessor) (IdsManager, error) {
+	t.Helper()
+	scopes := NewExecutionScopes()
+	dictManager := dict_manager.NewDictManager()
+	contractStateChanges := dictManager.NewDictionary(&map[MaybeRelocatable]MaybeRelocatable{
+		*NewMaybeRelocatableFelt(FeltFromUint64(5)): *NewMaybeRelocatableRelocatable(NewRelocatable(3, 0)),
+	}, vm)
+	scopes.AssignOrUpdateVariable("__dict_manager", &dictManager)
+	ids["contract_address"] = []*MaybeRelocatable{NewMaybeRelocatableFelt(FeltFromUint64(5))}
+	ids["syscall_ptr"] = []*MaybeRelocatable{NewMaybeRelocatableRelocatable(NewRelocatable(2, 0))}
+	ids["contract_state_changes"] = []*MaybeRelocatable{NewMaybeRelocatableRelocatable(contractStateChanges)}
+	idsManager := SetupIdsForTest(ids, vm)
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: code,
+	})
+	return idsManager, hintProcessor.ExecuteHint(vm, &hintData, nil, scopes)
+}
+
+func newOsSyscallVm() *VirtualMachine {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	// storage request: selector, address, value
+	vm.Segments.Memory.Insert(NewRelocatable(2, 1), NewMaybeRelocatableFelt(FeltFromUint64(7)))
+	vm.Segments.Memory.Insert(NewRelocatable(2, 2), NewMaybeRelocatableFelt(FeltFromUint64(42)))
+	return vm
+}
+
+func TestOsStorageWriteAndRead(t *testing.T) {
+	handler := &osStorageSyscallHandler{storage: map[Felt]map[Felt]Felt{}}
+	hintProcessor := CairoVmHintProcessor{SyscallHandler: handler}
+	for i, expectedPrevValue := range []uint64{0, 42} {
+		vm := newOsSyscallVm()
+		idsManager, err := executeOsSyscallHint(t, vm, OS_STORAGE_WRITE, map[string][]*MaybeRelocatable{
+			"prev_value":  {nil},
+			"state_entry": {nil},
+		}, hintProcessor)
+		if err != nil {
+			t.Fatalf("Write %d failed with error %s", i, err)
+		}
+		prevValue, _ := idsManager.GetFelt("prev_value", vm)
+		if prevValue != FeltFromUint64(expectedPrevValue) {
+			t.Errorf("Write %d: expected ids.prev_value to be %d, got %s", i, expectedPrevValue, prevValue.ToSignedFeltString())
+		}
+		stateEntry, err := idsManager.GetRelocatable("state_entry", vm)
+		if err != nil || stateEntry != NewRelocatable(3, 0) {
+			t.Errorf("Write %d: wrong ids.state_entry: %s, %v", i, stateEntry.ToString(), err)
+		}
+	}
+
+	_, err := executeOsSyscallHint(t, newOsSyscallVm(), OS_STORAGE_READ, map[string][]*MaybeRelocatable{
+		"value": {NewMaybeRelocatableFelt(FeltFromUint64(42))},
+	}, hintProcessor)
+	if err != nil {
+		t.Errorf("OS_STORAGE_READ failed with error %s", err)
+	}
+	_, err = executeOsSyscallHint(t, newOsSyscallVm(), OS_STORAGE_READ, map[string][]*MaybeRelocatable{
+		"value": {NewMaybeRelocatableFelt(FeltFromUint64(41))},
+	}, hintProcessor)
+	if !errors.Is(err, ErrInconsistentStorageValue) {
+		t.Errorf("Expected ErrInconsistentStorageValue, got: %v", err)
+	}
+}
+
+func TestOsStorageReadNoopSyscallHandler(t *testing.T) {
+	_, err := executeOsSyscallHint(t, newOsSyscallVm(), OS_STORAGE_READ, map[string][]*MaybeRelocatable{
+		"value": {NewMaybeRelocatableFelt(FeltZero())},
+	}, CairoVmHintProcessor{})
+	if err != nil {
+		t.Errorf("OS_STORAGE_READ failed with error %s", err)
+	}
+}
+
+func TestOsStorageWriteNoOsSyscallHandler(t *testing.T) {
+	hintProcessor := CairoVmHintProcessor{SyscallHandler: &storageSyscallHandler{storage: map[Felt]Felt{}}}
+	_, err := executeOsSyscallHint(t, newOsSyscallVm(), OS_STORAGE_WRITE, map[string][]*MaybeRelocatable{
+		"prev_value":  {nil},
+		"state_entry": {nil},
+	}, hintProcessor)
+	if !errors.Is(err, ErrNoOsSyscallHandler) {
+		t.Errorf("Expected ErrNoOsSyscallHandler, got: %v", err)
+	}
+}
+
+func TestOsDeployGetStateEntry(t *testing.T) {
+	vm := newOsSyscallVm()
+	idsManager, err := executeOsSyscallHint(t, vm, OS_DEPLOY_GET_STATE_ENTRY, map[string][]*MaybeRelocatable{
+		"state_entry": {nil},
+	}, CairoVmHintProcessor{})
+	if err != nil {
+		t.Fatalf("OS_DEPLOY_GET_STATE_ENTRY failed with error %s", err)
+	}
+	stateEntry, err := idsManager.GetRelocatable("state_entry", vm)
+	if err != nil || stateEntry != NewRelocatable(3, 0) {
+		t.Errorf("Wrong ids.state_entry: %s, %v", stateEntry.ToString(), err)
+	}
+}
+
+func TestOsExecuteSyscallsEnd(t *testing.T) {
+	vm := newOsSyscallVm()
+	// EntryPointReturnValues at (3, 0): gas_builtin, syscall_ptr
+	vm.Segments.Memory.Insert(NewRelocatable(3, 1), NewMaybeRelocatableRelocatable(NewRelocatable(2, 3)))
+	handler := &osStorageSyscallHandler{storage: map[Felt]map[Felt]Felt{}}
+	_, err := executeOsSyscallHint(t, vm, OS_EXECUTE_SYSCALLS_END, map[string][]*MaybeRelocatable{
+		"entry_point_return_values": {NewMaybeRelocatableRelocatable(NewRelocatable(3, 0))},
+	}, CairoVmHintProcessor{SyscallHandler: handler})
+	if err != nil {
+		t.Fatalf("OS_EXECUTE_SYSCALLS_END failed with error %s", err)
+	}
+	if handler.syscallPtrEnd != NewRelocatable(2, 3) || handler.exitedCalls != 1 {
+		t.Errorf("Expected the syscall pointer to end at (2, 3) & one exited call, got %s & %d", handler.syscallPtrEnd.ToString(), handler.exitedCalls)
+	}
+}
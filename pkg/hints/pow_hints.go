@@ -5,20 +5,47 @@ import (
 	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
 )
 
+// Offsets of the members of pow's LoopLocals struct
+// struct LoopLocals { bit: felt, temp0: felt, res: felt, base: felt, exp: felt }
+const (
+	LOOP_LOCALS_BIT = 0
+	LOOP_LOCALS_EXP = 4
+)
+
+// Returns the address of the LoopLocals struct referenced by name
+// The reference is either a LoopLocals pointer (ie: cast(ap + (-5), LoopLocals*)), in which case its value is used,
+// or a LoopLocals struct whose cells haven't been written yet, in which case its address is used
+func getLoopLocalsAddr(ids IdsManager, name string, vm *VirtualMachine) (Relocatable, error) {
+	val, err := ids.Get(name, vm)
+	if err != nil {
+		return ids.GetAddr(name, vm)
+	}
+	addr, ok := val.GetRelocatable()
+	if !ok {
+		return Relocatable{}, errors.Errorf("Identifier %s is not a LoopLocals pointer", name)
+	}
+	return addr, nil
+}
+
 // Implements hint:
 // %{ ids.locs.bit = (ids.prev_locs.exp % PRIME) & 1 %}
 func pow(ids IdsManager, vm *VirtualMachine) error {
-	prev_locs_exp_addr, err := ids.GetRelocatable("prev_locs", vm)
+	prevLocs, err := getLoopLocalsAddr(ids, "prev_locs", vm)
+	if err != nil {
+		return err
+	}
+	locs, err := getLoopLocalsAddr(ids, "locs", vm)
 	if err != nil {
 		return err
 	}
 
-	prev_locs_exp, err := vm.Segments.Memory.GetFelt(prev_locs_exp_addr.AddUint(4))
+	prevLocsExp, err := vm.Segments.Memory.GetFelt(prevLocs.AddUint(LOOP_LOCALS_EXP))
 	if err != nil {
 		return err
 	}
 
-	return ids.Insert("locs", NewMaybeRelocatableFelt(prev_locs_exp.And(FeltOne())), vm)
+	return vm.Segments.Memory.Insert(locs.AddUint(LOOP_LOCALS_BIT), NewMaybeRelocatableFelt(prevLocsExp.And(FeltOne())))
 }
@@ -5,6 +5,7 @@ import (
 	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 	"testing"
@@ -75,3 +76,71 @@ func TestPowHintEvenOk(t *testing.T) {
 		t.Errorf("locs.bit: %d != 0", locs)
 	}
 }
+
+func TestPowHintApTrackingReferences(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	// prev_locs was laid out 5 cells before locs, ap advanced by one cell since the references were created
+	vm.RunContext.Ap = NewRelocatable(0, 11)
+	vm.Segments.Memory.Insert(NewRelocatable(0, 9), NewMaybeRelocatableFelt(FeltFromUint64(7)))
+
+	idsManager := NewIdsManager(
+		map[string]HintReference{
+			"prev_locs": ParseHintReference(parser.Reference{
+				ApTrackingData: parser.ApTrackingData{Group: 1, Offset: 3},
+				Value:          "cast(ap + (-5), pow.LoopLocals*)",
+			}),
+			"locs": ParseHintReference(parser.Reference{
+				ApTrackingData: parser.ApTrackingData{Group: 1, Offset: 3},
+				Value:          "cast(ap, pow.LoopLocals*)",
+			}),
+		},
+		parser.ApTrackingData{Group: 1, Offset: 4},
+		[]string{},
+	)
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: POW,
+	})
+
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+	if err != nil {
+		t.Errorf("POW hint test failed with error %s", err)
+	}
+
+	bit, err := vm.Segments.Memory.GetFelt(NewRelocatable(0, 10))
+	if err != nil || bit != FeltOne() {
+		t.Errorf("Wrong locs.bit: %v, err: %v", bit, err)
+	}
+}
+
+func TestPowHintLocsPointerInMemory(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.Memory.Insert(NewRelocatable(1, 4), NewMaybeRelocatableFelt(FeltFromUint64(5)))
+
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"prev_locs": {NewMaybeRelocatableRelocatable(NewRelocatable(1, 0))},
+			"locs":      {NewMaybeRelocatableRelocatable(NewRelocatable(1, 5))},
+		},
+		vm,
+	)
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: POW,
+	})
+
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+	if err != nil {
+		t.Errorf("POW hint test failed with error %s", err)
+	}
+
+	bit, err := vm.Segments.Memory.GetFelt(NewRelocatable(1, 5))
+	if err != nil || bit != FeltOne() {
+		t.Errorf("Wrong locs.bit: %v, err: %v", bit, err)
+	}
+}
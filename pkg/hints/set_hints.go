@@ -6,7 +6,6 @@ import (
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 	"github.com/pkg/errors"
-	"reflect"
 )
 
 /*
@@ -44,32 +43,28 @@ func setAdd(ids IdsManager, vm *VirtualMachine) error {
 		return err
 	}
 
-	if elmSizeFelt.IsZero() {
-		return errors.Errorf("assert ids.elm_size > 0")
-	}
-
 	elmSize, err := elmSizeFelt.ToUint()
-
-	if err != nil {
-		return err
+	if err != nil || elmSize == 0 {
+		return errors.Errorf("assert ids.elm_size > 0, got: %s", elmSizeFelt.ToSignedFeltString())
 	}
 
-	if setPtr.Offset > setEndPtr.Offset {
-		return errors.Errorf("expected set_ptr: %v <= set_end_ptr: %v", setPtr, setEndPtr)
+	if setPtr.SegmentIndex != setEndPtr.SegmentIndex || setPtr.Offset > setEndPtr.Offset {
+		return errors.Errorf("expected set_ptr: %s <= set_end_ptr: %s", setPtr.ToString(), setEndPtr.ToString())
 	}
 
-	elem, err := vm.Segments.Memory.GetRange(elmPtr, elmSize)
-	if err != nil {
+	// The element has to be readable even if the set is empty
+	if _, err := vm.Segments.Memory.GetRange(elmPtr, elmSize); err != nil {
 		return err
 	}
 
-	for i := uint(0); i < setEndPtr.Offset-setPtr.Offset-elmSize; i++ {
-		otherElm, err := vm.Segments.Memory.GetRange(setPtr.AddUint(i*elmSize), elmSize)
+	setSize := setEndPtr.Offset - setPtr.Offset
+	for i := uint(0); i < setSize; i += elmSize {
+		isEqual, err := vm.Segments.Memory.MemEq(elmPtr, setPtr.AddUint(i), elmSize)
 		if err != nil {
 			return err
 		}
-		if reflect.DeepEqual(elem, otherElm) {
-			err := ids.Insert("index", NewMaybeRelocatableFelt(FeltFromUint(i)), vm)
+		if isEqual {
+			err := ids.Insert("index", NewMaybeRelocatableFelt(FeltFromUint(i/elmSize)), vm)
 			if err != nil {
 				return err
 			}
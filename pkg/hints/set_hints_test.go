@@ -102,3 +102,92 @@ func TestSetAddElmNotInSet(t *testing.T) {
 		t.Errorf("Expected is_elm_in_set to be 1, got: %s", isElmInSet.ToSignedFeltString())
 	}
 }
+
+// Vm with the set [(1, 1), (2, 3)] at (1, 0) & the element at (2, 0), with elements of 2 felts
+func setAddVm(elm []uint64, setEndPtr Relocatable, elmSize Felt) (*VirtualMachine, IdsManager) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	for i, value := range []uint64{1, 1, 2, 3} {
+		vm.Segments.Memory.Insert(NewRelocatable(1, uint(i)), NewMaybeRelocatableFelt(FeltFromUint64(value)))
+	}
+	for i, value := range elm {
+		vm.Segments.Memory.Insert(NewRelocatable(2, uint(i)), NewMaybeRelocatableFelt(FeltFromUint64(value)))
+	}
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"elm_ptr":       {NewMaybeRelocatableRelocatable(NewRelocatable(2, 0))},
+			"set_ptr":       {NewMaybeRelocatableRelocatable(NewRelocatable(1, 0))},
+			"set_end_ptr":   {NewMaybeRelocatableRelocatable(setEndPtr)},
+			"elm_size":      {NewMaybeRelocatableFelt(elmSize)},
+			"index":         {nil},
+			"is_elm_in_set": {nil},
+		},
+		vm,
+	)
+	return vm, idsManager
+}
+
+func executeSetAdd(vm *VirtualMachine, idsManager IdsManager) error {
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: SET_ADD,
+	})
+	return hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+}
+
+func TestSetAddMultiFeltElmInSet(t *testing.T) {
+	vm, idsManager := setAddVm([]uint64{2, 3}, NewRelocatable(1, 4), FeltFromUint64(2))
+	if err := executeSetAdd(vm, idsManager); err != nil {
+		t.Fatalf("SET_ADD failed with error: %s", err)
+	}
+	isElmInSet, _ := idsManager.GetFelt("is_elm_in_set", vm)
+	index, _ := idsManager.GetFelt("index", vm)
+	if !isElmInSet.IsOne() || !index.IsOne() {
+		t.Errorf("Expected the element at index 1, got is_elm_in_set: %s, index: %s", isElmInSet.ToSignedFeltString(), index.ToSignedFeltString())
+	}
+}
+
+func TestSetAddMultiFeltElmNotInSet(t *testing.T) {
+	// (1, 2) shares its felts with the end of the first element & the start of the second one
+	vm, idsManager := setAddVm([]uint64{1, 2}, NewRelocatable(1, 4), FeltFromUint64(2))
+	if err := executeSetAdd(vm, idsManager); err != nil {
+		t.Fatalf("SET_ADD failed with error: %s", err)
+	}
+	isElmInSet, _ := idsManager.GetFelt("is_elm_in_set", vm)
+	if !isElmInSet.IsZero() {
+		t.Errorf("Expected is_elm_in_set to be 0, got: %s", isElmInSet.ToSignedFeltString())
+	}
+	if index, err := idsManager.GetFelt("index", vm); err == nil {
+		t.Errorf("Expected index not to be written, got: %s", index.ToSignedFeltString())
+	}
+}
+
+func TestSetAddEmptySet(t *testing.T) {
+	vm, idsManager := setAddVm([]uint64{1, 1}, NewRelocatable(1, 0), FeltFromUint64(2))
+	if err := executeSetAdd(vm, idsManager); err != nil {
+		t.Fatalf("SET_ADD failed with error: %s", err)
+	}
+	isElmInSet, _ := idsManager.GetFelt("is_elm_in_set", vm)
+	if !isElmInSet.IsZero() {
+		t.Errorf("Expected is_elm_in_set to be 0, got: %s", isElmInSet.ToSignedFeltString())
+	}
+}
+
+func TestSetAddInvalidElmSize(t *testing.T) {
+	for _, elmSize := range []Felt{FeltZero(), FeltFromDecString("-2")} {
+		vm, idsManager := setAddVm([]uint64{2, 3}, NewRelocatable(1, 4), elmSize)
+		if err := executeSetAdd(vm, idsManager); err == nil {
+			t.Errorf("SET_ADD should fail with elm_size %s", elmSize.ToSignedFeltString())
+		}
+	}
+}
+
+func TestSetAddSetPtrAfterSetEndPtr(t *testing.T) {
+	vm, idsManager := setAddVm([]uint64{2, 3}, NewRelocatable(0, 4), FeltFromUint64(2))
+	if err := executeSetAdd(vm, idsManager); err == nil {
+		t.Error("SET_ADD should fail if set_end_ptr is in another segment")
+	}
+}
@@ -70,14 +70,8 @@ func squashDict(ids IdsManager, scopes *ExecutionScopes, vm *VirtualMachine) err
 	if err != nil {
 		return err
 	}
-	squashDictMaxSize, err := scopes.Get("__squash_dict_max_size")
-	if err == nil {
-		maxSize, ok := squashDictMaxSize.(uint64)
-		if ok {
-			if nAccesses > maxSize {
-				return errors.Errorf("squash_dict() can only be used with n_accesses<=%d.\nGot: n_accesses=%d.", maxSize, nAccesses)
-			}
-		}
+	if err := checkMaxSize(scopes, SQUASH_DICT_MAX_SIZE, nAccesses, "squash_dict() can only be used with n_accesses<=%d.\nGot: n_accesses=%d."); err != nil {
+		return err
 	}
 	// A map from key to the list of indices accessing it.
 	accessIndices := make(map[MaybeRelocatable][]int)
@@ -301,7 +295,9 @@ func squashDictInnerNextKey(ids IdsManager, scopes *ExecutionScopes, vm *Virtual
 	}
 	key := keys[len(keys)-1]
 	keys = keys[:len(keys)-1]
-	ids.Insert("next_key", &key, vm)
+	if err := ids.Insert("next_key", &key, vm); err != nil {
+		return err
+	}
 	// Update scope variables
 	scopes.AssignOrUpdateVariable("keys", keys)
 	scopes.AssignOrUpdateVariable("key", key)
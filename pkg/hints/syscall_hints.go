@@ -0,0 +1,107 @@
+package hints
+
+import (
+	"strings"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Executes the Starknet syscalls requested by contracts through the `syscall_handler` hints
+// Sequencers integrate with the vm by implementing it and setting it as the CairoVmHintProcessor's SyscallHandler
+// The hints of the Starknet OS go through OsSyscallHandler, which extends it
+type SyscallHandler interface {
+	// Executes the syscall with the given name (as in `syscall_handler.<name>(...)`)
+	// Its request struct starts at syscallPtr, and its response struct (if it has one) has to be written right after it,
+	// DeprecatedSyscallRequestSize gives the size of the request
+	ExecuteSyscall(vm *VirtualMachine, name string, syscallPtr Relocatable) error
+}
+
+type syscallResponseField int
+
+const (
+	feltResponseField syscallResponseField = iota
+	pointerResponseField
+)
+
+type deprecatedSyscall struct {
+	requestSize uint
+	response    []syscallResponseField
+}
+
+// Request sizes & response layouts of the structs in starkware/starknet/common/syscalls.cairo
+var deprecatedSyscalls = map[string]deprecatedSyscall{
+	// response: retdata_size, retdata
+	"call_contract":           {5, []syscallResponseField{feltResponseField, pointerResponseField}},
+	"library_call":            {5, []syscallResponseField{feltResponseField, pointerResponseField}},
+	"library_call_l1_handler": {5, []syscallResponseField{feltResponseField, pointerResponseField}},
+	"delegate_call":           {5, []syscallResponseField{feltResponseField, pointerResponseField}},
+	"delegate_l1_handler":     {5, []syscallResponseField{feltResponseField, pointerResponseField}},
+	// response: contract_address, constructor_retdata_size, constructor_retdata
+	"deploy":                {6, []syscallResponseField{feltResponseField, feltResponseField, pointerResponseField}},
+	"emit_event":            {5, nil},
+	"get_block_number":      {1, []syscallResponseField{feltResponseField}},
+	"get_block_timestamp":   {1, []syscallResponseField{feltResponseField}},
+	"get_caller_address":    {1, []syscallResponseField{feltResponseField}},
+	"get_contract_address":  {1, []syscallResponseField{feltResponseField}},
+	"get_sequencer_address": {1, []syscallResponseField{feltResponseField}},
+	// response: tx_info
+	"get_tx_info": {1, []syscallResponseField{pointerResponseField}},
+	// response: signature_len, signature
+	"get_tx_signature":   {1, []syscallResponseField{feltResponseField, pointerResponseField}},
+	"replace_class":      {2, nil},
+	"send_message_to_l1": {4, nil},
+	"storage_read":       {2, []syscallResponseField{feltResponseField}},
+	"storage_write":      {3, nil},
+}
+
+var ErrUnknownSyscall = errors.New("Unknown syscall")
+
+// Returns the size of the request struct of the syscall with the given name
+func DeprecatedSyscallRequestSize(name string) (uint, error) {
+	syscall, ok := deprecatedSyscalls[name]
+	if !ok {
+		return 0, errors.Wrapf(ErrUnknownSyscall, "%s", name)
+	}
+	return syscall.requestSize, nil
+}
+
+// SyscallHandler used when none is set: syscalls have no effect, and their responses are filled with zeros
+// (and empty segments for the pointers to arrays or structs)
+type NoopSyscallHandler struct{}
+
+func (h NoopSyscallHandler) ExecuteSyscall(vm *VirtualMachine, name string, syscallPtr Relocatable) error {
+	syscall, ok := deprecatedSyscalls[name]
+	if !ok {
+		return errors.Wrapf(ErrUnknownSyscall, "%s", name)
+	}
+	responsePtr := NewRelocatable(syscallPtr.SegmentIndex, syscallPtr.Offset+syscall.requestSize)
+	for i, field := range syscall.response {
+		value := NewMaybeRelocatableFelt(FeltZero())
+		if field == pointerResponseField {
+			value = NewMaybeRelocatableRelocatable(vm.Segments.AddSegment())
+		}
+		err := vm.Segments.Memory.Insert(NewRelocatable(responsePtr.SegmentIndex, responsePtr.Offset+uint(i)), value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Implements hints:
+// %{ syscall_handler.<name>(segments=segments, syscall_ptr=ids.syscall_ptr) %}
+func executeDeprecatedSyscall(ids IdsManager, vm *VirtualMachine, code string, syscallHandler SyscallHandler) error {
+	syscallPtr, err := ids.GetRelocatable("syscall_ptr", vm)
+	if err != nil {
+		return err
+	}
+	name := strings.TrimPrefix(code[:strings.Index(code, "(")], "syscall_handler.")
+	if syscallHandler == nil {
+		syscallHandler = NoopSyscallHandler{}
+	}
+	return syscallHandler.ExecuteSyscall(vm, name, syscallPtr)
+}
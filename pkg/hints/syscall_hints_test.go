@@ -0,0 +1,147 @@
+package hints_test
+
+import (
+	"testing"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/types"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Executes the syscall hint with ids.syscall_ptr = (2, 0)
+func executeSyscallHint(t *testing.T, vm *VirtualMachine, code string, hintProcessor CairoVmHintProcessor) error {
+	t.Helper()
+	idsManager := SetupIdsForTest(
+		map[string][]*MaybeRelocatable{
+			"syscall_ptr": {NewMaybeRelocatableRelocatable(NewRelocatable(2, 0))},
+		},
+		vm,
+	)
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: code,
+	})
+	return hintProcessor.ExecuteHint(vm, &hintData, nil, NewExecutionScopes())
+}
+
+func TestStorageReadNoopSyscallHandler(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	err := executeSyscallHint(t, vm, STORAGE_READ, CairoVmHintProcessor{})
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	value, err := vm.Segments.Memory.GetFelt(NewRelocatable(2, 2))
+	if err != nil || !value.IsZero() {
+		t.Errorf("Expected a zero response, got: %s, %v", value.ToSignedFeltString(), err)
+	}
+}
+
+func TestStorageWriteNoopSyscallHandler(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	err := executeSyscallHint(t, vm, STORAGE_WRITE, CairoVmHintProcessor{})
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	if _, err := vm.Segments.Memory.Get(NewRelocatable(2, 3)); err == nil {
+		t.Error("storage_write has no response, nothing should have been written")
+	}
+}
+
+func TestDeployNoopSyscallHandler(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	err := executeSyscallHint(t, vm, DEPLOY, CairoVmHintProcessor{})
+	if err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	for _, addr := range []Relocatable{NewRelocatable(2, 6), NewRelocatable(2, 7)} {
+		value, err := vm.Segments.Memory.GetFelt(addr)
+		if err != nil || !value.IsZero() {
+			t.Errorf("Expected a zero at %s, got: %s, %v", addr.ToString(), value.ToSignedFeltString(), err)
+		}
+	}
+	retdata, err := vm.Segments.Memory.GetRelocatable(NewRelocatable(2, 8))
+	if err != nil || retdata != NewRelocatable(3, 0) {
+		t.Errorf("Expected the constructor retdata to be an empty segment, got: %s, %v", retdata.ToString(), err)
+	}
+}
+
+type storageSyscallHandler struct {
+	storage map[Felt]Felt
+	calls   []string
+}
+
+func (h *storageSyscallHandler) ExecuteSyscall(vm *VirtualMachine, name string, syscallPtr Relocatable) error {
+	h.calls = append(h.calls, name)
+	address, err := vm.Segments.Memory.GetFelt(NewRelocatable(syscallPtr.SegmentIndex, syscallPtr.Offset+1))
+	if err != nil {
+		return err
+	}
+	switch name {
+	case "storage_write":
+		value, err := vm.Segments.Memory.GetFelt(NewRelocatable(syscallPtr.SegmentIndex, syscallPtr.Offset+2))
+		if err != nil {
+			return err
+		}
+		h.storage[address] = value
+		return nil
+	case "storage_read":
+		requestSize, err := DeprecatedSyscallRequestSize(name)
+		if err != nil {
+			return err
+		}
+		responsePtr := NewRelocatable(syscallPtr.SegmentIndex, syscallPtr.Offset+requestSize)
+		return vm.Segments.Memory.Insert(responsePtr, NewMaybeRelocatableFelt(h.storage[address]))
+	}
+	return nil
+}
+
+func TestStorageCustomSyscallHandler(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	// storage_write request: selector, address, value
+	vm.Segments.Memory.Insert(NewRelocatable(2, 1), NewMaybeRelocatableFelt(FeltFromUint64(7)))
+	vm.Segments.Memory.Insert(NewRelocatable(2, 2), NewMaybeRelocatableFelt(FeltFromUint64(42)))
+	handler := &storageSyscallHandler{storage: map[Felt]Felt{}}
+	hintProcessor := CairoVmHintProcessor{SyscallHandler: handler}
+	if err := executeSyscallHint(t, vm, STORAGE_WRITE, hintProcessor); err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+
+	// storage_read request: selector, address, then its response
+	vm.Segments = NewMemorySegmentManager()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+	vm.Segments.Memory.Insert(NewRelocatable(2, 1), NewMaybeRelocatableFelt(FeltFromUint64(7)))
+	if err := executeSyscallHint(t, vm, STORAGE_READ, hintProcessor); err != nil {
+		t.Fatalf("failed with error %s", err)
+	}
+	value, err := vm.Segments.Memory.GetFelt(NewRelocatable(2, 2))
+	if err != nil || value != FeltFromUint64(42) {
+		t.Errorf("Expected storage_read to return 42, got: %s, %v", value.ToSignedFeltString(), err)
+	}
+	if len(handler.calls) != 2 || handler.calls[0] != "storage_write" || handler.calls[1] != "storage_read" {
+		t.Errorf("Wrong syscalls: %v", handler.calls)
+	}
+}
+
+func TestDeprecatedSyscallRequestSizeUnknown(t *testing.T) {
+	if _, err := DeprecatedSyscallRequestSize("unknown"); err == nil {
+		t.Error("DeprecatedSyscallRequestSize should have failed")
+	}
+}
@@ -187,9 +187,9 @@ func uint256SignedNN(ids IdsManager, vm *VirtualMachine) error {
 	}
 	i128Max := FeltFromDecString("170141183460469231731687303715884105727")
 	if a.High.Cmp(FeltZero()) != -1 && a.High.Cmp(i128Max) != 1 {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltOne()))
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltOne()))
 	} else {
-		return vm.Segments.Memory.Insert(vm.RunContext.Ap, NewMaybeRelocatableFelt(FeltZero()))
+		return vm.InsertAtAp(0, NewMaybeRelocatableFelt(FeltZero()))
 	}
 }
 
@@ -246,6 +246,9 @@ func uint256OfssetedUnisgnedDivRem(ids IdsManager, vm *VirtualMachine, divOffset
 		return err
 	}
 	div := Uint256{Low: divLow, High: divHigh}
+	if div.IsZero() {
+		return DividedByZeroError(a.ToBigInt())
+	}
 	q, r := new(big.Int).DivMod(a.ToBigInt(), div.ToBigInt(), new(big.Int))
 
 	err = ids.InsertUint256("quotient", ToUint256(q), vm)
@@ -285,11 +288,10 @@ func uint256MulDivMod(ids IdsManager, vm *VirtualMachine) error {
 		return err
 	}
 
-	if div.ToBigInt().Cmp(big.NewInt(0)) == 0 {
-		return errors.Errorf("Attempted to divide by zero")
-	}
-
 	mul := new(big.Int).Mul(a.ToBigInt(), b.ToBigInt())
+	if div.IsZero() {
+		return DividedByZeroError(mul)
+	}
 	quotient, rem := new(big.Int).DivMod(mul, div.ToBigInt(), new(big.Int))
 
 	maxU128, _ := new(big.Int).SetString("340282366920938463463374607431768211455", 10)
@@ -1,6 +1,7 @@
 package hints_test
 
 import (
+	"errors"
 	"math/big"
 	"testing"
 
@@ -477,6 +478,35 @@ func TestUint256UnsignedDivRemInvalidMemoryInsert(t *testing.T) {
 	}
 }
 
+func TestUint256UnsignedDivRemDivByZero(t *testing.T) {
+	vm := NewVirtualMachine()
+	vm.Segments.AddSegment()
+	vm.Segments.AddSegment()
+
+	ids := map[string][]*MaybeRelocatable{
+		"a": {
+			NewMaybeRelocatableFeltFromUint64(89),
+			NewMaybeRelocatableFeltFromUint64(72),
+		},
+		"div": {
+			NewMaybeRelocatableFelt(FeltZero()),
+			NewMaybeRelocatableFelt(FeltZero()),
+		},
+		"quotient":  {nil, nil},
+		"remainder": {nil, nil},
+	}
+	idsManager := SetupIdsForTest(ids, vm)
+	hintData := any(HintData{
+		Ids:  idsManager,
+		Code: UINT256_UNSIGNED_DIV_REM,
+	})
+	hintProcessor := CairoVmHintProcessor{}
+	err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil)
+	if !errors.Is(err, ErrDividedByZero) {
+		t.Errorf("Expected ErrDividedByZero, got: %v", err)
+	}
+}
+
 func TestUint256ExpandedUnsignedDivRemOk(t *testing.T) {
 	vm := NewVirtualMachine()
 	vm.Segments.AddSegment()
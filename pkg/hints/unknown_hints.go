@@ -0,0 +1,75 @@
+package hints
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Unknown hint found during a run, see CairoVmHintProcessor.UnknownHints
+type UnknownHint struct {
+	Code string
+	// Pcs the hint was found at, in the order they were first reached
+	Pcs []memory.Relocatable
+	// Number of times the hint was reached
+	Count uint
+}
+
+// Collects the hints that the hint processor doesn't implement, so that they can be reported at the end of the run
+// instead of aborting it at the first one. It can be shared by concurrent runs
+type UnknownHintReport struct {
+	mutex sync.Mutex
+	hints map[string]*UnknownHint
+	order []string
+}
+
+func NewUnknownHintReport() *UnknownHintReport {
+	return &UnknownHintReport{hints: make(map[string]*UnknownHint)}
+}
+
+// Records that the hint with the given code was reached at pc
+func (r *UnknownHintReport) Record(code string, pc memory.Relocatable) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	hint, ok := r.hints[code]
+	if !ok {
+		hint = &UnknownHint{Code: code}
+		r.hints[code] = hint
+		r.order = append(r.order, code)
+	}
+	hint.Count++
+	for _, hintPc := range hint.Pcs {
+		if hintPc == pc {
+			return
+		}
+	}
+	hint.Pcs = append(hint.Pcs, pc)
+}
+
+// Returns the unknown hints, sorted by the first pc they were found at
+func (r *UnknownHintReport) Hints() []UnknownHint {
+	r.mutex.Lock()
+	hints := make([]UnknownHint, 0, len(r.order))
+	for _, code := range r.order {
+		hint := *r.hints[code]
+		hint.Pcs = append([]memory.Relocatable(nil), hint.Pcs...)
+		hints = append(hints, hint)
+	}
+	r.mutex.Unlock()
+	sort.SliceStable(hints, func(i, j int) bool {
+		lhs, rhs := hints[i].Pcs[0], hints[j].Pcs[0]
+		if lhs.SegmentIndex != rhs.SegmentIndex {
+			return lhs.SegmentIndex < rhs.SegmentIndex
+		}
+		return lhs.Offset < rhs.Offset
+	})
+	return hints
+}
+
+// Number of distinct unknown hints found
+func (r *UnknownHintReport) Len() int {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return len(r.hints)
+}
@@ -0,0 +1,50 @@
+package hints_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	. "github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	. "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestUnknownHintsAreReported(t *testing.T) {
+	vm := NewVirtualMachine()
+	report := NewUnknownHintReport()
+	hintProcessor := CairoVmHintProcessor{UnknownHints: report}
+	first := any(HintData{Code: "print('first')"})
+	second := any(HintData{Code: "print('second')"})
+
+	for _, pc := range []uint{7, 2, 7} {
+		vm.RunContext.Pc = NewRelocatable(0, pc)
+		if err := hintProcessor.ExecuteHint(vm, &first, nil, nil); err != nil {
+			t.Fatalf("Unknown hints should be skipped, got: %s", err)
+		}
+	}
+	vm.RunContext.Pc = NewRelocatable(0, 1)
+	if err := hintProcessor.ExecuteHint(vm, &second, nil, nil); err != nil {
+		t.Fatalf("Unknown hints should be skipped, got: %s", err)
+	}
+
+	expected := []UnknownHint{
+		{Code: "print('second')", Pcs: []Relocatable{NewRelocatable(0, 1)}, Count: 1},
+		{Code: "print('first')", Pcs: []Relocatable{NewRelocatable(0, 7), NewRelocatable(0, 2)}, Count: 3},
+	}
+	if got := report.Hints(); !reflect.DeepEqual(got, expected) {
+		t.Errorf("Wrong report, expected %v, got %v", expected, got)
+	}
+	if report.Len() != 2 {
+		t.Errorf("Expected 2 unknown hints, got %d", report.Len())
+	}
+}
+
+func TestUnknownHintsFailWithoutReport(t *testing.T) {
+	vm := NewVirtualMachine()
+	hintProcessor := CairoVmHintProcessor{}
+	hintData := any(HintData{Code: "print('hello')"})
+	if err := hintProcessor.ExecuteHint(vm, &hintData, nil, nil); !errors.Is(err, ErrUnknownHint) {
+		t.Errorf("Expected ErrUnknownHint, got: %v", err)
+	}
+}
@@ -1,7 +1,6 @@
 package hints
 
 import (
-	"fmt"
 	"sort"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
@@ -27,21 +26,15 @@ func (s SortFelt) Less(i, j int) bool {
 // Implements hint:
 // %{ vm_enter_scope(dict(__usort_max_size = globals().get('__usort_max_size'))) %}
 func usortEnterScope(executionScopes *types.ExecutionScopes) error {
-	usort_max_size_interface, err := executionScopes.Get("usort_max_size")
-
+	usortMaxSize, ok, err := getMaxSize(executionScopes, USORT_MAX_SIZE)
 	if err != nil {
-		executionScopes.EnterScope(make(map[string]interface{}))
-		return nil
-	}
-
-	usort_max_size, cast_ok := usort_max_size_interface.(uint64)
-
-	if !cast_ok {
-		return errors.New("Error casting usort_max_size into a uint64")
+		return err
 	}
 
 	scope := make(map[string]interface{})
-	scope["usort_max_size"] = usort_max_size
+	if ok {
+		scope[USORT_MAX_SIZE] = usortMaxSize
+	}
 	executionScopes.EnterScope(scope)
 
 	return nil
@@ -65,31 +58,19 @@ func usortBody(ids IdsManager, executionScopes *types.ExecutionScopes, vm *Virtu
 		return err
 	}
 
-	usort_max_size, err := executionScopes.Get("usort_max_size")
-
-	if err == nil {
-		usort_max_size_u64, cast_ok := usort_max_size.(uint64)
-
-		if !cast_ok {
-			return errors.New("Error casting usort_max_size into a uint64")
-		}
-
-		if input_len_u64 > usort_max_size_u64 {
-			return errors.New(fmt.Sprintf("usort() can only be used with input_len<= %v. Got: input_len=%v.", usort_max_size_u64, input_len_u64))
-		}
+	err = checkMaxSize(executionScopes, USORT_MAX_SIZE, input_len_u64, "usort() can only be used with input_len<= %v. Got: input_len=%v.")
+	if err != nil {
+		return err
 	}
 
 	positions_dict := make(map[lambdaworks.Felt][]uint64)
 
-	for i := uint64(0); i < input_len_u64; i++ {
-
-		val, err := vm.Segments.Memory.GetFelt(input_ptr.AddUint(uint(i)))
-
-		if err != nil {
-			return err
-		}
-
-		positions_dict[val] = append(positions_dict[val], i)
+	input, err := vm.Segments.GetFeltRange(input_ptr, uint(input_len_u64))
+	if err != nil {
+		return err
+	}
+	for i, val := range input {
+		positions_dict[val] = append(positions_dict[val], uint64(i))
 	}
 	executionScopes.AssignOrUpdateVariable("positions_dict", positions_dict)
 
@@ -110,28 +91,24 @@ func usortBody(ids IdsManager, executionScopes *types.ExecutionScopes, vm *Virtu
 
 	output_base := vm.Segments.AddSegment()
 
+	output_data := make([]memory.MaybeRelocatable, 0, len(output))
 	for i := range output {
-		err = vm.Segments.Memory.Insert(output_base.AddUint(uint(i)), memory.NewMaybeRelocatableFelt(output[i]))
-
-		if err != nil {
-			return err
-		}
+		output_data = append(output_data, *memory.NewMaybeRelocatableFelt(output[i]))
+	}
+	_, err = vm.Segments.WriteArg(output_base, output_data)
+	if err != nil {
+		return err
 	}
 
 	multiplicities_base := vm.Segments.AddSegment()
 
-	multiplicities := make([]uint64, 0, len(output))
-
+	multiplicities := make([]memory.MaybeRelocatable, 0, len(output))
 	for key := range output {
-		multiplicities = append(multiplicities, uint64(len(positions_dict[output[key]])))
+		multiplicities = append(multiplicities, *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(uint64(len(positions_dict[output[key]])))))
 	}
-
-	for i := range multiplicities {
-		err = vm.Segments.Memory.Insert(multiplicities_base.AddUint(uint(i)), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(multiplicities[i])))
-
-		if err != nil {
-			return err
-		}
+	_, err = vm.Segments.WriteArg(multiplicities_base, multiplicities)
+	if err != nil {
+		return err
 	}
 
 	err = ids.Insert("output", memory.NewMaybeRelocatableRelocatable(output_base), vm)
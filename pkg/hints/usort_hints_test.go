@@ -45,7 +45,8 @@ func TestUsortWithMaxSize(t *testing.T) {
 		t.Errorf("USORT_ENTER_SCOPE hint execution failed")
 	}
 
-	usort_max_size_interface, err := scopes.Get("usort_max_size")
+	// The limit is forwarded to the new scope under its cairo-lang name
+	usort_max_size_interface, err := scopes.Get(USORT_MAX_SIZE)
 
 	if err != nil {
 		t.Errorf("Error assigning usort_max_size")
@@ -0,0 +1,64 @@
+package keccak_utils
+
+import (
+	"encoding/binary"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Dimensions of the keccak-f[1600] state, as used by cairo-lang's keccak_utils
+const (
+	BYTES_IN_WORD             = 8
+	KECCAK_STATE_SIZE_FELTS   = 25
+	KECCAK_STATE_SIZE_BYTES   = KECCAK_STATE_SIZE_FELTS * BYTES_IN_WORD
+	KECCAK_CAPACITY_IN_WORDS  = 8
+	KECCAK_FULL_RATE_IN_WORDS = KECCAK_STATE_SIZE_FELTS - KECCAK_CAPACITY_IN_WORDS
+	KECCAK_FULL_RATE_IN_BYTES = KECCAK_FULL_RATE_IN_WORDS * BYTES_IN_WORD
+)
+
+// Reads n consecutive felts starting at addr, failing if any of them doesn't fit in a u64
+func U64ArrayFromMemory(mem *memory.Memory, addr memory.Relocatable, n uint) ([]uint64, error) {
+	felts, err := mem.GetFeltRange(addr, n)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]uint64, 0, n)
+	for i, felt := range felts {
+		value, err := felt.ToU64()
+		if err != nil {
+			valueAddr := addr.AddUint(uint(i))
+			return nil, errors.Errorf("Expected value at %s to fit in a u64", valueAddr.ToString())
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// Writes the values as felts starting at addr, returns the address after the last value written
+func U64ArrayToMemory(segments *memory.MemorySegmentManager, addr memory.Relocatable, values []uint64) (memory.Relocatable, error) {
+	data := make([]memory.MaybeRelocatable, 0, len(values))
+	for _, value := range values {
+		data = append(data, *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(value)))
+	}
+	return segments.WriteArg(addr, data)
+}
+
+// Converts the little endian bytes of a keccak state into its u64 lanes
+func StateFromLeBytes(bytes *[KECCAK_STATE_SIZE_BYTES]byte) [KECCAK_STATE_SIZE_FELTS]uint64 {
+	var state [KECCAK_STATE_SIZE_FELTS]uint64
+	for i := range state {
+		state[i] = binary.LittleEndian.Uint64(bytes[BYTES_IN_WORD*i : BYTES_IN_WORD*(i+1)])
+	}
+	return state
+}
+
+// Converts the u64 lanes of a keccak state into its little endian bytes
+func StateToLeBytes(state *[KECCAK_STATE_SIZE_FELTS]uint64) [KECCAK_STATE_SIZE_BYTES]byte {
+	var bytes [KECCAK_STATE_SIZE_BYTES]byte
+	for i, lane := range state {
+		binary.LittleEndian.PutUint64(bytes[BYTES_IN_WORD*i:BYTES_IN_WORD*(i+1)], lane)
+	}
+	return bytes
+}
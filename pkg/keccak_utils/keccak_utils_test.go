@@ -0,0 +1,60 @@
+package keccak_utils_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/keccak_utils"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestU64ArrayToAndFromMemory(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	base := segments.AddSegment()
+	values := []uint64{0, 1, math.MaxUint64}
+
+	end, err := keccak_utils.U64ArrayToMemory(&segments, base, values)
+	if err != nil {
+		t.Fatalf("U64ArrayToMemory failed with error: %s", err)
+	}
+	if end != memory.NewRelocatable(0, 3) {
+		t.Errorf("Wrong end pointer: %v", end)
+	}
+
+	read, err := keccak_utils.U64ArrayFromMemory(&segments.Memory, base, 3)
+	if err != nil {
+		t.Fatalf("U64ArrayFromMemory failed with error: %s", err)
+	}
+	for i := range values {
+		if read[i] != values[i] {
+			t.Errorf("Wrong value at index %d. Expected %d, got %d", i, values[i], read[i])
+		}
+	}
+}
+
+func TestU64ArrayFromMemoryValueTooBig(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	base := segments.AddSegment()
+	segments.Memory.Insert(base, memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne().Shl(64)))
+
+	_, err := keccak_utils.U64ArrayFromMemory(&segments.Memory, base, 1)
+	if err == nil {
+		t.Error("U64ArrayFromMemory should have failed")
+	}
+}
+
+func TestStateLeBytesRoundTrip(t *testing.T) {
+	var bytes [keccak_utils.KECCAK_STATE_SIZE_BYTES]byte
+	for i := range bytes {
+		bytes[i] = byte(i)
+	}
+
+	state := keccak_utils.StateFromLeBytes(&bytes)
+	if state[0] != 0x0706050403020100 {
+		t.Errorf("Wrong first lane: %x", state[0])
+	}
+	if keccak_utils.StateToLeBytes(&state) != bytes {
+		t.Error("Converting the state back to bytes should give the original bytes")
+	}
+}
@@ -0,0 +1,72 @@
+package lambdaworks
+
+/*
+#include "lib/lambdaworks.h"
+#include <stdlib.h>
+*/
+import "C"
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// Memory crossing the ffi is owned by whoever allocated it, and must be released by that side:
+//   - Buffers passed to lambdaworks (ie: strings to parse) are allocated with newCString & released with freeCString
+//   - Values returned by lambdaworks that aren't plain felts or limbs (ie: strings) are allocated by Rust, and must be
+//     copied into Go memory & released with its matching free function before returning (see takeRustString)
+//
+// No ffi memory outlives the binding that allocated it, so Go values never need finalizers. Bindings to new non-POD
+// returns must follow the same rule, so that Stats keeps accounting for every allocation
+
+// Allocations made through the ffi bindings since the process started, to detect leaks in long-running services
+type FfiStats struct {
+	// Buffers allocated by Go (C.CString) to pass values to lambdaworks
+	CAllocs uint64
+	CFrees  uint64
+	// Values allocated by lambdaworks (ie: to_signed_felt) & returned to Go
+	RustAllocs uint64
+	RustFrees  uint64
+}
+
+// Returns the number of ffi allocations not released yet
+// As every binding releases what it allocates before returning, it is only non-zero while bindings are running
+func (s FfiStats) Live() int64 {
+	return int64(s.CAllocs-s.CFrees) + int64(s.RustAllocs-s.RustFrees)
+}
+
+var ffiStats struct {
+	cAllocs, cFrees, rustAllocs, rustFrees atomic.Uint64
+}
+
+// Returns the allocations made through the ffi bindings so far
+func Stats() FfiStats {
+	// Frees are loaded before allocs, so that a concurrent binding can't make Live negative
+	cFrees, rustFrees := ffiStats.cFrees.Load(), ffiStats.rustFrees.Load()
+	return FfiStats{
+		CAllocs:    ffiStats.cAllocs.Load(),
+		CFrees:     cFrees,
+		RustAllocs: ffiStats.rustAllocs.Load(),
+		RustFrees:  rustFrees,
+	}
+}
+
+func newCString(value string) *C.char {
+	ffiStats.cAllocs.Add(1)
+	return C.CString(value)
+}
+
+func freeCString(cs *C.char) {
+	C.free(unsafe.Pointer(cs))
+	ffiStats.cFrees.Add(1)
+}
+
+// Copies a string allocated by lambdaworks into Go memory, and releases it
+func takeRustString(ptr *C.char) string {
+	ffiStats.rustAllocs.Add(1)
+	defer func() {
+		C.free_string(ptr)
+		ffiStats.rustFrees.Add(1)
+	}()
+	return C.GoString(ptr)
+}
@@ -23,10 +23,18 @@ const N_LIMBS_IN_FELT = 4
 type Limb C.limb_t
 
 // Go representation of a 256 bit prime field element (felt).
+// The limbs hold its canonical value, most significant first, never its Montgomery form (see CanonicalLimbs).
+// Code reading the limbs directly (ie: ToU64, IsZero) relies on this.
 type Felt struct {
 	limbs [N_LIMBS_IN_FELT]Limb
 }
 
+var ErrDividedByZero = errors.New("Division by zero")
+
+func DividedByZeroError(dividend *big.Int) error {
+	return LambdaworksError(errors.Wrapf(ErrDividedByZero, "%s / 0", dividend))
+}
+
 func LambdaworksError(err error) error {
 	return errors.Wrapf(err, "Lambdaworks Error")
 }
@@ -53,7 +61,7 @@ func fromC(result C.felt_t) Felt {
 	return Felt{limbs: limbs}
 }
 
-// Gets a Felt representing the "value" number, in Montgomery format.
+// Gets a Felt representing the "value" number (the felt holds its canonical value, see Felt.Limbs).
 func FeltFromUint64(value uint64) Felt {
 	var result C.felt_t
 	C.from(&result[0], C.uint64_t(value))
@@ -67,8 +75,8 @@ func FeltFromUint(value uint) Felt {
 }
 
 func FeltFromHex(value string) Felt {
-	cs := C.CString(value)
-	defer C.free(unsafe.Pointer(cs))
+	cs := newCString(value)
+	defer freeCString(cs)
 
 	var result C.felt_t
 	C.from_hex(&result[0], cs)
@@ -76,8 +84,8 @@ func FeltFromHex(value string) Felt {
 }
 
 func FeltFromDecString(value string) Felt {
-	cs := C.CString(value)
-	defer C.free(unsafe.Pointer(cs))
+	cs := newCString(value)
+	defer freeCString(cs)
 
 	var result C.felt_t
 	C.from_dec_str(&result[0], cs)
@@ -86,8 +94,8 @@ func FeltFromDecString(value string) Felt {
 
 // turns a felt to u64
 func (felt Felt) ToU64() (uint64, error) {
-	if felt.limbs[0] == 0 && felt.limbs[1] == 0 && felt.limbs[2] == 0 {
-		return uint64(felt.limbs[3]), nil
+	if limbs := felt.ToCanonical(); limbs[0] == 0 && limbs[1] == 0 && limbs[2] == 0 {
+		return limbs[3], nil
 	} else {
 		return 0, ConversionError(felt, "u64")
 	}
@@ -133,8 +141,8 @@ func (felt Felt) ToBeBytes() *[32]byte {
 
 func (felt Felt) ToHexString() string {
 	// We need to make sure enough space is allocated to fit the longest possible string
-	var result_c = C.CString(strings.Repeat(" ", 65))
-	defer C.free(unsafe.Pointer(result_c))
+	var result_c = newCString(strings.Repeat(" ", 65))
+	defer freeCString(result_c)
 
 	var value C.felt_t = felt.toC()
 	C.to_hex_string(result_c, &value[0])
@@ -177,8 +185,9 @@ func SignedFeltMaxValue() Felt {
 	return fromC(result)
 }
 
+// Zero is represented by all-zero canonical limbs, so there is no need to go through C
 func (f Felt) IsZero() bool {
-	return f == FeltZero()
+	return f == Felt{}
 }
 
 func (f Felt) IsPositive() bool {
@@ -217,7 +226,11 @@ func (a Felt) Mul(b Felt) Felt {
 }
 
 // Writes the result variable with a / b.
+// Panics with ErrDividedByZero if b is zero, see CheckedDiv
 func (a Felt) Div(b Felt) Felt {
+	if b.IsZero() {
+		panic(DividedByZeroError(a.ToBigInt()))
+	}
 	var result C.felt_t
 	var a_c C.felt_t = a.toC()
 	var b_c C.felt_t = b.toC()
@@ -228,10 +241,7 @@ func (a Felt) Div(b Felt) Felt {
 // Returns the felt
 func (f Felt) ToSignedFeltString() string {
 	var f_c = f.toC()
-	resultPtr := C.to_signed_felt(&f_c[0])
-	defer C.free_string(resultPtr)
-	goResult := C.GoString(resultPtr)
-	return goResult
+	return takeRustString(C.to_signed_felt(&f_c[0]))
 }
 
 // Returns the number of bits needed to represent the felt
@@ -292,6 +302,8 @@ func (a Felt) Pow(p Felt) Felt {
 	return fromC(result)
 }
 
+// Returns the smallest of the two square roots of a
+// a must be a quadratic residue, use CheckedSqrt when it may not be
 func (a Felt) Sqrt() Felt {
 	var result C.felt_t
 	var a_c C.felt_t = a.toC()
@@ -300,6 +312,35 @@ func (a Felt) Sqrt() Felt {
 	return fromC(result)
 }
 
+// Returns the Legendre symbol of a: 0 if a is zero, 1 if it is a quadratic residue & -1 otherwise
+// Computed as a^((PRIME - 1) / 2) (Euler's criterion)
+func (a Felt) LegendreSymbol() int {
+	if a.IsZero() {
+		return 0
+	}
+	if a.Pow(SignedFeltMaxValue()).IsOne() {
+		return 1
+	}
+	return -1
+}
+
+// Returns true if a has a square root, which is also the case for zero (ie: is_quad_residue)
+func (a Felt) IsQuadResidue() bool {
+	return a.LegendreSymbol() >= 0
+}
+
+// Returns the smallest of the two square roots of a, or false if a is not a quadratic residue
+func (a Felt) CheckedSqrt() (Felt, bool) {
+	switch a.LegendreSymbol() {
+	case 0:
+		return a, true
+	case 1:
+		return a.Sqrt(), true
+	default:
+		return Felt{}, false
+	}
+}
+
 func (a Felt) Shr(b uint) Felt {
 	var result C.felt_t
 	var a_c C.felt_t = a.toC()
@@ -343,7 +384,12 @@ func (f Felt) ToSigned() *big.Int {
 	return n
 }
 
+// Returns the quotient & remainder of the integer division of a by b
+// Panics with ErrDividedByZero if b is zero, see CheckedDivRem
 func (a Felt) DivRem(b Felt) (Felt, Felt) {
+	if b.IsZero() {
+		panic(DividedByZeroError(a.ToBigInt()))
+	}
 	var div C.felt_t
 	var rem C.felt_t
 	var a_c C.felt_t = a.toC()
@@ -352,6 +398,24 @@ func (a Felt) DivRem(b Felt) (Felt, Felt) {
 	return fromC(div), fromC(rem)
 }
 
+// Same as Div, failing with ErrDividedByZero if b is zero
+// Dividing by zero through the ffi would abort the process, so divisors that can be zero must go through this
+func (a Felt) CheckedDiv(b Felt) (Felt, error) {
+	if b.IsZero() {
+		return Felt{}, DividedByZeroError(a.ToBigInt())
+	}
+	return a.Div(b), nil
+}
+
+// Same as DivRem, failing with ErrDividedByZero if b is zero
+func (a Felt) CheckedDivRem(b Felt) (Felt, Felt, error) {
+	if b.IsZero() {
+		return Felt{}, Felt{}, DividedByZeroError(a.ToBigInt())
+	}
+	div, rem := a.DivRem(b)
+	return div, rem, nil
+}
+
 func (a Felt) ModFloor(b Felt) Felt {
 	_, rem := a.DivRem(b)
 	return rem
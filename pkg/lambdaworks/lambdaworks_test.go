@@ -1,7 +1,9 @@
 package lambdaworks_test
 
 import (
+	"errors"
 	"math/big"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -369,6 +371,38 @@ func TestFeltDiv4Error(t *testing.T) {
 	}
 }
 
+func TestFeltCheckedDiv(t *testing.T) {
+	result, err := lambdaworks.FeltFromUint64(4).CheckedDiv(lambdaworks.FeltFromUint64(2))
+	if err != nil || result != lambdaworks.FeltFromUint64(2) {
+		t.Errorf("TestFeltCheckedDiv failed. Expected: 2, Got: %v, %v", result, err)
+	}
+	_, err = lambdaworks.FeltFromUint64(4).CheckedDiv(lambdaworks.FeltZero())
+	if !errors.Is(err, lambdaworks.ErrDividedByZero) {
+		t.Errorf("TestFeltCheckedDiv failed. Expected ErrDividedByZero, Got: %v", err)
+	}
+}
+
+func TestFeltCheckedDivRem(t *testing.T) {
+	div, rem, err := lambdaworks.FeltFromUint64(7).CheckedDivRem(lambdaworks.FeltFromUint64(2))
+	if err != nil || div != lambdaworks.FeltFromUint64(3) || rem != lambdaworks.FeltOne() {
+		t.Errorf("TestFeltCheckedDivRem failed. Expected: (3, 1), Got: (%v, %v), %v", div, rem, err)
+	}
+	_, _, err = lambdaworks.FeltFromUint64(7).CheckedDivRem(lambdaworks.FeltZero())
+	if !errors.Is(err, lambdaworks.ErrDividedByZero) {
+		t.Errorf("TestFeltCheckedDivRem failed. Expected ErrDividedByZero, Got: %v", err)
+	}
+}
+
+func TestFeltDivByZeroPanics(t *testing.T) {
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, lambdaworks.ErrDividedByZero) {
+			t.Errorf("TestFeltDivByZeroPanics failed. Expected ErrDividedByZero, Got: %v", err)
+		}
+	}()
+	lambdaworks.FeltFromUint64(4).Div(lambdaworks.FeltZero())
+}
+
 func TestBits(t *testing.T) {
 	f_zero := lambdaworks.FeltZero()
 	if f_zero.Bits() != 0 {
@@ -739,3 +773,280 @@ func TestSignedMaxValue(t *testing.T) {
 	}
 
 }
+
+// Property tests: the felt operations are checked against the same operations on big.Int, reduced modulo the prime
+// They only rely on the exported Felt api, so they also apply to any other felt backend
+
+// Returns a random value in [0, prime), biased towards the edge cases: small values & values close to the prime
+func randomFeltValue(r *rand.Rand) *big.Int {
+	prime := lambdaworks.Prime()
+	switch r.Intn(4) {
+	case 0:
+		return big.NewInt(r.Int63n(1 << 16))
+	case 1:
+		return new(big.Int).Sub(prime, big.NewInt(r.Int63n(1<<16)+1))
+	case 2:
+		return new(big.Int).SetUint64(r.Uint64())
+	default:
+		return new(big.Int).Rand(r, prime)
+	}
+}
+
+func feltFromBigIntValue(n *big.Int) lambdaworks.Felt {
+	return lambdaworks.FeltFromDecString(n.String())
+}
+
+func mod(n *big.Int) *big.Int {
+	return n.Mod(n, lambdaworks.Prime())
+}
+
+// Checks every felt operation on a & b (both in [0, prime)) against its big.Int counterpart
+func checkFeltOperations(t *testing.T, a *big.Int, b *big.Int) {
+	t.Helper()
+	prime := lambdaworks.Prime()
+	feltA, feltB := feltFromBigIntValue(a), feltFromBigIntValue(b)
+	check := func(operation string, got lambdaworks.Felt, expected *big.Int) {
+		t.Helper()
+		if got.ToBigInt().Cmp(expected) != 0 {
+			t.Errorf("%s failed for a=%s, b=%s. Expected: %s, got: %s", operation, a, b, expected, got.ToBigInt())
+		}
+	}
+
+	check("ToBigInt", feltA, a)
+	check("Add", feltA.Add(feltB), mod(new(big.Int).Add(a, b)))
+	check("Sub", feltA.Sub(feltB), mod(new(big.Int).Sub(a, b)))
+	check("Mul", feltA.Mul(feltB), mod(new(big.Int).Mul(a, b)))
+	if b.Sign() != 0 {
+		check("Div", feltA.Div(feltB), mod(new(big.Int).Mul(a, new(big.Int).ModInverse(b, prime))))
+		quotient, remainder := new(big.Int).QuoRem(a, b, new(big.Int))
+		div, rem := feltA.DivRem(feltB)
+		check("DivRem quotient", div, quotient)
+		check("DivRem remainder", rem, remainder)
+	}
+	check("Pow", feltA.Pow(feltB), new(big.Int).Exp(a, b, prime))
+	check("PowUint", feltA.PowUint(uint32(b.Uint64())), new(big.Int).Exp(a, new(big.Int).SetUint64(uint64(uint32(b.Uint64()))), prime))
+	// Sqrt is only defined for quadratic residues, it returns the smallest of the two roots
+	square := feltA.Mul(feltA)
+	root := mod(new(big.Int).Set(a))
+	if otherRoot := new(big.Int).Sub(prime, root); root.Sign() != 0 && otherRoot.Cmp(root) < 0 {
+		root = otherRoot
+	}
+	check("Sqrt", square.Sqrt(), root)
+	check("And", feltA.And(feltB), new(big.Int).And(a, b))
+	check("Or", feltA.Or(feltB), mod(new(big.Int).Or(a, b)))
+	check("Xor", feltA.Xor(feltB), mod(new(big.Int).Xor(a, b)))
+	shift := uint(b.Uint64() % 256)
+	check("Shr", feltA.Shr(shift), new(big.Int).Rsh(a, shift))
+	// Shl operates on the 256 bit representative, bits shifted past it are lost
+	shifted := new(big.Int).Lsh(a, shift)
+	check("Shl", feltA.Shl(uint64(shift)), mod(shifted.And(shifted, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)))))
+
+	if cmp := feltA.Cmp(feltB); cmp != a.Cmp(b) {
+		t.Errorf("Cmp failed for a=%s, b=%s. Expected: %d, got: %d", a, b, a.Cmp(b), cmp)
+	}
+	if bits := uint64(feltA.Bits()); bits != uint64(a.BitLen()) {
+		t.Errorf("Bits failed for a=%s. Expected: %d, got: %d", a, a.BitLen(), bits)
+	}
+	if isZero := feltA.IsZero(); isZero != (a.Sign() == 0) {
+		t.Errorf("IsZero failed for a=%s, got: %t", a, isZero)
+	}
+	value, err := feltA.ToU64()
+	if a.IsUint64() && (err != nil || value != a.Uint64()) {
+		t.Errorf("ToU64 failed for a=%s. Got: %d, error: %v", a, value, err)
+	}
+	if !a.IsUint64() && err == nil {
+		t.Errorf("ToU64 should fail for a=%s, got: %d", a, value)
+	}
+
+	signed := new(big.Int).Set(a)
+	if a.Cmp(new(big.Int).Rsh(prime, 1)) > 0 {
+		signed.Sub(a, prime)
+	}
+	if feltA.ToSigned().Cmp(signed) != 0 {
+		t.Errorf("ToSigned failed for a=%s. Expected: %s, got: %s", a, signed, feltA.ToSigned())
+	}
+
+	var beBytes [32]byte
+	a.FillBytes(beBytes[:])
+	if *feltA.ToBeBytes() != beBytes {
+		t.Errorf("ToBeBytes failed for a=%s. Expected: %x, got: %x", a, beBytes, *feltA.ToBeBytes())
+	}
+	check("FeltFromBeBytes", lambdaworks.FeltFromBeBytes(&beBytes), a)
+	var leBytes [32]byte
+	for i := range beBytes {
+		leBytes[i] = beBytes[31-i]
+	}
+	if *feltA.ToLeBytes() != leBytes {
+		t.Errorf("ToLeBytes failed for a=%s. Expected: %x, got: %x", a, leBytes, *feltA.ToLeBytes())
+	}
+	check("FeltFromLeBytes", lambdaworks.FeltFromLeBytes(&leBytes), a)
+	if hex, ok := new(big.Int).SetString(feltA.ToHexString(), 0); !ok || hex.Cmp(a) != 0 {
+		t.Errorf("ToHexString failed for a=%s, got: %s", a, feltA.ToHexString())
+	}
+	check("FeltFromHex", lambdaworks.FeltFromHex("0x"+a.Text(16)), a)
+	check("FeltFromBigInt", lambdaworks.FeltFromBigInt(new(big.Int).Add(a, prime)), a)
+	if a.IsUint64() {
+		check("FeltFromUint64", lambdaworks.FeltFromUint64(a.Uint64()), a)
+	}
+}
+
+func TestFeltOperationsMatchBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	for i := 0; i < 1000; i++ {
+		checkFeltOperations(t, randomFeltValue(r), randomFeltValue(r))
+	}
+}
+
+func FuzzFeltOperations(f *testing.F) {
+	f.Add([]byte{0}, []byte{1})
+	f.Add([]byte{1, 2, 3}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	f.Add(lambdaworks.Prime().Bytes(), new(big.Int).Sub(lambdaworks.Prime(), big.NewInt(1)).Bytes())
+	f.Fuzz(func(t *testing.T, a []byte, b []byte) {
+		checkFeltOperations(t, mod(new(big.Int).SetBytes(a)), mod(new(big.Int).SetBytes(b)))
+	})
+}
+
+// Checks the native sqrt & Legendre symbol against big.Int's ModSqrt & Jacobi on random quadratic residues & non residues
+func TestSqrtMatchesBigInt(t *testing.T) {
+	r := rand.New(rand.NewSource(0))
+	prime := lambdaworks.Prime()
+	three := lambdaworks.FeltFromUint64(3)
+	for i := 0; i < 200; i++ {
+		root := feltFromBigIntValue(randomFeltValue(r))
+		square := root.Mul(root)
+		sqrt, ok := square.CheckedSqrt()
+		if !ok || sqrt.Mul(sqrt) != square {
+			t.Fatalf("CheckedSqrt failed for %s: got %s, %t", square.ToBigInt(), sqrt.ToBigInt(), ok)
+		}
+		expected := new(big.Int).ModSqrt(square.ToBigInt(), prime)
+		if otherRoot := new(big.Int).Sub(prime, expected); expected.Sign() != 0 && otherRoot.Cmp(expected) < 0 {
+			expected = otherRoot
+		}
+		if sqrt.ToBigInt().Cmp(expected) != 0 {
+			t.Errorf("CheckedSqrt(%s): expected the smallest root %s, got %s", square.ToBigInt(), expected, sqrt.ToBigInt())
+		}
+		if symbol := square.LegendreSymbol(); symbol != big.Jacobi(square.ToBigInt(), prime) || !square.IsQuadResidue() {
+			t.Errorf("LegendreSymbol(%s): expected a quadratic residue, got %d", square.ToBigInt(), symbol)
+		}
+
+		// 3 is not a quadratic residue, so neither is 3 * square unless it is zero
+		if nonResidue := square.Mul(three); !nonResidue.IsZero() {
+			if sqrt, ok := nonResidue.CheckedSqrt(); ok || nonResidue.IsQuadResidue() {
+				t.Errorf("CheckedSqrt(%s) should fail, got: %s", nonResidue.ToBigInt(), sqrt.ToBigInt())
+			}
+			if symbol := nonResidue.LegendreSymbol(); symbol != -1 || big.Jacobi(nonResidue.ToBigInt(), prime) != -1 {
+				t.Errorf("LegendreSymbol(%s): expected -1, got %d", nonResidue.ToBigInt(), symbol)
+			}
+		}
+	}
+	if symbol := lambdaworks.FeltZero().LegendreSymbol(); symbol != 0 {
+		t.Errorf("LegendreSymbol(0): expected 0, got %d", symbol)
+	}
+}
+
+func TestFeltLimbs(t *testing.T) {
+	felt := lambdaworks.FeltFromHex("0x100000000000000020000000000000003")
+	if limbs := felt.Limbs(); limbs != [4]uint64{0, 1, 2, 3} {
+		t.Errorf("Wrong limbs: %v", limbs)
+	}
+	if limbs := felt.LeLimbs(); limbs != [4]uint64{3, 2, 1, 0} {
+		t.Errorf("Wrong little endian limbs: %v", limbs)
+	}
+	if lambdaworks.FeltFromLimbs(felt.Limbs()) != felt || lambdaworks.FeltFromLeLimbs(felt.LeLimbs()) != felt {
+		t.Errorf("Limbs round trip failed")
+	}
+	if limbs := lambdaworks.FeltFromDecString("-1").Limbs(); limbs != [4]uint64{0x0800000000000011, 0, 0, 0} {
+		t.Errorf("Wrong limbs of -1: %v", limbs)
+	}
+}
+
+func TestFeltFromLimbsReducesModuloPrime(t *testing.T) {
+	// PRIME + 5
+	felt := lambdaworks.FeltFromLimbs([4]uint64{0x0800000000000011, 0, 0, 6})
+	if felt != lambdaworks.FeltFromUint64(5) {
+		t.Errorf("Expected 5, got %s", felt.ToHexString())
+	}
+}
+
+func TestFeltMontgomeryLimbs(t *testing.T) {
+	felt := lambdaworks.FeltFromUint64(7)
+	r := new(big.Int).Lsh(big.NewInt(1), 256)
+	expected := new(big.Int).Mod(new(big.Int).Mul(big.NewInt(7), r), lambdaworks.Prime())
+	limbs := felt.MontgomeryLimbs()
+	montgomery := new(big.Int)
+	for _, limb := range limbs {
+		montgomery.Lsh(montgomery, 64).Or(montgomery, new(big.Int).SetUint64(limb))
+	}
+	if montgomery.Cmp(expected) != 0 {
+		t.Errorf("Wrong Montgomery form: %s, expected %s", montgomery, expected)
+	}
+	if lambdaworks.FeltFromMontgomeryLimbs(limbs) != felt {
+		t.Errorf("Montgomery limbs round trip failed")
+	}
+}
+
+func TestFeltCanonicalRoundTrip(t *testing.T) {
+	for _, felt := range []lambdaworks.Felt{lambdaworks.FeltZero(), lambdaworks.FeltOne(), lambdaworks.FeltFromDecString("-1"), lambdaworks.FeltFromHex("0x1234567890abcdef1234567890abcdef")} {
+		canonical := felt.ToCanonical()
+		value := new(big.Int)
+		for _, limb := range canonical {
+			value.Lsh(value, 64).Or(value, new(big.Int).SetUint64(limb))
+		}
+		if value.Cmp(felt.ToBigInt()) != 0 {
+			t.Errorf("Canonical limbs of %s don't match its value", felt.ToHexString())
+		}
+		fromCanonical, err := lambdaworks.FeltFromCanonical(canonical)
+		if err != nil || fromCanonical != felt {
+			t.Errorf("Canonical round trip of %s failed: %s, %v", felt.ToHexString(), fromCanonical.ToHexString(), err)
+		}
+		fromMontgomery, err := lambdaworks.FeltFromMontgomery(felt.ToMontgomery())
+		if err != nil || fromMontgomery != felt {
+			t.Errorf("Montgomery round trip of %s failed: %s, %v", felt.ToHexString(), fromMontgomery.ToHexString(), err)
+		}
+	}
+}
+
+func TestFeltCanonicalRepresentationInvariants(t *testing.T) {
+	// The limbs are the canonical value, so small values fit in the least significant limb
+	felt := lambdaworks.FeltFromUint64(0xffff)
+	if canonical := felt.ToCanonical(); canonical != (lambdaworks.CanonicalLimbs{0, 0, 0, 0xffff}) {
+		t.Errorf("Wrong canonical limbs: %#x", canonical)
+	}
+	if value, err := felt.ToU64(); err != nil || value != 0xffff {
+		t.Errorf("Wrong u64: %d, %v", value, err)
+	}
+	if felt.Bits() != 16 {
+		t.Errorf("Wrong bits: %d", felt.Bits())
+	}
+	if bytes := felt.ToLeBytes(); bytes[0] != 0xff || bytes[1] != 0xff || bytes[2] != 0 {
+		t.Errorf("Wrong little endian bytes: %x", bytes)
+	}
+	if lambdaworks.FeltOne().ToMontgomery() == lambdaworks.MontgomeryLimbs(lambdaworks.FeltOne().ToCanonical()) {
+		t.Errorf("The Montgomery form of one shouldn't match its canonical value")
+	}
+}
+
+func TestFeltFromCanonicalRejectsValuesAbovePrime(t *testing.T) {
+	prime := lambdaworks.CanonicalLimbs{0x0800000000000011, 0, 0, 1}
+	if _, err := lambdaworks.FeltFromCanonical(prime); !errors.Is(err, lambdaworks.ErrNonCanonicalLimbs) {
+		t.Errorf("Expected ErrNonCanonicalLimbs, got: %v", err)
+	}
+	if _, err := lambdaworks.FeltFromMontgomery(lambdaworks.MontgomeryLimbs(prime)); !errors.Is(err, lambdaworks.ErrNonCanonicalLimbs) {
+		t.Errorf("Expected ErrNonCanonicalLimbs, got: %v", err)
+	}
+}
+
+func TestStatsNoLiveFfiAllocations(t *testing.T) {
+	before := lambdaworks.Stats()
+	felt := lambdaworks.FeltFromHex("0x1f").Add(lambdaworks.FeltFromDecString("-1"))
+	if felt.ToHexString() != "0x1e" || felt.ToSignedFeltString() != "30" {
+		t.Errorf("Wrong felt: %s", felt.ToHexString())
+	}
+	after := lambdaworks.Stats()
+	if after.CAllocs-before.CAllocs != 3 || after.RustAllocs-before.RustAllocs != 1 {
+		t.Errorf("Expected 3 C & 1 Rust allocations, got %+v (before: %+v)", after, before)
+	}
+	if after.Live() != 0 {
+		t.Errorf("Expected no live ffi allocations, got %d: %+v", after.Live(), after)
+	}
+}
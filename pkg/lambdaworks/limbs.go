@@ -0,0 +1,142 @@
+package lambdaworks
+
+import (
+	"math/big"
+
+	"github.com/pkg/errors"
+)
+
+// Limbs of the Cairo prime, most significant first
+var primeLimbs = [N_LIMBS_IN_FELT]uint64{0x0800000000000011, 0, 0, 1}
+
+// Limbs of a felt's canonical value (in [0, PRIME)), most significant first
+// This is the only representation Felt holds, every value crossing the ffi is converted to it by lambdaworks
+type CanonicalLimbs [N_LIMBS_IN_FELT]uint64
+
+// Limbs of a felt's Montgomery form (value * 2^256 mod PRIME), most significant first
+// Felt never holds this representation, it's only used to exchange felts with code that operates on Montgomery form elements
+type MontgomeryLimbs [N_LIMBS_IN_FELT]uint64
+
+var ErrNonCanonicalLimbs = errors.New("Limbs are not below the prime")
+
+func NonCanonicalLimbsError(limbs [N_LIMBS_IN_FELT]uint64) error {
+	return LambdaworksError(errors.Wrapf(ErrNonCanonicalLimbs, "%#x", limbs))
+}
+
+// Returns the felt's canonical value
+func (f Felt) ToCanonical() CanonicalLimbs {
+	var limbs CanonicalLimbs
+	for i, limb := range f.limbs {
+		limbs[i] = uint64(limb)
+	}
+	return limbs
+}
+
+// Creates a felt from its canonical value, failing if the limbs are not below the prime
+// Unlike FeltFromLimbs, it doesn't reduce the value, so it can be used to validate untrusted encodings
+func FeltFromCanonical(limbs CanonicalLimbs) (Felt, error) {
+	if !limbsBelowPrime(limbs) {
+		return Felt{}, NonCanonicalLimbsError(limbs)
+	}
+	var felt Felt
+	for i, limb := range limbs {
+		felt.limbs[i] = Limb(limb)
+	}
+	return felt, nil
+}
+
+// Returns the felt's Montgomery form
+func (f Felt) ToMontgomery() MontgomeryLimbs {
+	montgomery := new(big.Int).Lsh(f.ToBigInt(), 64*N_LIMBS_IN_FELT)
+	return bigIntToLimbs(montgomery.Mod(montgomery, Prime()))
+}
+
+// Creates a felt from its Montgomery form, failing if the limbs are not below the prime
+func FeltFromMontgomery(limbs MontgomeryLimbs) (Felt, error) {
+	if !limbsBelowPrime(limbs) {
+		return Felt{}, NonCanonicalLimbsError(limbs)
+	}
+	prime := Prime()
+	rInverse := new(big.Int).ModInverse(new(big.Int).Lsh(big.NewInt(1), 64*N_LIMBS_IN_FELT), prime)
+	value := new(big.Int).Mul(limbsToBigInt(limbs), rInverse)
+	return FeltFromCanonical(bigIntToLimbs(value.Mod(value, prime)))
+}
+
+// Returns the limbs of the felt's canonical value (in [0, PRIME)), most significant first
+//
+// Felt holds its canonical value, not its Montgomery form: lambdaworks converts to & from the Montgomery
+// form on each operation, so these limbs can be serialized as they are. See ToCanonical & ToMontgomery
+func (f Felt) Limbs() [N_LIMBS_IN_FELT]uint64 {
+	return f.ToCanonical()
+}
+
+// Returns the limbs of the felt's canonical value, least significant first
+func (f Felt) LeLimbs() [N_LIMBS_IN_FELT]uint64 {
+	return reverseLimbs(f.Limbs())
+}
+
+// Creates a felt from the limbs of its value, most significant first, as returned by Limbs
+// Values that are not below the prime are reduced modulo the prime
+func FeltFromLimbs(limbs [N_LIMBS_IN_FELT]uint64) Felt {
+	felt, err := FeltFromCanonical(limbs)
+	if err != nil {
+		return FeltFromBigInt(limbsToBigInt(limbs))
+	}
+	return felt
+}
+
+// Creates a felt from the limbs of its value, least significant first, as returned by LeLimbs
+func FeltFromLeLimbs(limbs [N_LIMBS_IN_FELT]uint64) Felt {
+	return FeltFromLimbs(reverseLimbs(limbs))
+}
+
+// Returns the limbs of the felt's Montgomery form (value * 2^256 mod PRIME), most significant first,
+// for provers that operate on Montgomery form elements
+func (f Felt) MontgomeryLimbs() [N_LIMBS_IN_FELT]uint64 {
+	return f.ToMontgomery()
+}
+
+// Creates a felt from the limbs of its Montgomery form, most significant first, as returned by MontgomeryLimbs
+// Values that are not below the prime are reduced modulo the prime
+func FeltFromMontgomeryLimbs(limbs [N_LIMBS_IN_FELT]uint64) Felt {
+	reduced := new(big.Int).Mod(limbsToBigInt(limbs), Prime())
+	felt, _ := FeltFromMontgomery(bigIntToLimbs(reduced))
+	return felt
+}
+
+func limbsBelowPrime(limbs [N_LIMBS_IN_FELT]uint64) bool {
+	for i := range limbs {
+		if limbs[i] != primeLimbs[i] {
+			return limbs[i] < primeLimbs[i]
+		}
+	}
+	return false
+}
+
+func reverseLimbs(limbs [N_LIMBS_IN_FELT]uint64) [N_LIMBS_IN_FELT]uint64 {
+	for i, j := 0, len(limbs)-1; i < j; i, j = i+1, j-1 {
+		limbs[i], limbs[j] = limbs[j], limbs[i]
+	}
+	return limbs
+}
+
+func limbsToBigInt(limbs [N_LIMBS_IN_FELT]uint64) *big.Int {
+	n := new(big.Int)
+	for _, limb := range limbs {
+		n.Lsh(n, 64)
+		n.Or(n, new(big.Int).SetUint64(limb))
+	}
+	return n
+}
+
+// Assumes n fits in N_LIMBS_IN_FELT limbs
+func bigIntToLimbs(n *big.Int) [N_LIMBS_IN_FELT]uint64 {
+	var limbs [N_LIMBS_IN_FELT]uint64
+	mask := new(big.Int).SetUint64(^uint64(0))
+	rest := new(big.Int).Set(n)
+	for i := len(limbs) - 1; i >= 0; i-- {
+		limbs[i] = new(big.Int).And(rest, mask).Uint64()
+		rest.Rsh(rest, 64)
+	}
+	return limbs
+}
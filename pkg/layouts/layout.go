@@ -2,6 +2,7 @@ package layouts
 
 import (
 	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/pkg/errors"
 )
 
 // Representation of a cairo layout.
@@ -20,10 +21,30 @@ type CairoLayout struct {
 	// cpuInstanceDef CpuInstanceDef
 }
 
+// Builtin present in a layout, with the ratio it has in that layout
+type builtinInstance struct {
+	name  string
+	ratio uint
+}
+
+// Instantiates the builtins of a layout through the builtins registry
+// Layouts are static, so an unknown builtin name is a programming error
+func newLayoutBuiltins(instances ...builtinInstance) []builtins.BuiltinRunner {
+	builtinRunners := make([]builtins.BuiltinRunner, 0, len(instances))
+	for _, instance := range instances {
+		builtinRunner, err := builtins.NewBuiltinRunner(instance.name, instance.ratio)
+		if err != nil {
+			panic(err)
+		}
+		builtinRunners = append(builtinRunners, builtinRunner)
+	}
+	return builtinRunners
+}
+
 func NewPlainLayout() CairoLayout {
 	return CairoLayout{
 		Name:                 "plain",
-		Builtins:             []builtins.BuiltinRunner{builtins.NewOutputBuiltinRunner()},
+		Builtins:             newLayoutBuiltins(builtinInstance{name: builtins.OUTPUT_BUILTIN_NAME}),
 		RcUnits:              16,
 		PublicMemoryFraction: 4,
 		MemoryUnitsPerStep:   8,
@@ -34,12 +55,12 @@ func NewPlainLayout() CairoLayout {
 func NewSmallLayout() CairoLayout {
 	return CairoLayout{
 		Name: "small",
-		Builtins: []builtins.BuiltinRunner{
-			builtins.NewOutputBuiltinRunner(),
-			builtins.NewPedersenBuiltinRunner(256),
-			builtins.DefaultRangeCheckBuiltinRunner(),
-			builtins.NewSignatureBuiltinRunner(2048),
-		},
+		Builtins: newLayoutBuiltins(
+			builtinInstance{name: builtins.OUTPUT_BUILTIN_NAME},
+			builtinInstance{name: builtins.PEDERSEN_BUILTIN_NAME, ratio: 256},
+			builtinInstance{name: builtins.RANGE_CHECK_BUILTIN_NAME, ratio: 8},
+			builtinInstance{name: builtins.SIGNATURE_BUILTIN_NAME, ratio: 2048},
+		),
 		RcUnits:              16,
 		PublicMemoryFraction: 4,
 		MemoryUnitsPerStep:   8,
@@ -50,18 +71,33 @@ func NewSmallLayout() CairoLayout {
 func NewAllCairoLayout() CairoLayout {
 	return CairoLayout{
 		Name: "all_cairo",
-		Builtins: []builtins.BuiltinRunner{
-			builtins.NewOutputBuiltinRunner(),
-			builtins.NewPedersenBuiltinRunner(256),
-			builtins.DefaultRangeCheckBuiltinRunner(),
-			builtins.NewSignatureBuiltinRunner(2048),
-			builtins.NewBitwiseBuiltinRunner(16),
-			builtins.NewEcOpBuiltinRunner(1024),
-			builtins.NewKeccakBuiltinRunner(2048),
-			builtins.NewPoseidonBuiltinRunner(256)},
+		Builtins: newLayoutBuiltins(
+			builtinInstance{name: builtins.OUTPUT_BUILTIN_NAME},
+			builtinInstance{name: builtins.PEDERSEN_BUILTIN_NAME, ratio: 256},
+			builtinInstance{name: builtins.RANGE_CHECK_BUILTIN_NAME, ratio: 8},
+			builtinInstance{name: builtins.SIGNATURE_BUILTIN_NAME, ratio: 2048},
+			builtinInstance{name: builtins.BITWISE_BUILTIN_NAME, ratio: 16},
+			builtinInstance{name: builtins.EC_OP_BUILTIN_NAME, ratio: 1024},
+			builtinInstance{name: builtins.KECCAK_BUILTIN_NAME, ratio: 2048},
+			builtinInstance{name: builtins.POSEIDON_BUILTIN_NAME, ratio: 256},
+		),
 		RcUnits:              4,
 		PublicMemoryFraction: 8,
 		MemoryUnitsPerStep:   8,
 		DilutedPoolInstance:  DefaultDilutedPoolInstance(),
 	}
 }
+
+// Creates the layout with the given name
+func NewLayout(name string) (CairoLayout, error) {
+	switch name {
+	case "plain":
+		return NewPlainLayout(), nil
+	case "small":
+		return NewSmallLayout(), nil
+	case "all_cairo":
+		return NewAllCairoLayout(), nil
+	default:
+		return CairoLayout{}, errors.Errorf("Layout not implemented: %s", name)
+	}
+}
@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{LevelDebug: "debug", LevelInfo: "info", LevelWarn: "warn", LevelError: "error"}
+
+func (l Level) String() string {
+	name, ok := levelNames[l]
+	if !ok {
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+	return name
+}
+
+// Parses a level name (debug, info, warn or error), case insensitive
+func ParseLevel(name string) (Level, error) {
+	for level, levelName := range levelNames {
+		if strings.EqualFold(name, levelName) {
+			return level, nil
+		}
+	}
+	return LevelDebug, errors.Errorf("Invalid log level %s, expected one of: debug, info, warn, error", name)
+}
+
+// Key-value pair attached to a log record
+type Field struct {
+	Key   string
+	Value any
+}
+
+func F(key string, value any) Field {
+	return Field{Key: key, Value: value}
+}
+
+// Leveled logger that writes one record per line in logfmt format:
+// time=<RFC3339> level=<level> msg=<message> key=value...
+// It is safe to use from multiple goroutines
+type Logger struct {
+	mu    sync.Mutex
+	dest  io.Writer
+	level Level
+}
+
+// Creates a logger that writes the records with a level of at least minLevel to dest
+func NewLogger(dest io.Writer, minLevel Level) *Logger {
+	return &Logger{dest: dest, level: minLevel}
+}
+
+// Returns true if records of the given level are written
+// Callers should check it before building expensive fields
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.level
+}
+
+func (l *Logger) Log(level Level, msg string, fields ...Field) {
+	if !l.Enabled(level) {
+		return
+	}
+	var record strings.Builder
+	record.WriteString("time=")
+	record.WriteString(time.Now().Format(time.RFC3339))
+	record.WriteString(" level=")
+	record.WriteString(level.String())
+	record.WriteString(" msg=")
+	record.WriteString(formatValue(msg))
+	for _, field := range fields {
+		record.WriteString(" ")
+		record.WriteString(field.Key)
+		record.WriteString("=")
+		record.WriteString(formatValue(fmt.Sprint(field.Value)))
+	}
+	record.WriteString("\n")
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	io.WriteString(l.dest, record.String())
+}
+
+func (l *Logger) Debug(msg string, fields ...Field) {
+	l.Log(LevelDebug, msg, fields...)
+}
+
+func (l *Logger) Info(msg string, fields ...Field) {
+	l.Log(LevelInfo, msg, fields...)
+}
+
+func (l *Logger) Warn(msg string, fields ...Field) {
+	l.Log(LevelWarn, msg, fields...)
+}
+
+func (l *Logger) Error(msg string, fields ...Field) {
+	l.Log(LevelError, msg, fields...)
+}
+
+// Quotes the value if it can't be written as a bare logfmt value
+func formatValue(value string) string {
+	if value == "" || strings.ContainsAny(value, " =\"\t\n") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+var defaultLogger atomic.Pointer[Logger]
+
+func init() {
+	defaultLogger.Store(NewLogger(os.Stderr, LevelWarn))
+}
+
+// Returns the logger used by the vm, hints & builtins
+// By default, warnings & errors are written to stderr
+func Default() *Logger {
+	return defaultLogger.Load()
+}
+
+// Replaces the logger used by the vm, hints & builtins
+func SetDefault(logger *Logger) {
+	defaultLogger.Store(logger)
+}
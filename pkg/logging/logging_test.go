@@ -0,0 +1,48 @@
+package logging_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
+)
+
+func TestLoggerFiltersByLevel(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := logging.NewLogger(&buffer, logging.LevelInfo)
+	logger.Debug("hidden")
+	logger.Info("shown")
+	logger.Error("also shown")
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected two records, got: %s", buffer.String())
+	}
+	if !strings.Contains(lines[0], "level=info msg=shown") || !strings.Contains(lines[1], "level=error") {
+		t.Errorf("Wrong records: %s", buffer.String())
+	}
+}
+
+func TestLoggerFormatsFields(t *testing.T) {
+	var buffer bytes.Buffer
+	logger := logging.NewLogger(&buffer, logging.LevelDebug)
+	logger.Debug("Executing step", logging.F("step", 3), logging.F("pc", "{0:1}"), logging.F("instruction", "AssertEq dst=[ap+0]"))
+	expected := `level=debug msg="Executing step" step=3 pc={0:1} instruction="AssertEq dst=[ap+0]"`
+	if !strings.Contains(buffer.String(), expected) {
+		t.Errorf("Wrong record.\nExpected to contain: %s\nGot: %s", expected, buffer.String())
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	level, err := logging.ParseLevel("WARN")
+	if err != nil {
+		t.Errorf("ParseLevel failed with error: %s", err)
+	}
+	if level != logging.LevelWarn {
+		t.Errorf("Expected warn level, got: %s", level)
+	}
+	_, err = logging.ParseLevel("verbose")
+	if err == nil {
+		t.Errorf("ParseLevel should fail for an unknown level")
+	}
+}
@@ -0,0 +1,59 @@
+package parallel
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Splits the range [0, n) into one contiguous chunk per worker & calls fn concurrently on each of them
+// fn receives the index of the chunk along with its bounds (start inclusive, end exclusive)
+// A non-positive number of workers uses one worker per available cpu (GOMAXPROCS)
+// Returns the error of the first chunk that failed, if any
+func ForEachChunk(n int, workers int, fn func(chunk int, start int, end int) error) error {
+	if n <= 0 {
+		return nil
+	}
+	workers = Workers(workers)
+	if workers > n {
+		workers = n
+	}
+	if workers == 1 {
+		return fn(0, 0, n)
+	}
+
+	chunkSize := (n + workers - 1) / workers
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for chunk := 0; chunk < workers; chunk++ {
+		start := chunk * chunkSize
+		end := start + chunkSize
+		if end > n {
+			end = n
+		}
+		if start >= end {
+			break
+		}
+		wg.Add(1)
+		go func(chunk int, start int, end int) {
+			defer wg.Done()
+			errs[chunk] = fn(chunk, start, end)
+		}(chunk, start, end)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the number of workers that will be used for the requested amount
+// A non-positive amount means one worker per available cpu
+func Workers(workers int) int {
+	if workers <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return workers
+}
@@ -0,0 +1,55 @@
+package parallel_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/parallel"
+)
+
+func TestForEachChunkCoversRange(t *testing.T) {
+	for _, workers := range []int{0, 1, 3, 10, 50} {
+		var mu sync.Mutex
+		visited := make([]int, 23)
+		err := parallel.ForEachChunk(len(visited), workers, func(_ int, start int, end int) error {
+			mu.Lock()
+			defer mu.Unlock()
+			for i := start; i < end; i++ {
+				visited[i]++
+			}
+			return nil
+		})
+		if err != nil {
+			t.Errorf("ForEachChunk failed with error: %s", err)
+		}
+		for i, count := range visited {
+			if count != 1 {
+				t.Errorf("Index %d visited %d times with %d workers", i, count, workers)
+			}
+		}
+	}
+}
+
+func TestForEachChunkReturnsError(t *testing.T) {
+	expectedErr := errors.New("chunk failed")
+	err := parallel.ForEachChunk(10, 4, func(chunk int, start int, end int) error {
+		if chunk == 2 {
+			return expectedErr
+		}
+		return nil
+	})
+	if err != expectedErr {
+		t.Errorf("Expected the chunk's error, got: %v", err)
+	}
+}
+
+func TestForEachChunkEmptyRange(t *testing.T) {
+	err := parallel.ForEachChunk(0, 4, func(int, int, int) error {
+		t.Errorf("fn shouldn't be called for an empty range")
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ForEachChunk failed with error: %s", err)
+	}
+}
@@ -0,0 +1,256 @@
+// Writes tables to parquet files, so that they can be loaded by data-analysis tools (ie: pandas, DuckDB)
+// Only what the exporters of the vm need is supported: required INT64 & UTF8 columns, stored uncompressed with the
+// plain encoding. See https://github.com/apache/parquet-format for the format
+package parquet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+const magic = "PAR1"
+
+// Number of rows buffered before they are written as a row group, unless Writer.RowGroupSize is set
+const DefaultRowGroupSize = 1 << 20
+
+type ColumnType int
+
+const (
+	Int64 ColumnType = iota
+	String
+)
+
+type Column struct {
+	Name string
+	Type ColumnType
+}
+
+var ErrInvalidRow = errors.New("Invalid parquet row")
+
+// Values of the format's enums
+const (
+	typeInt64          = 2
+	typeByteArray      = 6
+	repetitionRequired = 0
+	convertedUtf8      = 0
+	encodingPlain      = 0
+	encodingRle        = 3
+	pageTypeData       = 0
+	codecUncompressed  = 0
+)
+
+type columnChunk struct {
+	offset int64
+	size   int64
+}
+
+type rowGroup struct {
+	columns []columnChunk
+	numRows int64
+}
+
+// Writes the rows of a table to dest, buffering up to RowGroupSize rows at a time
+// Close has to be called once all the rows are written, to write the buffered rows & the file's metadata
+type Writer struct {
+	// Number of rows buffered before they are written as a row group
+	RowGroupSize int
+	dest         io.Writer
+	columns      []Column
+	offset       int64
+	rowGroups    []rowGroup
+	numRows      int64
+	// Buffered values of each column, only the slice matching the column's type is used
+	ints    [][]int64
+	strings [][]string
+	rows    int
+}
+
+func NewWriter(dest io.Writer, columns ...Column) *Writer {
+	return &Writer{
+		RowGroupSize: DefaultRowGroupSize,
+		dest:         dest,
+		columns:      columns,
+		ints:         make([][]int64, len(columns)),
+		strings:      make([][]string, len(columns)),
+	}
+}
+
+// Adds a row holding one value per column, an int64 for Int64 columns & a string for String columns
+func (w *Writer) WriteRow(values ...any) error {
+	if len(values) != len(w.columns) {
+		return errors.Wrapf(ErrInvalidRow, "expected %d values, got %d", len(w.columns), len(values))
+	}
+	for i, column := range w.columns {
+		switch value := values[i].(type) {
+		case int64:
+			if column.Type != Int64 {
+				return errors.Wrapf(ErrInvalidRow, "column %s expects a string, got %d", column.Name, value)
+			}
+		case string:
+			if column.Type != String {
+				return errors.Wrapf(ErrInvalidRow, "column %s expects an int64, got %q", column.Name, value)
+			}
+		default:
+			return errors.Wrapf(ErrInvalidRow, "unsupported value %v of type %T for column %s", value, value, column.Name)
+		}
+	}
+	for i, column := range w.columns {
+		if column.Type == Int64 {
+			w.ints[i] = append(w.ints[i], values[i].(int64))
+		} else {
+			w.strings[i] = append(w.strings[i], values[i].(string))
+		}
+	}
+	w.rows++
+	if w.rows >= w.RowGroupSize {
+		return w.flush()
+	}
+	return nil
+}
+
+// Writes the buffered rows & the metadata of the file, dest is not closed
+func (w *Writer) Close() error {
+	if err := w.flush(); err != nil {
+		return err
+	}
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+	metadata := w.fileMetadata()
+	footer := binary.LittleEndian.AppendUint32(metadata, uint32(len(metadata)))
+	return w.write(append(footer, magic...))
+}
+
+func (w *Writer) write(data []byte) error {
+	n, err := w.dest.Write(data)
+	w.offset += int64(n)
+	return err
+}
+
+// Writes the buffered rows as a row group, with a single data page per column
+func (w *Writer) flush() error {
+	if w.rows == 0 {
+		return nil
+	}
+	if w.offset == 0 {
+		if err := w.write([]byte(magic)); err != nil {
+			return err
+		}
+	}
+	group := rowGroup{numRows: int64(w.rows)}
+	for i, column := range w.columns {
+		var data []byte
+		if column.Type == Int64 {
+			data = make([]byte, 0, 8*w.rows)
+			for _, value := range w.ints[i] {
+				data = binary.LittleEndian.AppendUint64(data, uint64(value))
+			}
+		} else {
+			for _, value := range w.strings[i] {
+				data = binary.LittleEndian.AppendUint32(data, uint32(len(value)))
+				data = append(data, value...)
+			}
+		}
+		if len(data) > 1<<31-1 {
+			return fmt.Errorf("The page of column %s is too large (%d bytes), use a smaller RowGroupSize", column.Name, len(data))
+		}
+		chunk := columnChunk{offset: w.offset}
+		page := append(pageHeader(w.rows, len(data)), data...)
+		if err := w.write(page); err != nil {
+			return err
+		}
+		chunk.size = int64(len(page))
+		group.columns = append(group.columns, chunk)
+		w.ints[i] = w.ints[i][:0]
+		w.strings[i] = w.strings[i][:0]
+	}
+	w.rowGroups = append(w.rowGroups, group)
+	w.numRows += int64(w.rows)
+	w.rows = 0
+	return nil
+}
+
+func pageHeader(numValues int, size int) []byte {
+	var w thriftWriter
+	w.beginStruct(0)
+	w.i32Field(1, pageTypeData)
+	w.i32Field(2, int32(size))
+	w.i32Field(3, int32(size))
+	w.beginStruct(5)
+	w.i32Field(1, int32(numValues))
+	w.i32Field(2, encodingPlain)
+	w.i32Field(3, encodingRle)
+	w.i32Field(4, encodingRle)
+	w.end()
+	w.end()
+	return w.buf
+}
+
+func (w *Writer) fileMetadata() []byte {
+	var t thriftWriter
+	t.beginStruct(0)
+	t.i32Field(1, 1)
+
+	t.listField(2, thriftStruct, len(w.columns)+1)
+	t.beginStruct(0)
+	t.stringField(4, "schema")
+	t.i32Field(5, int32(len(w.columns)))
+	t.end()
+	for _, column := range w.columns {
+		t.beginStruct(0)
+		t.i32Field(1, column.physicalType())
+		t.i32Field(3, repetitionRequired)
+		t.stringField(4, column.Name)
+		if column.Type == String {
+			t.i32Field(6, convertedUtf8)
+		}
+		t.end()
+	}
+
+	t.i64Field(3, w.numRows)
+
+	t.listField(4, thriftStruct, len(w.rowGroups))
+	for _, group := range w.rowGroups {
+		t.beginStruct(0)
+		var totalSize int64
+		t.listField(1, thriftStruct, len(group.columns))
+		for i, chunk := range group.columns {
+			totalSize += chunk.size
+			t.beginStruct(0)
+			t.i64Field(2, chunk.offset)
+			t.beginStruct(3)
+			t.i32Field(1, w.columns[i].physicalType())
+			t.listField(2, thriftI32, 2)
+			t.zigzag(encodingPlain)
+			t.zigzag(encodingRle)
+			t.listField(3, thriftBinary, 1)
+			t.binary(w.columns[i].Name)
+			t.i32Field(4, codecUncompressed)
+			t.i64Field(5, group.numRows)
+			t.i64Field(6, chunk.size)
+			t.i64Field(7, chunk.size)
+			t.i64Field(9, chunk.offset)
+			t.end()
+			t.end()
+		}
+		t.i64Field(2, totalSize)
+		t.i64Field(3, group.numRows)
+		t.end()
+	}
+
+	t.stringField(6, "cairo-vm.go")
+	t.end()
+	return t.buf
+}
+
+func (c Column) physicalType() int32 {
+	if c.Type == String {
+		return typeByteArray
+	}
+	return typeInt64
+}
@@ -0,0 +1,155 @@
+package parquet_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/parquet"
+)
+
+// Minimal reader of the thrift compact protocol, decoding structs as maps from field id to value
+type thriftReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *thriftReader) varint() uint64 {
+	n, size := binary.Uvarint(r.data[r.pos:])
+	r.pos += size
+	return n
+}
+
+func (r *thriftReader) zigzag() int64 {
+	n := r.varint()
+	return int64(n>>1) ^ -int64(n&1)
+}
+
+func (r *thriftReader) value(fieldType byte) any {
+	switch fieldType {
+	case 1, 2:
+		return fieldType == 1
+	case 5, 6:
+		return r.zigzag()
+	case 8:
+		size := int(r.varint())
+		r.pos += size
+		return string(r.data[r.pos-size : r.pos])
+	case 9:
+		header := r.data[r.pos]
+		r.pos++
+		size := int(header >> 4)
+		if size == 15 {
+			size = int(r.varint())
+		}
+		list := make([]any, size)
+		for i := range list {
+			list[i] = r.value(header & 0xf)
+		}
+		return list
+	case 12:
+		return r.structValue()
+	}
+	panic("unsupported thrift type")
+}
+
+func (r *thriftReader) structValue() map[int16]any {
+	fields := make(map[int16]any)
+	var id int16
+	for {
+		header := r.data[r.pos]
+		r.pos++
+		if header == 0 {
+			return fields
+		}
+		if delta := int16(header >> 4); delta != 0 {
+			id += delta
+		} else {
+			id = int16(r.zigzag())
+		}
+		fields[id] = r.value(header & 0xf)
+	}
+}
+
+// Reads back the values of each column of the parquet file written by parquet.Writer
+func readParquet(t *testing.T, file []byte) (map[int16]any, [][]any) {
+	if string(file[:4]) != "PAR1" || string(file[len(file)-4:]) != "PAR1" {
+		t.Fatalf("Missing the magic number")
+	}
+	size := int(binary.LittleEndian.Uint32(file[len(file)-8:]))
+	reader := thriftReader{data: file[:len(file)-8], pos: len(file) - 8 - size}
+	metadata := reader.structValue()
+
+	schema := metadata[2].([]any)
+	columns := make([][]any, len(schema)-1)
+	for _, group := range metadata[4].([]any) {
+		for i, chunk := range group.(map[int16]any)[1].([]any) {
+			columnMetadata := chunk.(map[int16]any)[3].(map[int16]any)
+			pageReader := thriftReader{data: file, pos: int(columnMetadata[9].(int64))}
+			header := pageReader.structValue()
+			numValues := int(header[5].(map[int16]any)[1].(int64))
+			data := file[pageReader.pos : pageReader.pos+int(header[3].(int64))]
+			for j := 0; j < numValues; j++ {
+				if columnMetadata[1].(int64) == 2 {
+					columns[i] = append(columns[i], int64(binary.LittleEndian.Uint64(data)))
+					data = data[8:]
+				} else {
+					length := binary.LittleEndian.Uint32(data)
+					columns[i] = append(columns[i], string(data[4:4+length]))
+					data = data[4+length:]
+				}
+			}
+		}
+	}
+	return metadata, columns
+}
+
+func TestWriteParquet(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := parquet.NewWriter(&buffer, parquet.Column{Name: "address", Type: parquet.Int64}, parquet.Column{Name: "value", Type: parquet.String})
+	writer.RowGroupSize = 2
+	rows := [][]any{{int64(1), "0x5"}, {int64(2), ""}, {int64(-3), "0x800000000000011000000000000000000000000000000000000000000000000"}}
+	for _, row := range rows {
+		if err := writer.WriteRow(row...); err != nil {
+			t.Fatalf("WriteRow failed with error: %s", err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close failed with error: %s", err)
+	}
+
+	metadata, columns := readParquet(t, buffer.Bytes())
+	if metadata[3].(int64) != 3 || len(metadata[4].([]any)) != 2 {
+		t.Errorf("Expected 3 rows in 2 row groups, got %d rows in %d", metadata[3], len(metadata[4].([]any)))
+	}
+	schema := metadata[2].([]any)
+	if schema[1].(map[int16]any)[4] != "address" || schema[2].(map[int16]any)[4] != "value" || schema[2].(map[int16]any)[6] != int64(0) {
+		t.Errorf("Wrong schema: %v", schema)
+	}
+	expected := [][]any{{int64(1), int64(2), int64(-3)}, {"0x5", "", "0x800000000000011000000000000000000000000000000000000000000000000"}}
+	if !reflect.DeepEqual(columns, expected) {
+		t.Errorf("Wrong columns, expected %v, got %v", expected, columns)
+	}
+}
+
+func TestWriteParquetEmpty(t *testing.T) {
+	var buffer bytes.Buffer
+	if err := parquet.NewWriter(&buffer, parquet.Column{Name: "step", Type: parquet.Int64}).Close(); err != nil {
+		t.Fatalf("Close failed with error: %s", err)
+	}
+	metadata, columns := readParquet(t, buffer.Bytes())
+	if metadata[3].(int64) != 0 || len(columns[0]) != 0 {
+		t.Errorf("Expected no rows, got %v", columns)
+	}
+}
+
+func TestWriteParquetInvalidRow(t *testing.T) {
+	writer := parquet.NewWriter(&bytes.Buffer{}, parquet.Column{Name: "step", Type: parquet.Int64})
+	for _, row := range [][]any{{}, {"1"}, {1}} {
+		if err := writer.WriteRow(row...); !errors.Is(err, parquet.ErrInvalidRow) {
+			t.Errorf("Expected ErrInvalidRow for %v, got %v", row, err)
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package parquet
+
+import "encoding/binary"
+
+// Types of the fields of the thrift compact protocol, in which the metadata of parquet files is encoded
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// Encodes thrift structs with the compact protocol
+// Fields must be written in increasing id order, and each struct closed with end
+type thriftWriter struct {
+	buf     []byte
+	lastIds []int16
+	lastId  int16
+}
+
+func (w *thriftWriter) varint(n uint64) {
+	w.buf = binary.AppendUvarint(w.buf, n)
+}
+
+func (w *thriftWriter) zigzag(n int64) {
+	w.varint(uint64((n << 1) ^ (n >> 63)))
+}
+
+func (w *thriftWriter) fieldHeader(id int16, fieldType byte) {
+	if delta := id - w.lastId; delta > 0 && delta <= 15 {
+		w.buf = append(w.buf, byte(delta)<<4|fieldType)
+	} else {
+		w.buf = append(w.buf, fieldType)
+		w.zigzag(int64(id))
+	}
+	w.lastId = id
+}
+
+func (w *thriftWriter) i32Field(id int16, n int32) {
+	w.fieldHeader(id, thriftI32)
+	w.zigzag(int64(n))
+}
+
+func (w *thriftWriter) i64Field(id int16, n int64) {
+	w.fieldHeader(id, thriftI64)
+	w.zigzag(n)
+}
+
+func (w *thriftWriter) stringField(id int16, s string) {
+	w.fieldHeader(id, thriftBinary)
+	w.binary(s)
+}
+
+func (w *thriftWriter) binary(s string) {
+	w.varint(uint64(len(s)))
+	w.buf = append(w.buf, s...)
+}
+
+// Starts a list field, whose size elements are then written without field headers
+func (w *thriftWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	if size < 15 {
+		w.buf = append(w.buf, byte(size)<<4|elemType)
+	} else {
+		w.buf = append(w.buf, 0xf0|elemType)
+		w.varint(uint64(size))
+	}
+}
+
+// Starts a struct field, or a struct element of a list if id is 0
+func (w *thriftWriter) beginStruct(id int16) {
+	if id != 0 {
+		w.fieldHeader(id, thriftStruct)
+	}
+	w.lastIds = append(w.lastIds, w.lastId)
+	w.lastId = 0
+}
+
+func (w *thriftWriter) end() {
+	w.buf = append(w.buf, 0)
+	w.lastId = w.lastIds[len(w.lastIds)-1]
+	w.lastIds = w.lastIds[:len(w.lastIds)-1]
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// Compiled Cairo 1 contract class, as written by starknet-sierra-compile
+type CasmContractClass struct {
+	Prime             string                `json:"prime"`
+	CompilerVersion   string                `json:"compiler_version"`
+	Bytecode          []string              `json:"bytecode"`
+	Hints             []CasmHints           `json:"hints"`
+	EntryPointsByType CasmEntryPointsByType `json:"entry_points_by_type"`
+}
+
+// Hints at a given pc, each hint is kept in its json form, as their format depends on the hint processor
+type CasmHints struct {
+	Pc    uint
+	Hints []json.RawMessage
+}
+
+// Hints are written as [pc, [hint...]] pairs
+func (h *CasmHints) UnmarshalJSON(data []byte) error {
+	var pair []json.RawMessage
+	if err := json.Unmarshal(data, &pair); err != nil {
+		return err
+	}
+	if len(pair) != 2 {
+		return errors.Errorf("Expected a [pc, hints] pair, got %d elements", len(pair))
+	}
+	if err := json.Unmarshal(pair[0], &h.Pc); err != nil {
+		return err
+	}
+	return json.Unmarshal(pair[1], &h.Hints)
+}
+
+type CasmEntryPointsByType struct {
+	External    []CasmEntryPoint `json:"EXTERNAL"`
+	L1Handler   []CasmEntryPoint `json:"L1_HANDLER"`
+	Constructor []CasmEntryPoint `json:"CONSTRUCTOR"`
+}
+
+type CasmEntryPoint struct {
+	Selector string `json:"selector"`
+	Offset   uint   `json:"offset"`
+	// Builtins taken by the entrypoint, in the order in which their pointers are passed to it
+	Builtins []string `json:"builtins"`
+}
+
+func ParseCasm(jsonPath string) (CasmContractClass, error) {
+	byteValue, err := os.ReadFile(jsonPath)
+	if err != nil {
+		return CasmContractClass{}, ParserError(err)
+	}
+
+	var class CasmContractClass
+	err = json.Unmarshal(byteValue, &class)
+	if err != nil {
+		return CasmContractClass{}, ParserError(err)
+	}
+
+	return class, nil
+}
@@ -97,15 +97,18 @@ func Parse(jsonPath string) (CompiledJson, error) {
 	}
 	defer jsonFile.Close()
 
-	var cJson CompiledJson
-
 	byteValue, _ := ioutil.ReadAll(jsonFile)
-	err = json.Unmarshal(byteValue, &cJson)
+	return ParseBytes(byteValue)
+}
+
+// Parses the contents of a compiled program json file
+func ParseBytes(data []byte) (CompiledJson, error) {
+	var cJson CompiledJson
+	err := json.Unmarshal(data, &cJson)
 
 	if err != nil {
 		return CompiledJson{}, ParserError(err)
 	}
 
 	return cJson, nil
-
 }
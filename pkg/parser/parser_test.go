@@ -40,3 +40,30 @@ func TestData(t *testing.T) {
 		t.Errorf("We should have this data %s, got %s", expected, got.Data)
 	}
 }
+
+func TestParseCasm(t *testing.T) {
+	class, err := parser.ParseCasm("../vm/cairo_run/testdata/cairo1_echo.casm.json")
+	if err != nil {
+		t.Fatalf("Test failed with error: %v", err)
+	}
+	if len(class.Bytecode) != 11 || class.Bytecode[0] != "0x40780017fff7fff" {
+		t.Errorf("Wrong bytecode: %v", class.Bytecode)
+	}
+	if len(class.Hints) != 1 || class.Hints[0].Pc != 0 || len(class.Hints[0].Hints) != 1 {
+		t.Errorf("Wrong hints: %v", class.Hints)
+	}
+	expectedEntrypoints := []parser.CasmEntryPoint{
+		{Selector: "0x1", Offset: 0, Builtins: []string{"range_check"}},
+		{Selector: "0x2", Offset: 0, Builtins: []string{"segment_arena"}},
+	}
+	if !reflect.DeepEqual(class.EntryPointsByType.External, expectedEntrypoints) {
+		t.Errorf("Wrong entrypoints: %v", class.EntryPointsByType.External)
+	}
+}
+
+func TestParseCasmInvalidHints(t *testing.T) {
+	var hints parser.CasmHints
+	if err := hints.UnmarshalJSON([]byte(`[0]`)); err == nil {
+		t.Error("Expected hints without a pc to fail")
+	}
+}
@@ -0,0 +1,196 @@
+package runners
+
+import (
+	"encoding/json"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/utils"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+var ErrAirInputsNoProofMode = RunnerError(errors.New("Air inputs can only be built for runs in proof mode"))
+var ErrAirInputsNotFinalized = RunnerError(errors.New("Air inputs can only be built once the segments have been finalized"))
+
+// Public input of the prover (air_public_input.json)
+type AirPublicInput struct {
+	Layout         string                            `json:"layout"`
+	RcMin          int                               `json:"rc_min"`
+	RcMax          int                               `json:"rc_max"`
+	NSteps         int                               `json:"n_steps"`
+	MemorySegments map[string]MemorySegmentAddresses `json:"memory_segments"`
+	PublicMemory   []PublicMemoryEntry               `json:"public_memory"`
+	DynamicParams  any                               `json:"dynamic_params"`
+}
+
+// Relocated addresses of the first cell of a segment & of the cell after its last used one
+type MemorySegmentAddresses struct {
+	BeginAddr uint `json:"begin_addr"`
+	StopPtr   uint `json:"stop_ptr"`
+}
+
+type PublicMemoryEntry struct {
+	Address uint `json:"address"`
+	// Nil for the memory holes of the public segments (ie: output cells the program skipped), written as null
+	Value *string `json:"value"`
+	Page  uint    `json:"page"`
+}
+
+// Private input of the prover (air_private_input.json)
+type AirPrivateInput struct {
+	TracePath  string
+	MemoryPath string
+	// Private inputs of the builtins, by builtin name
+	Builtins map[string][]any
+}
+
+// The builtins' private inputs are written alongside the trace & memory paths
+func (i AirPrivateInput) MarshalJSON() ([]byte, error) {
+	fields := make(map[string]any, len(i.Builtins)+2)
+	for name, privateInput := range i.Builtins {
+		fields[name] = privateInput
+	}
+	fields["trace_path"] = i.TracePath
+	fields["memory_path"] = i.MemoryPath
+	return json.Marshal(fields)
+}
+
+// Builds the prover's public input of a relocated proof mode run, whose segments have been finalized
+// The public memory cells left empty by the run are included as memory holes, without a value
+func (r *CairoRunner) GetAirPublicInput() (AirPublicInput, error) {
+	if !r.ProofMode {
+		return AirPublicInput{}, ErrAirInputsNoProofMode
+	}
+	if !r.SegmentsFinalized {
+		return AirPublicInput{}, ErrAirInputsNotFinalized
+	}
+
+	// The program & execution segments span from the first to the last pc & ap of the trace
+	var first, last vm.RelocatedTraceEntry
+	nSteps := 0
+	err := r.Vm.ForEachRelocatedTraceEntry(func(entry vm.RelocatedTraceEntry) error {
+		if nSteps == 0 {
+			first = entry
+		}
+		last = entry
+		nSteps++
+		return nil
+	})
+	if err != nil {
+		return AirPublicInput{}, err
+	}
+	if nSteps == 0 {
+		return AirPublicInput{}, RunnerError(errors.New("Empty trace"))
+	}
+	firstPc, _ := first.Pc.ToUint()
+	lastPc, _ := last.Pc.ToUint()
+	firstAp, _ := first.Ap.ToUint()
+	lastAp, _ := last.Ap.ToUint()
+
+	relocationTable, err := r.Vm.GetRelocationTable()
+	if err != nil {
+		return AirPublicInput{}, err
+	}
+	memorySegments := map[string]MemorySegmentAddresses{
+		"program":   {BeginAddr: firstPc, StopPtr: lastPc},
+		"execution": {BeginAddr: firstAp, StopPtr: lastAp},
+	}
+	for _, builtin := range r.Vm.BuiltinRunners {
+		base, stopPtr, err := builtin.GetMemorySegmentAddresses()
+		if err != nil {
+			return AirPublicInput{}, err
+		}
+		segmentBase, err := relocationTable.SegmentBase(base.SegmentIndex)
+		if err != nil {
+			return AirPublicInput{}, RunnerError(err)
+		}
+		memorySegments[builtin.Name()] = MemorySegmentAddresses{BeginAddr: segmentBase, StopPtr: segmentBase + stopPtr.Offset}
+	}
+
+	publicMemoryAddresses, err := r.Vm.GetPublicMemoryAddresses()
+	if err != nil {
+		return AirPublicInput{}, err
+	}
+	outputPages := r.outputPages(relocationTable)
+	publicMemory := make([]PublicMemoryEntry, 0, len(publicMemoryAddresses))
+	for _, address := range publicMemoryAddresses {
+		entry := PublicMemoryEntry{Address: address, Page: outputPages[address]}
+		if value, ok := r.Vm.RelocatedMemory.Get(address); ok {
+			hex := value.ToHexString()
+			entry.Value = &hex
+		}
+		publicMemory = append(publicMemory, entry)
+	}
+
+	rcMin, rcMax, err := r.getPermRangeCheckLimits()
+	if err != nil {
+		return AirPublicInput{}, err
+	}
+
+	return AirPublicInput{
+		Layout:         r.Layout.Name,
+		RcMin:          rcMin,
+		RcMax:          rcMax,
+		NSteps:         nSteps,
+		MemorySegments: memorySegments,
+		PublicMemory:   publicMemory,
+	}, nil
+}
+
+// Returns the page of each relocated output cell that belongs to a page of the output builtin (see AddPage)
+// The cells that are not in the map belong to page 0
+func (r *CairoRunner) outputPages(relocationTable memory.RelocationTable) map[uint]uint {
+	pages := make(map[uint]uint)
+	for _, builtin := range r.Vm.BuiltinRunners {
+		output, ok := builtin.(*builtins.OutputBuiltinRunner)
+		if !ok {
+			continue
+		}
+		base, err := relocationTable.SegmentBase(output.Base().SegmentIndex)
+		if err != nil {
+			continue
+		}
+		data, _ := output.GetAdditionalData().(builtins.OutputAdditionalData)
+		for id, page := range data.Pages {
+			for offset := page.Start; offset < page.Start+page.Size; offset++ {
+				pages[base+offset] = id
+			}
+		}
+	}
+	return pages
+}
+
+// Builds the prover's private input of a run, pointing to the trace & memory files at the given paths
+func (r *CairoRunner) GetAirPrivateInput(tracePath string, memoryPath string) (AirPrivateInput, error) {
+	privateInput := AirPrivateInput{TracePath: tracePath, MemoryPath: memoryPath, Builtins: make(map[string][]any)}
+	for _, builtin := range r.Vm.BuiltinRunners {
+		builtinPrivateInput, err := builtins.AirPrivateInput(builtin, &r.Vm.Segments)
+		if err != nil {
+			return AirPrivateInput{}, err
+		}
+		if builtinPrivateInput != nil {
+			privateInput.Builtins[builtin.Name()] = builtinPrivateInput
+		}
+	}
+	return privateInput, nil
+}
+
+// Returns the minimum & maximum of the values checked by the range check permutation:
+// the (biased) offsets of the executed instructions & the 16-bit parts of the values checked by the builtins
+func (r *CairoRunner) getPermRangeCheckLimits() (int, int, error) {
+	if r.Vm.RcLimitsMin == nil || r.Vm.RcLimitsMax == nil {
+		return 0, 0, RunnerError(errors.New("No range check limits"))
+	}
+	rcMin, rcMax := *r.Vm.RcLimitsMin, *r.Vm.RcLimitsMax
+	for _, builtin := range r.Vm.BuiltinRunners {
+		builtinMin, builtinMax := builtin.GetRangeCheckUsage(&r.Vm.Segments.Memory)
+		if builtinMin != nil {
+			rcMin = utils.MinInt(rcMin, int(*builtinMin))
+		}
+		if builtinMax != nil {
+			rcMax = utils.MaxInt(rcMax, int(*builtinMax))
+		}
+	}
+	return rcMin, rcMax, nil
+}
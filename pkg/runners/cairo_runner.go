@@ -1,11 +1,17 @@
 package runners
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"math/big"
+	"time"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints/dict_manager"
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	"github.com/lambdaclass/cairo-vm.go/pkg/layouts"
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
 	"github.com/lambdaclass/cairo-vm.go/pkg/types"
 	"github.com/lambdaclass/cairo-vm.go/pkg/utils"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
@@ -13,16 +19,21 @@ import (
 	"github.com/pkg/errors"
 )
 
-var ErrRunnerCalledTwice = errors.New("Cairo Runner was called twice")
+// Offset of the initial ap & fp from the execution base in proof mode
+// The cells before them hold the stack prefix: the address of the execution base + 2 & a zero
+const PROOF_MODE_INITIAL_AP_OFFSET = 2
 
 type CairoRunner struct {
-	Program               vm.Program
-	Vm                    vm.VirtualMachine
-	ProgramBase           memory.Relocatable
-	executionBase         memory.Relocatable
-	initialPc             memory.Relocatable
-	initialAp             memory.Relocatable
-	initialFp             memory.Relocatable
+	Program       vm.Program
+	Vm            vm.VirtualMachine
+	ProgramBase   memory.Relocatable
+	executionBase memory.Relocatable
+	initialPc     memory.Relocatable
+	initialAp     memory.Relocatable
+	initialFp     memory.Relocatable
+	// Set through SetInitialRegisters & SetInitialApOffset, override the initial registers computed from the entrypoint
+	initialRegisters      *vm.RunContext
+	initialApOffset       *uint
 	finalPc               *memory.Relocatable
 	mainOffset            uint
 	ProofMode             bool
@@ -31,30 +42,38 @@ type CairoRunner struct {
 	execScopes            types.ExecutionScopes
 	ExecutionPublicMemory *[]uint
 	SegmentsFinalized     bool
+	// Set once InitializeVM succeeds, the vm can't have executed any step before
+	VmInitialized bool
+	// Time spent executing steps & hints executed, see Summary
+	elapsed        time.Duration
+	hintExecutions uint
 }
 
 func NewCairoRunner(program vm.Program, layoutName string, proofMode bool) (*CairoRunner, error) {
-	mainIdentifier, ok := (program.Identifiers)["__main__.main"]
-	main_offset := uint(0)
-	if ok {
-		main_offset = uint(mainIdentifier.PC)
+	layout, err := layouts.NewLayout(layoutName)
+	if err != nil {
+		return nil, RunnerError(err)
 	}
+	return NewCairoRunnerWithLayout(program, layout, proofMode)
+}
 
-	err := utils.CheckBuiltinsSubsequence(program.Builtins)
+// Creates a runner for an already built layout, which can include builtins other than the ones of the standard layouts
+// Builtins that are not part of the standard layouts can be used by the program in any order
+func NewCairoRunnerWithLayout(program vm.Program, layout layouts.CairoLayout, proofMode bool) (*CairoRunner, error) {
+	if err := checkProgramPrime(program.Prime); err != nil {
+		return nil, err
+	}
+	warnUnsupportedCompilerVersion(&program)
+
+	// Programs without a main function (ie: proof mode programs) run from their start
+	main_offset, err := program.GetLabelPc("__main__.main")
 	if err != nil {
-		return nil, errors.New(err.Error())
+		main_offset = 0
 	}
 
-	var layout layouts.CairoLayout
-	switch layoutName {
-	case "plain":
-		layout = layouts.NewPlainLayout()
-	case "small":
-		layout = layouts.NewSmallLayout()
-	case "all_cairo":
-		layout = layouts.NewAllCairoLayout()
-	default:
-		panic("Layout not implemented")
+	err = utils.CheckBuiltinsSubsequence(withoutCustomBuiltins(program.Builtins, layout))
+	if err != nil {
+		return nil, RunnerError(err)
 	}
 
 	runner := CairoRunner{
@@ -68,16 +87,74 @@ func NewCairoRunner(program vm.Program, layoutName string, proofMode bool) (*Cai
 	return &runner, nil
 }
 
+// Programs without a prime are assumed to use the Stark prime
+func checkProgramPrime(prime string) error {
+	if prime == "" {
+		return nil
+	}
+	n, ok := new(big.Int).SetString(prime, 0)
+	if !ok || n.Cmp(lambdaworks.Prime()) != 0 {
+		return PrimeDiffersError(prime)
+	}
+	return nil
+}
+
+// Programs compiled by unsupported versions can still run, as long as they only use hints this vm implements
+// Programs that don't record their compiler version (ie: built in memory) are assumed to be supported
+func warnUnsupportedCompilerVersion(program *vm.Program) {
+	version, err := program.CompilerVersion()
+	if errors.Is(err, vm.ErrUnknownCompilerVersion) {
+		return
+	}
+	if err != nil {
+		logging.Default().Warn("Program has an invalid compiler version", logging.F("version", program.Metadata.CompilerVersion))
+		return
+	}
+	if !version.IsSupported() {
+		logging.Default().Warn("Program was compiled by an unsupported compiler version, some hints may not be recognized",
+			logging.F("version", version), logging.F("min_supported", vm.MinSupportedCompilerVersion), logging.F("max_unsupported", vm.MaxUnsupportedCompilerVersion))
+	}
+}
+
+// Sets the function of the __main__ module the run starts from instead of main (ie: "test" runs __main__.test)
+// Has no effect in proof mode, where the run starts from __start__
+func (r *CairoRunner) SetEntrypoint(name string) error {
+	offset, err := r.Program.GetLabelPc("__main__." + name)
+	if err != nil {
+		return RunnerError(err)
+	}
+	r.mainOffset = offset
+	return nil
+}
+
+// Filters out the builtins of the layout that can't be created through the builtins registry
+func withoutCustomBuiltins(programBuiltins []string, layout layouts.CairoLayout) []string {
+	customBuiltins := make(map[string]bool)
+	for _, builtin := range layout.Builtins {
+		if !builtins.IsRegisteredBuiltin(builtin.Name()) {
+			customBuiltins[builtin.Name()] = true
+		}
+	}
+	standardBuiltins := make([]string, 0, len(programBuiltins))
+	for _, name := range programBuiltins {
+		if !customBuiltins[name] {
+			standardBuiltins = append(standardBuiltins, name)
+		}
+	}
+	return standardBuiltins
+}
+
 // Performs the initialization step, returns the end pointer (pc upon which execution should stop)
+// Runs InitializeBuiltins, InitializeSegments, InitializeMainEntrypoint & InitializeVM, which can also be called one by one
 func (r *CairoRunner) Initialize() (memory.Relocatable, error) {
 	err := r.InitializeBuiltins()
 	if err != nil {
-		return memory.Relocatable{}, errors.New(err.Error())
+		return memory.Relocatable{}, err
 	}
 	r.InitializeSegments()
-	end, err := r.initializeMainEntrypoint()
+	end, err := r.InitializeMainEntrypoint()
 	if err == nil {
-		err = r.initializeVM()
+		err = r.InitializeVM()
 	}
 	return end, err
 }
@@ -104,7 +181,7 @@ func (r *CairoRunner) InitializeBuiltins() error {
 	}
 
 	if len(programBuiltins) != 0 {
-		return errors.Errorf("Builtin(s) %v not present in layout %s", programBuiltins, r.Layout.Name)
+		return RunnerError(fmt.Errorf("Builtin(s) %v %w %s", programBuiltins, ErrNoBuiltinForInstance, r.Layout.Name))
 	}
 
 	r.Vm.BuiltinRunners = builtinRunners
@@ -112,10 +189,26 @@ func (r *CairoRunner) InitializeBuiltins() error {
 	return nil
 }
 
+// Returns the builtin runners used by the program, in the order of the program's builtins list
+// This is the order in which the main entrypoint receives & returns the builtin pointers, which can differ from the
+// order of the builtin runners (and their segments) when the layout has custom builtins
+func (r *CairoRunner) ProgramBuiltinRunners() []builtins.BuiltinRunner {
+	builtinRunners := make([]builtins.BuiltinRunner, 0, len(r.Program.Builtins))
+	for _, name := range r.Program.Builtins {
+		for _, builtinRunner := range r.Vm.BuiltinRunners {
+			if builtinRunner.Name() == name {
+				builtinRunners = append(builtinRunners, builtinRunner)
+				break
+			}
+		}
+	}
+	return builtinRunners
+}
+
 // Creates program, execution and builtin segments
 func (r *CairoRunner) InitializeSegments() {
 	// Program Segment
-	r.ProgramBase = r.Vm.Segments.AddSegment()
+	r.ProgramBase = r.Vm.Segments.AddSegmentWithSizeHint(uint(len(r.Program.Data)))
 	// Execution Segment
 	r.executionBase = r.Vm.Segments.AddSegment()
 	// Builtin Segments
@@ -134,11 +227,7 @@ func (r *CairoRunner) initializeState(entrypoint uint, stack *[]memory.MaybeRelo
 		_, err = r.Vm.Segments.LoadData(r.executionBase, stack)
 	}
 	// Mark data segment as accessed
-	base := r.ProgramBase
-	var i uint
-	for i = 0; i < uint(len(r.Program.Data)); i++ {
-		r.Vm.Segments.Memory.MarkAsAccessed(memory.NewRelocatable(base.SegmentIndex, base.Offset+i))
-	}
+	r.Vm.MarkAddressRangeAccessed(r.ProgramBase, uint(len(r.Program.Data)))
 	return err
 }
 
@@ -155,18 +244,19 @@ func (r *CairoRunner) initializeFunctionEntrypoint(entrypoint uint, stack *[]mem
 }
 
 // Initializes memory, initial register values & returns the end pointer (final pc) to run from the main entrypoint
-func (r *CairoRunner) initializeMainEntrypoint() (memory.Relocatable, error) {
+// Has to be called after InitializeBuiltins & InitializeSegments, and followed by InitializeVM
+func (r *CairoRunner) InitializeMainEntrypoint() (memory.Relocatable, error) {
 	// When running from main entrypoint, only up to 11 values will be written (9 builtin bases + end + return_fp)
 	stack := make([]memory.MaybeRelocatable, 0, 11)
 	// Append builtins initial stack to stack
-	for i := range r.Vm.BuiltinRunners {
-		for _, val := range r.Vm.BuiltinRunners[i].InitialStack() {
+	for _, builtinRunner := range r.ProgramBuiltinRunners() {
+		for _, val := range builtinRunner.InitialStack() {
 			stack = append(stack, val)
 		}
 	}
 
 	if r.ProofMode {
-		basePlusTwo := memory.NewRelocatable(r.executionBase.SegmentIndex, r.executionBase.Offset+2)
+		basePlusTwo := r.executionBase.AddUint(PROOF_MODE_INITIAL_AP_OFFSET)
 		stackPrefix := []memory.MaybeRelocatable{*memory.NewMaybeRelocatableRelocatable(basePlusTwo), *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(0))}
 
 		stackPrefix = append(stackPrefix, stack...)
@@ -181,8 +271,7 @@ func (r *CairoRunner) initializeMainEntrypoint() (memory.Relocatable, error) {
 
 		r.initializeState(r.Program.Start, &stackPrefix)
 
-		initialFp := memory.NewRelocatable(r.executionBase.SegmentIndex, r.executionBase.Offset+2)
-		r.initialFp = initialFp
+		r.initialFp = basePlusTwo
 		r.initialAp = r.initialFp
 
 		return memory.NewRelocatable(r.ProgramBase.SegmentIndex, r.ProgramBase.Offset+r.Program.End), nil
@@ -193,16 +282,64 @@ func (r *CairoRunner) initializeMainEntrypoint() (memory.Relocatable, error) {
 }
 
 // Initializes the vm's run_context, adds builtin validation rules & validates memory
-func (r *CairoRunner) initializeVM() error {
-	r.Vm.RunContext.Ap = r.initialAp
-	r.Vm.RunContext.Fp = r.initialFp
-	r.Vm.RunContext.Pc = r.initialPc
+// This is the last initialization step, after it the vm can run until the end pointer (see RunUntilPC)
+func (r *CairoRunner) InitializeVM() error {
+	if r.initialApOffset != nil {
+		r.initialAp = r.executionBase.AddUint(*r.initialApOffset)
+	}
+	if r.initialRegisters != nil {
+		r.initialPc = r.initialRegisters.GetPc()
+		r.initialAp = r.initialRegisters.GetAp()
+		r.initialFp = r.initialRegisters.GetFp()
+	}
+	r.Vm.RunContext = r.InitialRegisters()
 	// Add validation rules
 	for i := range r.Vm.BuiltinRunners {
 		r.Vm.BuiltinRunners[i].AddValidationRule(&r.Vm.Segments.Memory)
 	}
 	// Apply validation rules to memory
-	return r.Vm.Segments.Memory.ValidateExistingMemory()
+	if err := r.Vm.Segments.Memory.ValidateExistingMemory(); err != nil {
+		return err
+	}
+	r.VmInitialized = true
+	return nil
+}
+
+// Returns the registers the vm starts the run with
+// They are computed by Initialize (or RunFromEntrypoint), unless set through SetInitialRegisters
+func (r *CairoRunner) InitialRegisters() vm.RunContext {
+	return vm.NewRunContext(r.initialPc, r.initialAp, r.initialFp)
+}
+
+// Sets the registers the vm starts the run with, instead of the ones computed from the entrypoint
+// Must be called before the runner is initialized, takes precedence over SetInitialApOffset
+func (r *CairoRunner) SetInitialRegisters(registers vm.RunContext) {
+	r.initialRegisters = &registers
+}
+
+// Sets the offset of the initial ap from the execution base, instead of starting right after the initial stack
+// (or at PROOF_MODE_INITIAL_AP_OFFSET in proof mode). The initial fp is left unchanged
+// Must be called before the runner is initialized
+func (r *CairoRunner) SetInitialApOffset(offset uint) {
+	r.initialApOffset = &offset
+}
+
+// Returns an independent copy of the runner, ie: to run a speculative branch of the execution & discard it
+// Its vm is a fork of the runner's vm (see vm.VirtualMachine.Fork) & its execution scopes are a clone of the runner's,
+// so that the builtins' data (ie: signatures, stop pointers) & the dicts of the dict manager aren't shared
+func (r *CairoRunner) Fork() (*CairoRunner, error) {
+	forkedVm, err := r.Vm.Fork()
+	if err != nil {
+		return nil, err
+	}
+	fork := *r
+	fork.Vm = *forkedVm
+	fork.execScopes = *r.execScopes.Clone()
+	if r.ExecutionPublicMemory != nil {
+		publicMemory := append([]uint(nil), *r.ExecutionPublicMemory...)
+		fork.ExecutionPublicMemory = &publicMemory
+	}
+	return &fork, nil
 }
 
 func (r *CairoRunner) BuildHintDataMap(hintProcessor vm.HintProcessor) (map[uint][]any, error) {
@@ -223,28 +360,55 @@ func (r *CairoRunner) BuildHintDataMap(hintProcessor vm.HintProcessor) (map[uint
 }
 
 func (r *CairoRunner) RunUntilPC(end memory.Relocatable, hintProcessor vm.HintProcessor) error {
+	return r.RunUntilPCContext(context.Background(), end, hintProcessor)
+}
+
+// Same as RunUntilPC, stopping with ErrRunCancelled once ctx is done (ie: cancelled or timed out)
+// The context is checked every CONTEXT_CHECK_INTERVAL steps, so that checking it doesn't slow down the run
+func (r *CairoRunner) RunUntilPCContext(ctx context.Context, end memory.Relocatable, hintProcessor vm.HintProcessor) error {
+	if err := ctx.Err(); err != nil {
+		return RunCancelledError(err, r.Vm.CurrentStep)
+	}
 	hintDataMap, err := r.BuildHintDataMap(hintProcessor)
 	if err != nil {
 		return err
 	}
 	constants := r.Program.ExtractConstants()
-	for r.Vm.RunContext.Pc != end &&
-		(r.Vm.RunResources == nil || !r.Vm.RunResources.Consumed()) {
-		err := r.Vm.Step(hintProcessor, &hintDataMap, &constants, &r.execScopes)
+	start := time.Now()
+	defer func() { r.elapsed += time.Since(start) }()
+	for steps := uint(1); r.Vm.RunContext.Pc != end &&
+		(r.Vm.RunResources == nil || !r.Vm.RunResources.Consumed()); steps++ {
+		if steps%CONTEXT_CHECK_INTERVAL == 0 {
+			if err := ctx.Err(); err != nil {
+				return RunCancelledError(err, r.Vm.CurrentStep)
+			}
+		}
+		registers := r.Vm.RunContext
+		err := r.step(hintProcessor, &hintDataMap, &constants)
 		if err != nil {
 			return err
 		}
+		// A step that leaves the registers untouched (ie: jmp rel 0) will be repeated forever,
+		// unless a hint at its pc changes the state of the vm
+		if r.Vm.RunContext == registers && len(hintDataMap[registers.Pc.Offset]) == 0 {
+			return InfiniteLoopError(registers.Pc)
+		}
 		if r.Vm.RunResources != nil {
 			r.Vm.RunResources.ConsumeStep()
 		}
 	}
 	if r.Vm.RunContext.Pc != end {
-		return errors.New("Could not reach the end of the program. RunResources has no remaining steps.")
+		return ErrUnfinishedExecution
 	}
 	return nil
 }
 
 func (runner *CairoRunner) EndRun(disableTracePadding bool, disableFinalizeAll bool, hintProcessor vm.HintProcessor) error {
+	return runner.EndRunContext(context.Background(), disableTracePadding, disableFinalizeAll, hintProcessor)
+}
+
+// Same as EndRun, stopping with ErrRunCancelled once ctx is done while padding the trace of a proof mode run
+func (runner *CairoRunner) EndRunContext(ctx context.Context, disableTracePadding bool, disableFinalizeAll bool, hintProcessor vm.HintProcessor) error {
 	if runner.RunEnded {
 		return ErrRunnerCalledTwice
 	}
@@ -263,26 +427,26 @@ func (runner *CairoRunner) EndRun(disableTracePadding bool, disableFinalizeAll b
 
 	runner.Vm.Segments.ComputeEffectiveSizes()
 	if runner.ProofMode && !disableTracePadding {
-		err := runner.RunUntilNextPowerOfTwo(hintProcessor)
+		err := runner.runUntilNextPowerOfTwo(ctx, hintProcessor)
 		if err != nil {
 			return err
 		}
 
 		for true {
 			err := runner.CheckUsedCells()
-			if errors.Unwrap(err) == memory.ErrInsufficientAllocatedCells {
+			if errors.Is(err, memory.ErrInsufficientAllocatedCells) {
 			} else if err != nil {
 				return err
 			} else {
 				break
 			}
 
-			err = runner.RunForSteps(1, hintProcessor)
+			err = runner.RunForStepsContext(ctx, 1, hintProcessor)
 			if err != nil {
 				return err
 			}
 
-			err = runner.RunUntilNextPowerOfTwo(hintProcessor)
+			err = runner.runUntilNextPowerOfTwo(ctx, hintProcessor)
 			if err != nil {
 				return err
 			}
@@ -299,7 +463,7 @@ func (r *CairoRunner) FinalizeSegments() error {
 	}
 
 	if !r.RunEnded {
-		return errors.New("Called Finalize Segments before run had ended")
+		return ErrFinalizeNoEndRun
 	}
 
 	var size = new(uint)
@@ -316,7 +480,7 @@ func (r *CairoRunner) FinalizeSegments() error {
 	publicMemory = make([]uint, 0)
 	execBase := r.executionBase
 	if r.ExecutionPublicMemory == nil {
-		return errors.New("Called Finalized Segments without an Execution Public Memory")
+		return ErrNoExecPublicMemory
 	}
 
 	for _, elem := range *r.ExecutionPublicMemory {
@@ -346,24 +510,45 @@ func (r *CairoRunner) FinalizeSegments() error {
 	return nil
 }
 
+// Relocates the memory & trace of the run into a single address space, which is the last step of a run
+// In proof mode, FinalizeSegments has to be called first so that the relocated segments have their final sizes
+func (r *CairoRunner) Relocate() error {
+	if !r.RunEnded {
+		return ErrRelocateNoEndRun
+	}
+	return r.Vm.Relocate()
+}
+
 func (r *CairoRunner) ReadReturnValues() error {
 	if !r.RunEnded {
-		return errors.New("Tried to read return values before run ended")
+		return ErrReadReturnValuesNoEndRun
 	}
 
 	pointer := r.Vm.RunContext.Ap
 
-	for i := len(r.Vm.BuiltinRunners) - 1; i >= 0; i-- {
-		newPointer, err := r.Vm.BuiltinRunners[i].FinalStack(&r.Vm.Segments, pointer)
+	programBuiltinRunners := r.ProgramBuiltinRunners()
+	usedBuiltins := map[string]struct{}{}
+	for i := len(programBuiltinRunners) - 1; i >= 0; i-- {
+		newPointer, err := programBuiltinRunners[i].FinalStack(&r.Vm.Segments, pointer)
 		if err != nil {
 			return err
 		}
+		usedBuiltins[programBuiltinRunners[i].Name()] = struct{}{}
 
 		pointer = newPointer
 	}
+	// Builtins not used by the program (only present in proof mode) don't have values on the stack,
+	// but still need their stop pointers
+	for _, builtinRunner := range r.Vm.BuiltinRunners {
+		if _, used := usedBuiltins[builtinRunner.Name()]; !used {
+			if _, err := builtinRunner.FinalStack(&r.Vm.Segments, pointer); err != nil {
+				return err
+			}
+		}
+	}
 
 	if r.SegmentsFinalized {
-		return errors.New("Failed Adding Return Values")
+		return ErrFailedAddingReturnValues
 	}
 
 	if r.ProofMode {
@@ -386,6 +571,81 @@ func (r *CairoRunner) ReadReturnValues() error {
 
 }
 
+// Writes the values of the output builtin's segment to the writer, one per line
+// Relocatable values are written as {segment:offset} & missing cells as <missing>
+func (r *CairoRunner) WriteOutput(writer io.Writer) error {
+	return r.Vm.WriteOutput(writer)
+}
+
+// Assigns the variables in the current execution scope, which is the main scope until the run starts
+// (ie: the globals read by cairo-lang's hints)
+func (r *CairoRunner) AssignScopeVariables(variables map[string]any) {
+	for name, value := range variables {
+		r.execScopes.AssignOrUpdateVariable(name, value)
+	}
+}
+
+// Returns the variables (names & types) of the runner's execution scopes, from the main scope to the innermost one
+func (r *CairoRunner) InspectExecutionScopes() [][]types.ScopeVariable {
+	return r.execScopes.Inspect()
+}
+
+// Returns the state of the dicts of the dict manager in the current execution scope, sorted by segment index
+// Returns nil if no dict has been created in the current scope
+func (r *CairoRunner) DumpDicts() []dict_manager.DictTrackerDump {
+	dictManager, err := types.FetchScopeVar[*dict_manager.DictManager]("__dict_manager", &r.execScopes)
+	if err != nil {
+		return nil
+	}
+	trackers := dictManager.Trackers()
+	dumps := make([]dict_manager.DictTrackerDump, 0, len(trackers))
+	for _, tracker := range trackers {
+		dumps = append(dumps, tracker.Dump())
+	}
+	return dumps
+}
+
+// Returns the values of the output builtin's segment
+// Fails if a cell is missing or holds a relocatable value
+func (r *CairoRunner) GetOutputs() ([]lambdaworks.Felt, error) {
+	return r.Vm.GetOutputs()
+}
+
+// Returns the additional data of the builtins that have any, indexed by builtin name
+func (r *CairoRunner) GetAdditionalData() map[string]builtins.BuiltinAdditionalData {
+	additionalData := make(map[string]builtins.BuiltinAdditionalData)
+	for _, builtin := range r.Vm.BuiltinRunners {
+		if data := builtin.GetAdditionalData(); data != nil {
+			additionalData[builtin.Name()] = data
+		}
+	}
+	return additionalData
+}
+
+// Restores the additional data of the builtins, indexed by builtin name
+// Fails if a builtin isn't used by the run
+func (r *CairoRunner) SetAdditionalData(additionalData map[string]builtins.BuiltinAdditionalData) error {
+	for name, data := range additionalData {
+		builtin, err := r.Vm.GetBuiltinRunner(name)
+		if err != nil {
+			return err
+		}
+		if err := (*builtin).SetAdditionalData(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the n values located right below ap once the run has ended
+// These are the return values of main, or of the function ran through RunFromEntrypoint
+func (r *CairoRunner) GetReturnValues(n uint) ([]memory.MaybeRelocatable, error) {
+	if !r.RunEnded {
+		return nil, ErrGetReturnValuesNoEndRun
+	}
+	return r.Vm.GetReturnValues(n)
+}
+
 func (runner *CairoRunner) CheckUsedCells() error {
 	for _, builtin := range runner.Vm.BuiltinRunners {
 		// I guess we call this just in case it errors out, even though later on we also call it?
@@ -413,7 +673,9 @@ func (runner *CairoRunner) CheckUsedCells() error {
 	return nil
 }
 
-func (runner *CairoRunner) CheckMemoryUsage() error {
+// Returns the memory units used by the public memory, the instructions & the builtins, and the total memory units
+// available in the layout for the current step
+func (runner *CairoRunner) GetMemoryUnits() (uint, uint, error) {
 	instance := runner.Layout
 
 	var builtinsMemoryUnits uint = 0
@@ -421,30 +683,37 @@ func (runner *CairoRunner) CheckMemoryUsage() error {
 	for _, builtin := range runner.Vm.BuiltinRunners {
 		result, err := builtin.GetAllocatedMemoryUnits(&runner.Vm.Segments, runner.Vm.CurrentStep)
 		if err != nil {
-			return err
+			return 0, 0, err
 		}
 
 		builtinsMemoryUnits += result
 	}
 
 	totalMemoryUnits := instance.MemoryUnitsPerStep * runner.Vm.CurrentStep
-	publicMemoryUnits := totalMemoryUnits / instance.PublicMemoryFraction
-	remainder := totalMemoryUnits % instance.PublicMemoryFraction
-
-	if remainder != 0 {
-		return errors.Errorf("Total Memory units was not divisible by the Public Memory Fraction. TotalMemoryUnits: %d PublicMemoryFraction: %d", totalMemoryUnits, instance.PublicMemoryFraction)
+	publicMemoryUnits, err := utils.SafeDiv(totalMemoryUnits, instance.PublicMemoryFraction)
+	if err != nil {
+		return 0, 0, errors.Errorf("Total Memory units was not divisible by the Public Memory Fraction. TotalMemoryUnits: %d PublicMemoryFraction: %d", totalMemoryUnits, instance.PublicMemoryFraction)
 	}
 
 	instructionMemoryUnits := 4 * runner.Vm.CurrentStep
-	unusedMemoryUnits := totalMemoryUnits - (publicMemoryUnits + instructionMemoryUnits + builtinsMemoryUnits)
+
+	return publicMemoryUnits + instructionMemoryUnits + builtinsMemoryUnits, totalMemoryUnits, nil
+}
+
+// Checks that the memory units left unused by the run are enough to fill the memory holes
+func (runner *CairoRunner) CheckMemoryUsage() error {
+	usedMemoryUnits, totalMemoryUnits, err := runner.GetMemoryUnits()
+	if err != nil {
+		return err
+	}
 
 	memoryAddressHoles, err := runner.GetMemoryHoles()
 	if err != nil {
 		return err
 	}
 
-	if unusedMemoryUnits < memoryAddressHoles {
-		return memory.InsufficientAllocatedCellsError(unusedMemoryUnits, memoryAddressHoles)
+	if usedMemoryUnits > totalMemoryUnits || totalMemoryUnits-usedMemoryUnits < memoryAddressHoles {
+		return memory.InsufficientAllocatedCellsError(usedMemoryUnits+memoryAddressHoles, totalMemoryUnits)
 	}
 
 	return nil
@@ -454,10 +723,13 @@ func (runner *CairoRunner) GetMemoryHoles() (uint, error) {
 	return runner.Vm.Segments.GetMemoryHoles(uint(len(runner.Vm.BuiltinRunners)))
 }
 
-func (runner *CairoRunner) CheckDilutedCheckUsage() error {
+// Returns the diluted check units used by the builtins, and the total diluted check units available in the layout for
+// the current step
+// Both are zero if the layout has no diluted pool
+func (runner *CairoRunner) GetDilutedCheckUnits() (uint, uint, error) {
 	dilutedPoolInstance := runner.Layout.DilutedPoolInstance
 	if dilutedPoolInstance == nil {
-		return nil
+		return 0, 0, nil
 	}
 
 	var usedUnitsByBuiltins uint = 0
@@ -465,26 +737,38 @@ func (runner *CairoRunner) CheckDilutedCheckUsage() error {
 	for _, builtin := range runner.Vm.BuiltinRunners {
 		usedUnits := builtin.GetUsedDilutedCheckUnits(dilutedPoolInstance.Spacing, dilutedPoolInstance.NBits)
 
-		ratio := builtin.Ratio()
-		if ratio == 0 {
-			ratio = 1
-		}
-		multiplier, err := utils.SafeDiv(runner.Vm.CurrentStep, ratio)
-
-		if err != nil {
-			return err
+		// Builtins without a ratio use their units once
+		var multiplier uint = 1
+		if ratio := builtin.Ratio(); ratio != 0 {
+			var err error
+			multiplier, err = utils.SafeDiv(runner.Vm.CurrentStep, ratio)
+			if err != nil {
+				return 0, 0, err
+			}
 		}
 
 		usedUnitsByBuiltins += usedUnits * multiplier
 	}
 
-	var dilutedUnits uint = dilutedPoolInstance.UnitsPerStep * runner.Vm.CurrentStep
-	var unusedDilutedUnits uint = dilutedUnits - usedUnitsByBuiltins
+	return usedUnitsByBuiltins, dilutedPoolInstance.UnitsPerStep * runner.Vm.CurrentStep, nil
+}
+
+// Checks that the diluted check units left unused by the builtins are enough to hold every diluted value
+func (runner *CairoRunner) CheckDilutedCheckUsage() error {
+	dilutedPoolInstance := runner.Layout.DilutedPoolInstance
+	if dilutedPoolInstance == nil {
+		return nil
+	}
+
+	usedUnitsByBuiltins, dilutedUnits, err := runner.GetDilutedCheckUnits()
+	if err != nil {
+		return err
+	}
 
 	var dilutedUsageUpperBound uint = 1 << dilutedPoolInstance.NBits
 
-	if unusedDilutedUnits < dilutedUsageUpperBound {
-		return memory.InsufficientAllocatedCellsError(unusedDilutedUnits, dilutedUsageUpperBound)
+	if usedUnitsByBuiltins > dilutedUnits || dilutedUnits-usedUnitsByBuiltins < dilutedUsageUpperBound {
+		return memory.InsufficientAllocatedCellsError(usedUnitsByBuiltins+dilutedUsageUpperBound, dilutedUnits)
 	}
 
 	return nil
@@ -496,11 +780,11 @@ func (runner *CairoRunner) CheckRangeCheckUsage() error {
 	for _, builtin := range runner.Vm.BuiltinRunners {
 		resultMin, resultMax := builtin.GetRangeCheckUsage(&runner.Vm.Segments.Memory)
 
-		if resultMin != nil {
+		if resultMin != nil && (rcMin == nil || *resultMin < *rcMin) {
 			rcMin = resultMin
 		}
 
-		if resultMax != nil {
+		if resultMax != nil && (rcMax == nil || *resultMax > *rcMax) {
 			rcMax = resultMax
 		}
 	}
@@ -520,7 +804,7 @@ func (runner *CairoRunner) CheckRangeCheckUsage() error {
 	var rcUnitsUsedByBuiltins uint = 0
 
 	for _, builtin := range runner.Vm.BuiltinRunners {
-		usedUnits, err := builtin.GetUsedPermRangeCheckLimits(&runner.Vm.Segments, runner.Vm.CurrentStep)
+		usedUnits, err := builtin.GetUsedPermRangeCheckUnits(&runner.Vm.Segments, runner.Vm.CurrentStep)
 		if err != nil {
 			return err
 		}
@@ -538,18 +822,34 @@ func (runner *CairoRunner) CheckRangeCheckUsage() error {
 }
 
 func (runner *CairoRunner) RunForSteps(steps uint, hintProcessor vm.HintProcessor) error {
+	return runner.RunForStepsContext(context.Background(), steps, hintProcessor)
+}
+
+// Same as RunForSteps, stopping with ErrRunCancelled once ctx is done
+// The context is checked every CONTEXT_CHECK_INTERVAL steps, as done by RunUntilPCContext
+func (runner *CairoRunner) RunForStepsContext(ctx context.Context, steps uint, hintProcessor vm.HintProcessor) error {
+	if err := ctx.Err(); err != nil {
+		return RunCancelledError(err, runner.Vm.CurrentStep)
+	}
 	hintDataMap, err := runner.BuildHintDataMap(hintProcessor)
 	if err != nil {
 		return err
 	}
 	constants := runner.Program.ExtractConstants()
-	var remainingSteps int
-	for remainingSteps = int(steps); remainingSteps > 0; remainingSteps-- {
+	start := time.Now()
+	defer func() { runner.elapsed += time.Since(start) }()
+	for step := uint(1); step <= steps; step++ {
+		remainingSteps := steps - step + 1
+		if step%CONTEXT_CHECK_INTERVAL == 0 {
+			if err := ctx.Err(); err != nil {
+				return RunCancelledError(err, runner.Vm.CurrentStep)
+			}
+		}
 		if runner.finalPc != nil && *runner.finalPc == runner.Vm.RunContext.Pc {
-			return &vm.VirtualMachineError{Msg: fmt.Sprintf("EndOfProgram: %d", remainingSteps)}
+			return vm.EndOfProgramError(remainingSteps)
 		}
 
-		err := runner.Vm.Step(hintProcessor, &hintDataMap, &constants, &runner.execScopes)
+		err := runner.step(hintProcessor, &hintDataMap, &constants)
 		if err != nil {
 			return err
 		}
@@ -558,16 +858,26 @@ func (runner *CairoRunner) RunForSteps(steps uint, hintProcessor vm.HintProcesso
 	return nil
 }
 
+// Runs a single step of the vm, counting the hints it executes for Summary
+func (runner *CairoRunner) step(hintProcessor vm.HintProcessor, hintDataMap *map[uint][]any, constants *map[string]lambdaworks.Felt) error {
+	runner.hintExecutions += uint(len((*hintDataMap)[runner.Vm.RunContext.Pc.Offset]))
+	return runner.Vm.Step(hintProcessor, hintDataMap, constants, &runner.execScopes)
+}
+
 func (runner *CairoRunner) RunUntilSteps(steps uint, hintProcessor vm.HintProcessor) error {
 	return runner.RunForSteps(steps-runner.Vm.CurrentStep, hintProcessor)
 }
 
 func (runner *CairoRunner) RunUntilNextPowerOfTwo(hintProcessor vm.HintProcessor) error {
-	return runner.RunUntilSteps(utils.NextPowOf2(runner.Vm.CurrentStep), hintProcessor)
+	return runner.runUntilNextPowerOfTwo(context.Background(), hintProcessor)
+}
+
+func (runner *CairoRunner) runUntilNextPowerOfTwo(ctx context.Context, hintProcessor vm.HintProcessor) error {
+	return runner.RunForStepsContext(ctx, utils.NextPowOf2(runner.Vm.CurrentStep)-runner.Vm.CurrentStep, hintProcessor)
 }
 
 func (runner *CairoRunner) GetExecutionResources() (ExecutionResources, error) {
-	nSteps := uint(len(runner.Vm.Trace))
+	nSteps := uint(runner.Vm.TraceLen())
 	if nSteps == 0 {
 		nSteps = runner.Vm.CurrentStep
 	}
@@ -593,11 +903,16 @@ func (runner *CairoRunner) GetExecutionResources() (ExecutionResources, error) {
 Runs a cairo program from a give entrypoint, indicated by its pc offset, with the given arguments.
 If `verifySecure` is set to true, [verifySecureRunner] will be called to run extra verifications.
 `programSegmentSize` is only used by the [verifySecureRunner] function and will be ignored if `verifySecure` is set to false.
-Each arg can be either MaybeRelocatable, []MaybeRelocatable or [][]MaybeRelocatable
+Each arg can be either MaybeRelocatable, *big.Int, []MaybeRelocatable or [][]MaybeRelocatable
+The args are validated before the run starts, see ValidateEntrypointArgs
 */
 func (runner *CairoRunner) RunFromEntrypoint(entrypoint uint, args []any, hintProcessor vm.HintProcessor, runResources *vm.RunResources, verifySecure bool, programSegmentSize *uint) error {
+	args, err := runner.entrypointArgs(args)
+	if err != nil {
+		return err
+	}
 	runner.Vm.RunResources = runResources
-	stack := make([]memory.MaybeRelocatable, 0)
+	stack := make([]memory.MaybeRelocatable, 0, len(args))
 	for _, arg := range args {
 		val, err := runner.Vm.Segments.GenArg(arg)
 		if err != nil {
@@ -610,7 +925,7 @@ func (runner *CairoRunner) RunFromEntrypoint(entrypoint uint, args []any, hintPr
 	if err != nil {
 		return err
 	}
-	err = runner.initializeVM()
+	err = runner.InitializeVM()
 	if err != nil {
 		return err
 	}
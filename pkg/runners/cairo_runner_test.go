@@ -2,18 +2,25 @@ package runners_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
 	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints/dict_manager"
 	"github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_utils"
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/layouts"
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
 	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
 	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
 )
 
 func TestNewCairoRunnerInvalidBuiltin(t *testing.T) {
@@ -28,6 +35,44 @@ func TestNewCairoRunnerInvalidBuiltin(t *testing.T) {
 		t.Errorf("Expected creating a CairoRunner with fake builtin to fail")
 	}
 }
+func TestNewCairoRunnerBuiltinsOutOfOrder(t *testing.T) {
+	program := vm.Program{Identifiers: map[string]vm.Identifier{}, Builtins: []string{builtins.RANGE_CHECK_BUILTIN_NAME, builtins.OUTPUT_BUILTIN_NAME}}
+	if _, err := runners.NewCairoRunner(program, "all_cairo", false); !errors.Is(err, runners.ErrRunner) {
+		t.Errorf("Expected a runner error for builtins out of order, got %v", err)
+	}
+}
+
+func TestNewCairoRunnerPrime(t *testing.T) {
+	for _, prime := range []string{"", lambdaworks.CAIRO_PRIME_HEX, "3618502788666131213697322783095070105623107215331596699973092056135872020481"} {
+		program := vm.Program{Identifiers: map[string]vm.Identifier{}, Prime: prime}
+		if _, err := runners.NewCairoRunner(program, "plain", false); err != nil {
+			t.Errorf("The prime %q should be accepted: %s", prime, err)
+		}
+	}
+	// Goldilocks prime
+	program := vm.Program{Identifiers: map[string]vm.Identifier{}, Prime: "0xffffffff00000001"}
+	_, err := runners.NewCairoRunner(program, "plain", false)
+	if !errors.Is(err, runners.ErrPrimeDiffers) || !errors.Is(err, runners.ErrRunner) {
+		t.Errorf("Expected ErrPrimeDiffers, got %v", err)
+	}
+}
+
+func TestNewCairoRunnerCompilerVersionWarning(t *testing.T) {
+	previous := logging.Default()
+	defer logging.SetDefault(previous)
+	for version, warned := range map[string]bool{"": false, "0.11.0": false, "0.9.1": true, "0.13.0": true, "latest": true} {
+		var logs bytes.Buffer
+		logging.SetDefault(logging.NewLogger(&logs, logging.LevelWarn))
+		program := vm.Program{Identifiers: map[string]vm.Identifier{}, Metadata: vm.ProgramMetadata{CompilerVersion: version}}
+		if _, err := runners.NewCairoRunner(program, "plain", false); err != nil {
+			t.Errorf("Programs compiled by %q should be accepted: %s", version, err)
+		}
+		if (logs.Len() != 0) != warned {
+			t.Errorf("Wrong warnings for compiler version %q: %q", version, logs.String())
+		}
+	}
+}
+
 func TestInitializeRunnerNoBuiltinsNoProofModeEmptyProgram(t *testing.T) {
 	// Create a Program with empty data
 	program_data := make([]memory.MaybeRelocatable, 0)
@@ -93,6 +138,37 @@ func TestInitializeRunnerNoBuiltinsNoProofModeEmptyProgram(t *testing.T) {
 	}
 }
 
+func TestInitializeRunnerSetsVmInitialized(t *testing.T) {
+	program := vm.Program{Data: []memory.MaybeRelocatable{}, Identifiers: map[string]vm.Identifier{}}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner error in test: %s", err)
+	}
+	if runner.VmInitialized {
+		t.Errorf("VmInitialized should be false before Initialize")
+	}
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatalf("Initialize error in test: %s", err)
+	}
+	if !runner.VmInitialized {
+		t.Errorf("VmInitialized should be true after Initialize")
+	}
+}
+
+func TestInitializeRunnerFailureLeavesVmUninitialized(t *testing.T) {
+	program := vm.Program{Data: []memory.MaybeRelocatable{}, Identifiers: map[string]vm.Identifier{}, Builtins: []string{builtins.RANGE_CHECK_BUILTIN_NAME}}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner error in test: %s", err)
+	}
+	if _, err := runner.Initialize(); !errors.Is(err, runners.ErrNoBuiltinForInstance) || !errors.Is(err, runners.ErrRunner) {
+		t.Fatalf("Expected Initialize to fail with ErrNoBuiltinForInstance, got %v", err)
+	}
+	if runner.VmInitialized {
+		t.Errorf("VmInitialized should be false after a failed Initialize")
+	}
+}
+
 func TestInitializeRunnerNoBuiltinsNoProofModeNonEmptyProgram(t *testing.T) {
 	// Create a Program with one fake instruction
 	program_data := make([]memory.MaybeRelocatable, 1)
@@ -204,6 +280,48 @@ func TestInitializeRunnerWithRangeCheckValid(t *testing.T) {
 	}
 }
 
+// Output builtin registered under another name, used as a builtin that is not part of the standard layouts
+type customOutputBuiltinRunner struct {
+	*builtins.OutputBuiltinRunner
+}
+
+func (c customOutputBuiltinRunner) Name() string {
+	return "custom_output"
+}
+
+func TestInitializeRunnerBuiltinsInProgramOrder(t *testing.T) {
+	program := vm.Program{
+		Data:        []memory.MaybeRelocatable{*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1))},
+		Identifiers: make(map[string]vm.Identifier),
+		Builtins:    []string{"custom_output", builtins.OUTPUT_BUILTIN_NAME},
+	}
+	layout := layouts.NewPlainLayout()
+	layout.Builtins = append(layout.Builtins, customOutputBuiltinRunner{builtins.NewOutputBuiltinRunner()})
+	runner, err := runners.NewCairoRunnerWithLayout(program, layout, false)
+	if err != nil {
+		t.Fatalf("NewCairoRunnerWithLayout error in test: %s", err)
+	}
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatalf("Initialize error in test: %s", err)
+	}
+
+	// Segments are created in the order of the layout
+	if runner.Vm.BuiltinRunners[0].Base() != memory.NewRelocatable(2, 0) || runner.Vm.BuiltinRunners[1].Base() != memory.NewRelocatable(3, 0) {
+		t.Errorf("Wrong builtin bases: %v, %v", runner.Vm.BuiltinRunners[0].Base(), runner.Vm.BuiltinRunners[1].Base())
+	}
+	programBuiltinRunners := runner.ProgramBuiltinRunners()
+	if len(programBuiltinRunners) != 2 || programBuiltinRunners[0].Name() != "custom_output" || programBuiltinRunners[1].Name() != builtins.OUTPUT_BUILTIN_NAME {
+		t.Errorf("Wrong program builtin runners: %v", programBuiltinRunners)
+	}
+	// main receives the builtin pointers in the order of the program's builtins
+	for i, expected := range []memory.Relocatable{memory.NewRelocatable(3, 0), memory.NewRelocatable(2, 0)} {
+		ptr, err := runner.Vm.Segments.Memory.GetRelocatable(memory.NewRelocatable(1, uint(i)))
+		if err != nil || ptr != expected {
+			t.Errorf("Wrong builtin pointer %d on the initial stack. Expected %v, got %v, err: %v", i, expected, ptr, err)
+		}
+	}
+}
+
 func TestInitializeRunnerWithRangeCheckInvalid(t *testing.T) {
 	t.Helper()
 	// Create a Program with one fake instruction
@@ -473,6 +591,78 @@ func TestWriteOutputFromProgramGapRelocatableOutput(t *testing.T) {
 	}
 }
 
+func TestRunnerWriteOutputToWriter(t *testing.T) {
+	empty_identifiers := make(map[string]vm.Identifier, 0)
+	program_builtins := []string{builtins.OUTPUT_BUILTIN_NAME}
+	program := vm.Program{Identifiers: empty_identifiers, Builtins: program_builtins}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Errorf("NewCairoRunner error in test: %s", err)
+	}
+	_, err = runner.Initialize()
+	if err != nil {
+		t.Errorf("Initialize error in test: %s", err)
+	}
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(2, 0), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 3)))
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(2, 2), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(7)))
+
+	var builder strings.Builder
+	err = runner.WriteOutput(&builder)
+	if err != nil {
+		t.Errorf("WriteOutput failed with error: %s", err)
+	}
+
+	expected := "{1:3}\n<missing>\n7\n"
+	if builder.String() != expected {
+		t.Errorf("TestRunnerWriteOutputToWriter failed. Expected: %s, got: %s", expected, builder.String())
+	}
+}
+
+func outputRunner(t *testing.T) *runners.CairoRunner {
+	program := vm.Program{Identifiers: make(map[string]vm.Identifier), Builtins: []string{builtins.OUTPUT_BUILTIN_NAME}}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner error in test: %s", err)
+	}
+	if _, err = runner.Initialize(); err != nil {
+		t.Fatalf("Initialize error in test: %s", err)
+	}
+	return runner
+}
+
+func TestGetOutputs(t *testing.T) {
+	runner := outputRunner(t)
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(2, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)))
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(2, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-2")))
+
+	outputs, err := runner.GetOutputs()
+	if err != nil {
+		t.Fatalf("GetOutputs failed with error: %s", err)
+	}
+	expected := []lambdaworks.Felt{lambdaworks.FeltFromUint64(1), lambdaworks.FeltFromDecString("-2")}
+	if !reflect.DeepEqual(outputs, expected) {
+		t.Errorf("Wrong outputs. Expected: %v, got: %v", expected, outputs)
+	}
+}
+
+func TestGetOutputsMissingValue(t *testing.T) {
+	runner := outputRunner(t)
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(2, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)))
+
+	if _, err := runner.GetOutputs(); !errors.Is(err, vm.ErrMissingOutput) {
+		t.Errorf("Expected ErrMissingOutput, got: %v", err)
+	}
+}
+
+func TestGetOutputsRelocatableValue(t *testing.T) {
+	runner := outputRunner(t)
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(2, 0), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 3)))
+
+	if _, err := runner.GetOutputs(); !errors.Is(err, vm.ErrRelocatableOutput) {
+		t.Errorf("Expected ErrRelocatableOutput, got: %v", err)
+	}
+}
+
 func TestWriteOutputFromPresentMemoryNegOutput(t *testing.T) {
 	empty_identifiers := make(map[string]vm.Identifier, 0)
 	program_builtins := []string{builtins.OUTPUT_BUILTIN_NAME}
@@ -692,6 +882,51 @@ func TestCheckDilutedCheckUsage(t *testing.T) {
 	}
 }
 
+func TestGetMemoryUnits(t *testing.T) {
+	runner, err := runners.NewCairoRunner(vm.Program{}, "all_cairo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner.Vm.CurrentStep = 8
+
+	// public memory (64 / 8) + instructions (4 * 8)
+	used, total, err := runner.GetMemoryUnits()
+	if err != nil || used != 40 || total != 64 {
+		t.Errorf("Expected 40 used memory units out of 64, got %d out of %d, err: %v", used, total, err)
+	}
+}
+
+func TestCheckMemoryUsageMoreUnitsUsedThanAvailable(t *testing.T) {
+	runner, err := runners.NewCairoRunner(vm.Program{}, "plain", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner.Vm.CurrentStep = 8
+	runner.Layout.MemoryUnitsPerStep = 4
+
+	err = runner.CheckMemoryUsage()
+	if !errors.Is(err, memory.ErrInsufficientAllocatedCells) {
+		t.Errorf("Expected ErrInsufficientAllocatedCells, got: %v", err)
+	}
+}
+
+func TestGetDilutedCheckUnits(t *testing.T) {
+	runner, err := runners.NewCairoRunner(vm.Program{}, "all_cairo", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	runner.Vm.CurrentStep = 64
+	bitwise := builtins.NewBitwiseBuiltinRunner(16)
+	runner.Vm.BuiltinRunners = []builtins.BuiltinRunner{builtins.NewOutputBuiltinRunner(), bitwise}
+
+	pool := runner.Layout.DilutedPoolInstance
+	used, total, err := runner.GetDilutedCheckUnits()
+	expectedUsed := bitwise.GetUsedDilutedCheckUnits(pool.Spacing, pool.NBits) * 4
+	if err != nil || used != expectedUsed || total != pool.UnitsPerStep*64 {
+		t.Errorf("Expected %d used diluted units out of %d, got %d out of %d, err: %v", expectedUsed, pool.UnitsPerStep*64, used, total, err)
+	}
+}
+
 // This test is a huge meme, revisit
 func TestCheckUsedCellsDilutedCheckUsageError(t *testing.T) {
 	program := vm.Program{Data: nil, Builtins: nil, Identifiers: nil, Hints: nil, ReferenceManager: parser.ReferenceManager{}}
@@ -783,3 +1018,417 @@ func TestRunFromEntryPointFibonacciNotEnoughSteps(t *testing.T) {
 	}
 
 }
+
+func TestGetReturnValues(t *testing.T) {
+	program := vm.Program{Identifiers: make(map[string]vm.Identifier)}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Errorf("NewCairoRunner error in test: %s", err)
+	}
+	runner.Vm.Segments.AddSegment()
+	runner.Vm.Segments.AddSegment()
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(1, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)))
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(1, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)))
+	runner.Vm.Segments.Memory.Insert(memory.NewRelocatable(1, 2), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 3)))
+	runner.Vm.RunContext.Ap = memory.NewRelocatable(1, 3)
+	runner.RunEnded = true
+
+	returnValues, err := runner.GetReturnValues(2)
+	if err != nil {
+		t.Errorf("GetReturnValues failed with error: %s", err)
+	}
+	expected := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)),
+		*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 3)),
+	}
+	if !reflect.DeepEqual(returnValues, expected) {
+		t.Errorf("Wrong return values. Expected: %v, got: %v", expected, returnValues)
+	}
+}
+
+func TestGetReturnValuesBeforeRunEnded(t *testing.T) {
+	program := vm.Program{Identifiers: make(map[string]vm.Identifier)}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Errorf("NewCairoRunner error in test: %s", err)
+	}
+	_, err = runner.GetReturnValues(1)
+	if !errors.Is(err, runners.ErrGetReturnValuesNoEndRun) || !errors.Is(err, runners.ErrRunner) {
+		t.Errorf("GetReturnValues should fail before the run ended, got: %v", err)
+	}
+}
+
+func TestGetReturnValuesMoreValuesThanStack(t *testing.T) {
+	program := vm.Program{Identifiers: make(map[string]vm.Identifier)}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Errorf("NewCairoRunner error in test: %s", err)
+	}
+	runner.Vm.RunContext.Ap = memory.NewRelocatable(1, 2)
+	runner.RunEnded = true
+	_, err = runner.GetReturnValues(3)
+	if err == nil {
+		t.Errorf("GetReturnValues should fail if there are less than n values below ap")
+	}
+}
+
+func TestRunUntilPCDetectsInfiniteLoop(t *testing.T) {
+	// main: jmp rel 0
+	program := vmtest.Program("0x10780017fff7fff", "0")
+	runner, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "plain"})
+	if !errors.Is(err, runners.ErrInfiniteLoop) {
+		t.Errorf("Expected an infinite loop error, got: %v", err)
+	}
+	if runner.Vm.CurrentStep != 1 {
+		t.Errorf("The loop should be detected after the first step, current step: %d", runner.Vm.CurrentStep)
+	}
+}
+
+// Builds a program out of the words in data, with the builtins selected by the bits of builtinsMask
+// Words are read as signed 64 bit values, so that negative immediates can be expressed
+func programForFuzzing(builtinsMask uint8, data []byte) vm.Program {
+	builtinNames := []string{
+		builtins.OUTPUT_BUILTIN_NAME, builtins.PEDERSEN_BUILTIN_NAME, builtins.RANGE_CHECK_BUILTIN_NAME, builtins.SIGNATURE_BUILTIN_NAME,
+		builtins.BITWISE_BUILTIN_NAME, builtins.EC_OP_BUILTIN_NAME, builtins.KECCAK_BUILTIN_NAME, builtins.POSEIDON_BUILTIN_NAME,
+	}
+	program := vmtest.Program()
+	for i, name := range builtinNames {
+		if builtinsMask&(1<<i) != 0 {
+			program.Builtins = append(program.Builtins, name)
+		}
+	}
+	for len(data) >= 8 {
+		word := int64(binary.LittleEndian.Uint64(data))
+		value := lambdaworks.FeltFromUint64(uint64(word))
+		if word < 0 {
+			value = lambdaworks.FeltZero().Sub(lambdaworks.FeltFromUint64(uint64(-word)))
+		}
+		program.Data = append(program.Data, *memory.NewMaybeRelocatableFelt(value))
+		data = data[8:]
+	}
+	return program
+}
+
+// Running arbitrary programs for a bounded amount of steps should never panic,
+// and every failure should be reported through the vm's typed errors
+func FuzzRunProgram(f *testing.F) {
+	type seedProgram struct {
+		builtinsMask uint8
+		words        []int64
+	}
+	seedPrograms := []seedProgram{
+		// [ap] = 10, ap++; loop: [ap] = [ap - 1] - 1, ap++; jmp loop if [ap - 1] != 0; ret
+		{0, []int64{0x480680017fff8000, 10, 0x482480017fff8000, -1, 0x20680017fff7fff, -2, 0x208b7fff7fff7ffe}},
+		// jmp rel 0
+		{0, []int64{0x10780017fff7fff, 0}},
+		// [ap] = [fp - 3], ap++; ret
+		{0x01, []int64{0x480a7ffd7fff8000, 0x208b7fff7fff7ffe}},
+	}
+	for i := 0; i < 8; i++ {
+		// Reads a couple of cells from the segment of a single builtin, so that they have to be deduced
+		// [ap] = [[fp - 3] + 2], ap++; [ap] = [[fp - 3] + 5], ap++; ret
+		seedPrograms = append(seedPrograms, seedProgram{1 << i, []int64{0x480280027ffd8000, 0x480280057ffd8000, 0x208b7fff7fff7ffe}})
+		// Fills the first cells of the segment before reading the following ones
+		// [ap] = 5, ap++; [ap - 1] = [[fp - 3] + k] for k in 0..3; [ap] = [[fp - 3] + k], ap++ for k in 4..8; ret
+		seedPrograms = append(seedPrograms, seedProgram{1 << i, []int64{
+			0x480680017fff8000, 5,
+			0x400280007ffd7fff, 0x400280017ffd7fff, 0x400280027ffd7fff, 0x400280037ffd7fff,
+			0x480280047ffd8000, 0x480280057ffd8000, 0x480280067ffd8000, 0x480280077ffd8000, 0x480280087ffd8000,
+			0x208b7fff7fff7ffe,
+		}})
+	}
+	for _, seed := range seedPrograms {
+		var data []byte
+		for _, word := range seed.words {
+			data = binary.LittleEndian.AppendUint64(data, uint64(word))
+		}
+		f.Add(seed.builtinsMask, data)
+	}
+	f.Fuzz(func(t *testing.T, builtinsMask uint8, data []byte) {
+		program := programForFuzzing(builtinsMask, data)
+		runner, err := runners.NewCairoRunner(program, "all_cairo", false)
+		if err != nil {
+			t.Fatalf("Failed to create the runner: %s", err)
+		}
+		end, err := runner.Initialize()
+		if err != nil {
+			t.Fatalf("Failed to initialize the runner: %s", err)
+		}
+		runResources := vm.NewRunResources(100)
+		runner.Vm.RunResources = &runResources
+		hintProcessor := hints.CairoVmHintProcessor{}
+		err = runner.RunUntilPC(end, &hintProcessor)
+		if err == nil {
+			err = runner.EndRun(false, false, &hintProcessor)
+		}
+		if err == nil {
+			err = runner.Vm.Relocate()
+		}
+		if err != nil && !isTypedVmError(err) {
+			t.Fatalf("Run failed with an untyped error: %s", err)
+		}
+	})
+}
+
+func isTypedVmError(err error) bool {
+	var stepError *vm.StepError
+	return errors.As(err, &stepError) ||
+		errors.Is(err, vm.ErrVirtualMachine) ||
+		errors.Is(err, memory.ErrMemory) ||
+		errors.Is(err, memory.ErrMath) ||
+		errors.Is(err, runners.ErrRunner)
+}
+
+// Program made of n copies of "[ap] = [ap - 1] + 1, ap++", followed by a ret
+func compiledProgramForLoadingBenchmark(n int) parser.CompiledJson {
+	compiledProgram := parser.CompiledJson{Identifiers: map[string]parser.Identifier{"__main__.main": {PC: 0, Type: "function"}}}
+	for i := 0; i < n; i++ {
+		compiledProgram.Data = append(compiledProgram.Data, "0x482480017fff8000", "0x1")
+	}
+	compiledProgram.Data = append(compiledProgram.Data, "0x208b7fff7fff7ffe")
+	return compiledProgram
+}
+
+func BenchmarkLoadProgram(b *testing.B) {
+	compiledProgram := compiledProgramForLoadingBenchmark(50000)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		runner, err := runners.NewCairoRunner(vm.DeserializeProgramJson(compiledProgram), "plain", false)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if _, err := runner.Initialize(); err != nil {
+			b.Fatalf("Initialize failed with error: %s", err)
+		}
+	}
+}
+
+func TestInitialRegistersProofMode(t *testing.T) {
+	program := vm.Program{Identifiers: map[string]vm.Identifier{}}
+	runner, err := runners.NewCairoRunner(program, "plain", true)
+	if err != nil {
+		t.Fatalf("NewCairoRunner error in test: %s", err)
+	}
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatalf("Initialize error in test: %s", err)
+	}
+	expectedRegisters := vm.NewRunContext(memory.NewRelocatable(0, 0), memory.NewRelocatable(1, runners.PROOF_MODE_INITIAL_AP_OFFSET), memory.NewRelocatable(1, runners.PROOF_MODE_INITIAL_AP_OFFSET))
+	if runner.InitialRegisters() != expectedRegisters {
+		t.Errorf("Wrong initial registers, expected %+v, got %+v", expectedRegisters, runner.InitialRegisters())
+	}
+	if runner.Vm.RunContext != expectedRegisters {
+		t.Errorf("Wrong vm registers, expected %+v, got %+v", expectedRegisters, runner.Vm.RunContext)
+	}
+}
+
+func TestSetInitialApOffset(t *testing.T) {
+	program := vm.Program{Identifiers: map[string]vm.Identifier{}}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner error in test: %s", err)
+	}
+	runner.SetInitialApOffset(5)
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatalf("Initialize error in test: %s", err)
+	}
+	if runner.Vm.RunContext.GetAp() != memory.NewRelocatable(1, 5) {
+		t.Errorf("Wrong Ap value, got %s", runner.Vm.RunContext.GetAp())
+	}
+	// The initial fp still points right after the initial stack (return_fp & end)
+	if runner.Vm.RunContext.GetFp() != memory.NewRelocatable(1, 2) {
+		t.Errorf("Wrong Fp value, got %s", runner.Vm.RunContext.GetFp())
+	}
+}
+
+func TestSetInitialRegisters(t *testing.T) {
+	program := vm.Program{Identifiers: map[string]vm.Identifier{}}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner error in test: %s", err)
+	}
+	registers := vm.NewRunContext(memory.NewRelocatable(0, 3), memory.NewRelocatable(1, 7), memory.NewRelocatable(1, 6))
+	runner.SetInitialRegisters(registers)
+	runner.SetInitialApOffset(5)
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatalf("Initialize error in test: %s", err)
+	}
+	if runner.Vm.RunContext != registers || runner.InitialRegisters() != registers {
+		t.Errorf("Wrong registers, expected %+v, got %+v", registers, runner.Vm.RunContext)
+	}
+}
+
+func TestRunForStepsEndOfProgram(t *testing.T) {
+	// main:  [ap] = 5, ap++; [ap] = 6, ap++; ret
+	program := vmtest.Program("0x480680017fff8000", "5", "0x480680017fff8000", "6", "0x208b7fff7fff7ffe")
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner failed with error: %s", err)
+	}
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatalf("Initialize failed with error: %s", err)
+	}
+	err = runner.RunForSteps(5, &hints.CairoVmHintProcessor{})
+	if !errors.Is(err, vm.ErrEndOfProgram) || err.Error() != "EndOfProgram: 2" {
+		t.Errorf("Expected ErrEndOfProgram with 2 remaining steps, got %v", err)
+	}
+}
+
+func TestForkRunsIndependently(t *testing.T) {
+	// main:  [ap] = 5, ap++; [ap] = 6, ap++; ret
+	program := vmtest.Program("0x480680017fff8000", "5", "0x480680017fff8000", "6", "0x208b7fff7fff7ffe")
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner failed with error: %s", err)
+	}
+	end, err := runner.Initialize()
+	if err != nil {
+		t.Fatalf("Initialize failed with error: %s", err)
+	}
+	hintProcessor := &hints.CairoVmHintProcessor{}
+	if err := runner.RunForSteps(1, hintProcessor); err != nil {
+		t.Fatalf("RunForSteps failed with error: %s", err)
+	}
+
+	fork, err := runner.Fork()
+	if err != nil {
+		t.Fatalf("Fork failed with error: %s", err)
+	}
+	if err := fork.RunUntilPC(end, hintProcessor); err != nil {
+		t.Fatalf("The fork failed to run until the end: %s", err)
+	}
+
+	if fork.Vm.CurrentStep != 3 || runner.Vm.CurrentStep != 1 || len(runner.Vm.Trace) != 1 {
+		t.Errorf("Wrong steps, fork: %d, original: %d", fork.Vm.CurrentStep, runner.Vm.CurrentStep)
+	}
+	if runner.Vm.RunContext.Pc != memory.NewRelocatable(0, 2) {
+		t.Errorf("The original's registers shouldn't change, pc: %s", runner.Vm.RunContext.Pc)
+	}
+	if _, err := runner.Vm.Segments.Memory.Get(memory.NewRelocatable(1, 3)); err == nil {
+		t.Error("The fork's writes shouldn't reach the original's memory")
+	}
+	if value, err := fork.Vm.Segments.Memory.GetFelt(memory.NewRelocatable(1, 3)); err != nil || value != lambdaworks.FeltFromUint64(6) {
+		t.Errorf("Wrong value written by the fork: %v, %v", value, err)
+	}
+
+	// The original can still run on its own
+	if err := runner.RunUntilPC(end, hintProcessor); err != nil || runner.Vm.CurrentStep != 3 {
+		t.Errorf("The original failed to run after the fork: %v", err)
+	}
+}
+
+func TestForkDoesNotShareBuiltinsOrScopes(t *testing.T) {
+	// main{ecdsa_ptr}: %{ write_dict() %} [ap] = [fp - 3] + 0, ap++; ret
+	program := vmtest.Program("0x482680017ffd8000", "0x0", "0x208b7fff7fff7ffe")
+	program.Builtins = []string{builtins.SIGNATURE_BUILTIN_NAME}
+	program.Hints = map[uint][]parser.HintParams{0: {{Code: "write_dict()"}}}
+	runner, err := runners.NewCairoRunner(program, "small", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner failed with error: %s", err)
+	}
+	end, err := runner.Initialize()
+	if err != nil {
+		t.Fatalf("Initialize failed with error: %s", err)
+	}
+	signatureBuiltin := func(r *runners.CairoRunner) *builtins.SignatureBuiltinRunner {
+		builtin, err := r.Vm.GetBuiltinRunner(builtins.SIGNATURE_BUILTIN_NAME)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return (*builtin).(*builtins.SignatureBuiltinRunner)
+	}
+	signatureBase := signatureBuiltin(runner).Base()
+	signatureBuiltin(runner).AddSignature(signatureBase, builtins.Signature{R: lambdaworks.FeltOne(), S: lambdaworks.FeltOne()})
+	dictManager := dict_manager.NewDictManager()
+	dictManager.NewDictionary(&map[memory.MaybeRelocatable]memory.MaybeRelocatable{}, &runner.Vm)
+	runner.AssignScopeVariables(map[string]any{"__dict_manager": &dictManager})
+
+	fork, err := runner.Fork()
+	if err != nil {
+		t.Fatalf("Fork failed with error: %s", err)
+	}
+	// Writes a key to the first dict of the dict manager in scope
+	writeDict := func(ctx *hints.HintContext) error {
+		manager, err := ctx.ScopeVar("__dict_manager")
+		if err != nil {
+			return err
+		}
+		key := memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne())
+		manager.(*dict_manager.DictManager).Trackers()[0].InsertValue(key, key)
+		return nil
+	}
+	hintProcessor := &hints.CairoVmHintProcessor{CustomHints: map[string]hints.CustomHint{"write_dict()": writeDict}}
+	if err := fork.RunUntilPC(end, hintProcessor); err != nil {
+		t.Fatalf("The fork failed to run until the end: %s", err)
+	}
+	if err := fork.EndRun(false, false, hintProcessor); err != nil {
+		t.Fatalf("EndRun failed with error: %s", err)
+	}
+	if err := fork.ReadReturnValues(); err != nil {
+		t.Fatalf("ReadReturnValues failed with error: %s", err)
+	}
+	signatureBuiltin(fork).AddSignature(signatureBase.AddUint(2), builtins.Signature{R: lambdaworks.FeltOne(), S: lambdaworks.FeltOne()})
+
+	if signatureBuiltin(fork).StopPtr == nil || signatureBuiltin(runner).StopPtr != nil {
+		t.Errorf("Only the fork's stop pointer should be set, fork: %v, original: %v", signatureBuiltin(fork).StopPtr, signatureBuiltin(runner).StopPtr)
+	}
+	if signatures := runner.GetAdditionalData()[builtins.SIGNATURE_BUILTIN_NAME].(builtins.SignatureAdditionalData); len(signatures) != 1 {
+		t.Errorf("The original's signatures shouldn't change, got %v", signatures)
+	}
+	if dicts := fork.DumpDicts(); len(dicts) != 1 || len(dicts[0].Entries) != 1 {
+		t.Errorf("The fork's dict should hold the written key, got %+v", dicts)
+	}
+	if dicts := runner.DumpDicts(); len(dicts) != 1 || len(dicts[0].Entries) != 0 {
+		t.Errorf("The original's dict shouldn't change, got %+v", dicts)
+	}
+}
+
+func TestRunnerPhases(t *testing.T) {
+	// main{output_ptr}: [ap] = 42, ap++; [ap - 1] = [[fp - 3]]; [ap] = [fp - 3] + 1, ap++; ret
+	program := vmtest.Program("0x480680017fff8000", "0x2a", "0x400280007ffd7fff", "0x482680017ffd8000", "0x1", "0x208b7fff7fff7ffe")
+	program.Builtins = []string{"output"}
+	runner, err := runners.NewCairoRunner(program, "small", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner failed with error: %s", err)
+	}
+	if err := runner.InitializeBuiltins(); err != nil {
+		t.Fatalf("InitializeBuiltins failed with error: %s", err)
+	}
+	runner.InitializeSegments()
+	end, err := runner.InitializeMainEntrypoint()
+	if err != nil {
+		t.Fatalf("InitializeMainEntrypoint failed with error: %s", err)
+	}
+	if err := runner.InitializeVM(); err != nil {
+		t.Fatalf("InitializeVM failed with error: %s", err)
+	}
+	if runner.Vm.RunContext != runner.InitialRegisters() {
+		t.Errorf("InitializeVM should set the initial registers, got %+v", runner.Vm.RunContext)
+	}
+
+	hintProcessor := &hints.CairoVmHintProcessor{}
+	if err := runner.RunUntilPC(end, hintProcessor); err != nil {
+		t.Fatalf("RunUntilPC failed with error: %s", err)
+	}
+	if err := runner.Relocate(); !errors.Is(err, runners.ErrRelocateNoEndRun) {
+		t.Errorf("Expected ErrRelocateNoEndRun, got %v", err)
+	}
+	if err := runner.EndRun(false, false, hintProcessor); err != nil {
+		t.Fatalf("EndRun failed with error: %s", err)
+	}
+	if err := runner.Relocate(); err != nil {
+		t.Fatalf("Relocate failed with error: %s", err)
+	}
+
+	trace, err := runner.Vm.GetRelocatedTrace()
+	if err != nil || len(trace) != 4 {
+		t.Errorf("Expected a relocated trace of 4 steps, got %v, %v", trace, err)
+	}
+	// The program segment is relocated to address 1
+	if value, ok := runner.Vm.RelocatedMemory.Get(2); !ok || value != lambdaworks.FeltFromUint64(42) {
+		t.Errorf("Wrong relocated memory at address 2: %s, %t", value.ToSignedFeltString(), ok)
+	}
+	outputs, err := runner.GetOutputs()
+	if err != nil || !reflect.DeepEqual(outputs, []lambdaworks.Felt{lambdaworks.FeltFromUint64(42)}) {
+		t.Errorf("Wrong outputs: %v, %v", outputs, err)
+	}
+}
@@ -0,0 +1,291 @@
+package runners
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/types"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// State of a run in progress, from which a runner initialized with the same program & layout can resume it
+// (ie: to split a long run across processes). Runs that stream their trace don't store it in the checkpoint
+type Checkpoint struct {
+	RunContext        vm.RunContext
+	CurrentStep       uint
+	NumSegments       uint
+	Memory            map[memory.Relocatable]memory.MaybeRelocatable
+	AccessedAddresses []memory.Relocatable
+	Trace             []vm.TraceEntry
+	RcLimitsMin       *int
+	RcLimitsMax       *int
+	ExecutionScopes   types.ExecutionScopes
+	AdditionalData    map[string]builtins.BuiltinAdditionalData
+}
+
+var ErrCheckpoint = errors.New("Checkpoint error")
+
+func CheckpointError(reason string) error {
+	return RunnerError(errors.Wrap(ErrCheckpoint, reason))
+}
+
+// Captures the state of the run, which must not have ended
+// The checkpoint shares nothing with the runner, except for the scope values ExecutionScopes.Clone doesn't copy
+func (r *CairoRunner) Checkpoint() (*Checkpoint, error) {
+	if r.RunEnded {
+		return nil, CheckpointError("the run has already ended")
+	}
+	segments := &r.Vm.Segments
+	checkpoint := Checkpoint{
+		RunContext:      r.Vm.RunContext,
+		CurrentStep:     r.Vm.CurrentStep,
+		NumSegments:     segments.Memory.NumSegments(),
+		Memory:          make(map[memory.Relocatable]memory.MaybeRelocatable, len(segments.Memory.Data)),
+		Trace:           append([]vm.TraceEntry(nil), r.Vm.Trace...),
+		RcLimitsMin:     copyIntPtr(r.Vm.RcLimitsMin),
+		RcLimitsMax:     copyIntPtr(r.Vm.RcLimitsMax),
+		ExecutionScopes: *r.execScopes.Clone(),
+		AdditionalData:  r.GetAdditionalData(),
+	}
+	for addr, value := range segments.Memory.Data {
+		checkpoint.Memory[addr] = value
+	}
+	for i := 0; i < int(checkpoint.NumSegments); i++ {
+		checkpoint.AccessedAddresses = append(checkpoint.AccessedAddresses, segments.Memory.GetAccessedAddresses(i)...)
+	}
+	return &checkpoint, nil
+}
+
+// Resumes the run from a checkpoint. The runner must have been initialized with the program & layout of the
+// checkpointed run, without running any step. Fails if the checkpoint doesn't match the initialized memory
+func (r *CairoRunner) RestoreCheckpoint(checkpoint *Checkpoint) error {
+	if r.Vm.CurrentStep != 0 || r.RunEnded {
+		return CheckpointError("the runner has already run")
+	}
+	segments := &r.Vm.Segments
+	if checkpoint.NumSegments < segments.Memory.NumSegments() {
+		return CheckpointError("the checkpoint has less segments than the initialized runner")
+	}
+	for segments.Memory.NumSegments() < checkpoint.NumSegments {
+		segments.AddSegment()
+	}
+	// Restored before the memory, as validation rules may depend on it (ie: the signatures of the ecdsa builtin)
+	if err := r.SetAdditionalData(checkpoint.AdditionalData); err != nil {
+		return err
+	}
+	// Cells are inserted in address order so that restoring is deterministic
+	addresses := make([]memory.Relocatable, 0, len(checkpoint.Memory))
+	for addr := range checkpoint.Memory {
+		addresses = append(addresses, addr)
+	}
+	sortAddresses(addresses)
+	segments.Memory.Reserve(uint(len(addresses)))
+	for _, addr := range addresses {
+		value := checkpoint.Memory[addr]
+		if err := segments.Memory.Insert(addr, &value); err != nil {
+			return err
+		}
+	}
+	for _, addr := range checkpoint.AccessedAddresses {
+		segments.Memory.MarkAsAccessed(addr)
+	}
+	r.Vm.RunContext = checkpoint.RunContext
+	r.Vm.CurrentStep = checkpoint.CurrentStep
+	r.Vm.Trace = append([]vm.TraceEntry(nil), checkpoint.Trace...)
+	r.Vm.RcLimitsMin = copyIntPtr(checkpoint.RcLimitsMin)
+	r.Vm.RcLimitsMax = copyIntPtr(checkpoint.RcLimitsMax)
+	r.execScopes = *checkpoint.ExecutionScopes.Clone()
+	return nil
+}
+
+func copyIntPtr(value *int) *int {
+	if value == nil {
+		return nil
+	}
+	copied := *value
+	return &copied
+}
+
+func sortAddresses(addresses []memory.Relocatable) {
+	sort.Slice(addresses, func(i, j int) bool {
+		if addresses[i].SegmentIndex != addresses[j].SegmentIndex {
+			return addresses[i].SegmentIndex < addresses[j].SegmentIndex
+		}
+		return addresses[i].Offset < addresses[j].Offset
+	})
+}
+
+// Writes the checkpoint as JSON. Fails if an execution scope holds a variable that can't be serialized
+func WriteCheckpoint(writer io.Writer, checkpoint *Checkpoint) error {
+	encoded, err := json.Marshal(checkpoint)
+	if err != nil {
+		return CheckpointError(err.Error())
+	}
+	_, err = writer.Write(encoded)
+	return err
+}
+
+// Reads a checkpoint written by WriteCheckpoint
+func ReadCheckpoint(reader io.Reader) (*Checkpoint, error) {
+	var checkpoint Checkpoint
+	if err := json.NewDecoder(reader).Decode(&checkpoint); err != nil {
+		return nil, CheckpointError(err.Error())
+	}
+	return &checkpoint, nil
+}
+
+// Addresses are encoded as [segment_index, offset], felts as decimal strings & memory cells as [address, value]
+type checkpointJSON struct {
+	RunContext        [3]memory.JsonAddress      `json:"run_context"`
+	CurrentStep       uint                       `json:"current_step"`
+	NumSegments       uint                       `json:"num_segments"`
+	Memory            [][2]json.RawMessage       `json:"memory"`
+	AccessedAddresses []memory.JsonAddress       `json:"accessed_addresses"`
+	Trace             [][3]memory.JsonAddress    `json:"trace"`
+	RcLimitsMin       *int                       `json:"rc_limits_min"`
+	RcLimitsMax       *int                       `json:"rc_limits_max"`
+	ExecutionScopes   json.RawMessage            `json:"execution_scopes"`
+	AdditionalData    map[string]json.RawMessage `json:"additional_data"`
+}
+
+func (c Checkpoint) MarshalJSON() ([]byte, error) {
+	encoded := checkpointJSON{
+		RunContext:        registersJSON(c.RunContext.Pc, c.RunContext.Ap, c.RunContext.Fp),
+		CurrentStep:       c.CurrentStep,
+		NumSegments:       c.NumSegments,
+		Memory:            make([][2]json.RawMessage, 0, len(c.Memory)),
+		AccessedAddresses: make([]memory.JsonAddress, 0, len(c.AccessedAddresses)),
+		Trace:             make([][3]memory.JsonAddress, 0, len(c.Trace)),
+		RcLimitsMin:       c.RcLimitsMin,
+		RcLimitsMax:       c.RcLimitsMax,
+		AdditionalData:    make(map[string]json.RawMessage, len(c.AdditionalData)),
+	}
+	addresses := make([]memory.Relocatable, 0, len(c.Memory))
+	for addr := range c.Memory {
+		addresses = append(addresses, addr)
+	}
+	sortAddresses(addresses)
+	for _, addr := range addresses {
+		cell, err := marshalCell(addr, c.Memory[addr])
+		if err != nil {
+			return nil, err
+		}
+		encoded.Memory = append(encoded.Memory, cell)
+	}
+	for _, addr := range c.AccessedAddresses {
+		encoded.AccessedAddresses = append(encoded.AccessedAddresses, memory.ToJsonAddress(addr))
+	}
+	for _, entry := range c.Trace {
+		encoded.Trace = append(encoded.Trace, registersJSON(entry.Pc, entry.Ap, entry.Fp))
+	}
+	scopes, err := json.Marshal(c.ExecutionScopes)
+	if err != nil {
+		return nil, err
+	}
+	encoded.ExecutionScopes = scopes
+	for name, data := range c.AdditionalData {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		encoded.AdditionalData[name] = raw
+	}
+	return json.Marshal(encoded)
+}
+
+func (c *Checkpoint) UnmarshalJSON(data []byte) error {
+	var encoded checkpointJSON
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	runContext, err := decodeRegisters(encoded.RunContext)
+	if err != nil {
+		return err
+	}
+	checkpoint := Checkpoint{
+		RunContext:     vm.RunContext{Pc: runContext[0], Ap: runContext[1], Fp: runContext[2]},
+		CurrentStep:    encoded.CurrentStep,
+		NumSegments:    encoded.NumSegments,
+		Memory:         make(map[memory.Relocatable]memory.MaybeRelocatable, len(encoded.Memory)),
+		Trace:          make([]vm.TraceEntry, 0, len(encoded.Trace)),
+		RcLimitsMin:    encoded.RcLimitsMin,
+		RcLimitsMax:    encoded.RcLimitsMax,
+		AdditionalData: make(map[string]builtins.BuiltinAdditionalData, len(encoded.AdditionalData)),
+	}
+	for _, cell := range encoded.Memory {
+		addr, value, err := unmarshalCell(cell)
+		if err != nil {
+			return err
+		}
+		checkpoint.Memory[addr] = value
+	}
+	for _, encodedAddr := range encoded.AccessedAddresses {
+		addr, err := encodedAddr.Relocatable()
+		if err != nil {
+			return err
+		}
+		checkpoint.AccessedAddresses = append(checkpoint.AccessedAddresses, addr)
+	}
+	for _, entry := range encoded.Trace {
+		registers, err := decodeRegisters(entry)
+		if err != nil {
+			return err
+		}
+		checkpoint.Trace = append(checkpoint.Trace, vm.TraceEntry{Pc: registers[0], Ap: registers[1], Fp: registers[2]})
+	}
+	if err := json.Unmarshal(encoded.ExecutionScopes, &checkpoint.ExecutionScopes); err != nil {
+		return err
+	}
+	for name, raw := range encoded.AdditionalData {
+		additionalData, err := builtins.UnmarshalAdditionalData(name, raw)
+		if err != nil {
+			return err
+		}
+		if additionalData != nil {
+			checkpoint.AdditionalData[name] = additionalData
+		}
+	}
+	*c = checkpoint
+	return nil
+}
+
+func registersJSON(pc memory.Relocatable, ap memory.Relocatable, fp memory.Relocatable) [3]memory.JsonAddress {
+	return [3]memory.JsonAddress{memory.ToJsonAddress(pc), memory.ToJsonAddress(ap), memory.ToJsonAddress(fp)}
+}
+
+func decodeRegisters(encoded [3]memory.JsonAddress) ([3]memory.Relocatable, error) {
+	var registers [3]memory.Relocatable
+	for i, encodedAddr := range encoded {
+		addr, err := encodedAddr.Relocatable()
+		if err != nil {
+			return registers, err
+		}
+		registers[i] = addr
+	}
+	return registers, nil
+}
+
+func marshalCell(addr memory.Relocatable, value memory.MaybeRelocatable) ([2]json.RawMessage, error) {
+	rawAddr, err := json.Marshal(memory.ToJsonAddress(addr))
+	if err != nil {
+		return [2]json.RawMessage{}, err
+	}
+	rawValue, err := json.Marshal(memory.MaybeRelocatableToJson(value))
+	return [2]json.RawMessage{rawAddr, rawValue}, err
+}
+
+func unmarshalCell(cell [2]json.RawMessage) (memory.Relocatable, memory.MaybeRelocatable, error) {
+	addr, err := memory.DecodeJsonRelocatable(cell[0])
+	if err != nil {
+		return memory.Relocatable{}, memory.MaybeRelocatable{}, err
+	}
+	value, err := memory.DecodeJsonMaybeRelocatable(cell[1])
+	if err != nil {
+		return addr, memory.MaybeRelocatable{}, errors.Wrapf(err, "invalid value at %s", addr.ToString())
+	}
+	return addr, value, nil
+}
@@ -0,0 +1,112 @@
+package runners_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+// main:  [ap] = first, ap++; [ap] = second, ap++; ret
+func checkpointTestProgram(first uint64, second uint64) vm.Program {
+	return vmtest.Program("0x480680017fff8000", strconv.FormatUint(first, 10), "0x480680017fff8000", strconv.FormatUint(second, 10), "0x208b7fff7fff7ffe")
+}
+
+func initializedCheckpointRunner(t *testing.T, program vm.Program) (*runners.CairoRunner, memory.Relocatable) {
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner failed with error: %s", err)
+	}
+	end, err := runner.Initialize()
+	if err != nil {
+		t.Fatalf("Initialize failed with error: %s", err)
+	}
+	return runner, end
+}
+
+func TestCheckpointResumesRun(t *testing.T) {
+	program := checkpointTestProgram(5, 6)
+	hintProcessor := &hints.CairoVmHintProcessor{}
+
+	expected, end := initializedCheckpointRunner(t, program)
+	if err := expected.RunUntilPC(end, hintProcessor); err != nil {
+		t.Fatalf("RunUntilPC failed with error: %s", err)
+	}
+
+	runner, _ := initializedCheckpointRunner(t, program)
+	runResources := vm.NewRunResources(1)
+	runner.Vm.RunResources = &runResources
+	if err := runner.RunUntilPC(end, hintProcessor); !errors.Is(err, runners.ErrUnfinishedExecution) {
+		t.Fatalf("The run should have stopped after one step, got: %v", err)
+	}
+	checkpoint, err := runner.Checkpoint()
+	if err != nil {
+		t.Fatalf("Checkpoint failed with error: %s", err)
+	}
+	var buffer bytes.Buffer
+	if err := runners.WriteCheckpoint(&buffer, checkpoint); err != nil {
+		t.Fatalf("WriteCheckpoint failed with error: %s", err)
+	}
+	read, err := runners.ReadCheckpoint(&buffer)
+	if err != nil {
+		t.Fatalf("ReadCheckpoint failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(read.Memory, checkpoint.Memory) || !reflect.DeepEqual(read.Trace, checkpoint.Trace) ||
+		read.RunContext != checkpoint.RunContext || read.CurrentStep != 1 {
+		t.Fatalf("The checkpoint changed through serialization: %+v, %+v", read, checkpoint)
+	}
+
+	resumed, _ := initializedCheckpointRunner(t, program)
+	if err := resumed.RestoreCheckpoint(read); err != nil {
+		t.Fatalf("RestoreCheckpoint failed with error: %s", err)
+	}
+	if err := resumed.RunUntilPC(end, hintProcessor); err != nil {
+		t.Fatalf("The resumed run failed with error: %s", err)
+	}
+	if resumed.Vm.CurrentStep != expected.Vm.CurrentStep || resumed.Vm.RunContext != expected.Vm.RunContext {
+		t.Errorf("Wrong state after resuming, expected step %d, got %d", expected.Vm.CurrentStep, resumed.Vm.CurrentStep)
+	}
+	if !reflect.DeepEqual(resumed.Vm.Trace, expected.Vm.Trace) {
+		t.Errorf("Wrong trace after resuming, expected %v, got %v", expected.Vm.Trace, resumed.Vm.Trace)
+	}
+	if !reflect.DeepEqual(resumed.Vm.Segments.Memory.Data, expected.Vm.Segments.Memory.Data) {
+		t.Errorf("Wrong memory after resuming")
+	}
+	if !reflect.DeepEqual(resumed.Vm.Segments.Memory.GetAccessedAddresses(1), expected.Vm.Segments.Memory.GetAccessedAddresses(1)) {
+		t.Errorf("Wrong accessed addresses after resuming")
+	}
+}
+
+func TestRestoreCheckpointDifferentProgram(t *testing.T) {
+	runner, _ := initializedCheckpointRunner(t, checkpointTestProgram(5, 6))
+	checkpoint, err := runner.Checkpoint()
+	if err != nil {
+		t.Fatalf("Checkpoint failed with error: %s", err)
+	}
+	other, _ := initializedCheckpointRunner(t, checkpointTestProgram(5, 7))
+	if err := other.RestoreCheckpoint(checkpoint); !errors.Is(err, memory.ErrInconsistentMemory) {
+		t.Errorf("Expected ErrInconsistentMemory, got: %v", err)
+	}
+}
+
+func TestRestoreCheckpointAfterRunning(t *testing.T) {
+	program := checkpointTestProgram(5, 6)
+	runner, _ := initializedCheckpointRunner(t, program)
+	checkpoint, err := runner.Checkpoint()
+	if err != nil {
+		t.Fatalf("Checkpoint failed with error: %s", err)
+	}
+	if err := runner.RunForSteps(1, &hints.CairoVmHintProcessor{}); err != nil {
+		t.Fatalf("RunForSteps failed with error: %s", err)
+	}
+	if err := runner.RestoreCheckpoint(checkpoint); !errors.Is(err, runners.ErrCheckpoint) {
+		t.Errorf("Expected ErrCheckpoint, got: %v", err)
+	}
+}
@@ -0,0 +1,110 @@
+package runners
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Matches every error returned by ValidateEntrypointArgs, through errors.Is
+var ErrInvalidEntrypointArg = errors.New("Invalid entrypoint argument")
+
+// Reasons an entrypoint argument can be rejected for, see EntrypointArgError.Err
+var ErrArgUnknownSegment = errors.New("references a segment that doesn't exist")
+var ErrArgNotInField = errors.New("doesn't fit in the field")
+var ErrArgInvalidType = errors.New("has an invalid type, expected MaybeRelocatable, *big.Int, []MaybeRelocatable or [][]MaybeRelocatable")
+
+// Describes an entrypoint argument rejected by ValidateEntrypointArgs
+type EntrypointArgError struct {
+	// Position of the argument in the args
+	Arg int
+	// Position of the rejected value inside the argument, when it is a slice (ie: [1 2] for args[Arg][1][2])
+	Path []int
+	// The rejected value
+	Value any
+	// ErrArgUnknownSegment, ErrArgNotInField or ErrArgInvalidType
+	Err error
+}
+
+func (e *EntrypointArgError) Error() string {
+	position := fmt.Sprintf("args[%d]", e.Arg)
+	for _, idx := range e.Path {
+		position += fmt.Sprintf("[%d]", idx)
+	}
+	return fmt.Sprintf("%s: %s (%v) %s", ErrInvalidEntrypointArg, position, e.Value, e.Err)
+}
+
+func (e *EntrypointArgError) Unwrap() []error {
+	return []error{ErrRunner, ErrInvalidEntrypointArg, e.Err}
+}
+
+/*
+Checks that the args can be passed to RunFromEntrypoint, so that invalid args are rejected before any of them is
+loaded into memory instead of failing mid-run
+Relocatable values must reference segments that already exist & *big.Int values must be in the range [0, PRIME)
+Returns an *EntrypointArgError describing the first invalid value found
+*/
+func (runner *CairoRunner) ValidateEntrypointArgs(args []any) error {
+	_, err := runner.entrypointArgs(args)
+	return err
+}
+
+// Validates the args, converting the *big.Int values into felts so that they can be handled by GenArg
+func (runner *CairoRunner) entrypointArgs(args []any) ([]any, error) {
+	converted := make([]any, 0, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case memory.MaybeRelocatable:
+			if err := runner.validateEntrypointValue(arg, i, nil); err != nil {
+				return nil, err
+			}
+			converted = append(converted, arg)
+		case *big.Int:
+			felt, err := entrypointFelt(arg, i)
+			if err != nil {
+				return nil, err
+			}
+			converted = append(converted, *memory.NewMaybeRelocatableFelt(felt))
+		case []memory.MaybeRelocatable:
+			for j, value := range arg {
+				if err := runner.validateEntrypointValue(value, i, []int{j}); err != nil {
+					return nil, err
+				}
+			}
+			converted = append(converted, arg)
+		case [][]memory.MaybeRelocatable:
+			for j, data := range arg {
+				for k, value := range data {
+					if err := runner.validateEntrypointValue(value, i, []int{j, k}); err != nil {
+						return nil, err
+					}
+				}
+			}
+			converted = append(converted, arg)
+		default:
+			return nil, &EntrypointArgError{Arg: i, Value: arg, Err: ErrArgInvalidType}
+		}
+	}
+	return converted, nil
+}
+
+func (runner *CairoRunner) validateEntrypointValue(value memory.MaybeRelocatable, arg int, path []int) error {
+	ptr, ok := value.GetRelocatable()
+	if ok && (ptr.SegmentIndex < 0 || uint(ptr.SegmentIndex) >= runner.Vm.Segments.Memory.NumSegments()) {
+		return &EntrypointArgError{Arg: arg, Path: path, Value: ptr.ToString(), Err: ErrArgUnknownSegment}
+	}
+	return nil
+}
+
+func entrypointFelt(value *big.Int, arg int) (lambdaworks.Felt, error) {
+	if value == nil {
+		return lambdaworks.Felt{}, &EntrypointArgError{Arg: arg, Value: value, Err: ErrArgInvalidType}
+	}
+	if value.Sign() < 0 || value.Cmp(lambdaworks.Prime()) >= 0 {
+		return lambdaworks.Felt{}, &EntrypointArgError{Arg: arg, Value: value, Err: ErrArgNotInField}
+	}
+	return lambdaworks.FeltFromBigInt(value), nil
+}
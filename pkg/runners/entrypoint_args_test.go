@@ -0,0 +1,91 @@
+package runners_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Returns a runner for a function that returns its only argument: [ap] = [fp - 3], ap++; ret
+func identityRunner(t *testing.T) *runners.CairoRunner {
+	program := vm.Program{
+		Data: []memory.MaybeRelocatable{
+			*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x480a7ffd7fff8000")),
+			*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x208b7fff7fff7ffe")),
+		},
+		Identifiers: map[string]vm.Identifier{},
+	}
+	runner, err := runners.NewCairoRunner(program, "plain", false)
+	if err != nil {
+		t.Fatalf("NewCairoRunner error in test: %s", err)
+	}
+	runner.InitializeBuiltins()
+	runner.InitializeSegments()
+	return runner
+}
+
+func TestRunFromEntrypointBigIntArg(t *testing.T) {
+	runner := identityRunner(t)
+	arg := new(big.Int).Sub(lambdaworks.Prime(), big.NewInt(1))
+	if err := runner.RunFromEntrypoint(0, []any{arg}, &hints.CairoVmHintProcessor{}, nil, false, nil); err != nil {
+		t.Fatalf("RunFromEntrypoint failed with error: %s", err)
+	}
+	res, err := runner.Vm.GetReturnValues(1)
+	if err != nil || res[0] != *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-1")) {
+		t.Errorf("Wrong value returned: %v, %v", res, err)
+	}
+}
+
+func TestRunFromEntrypointInvalidArgs(t *testing.T) {
+	cases := []struct {
+		name     string
+		args     []any
+		expected error
+		arg      int
+		path     []int
+	}{
+		{"FeltOverPrime", []any{lambdaworks.Prime()}, runners.ErrArgNotInField, 0, nil},
+		{"NegativeFelt", []any{*memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()), big.NewInt(-1)}, runners.ErrArgNotInField, 1, nil},
+		{"UnknownSegment", []any{*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(7, 0))}, runners.ErrArgUnknownSegment, 0, nil},
+		{"NegativeSegment", []any{*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(-1, 0))}, runners.ErrArgUnknownSegment, 0, nil},
+		{"UnknownSegmentInSlice", []any{[][]memory.MaybeRelocatable{
+			{*memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne())},
+			{*memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()), *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(9, 1))},
+		}}, runners.ErrArgUnknownSegment, 0, []int{1, 1}},
+		{"InvalidType", []any{3}, runners.ErrArgInvalidType, 0, nil},
+	}
+	for _, c := range cases {
+		runner := identityRunner(t)
+		numSegments := runner.Vm.Segments.Memory.NumSegments()
+		err := runner.RunFromEntrypoint(0, c.args, &hints.CairoVmHintProcessor{}, nil, false, nil)
+		if !errors.Is(err, c.expected) || !errors.Is(err, runners.ErrInvalidEntrypointArg) || !errors.Is(err, runners.ErrRunner) {
+			t.Errorf("%s: expected %s, got %v", c.name, c.expected, err)
+			continue
+		}
+		var argErr *runners.EntrypointArgError
+		if !errors.As(err, &argErr) || argErr.Arg != c.arg || len(argErr.Path) != len(c.path) {
+			t.Errorf("%s: wrong position of the invalid arg: %v", c.name, err)
+		}
+		if runner.Vm.Segments.Memory.NumSegments() != numSegments {
+			t.Errorf("%s: the args should have been rejected before loading any of them", c.name)
+		}
+	}
+}
+
+func TestValidateEntrypointArgs(t *testing.T) {
+	runner := identityRunner(t)
+	args := []any{
+		*memory.NewMaybeRelocatableRelocatable(runner.ProgramBase),
+		big.NewInt(5),
+		[]memory.MaybeRelocatable{*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 3))},
+	}
+	if err := runner.ValidateEntrypointArgs(args); err != nil {
+		t.Errorf("The args should be valid: %s", err)
+	}
+}
@@ -0,0 +1,50 @@
+package runners
+
+import (
+	"fmt"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Error category of the errors raised by the runner, which can be matched through errors.Is
+var ErrRunner = errors.New("Runner error")
+
+func RunnerError(err error) error {
+	return &memory.CategoryError{Category: ErrRunner, Err: err}
+}
+
+var ErrRunnerCalledTwice = RunnerError(errors.New("EndRun called twice."))
+var ErrFinalizeNoEndRun = RunnerError(errors.New("end_run must be called before finalize_segments."))
+var ErrNoExecPublicMemory = RunnerError(errors.New("Cannot finalize segments without an execution public memory"))
+var ErrReadReturnValuesNoEndRun = RunnerError(errors.New("end_run must be called before read_return_values."))
+var ErrFailedAddingReturnValues = RunnerError(errors.New("Cannot add the return values to the public memory after segment finalization."))
+var ErrGetReturnValuesNoEndRun = RunnerError(errors.New("end_run must be called before get_return_values."))
+var ErrSummaryNoEndRun = RunnerError(errors.New("end_run must be called before summary."))
+var ErrRelocateNoEndRun = RunnerError(errors.New("end_run must be called before relocate."))
+var ErrUnfinishedExecution = RunnerError(errors.New("Could not reach the end of the program. RunResources has no remaining steps."))
+var ErrNoBuiltinForInstance = errors.New("not present in layout")
+var ErrInfiniteLoop = errors.New("Infinite loop detected")
+
+// The field of the vm is fixed to the Stark prime by the felt implementation, programs compiled for another prime
+// would have their constants silently reduced, so they are rejected
+var ErrPrimeDiffers = errors.New("The program was compiled for a prime other than the Stark prime")
+
+func PrimeDiffersError(prime string) error {
+	return RunnerError(fmt.Errorf("%w: %s, expected %s", ErrPrimeDiffers, prime, lambdaworks.CAIRO_PRIME_HEX))
+}
+
+// Number of steps between two checks of the context of a run, see CairoRunner.RunUntilPCContext
+const CONTEXT_CHECK_INTERVAL = 1024
+
+var ErrRunCancelled = errors.New("Run cancelled")
+
+// The cause (ie: context.DeadlineExceeded) can be matched through errors.Is too
+func RunCancelledError(cause error, step uint) error {
+	return RunnerError(fmt.Errorf("%w at step %d: %w", ErrRunCancelled, step, cause))
+}
+
+func InfiniteLoopError(pc memory.Relocatable) error {
+	return RunnerError(fmt.Errorf("%w at pc=%s: the step left pc, ap & fp unchanged", ErrInfiniteLoop, pc.ToString()))
+}
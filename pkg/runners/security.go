@@ -5,6 +5,9 @@ import (
 	"github.com/pkg/errors"
 )
 
+var ErrRelocatableInBuiltinSegment = errors.New("Security Error: Invalid relocatable value in builtin segment")
+var ErrBuiltinPointerOutOfBounds = errors.New("Security Error: Pointer out of bounds of builtin segment")
+
 /*
 Verify that the completed run in a runner is safe to be relocated and be
 used by other Cairo programs.
@@ -15,6 +18,8 @@ Checks include:
   - There must not be accesses to the program segment outside the program
     data range. This check will use the `programSegmentSize` instead of the program data length if available.
   - All addresses in memory must be real (not temporary)
+  - (Only if `verifyBuiltins` is set to true) The values of the builtin segments must follow the builtins' ValuePolicy,
+    and the pointers to the builtin segments can't go past their stop pointers.
 
 Note: Each builtin is responsible for checking its own segments' data.
 */
@@ -26,6 +31,7 @@ func VerifySecureRunner(runner *CairoRunner, verifyBuiltins bool, programSegment
 	// Get builtin segment info
 	builtinNames := make(map[int]string)
 	builtinSizes := make(map[int]uint)
+	builtinPolicies := make(map[int]builtins.ValuePolicy)
 	if verifyBuiltins {
 		for i := 0; i < len(runner.Vm.BuiltinRunners); i++ {
 			base, stopPtr, err := runner.Vm.BuiltinRunners[i].GetMemorySegmentAddresses()
@@ -34,6 +40,7 @@ func VerifySecureRunner(runner *CairoRunner, verifyBuiltins bool, programSegment
 			}
 			builtinNames[base.SegmentIndex] = runner.Vm.BuiltinRunners[i].Name()
 			builtinSizes[base.SegmentIndex] = stopPtr.Offset
+			builtinPolicies[base.SegmentIndex] = runner.Vm.BuiltinRunners[i].ValuePolicy()
 		}
 	}
 	// Run memory checks
@@ -55,6 +62,19 @@ func VerifySecureRunner(runner *CairoRunner, verifyBuiltins bool, programSegment
 		if isRel && relVal.SegmentIndex < 0 {
 			return errors.Errorf("Security Error: Invalid Memory Value: temporary address not relocated: %s", relVal.ToString())
 		}
+		if !isRel {
+			continue
+		}
+		// Check relocatable values written to builtin segments
+		if policy, ok := builtinPolicies[addr.SegmentIndex]; ok {
+			if policy == builtins.FeltValuesOnly || (policy == builtins.RelocatableValuesWithinSegment && relVal.SegmentIndex != addr.SegmentIndex) {
+				return errors.Wrapf(ErrRelocatableInBuiltinSegment, "%s at %s: %s", builtinNames[addr.SegmentIndex], addr.ToString(), relVal.ToString())
+			}
+		}
+		// Check pointers past the end of builtin segments, the stop pointer itself is valid
+		if size, ok := builtinSizes[relVal.SegmentIndex]; ok && relVal.Offset > size {
+			return errors.Wrapf(ErrBuiltinPointerOutOfBounds, "%s at %s: %s", builtinNames[relVal.SegmentIndex], addr.ToString(), relVal.ToString())
+		}
 	}
 	// Run builtin-specific checks
 	for i := 0; i < len(runner.Vm.BuiltinRunners); i++ {
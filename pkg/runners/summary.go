@@ -0,0 +1,95 @@
+package runners
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Statistics of a finished run, to compare it at a glance with other runs (ie: with the output of the Rust vm)
+type RunSummary struct {
+	Steps uint
+	// Size of each memory segment (including holes), by segment index
+	// Temporary segments are not included, as they are merged into the other segments when relocated
+	SegmentSizes []uint
+	MemoryHoles  uint
+	// Builtins used by the program, in the order of the layout
+	Builtins []BuiltinSummary
+	// Hints declared by the program, and number of hint executions during the run
+	Hints          uint
+	HintExecutions uint
+	// Time spent executing the steps of the run, which doesn't include relocating it
+	Elapsed time.Duration
+}
+
+type BuiltinSummary struct {
+	Name      string
+	Instances uint
+	UsedCells uint
+}
+
+// Returns the statistics of the run, which has to be ended (see EndRun)
+func (runner *CairoRunner) Summary() (RunSummary, error) {
+	if !runner.RunEnded {
+		return RunSummary{}, ErrSummaryNoEndRun
+	}
+	segments := &runner.Vm.Segments
+	summary := RunSummary{
+		Steps:          runner.Vm.CurrentStep,
+		SegmentSizes:   make([]uint, 0, segments.Memory.NumSegments()),
+		HintExecutions: runner.hintExecutions,
+		Elapsed:        runner.elapsed,
+	}
+	for i := uint(0); i < segments.Memory.NumSegments(); i++ {
+		size, err := segments.GetSegmentSize(i)
+		if err != nil {
+			return RunSummary{}, err
+		}
+		summary.SegmentSizes = append(summary.SegmentSizes, size)
+	}
+	holes, err := runner.GetMemoryHoles()
+	if err != nil {
+		return RunSummary{}, err
+	}
+	summary.MemoryHoles = holes
+	for _, builtin := range runner.Vm.BuiltinRunners {
+		instances, err := builtin.GetUsedInstances(segments)
+		if err != nil {
+			return RunSummary{}, err
+		}
+		usedCells, err := segments.GetSegmentUsedSize(uint(builtin.Base().SegmentIndex))
+		if err != nil {
+			return RunSummary{}, err
+		}
+		summary.Builtins = append(summary.Builtins, BuiltinSummary{Name: builtin.Name(), Instances: instances, UsedCells: usedCells})
+	}
+	for _, hints := range runner.Program.Hints {
+		summary.Hints += uint(len(hints))
+	}
+	return summary, nil
+}
+
+// Formats the summary as a human readable report, one statistic per line & a table per segment & builtin
+func (s RunSummary) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "steps: %d\n", s.Steps)
+	fmt.Fprintf(&builder, "elapsed: %s\n", s.Elapsed.Round(time.Microsecond))
+	fmt.Fprintf(&builder, "memory holes: %d\n", s.MemoryHoles)
+	fmt.Fprintf(&builder, "hints: %d declared, %d executions\n", s.Hints, s.HintExecutions)
+
+	writer := tabwriter.NewWriter(&builder, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "segment\tsize")
+	for i, size := range s.SegmentSizes {
+		fmt.Fprintf(writer, "%d\t%d\n", i, size)
+	}
+	writer.Flush()
+	if len(s.Builtins) != 0 {
+		fmt.Fprintln(writer, "builtin\tinstances\tused cells")
+		for _, builtin := range s.Builtins {
+			fmt.Fprintf(writer, "%s\t%d\t%d\n", builtin.Name, builtin.Instances, builtin.UsedCells)
+		}
+		writer.Flush()
+	}
+	return builder.String()
+}
@@ -0,0 +1,77 @@
+package runners_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints/hint_codes"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+func TestRunSummary(t *testing.T) {
+	// main{output_ptr}: %{ memory[ap] = segments.add() %} ap += 1; [ap] = 42, ap++; [ap - 1] = [[fp - 3]];
+	// [ap] = [fp - 3] + 1, ap++; ret
+	// Besides the program, execution & output segments, the run adds the return fp, end & hint segments
+	program := vmtest.Program("0x40780017fff7fff", "0x1", "0x480680017fff8000", "0x2a", "0x400280007ffd7fff", "0x482680017ffd8000", "0x1", "0x208b7fff7fff7ffe")
+	program.Builtins = []string{"output"}
+	program.Hints = map[uint][]parser.HintParams{0: {{
+		Code:             hint_codes.ADD_SEGMENT,
+		AccessibleScopes: []string{"__main__", "__main__.main"},
+	}}}
+	runner, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "small"})
+	if err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	summary, err := runner.Summary()
+	if err != nil {
+		t.Fatalf("Summary failed with error: %s", err)
+	}
+	if summary.Steps != 5 || summary.Hints != 1 || summary.HintExecutions != 1 || summary.Elapsed <= 0 {
+		t.Errorf("Wrong summary: %+v", summary)
+	}
+	if !reflect.DeepEqual(summary.SegmentSizes, []uint{8, 6, 1, 0, 0, 0}) || summary.MemoryHoles != 1 {
+		t.Errorf("Wrong segments: %v, %d holes", summary.SegmentSizes, summary.MemoryHoles)
+	}
+	if !reflect.DeepEqual(summary.Builtins, []runners.BuiltinSummary{{Name: "output", Instances: 1, UsedCells: 1}}) {
+		t.Errorf("Wrong builtins: %+v", summary.Builtins)
+	}
+	if report := summary.String(); !strings.Contains(report, "steps: 5\n") || !strings.Contains(report, "output   1          1\n") {
+		t.Errorf("Wrong report:\n%s", report)
+	}
+}
+
+func TestRunSummaryProofModePadding(t *testing.T) {
+	// __start__: call main; __end__: %{ noop() %} jmp rel 0; main: [ap] = 1, ap++; ret
+	// Padding the trace runs the hint of __end__ on every step after the first three
+	program := vmtest.ProofModeProgram(4, 2, "0x1104800180018000", "0x4", "0x10780017fff7fff", "0x0", "0x480680017fff8000", "0x1", "0x208b7fff7fff7ffe")
+	program.Hints = map[uint][]parser.HintParams{2: {{Code: "noop()"}}}
+	noop := func(*hints.HintContext) error { return nil }
+	hintProcessor := &hints.CairoVmHintProcessor{CustomHints: map[string]hints.CustomHint{"noop()": noop}}
+	runner, err := cairo_run.NewRunner(program, cairo_run.WithProofMode(), cairo_run.WithHintProcessor(hintProcessor))
+	if err != nil {
+		t.Fatalf("NewRunner failed with error: %s", err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	summary, err := runner.Summary()
+	if err != nil {
+		t.Fatalf("Summary failed with error: %s", err)
+	}
+	if summary.Steps <= 3 || summary.HintExecutions != summary.Steps-3 {
+		t.Errorf("The padding steps should be counted, got %d hint executions in %d steps", summary.HintExecutions, summary.Steps)
+	}
+}
+
+func TestRunSummaryNoEndRun(t *testing.T) {
+	runner := identityRunner(t)
+	if _, err := runner.Summary(); !errors.Is(err, runners.ErrSummaryNoEndRun) {
+		t.Errorf("Expected ErrSummaryNoEndRun, got %v", err)
+	}
+}
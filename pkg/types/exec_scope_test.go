@@ -1,10 +1,15 @@
 package types_test
 
 import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	"github.com/lambdaclass/cairo-vm.go/pkg/types"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 )
 
 func TestGetLocalVariables(t *testing.T) {
@@ -254,8 +259,33 @@ func TestErrExitMainScope(t *testing.T) {
 	scopes := types.NewExecutionScopes()
 
 	err := scopes.ExitScope()
-	if err != types.ErrCannotExitMainScop {
-		t.Errorf("TestErrExitMainScope should fail with error: %s and fails with: %s", types.ErrCannotExitMainScop, err)
+	if err != types.ErrCannotExitMainScope {
+		t.Errorf("TestErrExitMainScope should fail with error: %s and fails with: %s", types.ErrCannotExitMainScope, err)
+	}
+}
+
+func TestErrExitMainScopeType(t *testing.T) {
+	scopes := types.NewExecutionScopes()
+	scopes.EnterScope(nil)
+	if err := scopes.ExitScope(); err != nil {
+		t.Fatalf("Exiting a nested scope failed with error: %s", err)
+	}
+
+	var exitMainScopeErr *types.ExitMainScopeError
+	if err := scopes.ExitScope(); !errors.As(err, &exitMainScopeErr) {
+		t.Errorf("Expected an ExitMainScopeError, got: %v", err)
+	}
+	if locals, err := scopes.GetLocalVariables(); err != nil || locals == nil {
+		t.Errorf("The main scope should remain, got: %v, err: %v", locals, err)
+	}
+}
+
+func TestEnterNilScope(t *testing.T) {
+	scopes := types.NewExecutionScopes()
+	scopes.EnterScope(nil)
+	scopes.AssignOrUpdateVariable("a", uint64(1))
+	if val, err := types.FetchScopeVar[uint64]("a", scopes); err != nil || val != 1 {
+		t.Errorf("Wrong value: %d, err: %v", val, err)
 	}
 }
 
@@ -276,3 +306,188 @@ func TestFetchScopeVar(t *testing.T) {
 		t.Errorf("TestGetLocalVariables failed, expected: %s, got: %s", expected.ToSignedFeltString(), result.ToSignedFeltString())
 	}
 }
+
+func TestInspectExecutionScopes(t *testing.T) {
+	scopes := types.NewExecutionScopes()
+	scopes.AssignOrUpdateVariable("n", uint64(1))
+	scopes.EnterScope(map[string]interface{}{"b": lambdaworks.FeltOne(), "a": []uint{}})
+	scopes.EnterScope(map[string]interface{}{})
+
+	expected := [][]types.ScopeVariable{
+		{{Name: "n", Type: "uint64"}},
+		{{Name: "a", Type: "[]uint"}, {Name: "b", Type: "lambdaworks.Felt"}},
+		{},
+	}
+	inspected := scopes.Inspect()
+	if !reflect.DeepEqual(inspected, expected) {
+		t.Errorf("Wrong scopes, expected: %v, got: %v", expected, inspected)
+	}
+	formatted := types.FormatScopes(inspected)
+	expectedFormat := "scope 0: n (uint64)\nscope 1: a ([]uint), b (lambdaworks.Felt)\nscope 2: <empty>"
+	if formatted != expectedFormat {
+		t.Errorf("Wrong format, expected:\n%s\ngot:\n%s", expectedFormat, formatted)
+	}
+}
+
+func TestCloneExecutionScopes(t *testing.T) {
+	scopes := types.NewExecutionScopes()
+	scopes.AssignOrUpdateVariable("a", uint64(1))
+	scopes.EnterScope(map[string]interface{}{"b": uint64(2)})
+
+	clone := scopes.Clone()
+	clone.AssignOrUpdateVariable("b", uint64(3))
+	if err := clone.ExitScope(); err != nil {
+		t.Fatalf("ExitScope failed with error: %s", err)
+	}
+
+	if b, err := scopes.Get("b"); err != nil || b != uint64(2) {
+		t.Errorf("The original scopes shouldn't change, got b = %v, %v", b, err)
+	}
+	if a, err := clone.Get("a"); err != nil || a != uint64(1) {
+		t.Errorf("Wrong value for a in the clone: %v, %v", a, err)
+	}
+}
+
+type counter struct{ n int }
+
+func (c *counter) CloneScopeValue() interface{} {
+	clone := *c
+	return &clone
+}
+
+func TestCloneExecutionScopesCopiesValues(t *testing.T) {
+	shared := &counter{n: 1}
+	positions := map[lambdaworks.Felt][]uint64{lambdaworks.FeltOne(): {1, 2}}
+	scopes := types.NewExecutionScopes()
+	scopes.AssignOrUpdateVariable("counter", shared)
+	scopes.AssignOrUpdateVariable("positions_dict", positions)
+	scopes.EnterScope(map[string]interface{}{"counter": shared})
+
+	clone := scopes.Clone()
+	clonedCounter, err := types.FetchScopeVar[*counter]("counter", clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clonedCounter.n++
+	if err := clone.ExitScope(); err != nil {
+		t.Fatal(err)
+	}
+	mainCounter, err := types.FetchScopeVar[*counter]("counter", clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mainCounter != clonedCounter {
+		t.Error("A value held by several scopes should be copied once")
+	}
+	clonedPositions, err := types.FetchScopeVar[map[lambdaworks.Felt][]uint64]("positions_dict", clone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clonedPositions[lambdaworks.FeltOne()][0] = 7
+
+	if shared.n != 1 || positions[lambdaworks.FeltOne()][0] != 1 {
+		t.Errorf("The original values shouldn't change, got counter %d & positions %v", shared.n, positions)
+	}
+}
+
+// Not comparable, so it can't be used as a map key
+type felts []lambdaworks.Felt
+
+func (f felts) CloneScopeValue() interface{} {
+	return append(felts(nil), f...)
+}
+
+func TestCloneExecutionScopesNonComparableValue(t *testing.T) {
+	values := felts{lambdaworks.FeltOne()}
+	scopes := types.NewExecutionScopes()
+	scopes.AssignOrUpdateVariable("values", values)
+
+	clonedValues, err := types.FetchScopeVar[felts]("values", scopes.Clone())
+	if err != nil {
+		t.Fatal(err)
+	}
+	clonedValues[0] = lambdaworks.FeltZero()
+	if values[0] != lambdaworks.FeltOne() {
+		t.Errorf("The original value shouldn't change, got %v", values)
+	}
+}
+
+func TestExecutionScopesJSONRoundTrip(t *testing.T) {
+	felt := *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-1"))
+	addr := *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 3))
+	mainScope := map[string]interface{}{
+		"n":       3,
+		"done":    true,
+		"name":    "main",
+		"SECP_P":  *big.NewInt(-7),
+		"value":   big.NewInt(11),
+		"missing": (*big.Int)(nil),
+	}
+	innerScope := map[string]interface{}{
+		"felt":         lambdaworks.FeltFromDecString("-2"),
+		"count":        uint(4),
+		"last":         uint64(5),
+		"ptr":          memory.NewRelocatable(1, 0),
+		"key":          addr,
+		"indices":      []int{3, 1},
+		"keys":         []uint64{7},
+		"felts":        []lambdaworks.Felt{lambdaworks.FeltOne()},
+		"values":       []memory.MaybeRelocatable{felt, addr},
+		"access":       map[memory.MaybeRelocatable][]int{felt: {1}, addr: {0, 2}},
+		"positions":    map[lambdaworks.Felt][]uint64{lambdaworks.FeltOne(): {4}},
+		"initial_dict": map[memory.MaybeRelocatable]memory.MaybeRelocatable{felt: addr, addr: felt},
+	}
+	scopes := types.NewExecutionScopes()
+	for name, value := range mainScope {
+		scopes.AssignOrUpdateVariable(name, value)
+	}
+	scopes.EnterScope(innerScope)
+
+	encoded, err := json.Marshal(scopes)
+	if err != nil {
+		t.Fatalf("Marshal failed with error: %s", err)
+	}
+	reencoded, _ := json.Marshal(scopes)
+	if string(encoded) != string(reencoded) {
+		t.Errorf("The encoding should be deterministic:\n%s\n%s", encoded, reencoded)
+	}
+	var decoded types.ExecutionScopes
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed with error: %s", err)
+	}
+
+	locals, _ := decoded.GetLocalVariables()
+	if !reflect.DeepEqual(locals, innerScope) {
+		t.Errorf("Wrong inner scope, expected %v, got %v", innerScope, locals)
+	}
+	if err := decoded.ExitScope(); err != nil {
+		t.Fatalf("The decoded scopes should have an inner scope: %s", err)
+	}
+	locals, _ = decoded.GetLocalVariables()
+	if !reflect.DeepEqual(locals, mainScope) {
+		t.Errorf("Wrong main scope, expected %v, got %v", mainScope, locals)
+	}
+}
+
+func TestExecutionScopesJSONUnserializableVariable(t *testing.T) {
+	scopes := types.NewExecutionScopesWithInitValue("__dict_manager", &struct{}{})
+	_, err := json.Marshal(scopes)
+	if !errors.Is(err, types.ErrUnserializableScopeVariable) {
+		t.Errorf("Expected ErrUnserializableScopeVariable, got: %v", err)
+	}
+}
+
+func TestExecutionScopesJSONInvalidInput(t *testing.T) {
+	inputs := []string{
+		`[]`,
+		`[{"n": {"type": "dict_manager", "value": null}}]`,
+		`[{"n": {"type": "felt", "value": "-1"}}]`,
+		`[{"n": {"type": "relocatable", "value": [1, -1]}}]`,
+	}
+	for _, input := range inputs {
+		var scopes types.ExecutionScopes
+		if err := json.Unmarshal([]byte(input), &scopes); err == nil {
+			t.Errorf("Unmarshaling %s should fail", input)
+		}
+	}
+}
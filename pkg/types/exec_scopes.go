@@ -1,6 +1,15 @@
 package types
 
 import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+
 	"github.com/pkg/errors"
 )
 
@@ -8,7 +17,17 @@ type ExecutionScopes struct {
 	data []map[string]interface{}
 }
 
-var ErrCannotExitMainScop error = ExecutionScopesError(errors.Errorf("Cannot exit main scope."))
+// Error returned when attempting to exit the main scope, which is never popped
+type ExitMainScopeError struct{}
+
+func (e *ExitMainScopeError) Error() string {
+	return "Execution scopes error: Cannot exit main scope."
+}
+
+var ErrCannotExitMainScope error = &ExitMainScopeError{}
+
+// Deprecated: use ErrCannotExitMainScope
+var ErrCannotExitMainScop = ErrCannotExitMainScope
 
 func ExecutionScopesError(err error) error {
 	return errors.Wrapf(err, "Execution scopes error")
@@ -28,14 +47,97 @@ func NewExecutionScopes() *ExecutionScopes {
 	return &ExecutionScopes{data}
 }
 
+// Implemented by the scope variables holding state the hints mutate in place (ie: the dict manager), so that
+// ExecutionScopes.Clone copies them instead of sharing them with the clone
+// Only pointer implementers are copied once when held by several scopes, other implementers are copied every time
+type ScopeValueCloner interface {
+	CloneScopeValue() interface{}
+}
+
+// Returns a copy of the scopes, entering or exiting scopes & assigning variables in it doesn't affect the original
+// The lists, maps & big ints held by the variables are copied, as are the values implementing ScopeValueCloner
+// A value held by several scopes (ie: the dict manager) is copied once, so that the copies are shared in the clone
+func (es *ExecutionScopes) Clone() *ExecutionScopes {
+	cloned := make(map[clonedPointer]interface{})
+	data := make([]map[string]interface{}, 0, len(es.data))
+	for _, scope := range es.data {
+		clonedScope := make(map[string]interface{}, len(scope))
+		for name, value := range scope {
+			clonedScope[name] = cloneScopeValue(value, cloned)
+		}
+		data = append(data, clonedScope)
+	}
+	return &ExecutionScopes{data}
+}
+
+// Identity of a pointer ScopeValueCloner, the implementers themselves can't be used as map keys
+// as they may not be comparable
+type clonedPointer struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// Copies the mutable values held by scope variables, cloned holds the pointer ScopeValueCloners copied so far
+func cloneScopeValue(value interface{}, cloned map[clonedPointer]interface{}) interface{} {
+	switch v := value.(type) {
+	case ScopeValueCloner:
+		reflected := reflect.ValueOf(v)
+		if reflected.Kind() != reflect.Pointer {
+			return v.CloneScopeValue()
+		}
+		key := clonedPointer{typ: reflected.Type(), ptr: reflected.Pointer()}
+		clone, ok := cloned[key]
+		if !ok {
+			clone = v.CloneScopeValue()
+			cloned[key] = clone
+		}
+		return clone
+	case *big.Int:
+		if v == nil {
+			return v
+		}
+		return new(big.Int).Set(v)
+	case []int:
+		return append([]int(nil), v...)
+	case []uint64:
+		return append([]uint64(nil), v...)
+	case []lambdaworks.Felt:
+		return append([]lambdaworks.Felt(nil), v...)
+	case []memory.MaybeRelocatable:
+		return append([]memory.MaybeRelocatable(nil), v...)
+	case map[memory.MaybeRelocatable][]int:
+		clone := make(map[memory.MaybeRelocatable][]int, len(v))
+		for key, indices := range v {
+			clone[key] = append([]int(nil), indices...)
+		}
+		return clone
+	case map[lambdaworks.Felt][]uint64:
+		clone := make(map[lambdaworks.Felt][]uint64, len(v))
+		for key, positions := range v {
+			clone[key] = append([]uint64(nil), positions...)
+		}
+		return clone
+	case map[memory.MaybeRelocatable]memory.MaybeRelocatable:
+		clone := make(map[memory.MaybeRelocatable]memory.MaybeRelocatable, len(v))
+		for key, dictValue := range v {
+			clone[key] = dictValue
+		}
+		return clone
+	}
+	return value
+}
+
+// Enters a new scope holding the given variables, a nil map enters an empty scope
 func (es *ExecutionScopes) EnterScope(newScopeLocals map[string]interface{}) {
+	if newScopeLocals == nil {
+		newScopeLocals = make(map[string]interface{})
+	}
 	es.data = append(es.data, newScopeLocals)
-
 }
 
 func (es *ExecutionScopes) ExitScope() error {
 	if len(es.data) < 2 {
-		return ErrCannotExitMainScop
+		return ErrCannotExitMainScope
 	}
 	i := len(es.data) - 1
 	es.data = es.data[:i]
@@ -103,3 +205,46 @@ func FetchScopeVar[T interface{}](varName string, scopes *ExecutionScopes) (T, e
 	}
 	return val, nil
 }
+
+// Name & dynamic type of a variable of an execution scope
+type ScopeVariable struct {
+	Name string
+	Type string
+}
+
+// Returns the variables of each scope, from the main scope to the innermost one, sorted by name
+// The values themselves are not exposed, so the scopes can't be modified through the result
+func (es *ExecutionScopes) Inspect() [][]ScopeVariable {
+	scopes := make([][]ScopeVariable, 0, len(es.data))
+	for _, locals := range es.data {
+		variables := make([]ScopeVariable, 0, len(locals))
+		for name, value := range locals {
+			variables = append(variables, ScopeVariable{Name: name, Type: fmt.Sprintf("%T", value)})
+		}
+		sort.Slice(variables, func(i, j int) bool { return variables[i].Name < variables[j].Name })
+		scopes = append(scopes, variables)
+	}
+	return scopes
+}
+
+// Formats the result of ExecutionScopes.Inspect, one scope per line
+// ie: scope 1: dict_manager (*hints.DictManager), n (uint64)
+func FormatScopes(scopes [][]ScopeVariable) string {
+	var builder strings.Builder
+	for i, variables := range scopes {
+		if i > 0 {
+			builder.WriteString("\n")
+		}
+		fmt.Fprintf(&builder, "scope %d: ", i)
+		if len(variables) == 0 {
+			builder.WriteString("<empty>")
+		}
+		for j, variable := range variables {
+			if j > 0 {
+				builder.WriteString(", ")
+			}
+			fmt.Fprintf(&builder, "%s (%s)", variable.Name, variable.Type)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,294 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"sort"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Execution scopes are encoded as a list of scopes, from the main scope to the innermost one, each of them mapping
+// the name of each variable to its type & value: [{"n": {"type": "int", "value": 3}}]
+// Only the variables of the types listed below can be serialized, so that a run can be checkpointed & resumed.
+// Variables holding state shared with hints (ie: __dict_manager) can't
+
+var ErrUnserializableScopeVariable = errors.New("Scope variable can't be serialized")
+
+func UnserializableScopeVariableError(varName string, value interface{}) error {
+	return ExecutionScopesError(errors.Wrapf(ErrUnserializableScopeVariable, "%s of type %T", varName, value))
+}
+
+func InvalidSerializedScopeVariableError(varName string, err error) error {
+	return ExecutionScopesError(errors.Wrapf(err, "Invalid serialized variable %s", varName))
+}
+
+// Type tags of the serializable variables
+const (
+	scopeFelt                    = "felt"
+	scopeBigInt                  = "big_int"
+	scopeBigIntPtr               = "big_int_ptr"
+	scopeInt                     = "int"
+	scopeUint                    = "uint"
+	scopeUint64                  = "uint64"
+	scopeBool                    = "bool"
+	scopeString                  = "string"
+	scopeRelocatable             = "relocatable"
+	scopeMaybeRelocatable        = "maybe_relocatable"
+	scopeIntList                 = "int_list"
+	scopeUint64List              = "uint64_list"
+	scopeFeltList                = "felt_list"
+	scopeMaybeRelocatableList    = "maybe_relocatable_list"
+	scopeMaybeRelocatableToInts  = "maybe_relocatable_to_int_list"
+	scopeFeltToUint64s           = "felt_to_uint64_list"
+	scopeMaybeRelocatableToValue = "maybe_relocatable_to_maybe_relocatable"
+)
+
+type serializedScopeVariable struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Fails if a variable can't be serialized
+func (es ExecutionScopes) MarshalJSON() ([]byte, error) {
+	scopes := make([]map[string]serializedScopeVariable, 0, len(es.data))
+	for _, locals := range es.data {
+		scope := make(map[string]serializedScopeVariable, len(locals))
+		for name, value := range locals {
+			variable, err := serializeScopeVariable(value)
+			if err != nil {
+				return nil, UnserializableScopeVariableError(name, value)
+			}
+			scope[name] = variable
+		}
+		scopes = append(scopes, scope)
+	}
+	return json.Marshal(scopes)
+}
+
+func (es *ExecutionScopes) UnmarshalJSON(data []byte) error {
+	var scopes []map[string]serializedScopeVariable
+	if err := json.Unmarshal(data, &scopes); err != nil {
+		return ExecutionScopesError(err)
+	}
+	if len(scopes) == 0 {
+		return ExecutionScopesError(errors.New("Serialized scopes have no main scope"))
+	}
+	es.data = make([]map[string]interface{}, 0, len(scopes))
+	for _, scope := range scopes {
+		locals := make(map[string]interface{}, len(scope))
+		for name, variable := range scope {
+			value, err := deserializeScopeVariable(variable)
+			if err != nil {
+				return InvalidSerializedScopeVariableError(name, err)
+			}
+			locals[name] = value
+		}
+		es.data = append(es.data, locals)
+	}
+	return nil
+}
+
+func serializeScopeVariable(value interface{}) (serializedScopeVariable, error) {
+	var tag string
+	var encoded interface{}
+	switch v := value.(type) {
+	case lambdaworks.Felt:
+		tag, encoded = scopeFelt, feltString(v)
+	case big.Int:
+		tag, encoded = scopeBigInt, v.String()
+	case *big.Int:
+		tag, encoded = scopeBigIntPtr, nil
+		if v != nil {
+			encoded = v.String()
+		}
+	case int:
+		tag, encoded = scopeInt, v
+	case uint:
+		tag, encoded = scopeUint, v
+	case uint64:
+		tag, encoded = scopeUint64, v
+	case bool:
+		tag, encoded = scopeBool, v
+	case string:
+		tag, encoded = scopeString, v
+	case memory.Relocatable:
+		tag, encoded = scopeRelocatable, memory.ToJsonAddress(v)
+	case memory.MaybeRelocatable:
+		tag, encoded = scopeMaybeRelocatable, memory.MaybeRelocatableToJson(v)
+	case []int:
+		tag, encoded = scopeIntList, v
+	case []uint64:
+		tag, encoded = scopeUint64List, v
+	case []lambdaworks.Felt:
+		tag, encoded = scopeFeltList, mapSlice(v, feltString)
+	case []memory.MaybeRelocatable:
+		tag, encoded = scopeMaybeRelocatableList, mapSlice(v, memory.MaybeRelocatableToJson)
+	case map[memory.MaybeRelocatable][]int:
+		tag, encoded = scopeMaybeRelocatableToInts, sortedPairs(v, memory.MaybeRelocatableToJson, identity[[]int])
+	case map[lambdaworks.Felt][]uint64:
+		tag, encoded = scopeFeltToUint64s, sortedPairs(v, feltString, identity[[]uint64])
+	case map[memory.MaybeRelocatable]memory.MaybeRelocatable:
+		tag, encoded = scopeMaybeRelocatableToValue, sortedPairs(v, memory.MaybeRelocatableToJson, memory.MaybeRelocatableToJson)
+	default:
+		return serializedScopeVariable{}, errors.Errorf("unsupported type %T", value)
+	}
+	raw, err := json.Marshal(encoded)
+	if err != nil {
+		return serializedScopeVariable{}, err
+	}
+	return serializedScopeVariable{Type: tag, Value: raw}, nil
+}
+
+func deserializeScopeVariable(variable serializedScopeVariable) (interface{}, error) {
+	raw := variable.Value
+	switch variable.Type {
+	case scopeFelt:
+		return memory.DecodeJsonFelt(raw)
+	case scopeBigInt:
+		n, err := decodeBigInt(raw)
+		if err != nil {
+			return nil, err
+		}
+		return *n, nil
+	case scopeBigIntPtr:
+		if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+			return (*big.Int)(nil), nil
+		}
+		return decodeBigInt(raw)
+	case scopeInt:
+		return decode[int](raw)
+	case scopeUint:
+		return decode[uint](raw)
+	case scopeUint64:
+		return decode[uint64](raw)
+	case scopeBool:
+		return decode[bool](raw)
+	case scopeString:
+		return decode[string](raw)
+	case scopeRelocatable:
+		return memory.DecodeJsonRelocatable(raw)
+	case scopeMaybeRelocatable:
+		return memory.DecodeJsonMaybeRelocatable(raw)
+	case scopeIntList:
+		return decode[[]int](raw)
+	case scopeUint64List:
+		return decode[[]uint64](raw)
+	case scopeFeltList:
+		return decodeSlice(raw, memory.DecodeJsonFelt)
+	case scopeMaybeRelocatableList:
+		return decodeSlice(raw, memory.DecodeJsonMaybeRelocatable)
+	case scopeMaybeRelocatableToInts:
+		return decodePairs(raw, memory.DecodeJsonMaybeRelocatable, decode[[]int])
+	case scopeFeltToUint64s:
+		return decodePairs(raw, memory.DecodeJsonFelt, decode[[]uint64])
+	case scopeMaybeRelocatableToValue:
+		return decodePairs(raw, memory.DecodeJsonMaybeRelocatable, memory.DecodeJsonMaybeRelocatable)
+	}
+	return nil, errors.Errorf("unknown type %q", variable.Type)
+}
+
+// Felts are encoded as decimal strings, as they don't fit in a JSON number
+func feltString(felt lambdaworks.Felt) interface{} {
+	return memory.FeltToJson(felt)
+}
+
+func identity[T any](value T) interface{} {
+	return value
+}
+
+func mapSlice[T any](values []T, encode func(T) interface{}) []interface{} {
+	if values == nil {
+		return nil
+	}
+	encoded := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		encoded = append(encoded, encode(value))
+	}
+	return encoded
+}
+
+// Encodes a map as a list of [key, value] pairs, sorted by encoded key so that the encoding is deterministic
+func sortedPairs[K comparable, V any](values map[K]V, encodeKey func(K) interface{}, encodeValue func(V) interface{}) [][2]interface{} {
+	pairs := make([][2]interface{}, 0, len(values))
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		pairs = append(pairs, [2]interface{}{encodeKey(key), encodeValue(value)})
+	}
+	for _, pair := range pairs {
+		// Keys are strings or addresses, which always encode
+		key, _ := json.Marshal(pair[0])
+		keys = append(keys, string(key))
+	}
+	sort.Sort(pairsByKey{pairs, keys})
+	return pairs
+}
+
+type pairsByKey struct {
+	pairs [][2]interface{}
+	keys  []string
+}
+
+func (p pairsByKey) Len() int           { return len(p.pairs) }
+func (p pairsByKey) Less(i, j int) bool { return p.keys[i] < p.keys[j] }
+func (p pairsByKey) Swap(i, j int) {
+	p.pairs[i], p.pairs[j] = p.pairs[j], p.pairs[i]
+	p.keys[i], p.keys[j] = p.keys[j], p.keys[i]
+}
+
+func decode[T any](raw json.RawMessage) (T, error) {
+	var value T
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
+
+func decodeBigInt(raw json.RawMessage) (*big.Int, error) {
+	s, err := decode[string](raw)
+	if err != nil {
+		return nil, err
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, errors.Errorf("invalid integer %q", s)
+	}
+	return n, nil
+}
+
+func decodeSlice[T any](raw json.RawMessage, decodeValue func(json.RawMessage) (T, error)) ([]T, error) {
+	var encoded []json.RawMessage
+	if err := json.Unmarshal(raw, &encoded); err != nil || encoded == nil {
+		return nil, err
+	}
+	values := make([]T, 0, len(encoded))
+	for _, element := range encoded {
+		value, err := decodeValue(element)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+func decodePairs[K comparable, V any](raw json.RawMessage, decodeKey func(json.RawMessage) (K, error), decodeValue func(json.RawMessage) (V, error)) (map[K]V, error) {
+	var pairs [][2]json.RawMessage
+	if err := json.Unmarshal(raw, &pairs); err != nil {
+		return nil, err
+	}
+	values := make(map[K]V, len(pairs))
+	for _, pair := range pairs {
+		key, err := decodeKey(pair[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeValue(pair[1])
+		if err != nil {
+			return nil, err
+		}
+		values[key] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,242 @@
+package cairo_run
+
+import (
+	"strings"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Name of the segment arena in the builtins of Cairo 1 entrypoints
+// It is not a builtin runner, its segment is created & initialized along with the entrypoint's arguments
+const SEGMENT_ARENA_BUILTIN_NAME = "segment_arena"
+
+var ErrEntrypointNotFound = errors.New("Entrypoint not found")
+var ErrAmbiguousEntrypoint = errors.New("Entrypoint selector shared by entrypoints of different kinds")
+
+// Result of a Cairo 1 entrypoint run
+type Cairo1EntrypointResult struct {
+	// Return data of the entrypoint, or its panic data if it failed
+	Retdata      []lambdaworks.Felt
+	RemainingGas lambdaworks.Felt
+	// Set when the entrypoint panicked
+	Failed bool
+	// Runner used for the run, which holds the vm & the execution resources
+	Runner *Runner
+}
+
+// Converts a Cairo 1 contract class into a program, whose hints are run by hints.Cairo1HintProcessor
+// The program's builtins are the ones of the given entrypoint
+func Cairo1Program(class parser.CasmContractClass, entrypoint parser.CasmEntryPoint) vm.Program {
+	program := vm.Program{Hints: make(map[uint][]parser.HintParams)}
+	for _, word := range class.Bytecode {
+		program.Data = append(program.Data, *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex(word)))
+	}
+	for _, pcHints := range class.Hints {
+		for _, hint := range pcHints.Hints {
+			program.Hints[pcHints.Pc] = append(program.Hints[pcHints.Pc], parser.HintParams{Code: string(hint)})
+		}
+	}
+	for _, builtin := range entrypoint.Builtins {
+		if builtin != SEGMENT_ARENA_BUILTIN_NAME {
+			program.Builtins = append(program.Builtins, builtin)
+		}
+	}
+	return program
+}
+
+// Returns the entrypoint of the class with the given selector, among its external, l1 handler & constructor entrypoints
+// Fails with ErrAmbiguousEntrypoint if the selector belongs to entrypoints of more than one kind
+func FindCairo1Entrypoint(class parser.CasmContractClass, selector lambdaworks.Felt) (parser.CasmEntryPoint, error) {
+	entrypointsByType := []struct {
+		kind        string
+		entrypoints []parser.CasmEntryPoint
+	}{
+		{"external", class.EntryPointsByType.External},
+		{"l1_handler", class.EntryPointsByType.L1Handler},
+		{"constructor", class.EntryPointsByType.Constructor},
+	}
+	var found *parser.CasmEntryPoint
+	var foundKinds []string
+	for _, byType := range entrypointsByType {
+		for i := range byType.entrypoints {
+			if lambdaworks.FeltFromHex(byType.entrypoints[i].Selector) == selector {
+				found = &byType.entrypoints[i]
+				foundKinds = append(foundKinds, byType.kind)
+				break
+			}
+		}
+	}
+	if len(foundKinds) > 1 {
+		return parser.CasmEntryPoint{}, errors.Wrapf(ErrAmbiguousEntrypoint, "selector: %s, kinds: %s", selector.ToHexString(), strings.Join(foundKinds, ", "))
+	}
+	if found == nil {
+		return parser.CasmEntryPoint{}, errors.Wrapf(ErrEntrypointNotFound, "selector: %s", selector.ToHexString())
+	}
+	return *found, nil
+}
+
+// Runs the entrypoint of the Cairo 1 contract class with the given selector
+//
+// The entrypoint is called with the pointers of its builtins (in the order of its builtins list), the initial gas,
+// a pointer to an empty syscall segment & the calldata (as a start and end pointer), and returns the builtin pointers,
+// the remaining gas, the syscall pointer, a failure flag & the retdata (as a start and end pointer)
+// Secure runs also validate the segment arena returned by the entrypoint, if it takes one
+// Runs use the all_cairo layout and a hints.Cairo1HintProcessor unless the given options override them,
+// the proof mode option is not supported
+func RunCairo1Entrypoint(class parser.CasmContractClass, selector lambdaworks.Felt, calldata []lambdaworks.Felt, gas uint64, opts ...Option) (*Cairo1EntrypointResult, error) {
+	entrypoint, err := FindCairo1Entrypoint(class, selector)
+	if err != nil {
+		return nil, err
+	}
+	defaults := []Option{WithLayout("all_cairo"), WithHintProcessor(&hints.Cairo1HintProcessor{})}
+	runner, err := NewRunner(Cairo1Program(class, entrypoint), append(defaults, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	if runner.ProofMode {
+		return nil, errors.New("Cairo 1 entrypoints can't be run in proof mode")
+	}
+	if err := runner.InitializeBuiltins(); err != nil {
+		return nil, err
+	}
+	runner.InitializeSegments()
+
+	args, err := cairo1EntrypointArgs(runner, entrypoint, calldata, gas)
+	if err != nil {
+		return nil, err
+	}
+	programSegmentSize := uint(len(class.Bytecode))
+	err = runner.RunFromEntrypoint(entrypoint.Offset, args, runner.HintProcessor, runner.Vm.RunResources, false, nil)
+	if err != nil {
+		return nil, err
+	}
+	// The builtins' stop pointers are only read for main entrypoints, so they are not verified
+	if runner.SecureRun {
+		if err := runners.VerifySecureRunner(runner.CairoRunner, false, &programSegmentSize); err != nil {
+			return nil, err
+		}
+		if err := validateCairo1SegmentArena(runner, entrypoint); err != nil {
+			return nil, err
+		}
+	}
+	return readCairo1EntrypointResult(runner)
+}
+
+func cairo1EntrypointArgs(runner *Runner, entrypoint parser.CasmEntryPoint, calldata []lambdaworks.Felt, gas uint64) ([]any, error) {
+	args := make([]any, 0, len(entrypoint.Builtins)+4)
+	for _, name := range entrypoint.Builtins {
+		if name == SEGMENT_ARENA_BUILTIN_NAME {
+			// The segment arena starts with its info segment and its number of constructed & destructed segments
+			arena := runner.Vm.Segments.AddSegment()
+			info := runner.Vm.Segments.AddSegment()
+			data := []memory.MaybeRelocatable{
+				*memory.NewMaybeRelocatableRelocatable(info),
+				*memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()),
+				*memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()),
+			}
+			arenaPtr, err := runner.Vm.Segments.LoadData(arena, &data)
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, *memory.NewMaybeRelocatableRelocatable(arenaPtr))
+			continue
+		}
+		found := false
+		for i := range runner.Vm.BuiltinRunners {
+			if runner.Vm.BuiltinRunners[i].Name() == name {
+				for _, value := range runner.Vm.BuiltinRunners[i].InitialStack() {
+					args = append(args, value)
+				}
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("Unknown builtin in entrypoint: %s", name)
+		}
+	}
+
+	calldataStart := runner.Vm.Segments.AddSegment()
+	calldataValues := make([]memory.MaybeRelocatable, 0, len(calldata))
+	for _, value := range calldata {
+		calldataValues = append(calldataValues, *memory.NewMaybeRelocatableFelt(value))
+	}
+	calldataEnd, err := runner.Vm.Segments.LoadData(calldataStart, &calldataValues)
+	if err != nil {
+		return nil, err
+	}
+	return append(args,
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(gas)),
+		*memory.NewMaybeRelocatableRelocatable(runner.Vm.Segments.AddSegment()),
+		*memory.NewMaybeRelocatableRelocatable(calldataStart),
+		*memory.NewMaybeRelocatableRelocatable(calldataEnd),
+	), nil
+}
+
+// Validates the segment arena returned by the entrypoint (see ValidateSegmentArena), if it takes one
+// The entrypoint returns its builtin pointers in the same order it takes them, before the other 5 return values
+func validateCairo1SegmentArena(runner *Runner, entrypoint parser.CasmEntryPoint) error {
+	for i, name := range entrypoint.Builtins {
+		if name != SEGMENT_ARENA_BUILTIN_NAME {
+			continue
+		}
+		returnValues, err := runner.GetReturnValues(uint(len(entrypoint.Builtins)) + 5)
+		if err != nil {
+			return err
+		}
+		arenaPtr, ok := returnValues[i].GetRelocatable()
+		if !ok {
+			return errors.New("Expected the segment arena to be a pointer")
+		}
+		return ValidateSegmentArena(&runner.Vm.Segments, arenaPtr)
+	}
+	return nil
+}
+
+// Reads the remaining gas, failure flag & retdata at the end of the entrypoint's return values
+func readCairo1EntrypointResult(runner *Runner) (*Cairo1EntrypointResult, error) {
+	returnValues, err := runner.GetReturnValues(5)
+	if err != nil {
+		return nil, err
+	}
+	remainingGas, ok := returnValues[0].GetFelt()
+	if !ok {
+		return nil, errors.New("Expected the remaining gas to be a felt")
+	}
+	failureFlag, ok := returnValues[2].GetFelt()
+	if !ok {
+		return nil, errors.New("Expected the failure flag to be a felt")
+	}
+	retdataStart, ok := returnValues[3].GetRelocatable()
+	if !ok {
+		return nil, errors.New("Expected the retdata start to be a pointer")
+	}
+	retdataEnd, ok := returnValues[4].GetRelocatable()
+	if !ok {
+		return nil, errors.New("Expected the retdata end to be a pointer")
+	}
+	retdataSize, err := retdataEnd.Sub(retdataStart)
+	if err != nil {
+		return nil, err
+	}
+	size, err := retdataSize.ToUint()
+	if err != nil {
+		return nil, err
+	}
+	retdata, err := runner.Vm.Segments.GetFeltRange(retdataStart, size)
+	if err != nil {
+		return nil, err
+	}
+	return &Cairo1EntrypointResult{
+		Retdata:      retdata,
+		RemainingGas: remainingGas,
+		Failed:       !failureFlag.IsZero(),
+		Runner:       runner,
+	}, nil
+}
@@ -0,0 +1,107 @@
+package cairo_run_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Hand-assembled class whose entrypoints charge 10 gas & return their calldata
+// A hint at its first pc writes whether the initial gas covers that charge at [fp]
+// Entrypoint 0x1 takes the range_check builtin, entrypoint 0x2 the segment arena
+const echoClassPath = "testdata/cairo1_echo.casm.json"
+
+func parseEchoClass(t *testing.T) parser.CasmContractClass {
+	class, err := parser.ParseCasm(echoClassPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return class
+}
+
+func TestRunCairo1Entrypoint(t *testing.T) {
+	calldata := []lambdaworks.Felt{lambdaworks.FeltFromUint64(3), lambdaworks.FeltFromUint64(5)}
+	result, err := cairo_run.RunCairo1Entrypoint(parseEchoClass(t), lambdaworks.FeltFromUint64(1), calldata, 100)
+	if err != nil {
+		t.Fatalf("Entrypoint execution failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(result.Retdata, calldata) {
+		t.Errorf("Wrong retdata, expected %v, got %v", calldata, result.Retdata)
+	}
+	if result.RemainingGas != lambdaworks.FeltFromUint64(90) {
+		t.Errorf("Wrong remaining gas, expected 90, got %s", result.RemainingGas.ToSignedFeltString())
+	}
+	if result.Failed {
+		t.Error("Entrypoint should not have failed")
+	}
+	// Execution segment: range_check, gas, system, calldata start & end, return fp, return pc, then the hint's result
+	enoughGas, err := result.Runner.Vm.Segments.Memory.GetFelt(memory.NewRelocatable(1, 7))
+	if err != nil || !enoughGas.IsOne() {
+		t.Errorf("Expected the hint to find enough gas, got: %s, %v", enoughGas.ToSignedFeltString(), err)
+	}
+}
+
+func TestRunCairo1EntrypointNotEnoughGas(t *testing.T) {
+	result, err := cairo_run.RunCairo1Entrypoint(parseEchoClass(t), lambdaworks.FeltFromUint64(1), nil, 5)
+	if err != nil {
+		t.Fatalf("Entrypoint execution failed with error: %s", err)
+	}
+	if len(result.Retdata) != 0 {
+		t.Errorf("Expected an empty retdata, got %v", result.Retdata)
+	}
+	enoughGas, err := result.Runner.Vm.Segments.Memory.GetFelt(memory.NewRelocatable(1, 7))
+	if err != nil || !enoughGas.IsZero() {
+		t.Errorf("Expected the hint to find that there isn't enough gas, got: %s, %v", enoughGas.ToSignedFeltString(), err)
+	}
+}
+
+func TestRunCairo1EntrypointSegmentArena(t *testing.T) {
+	result, err := cairo_run.RunCairo1Entrypoint(parseEchoClass(t), lambdaworks.FeltFromUint64(2), nil, 100)
+	if err != nil {
+		t.Fatalf("Entrypoint execution failed with error: %s", err)
+	}
+	// The segment arena is passed in place of the range_check builtin
+	arenaPtr, err := result.Runner.Vm.Segments.Memory.GetRelocatable(memory.NewRelocatable(1, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if arenaPtr.Offset != 3 {
+		t.Errorf("Expected the arena pointer to be after its header, got: %s", arenaPtr.ToString())
+	}
+	if _, err := result.Runner.Vm.Segments.Memory.GetRelocatable(memory.NewRelocatable(arenaPtr.SegmentIndex, 0)); err != nil {
+		t.Errorf("Expected the arena to start with its info segment: %s", err)
+	}
+}
+
+func TestRunCairo1EntrypointMaxSteps(t *testing.T) {
+	_, err := cairo_run.RunCairo1Entrypoint(parseEchoClass(t), lambdaworks.FeltFromUint64(1), nil, 100, cairo_run.WithMaxSteps(3))
+	if err == nil {
+		t.Error("Entrypoint execution should have failed")
+	}
+}
+
+func TestRunCairo1EntrypointUnknownSelector(t *testing.T) {
+	_, err := cairo_run.RunCairo1Entrypoint(parseEchoClass(t), lambdaworks.FeltFromUint64(3), nil, 100)
+	if !errors.Is(err, cairo_run.ErrEntrypointNotFound) {
+		t.Errorf("Expected ErrEntrypointNotFound, got: %v", err)
+	}
+}
+
+func TestFindCairo1EntrypointAmbiguousSelector(t *testing.T) {
+	class := parseEchoClass(t)
+	class.EntryPointsByType.L1Handler = []parser.CasmEntryPoint{{Selector: "0x1", Offset: 0}}
+	_, err := cairo_run.FindCairo1Entrypoint(class, lambdaworks.FeltFromUint64(1))
+	if !errors.Is(err, cairo_run.ErrAmbiguousEntrypoint) {
+		t.Errorf("Expected ErrAmbiguousEntrypoint, got: %v", err)
+	}
+
+	entrypoint, err := cairo_run.FindCairo1Entrypoint(class, lambdaworks.FeltFromUint64(2))
+	if err != nil || entrypoint.Selector != "0x2" {
+		t.Errorf("Wrong entrypoint for an unambiguous selector: %+v, %v", entrypoint, err)
+	}
+}
@@ -1,16 +1,18 @@
 package cairo_run
 
 import (
-	"encoding/binary"
+	"context"
 	"fmt"
 	"io"
-	"sort"
+	"math/big"
+	"time"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
 	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 	"github.com/pkg/errors"
 )
 
@@ -24,59 +26,150 @@ type CairoRunConfig struct {
 	DisableTracePadding bool
 	ProofMode           bool
 	Layout              string
-	SecureRun           bool
+	// Function of the __main__ module the run starts from, main if empty
+	Entrypoint string
+	SecureRun  bool
+	// Hooks installed in the vm before the run starts
+	Hooks vm.StepHooks
+	// Observers registered in the vm before the run starts
+	StepObservers []vm.StepObserver
+	// Stream the trace to a temporary file instead of holding it in memory
+	// The caller is responsible for closing the runner's Vm.StreamedTrace
+	StreamTrace bool
+	// Number of workers used to relocate the trace & memory, a non-positive value uses one per available cpu
+	RelocationWorkers int
+	// Maximum number of steps the program can run for, zero means there is no limit
+	// Reaching it fails the run with runners.ErrUnfinishedExecution
+	MaxSteps uint
+	// Maximum size of the relocated memory, zero means there is no limit
+	// Exceeding it fails the relocation with vm.ErrMemoryLimitExceeded
+	MaxMemorySize uint
+	// Receives the events of the execution if set
+	Tracer vm.Tracer
+	// Charges the steps & builtin deductions of the execution if set, an error returned by it aborts the run
+	Meter vm.Meter
+	// Loads the program in CairoRun if set, instead of parsing it on every run
+	ProgramCache *vm.ProgramCache
+	// Collects the execution statistics of the program's hints if set
+	HintStats *hints.HintStats
+	// If set, the unknown hints of the program are recorded in it & skipped instead of failing the run
+	UnknownHints *hints.UnknownHintReport
+	// Return errors with the messages of the Rust vm, see WithCompatErrors
+	CompatErrors bool
+	// Hints implemented outside of the vm, by hint code, see hints.CairoVmHintProcessor.CustomHints
+	CustomHints map[string]hints.CustomHint
+	// Limits on the input size of the hints (ie: for untrusted programs), see hints.HintLimits
+	HintLimits hints.HintLimits
+	// Stops the execution once done if set, see WithContext
+	Context context.Context
+	// Maximum duration of the execution, zero means there is no limit, see WithTimeout
+	Timeout time.Duration
 }
 
 func CairoRunError(err error) error {
 	return errors.Wrapf(err, "Cairo Run Error\n")
 }
 
+// Parses & runs the program at programPath according to the given config
+// If the run fails after the runner has been created, the runner is returned alongside the error
+// so that its state can be inspected
 func CairoRun(programPath string, cairoRunConfig CairoRunConfig) (*runners.CairoRunner, error) {
+	if cairoRunConfig.ProgramCache != nil {
+		program, err := cairoRunConfig.ProgramCache.Load(programPath)
+		if err != nil {
+			return nil, CairoRunError(err)
+		}
+		return CairoRunProgram(program, cairoRunConfig)
+	}
 	compiledProgram, err := parser.Parse(programPath)
 	if err != nil {
 		return nil, CairoRunError(err)
 	}
 	programJson := vm.DeserializeProgramJson(compiledProgram)
+	return CairoRunProgram(programJson, cairoRunConfig)
+}
 
-	layout := cairoRunConfig.Layout
-	proofMode := cairoRunConfig.ProofMode
-
-	cairoRunner, err := runners.NewCairoRunner(programJson, layout, proofMode)
-	if err != nil {
-		return nil, err
-	}
-	end, err := cairoRunner.Initialize()
-	if err != nil {
-		return nil, err
-	}
-	hintProcessor := hints.CairoVmHintProcessor{}
-	err = cairoRunner.RunUntilPC(end, &hintProcessor)
-	if err != nil {
-		return nil, err
+// Same as CairoRun, with the contents of the compiled program instead of its path (ie: a program read from stdin)
+func CairoRunBytes(programJson []byte, cairoRunConfig CairoRunConfig) (*runners.CairoRunner, error) {
+	if cairoRunConfig.ProgramCache != nil {
+		program, err := cairoRunConfig.ProgramCache.LoadBytes(programJson)
+		if err != nil {
+			return nil, CairoRunError(err)
+		}
+		return CairoRunProgram(program, cairoRunConfig)
 	}
-	err = cairoRunner.EndRun(cairoRunConfig.DisableTracePadding, false, &hintProcessor)
+	compiledProgram, err := parser.ParseBytes(programJson)
 	if err != nil {
-		return nil, err
+		return nil, CairoRunError(err)
 	}
+	return CairoRunProgram(vm.DeserializeProgramJson(compiledProgram), cairoRunConfig)
+}
 
-	err = cairoRunner.ReadReturnValues()
+// Runs an already deserialized program according to the given config
+// As in CairoRun, the runner is returned alongside the error if the run fails after its creation
+func CairoRunProgram(program vm.Program, cairoRunConfig CairoRunConfig) (*runners.CairoRunner, error) {
+	runner, err := NewRunner(program, cairoRunConfig.Options()...)
 	if err != nil {
 		return nil, err
 	}
+	return runner.CairoRunner, runner.Run()
+}
 
+// Returns the runner options equivalent to the config, to be used with NewRunner
+func (cairoRunConfig CairoRunConfig) Options() []Option {
+	opts := []Option{
+		WithLayout(cairoRunConfig.Layout),
+		WithSecureRun(cairoRunConfig.SecureRun),
+		WithVmOptions(
+			vm.WithHooks(cairoRunConfig.Hooks),
+			vm.WithStepObservers(cairoRunConfig.StepObservers...),
+			vm.WithRelocationWorkers(cairoRunConfig.RelocationWorkers),
+		),
+	}
+	if cairoRunConfig.Entrypoint != "" {
+		opts = append(opts, WithEntrypoint(cairoRunConfig.Entrypoint))
+	}
 	if cairoRunConfig.ProofMode {
-		cairoRunner.FinalizeSegments()
+		opts = append(opts, WithProofMode())
 	}
-
-	if cairoRunConfig.SecureRun {
-		err = runners.VerifySecureRunner(cairoRunner, true, nil)
-		if err != nil {
-			return nil, err
-		}
+	if cairoRunConfig.DisableTracePadding {
+		opts = append(opts, WithoutTracePadding())
 	}
-
-	err = cairoRunner.Vm.Relocate()
-	return cairoRunner, err
+	if cairoRunConfig.StreamTrace {
+		opts = append(opts, WithStreamedTrace())
+	}
+	if cairoRunConfig.CompatErrors {
+		opts = append(opts, WithCompatErrors())
+	}
+	if cairoRunConfig.MaxSteps != 0 {
+		opts = append(opts, WithMaxSteps(cairoRunConfig.MaxSteps))
+	}
+	if cairoRunConfig.MaxMemorySize != 0 {
+		opts = append(opts, WithMaxMemorySize(cairoRunConfig.MaxMemorySize))
+	}
+	if cairoRunConfig.HintLimits != (hints.HintLimits{}) {
+		opts = append(opts, WithHintLimits(cairoRunConfig.HintLimits))
+	}
+	if cairoRunConfig.Context != nil {
+		opts = append(opts, WithContext(cairoRunConfig.Context))
+	}
+	if cairoRunConfig.Timeout != 0 {
+		opts = append(opts, WithTimeout(cairoRunConfig.Timeout))
+	}
+	if cairoRunConfig.Tracer != nil {
+		opts = append(opts, WithVmOptions(vm.WithTracer(cairoRunConfig.Tracer)))
+	}
+	if cairoRunConfig.Meter != nil {
+		opts = append(opts, WithVmOptions(vm.WithMeter(cairoRunConfig.Meter)))
+	}
+	if cairoRunConfig.HintStats != nil || cairoRunConfig.UnknownHints != nil || cairoRunConfig.CustomHints != nil {
+		opts = append(opts, WithHintProcessor(&hints.CairoVmHintProcessor{
+			Stats:        cairoRunConfig.HintStats,
+			UnknownHints: cairoRunConfig.UnknownHints,
+			CustomHints:  cairoRunConfig.CustomHints,
+		}))
+	}
+	return opts
 }
 
 // Writes the trace binary representation.
@@ -84,41 +177,57 @@ func CairoRun(programPath string, cairoRunConfig CairoRunConfig) (*runners.Cairo
 // Bincode encodes to little endian by default and each trace entry is composed of
 // 3 usize values that are padded to always reach 64 bit size.
 func WriteEncodedTrace(relocatedTrace []vm.RelocatedTraceEntry, dest io.Writer) error {
+	return WriteEncodedTraceWithOptions(relocatedTrace, dest, DefaultEncodingOptions())
+}
+
+// Writes the trace binary representation with the given encoding
+func WriteEncodedTraceWithOptions(relocatedTrace []vm.RelocatedTraceEntry, dest io.Writer, options EncodingOptions) error {
+	options, err := options.normalize()
+	if err != nil {
+		return err
+	}
 	for i, entry := range relocatedTrace {
-		ap_buffer := make([]byte, 8)
-		ap, err := entry.Ap.ToU64()
+		err := writeEncodedTraceEntry(i, entry, dest, options)
 		if err != nil {
 			return err
 		}
-		binary.LittleEndian.PutUint64(ap_buffer, ap)
-		_, err = dest.Write(ap_buffer)
-		if err != nil {
-			return encodeTraceError(i, err)
-		}
+	}
 
-		fp_buffer := make([]byte, 8)
-		fp, err := entry.Fp.ToU64()
-		if err != nil {
-			return err
-		}
-		binary.LittleEndian.PutUint64(fp_buffer, fp)
-		_, err = dest.Write(fp_buffer)
-		if err != nil {
-			return encodeTraceError(i, err)
-		}
+	return nil
+}
+
+// Writes the binary representation of the vm's relocated trace, in the same format as WriteEncodedTrace
+// Unlike WriteEncodedTrace, it also supports streamed traces, which are relocated while being written
+func WriteVmEncodedTrace(virtualMachine *vm.VirtualMachine, dest io.Writer) error {
+	return WriteVmEncodedTraceWithOptions(virtualMachine, dest, DefaultEncodingOptions())
+}
+
+// Writes the binary representation of the vm's relocated trace with the given encoding
+func WriteVmEncodedTraceWithOptions(virtualMachine *vm.VirtualMachine, dest io.Writer, options EncodingOptions) error {
+	options, err := options.normalize()
+	if err != nil {
+		return err
+	}
+	i := 0
+	return virtualMachine.ForEachRelocatedTraceEntry(func(entry vm.RelocatedTraceEntry) error {
+		err := writeEncodedTraceEntry(i, entry, dest, options)
+		i++
+		return err
+	})
+}
 
-		pc_buffer := make([]byte, 8)
-		pc, err := entry.Pc.ToU64()
+func writeEncodedTraceEntry(i int, entry vm.RelocatedTraceEntry, dest io.Writer, options EncodingOptions) error {
+	var buffer [3 * 8]byte
+	for j, register := range []lambdaworks.Felt{entry.Ap, entry.Fp, entry.Pc} {
+		value, err := register.ToU64()
 		if err != nil {
 			return err
 		}
-		binary.LittleEndian.PutUint64(pc_buffer, pc)
-		_, err = dest.Write(pc_buffer)
-		if err != nil {
-			return encodeTraceError(i, err)
-		}
+		options.ByteOrder.PutUint64(buffer[j*8:(j+1)*8], value)
+	}
+	if _, err := dest.Write(buffer[:]); err != nil {
+		return encodeTraceError(i, err)
 	}
-
 	return nil
 }
 
@@ -131,39 +240,103 @@ func encodeTraceError(i int, err error) error {
 // The memory pairs (address, value) are encoded and concatenated:
 // * address -> 8-byte encoded
 // * value -> 32-byte encoded
-func WriteEncodedMemory(relocatedMemory map[uint]lambdaworks.Felt, dest io.Writer) error {
-	// create a slice to store keys of the relocatedMemory map
-	keysMap := make([]uint, 0, len(relocatedMemory))
-	for k := range relocatedMemory {
-		keysMap = append(keysMap, k)
+func WriteEncodedMemory(relocatedMemory memory.RelocatedMemory, dest io.Writer) error {
+	return WriteEncodedMemoryWithOptions(relocatedMemory, dest, DefaultEncodingOptions())
+}
+
+// Writes a binary representation of the relocated memory with the given encoding
+func WriteEncodedMemoryWithOptions(relocatedMemory memory.RelocatedMemory, dest io.Writer, options EncodingOptions) error {
+	options, err := options.normalize()
+	if err != nil {
+		return err
 	}
+	// Only the written cells are encoded, by ascending address, holes are skipped
+	return relocatedMemory.ForEach(func(addr uint, value lambdaworks.Felt) error {
+		var keyArray [8]byte
+		options.ByteOrder.PutUint64(keyArray[:], uint64(addr))
+		if _, err := dest.Write(keyArray[:]); err != nil {
+			return encodeMemoryError(addr, err)
+		}
+
+		valueArray := options.feltBytes(value)
+		if _, err := dest.Write(valueArray[:]); err != nil {
+			return encodeMemoryError(addr, err)
+		}
+		return nil
+	})
+}
 
-	// sort the keys
-	sort.Slice(keysMap, func(i, j int) bool { return keysMap[i] < keysMap[j] })
+func encodeMemoryError(i uint, err error) error {
+	return fmt.Errorf("Failed to encode trace at position %d, serialize error: %s", i, err)
+}
 
-	// iterate over the `relocatedMemory` map in sorted key order
-	for _, k := range keysMap {
+// Reads a relocated trace in the binary representation written by WriteEncodedTrace
+func ReadEncodedTrace(src io.Reader) ([]vm.RelocatedTraceEntry, error) {
+	return ReadEncodedTraceWithOptions(src, DefaultEncodingOptions())
+}
 
-		// write the key
-		keyArray := make([]byte, 8)
-		binary.LittleEndian.PutUint64(keyArray, uint64(k))
-		_, err := dest.Write(keyArray)
+// Reads a relocated trace in the binary representation written by WriteEncodedTraceWithOptions
+func ReadEncodedTraceWithOptions(src io.Reader, options EncodingOptions) ([]vm.RelocatedTraceEntry, error) {
+	options, err := options.normalize()
+	if err != nil {
+		return nil, err
+	}
+	trace := make([]vm.RelocatedTraceEntry, 0)
+	var buffer [3 * 8]byte
+	for {
+		_, err := io.ReadFull(src, buffer[:])
+		if err == io.EOF {
+			return trace, nil
+		}
 		if err != nil {
-			return encodeMemoryError(k, err)
+			return nil, decodeTraceError(len(trace), err)
 		}
+		trace = append(trace, vm.RelocatedTraceEntry{
+			Ap: lambdaworks.FeltFromUint64(options.ByteOrder.Uint64(buffer[0:8])),
+			Fp: lambdaworks.FeltFromUint64(options.ByteOrder.Uint64(buffer[8:16])),
+			Pc: lambdaworks.FeltFromUint64(options.ByteOrder.Uint64(buffer[16:24])),
+		})
+	}
+}
+
+func decodeTraceError(i int, err error) error {
+	return fmt.Errorf("Failed to decode trace at position %d, deserialize error: %s", i, err)
+}
 
-		// write the value
-		valueArray := relocatedMemory[k].ToLeBytes()
+// Reads a relocated memory in the binary representation written by WriteEncodedMemory
+func ReadEncodedMemory(src io.Reader) (memory.RelocatedMemory, error) {
+	return ReadEncodedMemoryWithOptions(src, DefaultEncodingOptions())
+}
 
-		_, err = dest.Write(valueArray[:])
+// Reads a relocated memory in the binary representation written by WriteEncodedMemoryWithOptions
+func ReadEncodedMemoryWithOptions(src io.Reader, options EncodingOptions) (memory.RelocatedMemory, error) {
+	options, err := options.normalize()
+	if err != nil {
+		return memory.RelocatedMemory{}, err
+	}
+	var relocatedMemory memory.RelocatedMemory
+	var buffer [8 + 32]byte
+	for {
+		_, err := io.ReadFull(src, buffer[:])
+		if err == io.EOF {
+			return relocatedMemory, nil
+		}
 		if err != nil {
-			return encodeMemoryError(k, err)
+			return memory.RelocatedMemory{}, decodeMemoryError(relocatedMemory.NumCells(), err)
+		}
+		value := options.feltBeBytes(buffer[8:])
+		if new(big.Int).SetBytes(value[:]).Cmp(lambdaworks.Prime()) >= 0 {
+			return memory.RelocatedMemory{}, decodeMemoryError(relocatedMemory.NumCells(), errors.New("value is not a valid felt"))
+		}
+		// Insert rejects addresses that would grow the memory far beyond the number of cells read so far,
+		// so a corrupt address can't make us allocate an unbounded address space
+		addr := options.ByteOrder.Uint64(buffer[0:8])
+		if err := relocatedMemory.Insert(uint(addr), lambdaworks.FeltFromBeBytes(&value)); err != nil {
+			return memory.RelocatedMemory{}, decodeMemoryError(relocatedMemory.NumCells(), err)
 		}
 	}
-
-	return nil
 }
 
-func encodeMemoryError(i uint, err error) error {
-	return fmt.Errorf("Failed to encode trace at position %d, serialize error: %s", i, err)
+func decodeMemoryError(i int, err error) error {
+	return fmt.Errorf("Failed to decode memory at position %d, deserialize error: %w", i, err)
 }
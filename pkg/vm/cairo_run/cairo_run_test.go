@@ -2,9 +2,17 @@ package cairo_run_test
 
 import (
 	"bytes"
+	"encoding/binary"
+	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+	"github.com/pkg/errors"
 )
 
 func testProgram(programName string, t *testing.T) {
@@ -395,3 +403,260 @@ func TestUint256(t *testing.T) {
 func TestUint256Root(t *testing.T) {
 	testProgram("uint256_root", t)
 }
+
+func TestWriteVmEncodedTraceStreamed(t *testing.T) {
+	// main:  [ap] = 5, ap++; ret
+	program := vmtest.Program("0x480680017fff8000", "5", "0x208b7fff7fff7ffe")
+
+	runner, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "plain"})
+	if err != nil {
+		t.Errorf("Program execution failed with error: %s", err)
+	}
+	var expected bytes.Buffer
+	cairo_run.WriteEncodedTrace(runner.Vm.RelocatedTrace, &expected)
+
+	streamedRunner, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "plain", StreamTrace: true})
+	if err != nil {
+		t.Errorf("Program execution failed with error: %s", err)
+	}
+	defer streamedRunner.Vm.StreamedTrace.Close()
+	if len(streamedRunner.Vm.Trace) != 0 || len(streamedRunner.Vm.RelocatedTrace) != 0 {
+		t.Errorf("A streamed trace shouldn't be held in memory")
+	}
+	var result bytes.Buffer
+	err = cairo_run.WriteVmEncodedTrace(&streamedRunner.Vm, &result)
+	if err != nil {
+		t.Errorf("WriteVmEncodedTrace failed with error: %s", err)
+	}
+
+	if expected.Len() != 48 || !bytes.Equal(expected.Bytes(), result.Bytes()) {
+		t.Errorf("Wrong streamed trace encoding. Expected: %v, got: %v", expected.Bytes(), result.Bytes())
+	}
+}
+
+func TestCairoRunBytes(t *testing.T) {
+	// main:  [ap] = 5, ap++; ret
+	programJson := []byte(`{
+		"data": ["0x480680017fff8000", "0x5", "0x208b7fff7fff7ffe"],
+		"identifiers": {"__main__.main": {"pc": 0, "type": "function"}},
+		"hints": {},
+		"reference_manager": {"references": []}
+	}`)
+	for _, config := range []cairo_run.CairoRunConfig{{Layout: "plain"}, {Layout: "plain", ProgramCache: vm.NewProgramCache("")}} {
+		runner, err := cairo_run.CairoRunBytes(programJson, config)
+		if err != nil {
+			t.Fatalf("Program execution failed with error: %s", err)
+		}
+		if runner.Vm.CurrentStep != 2 {
+			t.Errorf("Expected 2 steps, got %d", runner.Vm.CurrentStep)
+		}
+	}
+	if _, err := cairo_run.CairoRunBytes([]byte("{"), cairo_run.CairoRunConfig{Layout: "plain"}); err == nil {
+		t.Errorf("Running an invalid program should fail")
+	}
+}
+
+func relocatedTrace(pcs ...uint64) []vm.RelocatedTraceEntry {
+	trace := make([]vm.RelocatedTraceEntry, 0, len(pcs))
+	for i, pc := range pcs {
+		trace = append(trace, vm.RelocatedTraceEntry{Pc: lambdaworks.FeltFromUint64(pc), Ap: lambdaworks.FeltFromUint64(uint64(10 + i)), Fp: lambdaworks.FeltFromUint64(10)})
+	}
+	return trace
+}
+
+func TestReadEncodedTraceAndMemory(t *testing.T) {
+	trace := relocatedTrace(1, 3, 5)
+	var encodedTrace bytes.Buffer
+	if err := cairo_run.WriteEncodedTrace(trace, &encodedTrace); err != nil {
+		t.Fatal(err)
+	}
+	decodedTrace, err := cairo_run.ReadEncodedTrace(&encodedTrace)
+	if err != nil {
+		t.Fatalf("ReadEncodedTrace failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(decodedTrace, trace) {
+		t.Errorf("Wrong decoded trace. Expected: %v, got: %v", trace, decodedTrace)
+	}
+
+	relocatedMemory := map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(7), 4: lambdaworks.FeltFromDecString("-1")}
+	var encodedMemory bytes.Buffer
+	if err := cairo_run.WriteEncodedMemory(memory.RelocatedMemoryFromMap(relocatedMemory), &encodedMemory); err != nil {
+		t.Fatal(err)
+	}
+	decodedMemory, err := cairo_run.ReadEncodedMemory(&encodedMemory)
+	if err != nil {
+		t.Fatalf("ReadEncodedMemory failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(decodedMemory.ToMap(), relocatedMemory) {
+		t.Errorf("Wrong decoded memory. Expected: %v, got: %v", relocatedMemory, decodedMemory)
+	}
+
+	_, err = cairo_run.ReadEncodedTrace(bytes.NewReader(make([]byte, 30)))
+	if err == nil {
+		t.Errorf("ReadEncodedTrace should fail on a truncated trace")
+	}
+}
+
+func TestReadEncodedMemoryHugeAddress(t *testing.T) {
+	// A single cell at a huge address would otherwise allocate the whole address space up to it
+	var encodedMemory bytes.Buffer
+	var key [8]byte
+	binary.LittleEndian.PutUint64(key[:], 1<<40)
+	encodedMemory.Write(key[:])
+	encodedMemory.Write(make([]byte, 32))
+	_, err := cairo_run.ReadEncodedMemory(&encodedMemory)
+	if !errors.Is(err, memory.ErrRelocatedAddressOutOfBounds) {
+		t.Errorf("Expected ErrRelocatedAddressOutOfBounds, got %v", err)
+	}
+}
+
+func TestEncodingOptionsBigEndian(t *testing.T) {
+	options, err := cairo_run.ParseEncodingOptions("be")
+	if err != nil {
+		t.Fatal(err)
+	}
+	trace := relocatedTrace(1, 3)
+	var encodedTrace bytes.Buffer
+	if err := cairo_run.WriteEncodedTraceWithOptions(trace, &encodedTrace, options); err != nil {
+		t.Fatal(err)
+	}
+	if encodedTrace.Len() != 2*24 || binary.BigEndian.Uint64(encodedTrace.Bytes()[16:24]) != 1 {
+		t.Errorf("Wrong big endian trace: %x", encodedTrace.Bytes())
+	}
+	decodedTrace, err := cairo_run.ReadEncodedTraceWithOptions(&encodedTrace, options)
+	if err != nil || !reflect.DeepEqual(decodedTrace, trace) {
+		t.Errorf("Wrong decoded trace: %v, %v", decodedTrace, err)
+	}
+
+	relocatedMemory := map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(7), 4: lambdaworks.FeltFromDecString("-1")}
+	var encodedMemory bytes.Buffer
+	if err := cairo_run.WriteEncodedMemoryWithOptions(memory.RelocatedMemoryFromMap(relocatedMemory), &encodedMemory, options); err != nil {
+		t.Fatal(err)
+	}
+	encoded := encodedMemory.Bytes()
+	if binary.BigEndian.Uint64(encoded[0:8]) != 1 || encoded[8+31] != 7 {
+		t.Errorf("Wrong big endian memory: %x", encoded)
+	}
+	decodedMemory, err := cairo_run.ReadEncodedMemoryWithOptions(&encodedMemory, options)
+	if err != nil || !reflect.DeepEqual(decodedMemory.ToMap(), relocatedMemory) {
+		t.Errorf("Wrong decoded memory: %v, %v", decodedMemory.ToMap(), err)
+	}
+}
+
+func TestEncodingOptionsZeroValueIsDefault(t *testing.T) {
+	trace := relocatedTrace(1, 3)
+	var expected, result bytes.Buffer
+	cairo_run.WriteEncodedTrace(trace, &expected)
+	if err := cairo_run.WriteEncodedTraceWithOptions(trace, &result, cairo_run.EncodingOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(expected.Bytes(), result.Bytes()) {
+		t.Errorf("The zero options should use the default encoding")
+	}
+}
+
+func TestEncodingOptionsUnsupported(t *testing.T) {
+	var dest bytes.Buffer
+	err := cairo_run.WriteEncodedTraceWithOptions(relocatedTrace(1), &dest, cairo_run.EncodingOptions{Version: 2})
+	if !errors.Is(err, cairo_run.ErrUnsupportedEncoding) {
+		t.Errorf("Expected ErrUnsupportedEncoding, got %v", err)
+	}
+	if _, err := cairo_run.ParseEncodingOptions("middle"); !errors.Is(err, cairo_run.ErrUnsupportedEncoding) {
+		t.Errorf("Expected ErrUnsupportedEncoding, got %v", err)
+	}
+}
+
+func TestCompareTrace(t *testing.T) {
+	if diff := cairo_run.CompareTrace(relocatedTrace(1, 3, 5), relocatedTrace(1, 3, 5)); diff != nil {
+		t.Errorf("Equal traces should have no diff, got: %s", diff)
+	}
+
+	diff := cairo_run.CompareTrace(relocatedTrace(1, 2, 3, 4, 5, 6, 7, 8, 9), relocatedTrace(1, 2, 3, 4, 5, 0, 7, 8, 9))
+	if diff == nil || diff.Step != 5 || diff.A.Pc != lambdaworks.FeltFromUint64(6) || diff.B.Pc != lambdaworks.FeltZero() {
+		t.Fatalf("Wrong diff: %+v", diff)
+	}
+	if len(diff.Context) != 2*cairo_run.DiffContextSize+1 || diff.Context[0].Step != 2 {
+		t.Errorf("Wrong context window: %+v", diff.Context)
+	}
+	expected := "Traces differ at step 5 (lengths: 9, 9)\n"
+	if !strings.HasPrefix(diff.String(), expected) || !strings.Contains(diff.String(), "> 5: pc=6 ap=15 fp=10 | pc=0 ap=15 fp=10\n") {
+		t.Errorf("Wrong diff message: %s", diff)
+	}
+
+	diff = cairo_run.CompareTrace(relocatedTrace(1, 3), relocatedTrace(1, 3, 5))
+	if diff == nil || diff.Step != 2 || diff.A != nil || diff.B == nil || diff.LenA != 2 || diff.LenB != 3 {
+		t.Errorf("Wrong diff for traces of different length: %+v", diff)
+	}
+}
+
+func TestCompareMemory(t *testing.T) {
+	a := memory.RelocatedMemoryFromMap(map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(1), 2: lambdaworks.FeltFromUint64(2), 5: lambdaworks.FeltFromUint64(5)})
+	if diff := cairo_run.CompareMemory(a, a); diff != nil {
+		t.Errorf("Equal memories should have no diff, got: %s", diff)
+	}
+
+	b := memory.RelocatedMemoryFromMap(map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(1), 2: lambdaworks.FeltFromUint64(2), 4: lambdaworks.FeltFromUint64(4), 5: lambdaworks.FeltFromUint64(6)})
+	diff := cairo_run.CompareMemory(a, b)
+	if diff == nil || diff.Address != 4 || diff.A != nil || *diff.B != lambdaworks.FeltFromUint64(4) {
+		t.Fatalf("Wrong diff: %+v", diff)
+	}
+	if len(diff.Context) != 4 || diff.Context[3].Address != 5 {
+		t.Errorf("Wrong context window: %+v", diff.Context)
+	}
+	if !strings.Contains(diff.String(), "> 4: - | 4\n") {
+		t.Errorf("Wrong diff message: %s", diff)
+	}
+}
+
+func TestCompareMemorySparse(t *testing.T) {
+	// The diff only walks the written cells, however far apart they are
+	a := memory.RelocatedMemoryFromMap(map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(1), 1 << 20: lambdaworks.FeltFromUint64(2)})
+	b := memory.RelocatedMemoryFromMap(map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(1), 1 << 19: lambdaworks.FeltFromUint64(3), 1 << 20: lambdaworks.FeltFromUint64(2)})
+	diff := cairo_run.CompareMemory(a, b)
+	if diff == nil || diff.Address != 1<<19 || diff.A != nil {
+		t.Fatalf("Wrong diff: %+v", diff)
+	}
+	if len(diff.Context) != 3 || diff.Context[0].Address != 1 || diff.Context[2].Address != 1<<20 {
+		t.Errorf("Wrong context window: %+v", diff.Context)
+	}
+}
+
+func TestEncodedMemoryWithHolesMatchesRustFormat(t *testing.T) {
+	// Address 0 is never used & addresses 3 to 5 are holes
+	cells := map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(7), 2: lambdaworks.FeltFromUint64(258), 6: lambdaworks.FeltFromDecString("-1")}
+	relocatedMemory := memory.RelocatedMemoryFromMap(cells)
+
+	// The Rust vm writes each present cell as its address (u64) followed by its value (32 bytes), both little endian
+	var expected bytes.Buffer
+	for _, addr := range []uint{1, 2, 6} {
+		var encodedAddr [8]byte
+		binary.LittleEndian.PutUint64(encodedAddr[:], uint64(addr))
+		value := cells[addr].ToLeBytes()
+		expected.Write(encodedAddr[:])
+		expected.Write(value[:])
+	}
+
+	var encoded bytes.Buffer
+	if err := cairo_run.WriteEncodedMemory(relocatedMemory, &encoded); err != nil {
+		t.Fatalf("WriteEncodedMemory failed with error: %s", err)
+	}
+	if !bytes.Equal(encoded.Bytes(), expected.Bytes()) {
+		t.Errorf("Wrong encoding.\nExpected: %x\nGot:      %x", expected.Bytes(), encoded.Bytes())
+	}
+
+	decoded, err := cairo_run.ReadEncodedMemory(bytes.NewReader(encoded.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadEncodedMemory failed with error: %s", err)
+	}
+	for addr := uint(0); addr < 8; addr++ {
+		value, ok := decoded.Get(addr)
+		expectedValue, expectedOk := cells[addr]
+		if ok != expectedOk || value != expectedValue {
+			t.Errorf("Wrong cell %d after the round trip: %v (present: %t)", addr, value, ok)
+		}
+	}
+	var reencoded bytes.Buffer
+	if err := cairo_run.WriteEncodedMemory(decoded, &reencoded); err != nil || !bytes.Equal(reencoded.Bytes(), encoded.Bytes()) {
+		t.Errorf("The memory should round trip identically, err: %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package cairo_run
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Number of entries (or memory cells) before & after the first mismatch included in a diff
+const DiffContextSize = 3
+
+// Entry of the context window of a TraceDiff, A or B are nil if the corresponding trace has no entry at Step
+type TraceDiffEntry struct {
+	Step int
+	A    *vm.RelocatedTraceEntry
+	B    *vm.RelocatedTraceEntry
+}
+
+// First mismatch between two relocated traces
+type TraceDiff struct {
+	// The entry at the first step at which the traces differ
+	TraceDiffEntry
+	LenA int
+	LenB int
+	// Entries around the mismatching step, including it
+	Context []TraceDiffEntry
+}
+
+// Compares two relocated traces, returns nil if they are equal
+func CompareTrace(a []vm.RelocatedTraceEntry, b []vm.RelocatedTraceEntry) *TraceDiff {
+	traceDiffEntry := func(step int) TraceDiffEntry {
+		entry := TraceDiffEntry{Step: step}
+		if step < len(a) {
+			entry.A = &a[step]
+		}
+		if step < len(b) {
+			entry.B = &b[step]
+		}
+		return entry
+	}
+	steps := len(a)
+	if len(b) > steps {
+		steps = len(b)
+	}
+	for step := 0; step < steps; step++ {
+		if step < len(a) && step < len(b) && a[step] == b[step] {
+			continue
+		}
+		diff := TraceDiff{TraceDiffEntry: traceDiffEntry(step), LenA: len(a), LenB: len(b)}
+		for i := step - DiffContextSize; i <= step+DiffContextSize; i++ {
+			if i >= 0 && i < steps {
+				diff.Context = append(diff.Context, traceDiffEntry(i))
+			}
+		}
+		return &diff
+	}
+	return nil
+}
+
+func (d *TraceDiff) String() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Traces differ at step %d (lengths: %d, %d)\n", d.Step, d.LenA, d.LenB)
+	for _, entry := range d.Context {
+		marker := " "
+		if entry.Step == d.Step {
+			marker = ">"
+		}
+		fmt.Fprintf(&msg, "%s %d: %s | %s\n", marker, entry.Step, traceEntryToString(entry.A), traceEntryToString(entry.B))
+	}
+	return msg.String()
+}
+
+func traceEntryToString(entry *vm.RelocatedTraceEntry) string {
+	if entry == nil {
+		return "-"
+	}
+	return fmt.Sprintf("pc=%s ap=%s fp=%s", entry.Pc.ToSignedFeltString(), entry.Ap.ToSignedFeltString(), entry.Fp.ToSignedFeltString())
+}
+
+// Cell of the context window of a MemoryDiff, A or B are nil if the corresponding memory has no value at Address
+type MemoryDiffCell struct {
+	Address uint
+	A       *lambdaworks.Felt
+	B       *lambdaworks.Felt
+}
+
+// First mismatch between two relocated memories
+type MemoryDiff struct {
+	// The cell at the lowest address at which the memories differ
+	MemoryDiffCell
+	// Cells around the mismatching address (among the addresses present in either memory), including it
+	Context []MemoryDiffCell
+}
+
+// Compares two relocated memories, returns nil if they are equal
+func CompareMemory(a memory.RelocatedMemory, b memory.RelocatedMemory) *MemoryDiff {
+	// Addresses present in either memory, in ascending order
+	// They are collected from the written cells, so that the work done is bounded by the number of cells rather than
+	// by the size of the address spaces
+	addresses := mergeAddresses(writtenAddresses(a), writtenAddresses(b))
+
+	memoryDiffCell := func(addr uint) MemoryDiffCell {
+		cell := MemoryDiffCell{Address: addr}
+		if value, ok := a.Get(addr); ok {
+			cell.A = &value
+		}
+		if value, ok := b.Get(addr); ok {
+			cell.B = &value
+		}
+		return cell
+	}
+	for i, addr := range addresses {
+		valueA, okA := a.Get(addr)
+		valueB, okB := b.Get(addr)
+		if okA && okB && valueA == valueB {
+			continue
+		}
+		diff := MemoryDiff{MemoryDiffCell: memoryDiffCell(addr)}
+		for j := i - DiffContextSize; j <= i+DiffContextSize; j++ {
+			if j >= 0 && j < len(addresses) {
+				diff.Context = append(diff.Context, memoryDiffCell(addresses[j]))
+			}
+		}
+		return &diff
+	}
+	return nil
+}
+
+// Returns the addresses of the written cells of the memory, in ascending order
+func writtenAddresses(m memory.RelocatedMemory) []uint {
+	addresses := make([]uint, 0, m.NumCells())
+	m.ForEach(func(addr uint, _ lambdaworks.Felt) error {
+		addresses = append(addresses, addr)
+		return nil
+	})
+	return addresses
+}
+
+// Merges two ascending lists of addresses into a single one without duplicates
+func mergeAddresses(a []uint, b []uint) []uint {
+	merged := make([]uint, 0, len(a)+len(b))
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case j == len(b) || (i < len(a) && a[i] < b[j]):
+			merged = append(merged, a[i])
+			i++
+		case i == len(a) || b[j] < a[i]:
+			merged = append(merged, b[j])
+			j++
+		default:
+			merged = append(merged, a[i])
+			i++
+			j++
+		}
+	}
+	return merged
+}
+
+func (d *MemoryDiff) String() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Memories differ at address %d\n", d.Address)
+	for _, cell := range d.Context {
+		marker := " "
+		if cell.Address == d.Address {
+			marker = ">"
+		}
+		fmt.Fprintf(&msg, "%s %d: %s | %s\n", marker, cell.Address, feltToString(cell.A), feltToString(cell.B))
+	}
+	return msg.String()
+}
+
+func feltToString(felt *lambdaworks.Felt) string {
+	if felt == nil {
+		return "-"
+	}
+	return felt.ToSignedFeltString()
+}
@@ -0,0 +1,139 @@
+package cairo_run
+
+import (
+	"io/fs"
+	"path"
+	"sync"
+	"time"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/parallel"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+)
+
+// Options of RunCorpus
+type CorpusOptions struct {
+	// Options of every run, they must not share mutable values (ie: hint processors or step observers)
+	// as programs are run concurrently
+	Options []Option
+	// Maximum number of programs run at a time, a non-positive value runs one program per available cpu
+	Parallelism int
+	// Selects the files of the corpus that are programs, by their path in the filesystem
+	// All the .json files are selected if nil
+	Match func(path string) bool
+	// Selects the programs that are expected to fail, by their path in the filesystem
+	// Their runs pass if they fail & fail if they succeed
+	ExpectFailure func(path string) bool
+}
+
+// Outcome of running a program of a corpus
+type CorpusProgramResult struct {
+	// Path of the program in the filesystem
+	Path string
+	// Steps executed, zero if the program couldn't be loaded
+	Steps    uint
+	Duration time.Duration
+	// Error of the run, or of loading the program
+	Err error
+	// Set if the program was expected to fail, see CorpusOptions.ExpectFailure
+	ExpectedFailure bool
+}
+
+// Checks if the run had the expected outcome
+func (r CorpusProgramResult) Passed() bool {
+	return (r.Err != nil) == r.ExpectedFailure
+}
+
+// Outcome of running a corpus, with the results of its programs sorted by path
+type CorpusResult struct {
+	Programs []CorpusProgramResult
+	Duration time.Duration
+}
+
+// Returns the results of the programs that didn't have the expected outcome
+func (r CorpusResult) Failed() []CorpusProgramResult {
+	var failed []CorpusProgramResult
+	for _, program := range r.Programs {
+		if !program.Passed() {
+			failed = append(failed, program)
+		}
+	}
+	return failed
+}
+
+// Checks if every program of the corpus had the expected outcome
+func (r CorpusResult) Passed() bool {
+	return len(r.Failed()) == 0
+}
+
+// Runs every compiled program of the filesystem (ie: an embed.FS or an os.DirFS), so that a suite of programs
+// can be verified against this vm programmatically
+// Programs that can't be loaded or whose run fails are reported in their result, the error is only set if the
+// filesystem can't be walked
+// Runners are dropped as soon as their run is over, so that large corpora don't hold every vm in memory
+func RunCorpus(fsys fs.FS, opts CorpusOptions) (CorpusResult, error) {
+	match := opts.Match
+	if match == nil {
+		match = func(filePath string) bool { return path.Ext(filePath) == ".json" }
+	}
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.IsDir() && match(filePath) {
+			paths = append(paths, filePath)
+		}
+		return nil
+	})
+	if err != nil {
+		return CorpusResult{}, err
+	}
+
+	start := time.Now()
+	results := make([]CorpusProgramResult, len(paths))
+	workers := parallel.Workers(opts.Parallelism)
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				programStart := time.Now()
+				results[idx] = runCorpusProgram(fsys, paths[idx], opts)
+				results[idx].Duration = time.Since(programStart)
+			}
+		}()
+	}
+	// WalkDir visits the files in lexical order, so the results are sorted by path
+	for idx := range paths {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+	return CorpusResult{Programs: results, Duration: time.Since(start)}, nil
+}
+
+func runCorpusProgram(fsys fs.FS, programPath string, opts CorpusOptions) CorpusProgramResult {
+	result := CorpusProgramResult{Path: programPath, ExpectedFailure: opts.ExpectFailure != nil && opts.ExpectFailure(programPath)}
+	programJson, err := fs.ReadFile(fsys, programPath)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	compiledProgram, err := parser.ParseBytes(programJson)
+	if err != nil {
+		result.Err = CairoRunError(err)
+		return result
+	}
+	programResult := runProgramSpec(ProgramSpec{Name: programPath, Program: vm.DeserializeProgramJson(compiledProgram), Options: opts.Options})
+	if programResult.Runner != nil {
+		result.Steps = programResult.Runner.Vm.CurrentStep
+	}
+	result.Err = programResult.Err
+	return result
+}
@@ -0,0 +1,77 @@
+package cairo_run_test
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+)
+
+// Builds a compiled program whose main function is made of the given instructions
+func corpusProgram(data ...string) *fstest.MapFile {
+	return &fstest.MapFile{Data: []byte(`{
+		"data": ["` + strings.Join(data, `", "`) + `"],
+		"identifiers": {"__main__.main": {"pc": 0, "type": "function"}},
+		"hints": {},
+		"reference_manager": {"references": []}
+	}`)}
+}
+
+func TestRunCorpus(t *testing.T) {
+	corpus := fstest.MapFS{
+		// [ap] = 5, ap++; [ap] = 6, ap++; ret
+		"ok.json": corpusProgram("0x480680017fff8000", "0x5", "0x480680017fff8000", "0x6", "0x208b7fff7fff7ffe"),
+		// [ap] = 5; [ap] = 6; ret
+		"bad/overwrite.json": corpusProgram("0x400680017fff8000", "0x5", "0x400680017fff8000", "0x6", "0x208b7fff7fff7ffe"),
+		"bad/invalid.json":   &fstest.MapFile{Data: []byte("{")},
+		"README.md":          &fstest.MapFile{Data: []byte("not a program")},
+	}
+
+	result, err := cairo_run.RunCorpus(corpus, cairo_run.CorpusOptions{Parallelism: 2})
+	if err != nil {
+		t.Fatalf("RunCorpus failed with error: %s", err)
+	}
+	paths := make([]string, 0, len(result.Programs))
+	for _, program := range result.Programs {
+		paths = append(paths, program.Path)
+	}
+	if strings.Join(paths, ",") != "bad/invalid.json,bad/overwrite.json,ok.json" {
+		t.Fatalf("Wrong programs run: %v", paths)
+	}
+	if result.Passed() || len(result.Failed()) != 2 {
+		t.Errorf("Expected the 2 bad programs to fail, failed: %v", result.Failed())
+	}
+	if ok := result.Programs[2]; ok.Err != nil || ok.Steps != 3 {
+		t.Errorf("ok.json: expected 3 steps & no error, got %d steps & %v", ok.Steps, ok.Err)
+	}
+	if overwrite := result.Programs[1]; overwrite.Err == nil || overwrite.Steps == 0 {
+		t.Errorf("bad/overwrite.json should fail while running, got %d steps & %v", overwrite.Steps, overwrite.Err)
+	}
+
+	// Expecting the bad programs to fail makes the corpus pass
+	result, err = cairo_run.RunCorpus(corpus, cairo_run.CorpusOptions{
+		ExpectFailure: func(path string) bool { return strings.HasPrefix(path, "bad/") },
+	})
+	if err != nil || !result.Passed() {
+		t.Errorf("Expected the corpus to pass, failed: %v, %v", result.Failed(), err)
+	}
+
+	// Only the selected programs are run
+	result, err = cairo_run.RunCorpus(corpus, cairo_run.CorpusOptions{
+		Match: func(path string) bool { return path == "ok.json" },
+	})
+	if err != nil || len(result.Programs) != 1 || !result.Passed() {
+		t.Errorf("Expected only ok.json to be run, got: %v, %v", result.Programs, err)
+	}
+}
+
+func TestRunCorpusRunOptions(t *testing.T) {
+	corpus := fstest.MapFS{
+		"ok.json": corpusProgram("0x480680017fff8000", "0x5", "0x480680017fff8000", "0x6", "0x208b7fff7fff7ffe"),
+	}
+	result, err := cairo_run.RunCorpus(corpus, cairo_run.CorpusOptions{Options: []cairo_run.Option{cairo_run.WithMaxSteps(1)}})
+	if err != nil || result.Passed() {
+		t.Errorf("The run should have been limited to 1 step: %v, %v", result.Programs, err)
+	}
+}
@@ -0,0 +1,138 @@
+package cairo_run_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+)
+
+// Differential tests: every compiled program in cairo_programs is run both by this vm and by the Rust cairo-vm-cli,
+// and the relocated trace & memory of both runs are compared byte by byte
+// They only run when CAIRO_VM_CLI points to a cairo-vm-cli binary (see `make differential_test`)
+
+const traceEntrySize = 3 * 8
+
+func TestDifferentialAgainstRustVm(t *testing.T) {
+	testDifferential(t, "../../../cairo_programs", false)
+}
+
+func TestDifferentialAgainstRustVmProofMode(t *testing.T) {
+	testDifferential(t, "../../../cairo_programs/proof_programs", true)
+}
+
+func testDifferential(t *testing.T, programsDir string, proofMode bool) {
+	cli := os.Getenv("CAIRO_VM_CLI")
+	if cli == "" {
+		t.Skip("CAIRO_VM_CLI is not set")
+	}
+	programs, err := filepath.Glob(filepath.Join(programsDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) == 0 {
+		t.Skipf("No compiled programs in %s", programsDir)
+	}
+	for _, program := range programs {
+		program := program
+		t.Run(strings.TrimSuffix(filepath.Base(program), ".json"), func(t *testing.T) {
+			t.Parallel()
+			goTrace, goMemory, goErr := runGoVm(program, proofMode)
+			rsTrace, rsMemory, rsErr := runRustVm(cli, program, proofMode, t.TempDir())
+			if goErr != nil || rsErr != nil {
+				if goErr == nil || rsErr == nil {
+					t.Fatalf("Only one of the vms failed.\nGo error: %v\nRust error: %v", goErr, rsErr)
+				}
+				// Both vms rejected the program, there is nothing to compare
+				return
+			}
+			if err := compareTraces(goTrace, rsTrace); err != nil {
+				t.Error(err)
+			}
+			if err := compareMemories(goMemory, rsMemory); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func runGoVm(program string, proofMode bool) ([]byte, []byte, error) {
+	cairoRunConfig := cairo_run.CairoRunConfig{Layout: "all_cairo", ProofMode: proofMode, SecureRun: !proofMode}
+	runner, err := cairo_run.CairoRun(program, cairoRunConfig)
+	if err != nil {
+		return nil, nil, err
+	}
+	var trace, memory bytes.Buffer
+	if err := cairo_run.WriteVmEncodedTrace(&runner.Vm, &trace); err != nil {
+		return nil, nil, err
+	}
+	if err := cairo_run.WriteEncodedMemory(runner.Vm.RelocatedMemory, &memory); err != nil {
+		return nil, nil, err
+	}
+	return trace.Bytes(), memory.Bytes(), nil
+}
+
+func runRustVm(cli string, program string, proofMode bool, outputDir string) ([]byte, []byte, error) {
+	tracePath := filepath.Join(outputDir, "rs.trace")
+	memoryPath := filepath.Join(outputDir, "rs.memory")
+	args := []string{"--layout", "all_cairo", program, "--trace_file", tracePath, "--memory_file", memoryPath}
+	if proofMode {
+		args = append(args, "--proof_mode")
+	}
+	output, err := exec.Command(cli, args...).CombinedOutput()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %s", err, output)
+	}
+	trace, err := os.ReadFile(tracePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	memory, err := os.ReadFile(memoryPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return trace, memory, nil
+}
+
+// Compares the encoded traces of both vms, reporting the first step at which they diverge
+func compareTraces(goTrace []byte, rsTrace []byte) error {
+	if bytes.Equal(goTrace, rsTrace) {
+		return nil
+	}
+	goEntries, err := cairo_run.ReadEncodedTrace(bytes.NewReader(goTrace))
+	if err != nil {
+		return err
+	}
+	rsEntries, err := cairo_run.ReadEncodedTrace(bytes.NewReader(rsTrace))
+	if err != nil {
+		return err
+	}
+	if diff := cairo_run.CompareTrace(goEntries, rsEntries); diff != nil {
+		return fmt.Errorf("Go | Rust\n%s", diff)
+	}
+	return fmt.Errorf("Traces differ in their encoding")
+}
+
+// Compares the encoded memories of both vms, reporting the first address at which they diverge
+func compareMemories(goMemory []byte, rsMemory []byte) error {
+	if bytes.Equal(goMemory, rsMemory) {
+		return nil
+	}
+	goCells, err := cairo_run.ReadEncodedMemory(bytes.NewReader(goMemory))
+	if err != nil {
+		return err
+	}
+	rsCells, err := cairo_run.ReadEncodedMemory(bytes.NewReader(rsMemory))
+	if err != nil {
+		return err
+	}
+	if diff := cairo_run.CompareMemory(goCells, rsCells); diff != nil {
+		return fmt.Errorf("Go | Rust\n%s", diff)
+	}
+	return fmt.Errorf("Memories differ in their encoding")
+}
@@ -0,0 +1,81 @@
+package cairo_run
+
+import (
+	"encoding/binary"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/pkg/errors"
+)
+
+// Layout of the binary trace & memory files
+type EncodingVersion uint
+
+const (
+	// Layout used by the Rust vm (bincode): 3 u64 words (ap, fp, pc) per trace entry,
+	// a u64 address followed by a 32-byte felt per memory cell
+	EncodingV1 EncodingVersion = 1
+	// Latest supported layout, used when the version is left unset
+	LatestEncodingVersion = EncodingV1
+)
+
+var ErrUnsupportedEncoding = errors.New("Unsupported encoding")
+
+// Options of the binary trace & memory encoding, for provers expecting a layout other than the default one
+// The zero value is the default encoding: the latest version in little endian
+type EncodingOptions struct {
+	Version EncodingVersion
+	// Byte order of both the u64 words & the felts, little endian if nil
+	ByteOrder binary.ByteOrder
+}
+
+// Encoding expected by the Rust vm & the Stone prover
+func DefaultEncodingOptions() EncodingOptions {
+	return EncodingOptions{Version: LatestEncodingVersion, ByteOrder: binary.LittleEndian}
+}
+
+// Returns the options with their unset fields replaced by the defaults, or an error if the version isn't supported
+func (o EncodingOptions) normalize() (EncodingOptions, error) {
+	if o.Version == 0 {
+		o.Version = LatestEncodingVersion
+	}
+	if o.Version != EncodingV1 {
+		return o, errors.Wrapf(ErrUnsupportedEncoding, "version %d", o.Version)
+	}
+	if o.ByteOrder == nil {
+		o.ByteOrder = binary.LittleEndian
+	}
+	return o, nil
+}
+
+// Parses a byte order name ("le", "little", "be" or "big") into encoding options of the latest version
+func ParseEncodingOptions(byteOrder string) (EncodingOptions, error) {
+	options := DefaultEncodingOptions()
+	switch byteOrder {
+	case "", "le", "little":
+	case "be", "big":
+		options.ByteOrder = binary.BigEndian
+	default:
+		return options, errors.Wrapf(ErrUnsupportedEncoding, "unknown byte order %s", byteOrder)
+	}
+	return options, nil
+}
+
+func (o EncodingOptions) feltBytes(felt lambdaworks.Felt) *[32]byte {
+	if o.ByteOrder == binary.BigEndian {
+		return felt.ToBeBytes()
+	}
+	return felt.ToLeBytes()
+}
+
+// Returns the felt's bytes in big endian order, as expected by FeltFromBeBytes
+func (o EncodingOptions) feltBeBytes(encoded []byte) [32]byte {
+	var value [32]byte
+	if o.ByteOrder == binary.BigEndian {
+		copy(value[:], encoded)
+		return value
+	}
+	for i := range value {
+		value[i] = encoded[len(value)-1-i]
+	}
+	return value
+}
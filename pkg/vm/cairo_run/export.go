@@ -0,0 +1,137 @@
+package cairo_run
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parquet"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Formats the relocated trace & memory can be exported to, to be studied with data-analysis tools (ie: pandas, DuckDB)
+type ExportFormat string
+
+const (
+	ExportCsv     ExportFormat = "csv"
+	ExportParquet ExportFormat = "parquet"
+)
+
+var ErrUnsupportedExportFormat = errors.New("Unsupported export format")
+
+// Parses a format name, one of: csv, parquet
+func ParseExportFormat(name string) (ExportFormat, error) {
+	switch format := ExportFormat(name); format {
+	case ExportCsv, ExportParquet:
+		return format, nil
+	}
+	return "", errors.Wrapf(ErrUnsupportedExportFormat, "%s, expected one of: csv, parquet", name)
+}
+
+// Returns the format matching the extension of the path (.csv or .parquet)
+func ExportFormatFromPath(path string) (ExportFormat, error) {
+	ext := filepath.Ext(path)
+	if ext == "" {
+		return "", errors.Wrapf(ErrUnsupportedExportFormat, "%s has no extension, expected .csv or .parquet", path)
+	}
+	return ParseExportFormat(ext[1:])
+}
+
+// Writes the relocated trace as a table of step, pc, ap & fp
+// Streamed traces are supported, they are relocated while being written
+func ExportTrace(virtualMachine *vm.VirtualMachine, dest io.Writer, format ExportFormat) error {
+	table, err := newExportTable(dest, format, []parquet.Column{
+		{Name: "step", Type: parquet.Int64},
+		{Name: "pc", Type: parquet.Int64},
+		{Name: "ap", Type: parquet.Int64},
+		{Name: "fp", Type: parquet.Int64},
+	})
+	if err != nil {
+		return err
+	}
+	var step int64
+	err = virtualMachine.ForEachRelocatedTraceEntry(func(entry vm.RelocatedTraceEntry) error {
+		row := []any{step}
+		for _, register := range []lambdaworks.Felt{entry.Pc, entry.Ap, entry.Fp} {
+			value, err := register.ToU64()
+			if err != nil {
+				return encodeTraceError(int(step), err)
+			}
+			row = append(row, int64(value))
+		}
+		step++
+		return table.WriteRow(row...)
+	})
+	if err != nil {
+		return err
+	}
+	return table.Close()
+}
+
+// Writes the written cells of the relocated memory as a table of address & value, by ascending address
+// Values are written as 0x prefixed hex strings, as felts don't fit in the integer types of the formats
+func ExportMemory(relocatedMemory memory.RelocatedMemory, dest io.Writer, format ExportFormat) error {
+	table, err := newExportTable(dest, format, []parquet.Column{
+		{Name: "address", Type: parquet.Int64},
+		{Name: "value", Type: parquet.String},
+	})
+	if err != nil {
+		return err
+	}
+	err = relocatedMemory.ForEach(func(addr uint, value lambdaworks.Felt) error {
+		return table.WriteRow(int64(addr), value.ToHexString())
+	})
+	if err != nil {
+		return err
+	}
+	return table.Close()
+}
+
+// Writer of the rows of an exported table, Close writes what is still buffered
+type exportTable interface {
+	WriteRow(values ...any) error
+	Close() error
+}
+
+func newExportTable(dest io.Writer, format ExportFormat, columns []parquet.Column) (exportTable, error) {
+	switch format {
+	case ExportParquet:
+		return parquet.NewWriter(dest, columns...), nil
+	case ExportCsv:
+		header := make([]string, 0, len(columns))
+		for _, column := range columns {
+			header = append(header, column.Name)
+		}
+		table := &csvTable{writer: csv.NewWriter(dest)}
+		return table, table.writer.Write(header)
+	}
+	return nil, errors.Wrapf(ErrUnsupportedExportFormat, "%s", format)
+}
+
+type csvTable struct {
+	writer *csv.Writer
+	record []string
+}
+
+func (t *csvTable) WriteRow(values ...any) error {
+	t.record = t.record[:0]
+	for _, value := range values {
+		switch value := value.(type) {
+		case int64:
+			t.record = append(t.record, strconv.FormatInt(value, 10))
+		default:
+			t.record = append(t.record, fmt.Sprint(value))
+		}
+	}
+	return t.writer.Write(t.record)
+}
+
+func (t *csvTable) Close() error {
+	t.writer.Flush()
+	return t.writer.Error()
+}
@@ -0,0 +1,65 @@
+package cairo_run_test
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+func TestExportTraceCsv(t *testing.T) {
+	// main:  [ap] = 5, ap++; ret
+	program := vmtest.Program("0x480680017fff8000", "5", "0x208b7fff7fff7ffe")
+	for _, streamTrace := range []bool{false, true} {
+		runner, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "plain", StreamTrace: streamTrace})
+		if err != nil {
+			t.Fatalf("Program execution failed with error: %s", err)
+		}
+		var result bytes.Buffer
+		if err := cairo_run.ExportTrace(&runner.Vm, &result, cairo_run.ExportCsv); err != nil {
+			t.Fatalf("ExportTrace failed with error: %s", err)
+		}
+		expected := "step,pc,ap,fp\n0,1,6,6\n1,3,7,6\n"
+		if result.String() != expected {
+			t.Errorf("Wrong exported trace (streamed: %t).\nExpected:\n%s\nGot:\n%s", streamTrace, expected, result.String())
+		}
+		if streamTrace {
+			runner.Vm.StreamedTrace.Close()
+		}
+	}
+}
+
+func TestExportMemory(t *testing.T) {
+	relocatedMemory := memory.RelocatedMemoryFromMap(map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(7), 4: lambdaworks.FeltFromDecString("-1")})
+	var result bytes.Buffer
+	if err := cairo_run.ExportMemory(relocatedMemory, &result, cairo_run.ExportCsv); err != nil {
+		t.Fatalf("ExportMemory failed with error: %s", err)
+	}
+	expected := "address,value\n1,0x7\n4,0x800000000000011000000000000000000000000000000000000000000000000\n"
+	if result.String() != expected {
+		t.Errorf("Wrong exported memory.\nExpected:\n%s\nGot:\n%s", expected, result.String())
+	}
+
+	result.Reset()
+	if err := cairo_run.ExportMemory(relocatedMemory, &result, cairo_run.ExportParquet); err != nil {
+		t.Fatalf("ExportMemory failed with error: %s", err)
+	}
+	if !bytes.HasPrefix(result.Bytes(), []byte("PAR1")) || !bytes.HasSuffix(result.Bytes(), []byte("PAR1")) {
+		t.Errorf("The memory should have been written as a parquet file")
+	}
+}
+
+func TestExportFormatFromPath(t *testing.T) {
+	if format, err := cairo_run.ExportFormatFromPath("out/trace.parquet"); err != nil || format != cairo_run.ExportParquet {
+		t.Errorf("Wrong format: %s, %v", format, err)
+	}
+	for _, path := range []string{"trace", "trace.json"} {
+		if _, err := cairo_run.ExportFormatFromPath(path); !errors.Is(err, cairo_run.ErrUnsupportedExportFormat) {
+			t.Errorf("Expected ErrUnsupportedExportFormat for %s, got %v", path, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package cairo_run
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/pkg/errors"
+)
+
+// Names of the files written by BuildProverInputs
+const (
+	ProverTraceFileName        = "trace.bin"
+	ProverMemoryFileName       = "memory.bin"
+	ProverPublicInputFileName  = "air_public_input.json"
+	ProverPrivateInputFileName = "air_private_input.json"
+)
+
+// Writes the inputs of the prover for a finished proof mode run to dir, which is created if needed:
+// the relocated trace & memory (ProverTraceFileName, ProverMemoryFileName) and the air public & private inputs
+// (ProverPublicInputFileName, ProverPrivateInputFileName), whose trace & memory paths point to the written files
+// The run is relocated (and its segments finalized) first if that wasn't done yet
+func BuildProverInputs(runner *runners.CairoRunner, dir string) error {
+	if !runner.ProofMode {
+		return runners.ErrAirInputsNoProofMode
+	}
+	if !runner.RunEnded {
+		return CairoRunError(errors.New("The run has to end before building the prover inputs"))
+	}
+	if err := runner.FinalizeSegments(); err != nil {
+		return err
+	}
+	if _, err := runner.Vm.GetRelocationTable(); err != nil {
+		if err := runner.Relocate(); err != nil {
+			return err
+		}
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	tracePath := filepath.Join(dir, ProverTraceFileName)
+	memoryPath := filepath.Join(dir, ProverMemoryFileName)
+
+	err = writeProverFile(tracePath, func(w io.Writer) error {
+		return WriteVmEncodedTrace(&runner.Vm, w)
+	})
+	if err != nil {
+		return err
+	}
+	err = writeProverFile(memoryPath, func(w io.Writer) error {
+		return WriteEncodedMemory(runner.Vm.RelocatedMemory, w)
+	})
+	if err != nil {
+		return err
+	}
+
+	publicInput, err := runner.GetAirPublicInput()
+	if err != nil {
+		return err
+	}
+	err = writeProverFile(filepath.Join(dir, ProverPublicInputFileName), func(w io.Writer) error {
+		return writeIndentedJson(w, publicInput)
+	})
+	if err != nil {
+		return err
+	}
+	privateInput, err := runner.GetAirPrivateInput(tracePath, memoryPath)
+	if err != nil {
+		return err
+	}
+	return writeProverFile(filepath.Join(dir, ProverPrivateInputFileName), func(w io.Writer) error {
+		return writeIndentedJson(w, privateInput)
+	})
+}
+
+// Writes the prover inputs of the run to dir, see BuildProverInputs
+func (r *Runner) BuildProverInputs(dir string) error {
+	return BuildProverInputs(r.CairoRunner, dir)
+}
+
+func writeProverFile(path string, write func(w io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	writer := bufio.NewWriter(file)
+	if err := write(writer); err != nil {
+		return err
+	}
+	if err := writer.Flush(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+func writeIndentedJson(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
@@ -0,0 +1,264 @@
+package cairo_run_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+// __start__: call main
+// __end__: jmp rel 0
+// main: ret
+func proofModeProgram() vm.Program {
+	return vmtest.ProofModeProgram(4, 2,
+		"0x1104800180018000", "4",
+		"0x10780017fff7fff", "0",
+		"0x208b7fff7fff7ffe",
+	)
+}
+
+func TestBuildProverInputs(t *testing.T) {
+	runner, err := cairo_run.NewRunner(proofModeProgram(), cairo_run.WithProofMode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	dir := filepath.Join(t.TempDir(), "prover")
+	if err := runner.BuildProverInputs(dir); err != nil {
+		t.Fatalf("BuildProverInputs failed with error: %s", err)
+	}
+
+	for _, name := range []string{cairo_run.ProverTraceFileName, cairo_run.ProverMemoryFileName} {
+		info, err := os.Stat(filepath.Join(dir, name))
+		if err != nil || info.Size() == 0 {
+			t.Errorf("Expected a non empty %s, got: %v", name, err)
+		}
+	}
+
+	var publicInput runners.AirPublicInput
+	data, err := os.ReadFile(filepath.Join(dir, cairo_run.ProverPublicInputFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &publicInput); err != nil {
+		t.Fatal(err)
+	}
+	if publicInput.Layout != "plain" || publicInput.NSteps != int(runner.Vm.CurrentStep) {
+		t.Errorf("Wrong public input, got layout %s and %d steps", publicInput.Layout, publicInput.NSteps)
+	}
+	if _, ok := publicInput.MemorySegments["output"]; !ok {
+		t.Error("Expected the output segment in the public input")
+	}
+	if len(publicInput.PublicMemory) == 0 {
+		t.Error("Expected the program to be part of the public memory")
+	}
+
+	var privateInput map[string]any
+	data, err = os.ReadFile(filepath.Join(dir, cairo_run.ProverPrivateInputFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &privateInput); err != nil {
+		t.Fatal(err)
+	}
+	if privateInput["trace_path"] != filepath.Join(dir, cairo_run.ProverTraceFileName) ||
+		privateInput["memory_path"] != filepath.Join(dir, cairo_run.ProverMemoryFileName) {
+		t.Errorf("Wrong trace & memory paths in the private input: %v", privateInput)
+	}
+}
+
+func TestBuildProverInputsNoProofMode(t *testing.T) {
+	runner, err := cairo_run.NewRunner(countdownProgram())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	if err := runner.BuildProverInputs(t.TempDir()); !errors.Is(err, runners.ErrAirInputsNoProofMode) {
+		t.Errorf("Expected ErrAirInputsNoProofMode, got: %v", err)
+	}
+}
+
+// __start__: call main
+// __end__: jmp rel 0
+// main: asserts that fib(1, 1, 10) == 144
+// fib(first_element, second_element, n): returns second_element if n == 0, else fib(second_element, first_element + second_element, n - 1)
+func proofModeFibonacciProgram() vm.Program {
+	return vmtest.ProofModeProgram(4, 2,
+		// __start__
+		"0x1104800180018000", "4",
+		"0x10780017fff7fff", "0",
+		// main
+		"0x480680017fff8000", "1",
+		"0x480680017fff8000", "1",
+		"0x480680017fff8000", "10",
+		"0x1104800180018000", "5",
+		"0x400680017fff7fff", "144",
+		"0x208b7fff7fff7ffe",
+		// fib
+		"0x20780017fff7ffd", "4",
+		"0x480a7ffc7fff8000",
+		"0x208b7fff7fff7ffe",
+		// fib_body
+		"0x482a7ffc7ffb8000",
+		"0x480a7ffc7fff8000",
+		"0x48127ffe7fff8000",
+		"0x482680017ffd8000", "-1",
+		"0x1104800180018000", "-9",
+		"0x208b7fff7fff7ffe",
+	)
+}
+
+// __start__: ap += 1; call main
+// __end__: jmp rel 0
+// main(output_ptr): writes 7 to output_ptr[1], leaving output_ptr[0] as a hole, & returns output_ptr + 2
+func proofModeOutputHoleProgram() vm.Program {
+	program := vmtest.ProofModeProgram(6, 4,
+		// __start__
+		"0x40780017fff7fff", "1",
+		"0x1104800180018000", "4",
+		"0x10780017fff7fff", "0",
+		// main
+		"0x480680017fff8000", "7",
+		"0x400280017ffd7fff",
+		"0x482680017ffd8000", "2",
+		"0x208b7fff7fff7ffe",
+	)
+	program.Builtins = []string{"output"}
+	return program
+}
+
+// Builds the prover inputs of a proof mode run of program & returns its public input
+func buildPublicInput(t *testing.T, program vm.Program) (*cairo_run.Runner, runners.AirPublicInput) {
+	t.Helper()
+	runner, err := cairo_run.NewRunner(program, cairo_run.WithProofMode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	dir := t.TempDir()
+	if err := runner.BuildProverInputs(dir); err != nil {
+		t.Fatalf("BuildProverInputs failed with error: %s", err)
+	}
+	var publicInput runners.AirPublicInput
+	data, err := os.ReadFile(filepath.Join(dir, cairo_run.ProverPublicInputFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &publicInput); err != nil {
+		t.Fatal(err)
+	}
+	return runner, publicInput
+}
+
+func TestBuildProverInputsFibonacci(t *testing.T) {
+	program := proofModeFibonacciProgram()
+	runner, publicInput := buildPublicInput(t, program)
+
+	// The program is public, followed by the initial stack of the execution segment
+	if len(publicInput.PublicMemory) < len(program.Data) {
+		t.Fatalf("Expected the program to be part of the public memory, got %d entries", len(publicInput.PublicMemory))
+	}
+	for i, entry := range publicInput.PublicMemory {
+		if entry.Value == nil {
+			t.Errorf("Unexpected memory hole at %d", entry.Address)
+			continue
+		}
+		if i < len(program.Data) {
+			expected, _ := program.Data[i].GetFelt()
+			if *entry.Value != expected.ToHexString() || entry.Address != uint(i+1) {
+				t.Errorf("Wrong public memory entry for the program cell %d: %+v", i, entry)
+			}
+		}
+	}
+	if publicInput.NSteps != int(runner.Vm.CurrentStep) || publicInput.NSteps&(publicInput.NSteps-1) != 0 {
+		t.Errorf("Expected the steps to be padded to a power of 2, got %d", publicInput.NSteps)
+	}
+	// The only hole is the return-fp of the initial stack, as __end__ loops instead of returning
+	holes, err := runner.GetMemoryHoles()
+	if err != nil || holes != 1 {
+		t.Errorf("Expected a single memory hole, got %d, %v", holes, err)
+	}
+}
+
+func TestBuildProverInputsOutputHole(t *testing.T) {
+	runner, publicInput := buildPublicInput(t, proofModeOutputHoleProgram())
+
+	output, ok := publicInput.MemorySegments["output"]
+	if !ok || output.StopPtr-output.BeginAddr != 2 {
+		t.Fatalf("Expected an output segment of 2 cells, got %+v", publicInput.MemorySegments)
+	}
+	entries := make(map[uint]*string)
+	for _, entry := range publicInput.PublicMemory {
+		entries[entry.Address] = entry.Value
+	}
+	hole, ok := entries[output.BeginAddr]
+	if !ok || hole != nil {
+		t.Errorf("Expected the skipped output cell to be recorded as a hole, got %v", hole)
+	}
+	value, ok := entries[output.BeginAddr+1]
+	if !ok || value == nil || *value != "0x7" {
+		t.Errorf("Expected the written output cell to be public, got %v", value)
+	}
+	// Builtin segments, output included, are not counted: the only hole is the return-fp of the initial stack
+	holes, err := runner.GetMemoryHoles()
+	if err != nil || holes != 1 {
+		t.Errorf("Expected a single memory hole, got %d, %v", holes, err)
+	}
+}
+
+func TestBuildProverInputsOutputPages(t *testing.T) {
+	runner, err := cairo_run.NewRunner(proofModeOutputHoleProgram(), cairo_run.WithProofMode())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	builtin, err := runner.Vm.GetBuiltinRunner(builtins.OUTPUT_BUILTIN_NAME)
+	if err != nil {
+		t.Fatal(err)
+	}
+	output := (*builtin).(*builtins.OutputBuiltinRunner)
+	output.AddPage(1, 1, 1)
+	output.AddAttribute(builtins.GPS_FACT_TOPOLOGY, []uint{1, 0})
+	if topology, err := output.GetFactTopology(); err != nil || len(topology.PageSizes) != 2 || topology.PageSizes[0] != 1 {
+		t.Errorf("Wrong fact topology: %v, %v", topology, err)
+	}
+
+	dir := t.TempDir()
+	if err := runner.BuildProverInputs(dir); err != nil {
+		t.Fatalf("BuildProverInputs failed with error: %s", err)
+	}
+	var publicInput runners.AirPublicInput
+	data, err := os.ReadFile(filepath.Join(dir, cairo_run.ProverPublicInputFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &publicInput); err != nil {
+		t.Fatal(err)
+	}
+	outputBegin := publicInput.MemorySegments["output"].BeginAddr
+	for _, entry := range publicInput.PublicMemory {
+		expectedPage := uint(0)
+		if entry.Address == outputBegin+1 {
+			expectedPage = 1
+		}
+		if entry.Page != expectedPage {
+			t.Errorf("Expected the cell %d to be in page %d, got %d", entry.Address, expectedPage, entry.Page)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cairo_run
+
+import (
+	"sync"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/parallel"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+)
+
+// A program to be run by RunMany, along with the options of its run
+// The options must not share mutable values (ie: hint processors, builtin runners or step observers)
+// with the options of other programs, as they are used concurrently
+type ProgramSpec struct {
+	// Identifies the program in its result (ie: its path)
+	Name    string
+	Program vm.Program
+	Options []Option
+}
+
+// Outcome of running a ProgramSpec
+type ProgramResult struct {
+	Name string
+	// Nil if the runner couldn't be created
+	Runner *Runner
+	Err    error
+}
+
+// Runs each program on its own runner, running up to parallelism programs at a time
+// A non-positive parallelism runs one program per available cpu
+// Runs don't share any memory, execution scopes or hint processor state, so the same vm.Program
+// can be used by several specs
+// The results are returned in the same order as the programs
+func RunMany(programs []ProgramSpec, parallelism int) []ProgramResult {
+	results := make([]ProgramResult, len(programs))
+	workers := parallel.Workers(parallelism)
+	if workers > len(programs) {
+		workers = len(programs)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = runProgramSpec(programs[idx])
+			}
+		}()
+	}
+	for idx := range programs {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func runProgramSpec(spec ProgramSpec) ProgramResult {
+	runner, err := NewRunner(spec.Program, spec.Options...)
+	if err != nil {
+		return ProgramResult{Name: spec.Name, Err: err}
+	}
+	return ProgramResult{Name: spec.Name, Runner: runner, Err: runner.Run()}
+}
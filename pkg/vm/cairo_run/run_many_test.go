@@ -0,0 +1,69 @@
+package cairo_run_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+)
+
+// These tests are meant to be run with the race detector enabled (make test_race)
+
+func TestRunManySharedProgram(t *testing.T) {
+	program := countdownProgram()
+	specs := make([]cairo_run.ProgramSpec, 0, 16)
+	for i := 0; i < 16; i++ {
+		specs = append(specs, cairo_run.ProgramSpec{Name: fmt.Sprintf("countdown_%d", i), Program: program})
+	}
+
+	results := cairo_run.RunMany(specs, 4)
+	if len(results) != len(specs) {
+		t.Fatalf("Expected %d results, got %d", len(specs), len(results))
+	}
+	for i, result := range results {
+		if result.Name != specs[i].Name {
+			t.Errorf("Result %d is out of order: %s", i, result.Name)
+		}
+		if result.Err != nil {
+			t.Errorf("%s failed with error: %s", result.Name, result.Err)
+			continue
+		}
+		if result.Runner.Vm.CurrentStep != 22 {
+			t.Errorf("%s: wrong number of steps, expected 22, got %d", result.Name, result.Runner.Vm.CurrentStep)
+		}
+		if result.Runner.Vm.RelocatedMemory.NumCells() != results[0].Runner.Vm.RelocatedMemory.NumCells() {
+			t.Errorf("%s: memory differs from the other runs", result.Name)
+		}
+	}
+}
+
+func TestRunManyIsolatesFailures(t *testing.T) {
+	specs := []cairo_run.ProgramSpec{
+		{Name: "ok", Program: countdownProgram()},
+		{Name: "max_steps", Program: countdownProgram(), Options: []cairo_run.Option{cairo_run.WithMaxSteps(10)}},
+		{Name: "bad_layout", Program: countdownProgram(), Options: []cairo_run.Option{cairo_run.WithLayout("unknown")}},
+		{Name: "small_layout", Program: countdownProgram(), Options: []cairo_run.Option{cairo_run.WithLayout("small"), cairo_run.WithSecureRun(true)}},
+	}
+
+	results := cairo_run.RunMany(specs, 0)
+	if results[0].Err != nil {
+		t.Errorf("ok failed with error: %s", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, runners.ErrUnfinishedExecution) {
+		t.Errorf("Expected ErrUnfinishedExecution, got: %v", results[1].Err)
+	}
+	if results[2].Err == nil || results[2].Runner != nil {
+		t.Errorf("Expected the runner creation to fail, got: %v", results[2].Err)
+	}
+	if results[3].Err != nil {
+		t.Errorf("small_layout failed with error: %s", results[3].Err)
+	}
+}
+
+func TestRunManyNoPrograms(t *testing.T) {
+	if results := cairo_run.RunMany(nil, 4); len(results) != 0 {
+		t.Errorf("Expected no results, got %d", len(results))
+	}
+}
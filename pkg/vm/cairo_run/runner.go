@@ -0,0 +1,277 @@
+package cairo_run
+
+import (
+	"context"
+	"time"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/compat"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/layouts"
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+)
+
+// Runner created through NewRunner, which holds the settings of the run alongside the CairoRunner
+type Runner struct {
+	*runners.CairoRunner
+	HintProcessor       vm.HintProcessor
+	SecureRun           bool
+	DisableTracePadding bool
+	// Set if the errors returned by Run have the messages of the Rust vm, see compat.Error
+	CompatErrors bool
+	// Stops the execution once done, see WithContext
+	Context context.Context
+	// Maximum duration of the execution, zero means there is no limit
+	Timeout time.Duration
+}
+
+// Option configures a Runner created through NewRunner
+type Option func(*runnerOptions)
+
+type runnerOptions struct {
+	layout              string
+	entrypoint          string
+	proofMode           bool
+	secureRun           *bool
+	disableTracePadding bool
+	hintProcessor       vm.HintProcessor
+	streamTrace         bool
+	compatErrors        bool
+	builtins            []builtins.BuiltinRunner
+	vmOptions           []vm.Option
+	hintLimits          hints.HintLimits
+	ctx                 context.Context
+	timeout             time.Duration
+}
+
+// Layout used by the run, defaults to plain
+func WithLayout(name string) Option {
+	return func(o *runnerOptions) {
+		o.layout = name
+	}
+}
+
+// Function of the __main__ module the run starts from, defaults to main
+func WithEntrypoint(name string) Option {
+	return func(o *runnerOptions) {
+		o.entrypoint = name
+	}
+}
+
+func WithProofMode() Option {
+	return func(o *runnerOptions) {
+		o.proofMode = true
+	}
+}
+
+// Whether the run is verified by runners.VerifySecureRunner once it's over, defaults to true unless in proof mode
+func WithSecureRun(secureRun bool) Option {
+	return func(o *runnerOptions) {
+		o.secureRun = &secureRun
+	}
+}
+
+func WithoutTracePadding() Option {
+	return func(o *runnerOptions) {
+		o.disableTracePadding = true
+	}
+}
+
+// Makes Run return errors with the messages the Rust vm emits for them (ie: for tooling that parses them)
+// The errors can still be matched through errors.Is & errors.As
+func WithCompatErrors() Option {
+	return func(o *runnerOptions) {
+		o.compatErrors = true
+	}
+}
+
+// Hint processor used to run the program's hints, defaults to hints.CairoVmHintProcessor
+func WithHintProcessor(hintProcessor vm.HintProcessor) Option {
+	return func(o *runnerOptions) {
+		o.hintProcessor = hintProcessor
+	}
+}
+
+// Streams the trace to a temporary file instead of holding it in memory
+// The caller is responsible for closing the runner's Vm.StreamedTrace
+func WithStreamedTrace() Option {
+	return func(o *runnerOptions) {
+		o.streamTrace = true
+	}
+}
+
+// Limits the number of steps of the run, reaching it fails the run with runners.ErrUnfinishedExecution
+func WithMaxSteps(maxSteps uint) Option {
+	return WithVmOptions(vm.WithMaxSteps(maxSteps))
+}
+
+// Limits the size of the relocated memory, exceeding it fails the relocation with vm.ErrMemoryLimitExceeded
+func WithMaxMemorySize(maxMemorySize uint) Option {
+	return WithVmOptions(vm.WithMaxMemorySize(maxMemorySize))
+}
+
+// Adds a builtin to the layout, replacing the layout's builtin with the same name if there is one
+// As with the layout's builtins, it is only used if the program lists it (or in proof mode)
+func WithBuiltin(builtin builtins.BuiltinRunner) Option {
+	return func(o *runnerOptions) {
+		o.builtins = append(o.builtins, builtin)
+	}
+}
+
+// Limits the input size of the hints that support it, see hints.HintLimits
+func WithHintLimits(limits hints.HintLimits) Option {
+	return func(o *runnerOptions) {
+		o.hintLimits = limits
+	}
+}
+
+// Stops the execution with runners.ErrRunCancelled once ctx is done (ie: cancelled by the caller)
+// The context is checked every runners.CONTEXT_CHECK_INTERVAL steps
+func WithContext(ctx context.Context) Option {
+	return func(o *runnerOptions) {
+		o.ctx = ctx
+	}
+}
+
+// Stops the execution with runners.ErrRunCancelled once it has run for longer than timeout
+// Only the execution is timed, not the finalization & relocation of the run
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *runnerOptions) {
+		o.timeout = timeout
+	}
+}
+
+// Options applied to the runner's vm
+func WithVmOptions(opts ...vm.Option) Option {
+	return func(o *runnerOptions) {
+		o.vmOptions = append(o.vmOptions, opts...)
+	}
+}
+
+// Creates a runner for the program, configured by the given options
+func NewRunner(program vm.Program, opts ...Option) (*Runner, error) {
+	options := runnerOptions{layout: "plain"}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	layout, err := layouts.NewLayout(options.layout)
+	if err != nil {
+		return nil, runners.RunnerError(err)
+	}
+	for _, builtin := range options.builtins {
+		layout.Builtins = withBuiltin(layout.Builtins, builtin)
+	}
+	cairoRunner, err := runners.NewCairoRunnerWithLayout(program, layout, options.proofMode)
+	if err != nil {
+		return nil, err
+	}
+	if options.entrypoint != "" {
+		if err := cairoRunner.SetEntrypoint(options.entrypoint); err != nil {
+			return nil, err
+		}
+	}
+
+	if options.streamTrace {
+		streamedTrace, err := vm.NewStreamedTrace("")
+		if err != nil {
+			return nil, err
+		}
+		options.vmOptions = append(options.vmOptions, vm.WithStreamedTrace(streamedTrace))
+	}
+	for _, opt := range options.vmOptions {
+		opt(&cairoRunner.Vm)
+	}
+	cairoRunner.AssignScopeVariables(options.hintLimits.ScopeVariables())
+
+	runner := Runner{
+		CairoRunner:         cairoRunner,
+		HintProcessor:       options.hintProcessor,
+		SecureRun:           !options.proofMode,
+		DisableTracePadding: options.disableTracePadding,
+		CompatErrors:        options.compatErrors,
+		Context:             options.ctx,
+		Timeout:             options.timeout,
+	}
+	if runner.HintProcessor == nil {
+		runner.HintProcessor = &hints.CairoVmHintProcessor{}
+	}
+	if options.secureRun != nil {
+		runner.SecureRun = *options.secureRun
+	}
+	return &runner, nil
+}
+
+func withBuiltin(layoutBuiltins []builtins.BuiltinRunner, builtin builtins.BuiltinRunner) []builtins.BuiltinRunner {
+	for i := range layoutBuiltins {
+		if layoutBuiltins[i].Name() == builtin.Name() {
+			layoutBuiltins[i] = builtin
+			return layoutBuiltins
+		}
+	}
+	return append(layoutBuiltins, builtin)
+}
+
+// Runs the program until its end, then finalizes & relocates the run
+func (r *Runner) Run() error {
+	err := r.run()
+	if err != nil && r.CompatErrors {
+		return &compat.Error{Err: err}
+	}
+	return err
+}
+
+func (r *Runner) run() error {
+	ctx, cancel := r.runContext()
+	defer cancel()
+	end, err := r.Initialize()
+	if err != nil {
+		return err
+	}
+	err = r.RunUntilPCContext(ctx, end, r.HintProcessor)
+	if err != nil {
+		return err
+	}
+	// Padding the trace of a proof mode run can nearly double the steps, so it is cancelled by ctx too
+	err = r.EndRunContext(ctx, r.DisableTracePadding, false, r.HintProcessor)
+	if err != nil {
+		return err
+	}
+
+	err = r.ReadReturnValues()
+	if err != nil {
+		return err
+	}
+
+	if r.ProofMode {
+		r.FinalizeSegments()
+	}
+
+	if r.SecureRun {
+		err = runners.VerifySecureRunner(r.CairoRunner, true, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = r.Relocate()
+	if err != nil {
+		return err
+	}
+	logging.Default().Info("Program run finished", logging.F("steps", r.Vm.CurrentStep))
+	return nil
+}
+
+// Returns the context the run is stopped by, which is done once the Timeout expires if there is one
+func (r *Runner) runContext() (context.Context, context.CancelFunc) {
+	ctx := r.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if r.Timeout != 0 {
+		return context.WithTimeout(ctx, r.Timeout)
+	}
+	return ctx, func() {}
+}
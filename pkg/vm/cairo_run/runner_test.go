@@ -0,0 +1,231 @@
+package cairo_run_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/hints"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/runners"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+// main: [ap] = 10, ap++
+// loop: [ap] = [ap - 1] - 1, ap++; jmp loop if [ap - 1] != 0
+// ret
+func countdownProgram() vm.Program {
+	return vmtest.Program(
+		"0x480680017fff8000", "10",
+		"0x482480017fff8000", "-1",
+		"0x20680017fff7fff", "-2",
+		"0x208b7fff7fff7ffe",
+	)
+}
+
+func TestNewRunnerRun(t *testing.T) {
+	runner, err := cairo_run.NewRunner(countdownProgram())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !runner.SecureRun {
+		t.Error("Runs should be secure by default")
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	if runner.Vm.CurrentStep != 22 {
+		t.Errorf("Wrong number of steps, expected 22, got %d", runner.Vm.CurrentStep)
+	}
+	if len(runner.Vm.RelocatedTrace) == 0 {
+		t.Error("Run was not relocated")
+	}
+}
+
+func TestNewRunnerMaxSteps(t *testing.T) {
+	runner, err := cairo_run.NewRunner(countdownProgram(), cairo_run.WithMaxSteps(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); !errors.Is(err, runners.ErrUnfinishedExecution) {
+		t.Errorf("Expected ErrUnfinishedExecution, got: %v", err)
+	}
+}
+
+func TestNewRunnerMaxMemorySize(t *testing.T) {
+	runner, err := cairo_run.NewRunner(countdownProgram(), cairo_run.WithMaxMemorySize(10))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); !errors.Is(err, vm.ErrMemoryLimitExceeded) {
+		t.Errorf("Expected ErrMemoryLimitExceeded, got: %v", err)
+	}
+
+	runner, err = cairo_run.NewRunner(countdownProgram(), cairo_run.WithMaxMemorySize(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Errorf("Program execution failed with error: %s", err)
+	}
+}
+
+func TestNewRunnerVmOptions(t *testing.T) {
+	steps := 0
+	hooks := vm.StepHooks{PostStep: func(*vm.VirtualMachine) error {
+		steps++
+		return nil
+	}}
+	runner, err := cairo_run.NewRunner(countdownProgram(), cairo_run.WithVmOptions(vm.WithHooks(hooks)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Program execution failed with error: %s", err)
+	}
+	if steps != 22 {
+		t.Errorf("Hook ran %d times, expected 22", steps)
+	}
+}
+
+func TestNewRunnerUnknownLayout(t *testing.T) {
+	_, err := cairo_run.NewRunner(countdownProgram(), cairo_run.WithLayout("unknown"))
+	if err == nil {
+		t.Error("NewRunner should have failed")
+	}
+}
+
+func TestNewRunnerCustomBuiltin(t *testing.T) {
+	program := countdownProgram()
+	program.Builtins = []string{builtins.POSEIDON_BUILTIN_NAME}
+	runner, err := cairo_run.NewRunner(program)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.Initialize(); err == nil {
+		t.Error("The plain layout has no poseidon builtin, Initialize should have failed")
+	}
+
+	runner, err = cairo_run.NewRunner(program, cairo_run.WithBuiltin(builtins.NewPoseidonBuiltinRunner(32)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if len(runner.Vm.BuiltinRunners) != 1 || runner.Vm.BuiltinRunners[0].Name() != builtins.POSEIDON_BUILTIN_NAME {
+		t.Errorf("Expected the poseidon builtin to be included, got: %v", runner.Vm.BuiltinRunners)
+	}
+}
+
+func TestNewRunnerEntrypoint(t *testing.T) {
+	program := countdownProgram()
+	program.Identifiers["__main__.countdown_end"] = vm.Identifier{Type: "label", PC: 6}
+	runner, err := cairo_run.NewRunner(program, cairo_run.WithEntrypoint("countdown_end"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runner.Initialize(); err != nil {
+		t.Fatalf("Initialize failed with error: %s", err)
+	}
+	if runner.InitialRegisters().GetPc() != memory.NewRelocatable(0, 6) {
+		t.Errorf("Wrong initial pc: %s", runner.InitialRegisters().GetPc())
+	}
+
+	if _, err := cairo_run.NewRunner(program, cairo_run.WithEntrypoint("missing")); !errors.Is(err, vm.ErrIdentifierNotFound) {
+		t.Errorf("Expected ErrIdentifierNotFound, got: %v", err)
+	}
+}
+
+func TestWithHintLimits(t *testing.T) {
+	runner, err := cairo_run.NewRunner(countdownProgram(), cairo_run.WithHintLimits(hints.HintLimits{UsortMaxSize: 5}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	scopes := runner.InspectExecutionScopes()
+	if len(scopes) != 1 || len(scopes[0]) != 1 || scopes[0][0].Name != hints.USORT_MAX_SIZE {
+		t.Errorf("Expected the limit in the main scope, got %v", scopes)
+	}
+}
+
+// main: ap += 1; jmp main
+// Never ends, while changing ap at each step so that it isn't detected as an infinite loop
+func endlessProgram() vm.Program {
+	return vmtest.Program(
+		"0x40780017fff7fff", "1",
+		"0x10780017fff7fff", "-2",
+	)
+}
+
+func TestWithTimeout(t *testing.T) {
+	runner, err := cairo_run.NewRunner(endlessProgram(), cairo_run.WithTimeout(20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("NewRunner failed with error: %s", err)
+	}
+	err = runner.Run()
+	if !errors.Is(err, runners.ErrRunCancelled) || !errors.Is(err, context.DeadlineExceeded) || !errors.Is(err, runners.ErrRunner) {
+		t.Errorf("Expected the run to time out, got %v", err)
+	}
+	if runner.Vm.CurrentStep == 0 {
+		t.Errorf("The program should have run until the timeout")
+	}
+}
+
+// __start__: call main
+// __end__: jmp rel 0, with a cancel() hint, which is only run while padding the trace
+// main: counts down from 1100
+func proofModeCountdownProgram() vm.Program {
+	program := vmtest.ProofModeProgram(4, 2,
+		"0x1104800180018000", "4",
+		"0x10780017fff7fff", "0",
+		"0x480680017fff8000", "1100",
+		"0x482480017fff8000", "-1",
+		"0x20680017fff7fff", "-2",
+		"0x208b7fff7fff7ffe",
+	)
+	program.Hints = map[uint][]parser.HintParams{2: {{Code: "cancel()"}}}
+	return program
+}
+
+func TestWithContextCancelledWhilePadding(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cancelHint := func(*hints.HintContext) error {
+		cancel()
+		return nil
+	}
+	hintProcessor := &hints.CairoVmHintProcessor{CustomHints: map[string]hints.CustomHint{"cancel()": cancelHint}}
+	runner, err := cairo_run.NewRunner(proofModeCountdownProgram(), cairo_run.WithProofMode(), cairo_run.WithContext(ctx), cairo_run.WithHintProcessor(hintProcessor))
+	if err != nil {
+		t.Fatalf("NewRunner failed with error: %s", err)
+	}
+	err = runner.Run()
+	if !errors.Is(err, runners.ErrRunCancelled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the padding to be cancelled, got %v", err)
+	}
+	if runner.RunEnded || runner.Vm.CurrentStep >= 4096 {
+		t.Errorf("The trace shouldn't be padded to the next power of 2, ran %d steps", runner.Vm.CurrentStep)
+	}
+}
+
+func TestWithContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	runner, err := cairo_run.CairoRunProgram(countdownProgram(), cairo_run.CairoRunConfig{Layout: "plain", Context: ctx})
+	if !errors.Is(err, runners.ErrRunCancelled) || !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected the run to be cancelled, got %v", err)
+	}
+	if runner.Vm.CurrentStep != 0 {
+		t.Errorf("A cancelled run shouldn't run any step, ran %d", runner.Vm.CurrentStep)
+	}
+
+	// A context that is not done doesn't change the run
+	if _, err := cairo_run.CairoRunProgram(countdownProgram(), cairo_run.CairoRunConfig{Layout: "plain", Context: context.Background(), Timeout: time.Minute}); err != nil {
+		t.Errorf("The run should have succeeded: %s", err)
+	}
+}
@@ -0,0 +1,117 @@
+package cairo_run
+
+import (
+	"fmt"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// The segment arena manages the segments of Cairo 1 dicts, it is made of consecutive headers, each one holding:
+//   - A pointer to its infos, one per segment allocated by the arena
+//   - The number of segments allocated by the arena
+//   - The number of segments finalized (ie: dicts squashed) so far
+//
+// Each info is a triple of the segment's start, its end & the index at which it was finalized, all of them are unset
+// until the segment is finalized
+const segmentArenaHeaderSize = 3
+const segmentInfoSize = 3
+
+var ErrInvalidSegmentArena = errors.New("Invalid segment arena")
+var ErrSegmentNotFinalized = errors.New("Segment arena segment not finalized")
+var ErrSegmentFinalizedTwice = errors.New("Segment arena segment finalized more than once")
+var ErrInvalidSegmentInfo = errors.New("Invalid segment arena info")
+
+func InvalidSegmentArenaError(arenaPtr memory.Relocatable, err error) error {
+	return fmt.Errorf("%w at %s: %w", ErrInvalidSegmentArena, arenaPtr.ToString(), err)
+}
+
+func SegmentNotFinalizedError(index uint) error {
+	return fmt.Errorf("%w: segment %d", ErrSegmentNotFinalized, index)
+}
+
+func SegmentFinalizedTwiceError(finalizeIndex uint, index uint, other uint) error {
+	return fmt.Errorf("%w: finalize index %d is shared by segments %d and %d", ErrSegmentFinalizedTwice, finalizeIndex, other, index)
+}
+
+func InvalidSegmentInfoError(index uint, reason string) error {
+	return fmt.Errorf("%w of segment %d: %s", ErrInvalidSegmentInfo, index, reason)
+}
+
+// Validates the segment arena at the end of a run, given the arena pointer returned by the entrypoint
+// Every segment allocated by the arena has to be finalized exactly once, with an info consistent with its segment
+func ValidateSegmentArena(segments *memory.MemorySegmentManager, arenaPtr memory.Relocatable) error {
+	if err := validateSegmentArena(&segments.Memory, arenaPtr); err != nil {
+		return InvalidSegmentArenaError(arenaPtr, err)
+	}
+	return nil
+}
+
+func validateSegmentArena(mem *memory.Memory, arenaPtr memory.Relocatable) error {
+	header, err := arenaPtr.SubUint(segmentArenaHeaderSize)
+	if err != nil {
+		return err
+	}
+	infos, err := mem.GetRelocatable(header)
+	if err != nil {
+		return err
+	}
+	nSegmentsFelt, err := mem.GetFelt(header.AddUint(1))
+	if err != nil {
+		return err
+	}
+	nFinalizedFelt, err := mem.GetFelt(header.AddUint(2))
+	if err != nil {
+		return err
+	}
+	nSegments, err := nSegmentsFelt.ToUint()
+	if err != nil {
+		return err
+	}
+	nFinalized, err := nFinalizedFelt.ToUint()
+	if err != nil {
+		return err
+	}
+	if nFinalized > nSegments {
+		return errors.Errorf("%d segments finalized out of %d", nFinalized, nSegments)
+	}
+
+	// Segment that used each finalize index & each start segment, to detect segments finalized twice
+	finalizedBy := make(map[uint]uint, nFinalized)
+	startedBy := make(map[int]uint, nFinalized)
+	for i := uint(0); i < nSegments; i++ {
+		info := infos.AddUint(i * segmentInfoSize)
+		if _, ok := mem.GetValue(info.AddUint(1)); !ok {
+			return SegmentNotFinalizedError(i)
+		}
+		start, err := mem.GetRelocatable(info)
+		if err != nil {
+			return InvalidSegmentInfoError(i, "the start should be a pointer")
+		}
+		end, err := mem.GetRelocatable(info.AddUint(1))
+		if err != nil {
+			return InvalidSegmentInfoError(i, "the end should be a pointer")
+		}
+		if end.SegmentIndex != start.SegmentIndex || end.Offset < start.Offset {
+			return InvalidSegmentInfoError(i, fmt.Sprintf("end %s is not after start %s", end.ToString(), start.ToString()))
+		}
+		finalizeIndexFelt, err := mem.GetFelt(info.AddUint(2))
+		if err != nil {
+			return InvalidSegmentInfoError(i, "the finalize index should be a felt")
+		}
+		finalizeIndex, err := finalizeIndexFelt.ToUint()
+		if err != nil || finalizeIndex >= nFinalized {
+			return InvalidSegmentInfoError(i, fmt.Sprintf("finalize index %s is out of range [0, %d)", finalizeIndexFelt.ToSignedFeltString(), nFinalized))
+		}
+		if other, ok := finalizedBy[finalizeIndex]; ok {
+			return SegmentFinalizedTwiceError(finalizeIndex, i, other)
+		}
+		if other, ok := startedBy[start.SegmentIndex]; ok {
+			return InvalidSegmentInfoError(i, fmt.Sprintf("segment %d was already allocated to segment %d of the arena", start.SegmentIndex, other))
+		}
+		finalizedBy[finalizeIndex] = i
+		startedBy[start.SegmentIndex] = i
+	}
+	// Each of the nSegments infos took a different finalize index out of the nFinalized ones, so both counts match
+	return nil
+}
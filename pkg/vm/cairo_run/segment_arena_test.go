@@ -0,0 +1,103 @@
+package cairo_run_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+// Info of a segment of the arena, an unset end leaves the whole info unset
+type segmentInfo struct {
+	start         memory.Relocatable
+	end           *memory.Relocatable
+	finalizeIndex uint64
+}
+
+// Builds an arena whose last header holds the given infos & counts, and returns the arena pointer
+func buildSegmentArena(t *testing.T, segments *memory.MemorySegmentManager, nSegments uint64, nFinalized uint64, infos []segmentInfo) memory.Relocatable {
+	arena := segments.AddSegment()
+	infosPtr := segments.AddSegment()
+	header := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableRelocatable(infosPtr),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(nSegments)),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(nFinalized)),
+	}
+	arenaPtr, err := segments.LoadData(arena, &header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, info := range infos {
+		if info.end == nil {
+			continue
+		}
+		data := []memory.MaybeRelocatable{
+			*memory.NewMaybeRelocatableRelocatable(info.start),
+			*memory.NewMaybeRelocatableRelocatable(*info.end),
+			*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(info.finalizeIndex)),
+		}
+		if _, err := segments.LoadData(infosPtr.AddUint(uint(3*i)), &data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return arenaPtr
+}
+
+// Adds a dict segment, returning its start & its end after n entries
+func addDictSegment(segments *memory.MemorySegmentManager, n uint) (memory.Relocatable, *memory.Relocatable) {
+	start := segments.AddSegment()
+	end := start.AddUint(3 * n)
+	return start, &end
+}
+
+func TestValidateSegmentArenaEmpty(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	arenaPtr := buildSegmentArena(t, &segments, 0, 0, nil)
+	if err := cairo_run.ValidateSegmentArena(&segments, arenaPtr); err != nil {
+		t.Errorf("ValidateSegmentArena failed with error: %s", err)
+	}
+}
+
+func TestValidateSegmentArenaAllFinalized(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	start0, end0 := addDictSegment(&segments, 2)
+	start1, end1 := addDictSegment(&segments, 0)
+	arenaPtr := buildSegmentArena(t, &segments, 2, 2, []segmentInfo{{start0, end0, 1}, {start1, end1, 0}})
+	if err := cairo_run.ValidateSegmentArena(&segments, arenaPtr); err != nil {
+		t.Errorf("ValidateSegmentArena failed with error: %s", err)
+	}
+}
+
+func TestValidateSegmentArenaErrors(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	start0, end0 := addDictSegment(&segments, 2)
+	start1, end1 := addDictSegment(&segments, 1)
+	outside := start1.AddUint(1)
+	before := start0
+
+	for _, tc := range []struct {
+		name                  string
+		nSegments, nFinalized uint64
+		infos                 []segmentInfo
+		expected              error
+	}{
+		{"NotFinalized", 2, 1, []segmentInfo{{start0, end0, 0}, {start1, nil, 0}}, cairo_run.ErrSegmentNotFinalized},
+		{"FinalizedTwice", 2, 2, []segmentInfo{{start0, end0, 0}, {start1, end1, 0}}, cairo_run.ErrSegmentFinalizedTwice},
+		{"FinalizeIndexOutOfRange", 1, 1, []segmentInfo{{start0, end0, 1}}, cairo_run.ErrInvalidSegmentInfo},
+		{"EndInOtherSegment", 1, 1, []segmentInfo{{start0, &outside, 0}}, cairo_run.ErrInvalidSegmentInfo},
+		{"EndBeforeStart", 1, 1, []segmentInfo{{start0.AddUint(3), &before, 0}}, cairo_run.ErrInvalidSegmentInfo},
+		{"SegmentAllocatedTwice", 2, 2, []segmentInfo{{start0, end0, 0}, {start0, end0, 1}}, cairo_run.ErrInvalidSegmentInfo},
+		{"MoreFinalizedThanSegments", 1, 2, []segmentInfo{{start0, end0, 0}}, cairo_run.ErrInvalidSegmentArena},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			arenaSegments := segments.Clone()
+			arenaPtr := buildSegmentArena(t, &arenaSegments, tc.nSegments, tc.nFinalized, tc.infos)
+			err := cairo_run.ValidateSegmentArena(&arenaSegments, arenaPtr)
+			if !errors.Is(err, tc.expected) || !errors.Is(err, cairo_run.ErrInvalidSegmentArena) {
+				t.Errorf("Expected %v, got: %v", tc.expected, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package cairo_run_test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+// Snapshot tests: the relocated trace & memory of each program are hashed and compared against the ones stored in
+// testdata/snapshots, so that changes to the step loop or to relocation can't silently alter the result of a run
+// After an intended change, the snapshots can be regenerated with:
+//
+//	go test ./pkg/vm/cairo_run -run Snapshot -update-snapshots
+
+var updateSnapshots = flag.Bool("update-snapshots", false, "Regenerate the snapshots in testdata/snapshots instead of checking them")
+
+const snapshotsDir = "testdata/snapshots"
+
+type runSnapshot struct {
+	Steps        int    `json:"steps"`
+	TraceSha256  string `json:"trace_sha256"`
+	MemorySha256 string `json:"memory_sha256"`
+}
+
+func newRunSnapshot(trace []byte, memory []byte) runSnapshot {
+	traceHash := sha256.Sum256(trace)
+	memoryHash := sha256.Sum256(memory)
+	return runSnapshot{
+		Steps:        len(trace) / traceEntrySize,
+		TraceSha256:  hex.EncodeToString(traceHash[:]),
+		MemorySha256: hex.EncodeToString(memoryHash[:]),
+	}
+}
+
+func checkSnapshot(t *testing.T, name string, trace []byte, memory []byte) {
+	t.Helper()
+	got := newRunSnapshot(trace, memory)
+	path := filepath.Join(snapshotsDir, name+".json")
+	if *updateSnapshots {
+		encoded, err := json.MarshalIndent(got, "", "  ")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(snapshotsDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, append(encoded, '\n'), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	encoded, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		t.Skipf("No snapshot for %s, run the test with -update-snapshots to create it", name)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected runSnapshot
+	if err := json.Unmarshal(encoded, &expected); err != nil {
+		t.Fatalf("Invalid snapshot %s: %s", path, err)
+	}
+	if got.Steps != expected.Steps {
+		t.Errorf("Amount of steps changed. Expected: %d, got: %d", expected.Steps, got.Steps)
+	}
+	if got.TraceSha256 != expected.TraceSha256 {
+		t.Errorf("Relocated trace changed. Expected hash: %s, got: %s", expected.TraceSha256, got.TraceSha256)
+	}
+	if got.MemorySha256 != expected.MemorySha256 {
+		t.Errorf("Relocated memory changed. Expected hash: %s, got: %s", expected.MemorySha256, got.MemorySha256)
+	}
+}
+
+func TestProgramSnapshots(t *testing.T) {
+	testProgramSnapshots(t, "../../../cairo_programs", "", false)
+}
+
+func TestProgramSnapshotsProofMode(t *testing.T) {
+	testProgramSnapshots(t, "../../../cairo_programs/proof_programs", "proof_", true)
+}
+
+func testProgramSnapshots(t *testing.T, programsDir string, prefix string, proofMode bool) {
+	programs, err := filepath.Glob(filepath.Join(programsDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) == 0 {
+		t.Skipf("No compiled programs in %s", programsDir)
+	}
+	for _, program := range programs {
+		program := program
+		name := strings.TrimSuffix(filepath.Base(program), ".json")
+		t.Run(name, func(t *testing.T) {
+			trace, memory, err := runGoVm(program, proofMode)
+			if err != nil {
+				// Programs that are expected to fail are covered by their own tests
+				t.Skipf("Program execution failed with error: %s", err)
+			}
+			checkSnapshot(t, prefix+name, trace, memory)
+		})
+	}
+}
+
+// Programs that are built in the test, so that their snapshots can be checked without compiling the corpus
+func TestSyntheticProgramSnapshots(t *testing.T) {
+	programs := map[string][]string{
+		// main: [ap] = 10, ap++
+		// loop: [ap] = [ap - 1] - 1, ap++; jmp loop if [ap - 1] != 0
+		// ret
+		"synthetic_countdown": {"0x480680017fff8000", "10", "0x482480017fff8000", "-1", "0x20680017fff7fff", "-2", "0x208b7fff7fff7ffe"},
+		// main: call f; ret
+		// f: [ap] = 3, ap++; ret
+		"synthetic_call": {"0x1104800180018000", "3", "0x208b7fff7fff7ffe", "0x480680017fff8000", "3", "0x208b7fff7fff7ffe"},
+	}
+	for name, words := range programs {
+		words := words
+		t.Run(name, func(t *testing.T) {
+			runner, err := cairo_run.CairoRunProgram(vmtest.Program(words...), cairo_run.CairoRunConfig{Layout: "plain"})
+			if err != nil {
+				t.Fatalf("Program execution failed with error: %s", err)
+			}
+			var trace, relocatedMemory bytes.Buffer
+			if err := cairo_run.WriteVmEncodedTrace(&runner.Vm, &trace); err != nil {
+				t.Fatal(err)
+			}
+			if err := cairo_run.WriteEncodedMemory(runner.Vm.RelocatedMemory, &relocatedMemory); err != nil {
+				t.Fatal(err)
+			}
+			checkSnapshot(t, name, trace.Bytes(), relocatedMemory.Bytes())
+		})
+	}
+}
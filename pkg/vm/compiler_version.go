@@ -0,0 +1,97 @@
+package vm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidCompilerVersion = errors.New("Invalid compiler version")
+var ErrUnknownCompilerVersion = errors.New("Unknown compiler version")
+
+// Metadata of a compiled program, not needed to run it
+type ProgramMetadata struct {
+	// Version of cairo-lang that compiled the program (ie: 0.11.0), empty for programs that were not compiled
+	CompilerVersion string
+	// Module whose scope holds the program's main function (ie: __main__)
+	MainScope string
+}
+
+// Version of the cairo-lang compiler, used to gate the behaviour that changed between releases
+// Pre-release suffixes (ie: 0.11.0a0) are ignored
+type CompilerVersion struct {
+	Major uint
+	Minor uint
+	Patch uint
+}
+
+// Oldest compiler version whose hints are supported
+var MinSupportedCompilerVersion = CompilerVersion{0, 10, 0}
+
+// First compiler version whose hints are not supported
+var MaxUnsupportedCompilerVersion = CompilerVersion{0, 13, 0}
+
+func InvalidCompilerVersionError(version string) error {
+	return fmt.Errorf("%w: %q, expected major.minor.patch", ErrInvalidCompilerVersion, version)
+}
+
+// Parses a version of the form major.minor[.patch], where the last number can carry a pre-release suffix
+func ParseCompilerVersion(version string) (CompilerVersion, error) {
+	parts := strings.Split(version, ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return CompilerVersion{}, InvalidCompilerVersionError(version)
+	}
+	var numbers [3]uint
+	for i, part := range parts {
+		if i == len(parts)-1 {
+			// Drop suffixes like a0, rc1 or -dev
+			if end := strings.IndexFunc(part, func(r rune) bool { return r < '0' || r > '9' }); end != -1 {
+				part = part[:end]
+			}
+		}
+		n, err := strconv.ParseUint(part, 10, 0)
+		if err != nil {
+			return CompilerVersion{}, InvalidCompilerVersionError(version)
+		}
+		numbers[i] = uint(n)
+	}
+	return CompilerVersion{numbers[0], numbers[1], numbers[2]}, nil
+}
+
+func (v CompilerVersion) String() string {
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
+
+// Returns -1, 0 or 1 if v is older than, the same as or newer than other
+func (v CompilerVersion) Compare(other CompilerVersion) int {
+	for _, pair := range [][2]uint{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if pair[0] < pair[1] {
+			return -1
+		}
+		if pair[0] > pair[1] {
+			return 1
+		}
+	}
+	return 0
+}
+
+// Returns true if v is the same as or newer than other
+func (v CompilerVersion) AtLeast(other CompilerVersion) bool {
+	return v.Compare(other) >= 0
+}
+
+// Returns true if the hints emitted by this version of the compiler are supported
+func (v CompilerVersion) IsSupported() bool {
+	return v.AtLeast(MinSupportedCompilerVersion) && !v.AtLeast(MaxUnsupportedCompilerVersion)
+}
+
+// Returns the version of the compiler the program was compiled with
+// Fails with ErrUnknownCompilerVersion if the program doesn't record it
+func (p *Program) CompilerVersion() (CompilerVersion, error) {
+	if p.Metadata.CompilerVersion == "" {
+		return CompilerVersion{}, ErrUnknownCompilerVersion
+	}
+	return ParseCompilerVersion(p.Metadata.CompilerVersion)
+}
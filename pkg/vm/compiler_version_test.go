@@ -0,0 +1,65 @@
+package vm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+)
+
+func TestParseCompilerVersion(t *testing.T) {
+	for version, expected := range map[string]vm.CompilerVersion{
+		"0.11.0":      {0, 11, 0},
+		"0.10.3":      {0, 10, 3},
+		"0.12":        {0, 12, 0},
+		"0.11.0a0":    {0, 11, 0},
+		"0.13.1rc1":   {0, 13, 1},
+		"2.4.0-dev":   {2, 4, 0},
+		"0.12.2.post": {},
+	} {
+		parsed, err := vm.ParseCompilerVersion(version)
+		if expected == (vm.CompilerVersion{}) {
+			if !errors.Is(err, vm.ErrInvalidCompilerVersion) {
+				t.Errorf("Expected ErrInvalidCompilerVersion for %s, got %v", version, err)
+			}
+			continue
+		}
+		if err != nil || parsed != expected {
+			t.Errorf("Wrong version for %s. Expected %s, got %s (%v)", version, expected, parsed, err)
+		}
+	}
+	for _, version := range []string{"", "0", "a.b.c", "0..1"} {
+		if _, err := vm.ParseCompilerVersion(version); !errors.Is(err, vm.ErrInvalidCompilerVersion) {
+			t.Errorf("Expected ErrInvalidCompilerVersion for %q, got %v", version, err)
+		}
+	}
+}
+
+func TestCompilerVersionIsSupported(t *testing.T) {
+	for version, supported := range map[vm.CompilerVersion]bool{
+		{0, 9, 1}:  false,
+		{0, 10, 0}: true,
+		{0, 11, 0}: true,
+		{0, 12, 2}: true,
+		{0, 13, 0}: false,
+		{2, 4, 0}:  false,
+	} {
+		if version.IsSupported() != supported {
+			t.Errorf("Expected IsSupported to be %t for %s", supported, version)
+		}
+	}
+	if !(vm.CompilerVersion{0, 11, 0}).AtLeast(vm.CompilerVersion{0, 10, 3}) || (vm.CompilerVersion{0, 10, 3}).AtLeast(vm.CompilerVersion{0, 11, 0}) {
+		t.Error("0.11.0 is newer than 0.10.3")
+	}
+}
+
+func TestProgramCompilerVersion(t *testing.T) {
+	program := vm.Program{}
+	if _, err := program.CompilerVersion(); !errors.Is(err, vm.ErrUnknownCompilerVersion) {
+		t.Errorf("Expected ErrUnknownCompilerVersion, got %v", err)
+	}
+	program.Metadata.CompilerVersion = "0.11.0"
+	if version, err := program.CompilerVersion(); err != nil || version != (vm.CompilerVersion{0, 11, 0}) {
+		t.Errorf("Wrong compiler version: %s, %v", version, err)
+	}
+}
@@ -0,0 +1,217 @@
+package vm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/compat"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/types"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Error category of the errors raised by the vm while executing instructions, which can be matched through errors.Is
+var ErrVirtualMachine = errors.New("Virtual machine error")
+
+type VirtualMachineError struct {
+	Msg string
+}
+
+func (e *VirtualMachineError) Error() string {
+	return e.Msg
+}
+
+// Every VirtualMachineError belongs to the ErrVirtualMachine category
+func (e *VirtualMachineError) Is(target error) bool {
+	return target == ErrVirtualMachine
+}
+
+var ErrInvalidInstructionEncoding = &VirtualMachineError{"Instruction should be an int"}
+var ErrUnconstrainedResAssertEq = &VirtualMachineError{"Res.UNCONSTRAINED cannot be used with Opcode.ASSERT_EQ"}
+var ErrDiffAssertValues = &VirtualMachineError{"An ASSERT_EQ instruction failed"}
+var ErrCantWriteReturnPc = &VirtualMachineError{"Call failed to write return-pc (inconsistent op0)"}
+var ErrCantWriteReturnFp = &VirtualMachineError{"Call failed to write return-fp (inconsistent dst)"}
+var ErrRetMissingReturnPc = &VirtualMachineError{"Ret failed to read return-pc"}
+var ErrRetMissingReturnFp = &VirtualMachineError{"Ret failed to read return-fp"}
+var ErrRetInvalidReturnPc = &VirtualMachineError{"Ret failed: return-pc is not a relocatable value"}
+var ErrInconsistentAutoDeduction = &VirtualMachineError{"Inconsistent auto-deduction for builtin"}
+var ErrComputeResRelocatableMul = &VirtualMachineError{"Failed to compute Res.MUL: Could not complete computation of non pure values"}
+var ErrComputeResRelocatableAdd = &VirtualMachineError{"Failed to compute Res.ADD: Could not add two relocatable values"}
+var ErrFailedToComputeOperands = &VirtualMachineError{"Couldn't compute operand"}
+var ErrUnknownOp0 = &VirtualMachineError{"op0 must be known in double dereference"}
+var ErrImmShouldBe1 = &VirtualMachineError{"In immediate mode, off2 should be 1"}
+var ErrAddressNotRelocatable = &VirtualMachineError{"Op1 address is not relocatable"}
+var ErrUnknownRegister = &VirtualMachineError{"Unknown register"}
+var ErrUnknownOp1Src = &VirtualMachineError{"Unknown op1 source"}
+var ErrUnconstrainedResJump = &VirtualMachineError{"Res.UNCONSTRAINED cannot be used with PcUpdate.JUMP"}
+var ErrJumpNotRelocatable = &VirtualMachineError{"An integer value as Res cannot be used with PcUpdate.JUMP"}
+var ErrUnconstrainedResJumpRel = &VirtualMachineError{"Res.UNCONSTRAINED cannot be used with PcUpdate.JUMP_REL"}
+var ErrJumpRelNotInt = &VirtualMachineError{"A relocatable value as Res cannot be used with PcUpdate.JUMP_REL"}
+var ErrUnconstrainedResAdd = &VirtualMachineError{"Res.UNCONSTRAINED cannot be used with ApUpdate.ADD"}
+var ErrBuiltinNotFound = &VirtualMachineError{"Builtin not found"}
+var ErrTraceNotRelocated = &VirtualMachineError{"Trace not relocated"}
+var ErrMissingOutput = &VirtualMachineError{"Missing value in the output segment"}
+var ErrRelocatableOutput = &VirtualMachineError{"Relocatable value in the output segment"}
+var ErrMemoryLimitExceeded = &VirtualMachineError{"Memory limit exceeded"}
+var ErrEndOfProgram = &VirtualMachineError{"EndOfProgram"}
+
+// The Rust vm lists dst first
+func DiffAssertValuesError(res memory.MaybeRelocatable, dst memory.MaybeRelocatable) error {
+	return compat.WithRustMessage(
+		fmt.Errorf("%w: %s != %s.", ErrDiffAssertValues, res.ToString(), dst.ToString()),
+		fmt.Sprintf("%s: %s != %s.", ErrDiffAssertValues, dst.RustString(), res.RustString()),
+	)
+}
+
+func CantWriteReturnPcError(op0 memory.MaybeRelocatable, returnPc memory.MaybeRelocatable) error {
+	return compat.WithRustMessage(
+		fmt.Errorf("%w: %s != %s. Did you forget to increment ap?", ErrCantWriteReturnPc, op0.ToString(), returnPc.ToString()),
+		fmt.Sprintf("%s: %s != %s. Did you forget to increment ap?", ErrCantWriteReturnPc, op0.RustString(), returnPc.RustString()),
+	)
+}
+
+func CantWriteReturnFpError(dst memory.MaybeRelocatable, returnFp memory.MaybeRelocatable) error {
+	return compat.WithRustMessage(
+		fmt.Errorf("%w: %s != %s. Did you forget to increment ap?", ErrCantWriteReturnFp, dst.ToString(), returnFp.ToString()),
+		fmt.Sprintf("%s: %s != %s. Did you forget to increment ap?", ErrCantWriteReturnFp, dst.RustString(), returnFp.RustString()),
+	)
+}
+
+// Raised when a run for a number of steps reaches the final pc before running all of them
+func EndOfProgramError(remainingSteps uint) error {
+	return compat.WithRustMessage(
+		fmt.Errorf("%w: %d", ErrEndOfProgram, remainingSteps),
+		fmt.Sprintf("Execution reached the end of the program. Requested remaining steps: %d.", remainingSteps),
+	)
+}
+
+func RetMissingReturnPcError(addr memory.Relocatable) error {
+	return fmt.Errorf("%w: unknown value for memory cell %s. Was the function called with call?", ErrRetMissingReturnPc, addr.ToString())
+}
+
+func RetMissingReturnFpError(addr memory.Relocatable) error {
+	return fmt.Errorf("%w: unknown value for memory cell %s. Was the function called with call?", ErrRetMissingReturnFp, addr.ToString())
+}
+
+func RetInvalidReturnPcError(returnPc memory.MaybeRelocatable) error {
+	return fmt.Errorf("%w: %s", ErrRetInvalidReturnPc, returnPc.ToString())
+}
+
+func InconsistentAutoDeductionError(builtinName string, expected memory.MaybeRelocatable, got memory.MaybeRelocatable) error {
+	return fmt.Errorf("%w %s, expected %s, got %s", ErrInconsistentAutoDeduction, builtinName, expected.ToString(), got.ToString())
+}
+
+func ComputeResRelocatableMulError(op0 memory.MaybeRelocatable, op1 memory.MaybeRelocatable) error {
+	return fmt.Errorf("%w %s * %s", ErrComputeResRelocatableMul, op0.ToString(), op1.ToString())
+}
+
+func ComputeResRelocatableAddError(op0 memory.MaybeRelocatable, op1 memory.MaybeRelocatable) error {
+	return fmt.Errorf("%w %s + %s", ErrComputeResRelocatableAdd, op0.ToString(), op1.ToString())
+}
+
+func FailedToComputeOperandsError(operand string, addr memory.Relocatable) error {
+	return compat.WithRustMessage(
+		fmt.Errorf("%w %s. Unknown value for memory cell %s", ErrFailedToComputeOperands, operand, addr.ToString()),
+		fmt.Sprintf("%s %s. Unknown value for memory cell %s", ErrFailedToComputeOperands, operand, addr.RustString()),
+	)
+}
+
+// Raised when an operand can't be deduced because it would require dividing dst by a zero operand, matches both
+// ErrFailedToComputeOperands & lambdaworks.ErrDividedByZero
+func DividedByZeroDeductionError(operand string, addr memory.Relocatable) error {
+	return fmt.Errorf("%w %s at %s: %w", ErrFailedToComputeOperands, operand, addr.ToString(), lambdaworks.ErrDividedByZero)
+}
+
+func UnknownRegisterError(register Register) error {
+	return fmt.Errorf("%w: %d", ErrUnknownRegister, register)
+}
+
+func UnknownOp1SrcError(op1Src Op1Src) error {
+	return fmt.Errorf("%w: %d", ErrUnknownOp1Src, op1Src)
+}
+
+func BuiltinNotFoundError(builtinName string) error {
+	return fmt.Errorf("%w: %s", ErrBuiltinNotFound, builtinName)
+}
+
+func MissingOutputError(addr memory.Relocatable) error {
+	return fmt.Errorf("%w: %s", ErrMissingOutput, addr.ToString())
+}
+
+func RelocatableOutputError(addr memory.Relocatable, value memory.Relocatable) error {
+	return fmt.Errorf("%w: %s at %s", ErrRelocatableOutput, value.ToString(), addr.ToString())
+}
+
+func MemoryLimitExceededError(maxMemorySize uint) error {
+	return fmt.Errorf("%w: the relocated memory would hold more than %d cells", ErrMemoryLimitExceeded, maxMemorySize)
+}
+
+// Error returned when a step fails, wraps the underlying error with the state of the vm at the time of the failure
+type StepError struct {
+	Pc memory.Relocatable
+	Ap memory.Relocatable
+	Fp memory.Relocatable
+	// Nil if the failure happened before the instruction was decoded (ie: while running hints)
+	Instruction *Instruction
+	// Nil if the failure happened before the addresses of the operands were computed
+	OperandsAddresses *OperandsAddresses
+	// Variables of the execution scopes, only set if the failure happened while running a hint
+	ExecutionScopes [][]types.ScopeVariable
+	Err             error
+	// Set if the failure happened while running a hint
+	hint bool
+}
+
+func (e *StepError) Error() string {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Error at pc=%s: %s\nap=%s, fp=%s", e.Pc.ToString(), e.Err, e.Ap.ToString(), e.Fp.ToString())
+	if e.Instruction != nil {
+		fmt.Fprintf(&msg, "\nInstruction: %s", e.Instruction.ToString())
+	}
+	if e.OperandsAddresses != nil {
+		fmt.Fprintf(&msg, "\nOperand addresses: dst=%s, op0=%s, op1=%s",
+			e.OperandsAddresses.DstAddr.ToString(), e.OperandsAddresses.Op0Addr.ToString(), e.OperandsAddresses.Op1Addr.ToString())
+	}
+	if e.ExecutionScopes != nil {
+		fmt.Fprintf(&msg, "\nExecution scopes:\n%s", types.FormatScopes(e.ExecutionScopes))
+	}
+	return msg.String()
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// Same message as the VmException of the Rust vm, without its source location & traceback
+func (e *StepError) RustMessage() string {
+	inner := compat.Message(e.Err)
+	if e.hint {
+		inner = "Got an exception while executing a hint: " + inner
+	}
+	return fmt.Sprintf("Error at pc=%s:\n%s\n", e.Pc.RustString(), inner)
+}
+
+// Builds the StepError for a step that started with the given register values
+// The instruction & operand addresses are copied, so that they don't have to be moved to the heap on the steps that succeed
+func newStepError(registers TraceEntry, err error, instruction *Instruction, operandsAddresses *OperandsAddresses) error {
+	stepErr := &StepError{Pc: registers.Pc, Ap: registers.Ap, Fp: registers.Fp, Err: err}
+	if instruction != nil {
+		instructionCopy := *instruction
+		stepErr.Instruction = &instructionCopy
+	}
+	if operandsAddresses != nil {
+		operandsAddressesCopy := *operandsAddresses
+		stepErr.OperandsAddresses = &operandsAddressesCopy
+	}
+	return stepErr
+}
+
+// Builds the StepError for a hint that failed, including the variables of the execution scopes it ran with
+func newHintStepError(registers TraceEntry, err error, execScopes *types.ExecutionScopes) error {
+	stepErr := &StepError{Pc: registers.Pc, Ap: registers.Ap, Fp: registers.Fp, Err: err, hint: true}
+	if execScopes != nil {
+		stepErr.ExecutionScopes = execScopes.Inspect()
+	}
+	return stepErr
+}
@@ -0,0 +1,55 @@
+package vm
+
+import (
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/pkg/errors"
+)
+
+var ErrForkStreamedTrace = errors.New("Cannot fork a vm that streams its trace")
+
+// Returns an independent copy of the vm, which can be run & discarded without affecting the original
+// (ie: to explore both branches of a nondeterministic hint, or to check a hint's effect before committing to it)
+// The memory is copied on write, so forking doesn't copy the cells until either vm writes to the memory
+// The builtin runners are cloned & their validation rules bound to the fork's memory, while the hooks, observers,
+// tracer & meter belong to the original execution & are not carried over
+func (v *VirtualMachine) Fork() (*VirtualMachine, error) {
+	if v.StreamedTrace != nil {
+		return nil, ErrForkStreamedTrace
+	}
+	fork := &VirtualMachine{
+		RunContext:        v.RunContext,
+		CurrentStep:       v.CurrentStep,
+		Segments:          v.Segments.Clone(),
+		BuiltinRunners:    make([]builtins.BuiltinRunner, 0, len(v.BuiltinRunners)),
+		Trace:             append([]TraceEntry(nil), v.Trace...),
+		RelocatedTrace:    append([]RelocatedTraceEntry(nil), v.RelocatedTrace...),
+		RunFinished:       v.RunFinished,
+		RelocationWorkers: v.RelocationWorkers,
+		relocationTable:   v.relocationTable,
+	}
+	for _, builtin := range v.BuiltinRunners {
+		clone := builtin.Clone()
+		// The rules are only added once the vm is initialized
+		if fork.Segments.Memory.HasValidationRule(uint(clone.Base().SegmentIndex)) {
+			clone.AddValidationRule(&fork.Segments.Memory)
+		}
+		fork.BuiltinRunners = append(fork.BuiltinRunners, clone)
+	}
+	fork.RelocatedMemory = v.RelocatedMemory.Clone()
+	if v.RcLimitsMin != nil {
+		rcLimitsMin := *v.RcLimitsMin
+		fork.RcLimitsMin = &rcLimitsMin
+	}
+	if v.RcLimitsMax != nil {
+		rcLimitsMax := *v.RcLimitsMax
+		fork.RcLimitsMax = &rcLimitsMax
+	}
+	if v.RunResources != nil {
+		runResources := RunResources{}
+		if v.RunResources.nSteps != nil {
+			runResources = NewRunResources(*v.RunResources.nSteps)
+		}
+		fork.RunResources = &runResources
+	}
+	return fork, nil
+}
@@ -12,3 +12,25 @@ type HintProcessor interface {
 	// Executes the hint which's data is provided by a dynamic structure previously created by CompileHint
 	ExecuteHint(vm *VirtualMachine, hintData *any, constants *map[string]lambdaworks.Felt, execScopes *types.ExecutionScopes) error
 }
+
+// Hints registered during the execution, indexed by the pc offset they are executed at
+// The hint datas must have been created by the CompileHint method of the processor that will execute them
+type HintExtension map[uint][]any
+
+// A HintProcessor whose hints may register additional hints at new pcs (ie: when loading code into memory)
+// If a hint processor implements it, the vm uses ExecuteHintExtensive instead of ExecuteHint
+type ExtensiveHintProcessor interface {
+	HintProcessor
+	// Executes the hint like ExecuteHint, returning the hints it registers (if any)
+	ExecuteHintExtensive(vm *VirtualMachine, hintData *any, constants *map[string]lambdaworks.Felt, execScopes *types.ExecutionScopes) (HintExtension, error)
+}
+
+// Adds the extension's hints to the hint data map, replacing the hints previously registered at the same pcs
+func (extension HintExtension) Merge(hintDataMap map[uint][]any) {
+	for pc, hintDatas := range extension {
+		if len(hintDatas) == 0 {
+			continue
+		}
+		hintDataMap[pc] = hintDatas
+	}
+}
@@ -1,7 +1,7 @@
 package vm
 
 import (
-	"errors"
+	"fmt"
 )
 
 //  Structure of the 63-bit that form the first word of each instruction.
@@ -100,12 +100,12 @@ const (
 	AssertEq Opcode = 4
 )
 
-var ErrNonZeroHighBitError = errors.New("Instruction high bit was not set to zero")
-var ErrInvalidOp1RegError = errors.New("Instruction had invalid Op1 Register")
-var ErrInvalidPcUpdateError = errors.New("Instruction had invalid Pc update")
-var ErrInvalidResError = errors.New("Instruction had an invalid res")
-var ErrInvalidOpcodeError = errors.New("Instruction had an invalid opcode")
-var ErrInvalidApUpdateError = errors.New("Instruction had an invalid Ap Update")
+var ErrNonZeroHighBitError = &VirtualMachineError{"Instruction MSB should be 0"}
+var ErrInvalidOp1RegError = &VirtualMachineError{"Invalid op1_register value"}
+var ErrInvalidPcUpdateError = &VirtualMachineError{"Invalid pc_update value"}
+var ErrInvalidResError = &VirtualMachineError{"Invalid res value"}
+var ErrInvalidOpcodeError = &VirtualMachineError{"Invalid opcode value"}
+var ErrInvalidApUpdateError = &VirtualMachineError{"Invalid ap_update value"}
 
 func DecodeInstruction(encodedInstruction uint64) (Instruction, error) {
 	const HighBit uint64 = 1 << 63
@@ -267,3 +267,32 @@ func (i *Instruction) Size() uint {
 	}
 	return 1
 }
+
+// Returns a human-readable representation of the instruction, used when printing traces
+// Example: "AssertEq dst=[fp-3] op0=[ap+0] op1=[pc+1] res=Op1 pc_update=Regular ap_update=Add1"
+func (i *Instruction) ToString() string {
+	var op1 string
+	switch i.Op1Addr {
+	case Op1SrcImm:
+		op1 = fmt.Sprintf("[pc%+d]", i.Off2)
+	case Op1SrcAP:
+		op1 = fmt.Sprintf("[ap%+d]", i.Off2)
+	case Op1SrcFP:
+		op1 = fmt.Sprintf("[fp%+d]", i.Off2)
+	case Op1SrcOp0:
+		op1 = fmt.Sprintf("[op0%+d]", i.Off2)
+	}
+	return fmt.Sprintf("%s dst=[%s%+d] op0=[%s%+d] op1=%s res=%s pc_update=%s ap_update=%s",
+		opcodeNames[i.Opcode], registerNames[i.DstReg], i.Off0, registerNames[i.Op0Reg], i.Off1, op1,
+		resLogicNames[i.ResLogic], pcUpdateNames[i.PcUpdate], apUpdateNames[i.ApUpdate])
+}
+
+var registerNames = map[Register]string{AP: "ap", FP: "fp"}
+
+var opcodeNames = map[Opcode]string{NOp: "NOp", Call: "Call", Ret: "Ret", AssertEq: "AssertEq"}
+
+var resLogicNames = map[ResLogic]string{ResOp1: "Op1", ResAdd: "Add", ResMul: "Mul", ResUnconstrained: "Unconstrained"}
+
+var pcUpdateNames = map[PcUpdate]string{PcUpdateRegular: "Regular", PcUpdateJump: "Jump", PcUpdateJumpRel: "JumpRel", PcUpdateJnz: "Jnz"}
+
+var apUpdateNames = map[ApUpdate]string{ApUpdateRegular: "Regular", ApUpdateAdd: "Add", ApUpdateAdd1: "Add1", ApUpdateAdd2: "Add2"}
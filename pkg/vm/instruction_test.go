@@ -1,6 +1,8 @@
 package vm_test
 
 import (
+	"errors"
+	"math"
 	"testing"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
@@ -266,3 +268,28 @@ func TestDecodeOffsetNegative(t *testing.T) {
 		t.Error("Wrong Instruction Offset destination")
 	}
 }
+
+// Decoding arbitrary words should never panic, and should either fail with a vm error or produce a well formed instruction
+func FuzzDecodeInstruction(f *testing.F) {
+	for _, seed := range []uint64{0x480680017fff8000, 0x208b7fff7fff7ffe, 0x1104800180018000, 0x94A7800080008000, 0x294F800080008000} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, encodedInstruction uint64) {
+		instruction, err := vm.DecodeInstruction(encodedInstruction)
+		if err != nil {
+			if !errors.Is(err, vm.ErrVirtualMachine) {
+				t.Fatalf("Decoding %#x failed with an untyped error: %s", encodedInstruction, err)
+			}
+			return
+		}
+		for _, offset := range []int{instruction.Off0, instruction.Off1, instruction.Off2} {
+			if offset < math.MinInt16 || offset > math.MaxInt16 {
+				t.Fatalf("Decoding %#x produced an out of range offset: %d", encodedInstruction, offset)
+			}
+		}
+		if size := instruction.Size(); size != 1 && size != 2 {
+			t.Fatalf("Decoding %#x produced an instruction of size %d", encodedInstruction, size)
+		}
+		instruction.ToString()
+	})
+}
@@ -0,0 +1,90 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/compat"
+	"github.com/pkg/errors"
+)
+
+// Error categories, which can be matched through errors.Is
+var ErrMemory = errors.New("Memory error")
+var ErrMath = errors.New("Math error")
+
+// Error belonging to one of the vm's error categories (ErrMemory, ErrMath, vm.ErrVirtualMachine, etc)
+// Its message is the one of the underlying error, so that it matches the one returned by the Rust VM,
+// while errors.Is & errors.As can be used to match either the category or the underlying error
+type CategoryError struct {
+	Category error
+	Err      error
+}
+
+func (e *CategoryError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CategoryError) Unwrap() []error {
+	return []error{e.Category, e.Err}
+}
+
+func MemoryError(err error) error {
+	return &CategoryError{Category: ErrMemory, Err: err}
+}
+
+func MathError(err error) error {
+	return &CategoryError{Category: ErrMath, Err: err}
+}
+
+var ErrUnallocatedSegment = errors.New("Can't insert into segment")
+var ErrUnknownMemoryCell = errors.New("Unknown memory cell")
+var ErrExpectedInteger = errors.New("Expected integer")
+var ErrExpectedRelocatable = errors.New("Expected relocatable")
+var ErrInconsistentMemory = errors.New("Inconsistent memory assignment")
+var ErrRelocatedAddressOutOfBounds = errors.New("Relocated address out of bounds")
+
+func UnallocatedSegmentError(segmentIndex int, numSegments uint) error {
+	err := fmt.Errorf("%w #%d; memory only has %d segment", ErrUnallocatedSegment, segmentIndex, numSegments)
+	return MemoryError(compat.WithRustMessage(err, err.Error()))
+}
+
+func UnknownMemoryCellError(addr Relocatable) error {
+	return MemoryError(fmt.Errorf("%w at address %s", ErrUnknownMemoryCell, addr.ToString()))
+}
+
+func ExpectedIntegerError(addr Relocatable) error {
+	return MemoryError(fmt.Errorf("%w at address %s", ErrExpectedInteger, addr.ToString()))
+}
+
+func ExpectedRelocatableError(addr Relocatable) error {
+	return MemoryError(fmt.Errorf("%w at address %s", ErrExpectedRelocatable, addr.ToString()))
+}
+
+func ErrMemoryWriteOnce(addr Relocatable, prevVal MaybeRelocatable, newVal MaybeRelocatable) error {
+	return MemoryError(fmt.Errorf("%w at address %s. %s != %s", ErrInconsistentMemory, addr.ToString(), prevVal.ToString(), newVal.ToString()))
+}
+
+var ErrRelocatableAdd = errors.New("can't add two relocatable values")
+var ErrRelocatableSubDiffIndex = errors.New("Can only subtract two relocatable values of the same segment")
+var ErrSubRelocatableFromInt = errors.New("can't subtract a relocatable value from an integer")
+var ErrRelocatableNegOffset = errors.New("Relocatable offset can't be negative")
+var ErrRelocatableOffsetOverflow = errors.New("Relocatable offset exceeds the maximum offset value")
+
+func RelocatableAddError(a MaybeRelocatable, b MaybeRelocatable) error {
+	return MathError(fmt.Errorf("Operation failed: %s + %s, %w", a.ToString(), b.ToString(), ErrRelocatableAdd))
+}
+
+func RelocatableSubDiffIndexError(a Relocatable, b Relocatable) error {
+	return MathError(fmt.Errorf("%w (%d != %d)", ErrRelocatableSubDiffIndex, a.SegmentIndex, b.SegmentIndex))
+}
+
+func RelocatableNegOffsetError(r Relocatable, sub uint) error {
+	return MathError(fmt.Errorf("%w: %s - %d", ErrRelocatableNegOffset, r.ToString(), sub))
+}
+
+func RelocatableOffsetOverflowError(r Relocatable, add uint) error {
+	return MathError(fmt.Errorf("%w: %s + %d", ErrRelocatableOffsetOverflow, r.ToString(), add))
+}
+
+func SubRelocatableFromIntError(a MaybeRelocatable, b MaybeRelocatable) error {
+	return MathError(fmt.Errorf("Operation failed: %s - %s, %w", a.ToString(), b.ToString(), ErrSubRelocatableFromInt))
+}
@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/pkg/errors"
+)
+
+// JSON encoding of the values of the vm shared by its serialized state (ie: checkpoints & execution scopes)
+// Addresses are encoded as [segment_index, offset], felts as decimal strings
+
+type JsonAddress [2]int
+
+func ToJsonAddress(addr Relocatable) JsonAddress {
+	return JsonAddress{addr.SegmentIndex, int(addr.Offset)}
+}
+
+func (a JsonAddress) Relocatable() (Relocatable, error) {
+	if a[1] < 0 {
+		return Relocatable{}, errors.Errorf("negative offset in address %v", a)
+	}
+	return NewRelocatable(a[0], uint(a[1])), nil
+}
+
+func FeltToJson(felt lambdaworks.Felt) string {
+	return felt.ToBigInt().String()
+}
+
+// Returns the address of relocatable values & the decimal string of felts
+func MaybeRelocatableToJson(value MaybeRelocatable) interface{} {
+	if addr, ok := value.GetRelocatable(); ok {
+		return ToJsonAddress(addr)
+	}
+	felt, _ := value.GetFelt()
+	return FeltToJson(felt)
+}
+
+// Fails for felts out of the [0, prime) range, which FeltToJson never produces
+func DecodeJsonFelt(raw json.RawMessage) (lambdaworks.Felt, error) {
+	var encoded string
+	if err := json.Unmarshal(raw, &encoded); err != nil {
+		return lambdaworks.Felt{}, err
+	}
+	n, ok := new(big.Int).SetString(encoded, 10)
+	if !ok || n.Sign() < 0 || n.Cmp(lambdaworks.Prime()) >= 0 {
+		return lambdaworks.Felt{}, errors.Errorf("invalid felt %q", encoded)
+	}
+	return lambdaworks.FeltFromBigInt(n), nil
+}
+
+func DecodeJsonRelocatable(raw json.RawMessage) (Relocatable, error) {
+	var addr JsonAddress
+	if err := json.Unmarshal(raw, &addr); err != nil {
+		return Relocatable{}, err
+	}
+	return addr.Relocatable()
+}
+
+func DecodeJsonMaybeRelocatable(raw json.RawMessage) (MaybeRelocatable, error) {
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		addr, err := DecodeJsonRelocatable(raw)
+		if err != nil {
+			return MaybeRelocatable{}, err
+		}
+		return *NewMaybeRelocatableRelocatable(addr), nil
+	}
+	felt, err := DecodeJsonFelt(raw)
+	if err != nil {
+		return MaybeRelocatable{}, err
+	}
+	return *NewMaybeRelocatableFelt(felt), nil
+}
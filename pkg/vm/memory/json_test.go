@@ -0,0 +1,37 @@
+package memory_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestMaybeRelocatableJsonRoundTrip(t *testing.T) {
+	values := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-1")),
+		*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(-1, 3)),
+	}
+	for _, value := range values {
+		raw, err := json.Marshal(memory.MaybeRelocatableToJson(value))
+		if err != nil {
+			t.Fatalf("Failed to encode %s: %s", value.ToString(), err)
+		}
+		decoded, err := memory.DecodeJsonMaybeRelocatable(raw)
+		if err != nil {
+			t.Fatalf("Failed to decode %s: %s", raw, err)
+		}
+		if decoded != value {
+			t.Errorf("Expected %s, got %s", value.ToString(), decoded.ToString())
+		}
+	}
+}
+
+func TestDecodeJsonInvalidValues(t *testing.T) {
+	for _, raw := range []string{`"-1"`, `"3618502788666131213697322783095070105623107215331596699973092056135872020481"`, `"0x1"`, `[1, -2]`} {
+		if _, err := memory.DecodeJsonMaybeRelocatable(json.RawMessage(raw)); err == nil {
+			t.Errorf("Decoding %s should fail", raw)
+		}
+	}
+}
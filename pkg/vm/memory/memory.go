@@ -10,18 +10,6 @@ import (
 // A Set to store Relocatable values
 type AddressSet map[Relocatable]bool
 
-func MemoryError(err error) error {
-	return errors.Wrapf(err, "Memory error")
-}
-
-func ErrMemoryWriteOnce(addr Relocatable, prevVal MaybeRelocatable, newVal MaybeRelocatable) error {
-	addrStr := addr.ToString()
-	prevValStr := prevVal.ToString()
-	newValStr := newVal.ToString()
-
-	return MemoryError(errors.Errorf("Memory is write-once, cannot overwrite memory value in %s. %s != %s", addrStr, prevValStr, newValStr))
-}
-
 func NewAddressSet() AddressSet {
 	return make(map[Relocatable]bool)
 }
@@ -37,42 +25,80 @@ func (set AddressSet) Contains(element Relocatable) bool {
 // A function that validates a memory address and returns a list of validated addresses
 type ValidationRule func(*Memory, Relocatable) ([]Relocatable, error)
 
+// Notified of the changes made to the memory (ie: by a vm.Tracer)
+type MemoryObserver interface {
+	// Called once value has been written at addr, rewriting the value a cell already holds is not notified
+	MemoryWritten(addr Relocatable, value MaybeRelocatable)
+	// Called once a segment has been added, with its base
+	SegmentAdded(base Relocatable)
+}
+
 // Memory represents the Cairo VM's memory.
 type Memory struct {
 	Data              map[Relocatable]MaybeRelocatable
 	numSegments       uint
 	validationRules   map[uint]ValidationRule
-	validatedAdresses AddressSet
-	// This is a map of addresses that were accessed during execution
-	// The map is of the form `segmentIndex` -> `offset`. This is to
-	// make the counting of memory holes easier
-	AccessedAddresses map[Relocatable]bool
+	validatedAdresses RelocatableMap[struct{}]
+	// Accessed cells are not counted as memory holes
+	accessedAddresses RelocatableMap[struct{}]
+	// Notified of each write & segment addition if set
+	Observer MemoryObserver
+	// Set when Data is shared with a clone, it is then copied before the next write
+	copyOnWrite bool
 }
 
 var ErrMissingSegmentUsize = errors.New("Segment effective sizes haven't been calculated")
 var ErrInsufficientAllocatedCells = errors.New("Insufficient Allocated Memory Cells")
 
 func InsufficientAllocatedCellsErrorWithBuiltinName(name string, used uint, size uint) error {
-	return fmt.Errorf("%w, builtin: %s, used: %d, size: %d", ErrInsufficientAllocatedCells, name, used, size)
+	return MemoryError(fmt.Errorf("%w, builtin: %s, used: %d, size: %d", ErrInsufficientAllocatedCells, name, used, size))
 }
 
 func InsufficientAllocatedCellsError(used uint, size uint) error {
-	return fmt.Errorf("%w, used: %d, size: %d", ErrInsufficientAllocatedCells, used, size)
+	return MemoryError(fmt.Errorf("%w, used: %d, size: %d", ErrInsufficientAllocatedCells, used, size))
 }
 
 func InsufficientAllocatedCellsErrorMinStepNotReached(minStep uint, builtinName string) error {
-	return fmt.Errorf("%w, Min Step not reached. minStep: %d, builtin: %s", ErrInsufficientAllocatedCells, minStep, builtinName)
+	return MemoryError(fmt.Errorf("%w, Min Step not reached. minStep: %d, builtin: %s", ErrInsufficientAllocatedCells, minStep, builtinName))
 }
 
 func NewMemory() *Memory {
 	return &Memory{
 		Data:              make(map[Relocatable]MaybeRelocatable),
-		validatedAdresses: NewAddressSet(),
+		validatedAdresses: NewRelocatableMap[struct{}](),
 		validationRules:   make(map[uint]ValidationRule),
-		AccessedAddresses: make(map[Relocatable]bool),
+		accessedAddresses: NewRelocatableMap[struct{}](),
 	}
 }
 
+// Makes room for the given number of new cells, so that inserting them doesn't grow the memory repeatedly
+// Maps can only be sized when created, so the cells already in memory are copied, which makes it
+// worth calling only before loading large amounts of data
+func (m *Memory) Reserve(cells uint) {
+	data := make(map[Relocatable]MaybeRelocatable, uint(len(m.Data))+cells)
+	for addr, value := range m.Data {
+		data[addr] = value
+	}
+	m.Data = data
+	m.copyOnWrite = false
+}
+
+// Returns an independent copy of the memory
+// The cells are shared with the copy until either memory is written to, at which point the writer copies them
+// The observer is not carried over to the copy
+func (m *Memory) Clone() Memory {
+	m.copyOnWrite = true
+	clone := *m
+	clone.Observer = nil
+	clone.validationRules = make(map[uint]ValidationRule, len(m.validationRules))
+	for segment, rule := range m.validationRules {
+		clone.validationRules[segment] = rule
+	}
+	clone.validatedAdresses = m.validatedAdresses.Clone()
+	clone.accessedAddresses = m.accessedAddresses.Clone()
+	return clone
+}
+
 func (m *Memory) NumSegments() uint {
 	return m.numSegments
 }
@@ -88,7 +114,7 @@ func (m *Memory) Insert(addr Relocatable, val *MaybeRelocatable) error {
 
 	// Check that insertions are preformed within the memory bounds
 	if addr.SegmentIndex >= int(m.numSegments) {
-		return errors.Errorf("Error: Inserting into a non allocated segment %s", addr.ToString())
+		return UnallocatedSegmentError(addr.SegmentIndex, m.numSegments)
 	}
 
 	// Check for possible overwrites
@@ -96,7 +122,13 @@ func (m *Memory) Insert(addr Relocatable, val *MaybeRelocatable) error {
 	if ok && prev_elem != *val {
 		return ErrMemoryWriteOnce(addr, prev_elem, *val)
 	}
+	if m.copyOnWrite {
+		m.Reserve(0)
+	}
 	m.Data[addr] = *val
+	if m.Observer != nil && !ok {
+		m.Observer.MemoryWritten(addr, *val)
+	}
 	return m.validateAddress(addr)
 }
 
@@ -117,12 +149,19 @@ func (m *Memory) Get(addr Relocatable) (*MaybeRelocatable, error) {
 	value, ok := m.Data[addr]
 
 	if !ok {
-		return nil, errors.Errorf("Memory Get: Value not found in addr: %s", addr.ToString())
+		return nil, UnknownMemoryCellError(addr)
 	}
 
 	return &value, nil
 }
 
+// Gets the value stored in the memory address `addr`, or false if it is missing
+// Unlike Get, it never allocates, which makes it suitable for the vm's hot paths
+func (m *Memory) GetValue(addr Relocatable) (MaybeRelocatable, bool) {
+	value, ok := m.Data[addr]
+	return value, ok
+}
+
 func (memory *Memory) GetSegment(segmentIndex int) []MaybeRelocatable {
 	var ret []MaybeRelocatable
 
@@ -144,7 +183,7 @@ func (m *Memory) GetFelt(addr Relocatable) (lambdaworks.Felt, error) {
 		if ok {
 			return felt, nil
 		} else {
-			return lambdaworks.FeltZero(), errors.New("Memory GetFelt: Fetched value is not a Felt")
+			return lambdaworks.FeltZero(), ExpectedIntegerError(addr)
 		}
 	}
 	return lambdaworks.FeltZero(), err
@@ -163,11 +202,64 @@ func (m *Memory) GetRange(start Relocatable, size uint) ([]MaybeRelocatable, err
 	return res, nil
 }
 
+// Compares the size values starting at lhs with the size values starting at rhs
+// Fails if a value is missing (memory gap) before the ranges are found to differ
+func (m *Memory) MemEq(lhs Relocatable, rhs Relocatable, size uint) (bool, error) {
+	for i := uint(0); i < size; i++ {
+		lhsVal, err := m.Get(lhs.AddUint(i))
+		if err != nil {
+			return false, err
+		}
+		rhsVal, err := m.Get(rhs.AddUint(i))
+		if err != nil {
+			return false, err
+		}
+		if !lhsVal.IsEqual(rhsVal) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// Gets a range of Felt memory values from start to start + size
+// Fails if any of the values inside the range is missing (memory gap), or is not a Felt
+func (m *Memory) GetFeltRange(start Relocatable, size uint) ([]lambdaworks.Felt, error) {
+	feltRange := make([]lambdaworks.Felt, 0, size)
+	for i := uint(0); i < size; i++ {
+		val, err := m.GetFelt(start.AddUint(i))
+		if err != nil {
+			return nil, err
+		}
+		feltRange = append(feltRange, val)
+	}
+	return feltRange, nil
+}
+
+// Gets a range of Relocatable memory values from start to start + size
+// Fails if any of the values inside the range is missing (memory gap), or is not a Relocatable
+func (m *Memory) GetRelocatableRange(start Relocatable, size uint) ([]Relocatable, error) {
+	relocatableRange := make([]Relocatable, 0, size)
+	for i := uint(0); i < size; i++ {
+		val, err := m.GetRelocatable(start.AddUint(i))
+		if err != nil {
+			return nil, err
+		}
+		relocatableRange = append(relocatableRange, val)
+	}
+	return relocatableRange, nil
+}
+
 // Adds a validation rule for a given segment
 func (m *Memory) AddValidationRule(SegmentIndex uint, rule ValidationRule) {
 	m.validationRules[SegmentIndex] = rule
 }
 
+// Returns true if a validation rule was added for the given segment
+func (m *Memory) HasValidationRule(segmentIndex uint) bool {
+	_, ok := m.validationRules[segmentIndex]
+	return ok
+}
+
 // Applies the validation rule for the addr's segment if any
 // Skips validation if the address is temporary or if it has been previously validated
 func (m *Memory) validateAddress(addr Relocatable) error {
@@ -183,13 +275,39 @@ func (m *Memory) validateAddress(addr Relocatable) error {
 		return err
 	}
 	for _, validated_address := range validated_addresses {
-		m.validatedAdresses.Add(validated_address)
+		m.validatedAdresses.Set(validated_address, struct{}{})
 	}
 	return nil
 }
 
 func (m *Memory) MarkAsAccessed(address Relocatable) {
-	m.AccessedAddresses[address] = true
+	m.accessedAddresses.Set(address, struct{}{})
+}
+
+// Marks the size cells starting at base as accessed
+func (m *Memory) MarkRangeAsAccessed(base Relocatable, size uint) {
+	for i := uint(0); i < size; i++ {
+		m.MarkAsAccessed(NewRelocatable(base.SegmentIndex, base.Offset+i))
+	}
+}
+
+func (m *Memory) IsAccessed(address Relocatable) bool {
+	return m.accessedAddresses.Contains(address)
+}
+
+// Returns the addresses of the accessed cells of a segment, sorted by offset
+func (m *Memory) GetAccessedAddresses(segmentIndex int) []Relocatable {
+	offsets := m.accessedAddresses.SegmentOffsets(segmentIndex)
+	addresses := make([]Relocatable, 0, len(offsets))
+	for _, offset := range offsets {
+		addresses = append(addresses, NewRelocatable(segmentIndex, offset))
+	}
+	return addresses
+}
+
+// Returns the amount of accessed cells in a segment
+func (m *Memory) CountAccessedAddresses(segmentIndex int) uint {
+	return uint(m.accessedAddresses.SegmentLen(segmentIndex))
 }
 
 // Applies validation_rules to every memory address, if applicatble
@@ -212,7 +330,7 @@ func (m *Memory) GetRelocatable(key Relocatable) (Relocatable, error) {
 
 	ret, isRelocatable := memoryValue.GetRelocatable()
 	if !isRelocatable {
-		return Relocatable{}, errors.Errorf("Expected Relocatable value in memory at address (%d, %d)", key.SegmentIndex, key.Offset)
+		return Relocatable{}, ExpectedRelocatableError(key)
 	}
 
 	return ret, nil
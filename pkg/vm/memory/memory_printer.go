@@ -0,0 +1,97 @@
+package memory
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Configuration used when dumping the memory
+type MemoryPrinterConfig struct {
+	// Render felts in hexadecimal instead of (signed) decimal
+	Hex bool
+	// Only print the segment with this index. A nil value prints every segment
+	Segment *int
+	// First offset to print within each segment (inclusive)
+	StartOffset uint
+	// Last offset to print within each segment (exclusive). A nil value prints until the end of the segment
+	EndOffset *uint
+}
+
+// Writes the memory grouped by segment, one cell per line
+// Consecutive missing cells (holes) are collapsed into a single line
+func (m *MemorySegmentManager) PrintMemory(dest io.Writer, config MemoryPrinterConfig) error {
+	segmentSizes := make(map[int]uint)
+	for addr := range m.Memory.Data {
+		if addr.Offset+1 > segmentSizes[addr.SegmentIndex] {
+			segmentSizes[addr.SegmentIndex] = addr.Offset + 1
+		}
+	}
+	segmentIndexes := make([]int, 0, len(segmentSizes))
+	for i := 0; i < int(m.Memory.numSegments); i++ {
+		if _, ok := segmentSizes[i]; !ok {
+			segmentSizes[i] = 0
+		}
+	}
+	for index := range segmentSizes {
+		if config.Segment == nil || *config.Segment == index {
+			segmentIndexes = append(segmentIndexes, index)
+		}
+	}
+	sort.Ints(segmentIndexes)
+
+	for _, index := range segmentIndexes {
+		size := segmentSizes[index]
+		if _, err := fmt.Fprintf(dest, "Segment %d (size: %d)\n", index, size); err != nil {
+			return err
+		}
+		end := size
+		if config.EndOffset != nil && *config.EndOffset < end {
+			end = *config.EndOffset
+		}
+		holeStart := -1
+		for offset := config.StartOffset; offset < end; offset++ {
+			addr := NewRelocatable(index, offset)
+			value, ok := m.Memory.Data[addr]
+			if !ok {
+				if holeStart == -1 {
+					holeStart = int(offset)
+				}
+				continue
+			}
+			if holeStart != -1 {
+				if err := printHole(dest, index, uint(holeStart), offset); err != nil {
+					return err
+				}
+				holeStart = -1
+			}
+			if _, err := fmt.Fprintf(dest, "  %s\t%s\n", addr.ToString(), formatMemoryValue(&value, config.Hex)); err != nil {
+				return err
+			}
+		}
+		if holeStart != -1 {
+			if err := printHole(dest, index, uint(holeStart), end); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func printHole(dest io.Writer, segmentIndex int, start uint, end uint) error {
+	var err error
+	if end-start == 1 {
+		_, err = fmt.Fprintf(dest, "  {%d:%d}\t<hole>\n", segmentIndex, start)
+	} else {
+		_, err = fmt.Fprintf(dest, "  {%d:%d}..{%d:%d}\t<hole x%d>\n", segmentIndex, start, segmentIndex, end-1, end-start)
+	}
+	return err
+}
+
+func formatMemoryValue(value *MaybeRelocatable, hex bool) string {
+	felt, isFelt := value.GetFelt()
+	if isFelt && hex {
+		return felt.ToHexString()
+	}
+	return value.ToString()
+}
@@ -0,0 +1,62 @@
+package memory_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func memoryForPrinterTest() *memory.MemorySegmentManager {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	segments.AddSegment()
+	segments.AddSegment()
+	segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(10)))
+	segments.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 0)))
+	segments.Memory.Insert(memory.NewRelocatable(1, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)))
+	segments.Memory.Insert(memory.NewRelocatable(1, 2), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)))
+	segments.Memory.Insert(memory.NewRelocatable(1, 5), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(26)))
+	return &segments
+}
+
+func TestPrintMemory(t *testing.T) {
+	segments := memoryForPrinterTest()
+	var buffer bytes.Buffer
+	err := segments.PrintMemory(&buffer, memory.MemoryPrinterConfig{})
+	if err != nil {
+		t.Errorf("PrintMemory failed with error: %s", err)
+	}
+	expected := "Segment 0 (size: 2)\n" +
+		"  {0:0}\t10\n" +
+		"  {0:1}\t{1:0}\n" +
+		"Segment 1 (size: 6)\n" +
+		"  {1:0}\t1\n" +
+		"  {1:1}\t<hole>\n" +
+		"  {1:2}\t2\n" +
+		"  {1:3}..{1:4}\t<hole x2>\n" +
+		"  {1:5}\t26\n" +
+		"Segment 2 (size: 0)\n"
+	if buffer.String() != expected {
+		t.Errorf("Wrong memory dump.\nExpected:\n%s\nGot:\n%s", expected, buffer.String())
+	}
+}
+
+func TestPrintMemorySegmentAndOffsetsHex(t *testing.T) {
+	segments := memoryForPrinterTest()
+	var buffer bytes.Buffer
+	segmentIndex := 1
+	end := uint(6)
+	err := segments.PrintMemory(&buffer, memory.MemoryPrinterConfig{Hex: true, Segment: &segmentIndex, StartOffset: 2, EndOffset: &end})
+	if err != nil {
+		t.Errorf("PrintMemory failed with error: %s", err)
+	}
+	expected := "Segment 1 (size: 6)\n" +
+		"  {1:2}\t0x2\n" +
+		"  {1:3}..{1:4}\t<hole x2>\n" +
+		"  {1:5}\t0x1a\n"
+	if buffer.String() != expected {
+		t.Errorf("Wrong memory dump.\nExpected:\n%s\nGot:\n%s", expected, buffer.String())
+	}
+}
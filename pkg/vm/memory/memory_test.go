@@ -29,7 +29,7 @@ func TestMemoryInsertWithValidationRulesOk(t *testing.T) {
 	mem.AddValidationRule(0, rule_always_ok)
 
 	// Instantiate the address where we want to insert and the value.
-	// We will insert the value Int(5) in segment 1, offset 0
+	// We will insert the value Felt(5) in segment 1, offset 0
 	key := memory.NewRelocatable(0, 0)
 	val := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5))
 
@@ -58,7 +58,7 @@ func TestMemoryInsertWithValidationRulesErr(t *testing.T) {
 	mem.AddValidationRule(0, rule_always_err)
 
 	// Instantiate the address where we want to insert and the value.
-	// We will insert the value Int(5) in segment 1, offset 0
+	// We will insert the value Felt(5) in segment 1, offset 0
 	key := memory.NewRelocatable(0, 0)
 	val := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5))
 
@@ -457,3 +457,75 @@ func TestValidateMemoryForValidSignature(t *testing.T) {
 		t.Errorf("ValidateExistingMemory error in test: %s", err)
 	}
 }
+
+func TestMemoryGetUnknownCellError(t *testing.T) {
+	mem_manager := memory.NewMemorySegmentManager()
+	mem_manager.AddSegment()
+	_, err := mem_manager.Memory.Get(memory.NewRelocatable(0, 3))
+	if !errors.Is(err, memory.ErrUnknownMemoryCell) || !errors.Is(err, memory.ErrMemory) {
+		t.Errorf("Expected an ErrUnknownMemoryCell memory error, got: %v", err)
+	}
+	if err.Error() != "Unknown memory cell at address {0:3}" {
+		t.Errorf("Wrong error message: %s", err)
+	}
+}
+
+func TestMemoryInsertInconsistentMemoryError(t *testing.T) {
+	mem_manager := memory.NewMemorySegmentManager()
+	mem_manager.AddSegment()
+	addr := memory.NewRelocatable(0, 0)
+	mem_manager.Memory.Insert(addr, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)))
+	err := mem_manager.Memory.Insert(addr, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)))
+	if !errors.Is(err, memory.ErrInconsistentMemory) || !errors.Is(err, memory.ErrMemory) {
+		t.Errorf("Expected an ErrInconsistentMemory memory error, got: %v", err)
+	}
+	if err.Error() != "Inconsistent memory assignment at address {0:0}. 1 != 2" {
+		t.Errorf("Wrong error message: %s", err)
+	}
+}
+
+func TestMemEq(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	mem := &segments.Memory
+	for i, value := range []uint64{1, 2, 1, 2, 1, 3} {
+		mem.Insert(memory.NewRelocatable(0, uint(i)), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(value)))
+	}
+	equal, err := mem.MemEq(memory.NewRelocatable(0, 0), memory.NewRelocatable(0, 2), 2)
+	if err != nil || !equal {
+		t.Errorf("Expected the ranges to be equal, got: %v, %v", equal, err)
+	}
+	equal, err = mem.MemEq(memory.NewRelocatable(0, 0), memory.NewRelocatable(0, 4), 2)
+	if err != nil || equal {
+		t.Errorf("Expected the ranges to differ, got: %v, %v", equal, err)
+	}
+	if _, err := mem.MemEq(memory.NewRelocatable(0, 0), memory.NewRelocatable(0, 6), 2); err == nil {
+		t.Error("Expected an error for a range with a missing cell")
+	}
+}
+
+func TestMemoryCloneIsCopiedOnWrite(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	shared := memory.NewRelocatable(0, 0)
+	segments.Memory.Insert(shared, memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()))
+
+	clone := segments.Memory.Clone()
+	clone.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)))
+	segments.Memory.Insert(memory.NewRelocatable(0, 2), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(3)))
+
+	if _, err := segments.Memory.Get(memory.NewRelocatable(0, 1)); err == nil {
+		t.Error("A write to the clone shouldn't reach the original")
+	}
+	if _, err := clone.Get(memory.NewRelocatable(0, 2)); err == nil {
+		t.Error("A write to the original shouldn't reach the clone")
+	}
+	for _, m := range []*memory.Memory{&segments.Memory, &clone} {
+		if value, err := m.GetFelt(shared); err != nil || value != lambdaworks.FeltOne() {
+			t.Errorf("The cells written before the clone should be kept, got: %v, %v", value, err)
+		}
+	}
+	if clone.NumSegments() != 1 {
+		t.Errorf("Wrong number of segments in the clone: %d", clone.NumSegments())
+	}
+}
@@ -1,7 +1,6 @@
 package memory
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
@@ -23,11 +22,6 @@ func NewRelocatable(segment_idx int, offset uint) Relocatable {
 
 }
 
-// Returns the index in a relocated memory (uint) from a relocatable address
-func (r *Relocatable) RelocateAddress(relocationTable *[]uint) uint {
-	return (*relocationTable)[r.SegmentIndex] + r.Offset
-}
-
 // Adds a Felt value to a Relocatable
 // Fails if the new offset exceeds the size of a uint
 func (r *Relocatable) AddFelt(other lambdaworks.Felt) (Relocatable, error) {
@@ -55,7 +49,7 @@ func (r *Relocatable) SubFelt(other lambdaworks.Felt) (Relocatable, error) {
 func (r *Relocatable) AddMaybeRelocatable(other MaybeRelocatable) (Relocatable, error) {
 	felt, ok := other.GetFelt()
 	if !ok {
-		return Relocatable{}, errors.New("Can't add two relocatable values")
+		return Relocatable{}, RelocatableAddError(*NewMaybeRelocatableRelocatable(*r), other)
 	}
 	return r.AddFelt(felt)
 }
@@ -64,7 +58,7 @@ func (r *Relocatable) AddMaybeRelocatable(other MaybeRelocatable) (Relocatable,
 // Fails if they have different segment indexes
 func (r *Relocatable) Sub(other Relocatable) (lambdaworks.Felt, error) {
 	if r.SegmentIndex != other.SegmentIndex {
-		return lambdaworks.Felt{}, errors.New("Cant subtract two relocatables with different segment indexes")
+		return lambdaworks.Felt{}, RelocatableSubDiffIndexError(*r, other)
 	}
 	return lambdaworks.FeltFromUint64(uint64(r.Offset)).Sub(lambdaworks.FeltFromUint64(uint64(other.Offset))), nil
 }
@@ -73,36 +67,52 @@ func (r *Relocatable) IsEqual(r1 *Relocatable) bool {
 	return (r.SegmentIndex == r1.SegmentIndex && r.Offset == r1.Offset)
 }
 
+// Subtracts other from the offset
+// Fails if the new offset would be negative
 func (relocatable *Relocatable) SubUint(other uint) (Relocatable, error) {
 	if relocatable.Offset < other {
-		return NewRelocatable(0, 0), &SubReloctableError{Msg: "RelocatableSubUsizeNegOffset"}
+		return NewRelocatable(0, 0), RelocatableNegOffsetError(*relocatable, other)
 	} else {
 		new_offset := relocatable.Offset - other
 		return NewRelocatable(relocatable.SegmentIndex, new_offset), nil
 	}
 }
 
+// Adds other to the offset
+// The offset wraps around if it exceeds the size of a uint, AddUintChecked can be used when other is not bounded
 func (relocatable *Relocatable) AddUint(other uint) Relocatable {
 	new_offset := relocatable.Offset + other
 	return NewRelocatable(relocatable.SegmentIndex, new_offset)
 }
 
+// Adds other to the offset
+// Fails if the new offset exceeds the size of a uint
+func (relocatable *Relocatable) AddUintChecked(other uint) (Relocatable, error) {
+	new_offset := relocatable.Offset + other
+	if new_offset < relocatable.Offset {
+		return NewRelocatable(0, 0), RelocatableOffsetOverflowError(*relocatable, other)
+	}
+	return NewRelocatable(relocatable.SegmentIndex, new_offset), nil
+}
+
+// Adds a signed offset (ie: the -3 of fp - 3)
+// Fails if the new offset would be negative or exceed the size of a uint
 func (relocatable *Relocatable) AddInt(other int) (Relocatable, error) {
 	if other > 0 {
-		return relocatable.AddUint(uint(other)), nil
+		return relocatable.AddUintChecked(uint(other))
 	}
 	return relocatable.SubUint(uint(-other))
 }
 
 // MaybeRelocatable is the type of the memory cells in the Cairo
 // VM. For now, `inner` will hold any type but it should be
-// instantiated only with `Relocatable` or `Int` types.
+// instantiated only with `Relocatable` or `lambdaworks.Felt` types.
 // We should analyze better alternatives to this.
 type MaybeRelocatable struct {
 	inner any
 }
 
-// Creates a new MaybeRelocatable with an Int inner value
+// Creates a new MaybeRelocatable with a Felt inner value
 func NewMaybeRelocatableFelt(felt lambdaworks.Felt) *MaybeRelocatable {
 	return &MaybeRelocatable{inner: felt}
 }
@@ -129,23 +139,6 @@ func (m *MaybeRelocatable) IsZero() bool {
 	return is_int && felt.IsZero()
 }
 
-// Turns a MaybeRelocatable into a Felt252 value.
-// If the inner value is an Int, it will extract the Felt252 value from it.
-// If the inner value is a Relocatable, it will relocate it according to the relocation_table
-func (m *MaybeRelocatable) RelocateValue(relocationTable *[]uint) (lambdaworks.Felt, error) {
-	inner_felt, ok := m.GetFelt()
-	if ok {
-		return inner_felt, nil
-	}
-
-	inner_relocatable, ok := m.GetRelocatable()
-	if ok {
-		return lambdaworks.FeltFromUint64(uint64(inner_relocatable.RelocateAddress(relocationTable))), nil
-	}
-
-	return lambdaworks.FeltZero(), errors.New(fmt.Sprintf("Unexpected type %T", m.inner))
-}
-
 func (m *MaybeRelocatable) IsEqual(m1 *MaybeRelocatable) bool {
 	a, a_type := m.GetFelt()
 	b, b_type := m1.GetFelt()
@@ -193,7 +186,7 @@ func (m MaybeRelocatable) Add(other MaybeRelocatable) (MaybeRelocatable, error)
 		}
 		return *NewMaybeRelocatableRelocatable(relocatable), nil
 	} else {
-		return *NewMaybeRelocatableFelt(lambdaworks.FeltZero()), errors.New("RelocatableAdd")
+		return *NewMaybeRelocatableFelt(lambdaworks.FeltZero()), RelocatableAddError(m, other)
 	}
 }
 
@@ -229,7 +222,7 @@ func (m MaybeRelocatable) Sub(other MaybeRelocatable) (MaybeRelocatable, error)
 		return *NewMaybeRelocatableFelt(res), err
 
 	} else {
-		return *NewMaybeRelocatableFelt(lambdaworks.FeltZero()), errors.New("Cant sub Relocatable from Felt")
+		return *NewMaybeRelocatableFelt(lambdaworks.FeltZero()), SubRelocatableFromIntError(m, other)
 	}
 }
 
@@ -245,3 +238,31 @@ func (m *MaybeRelocatable) ToString() string {
 func (r *Relocatable) ToString() string {
 	return fmt.Sprintf("{%d:%d}", r.SegmentIndex, r.Offset)
 }
+
+// Formats the address as the Rust vm does in its error messages (ie: 1:2), see compat.Message
+func (r Relocatable) RustString() string {
+	return fmt.Sprintf("%d:%d", r.SegmentIndex, r.Offset)
+}
+
+// Formats the value as the Rust vm does in its error messages: felts as their canonical value in decimal
+func (m MaybeRelocatable) RustString() string {
+	if rel, ok := m.GetRelocatable(); ok {
+		return rel.RustString()
+	}
+	felt, _ := m.GetFelt()
+	return felt.ToBigInt().String()
+}
+
+// Implements fmt.Stringer, so that addresses can be formatted with %s & %v
+// Addresses are formatted as {2:15}, as ToString does, so that %s matches the error messages that predate it.
+// Use RustString for the bare 2:15 form of the Rust vm
+func (r Relocatable) String() string {
+	return r.ToString()
+}
+
+// Implements fmt.Stringer, so that values can be formatted with %s & %v
+// Felts are formatted as signed integers & relocatables as addresses ({2:15}, see Relocatable.String)
+// Use RustString for the formatting of the Rust vm
+func (m MaybeRelocatable) String() string {
+	return m.ToString()
+}
@@ -0,0 +1,193 @@
+package memory
+
+import "sort"
+
+// Offsets further than this from the filled part of their segment are kept in the sparse map,
+// so that a single distant address doesn't allocate the whole gap
+const relocatableMapMaxGap = 1 << 16
+
+// Map keyed by Relocatable, that keeps the values of each segment in a slice indexed by offset
+// Segments are usually filled from offset 0 onwards, which makes it cheaper than a map[Relocatable]V on the
+// hot paths (ie: validated & accessed addresses, builtin caches)
+// Addresses of temporary segments & offsets far from the filled part of their segment are kept in a regular map
+// The zero value is an empty map ready to use
+type RelocatableMap[V any] struct {
+	segments [][]relocatableMapEntry[V]
+	// Amount of values stored in each segment, including its sparse ones
+	segmentLens []int
+	sparse      map[Relocatable]V
+	len         int
+}
+
+type relocatableMapEntry[V any] struct {
+	value V
+	ok    bool
+}
+
+func NewRelocatableMap[V any]() RelocatableMap[V] {
+	return RelocatableMap[V]{}
+}
+
+// Returns the value stored at addr, if any
+func (m *RelocatableMap[V]) Get(addr Relocatable) (V, bool) {
+	if addr.SegmentIndex >= 0 && addr.SegmentIndex < len(m.segments) {
+		segment := m.segments[addr.SegmentIndex]
+		if addr.Offset < uint(len(segment)) {
+			entry := segment[addr.Offset]
+			if entry.ok {
+				return entry.value, true
+			}
+		}
+	}
+	value, ok := m.sparse[addr]
+	return value, ok
+}
+
+func (m *RelocatableMap[V]) Contains(addr Relocatable) bool {
+	_, ok := m.Get(addr)
+	return ok
+}
+
+// Stores value at addr, replacing the previous value if any
+func (m *RelocatableMap[V]) Set(addr Relocatable, value V) {
+	if addr.SegmentIndex < 0 {
+		m.setSparse(addr, value)
+		return
+	}
+	for len(m.segments) <= addr.SegmentIndex {
+		m.segments = append(m.segments, nil)
+		m.segmentLens = append(m.segmentLens, 0)
+	}
+	segment := m.segments[addr.SegmentIndex]
+	if addr.Offset >= uint(len(segment)) {
+		if addr.Offset-uint(len(segment)) > relocatableMapMaxGap {
+			m.setSparse(addr, value)
+			return
+		}
+		segment = append(segment, make([]relocatableMapEntry[V], addr.Offset+1-uint(len(segment)))...)
+		m.segments[addr.SegmentIndex] = segment
+	}
+	entry := &segment[addr.Offset]
+	if !entry.ok {
+		if _, ok := m.sparse[addr]; ok {
+			// The segment grew over a sparse address, move it to the segment
+			delete(m.sparse, addr)
+		} else {
+			m.len++
+			m.segmentLens[addr.SegmentIndex]++
+		}
+	}
+	*entry = relocatableMapEntry[V]{value: value, ok: true}
+}
+
+func (m *RelocatableMap[V]) setSparse(addr Relocatable, value V) {
+	if m.sparse == nil {
+		m.sparse = make(map[Relocatable]V)
+	}
+	if _, ok := m.sparse[addr]; !ok {
+		m.len++
+		if addr.SegmentIndex >= 0 {
+			m.segmentLens[addr.SegmentIndex]++
+		}
+	}
+	m.sparse[addr] = value
+}
+
+// Removes the value stored at addr, if any
+func (m *RelocatableMap[V]) Delete(addr Relocatable) {
+	if addr.SegmentIndex >= 0 && addr.SegmentIndex < len(m.segments) {
+		segment := m.segments[addr.SegmentIndex]
+		if addr.Offset < uint(len(segment)) && segment[addr.Offset].ok {
+			segment[addr.Offset] = relocatableMapEntry[V]{}
+			m.len--
+			m.segmentLens[addr.SegmentIndex]--
+			return
+		}
+	}
+	if _, ok := m.sparse[addr]; ok {
+		delete(m.sparse, addr)
+		m.len--
+		if addr.SegmentIndex >= 0 {
+			m.segmentLens[addr.SegmentIndex]--
+		}
+	}
+}
+
+// Amount of values stored
+func (m *RelocatableMap[V]) Len() int {
+	return m.len
+}
+
+// Amount of values stored in a segment
+func (m *RelocatableMap[V]) SegmentLen(segmentIndex int) int {
+	if segmentIndex < 0 {
+		count := 0
+		for addr := range m.sparse {
+			if addr.SegmentIndex == segmentIndex {
+				count++
+			}
+		}
+		return count
+	}
+	if segmentIndex >= len(m.segmentLens) {
+		return 0
+	}
+	return m.segmentLens[segmentIndex]
+}
+
+// Returns the offsets with a value stored in a segment, sorted
+func (m *RelocatableMap[V]) SegmentOffsets(segmentIndex int) []uint {
+	offsets := make([]uint, 0, m.SegmentLen(segmentIndex))
+	if segmentIndex >= 0 && segmentIndex < len(m.segments) {
+		for offset, entry := range m.segments[segmentIndex] {
+			if entry.ok {
+				offsets = append(offsets, uint(offset))
+			}
+		}
+	}
+	sorted := len(offsets)
+	for addr := range m.sparse {
+		if addr.SegmentIndex == segmentIndex {
+			offsets = append(offsets, addr.Offset)
+		}
+	}
+	if len(offsets) != sorted {
+		sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+	}
+	return offsets
+}
+
+// Calls f with each address & its value, in no particular order, until f returns false
+func (m *RelocatableMap[V]) Range(f func(addr Relocatable, value V) bool) {
+	for segmentIndex, segment := range m.segments {
+		for offset, entry := range segment {
+			if entry.ok && !f(NewRelocatable(segmentIndex, uint(offset)), entry.value) {
+				return
+			}
+		}
+	}
+	for addr, value := range m.sparse {
+		if !f(addr, value) {
+			return
+		}
+	}
+}
+
+// Returns an independent copy of the map
+func (m *RelocatableMap[V]) Clone() RelocatableMap[V] {
+	clone := RelocatableMap[V]{
+		segments:    make([][]relocatableMapEntry[V], len(m.segments)),
+		segmentLens: append([]int(nil), m.segmentLens...),
+		len:         m.len,
+	}
+	for i, segment := range m.segments {
+		clone.segments[i] = append([]relocatableMapEntry[V](nil), segment...)
+	}
+	if m.sparse != nil {
+		clone.sparse = make(map[Relocatable]V, len(m.sparse))
+		for addr, value := range m.sparse {
+			clone.sparse[addr] = value
+		}
+	}
+	return clone
+}
@@ -0,0 +1,97 @@
+package memory_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestRelocatableMapSetGet(t *testing.T) {
+	m := memory.NewRelocatableMap[int]()
+	addresses := []memory.Relocatable{
+		memory.NewRelocatable(0, 0),
+		memory.NewRelocatable(0, 5),
+		memory.NewRelocatable(2, 1),
+		memory.NewRelocatable(-1, 3),
+		// Far from the filled part of its segment, stored in the sparse map
+		memory.NewRelocatable(2, 1<<40),
+	}
+	for i, addr := range addresses {
+		m.Set(addr, i)
+	}
+	m.Set(addresses[1], 10)
+
+	for i, addr := range addresses {
+		expected := i
+		if i == 1 {
+			expected = 10
+		}
+		if value, ok := m.Get(addr); !ok || value != expected {
+			t.Errorf("Wrong value at %v: expected %d, got %d (%v)", addr, expected, value, ok)
+		}
+	}
+	if m.Contains(memory.NewRelocatable(0, 1)) || m.Contains(memory.NewRelocatable(1, 0)) || m.Contains(memory.NewRelocatable(3, 0)) {
+		t.Error("The map contains addresses that weren't set")
+	}
+	if m.Len() != 5 || m.SegmentLen(0) != 2 || m.SegmentLen(1) != 0 || m.SegmentLen(2) != 2 || m.SegmentLen(-1) != 1 {
+		t.Errorf("Wrong lengths: %d, %d, %d, %d, %d", m.Len(), m.SegmentLen(0), m.SegmentLen(1), m.SegmentLen(2), m.SegmentLen(-1))
+	}
+	if offsets := m.SegmentOffsets(2); !reflect.DeepEqual(offsets, []uint{1, 1 << 40}) {
+		t.Errorf("Wrong offsets: %v", offsets)
+	}
+}
+
+func TestRelocatableMapSegmentGrowsOverSparseAddress(t *testing.T) {
+	var m memory.RelocatableMap[bool]
+	far := memory.NewRelocatable(0, 1<<17)
+	m.Set(far, true)
+	// Fill the segment up to the sparse address so that it's covered by the segment's slice
+	for offset := uint(0); offset < far.Offset; offset += 1 << 15 {
+		m.Set(memory.NewRelocatable(0, offset), false)
+	}
+	m.Set(far, false)
+	m.Set(far.AddUint(1), true)
+
+	if m.Len() != 6 || m.SegmentLen(0) != 6 {
+		t.Errorf("Wrong lengths: %d, %d", m.Len(), m.SegmentLen(0))
+	}
+	if value, ok := m.Get(far); !ok || value {
+		t.Errorf("Wrong value at %v: %v (%v)", far, value, ok)
+	}
+	if offsets := m.SegmentOffsets(0); len(offsets) != 6 || offsets[4] != far.Offset {
+		t.Errorf("Wrong offsets: %v", offsets)
+	}
+}
+
+func TestRelocatableMapDelete(t *testing.T) {
+	m := memory.NewRelocatableMap[int]()
+	dense, sparse := memory.NewRelocatable(0, 1), memory.NewRelocatable(0, 1<<20)
+	m.Set(dense, 1)
+	m.Set(sparse, 2)
+	m.Delete(dense)
+	m.Delete(sparse)
+	m.Delete(memory.NewRelocatable(4, 0))
+	if m.Contains(dense) || m.Contains(sparse) || m.Len() != 0 || m.SegmentLen(0) != 0 {
+		t.Errorf("The map should be empty, len: %d", m.Len())
+	}
+}
+
+func TestRelocatableMapCloneAndRange(t *testing.T) {
+	m := memory.NewRelocatableMap[int]()
+	m.Set(memory.NewRelocatable(1, 2), 3)
+	m.Set(memory.NewRelocatable(-2, 0), 4)
+	clone := m.Clone()
+	m.Set(memory.NewRelocatable(1, 2), 5)
+	m.Set(memory.NewRelocatable(1, 3), 6)
+
+	values := make(map[memory.Relocatable]int)
+	clone.Range(func(addr memory.Relocatable, value int) bool {
+		values[addr] = value
+		return true
+	})
+	expected := map[memory.Relocatable]int{memory.NewRelocatable(1, 2): 3, memory.NewRelocatable(-2, 0): 4}
+	if !reflect.DeepEqual(values, expected) || clone.Len() != 2 {
+		t.Errorf("Wrong clone contents: %v", values)
+	}
+}
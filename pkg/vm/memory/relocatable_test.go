@@ -1,6 +1,9 @@
 package memory_test
 
 import (
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
 	"testing"
 
@@ -257,3 +260,56 @@ func TestRelocatableAddIntNegative(t *testing.T) {
 		t.Errorf("got wrong value from Relocatable.AddInt, expected: %v, got: %v", expected, res)
 	}
 }
+
+func TestRelocatableAddIntNegativeOffset(t *testing.T) {
+	rel := memory.NewRelocatable(1, 2)
+	_, err := rel.AddInt(-3)
+	if !errors.Is(err, memory.ErrRelocatableNegOffset) || !errors.Is(err, memory.ErrMath) {
+		t.Errorf("Expected an ErrRelocatableNegOffset math error, got: %v", err)
+	}
+	if err.Error() != "Relocatable offset can't be negative: {1:2} - 3" {
+		t.Errorf("Wrong error message: %s", err)
+	}
+}
+
+func TestRelocatableAddUintCheckedOverflow(t *testing.T) {
+	rel := memory.NewRelocatable(1, 2)
+	_, err := rel.AddUintChecked(math.MaxUint - 1)
+	if !errors.Is(err, memory.ErrRelocatableOffsetOverflow) {
+		t.Errorf("Expected ErrRelocatableOffsetOverflow, got: %v", err)
+	}
+	_, err = rel.AddInt(math.MaxInt)
+	if err != nil {
+		t.Errorf("AddInt failed with error: %s", err)
+	}
+
+	res, err := rel.AddUintChecked(math.MaxUint - 2)
+	if err != nil || res != memory.NewRelocatable(1, math.MaxUint) {
+		t.Errorf("Wrong AddUintChecked result: %s, err: %v", res, err)
+	}
+}
+
+func TestRelocatableSubDiffIndexError(t *testing.T) {
+	a := memory.NewRelocatable(1, 4)
+	_, err := a.Sub(memory.NewRelocatable(2, 1))
+	if !errors.Is(err, memory.ErrRelocatableSubDiffIndex) || !errors.Is(err, memory.ErrMath) {
+		t.Errorf("Expected an ErrRelocatableSubDiffIndex math error, got: %v", err)
+	}
+	if err.Error() != "Can only subtract two relocatable values of the same segment (1 != 2)" {
+		t.Errorf("Wrong error message: %s", err)
+	}
+}
+
+func TestRelocatableAndMaybeRelocatableString(t *testing.T) {
+	rel := memory.NewRelocatable(2, 15)
+	if fmt.Sprintf("%s %v", rel, &rel) != "{2:15} {2:15}" {
+		t.Errorf("Wrong Relocatable formatting: %s %v", rel, &rel)
+	}
+	values := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableRelocatable(rel),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-3")),
+	}
+	if fmt.Sprintf("%v", values) != "[{2:15} -3]" {
+		t.Errorf("Wrong MaybeRelocatable formatting: %v", values)
+	}
+}
@@ -0,0 +1,124 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+)
+
+// Relocated memories are almost dense, so Insert only grows the address space up to
+// RelocatedMemoryMaxHolesPerCell addresses per written cell (and at least up to RelocatedMemoryMinAddressSpace).
+// This keeps a single huge address (ie: read from a corrupt file) from allocating an unbounded address space
+const (
+	RelocatedMemoryMaxHolesPerCell = 16
+	RelocatedMemoryMinAddressSpace = 1 << 20
+)
+
+// Memory relocated into a single address space, as a list of cells indexed by address
+// Addresses that were never written (memory holes, address 0 & the space after the last cell) are kept as holes,
+// so that exporting the memory only encodes the written cells, as the Rust vm does
+type RelocatedMemory struct {
+	values  []lambdaworks.Felt
+	written []bool
+	cells   int
+}
+
+// Creates an empty relocated memory with room for addresses up to size - 1
+func NewRelocatedMemory(size uint) RelocatedMemory {
+	return RelocatedMemory{values: make([]lambdaworks.Felt, size), written: make([]bool, size)}
+}
+
+// Creates a relocated memory holding the given cells, indexed by address
+// The address space is sized after the highest address without the bound applied by Insert, as the cells are
+// already held by the caller. Addresses read from untrusted sources should go through Insert instead
+func RelocatedMemoryFromMap(cells map[uint]lambdaworks.Felt) RelocatedMemory {
+	size := uint(0)
+	for addr := range cells {
+		if addr >= size {
+			size = addr + 1
+		}
+	}
+	m := NewRelocatedMemory(size)
+	for addr, value := range cells {
+		m.values[addr] = value
+		m.written[addr] = true
+	}
+	m.cells = len(cells)
+	return m
+}
+
+// Returns the size up to which Insert can grow the address space
+func (m *RelocatedMemory) maxAddressSpace() uint {
+	size := uint(m.cells+1) * RelocatedMemoryMaxHolesPerCell
+	if size < RelocatedMemoryMinAddressSpace {
+		return RelocatedMemoryMinAddressSpace
+	}
+	return size
+}
+
+// Writes the value at addr, growing the address space if needed
+// Fails if growing the address space up to addr would leave too many holes (see RelocatedMemoryMaxHolesPerCell)
+func (m *RelocatedMemory) Insert(addr uint, value lambdaworks.Felt) error {
+	if addr >= uint(len(m.values)) {
+		if addr >= m.maxAddressSpace() {
+			return MemoryError(fmt.Errorf("%w: address %d exceeds the maximum address space of %d for %d cells", ErrRelocatedAddressOutOfBounds, addr, m.maxAddressSpace(), m.cells))
+		}
+		m.values = append(m.values, make([]lambdaworks.Felt, addr+1-uint(len(m.values)))...)
+		m.written = append(m.written, make([]bool, addr+1-uint(len(m.written)))...)
+	}
+	if !m.written[addr] {
+		m.cells++
+	}
+	m.values[addr] = value
+	m.written[addr] = true
+	return nil
+}
+
+// Returns the value at addr, or false if addr is a hole
+func (m *RelocatedMemory) Get(addr uint) (lambdaworks.Felt, bool) {
+	if addr >= uint(len(m.values)) || !m.written[addr] {
+		return lambdaworks.Felt{}, false
+	}
+	return m.values[addr], true
+}
+
+// Returns the size of the address space, including the holes
+func (m *RelocatedMemory) Len() uint {
+	return uint(len(m.values))
+}
+
+// Returns the number of written cells
+func (m *RelocatedMemory) NumCells() int {
+	return m.cells
+}
+
+// Calls fn with each written cell, by ascending address
+func (m *RelocatedMemory) ForEach(fn func(addr uint, value lambdaworks.Felt) error) error {
+	for addr := range m.values {
+		if !m.written[addr] {
+			continue
+		}
+		if err := fn(uint(addr), m.values[addr]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the written cells indexed by address
+func (m *RelocatedMemory) ToMap() map[uint]lambdaworks.Felt {
+	cells := make(map[uint]lambdaworks.Felt, m.cells)
+	m.ForEach(func(addr uint, value lambdaworks.Felt) error {
+		cells[addr] = value
+		return nil
+	})
+	return cells
+}
+
+func (m *RelocatedMemory) Clone() RelocatedMemory {
+	return RelocatedMemory{
+		values:  append([]lambdaworks.Felt(nil), m.values...),
+		written: append([]bool(nil), m.written...),
+		cells:   m.cells,
+	}
+}
@@ -0,0 +1,53 @@
+package memory_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestRelocatedMemoryInsertKeepsHoles(t *testing.T) {
+	var relocatedMemory memory.RelocatedMemory
+	for _, addr := range []uint{1, 2, 6} {
+		if err := relocatedMemory.Insert(addr, lambdaworks.FeltFromUint64(uint64(addr))); err != nil {
+			t.Fatalf("Insert failed with error: %s", err)
+		}
+	}
+	if relocatedMemory.Len() != 7 || relocatedMemory.NumCells() != 3 {
+		t.Errorf("Wrong sizes, got Len %d & NumCells %d", relocatedMemory.Len(), relocatedMemory.NumCells())
+	}
+	if _, ok := relocatedMemory.Get(4); ok {
+		t.Errorf("Address 4 should be a hole")
+	}
+	if value, ok := relocatedMemory.Get(6); !ok || value != lambdaworks.FeltFromUint64(6) {
+		t.Errorf("Wrong value at address 6: %v", value)
+	}
+}
+
+func TestRelocatedMemoryInsertHugeAddress(t *testing.T) {
+	var relocatedMemory memory.RelocatedMemory
+	err := relocatedMemory.Insert(1<<40, lambdaworks.FeltOne())
+	if !errors.Is(err, memory.ErrRelocatedAddressOutOfBounds) || !errors.Is(err, memory.ErrMemory) {
+		t.Fatalf("Expected ErrRelocatedAddressOutOfBounds, got %v", err)
+	}
+	if relocatedMemory.Len() != 0 || relocatedMemory.NumCells() != 0 {
+		t.Errorf("A rejected insert shouldn't grow the memory")
+	}
+}
+
+func TestRelocatedMemoryInsertBoundGrowsWithCells(t *testing.T) {
+	var relocatedMemory memory.RelocatedMemory
+	last := uint(memory.RelocatedMemoryMinAddressSpace - 1)
+	if err := relocatedMemory.Insert(last, lambdaworks.FeltOne()); err != nil {
+		t.Fatalf("Insert within the minimum address space failed with error: %s", err)
+	}
+	if err := relocatedMemory.Insert(memory.RelocatedMemoryMinAddressSpace, lambdaworks.FeltOne()); !errors.Is(err, memory.ErrRelocatedAddressOutOfBounds) {
+		t.Fatalf("Expected ErrRelocatedAddressOutOfBounds, got %v", err)
+	}
+	// Addresses already in the address space can always be written
+	if err := relocatedMemory.Insert(last-1, lambdaworks.FeltOne()); err != nil {
+		t.Errorf("Insert within the address space failed with error: %s", err)
+	}
+}
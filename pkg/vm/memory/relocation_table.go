@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"fmt"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/pkg/errors"
+)
+
+var ErrMissingRelocation = errors.New("No relocation found for segment")
+var ErrInvalidRelocationTable = errors.New("Invalid relocation table")
+
+func MissingRelocationError(segmentIndex int) error {
+	return MemoryError(fmt.Errorf("%w %d", ErrMissingRelocation, segmentIndex))
+}
+
+func InvalidRelocationTableError(format string, args ...any) error {
+	return MemoryError(errors.Wrapf(ErrInvalidRelocationTable, format, args...))
+}
+
+// Maps the addresses of the segments to the contiguous addresses of the relocated memory, which start at 1
+// Temporary segments (negative indexes) are relocated through the rules mapping them into a real segment
+type RelocationTable struct {
+	bases          []uint
+	temporaryRules map[int]Relocatable
+}
+
+// Creates a table from the relocated address of the first cell of each segment
+// The bases can't be lower than 1 nor decrease from one segment to the next
+func NewRelocationTable(bases []uint) (RelocationTable, error) {
+	for i, base := range bases {
+		if base == 0 {
+			return RelocationTable{}, InvalidRelocationTableError("the base of segment %d is 0, relocated addresses start at 1", i)
+		}
+		if i > 0 && base < bases[i-1] {
+			return RelocationTable{}, InvalidRelocationTableError("the base of segment %d (%d) is lower than the one of segment %d (%d)", i, base, i-1, bases[i-1])
+		}
+	}
+	return RelocationTable{bases: append([]uint(nil), bases...)}, nil
+}
+
+// Returns the number of segments the table relocates, not counting the temporary ones
+func (t RelocationTable) Len() int {
+	return len(t.bases)
+}
+
+// Returns the relocated address of the first cell of each segment
+func (t RelocationTable) Bases() []uint {
+	return append([]uint(nil), t.bases...)
+}
+
+// Fails if the table doesn't relocate every one of the first numSegments segments
+func (t RelocationTable) CheckCoverage(numSegments uint) error {
+	if uint(len(t.bases)) < numSegments {
+		return MissingRelocationError(len(t.bases))
+	}
+	return nil
+}
+
+// Returns the relocated address of the first cell of the segment
+func (t RelocationTable) SegmentBase(segmentIndex int) (uint, error) {
+	if segmentIndex < 0 {
+		return t.Relocate(NewRelocatable(segmentIndex, 0))
+	}
+	if segmentIndex >= len(t.bases) {
+		return 0, MissingRelocationError(segmentIndex)
+	}
+	return t.bases[segmentIndex], nil
+}
+
+// Relocates the temporary segment to dest, so that its cell at offset i is relocated as dest + i
+func (t *RelocationTable) AddTemporaryRule(segmentIndex int, dest Relocatable) error {
+	if segmentIndex >= 0 {
+		return InvalidRelocationTableError("segment %d is not a temporary segment", segmentIndex)
+	}
+	if dest.SegmentIndex < 0 || dest.SegmentIndex >= len(t.bases) {
+		return MissingRelocationError(dest.SegmentIndex)
+	}
+	if _, ok := t.temporaryRules[segmentIndex]; ok {
+		return InvalidRelocationTableError("segment %d already has a relocation rule", segmentIndex)
+	}
+	// The rules are copied as tables are passed by value, so that the copies of the table are left unchanged
+	rules := make(map[int]Relocatable, len(t.temporaryRules)+1)
+	for index, rule := range t.temporaryRules {
+		rules[index] = rule
+	}
+	rules[segmentIndex] = dest
+	t.temporaryRules = rules
+	return nil
+}
+
+// Returns the address of the relocated memory the address is relocated to
+func (t RelocationTable) Relocate(addr Relocatable) (uint, error) {
+	if addr.SegmentIndex < 0 {
+		dest, ok := t.temporaryRules[addr.SegmentIndex]
+		if !ok {
+			return 0, MissingRelocationError(addr.SegmentIndex)
+		}
+		addr = dest.AddUint(addr.Offset)
+	}
+	base, err := t.SegmentBase(addr.SegmentIndex)
+	if err != nil {
+		return 0, err
+	}
+	return base + addr.Offset, nil
+}
+
+// Returns felts as they are & relocatable values as their relocated address
+func (t RelocationTable) RelocateValue(value MaybeRelocatable) (lambdaworks.Felt, error) {
+	addr, ok := value.GetRelocatable()
+	if !ok {
+		felt, _ := value.GetFelt()
+		return felt, nil
+	}
+	relocated, err := t.Relocate(addr)
+	if err != nil {
+		return lambdaworks.FeltZero(), err
+	}
+	return lambdaworks.FeltFromUint64(uint64(relocated)), nil
+}
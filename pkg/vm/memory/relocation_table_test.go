@@ -0,0 +1,82 @@
+package memory_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestNewRelocationTableInvalid(t *testing.T) {
+	for _, bases := range [][]uint{{0, 3}, {1, 5, 4}} {
+		if _, err := memory.NewRelocationTable(bases); !errors.Is(err, memory.ErrInvalidRelocationTable) || !errors.Is(err, memory.ErrMemory) {
+			t.Errorf("Expected ErrInvalidRelocationTable for %v, got %v", bases, err)
+		}
+	}
+}
+
+func TestRelocationTableRelocate(t *testing.T) {
+	table, err := memory.NewRelocationTable([]uint{1, 4, 4, 10})
+	if err != nil {
+		t.Fatalf("NewRelocationTable failed with error: %s", err)
+	}
+	if addr, err := table.Relocate(memory.NewRelocatable(3, 2)); err != nil || addr != 12 {
+		t.Errorf("Wrong relocation of (3,2): %d, %v", addr, err)
+	}
+	value, err := table.RelocateValue(*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 1)))
+	if err != nil || value != lambdaworks.FeltFromUint64(5) {
+		t.Errorf("Wrong relocation of the value (1,1): %s, %v", value.ToSignedFeltString(), err)
+	}
+	value, err = table.RelocateValue(*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(7)))
+	if err != nil || value != lambdaworks.FeltFromUint64(7) {
+		t.Errorf("Felts should be left unchanged: %s, %v", value.ToSignedFeltString(), err)
+	}
+
+	// Segments the table doesn't cover are reported instead of panicking
+	if _, err := table.Relocate(memory.NewRelocatable(4, 0)); !errors.Is(err, memory.ErrMissingRelocation) {
+		t.Errorf("Expected ErrMissingRelocation, got %v", err)
+	}
+	if err := table.CheckCoverage(5); !errors.Is(err, memory.ErrMissingRelocation) {
+		t.Errorf("Expected ErrMissingRelocation, got %v", err)
+	}
+}
+
+func TestRelocationTableTemporarySegments(t *testing.T) {
+	table, _ := memory.NewRelocationTable([]uint{1, 4})
+	if _, err := table.Relocate(memory.NewRelocatable(-1, 2)); !errors.Is(err, memory.ErrMissingRelocation) {
+		t.Errorf("Expected ErrMissingRelocation, got %v", err)
+	}
+
+	copied := table
+	if err := table.AddTemporaryRule(-1, memory.NewRelocatable(1, 3)); err != nil {
+		t.Fatalf("AddTemporaryRule failed with error: %s", err)
+	}
+	if addr, err := table.Relocate(memory.NewRelocatable(-1, 2)); err != nil || addr != 9 {
+		t.Errorf("Wrong relocation of (-1,2): %d, %v", addr, err)
+	}
+	if _, err := copied.Relocate(memory.NewRelocatable(-1, 2)); err == nil {
+		t.Error("Adding a rule shouldn't change the copies of the table")
+	}
+
+	if err := table.AddTemporaryRule(-1, memory.NewRelocatable(0, 0)); !errors.Is(err, memory.ErrInvalidRelocationTable) {
+		t.Errorf("Expected ErrInvalidRelocationTable for a second rule, got %v", err)
+	}
+	if err := table.AddTemporaryRule(1, memory.NewRelocatable(0, 0)); !errors.Is(err, memory.ErrInvalidRelocationTable) {
+		t.Errorf("Expected ErrInvalidRelocationTable for a real segment, got %v", err)
+	}
+	if err := table.AddTemporaryRule(-2, memory.NewRelocatable(2, 0)); !errors.Is(err, memory.ErrMissingRelocation) {
+		t.Errorf("Expected ErrMissingRelocation for an unknown destination, got %v", err)
+	}
+}
+
+func TestRelocateMemoryMissingSegment(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	segments.AddSegment()
+	segments.Memory.Insert(memory.NewRelocatable(1, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()))
+	table, _ := memory.NewRelocationTable([]uint{1})
+	if _, err := segments.RelocateMemory(table); !errors.Is(err, memory.ErrMissingRelocation) {
+		t.Errorf("Expected ErrMissingRelocation, got %v", err)
+	}
+}
@@ -2,8 +2,10 @@ package memory
 
 import (
 	"errors"
+	"sort"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parallel"
 )
 
 // MemorySegmentManager manages the list of memory segments.
@@ -24,13 +26,42 @@ func NewMemorySegmentManager() MemorySegmentManager {
 	return MemorySegmentManager{make(map[uint]uint), make(map[uint]uint), *memory, make(map[uint][]uint)}
 }
 
+// Returns an independent copy of the segment manager, its memory is cloned through Memory.Clone
+func (m *MemorySegmentManager) Clone() MemorySegmentManager {
+	clone := MemorySegmentManager{
+		SegmentUsedSizes:    make(map[uint]uint, len(m.SegmentUsedSizes)),
+		SegmentSizes:        make(map[uint]uint, len(m.SegmentSizes)),
+		Memory:              m.Memory.Clone(),
+		PublicMemoryOffsets: make(map[uint][]uint, len(m.PublicMemoryOffsets)),
+	}
+	for segment, size := range m.SegmentUsedSizes {
+		clone.SegmentUsedSizes[segment] = size
+	}
+	for segment, size := range m.SegmentSizes {
+		clone.SegmentSizes[segment] = size
+	}
+	for segment, offsets := range m.PublicMemoryOffsets {
+		clone.PublicMemoryOffsets[segment] = append([]uint(nil), offsets...)
+	}
+	return clone
+}
+
 // Adds a memory segment and returns the first address of the new segment
 func (m *MemorySegmentManager) AddSegment() Relocatable {
 	ptr := Relocatable{int(m.Memory.numSegments), 0}
 	m.Memory.numSegments += 1
+	if m.Memory.Observer != nil {
+		m.Memory.Observer.SegmentAdded(ptr)
+	}
 	return ptr
 }
 
+// Adds a memory segment expected to hold sizeHint cells, reserving room for them in the memory
+func (m *MemorySegmentManager) AddSegmentWithSizeHint(sizeHint uint) Relocatable {
+	m.Memory.Reserve(sizeHint)
+	return m.AddSegment()
+}
+
 // Calculates the size of each memory segment.
 func (m *MemorySegmentManager) ComputeEffectiveSizes() map[uint]uint {
 	if len(m.SegmentUsedSizes) == 0 {
@@ -47,59 +78,99 @@ func (m *MemorySegmentManager) ComputeEffectiveSizes() map[uint]uint {
 	return m.SegmentUsedSizes
 }
 
-// Returns a vector containing the first relocated address of each memory segment
-func (m *MemorySegmentManager) RelocateSegments() ([]uint, error) {
-	first_addr := uint(1)
-	relocation_table := []uint{first_addr}
-
+// Returns the table relocating the segments one after the other, starting at address 1
+func (m *MemorySegmentManager) RelocateSegments() (RelocationTable, error) {
+	bases := make([]uint, 0, m.Memory.numSegments)
+	next := uint(1)
 	for i := uint(0); i < m.Memory.numSegments; i++ {
 		segmentSize, err := m.GetSegmentSize(i)
 		if err != nil {
-			return nil, err
+			return RelocationTable{}, err
 		}
-
-		new_addr := relocation_table[i] + segmentSize
-		relocation_table = append(relocation_table, new_addr)
+		bases = append(bases, next)
+		next += segmentSize
 	}
-	relocation_table = relocation_table[:len(relocation_table)-1]
-
-	return relocation_table, nil
+	return NewRelocationTable(bases)
 }
 
 // Relocates the VM's memory, turning bidimensional indexes into contiguous numbers, and values
-// into Felt252s. Uses the relocation_table to assign each index a number according to the value
-// on its segment number.
-func (s *MemorySegmentManager) RelocateMemory(relocationTable *[]uint) (map[uint]lambdaworks.Felt, error) {
-	relocatedMemory := make(map[uint]lambdaworks.Felt, 0)
+// into Felt252s, according to the relocation table
+// Fails if the table doesn't relocate every segment
+func (s *MemorySegmentManager) RelocateMemory(relocationTable RelocationTable) (RelocatedMemory, error) {
+	return s.RelocateMemoryParallel(relocationTable, 1)
+}
+
+type relocatedCell struct {
+	addr  uint
+	value lambdaworks.Felt
+}
 
+// Same as RelocateMemory, but splits the cells of all segments across the given number of workers
+// A non-positive number of workers uses one worker per available cpu
+func (s *MemorySegmentManager) RelocateMemoryParallel(relocationTable RelocationTable, workers int) (RelocatedMemory, error) {
+	if err := relocationTable.CheckCoverage(s.Memory.numSegments); err != nil {
+		return RelocatedMemory{}, err
+	}
+	// segmentStarts[i] is the index of the first cell of segment i when all the segments are laid out contiguously
+	segmentStarts := make([]uint, 0, s.Memory.numSegments+1)
+	totalCells := uint(0)
 	for i := uint(0); i < s.Memory.numSegments; i++ {
 		segmentSize, err := s.GetSegmentSize(i)
 		if err != nil {
-			return nil, err
+			return RelocatedMemory{}, err
 		}
+		segmentStarts = append(segmentStarts, totalCells)
+		totalCells += segmentSize
+	}
+	segmentStarts = append(segmentStarts, totalCells)
 
-		for j := uint(0); j < segmentSize; j++ {
-			ptr := NewRelocatable(int(i), j)
+	chunks := make([][]relocatedCell, parallel.Workers(workers))
+	err := parallel.ForEachChunk(int(totalCells), workers, func(chunk int, start int, end int) error {
+		cells := make([]relocatedCell, 0, end-start)
+		segment := sort.Search(len(segmentStarts), func(i int) bool { return segmentStarts[i] > uint(start) }) - 1
+		for k := uint(start); k < uint(end); k++ {
+			for k >= segmentStarts[segment+1] {
+				segment++
+			}
+			ptr := NewRelocatable(segment, k-segmentStarts[segment])
 			cell, err := s.Memory.Get(ptr)
-			if err == nil {
-				relocatedAddr := ptr.RelocateAddress(relocationTable)
-				value, err := cell.RelocateValue(relocationTable)
-				if err != nil {
-					return nil, err
-				}
-				relocatedMemory[relocatedAddr] = value
+			if err != nil {
+				continue
+			}
+			value, err := relocationTable.RelocateValue(*cell)
+			if err != nil {
+				return err
 			}
+			addr, err := relocationTable.Relocate(ptr)
+			if err != nil {
+				return err
+			}
+			cells = append(cells, relocatedCell{addr: addr, value: value})
 		}
+		chunks[chunk] = cells
+		return nil
+	})
+	if err != nil {
+		return RelocatedMemory{}, err
 	}
 
+	// Relocated addresses start at 1
+	relocatedMemory := NewRelocatedMemory(totalCells + 1)
+	for _, cells := range chunks {
+		for _, cell := range cells {
+			if err := relocatedMemory.Insert(cell.addr, cell.value); err != nil {
+				return RelocatedMemory{}, err
+			}
+		}
+	}
 	return relocatedMemory, nil
 }
 
 // Writes data into the memory from address ptr and returns the first address after the data.
 // If any insertion fails, returns (0,0) and the memory insertion error
 func (m *MemorySegmentManager) LoadData(ptr Relocatable, data *[]MaybeRelocatable) (Relocatable, error) {
-	for _, val := range *data {
-		err := m.Memory.Insert(ptr, &val)
+	for i := range *data {
+		err := m.Memory.Insert(ptr, &(*data)[i])
 		if err != nil {
 			return Relocatable{0, 0}, err
 		}
@@ -108,6 +179,34 @@ func (m *MemorySegmentManager) LoadData(ptr Relocatable, data *[]MaybeRelocatabl
 	return ptr, nil
 }
 
+// Writes the values into consecutive cells starting at ptr & returns the address after the last cell written
+// Same as LoadData, but takes the values by value, which suits hints building the values on the fly
+func (m *MemorySegmentManager) WriteArg(ptr Relocatable, values []MaybeRelocatable) (Relocatable, error) {
+	return m.LoadData(ptr, &values)
+}
+
+// Copies the size cells starting at src into consecutive cells starting at dst & returns the address after the last
+// cell written. Fails if any of the source cells is missing
+func (m *MemorySegmentManager) MemcpyIntoSegment(dst Relocatable, src Relocatable, size uint) (Relocatable, error) {
+	values, err := m.Memory.GetRange(src, size)
+	if err != nil {
+		return Relocatable{}, err
+	}
+	return m.WriteArg(dst, values)
+}
+
+// Adds a segment holding size zeros & returns its base
+func (m *MemorySegmentManager) AddZeroSegment(size uint) (Relocatable, error) {
+	base := m.AddSegment()
+	zero := *NewMaybeRelocatableFelt(lambdaworks.FeltZero())
+	for i := uint(0); i < size; i++ {
+		if err := m.Memory.Insert(base.AddUint(i), &zero); err != nil {
+			return Relocatable{}, err
+		}
+	}
+	return base, nil
+}
+
 func (m *MemorySegmentManager) GetSegmentUsedSize(segmentIdx uint) (uint, error) {
 	size, ok := m.SegmentUsedSizes[segmentIdx]
 	if !ok {
@@ -133,19 +232,10 @@ func (m *MemorySegmentManager) GetSegmentSize(index uint) (uint, error) {
 // result
 func (m *MemorySegmentManager) GetMemoryHoles(builtinCount uint) (uint, error) {
 	var memoryHoles uint
-	accessedCellsBySegment := make(map[uint]uint)
 
 	var builtinSegmentsStart uint = 1
 	var builtinSegmentsEnd uint = builtinSegmentsStart + builtinCount
 
-	for address := range m.Memory.AccessedAddresses {
-		if uint(address.SegmentIndex) > builtinSegmentsStart && uint(address.SegmentIndex) <= builtinSegmentsEnd {
-			continue
-		}
-
-		accessedCellsBySegment[uint(address.SegmentIndex)]++
-	}
-
 	for segmentIndex := range m.SegmentUsedSizes {
 		if segmentIndex > builtinSegmentsStart && segmentIndex <= builtinSegmentsEnd {
 			continue
@@ -156,7 +246,7 @@ func (m *MemorySegmentManager) GetMemoryHoles(builtinCount uint) (uint, error) {
 			return 0, err
 		}
 
-		memoryHoles += size - accessedCellsBySegment[segmentIndex]
+		memoryHoles += size - m.Memory.CountAccessedAddresses(int(segmentIndex))
 	}
 
 	return memoryHoles, nil
@@ -178,15 +268,13 @@ func (m *MemorySegmentManager) Finalize(size *uint, segmentIndex uint, publicMem
 // Gets a range of Felt memory values from addr to addr + size
 // Fails if any of the values inside the range is missing (memory gap), or is not a Felt
 func (m *MemorySegmentManager) GetFeltRange(start Relocatable, size uint) ([]lambdaworks.Felt, error) {
-	feltRange := make([]lambdaworks.Felt, 0, size)
-	for i := uint(0); i < size; i++ {
-		val, err := m.Memory.GetFelt(start.AddUint(i))
-		if err != nil {
-			return nil, err
-		}
-		feltRange = append(feltRange, val)
-	}
-	return feltRange, nil
+	return m.Memory.GetFeltRange(start, size)
+}
+
+// Gets a range of Relocatable memory values from addr to addr + size
+// Fails if any of the values inside the range is missing (memory gap), or is not a Relocatable
+func (m *MemorySegmentManager) GetRelocatableRange(start Relocatable, size uint) ([]Relocatable, error) {
+	return m.Memory.GetRelocatableRange(start, size)
 }
 
 /*
@@ -1,6 +1,7 @@
 package memory_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
@@ -9,6 +10,24 @@ import (
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 )
 
+func TestAddSegmentWithSizeHintKeepsMemory(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(7)))
+
+	base := segments.AddSegmentWithSizeHint(100)
+	if base != memory.NewRelocatable(1, 0) || segments.Memory.NumSegments() != 2 {
+		t.Errorf("Wrong segment added: %s, number of segments: %d", base, segments.Memory.NumSegments())
+	}
+	value, err := segments.Memory.GetFelt(memory.NewRelocatable(0, 0))
+	if err != nil || value != lambdaworks.FeltFromUint64(7) {
+		t.Errorf("Reserving room in the memory should keep its cells, got: %v, err: %v", value, err)
+	}
+	if err := segments.Memory.Insert(base, memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne())); err != nil {
+		t.Errorf("Insert into the new segment failed with error: %s", err)
+	}
+}
+
 func TestComputeEffectiveSizeOneSegment(t *testing.T) {
 	segments := memory.NewMemorySegmentManager()
 	segments.AddSegment()
@@ -139,7 +158,7 @@ func TestRelocateOneSegment(t *testing.T) {
 	}
 
 	expectedTable := []uint{1}
-	if !reflect.DeepEqual(expectedTable, relocationTable) {
+	if !reflect.DeepEqual(expectedTable, relocationTable.Bases()) {
 		t.Errorf("Relocation tables are not the same")
 	}
 }
@@ -159,7 +178,7 @@ func TestRelocateFiveSegments(t *testing.T) {
 	}
 
 	expectedTable := []uint{1, 4, 7, 63, 141}
-	if !reflect.DeepEqual(expectedTable, relocationTable) {
+	if !reflect.DeepEqual(expectedTable, relocationTable.Bases()) {
 		t.Errorf("Relocation tables are not the same")
 	}
 }
@@ -177,7 +196,7 @@ func TestRelocateSegmentsWithHoles(t *testing.T) {
 	}
 
 	expectedTable := []uint{1, 4, 4}
-	if !reflect.DeepEqual(expectedTable, relocationTable) {
+	if !reflect.DeepEqual(expectedTable, relocationTable.Bases()) {
 		t.Errorf("Relocation tables are not the same")
 	}
 }
@@ -202,7 +221,7 @@ func TestRelocateMemory(t *testing.T) {
 		t.Errorf("Could not create relocation table")
 	}
 
-	relocatedMemory, err := segments.RelocateMemory(&relocationTable)
+	relocatedMemory, err := segments.RelocateMemory(relocationTable)
 	if err != nil {
 		t.Errorf("Test failed with error: %s", err)
 	}
@@ -216,13 +235,82 @@ func TestRelocateMemory(t *testing.T) {
 		9: lambdaworks.FeltFromUint64(5),
 	}
 	for i, v := range expectedMemory {
-		actual := relocatedMemory[i]
+		actual, _ := relocatedMemory.Get(i)
 		if actual != v {
 			t.Errorf("Expected relocated memory at index %d to be %d but it's %d", i, v, actual)
 		}
 	}
 }
 
+func TestRelocateMemoryParallel(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	for i := 0; i < 5; i++ {
+		segments.AddSegment()
+	}
+	// Segment 2 is left empty & segment 4 has holes
+	for i := uint(0); i < 7; i++ {
+		segments.Memory.Insert(memory.NewRelocatable(0, i), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(uint64(i))))
+	}
+	segments.Memory.Insert(memory.NewRelocatable(1, 0), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(4, 3)))
+	segments.Memory.Insert(memory.NewRelocatable(3, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(30)))
+	segments.Memory.Insert(memory.NewRelocatable(4, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(41)))
+	segments.Memory.Insert(memory.NewRelocatable(4, 4), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 2)))
+	segments.ComputeEffectiveSizes()
+
+	relocationTable, err := segments.RelocateSegments()
+	if err != nil {
+		t.Errorf("Could not create relocation table")
+	}
+	expectedMemory, err := segments.RelocateMemory(relocationTable)
+	if err != nil {
+		t.Errorf("RelocateMemory failed with error: %s", err)
+	}
+
+	for _, workers := range []int{0, 2, 3, 16} {
+		relocatedMemory, err := segments.RelocateMemoryParallel(relocationTable, workers)
+		if err != nil {
+			t.Errorf("RelocateMemoryParallel failed with error: %s", err)
+		}
+		if !reflect.DeepEqual(relocatedMemory, expectedMemory) {
+			t.Errorf("Wrong relocated memory with %d workers. Expected: %v, got: %v", workers, expectedMemory, relocatedMemory)
+		}
+	}
+	if value, _ := expectedMemory.Get(8); expectedMemory.NumCells() != 11 || value != lambdaworks.FeltFromUint64(13) {
+		t.Errorf("Wrong relocated memory: %v", expectedMemory)
+	}
+}
+
+func TestRelocateMemoryKeepsHoles(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	segments.AddSegment()
+	segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)))
+	segments.Memory.Insert(memory.NewRelocatable(0, 3), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(4)))
+	segments.Memory.Insert(memory.NewRelocatable(1, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(6)))
+	segments.ComputeEffectiveSizes()
+	relocationTable, err := segments.RelocateSegments()
+	if err != nil {
+		t.Fatalf("RelocateSegments failed with error: %s", err)
+	}
+
+	relocatedMemory, err := segments.RelocateMemory(relocationTable)
+	if err != nil {
+		t.Fatalf("RelocateMemory failed with error: %s", err)
+	}
+	expected := map[uint]lambdaworks.Felt{1: lambdaworks.FeltFromUint64(1), 4: lambdaworks.FeltFromUint64(4), 6: lambdaworks.FeltFromUint64(6)}
+	if !reflect.DeepEqual(relocatedMemory.ToMap(), expected) {
+		t.Errorf("Wrong relocated memory: %v", relocatedMemory.ToMap())
+	}
+	for _, hole := range []uint{0, 2, 3, 5} {
+		if _, ok := relocatedMemory.Get(hole); ok {
+			t.Errorf("Address %d should be a hole", hole)
+		}
+	}
+	if relocatedMemory.Len() != 7 || relocatedMemory.NumCells() != 3 {
+		t.Errorf("Wrong relocated memory size: %d addresses, %d cells", relocatedMemory.Len(), relocatedMemory.NumCells())
+	}
+}
+
 func TestGetMemoryHoles(t *testing.T) {
 	manager := memory.NewMemorySegmentManager()
 	manager.AddSegment()
@@ -311,6 +399,34 @@ func TestGetFeltRangeRelocatable(t *testing.T) {
 	}
 }
 
+func TestGetRelocatableRangeOk(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	segments.AddSegment()
+	segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 3)))
+	segments.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 0)))
+
+	relocatableRange, err := segments.GetRelocatableRange(memory.NewRelocatable(0, 0), 2)
+	expectedRange := []memory.Relocatable{memory.NewRelocatable(1, 3), memory.NewRelocatable(1, 0)}
+
+	if err != nil || !reflect.DeepEqual(relocatableRange, expectedRange) {
+		t.Errorf("GetRelocatableRange failed or returned wrong value")
+	}
+}
+
+func TestGetRelocatableRangeFelt(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 3)))
+	segments.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)))
+
+	_, err := segments.GetRelocatableRange(memory.NewRelocatable(0, 0), 2)
+
+	if !errors.Is(err, memory.ErrExpectedRelocatable) {
+		t.Errorf("GetRelocatableRange should have failed with ErrExpectedRelocatable, got: %v", err)
+	}
+}
+
 func TestGenArgMaybeRelocatable(t *testing.T) {
 	segments := memory.NewMemorySegmentManager()
 	arg := any(*memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()))
@@ -359,3 +475,72 @@ func TestGenArgSliceSliceMaybeRelocatable(t *testing.T) {
 		t.Error("GenArg inserted wrong value into memory")
 	}
 }
+
+func TestWriteArg(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	base := segments.AddSegment()
+	values := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(1)),
+		*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 0)),
+	}
+	end, err := segments.WriteArg(base.AddUint(1), values)
+	if err != nil {
+		t.Fatalf("WriteArg failed with error: %s", err)
+	}
+	if end != memory.NewRelocatable(0, 3) {
+		t.Errorf("Wrong end pointer: %s", end)
+	}
+	written, err := segments.Memory.GetRange(base.AddUint(1), 2)
+	if err != nil || !reflect.DeepEqual(written, values) {
+		t.Errorf("Wrong values written: %v, err: %v", written, err)
+	}
+}
+
+func TestMemcpyIntoSegment(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	src := segments.AddSegment()
+	dst := segments.AddSegment()
+	values := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(7)),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(8)),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(9)),
+	}
+	segments.WriteArg(src, values)
+
+	end, err := segments.MemcpyIntoSegment(dst, src, 3)
+	if err != nil || end != dst.AddUint(3) {
+		t.Fatalf("Wrong end pointer: %s, err: %v", end, err)
+	}
+	copied, err := segments.Memory.GetRange(dst, 3)
+	if err != nil || !reflect.DeepEqual(copied, values) {
+		t.Errorf("Wrong values copied: %v, err: %v", copied, err)
+	}
+
+	if _, err := segments.MemcpyIntoSegment(dst.AddUint(3), src, 4); err == nil {
+		t.Error("Copying a range with a missing cell should fail")
+	}
+}
+
+func TestAddZeroSegment(t *testing.T) {
+	segments := memory.NewMemorySegmentManager()
+	segments.AddSegment()
+	base, err := segments.AddZeroSegment(4)
+	if err != nil {
+		t.Fatalf("AddZeroSegment failed with error: %s", err)
+	}
+	if base != memory.NewRelocatable(1, 0) {
+		t.Errorf("Wrong segment base: %s", base)
+	}
+	zeros, err := segments.Memory.GetFeltRange(base, 4)
+	if err != nil {
+		t.Fatalf("Zero segment is missing cells: %s", err)
+	}
+	for i, value := range zeros {
+		if !value.IsZero() {
+			t.Errorf("Cell %d is not zero: %s", i, value.ToSignedFeltString())
+		}
+	}
+	if _, err := segments.Memory.Get(base.AddUint(4)); err == nil {
+		t.Error("Zero segment is bigger than requested")
+	}
+}
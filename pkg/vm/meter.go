@@ -0,0 +1,61 @@
+package vm
+
+import (
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+var ErrOutOfGas = errors.New("Out of gas")
+
+func OutOfGasError(cost uint64, remaining uint64) error {
+	return errors.Wrapf(ErrOutOfGas, "cost: %d, remaining: %d", cost, remaining)
+}
+
+// Charges the resources used by the execution (ie: for sequencer-style gas accounting)
+// Returning an error vetoes the execution, which is aborted with that error
+type Meter interface {
+	// Called before the instruction at pc is executed, once the hints at pc have been executed
+	ChargeStep(pc memory.Relocatable, instruction *Instruction) error
+	// Called each time a builtin deduces the value of one of its cells (ie: the result of a hash)
+	ChargeBuiltin(name string, addr memory.Relocatable) error
+}
+
+// Meter that charges a fixed cost per step & per builtin deduction from a limited budget
+type GasMeter struct {
+	remaining uint64
+	used      uint64
+	stepCost  uint64
+	// Cost of a deduction by each builtin, builtins without a cost are free
+	builtinCosts map[string]uint64
+}
+
+func NewGasMeter(budget uint64, stepCost uint64, builtinCosts map[string]uint64) *GasMeter {
+	return &GasMeter{remaining: budget, stepCost: stepCost, builtinCosts: builtinCosts}
+}
+
+func (m *GasMeter) ChargeStep(pc memory.Relocatable, instruction *Instruction) error {
+	return m.charge(m.stepCost)
+}
+
+func (m *GasMeter) ChargeBuiltin(name string, addr memory.Relocatable) error {
+	return m.charge(m.builtinCosts[name])
+}
+
+func (m *GasMeter) charge(cost uint64) error {
+	if cost > m.remaining {
+		return OutOfGasError(cost, m.remaining)
+	}
+	m.remaining -= cost
+	m.used += cost
+	return nil
+}
+
+// Returns the gas left in the budget
+func (m *GasMeter) Remaining() uint64 {
+	return m.remaining
+}
+
+// Returns the gas charged so far
+func (m *GasMeter) Used() uint64 {
+	return m.used
+}
@@ -0,0 +1,58 @@
+package vm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestGasMeterChargesSteps(t *testing.T) {
+	meter := vm.NewGasMeter(100, 10, nil)
+	_, err := cairo_run.CairoRunProgram(programForObserverTest(), cairo_run.CairoRunConfig{Layout: "plain", Meter: meter})
+	if err != nil {
+		t.Fatalf("CairoRunProgram failed with error: %s", err)
+	}
+	if meter.Used() != 20 || meter.Remaining() != 80 {
+		t.Errorf("Wrong gas usage, used: %d, remaining: %d", meter.Used(), meter.Remaining())
+	}
+}
+
+func TestGasMeterVetoesExecution(t *testing.T) {
+	meter := vm.NewGasMeter(15, 10, nil)
+	runner, err := cairo_run.CairoRunProgram(programForObserverTest(), cairo_run.CairoRunConfig{Layout: "plain", Meter: meter})
+	if !errors.Is(err, vm.ErrOutOfGas) {
+		t.Fatalf("Expected ErrOutOfGas, got: %v", err)
+	}
+	if runner.Vm.CurrentStep != 1 {
+		t.Errorf("The vetoed step shouldn't be executed, current step: %d", runner.Vm.CurrentStep)
+	}
+}
+
+func TestGasMeterChargesBuiltinDeductions(t *testing.T) {
+	meter := vm.NewGasMeter(10, 1, map[string]uint64{"bitwise": 7})
+	testVm := vm.New(vm.WithMeter(meter))
+	bitwise := builtins.NewBitwiseBuiltinRunner(256)
+	bitwise.InitializeSegments(&testVm.Segments)
+	testVm.BuiltinRunners = append(testVm.BuiltinRunners, bitwise)
+	testVm.Segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(12)))
+	testVm.Segments.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(10)))
+
+	if _, err := testVm.DeduceMemoryCell(memory.NewRelocatable(0, 2)); err != nil {
+		t.Fatalf("DeduceMemoryCell failed with error: %s", err)
+	}
+	if meter.Remaining() != 3 {
+		t.Errorf("Expected the deduction to cost 7, remaining: %d", meter.Remaining())
+	}
+	if _, err := testVm.DeduceMemoryCell(memory.NewRelocatable(0, 3)); !errors.Is(err, vm.ErrOutOfGas) {
+		t.Errorf("Expected ErrOutOfGas, got: %v", err)
+	}
+	// Cells without a deduction aren't charged
+	if _, err := testVm.DeduceMemoryCell(memory.NewRelocatable(0, 5)); err != nil {
+		t.Errorf("DeduceMemoryCell failed with error: %s", err)
+	}
+}
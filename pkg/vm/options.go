@@ -0,0 +1,73 @@
+package vm
+
+// Option configures a VirtualMachine created through New
+type Option func(*VirtualMachine)
+
+// Creates a VirtualMachine configured by the given options, which are applied in order
+func New(opts ...Option) *VirtualMachine {
+	v := NewVirtualMachine()
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+// Installs the given step hooks
+func WithHooks(hooks StepHooks) Option {
+	return func(v *VirtualMachine) {
+		v.Hooks = hooks
+	}
+}
+
+// Registers the given step observers, after the ones that are already registered
+func WithStepObservers(observers ...StepObserver) Option {
+	return func(v *VirtualMachine) {
+		for _, observer := range observers {
+			v.AddStepObserver(observer)
+		}
+	}
+}
+
+// Limits the number of steps the vm can run for
+func WithMaxSteps(maxSteps uint) Option {
+	return func(v *VirtualMachine) {
+		runResources := NewRunResources(maxSteps)
+		v.RunResources = &runResources
+	}
+}
+
+// Limits the size of the relocated memory, see VirtualMachine.MaxMemorySize
+func WithMaxMemorySize(maxMemorySize uint) Option {
+	return func(v *VirtualMachine) {
+		v.MaxMemorySize = maxMemorySize
+	}
+}
+
+// Writes the trace to the given streamed trace instead of holding it in memory
+// The caller is responsible for closing it
+func WithStreamedTrace(trace *StreamedTrace) Option {
+	return func(v *VirtualMachine) {
+		v.StreamedTrace = trace
+	}
+}
+
+// Sets the number of workers used to relocate the trace & memory, a non-positive value uses one per available cpu
+func WithRelocationWorkers(workers int) Option {
+	return func(v *VirtualMachine) {
+		v.RelocationWorkers = workers
+	}
+}
+
+// Sends the events of the execution to the tracer
+func WithTracer(tracer Tracer) Option {
+	return func(v *VirtualMachine) {
+		v.SetTracer(tracer)
+	}
+}
+
+// Charges the steps & builtin deductions of the execution through the meter, which can abort it
+func WithMeter(meter Meter) Option {
+	return func(v *VirtualMachine) {
+		v.Meter = meter
+	}
+}
@@ -1,11 +1,26 @@
 package vm
 
 import (
+	"fmt"
+	"strconv"
+
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
 	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
 )
 
+var ErrIdentifierNotFound = errors.New("Identifier not found")
+var ErrWrongIdentifierType = errors.New("Wrong identifier type")
+
+func IdentifierNotFoundError(fullName string) error {
+	return fmt.Errorf("%w: %s", ErrIdentifierNotFound, fullName)
+}
+
+func WrongIdentifierTypeError(fullName string, expected string, got string) error {
+	return fmt.Errorf("%w: %s is a %s, expected a %s", ErrWrongIdentifierType, fullName, got, expected)
+}
+
 type Identifier struct {
 	FullName    string
 	Members     map[string]any
@@ -18,6 +33,12 @@ type Identifier struct {
 	Destination string
 }
 
+// Member of a struct identifier
+type StructMember struct {
+	CairoType string
+	Offset    int
+}
+
 type Program struct {
 	Data             []memory.MaybeRelocatable
 	Builtins         []string
@@ -26,17 +47,34 @@ type Program struct {
 	ReferenceManager parser.ReferenceManager
 	Start            uint
 	End              uint
+	// Source locations of the instructions by pc, only present if the program was compiled with debug info
+	InstructionLocations map[uint]parser.InstructionLocation
+	// Prime of the field the program was compiled for, as written in the compiled json (ie: 0x800...001)
+	// Empty for programs that were not compiled, which are assumed to use the Stark prime
+	Prime string
+	// Compilation details of the program (ie: compiler version), see CompilerVersion
+	Metadata ProgramMetadata
 }
 
 func DeserializeProgramJson(compiledProgram parser.CompiledJson) Program {
 	var program Program
 
 	hexData := compiledProgram.Data
+	program.Data = make([]memory.MaybeRelocatable, 0, len(hexData))
+	// Programs repeat the same words (instructions, small immediates) many times,
+	// so each distinct word is converted once and its value shared by every cell holding it
+	decoded := make(map[string]memory.MaybeRelocatable)
 	for _, hexVal := range hexData {
-		felt := lambdaworks.FeltFromHex(hexVal)
-		program.Data = append(program.Data, *memory.NewMaybeRelocatableFelt(felt))
+		val, ok := decoded[hexVal]
+		if !ok {
+			val = *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex(hexVal))
+			decoded[hexVal] = val
+		}
+		program.Data = append(program.Data, val)
 	}
 	program.Builtins = compiledProgram.Builtins
+	program.Prime = compiledProgram.Prime
+	program.Metadata = ProgramMetadata{CompilerVersion: compiledProgram.CompilerVersion, MainScope: compiledProgram.MainScope}
 	program.Identifiers = make(map[string]Identifier)
 
 	start := uint(compiledProgram.Identifiers["__main__.__start__"].PC)
@@ -60,9 +98,86 @@ func DeserializeProgramJson(compiledProgram parser.CompiledJson) Program {
 	program.Hints = compiledProgram.Hints
 	program.ReferenceManager = compiledProgram.ReferenceManager
 
+	program.InstructionLocations = make(map[uint]parser.InstructionLocation)
+	for pcStr, location := range compiledProgram.DebugInfo.InstructionLocation {
+		pc, err := strconv.ParseUint(pcStr, 10, 0)
+		if err != nil {
+			continue
+		}
+		program.InstructionLocations[uint(pc)] = location
+	}
+
 	return program
 }
 
+// Returns the identifier with the given full name (ie: __main__.main), following aliases
+func (p *Program) GetIdentifier(fullName string) (Identifier, error) {
+	identifier, ok := p.Identifiers[fullName]
+	// An alias chain can't be longer than the amount of identifiers, unless it is cyclic
+	for i := 0; ok && identifier.Type == "alias" && i < len(p.Identifiers); i++ {
+		identifier, ok = p.Identifiers[identifier.Destination]
+	}
+	if !ok || identifier.Type == "alias" {
+		return Identifier{}, IdentifierNotFoundError(fullName)
+	}
+	return identifier, nil
+}
+
+// Returns the value of the constant with the given full name
+func (p *Program) GetConstant(fullName string) (lambdaworks.Felt, error) {
+	identifier, err := p.GetIdentifier(fullName)
+	if err != nil {
+		return lambdaworks.Felt{}, err
+	}
+	if identifier.Type != "const" {
+		return lambdaworks.Felt{}, WrongIdentifierTypeError(fullName, "const", identifier.Type)
+	}
+	return identifier.Value, nil
+}
+
+// Returns the pc offset of the label or function with the given full name
+func (p *Program) GetLabelPc(fullName string) (uint, error) {
+	identifier, err := p.GetIdentifier(fullName)
+	if err != nil {
+		return 0, err
+	}
+	if identifier.Type != "label" && identifier.Type != "function" {
+		return 0, WrongIdentifierTypeError(fullName, "label", identifier.Type)
+	}
+	return uint(identifier.PC), nil
+}
+
+// Returns the members of the struct with the given full name, by member name
+func (p *Program) GetStructMembers(fullName string) (map[string]StructMember, error) {
+	identifier, err := p.GetIdentifier(fullName)
+	if err != nil {
+		return nil, err
+	}
+	if identifier.Type != "struct" {
+		return nil, WrongIdentifierTypeError(fullName, "struct", identifier.Type)
+	}
+	members := make(map[string]StructMember, len(identifier.Members))
+	for name, value := range identifier.Members {
+		member, ok := value.(map[string]any)
+		if !ok {
+			return nil, errors.Errorf("Invalid member %s of struct %s", name, fullName)
+		}
+		cairoType, _ := member["cairo_type"].(string)
+		// Members decoded from json hold their offset as a float64
+		var offset int
+		switch memberOffset := member["offset"].(type) {
+		case float64:
+			offset = int(memberOffset)
+		case int:
+			offset = memberOffset
+		default:
+			return nil, errors.Errorf("Invalid offset for member %s of struct %s", name, fullName)
+		}
+		members[name] = StructMember{CairoType: cairoType, Offset: offset}
+	}
+	return members, nil
+}
+
 func (p *Program) ExtractConstants() map[string]lambdaworks.Felt {
 	constants := make(map[string]lambdaworks.Felt)
 	for name, identifier := range p.Identifiers {
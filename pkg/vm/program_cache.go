@@ -0,0 +1,228 @@
+package vm
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Version of the on-disk format of the cached programs, part of their file name so that a change in the format
+// ignores the programs cached by older versions
+const programCacheVersion = 3
+
+func ProgramCacheError(err error) error {
+	return errors.Wrapf(err, "Program cache error")
+}
+
+// Caches the programs loaded from compiled json files, keyed by the sha256 hash of the file's contents, so that
+// loading the same program repeatedly skips parsing its json & converting its data to felts
+// If dir isn't empty, the loaded programs are also stored in it, so that they are shared between processes
+// The cached programs are shared by every caller, and must not be modified
+type ProgramCache struct {
+	dir      string
+	mutex    sync.Mutex
+	programs map[[sha256.Size]byte]Program
+}
+
+// Creates a program cache, stored on disk in dir unless it's empty. The directory is created if needed
+func NewProgramCache(dir string) *ProgramCache {
+	return &ProgramCache{dir: dir, programs: make(map[[sha256.Size]byte]Program)}
+}
+
+// Loads the program at path, from the cache if a file with the same contents was loaded before
+// Programs that are loaded concurrently may be parsed more than once
+func (c *ProgramCache) Load(path string) (Program, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Program{}, ProgramCacheError(err)
+	}
+	return c.LoadBytes(data)
+}
+
+// Loads the program from the contents of a compiled json file, from the cache if it was loaded before
+func (c *ProgramCache) LoadBytes(data []byte) (Program, error) {
+	hash := sha256.Sum256(data)
+	c.mutex.Lock()
+	program, ok := c.programs[hash]
+	c.mutex.Unlock()
+	if ok {
+		return program, nil
+	}
+
+	program, ok = c.readFromDisk(hash)
+	if !ok {
+		compiledProgram, err := parser.ParseBytes(data)
+		if err != nil {
+			return Program{}, err
+		}
+		program = DeserializeProgramJson(compiledProgram)
+		// A program that can't be stored is still loaded, it will be parsed again by the next process
+		_ = c.writeToDisk(hash, program)
+	}
+
+	c.mutex.Lock()
+	c.programs[hash] = program
+	c.mutex.Unlock()
+	return program, nil
+}
+
+// Number of programs held in memory by the cache
+func (c *ProgramCache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.programs)
+}
+
+func (c *ProgramCache) path(hash [sha256.Size]byte) string {
+	return filepath.Join(c.dir, fmt.Sprintf("%x.v%d.gob", hash, programCacheVersion))
+}
+
+// Returns false if the program isn't stored on disk or can't be read, in which case it's parsed again
+func (c *ProgramCache) readFromDisk(hash [sha256.Size]byte) (Program, bool) {
+	if c.dir == "" {
+		return Program{}, false
+	}
+	data, err := os.ReadFile(c.path(hash))
+	if err != nil {
+		return Program{}, false
+	}
+	var cached cachedProgram
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&cached); err != nil {
+		return Program{}, false
+	}
+	return cached.program(), true
+}
+
+// Writes to a temporary file that is then renamed, so that concurrent readers never see a partial program
+func (c *ProgramCache) writeToDisk(hash [sha256.Size]byte, program Program) error {
+	if c.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(newCachedProgram(program)); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(c.dir, "program-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(buffer.Bytes()); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), c.path(hash))
+}
+
+func init() {
+	// Types held by the members of struct identifiers, as decoded from json
+	gob.Register(map[string]any{})
+	gob.Register([]any{})
+}
+
+// On-disk form of a program, felts are stored as their limbs (see lambdaworks.Felt.Limbs)
+type cachedProgram struct {
+	Data                 [][lambdaworks.N_LIMBS_IN_FELT]uint64
+	Builtins             []string
+	Identifiers          map[string]cachedIdentifier
+	Hints                map[uint][]parser.HintParams
+	ReferenceManager     parser.ReferenceManager
+	Start                uint
+	End                  uint
+	InstructionLocations map[uint]parser.InstructionLocation
+	Prime                string
+	Metadata             ProgramMetadata
+}
+
+type cachedIdentifier struct {
+	FullName    string
+	Members     map[string]any
+	Size        int
+	Decorators  []string
+	PC          int
+	Type        string
+	CairoType   string
+	Value       [lambdaworks.N_LIMBS_IN_FELT]uint64
+	Destination string
+}
+
+// The data of programs loaded from json only holds felts
+func newCachedProgram(program Program) cachedProgram {
+	cached := cachedProgram{
+		Data:                 make([][lambdaworks.N_LIMBS_IN_FELT]uint64, 0, len(program.Data)),
+		Builtins:             program.Builtins,
+		Identifiers:          make(map[string]cachedIdentifier, len(program.Identifiers)),
+		Hints:                program.Hints,
+		ReferenceManager:     program.ReferenceManager,
+		Start:                program.Start,
+		End:                  program.End,
+		InstructionLocations: program.InstructionLocations,
+		Prime:                program.Prime,
+		Metadata:             program.Metadata,
+	}
+	for _, value := range program.Data {
+		felt, _ := value.GetFelt()
+		cached.Data = append(cached.Data, felt.Limbs())
+	}
+	for name, identifier := range program.Identifiers {
+		cached.Identifiers[name] = cachedIdentifier{
+			FullName:    identifier.FullName,
+			Members:     identifier.Members,
+			Size:        identifier.Size,
+			Decorators:  identifier.Decorators,
+			PC:          identifier.PC,
+			Type:        identifier.Type,
+			CairoType:   identifier.CairoType,
+			Value:       identifier.Value.Limbs(),
+			Destination: identifier.Destination,
+		}
+	}
+	return cached
+}
+
+func (c cachedProgram) program() Program {
+	program := Program{
+		Data:                 make([]memory.MaybeRelocatable, 0, len(c.Data)),
+		Builtins:             c.Builtins,
+		Identifiers:          make(map[string]Identifier, len(c.Identifiers)),
+		Hints:                c.Hints,
+		ReferenceManager:     c.ReferenceManager,
+		Start:                c.Start,
+		End:                  c.End,
+		InstructionLocations: c.InstructionLocations,
+		Prime:                c.Prime,
+		Metadata:             c.Metadata,
+	}
+	for _, limbs := range c.Data {
+		program.Data = append(program.Data, *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromLimbs(limbs)))
+	}
+	for name, identifier := range c.Identifiers {
+		program.Identifiers[name] = Identifier{
+			FullName:    identifier.FullName,
+			Members:     identifier.Members,
+			Size:        identifier.Size,
+			Decorators:  identifier.Decorators,
+			PC:          identifier.PC,
+			Type:        identifier.Type,
+			CairoType:   identifier.CairoType,
+			Value:       lambdaworks.FeltFromLimbs(identifier.Value),
+			Destination: identifier.Destination,
+		}
+	}
+	return program
+}
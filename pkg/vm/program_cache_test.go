@@ -0,0 +1,122 @@
+package vm_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+)
+
+const cachedProgramJson = `{
+	"builtins": ["output"],
+	"compiler_version": "0.11.0",
+	"main_scope": "__main__",
+	"prime": "0x800000000000011000000000000000000000000000000000000000000000001",
+	"data": ["0x480680017fff8000", "0x800000000000011000000000000000000000000000000000000000000000000", "0x208b7fff7fff7ffe"],
+	"hints": {"0": [{"code": "memory[ap] = 1", "accessible_scopes": ["__main__", "__main__.main"], "flow_tracking_data": {"ap_tracking": {"group": 0, "offset": 0}, "reference_ids": {"__main__.main.x": 0}}}]},
+	"identifiers": {
+		"__main__.main": {"pc": 0, "type": "function"},
+		"__main__.MAX": {"type": "const", "value": -1},
+		"__main__.Point": {"full_name": "__main__.Point", "type": "struct", "size": 2, "members": {"x": {"cairo_type": "felt", "offset": 0}, "y": {"cairo_type": "felt", "offset": 1}}}
+	},
+	"reference_manager": {"references": [{"ap_tracking_data": {"group": 0, "offset": 0}, "pc": 0, "value": "[cast(fp + (-3), felt*)]"}]},
+	"debug_info": {"instruction_locations": {"0": {"accessible_scopes": ["__main__.main"], "inst": {"start_line": 3, "end_line": 3, "input_file": {"filename": "main.cairo"}}}}}
+}`
+
+func writeCachedProgram(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "program.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("Failed to write the program: %s", err)
+	}
+	return path
+}
+
+func checkCachedProgram(t *testing.T, program vm.Program) {
+	compiledProgram, _ := parser.ParseBytes([]byte(cachedProgramJson))
+	expected := vm.DeserializeProgramJson(compiledProgram)
+	if !reflect.DeepEqual(program.Data, expected.Data) || !reflect.DeepEqual(program.Builtins, expected.Builtins) || program.Prime != lambdaworks.CAIRO_PRIME_HEX {
+		t.Errorf("Wrong data, expected %v, got %v", expected.Data, program.Data)
+	}
+	if program.Metadata != (vm.ProgramMetadata{CompilerVersion: "0.11.0", MainScope: "__main__"}) {
+		t.Errorf("Wrong metadata: %+v", program.Metadata)
+	}
+	if !reflect.DeepEqual(program.Hints, expected.Hints) || !reflect.DeepEqual(program.ReferenceManager, expected.ReferenceManager) {
+		t.Errorf("Wrong hints, expected %v, got %v", expected.Hints, program.Hints)
+	}
+	if !reflect.DeepEqual(program.InstructionLocations, expected.InstructionLocations) {
+		t.Errorf("Wrong instruction locations, expected %v, got %v", expected.InstructionLocations, program.InstructionLocations)
+	}
+	if constant, err := program.GetConstant("__main__.MAX"); err != nil || constant != lambdaworks.FeltFromDecString("-1") {
+		t.Errorf("Wrong constant: %v, %v", constant, err)
+	}
+	members, err := program.GetStructMembers("__main__.Point")
+	expectedMembers, _ := expected.GetStructMembers("__main__.Point")
+	if err != nil || !reflect.DeepEqual(members, expectedMembers) {
+		t.Errorf("Wrong struct members, expected %v, got %v (%v)", expectedMembers, members, err)
+	}
+}
+
+func TestProgramCacheInMemory(t *testing.T) {
+	path := writeCachedProgram(t, cachedProgramJson)
+	cache := vm.NewProgramCache("")
+	first, err := cache.Load(path)
+	if err != nil {
+		t.Fatalf("Load failed with error: %s", err)
+	}
+	checkCachedProgram(t, first)
+
+	// Files with the same contents share the cached program
+	second, err := cache.Load(writeCachedProgram(t, cachedProgramJson))
+	if err != nil {
+		t.Fatalf("Load failed with error: %s", err)
+	}
+	if cache.Len() != 1 || &first.Data[0] != &second.Data[0] {
+		t.Errorf("The second load should have been served from the cache, cached programs: %d", cache.Len())
+	}
+}
+
+func TestProgramCacheOnDisk(t *testing.T) {
+	dir := t.TempDir()
+	path := writeCachedProgram(t, cachedProgramJson)
+	if _, err := vm.NewProgramCache(dir).Load(path); err != nil {
+		t.Fatalf("Load failed with error: %s", err)
+	}
+	stored, _ := filepath.Glob(filepath.Join(dir, "*.gob"))
+	if len(stored) != 1 {
+		t.Fatalf("Expected the program to be stored on disk, found: %v", stored)
+	}
+
+	// A new cache (ie: of another process) loads the stored program
+	program, err := vm.NewProgramCache(dir).Load(path)
+	if err != nil {
+		t.Fatalf("Load failed with error: %s", err)
+	}
+	checkCachedProgram(t, program)
+
+	// A corrupted stored program is parsed again
+	if err := os.WriteFile(stored[0], []byte("corrupted"), 0o644); err != nil {
+		t.Fatalf("Failed to corrupt the stored program: %s", err)
+	}
+	program, err = vm.NewProgramCache(dir).Load(path)
+	if err != nil {
+		t.Fatalf("Load failed with error: %s", err)
+	}
+	checkCachedProgram(t, program)
+}
+
+func TestProgramCacheInvalidProgram(t *testing.T) {
+	cache := vm.NewProgramCache(t.TempDir())
+	if _, err := cache.Load(writeCachedProgram(t, "{")); err == nil {
+		t.Error("Loading an invalid program should fail")
+	}
+	if _, err := cache.Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("Loading a missing program should fail")
+	}
+	if cache.Len() != 0 {
+		t.Errorf("Failed loads shouldn't be cached, cached programs: %d", cache.Len())
+	}
+}
@@ -1,17 +1,40 @@
 package vm_test
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 )
 
 func TestNewProgram(t *testing.T) {
 
 }
 
+func TestDeserializeProgramJsonRepeatedData(t *testing.T) {
+	compiledProgram := parser.CompiledJson{Data: []string{"0x480680017fff8000", "0x1", "0x480680017fff8000", "0x1", "0x208b7fff7fff7ffe"}}
+	program := vm.DeserializeProgramJson(compiledProgram)
+	expectedData := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x480680017fff8000")),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x480680017fff8000")),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x208b7fff7fff7ffe")),
+	}
+	if len(program.Data) != len(expectedData) {
+		t.Fatalf("Wrong Data length, expected %d, got %d", len(expectedData), len(program.Data))
+	}
+	for i := range expectedData {
+		if !program.Data[i].IsEqual(&expectedData[i]) {
+			t.Errorf("Wrong Data at %d, expected %s, got %s", i, expectedData[i], program.Data[i])
+		}
+	}
+}
+
 func TestExtractConstantsEmpty(t *testing.T) {
 	program := vm.Program{}
 	expectedConstants := make(map[string]lambdaworks.Felt)
@@ -80,3 +103,77 @@ func TestExtractConstantsWithAliasedConstants(t *testing.T) {
 		t.Errorf("Wrong Constants, expected %v, got %v", expectedConstants, program.ExtractConstants())
 	}
 }
+
+func programWithIdentifiers() vm.Program {
+	return vm.Program{
+		Identifiers: map[string]vm.Identifier{
+			"__main__.main":      {Type: "function", PC: 3},
+			"__main__.loop":      {Type: "label", PC: 7},
+			"__main__.MAX":       {Type: "const", Value: lambdaworks.FeltFromUint64(10)},
+			"__main__.MAX_ALIAS": {Type: "alias", Destination: "__main__.MAX"},
+			"__main__.CYCLE_A":   {Type: "alias", Destination: "__main__.CYCLE_B"},
+			"__main__.CYCLE_B":   {Type: "alias", Destination: "__main__.CYCLE_A"},
+			"__main__.Point": {
+				Type: "struct",
+				Size: 2,
+				Members: map[string]any{
+					"x": map[string]any{"cairo_type": "felt", "offset": float64(0)},
+					"y": map[string]any{"cairo_type": "felt*", "offset": float64(1)},
+				},
+			},
+		},
+	}
+}
+
+func TestGetIdentifierFollowsAliases(t *testing.T) {
+	program := programWithIdentifiers()
+	identifier, err := program.GetIdentifier("__main__.MAX_ALIAS")
+	if err != nil || identifier.Type != "const" || identifier.Value != lambdaworks.FeltFromUint64(10) {
+		t.Errorf("Wrong identifier: %+v, err: %v", identifier, err)
+	}
+	if _, err := program.GetIdentifier("__main__.CYCLE_A"); !errors.Is(err, vm.ErrIdentifierNotFound) {
+		t.Errorf("Expected ErrIdentifierNotFound for a cyclic alias, got: %v", err)
+	}
+	if _, err := program.GetIdentifier("__main__.missing"); !errors.Is(err, vm.ErrIdentifierNotFound) {
+		t.Errorf("Expected ErrIdentifierNotFound, got: %v", err)
+	}
+}
+
+func TestGetConstant(t *testing.T) {
+	program := programWithIdentifiers()
+	value, err := program.GetConstant("__main__.MAX_ALIAS")
+	if err != nil || value != lambdaworks.FeltFromUint64(10) {
+		t.Errorf("Wrong constant: %s, err: %v", value.ToSignedFeltString(), err)
+	}
+	if _, err := program.GetConstant("__main__.main"); !errors.Is(err, vm.ErrWrongIdentifierType) {
+		t.Errorf("Expected ErrWrongIdentifierType, got: %v", err)
+	}
+}
+
+func TestGetLabelPc(t *testing.T) {
+	program := programWithIdentifiers()
+	if pc, err := program.GetLabelPc("__main__.main"); err != nil || pc != 3 {
+		t.Errorf("Wrong function pc: %d, err: %v", pc, err)
+	}
+	if pc, err := program.GetLabelPc("__main__.loop"); err != nil || pc != 7 {
+		t.Errorf("Wrong label pc: %d, err: %v", pc, err)
+	}
+	if _, err := program.GetLabelPc("__main__.MAX"); !errors.Is(err, vm.ErrWrongIdentifierType) {
+		t.Errorf("Expected ErrWrongIdentifierType, got: %v", err)
+	}
+}
+
+func TestGetStructMembers(t *testing.T) {
+	program := programWithIdentifiers()
+	members, err := program.GetStructMembers("__main__.Point")
+	if err != nil {
+		t.Fatalf("GetStructMembers failed with error: %s", err)
+	}
+	expectedMembers := map[string]vm.StructMember{"x": {CairoType: "felt", Offset: 0}, "y": {CairoType: "felt*", Offset: 1}}
+	if !reflect.DeepEqual(members, expectedMembers) {
+		t.Errorf("Wrong members, expected %+v, got %+v", expectedMembers, members)
+	}
+	if _, err := program.GetStructMembers("__main__.MAX"); !errors.Is(err, vm.ErrWrongIdentifierType) {
+		t.Errorf("Expected ErrWrongIdentifierType, got: %v", err)
+	}
+}
@@ -1,9 +1,6 @@
 package vm
 
 import (
-	"errors"
-	"math"
-
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
 )
 
@@ -15,39 +12,52 @@ type RunContext struct {
 	Fp memory.Relocatable
 }
 
-func (run_context RunContext) ComputeDstAddr(instruction Instruction) (memory.Relocatable, error) {
-	var base_addr memory.Relocatable
-	switch instruction.DstReg {
-	case AP:
-		base_addr = run_context.Ap
-	case FP:
-		base_addr = run_context.Fp
-	}
+func NewRunContext(pc memory.Relocatable, ap memory.Relocatable, fp memory.Relocatable) RunContext {
+	return RunContext{Pc: pc, Ap: ap, Fp: fp}
+}
 
-	if instruction.Off0 < 0 {
-		return base_addr.SubUint(uint(math.Abs(float64(instruction.Off0))))
-	} else {
-		return base_addr.AddUint(uint(instruction.Off0)), nil
-	}
+func (run_context RunContext) GetPc() memory.Relocatable {
+	return run_context.Pc
+}
 
+func (run_context RunContext) GetAp() memory.Relocatable {
+	return run_context.Ap
 }
 
-func (run_context RunContext) ComputeOp0Addr(instruction Instruction) (memory.Relocatable, error) {
-	var base_addr memory.Relocatable
-	switch instruction.Op0Reg {
+func (run_context RunContext) GetFp() memory.Relocatable {
+	return run_context.Fp
+}
+
+// Returns the value of the ap or fp register
+func (run_context RunContext) getRegister(register Register) (memory.Relocatable, error) {
+	switch register {
 	case AP:
-		base_addr = run_context.Ap
+		return run_context.Ap, nil
 	case FP:
-		base_addr = run_context.Fp
+		return run_context.Fp, nil
+	default:
+		return memory.Relocatable{}, UnknownRegisterError(register)
+	}
+}
+
+func (run_context RunContext) ComputeDstAddr(instruction Instruction) (memory.Relocatable, error) {
+	base_addr, err := run_context.getRegister(instruction.DstReg)
+	if err != nil {
+		return memory.Relocatable{}, err
 	}
+	return base_addr.AddInt(instruction.Off0)
+}
 
-	if instruction.Off1 < 0 {
-		return base_addr.SubUint(uint(math.Abs(float64(instruction.Off1))))
-	} else {
-		return base_addr.AddUint(uint(instruction.Off1)), nil
+func (run_context RunContext) ComputeOp0Addr(instruction Instruction) (memory.Relocatable, error) {
+	base_addr, err := run_context.getRegister(instruction.Op0Reg)
+	if err != nil {
+		return memory.Relocatable{}, err
 	}
+	return base_addr.AddInt(instruction.Off1)
 }
 
+// Computes the address of op1, which is relative to pc (immediate), ap, fp or op0 (double dereference)
+// op0 is only needed when op1 is relative to it
 func (run_context RunContext) ComputeOp1Addr(instruction Instruction, op0 *memory.MaybeRelocatable) (memory.Relocatable, error) {
 	var base_addr memory.Relocatable
 
@@ -57,27 +67,22 @@ func (run_context RunContext) ComputeOp1Addr(instruction Instruction, op0 *memor
 	case Op1SrcAP:
 		base_addr = run_context.Ap
 	case Op1SrcImm:
-		if instruction.Off2 == 1 {
-			base_addr = run_context.Pc
-		} else {
-			base_addr = memory.NewRelocatable(0, 0)
-			return memory.Relocatable{}, &VirtualMachineError{Msg: "UnknownOp0"}
+		if instruction.Off2 != 1 {
+			return memory.Relocatable{}, ErrImmShouldBe1
 		}
+		base_addr = run_context.Pc
 	case Op1SrcOp0:
 		if op0 == nil {
-			return memory.Relocatable{}, errors.New("Unknown Op0")
+			return memory.Relocatable{}, ErrUnknownOp0
 		}
 		rel, is_rel := op0.GetRelocatable()
-		if is_rel {
-			base_addr = rel
-		} else {
-			return memory.Relocatable{}, errors.New("AddressNotRelocatable")
+		if !is_rel {
+			return memory.Relocatable{}, ErrAddressNotRelocatable
 		}
+		base_addr = rel
+	default:
+		return memory.Relocatable{}, UnknownOp1SrcError(instruction.Op1Addr)
 	}
 
-	if instruction.Off2 < 0 {
-		return base_addr.SubUint(uint(math.Abs(float64(instruction.Off2))))
-	} else {
-		return base_addr.AddUint(uint(instruction.Off2)), nil
-	}
+	return base_addr.AddInt(instruction.Off2)
 }
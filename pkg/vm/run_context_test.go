@@ -0,0 +1,100 @@
+package vm_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func testRunContext() vm.RunContext {
+	return vm.RunContext{
+		Pc: memory.NewRelocatable(0, 4),
+		Ap: memory.NewRelocatable(1, 5),
+		Fp: memory.NewRelocatable(1, 6),
+	}
+}
+
+func TestComputeDstAndOp0Addr(t *testing.T) {
+	runContext := testRunContext()
+	instruction := vm.Instruction{DstReg: vm.AP, Off0: -3, Op0Reg: vm.FP, Off1: 2}
+
+	dst, err := runContext.ComputeDstAddr(instruction)
+	if err != nil || dst != memory.NewRelocatable(1, 2) {
+		t.Errorf("Wrong dst address: %s, err: %v", dst, err)
+	}
+	op0, err := runContext.ComputeOp0Addr(instruction)
+	if err != nil || op0 != memory.NewRelocatable(1, 8) {
+		t.Errorf("Wrong op0 address: %s, err: %v", op0, err)
+	}
+}
+
+func TestComputeOp1AddrAllSources(t *testing.T) {
+	runContext := testRunContext()
+	op0 := memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 7))
+	cases := []struct {
+		src      vm.Op1Src
+		off      int
+		expected memory.Relocatable
+	}{
+		{vm.Op1SrcImm, 1, memory.NewRelocatable(0, 5)},
+		{vm.Op1SrcAP, -2, memory.NewRelocatable(1, 3)},
+		{vm.Op1SrcFP, 1, memory.NewRelocatable(1, 7)},
+		{vm.Op1SrcOp0, -7, memory.NewRelocatable(2, 0)},
+	}
+	for _, c := range cases {
+		addr, err := runContext.ComputeOp1Addr(vm.Instruction{Op1Addr: c.src, Off2: c.off}, op0)
+		if err != nil || addr != c.expected {
+			t.Errorf("Wrong op1 address for source %d. Expected %s, got %s, err: %v", c.src, c.expected, addr, err)
+		}
+	}
+}
+
+func TestComputeOp1AddrErrors(t *testing.T) {
+	runContext := testRunContext()
+	felt := memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne())
+	cases := []struct {
+		instruction vm.Instruction
+		op0         *memory.MaybeRelocatable
+		expected    error
+	}{
+		{vm.Instruction{Op1Addr: vm.Op1SrcImm, Off2: 2}, nil, vm.ErrImmShouldBe1},
+		{vm.Instruction{Op1Addr: vm.Op1SrcOp0}, nil, vm.ErrUnknownOp0},
+		{vm.Instruction{Op1Addr: vm.Op1SrcOp0}, felt, vm.ErrAddressNotRelocatable},
+		{vm.Instruction{Op1Addr: vm.Op1Src(3)}, nil, vm.ErrUnknownOp1Src},
+	}
+	for _, c := range cases {
+		_, err := runContext.ComputeOp1Addr(c.instruction, c.op0)
+		if !errors.Is(err, c.expected) {
+			t.Errorf("Expected error %s, got: %v", c.expected, err)
+		}
+	}
+}
+
+func TestComputeAddrNegativeOffsetOutOfSegment(t *testing.T) {
+	runContext := testRunContext()
+	_, err := runContext.ComputeOp1Addr(vm.Instruction{Op1Addr: vm.Op1SrcAP, Off2: -6}, nil)
+	if err == nil {
+		t.Error("ComputeOp1Addr should have failed")
+	}
+}
+
+func TestComputeDstAddrUnknownRegister(t *testing.T) {
+	runContext := testRunContext()
+	_, err := runContext.ComputeDstAddr(vm.Instruction{DstReg: vm.Register(2)})
+	if !errors.Is(err, vm.ErrUnknownRegister) {
+		t.Errorf("Expected ErrUnknownRegister, got: %v", err)
+	}
+}
+
+func TestRunContextRegisterAccessors(t *testing.T) {
+	runContext := vm.NewRunContext(memory.NewRelocatable(0, 4), memory.NewRelocatable(1, 5), memory.NewRelocatable(1, 6))
+	if runContext != testRunContext() {
+		t.Errorf("Wrong run context: %+v", runContext)
+	}
+	if runContext.GetPc() != memory.NewRelocatable(0, 4) || runContext.GetAp() != memory.NewRelocatable(1, 5) || runContext.GetFp() != memory.NewRelocatable(1, 6) {
+		t.Errorf("Wrong registers: pc %s, ap %s, fp %s", runContext.GetPc(), runContext.GetAp(), runContext.GetFp())
+	}
+}
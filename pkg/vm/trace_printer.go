@@ -0,0 +1,117 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+)
+
+// Configuration used when printing a trace as a table
+type TracePrinterConfig struct {
+	// Decode and display the instruction executed at each step
+	DecodeInstructions bool
+	// First step to print (inclusive)
+	StartStep uint
+	// Last step to print (exclusive). A nil value prints until the end of the trace
+	EndStep *uint
+}
+
+// Returns the steps range [start, end) that should be printed for a trace of traceLen entries
+func (c *TracePrinterConfig) stepsRange(traceLen int) (uint, uint) {
+	end := uint(traceLen)
+	if c.EndStep != nil && *c.EndStep < end {
+		end = *c.EndStep
+	}
+	start := c.StartStep
+	if start > end {
+		start = end
+	}
+	return start, end
+}
+
+// Writes the (unrelocated) trace as a table of step, pc, ap & fp
+// If the config requests it, the instruction at each pc is fetched from the vm's memory & decoded
+func (v *VirtualMachine) PrintTrace(dest io.Writer, config TracePrinterConfig) error {
+	start, end := config.stepsRange(len(v.Trace))
+	if err := writeTraceTableHeader(dest, config.DecodeInstructions); err != nil {
+		return err
+	}
+	for step := start; step < end; step++ {
+		entry := v.Trace[step]
+		line := fmt.Sprintf("%-8d %-12s %-12s %-12s", step, entry.Pc.ToString(), entry.Ap.ToString(), entry.Fp.ToString())
+		if config.DecodeInstructions {
+			line += " " + v.describeInstructionAt(entry)
+		}
+		if _, err := fmt.Fprintln(dest, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes the relocated trace as a table of step, pc, ap & fp
+// If the config requests it, the instruction at each pc is fetched from the relocated memory & decoded
+// Fails if the trace has not been relocated yet
+func (v *VirtualMachine) PrintRelocatedTrace(dest io.Writer, config TracePrinterConfig) error {
+	if len(v.RelocatedTrace) == 0 && len(v.Trace) != 0 {
+		return ErrTraceNotRelocated
+	}
+	start, end := config.stepsRange(len(v.RelocatedTrace))
+	if err := writeTraceTableHeader(dest, config.DecodeInstructions); err != nil {
+		return err
+	}
+	for step := start; step < end; step++ {
+		entry := v.RelocatedTrace[step]
+		line := fmt.Sprintf("%-8d %-12s %-12s %-12s", step, entry.Pc.ToSignedFeltString(), entry.Ap.ToSignedFeltString(), entry.Fp.ToSignedFeltString())
+		if config.DecodeInstructions {
+			line += " " + v.describeRelocatedInstructionAt(entry.Pc)
+		}
+		if _, err := fmt.Fprintln(dest, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func writeTraceTableHeader(dest io.Writer, decodeInstructions bool) error {
+	header := fmt.Sprintf("%-8s %-12s %-12s %-12s", "step", "pc", "ap", "fp")
+	if decodeInstructions {
+		header += " instruction"
+	}
+	_, err := fmt.Fprintln(dest, strings.TrimRight(header, " "))
+	return err
+}
+
+func (v *VirtualMachine) describeInstructionAt(entry TraceEntry) string {
+	encodedInstruction, err := v.Segments.Memory.GetFelt(entry.Pc)
+	if err != nil {
+		return "<missing>"
+	}
+	return describeEncodedInstruction(encodedInstruction)
+}
+
+func (v *VirtualMachine) describeRelocatedInstructionAt(pc lambdaworks.Felt) string {
+	pcAddr, err := pc.ToU64()
+	if err != nil {
+		return "<invalid pc>"
+	}
+	encodedInstruction, ok := v.RelocatedMemory.Get(uint(pcAddr))
+	if !ok {
+		return "<missing>"
+	}
+	return describeEncodedInstruction(encodedInstruction)
+}
+
+func describeEncodedInstruction(encodedInstruction lambdaworks.Felt) string {
+	encodedInstructionUint, err := encodedInstruction.ToU64()
+	if err != nil {
+		return "<invalid encoding>"
+	}
+	instruction, err := DecodeInstruction(encodedInstructionUint)
+	if err != nil {
+		return fmt.Sprintf("<%s>", err)
+	}
+	return instruction.ToString()
+}
@@ -0,0 +1,84 @@
+package vm_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func traceForTest() *vm.VirtualMachine {
+	vmachine := vm.NewVirtualMachine()
+	vmachine.Segments.AddSegment()
+	vmachine.Segments.AddSegment()
+	// [ap] = 5; ap++ (encoded instruction + immediate)
+	vmachine.Segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(0x480680017fff8000)))
+	vmachine.Segments.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5)))
+	vmachine.Trace = []vm.TraceEntry{
+		{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 2), Fp: memory.NewRelocatable(1, 2)},
+		{Pc: memory.NewRelocatable(0, 2), Ap: memory.NewRelocatable(1, 3), Fp: memory.NewRelocatable(1, 2)},
+	}
+	return vmachine
+}
+
+func TestPrintTraceDecodeInstructions(t *testing.T) {
+	vmachine := traceForTest()
+	var buffer bytes.Buffer
+	err := vmachine.PrintTrace(&buffer, vm.TracePrinterConfig{DecodeInstructions: true})
+	if err != nil {
+		t.Errorf("PrintTrace failed with error: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected a header and two steps, got: %s", buffer.String())
+	}
+	if !strings.HasPrefix(lines[1], "0") || !strings.Contains(lines[1], "{0:0}") || !strings.Contains(lines[1], "AssertEq dst=[ap+0]") {
+		t.Errorf("Wrong first step: %s", lines[1])
+	}
+	if !strings.Contains(lines[2], "<missing>") {
+		t.Errorf("Expected missing instruction in second step: %s", lines[2])
+	}
+}
+
+func TestPrintTraceRange(t *testing.T) {
+	vmachine := traceForTest()
+	var buffer bytes.Buffer
+	end := uint(2)
+	err := vmachine.PrintTrace(&buffer, vm.TracePrinterConfig{StartStep: 1, EndStep: &end})
+	if err != nil {
+		t.Errorf("PrintTrace failed with error: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[1], "1") {
+		t.Errorf("Expected only step 1 to be printed, got: %s", buffer.String())
+	}
+}
+
+func TestPrintRelocatedTraceNotRelocated(t *testing.T) {
+	vmachine := traceForTest()
+	var buffer bytes.Buffer
+	err := vmachine.PrintRelocatedTrace(&buffer, vm.TracePrinterConfig{})
+	if err == nil {
+		t.Errorf("PrintRelocatedTrace should fail if the trace wasn't relocated")
+	}
+}
+
+func TestPrintRelocatedTrace(t *testing.T) {
+	vmachine := traceForTest()
+	err := vmachine.Relocate()
+	if err != nil {
+		t.Errorf("Relocate failed with error: %s", err)
+	}
+	var buffer bytes.Buffer
+	err = vmachine.PrintRelocatedTrace(&buffer, vm.TracePrinterConfig{DecodeInstructions: true})
+	if err != nil {
+		t.Errorf("PrintRelocatedTrace failed with error: %s", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buffer.String()), "\n")
+	if len(lines) != 3 || !strings.Contains(lines[1], "AssertEq") {
+		t.Errorf("Wrong relocated trace table: %s", buffer.String())
+	}
+}
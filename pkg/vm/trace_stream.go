@@ -0,0 +1,84 @@
+package vm
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"os"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Size of an encoded trace entry: segment index & offset of pc, ap & fp, as 64 bit values
+const streamedTraceEntrySize = 6 * 8
+
+// Trace storage backed by a temporary file, used instead of VirtualMachine.Trace for very long runs
+// so that the trace doesn't have to be held in memory
+// Entries are appended as fixed size binary records & read back sequentially
+type StreamedTrace struct {
+	file   *os.File
+	writer *bufio.Writer
+	len    int
+}
+
+// Creates the temporary file backing the trace in dir (or the default temporary directory if dir is empty)
+// The file is removed when the trace is closed
+func NewStreamedTrace(dir string) (*StreamedTrace, error) {
+	file, err := os.CreateTemp(dir, "cairo-trace-*")
+	if err != nil {
+		return nil, err
+	}
+	return &StreamedTrace{file: file, writer: bufio.NewWriter(file)}, nil
+}
+
+func (t *StreamedTrace) Append(entry TraceEntry) error {
+	var record [streamedTraceEntrySize]byte
+	for i, register := range []memory.Relocatable{entry.Pc, entry.Ap, entry.Fp} {
+		binary.LittleEndian.PutUint64(record[i*16:], uint64(register.SegmentIndex))
+		binary.LittleEndian.PutUint64(record[i*16+8:], uint64(register.Offset))
+	}
+	if _, err := t.writer.Write(record[:]); err != nil {
+		return errors.Wrap(err, "Failed to write trace entry")
+	}
+	t.len++
+	return nil
+}
+
+// Returns the number of entries in the trace
+func (t *StreamedTrace) Len() int {
+	return t.len
+}
+
+// Calls fn with each entry of the trace, in order
+func (t *StreamedTrace) ForEach(fn func(step int, entry TraceEntry) error) error {
+	if err := t.writer.Flush(); err != nil {
+		return err
+	}
+	reader := bufio.NewReader(io.NewSectionReader(t.file, 0, int64(t.len*streamedTraceEntrySize)))
+	var record [streamedTraceEntrySize]byte
+	for step := 0; step < t.len; step++ {
+		if _, err := io.ReadFull(reader, record[:]); err != nil {
+			return errors.Wrap(err, "Failed to read trace entry")
+		}
+		var registers [3]memory.Relocatable
+		for i := range registers {
+			registers[i] = memory.NewRelocatable(
+				int(int64(binary.LittleEndian.Uint64(record[i*16:]))),
+				uint(binary.LittleEndian.Uint64(record[i*16+8:])))
+		}
+		if err := fn(step, TraceEntry{Pc: registers[0], Ap: registers[1], Fp: registers[2]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Closes & removes the file backing the trace
+func (t *StreamedTrace) Close() error {
+	closeErr := t.file.Close()
+	if err := os.Remove(t.file.Name()); err != nil {
+		return err
+	}
+	return closeErr
+}
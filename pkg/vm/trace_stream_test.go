@@ -0,0 +1,58 @@
+package vm_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestStreamedTraceRoundTrip(t *testing.T) {
+	trace, err := vm.NewStreamedTrace(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStreamedTrace failed with error: %s", err)
+	}
+	defer trace.Close()
+
+	entries := []vm.TraceEntry{
+		{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 2), Fp: memory.NewRelocatable(1, 2)},
+		{Pc: memory.NewRelocatable(0, 2), Ap: memory.NewRelocatable(1, 3), Fp: memory.NewRelocatable(-1, 5)},
+	}
+	for _, entry := range entries {
+		if err := trace.Append(entry); err != nil {
+			t.Errorf("Append failed with error: %s", err)
+		}
+	}
+	if trace.Len() != 2 {
+		t.Errorf("Wrong trace length. Expected: 2, got: %d", trace.Len())
+	}
+
+	readEntries := make([]vm.TraceEntry, 0)
+	err = trace.ForEach(func(step int, entry vm.TraceEntry) error {
+		readEntries = append(readEntries, entry)
+		return nil
+	})
+	if err != nil {
+		t.Errorf("ForEach failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(readEntries, entries) {
+		t.Errorf("Wrong trace entries. Expected: %v, got: %v", entries, readEntries)
+	}
+}
+
+func TestForEachRelocatedTraceEntryStreamedNotRelocated(t *testing.T) {
+	trace, err := vm.NewStreamedTrace(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStreamedTrace failed with error: %s", err)
+	}
+	defer trace.Close()
+	vmachine := vm.NewVirtualMachine()
+	vmachine.StreamedTrace = trace
+	trace.Append(vm.TraceEntry{})
+
+	err = vmachine.ForEachRelocatedTraceEntry(func(vm.RelocatedTraceEntry) error { return nil })
+	if err == nil {
+		t.Errorf("ForEachRelocatedTraceEntry should fail if the trace wasn't relocated")
+	}
+}
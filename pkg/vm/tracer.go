@@ -0,0 +1,164 @@
+package vm
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+type TraceEventKind string
+
+const (
+	// An instruction was executed
+	StepEvent TraceEventKind = "step"
+	// A value was written to a memory cell that was empty
+	MemoryWriteEvent TraceEventKind = "memory_write"
+	// A memory segment was added
+	SegmentAddEvent TraceEventKind = "segment_add"
+	// A hint was executed
+	HintExecutedEvent TraceEventKind = "hint_executed"
+	// A builtin deduced the value of an operand
+	BuiltinDeductionEvent TraceEventKind = "builtin_deduction"
+)
+
+// Structured description of something that happened during the execution
+// Only the fields relevant to its kind are set
+type TraceEvent struct {
+	Kind TraceEventKind
+	// Step the event happened at
+	Step uint
+	// Registers at the start of the step, for step & hint events
+	Registers TraceEntry
+	// Written or deduced address for memory write & builtin deduction events, base of the segment for segment add events
+	Address memory.Relocatable
+	// Written or deduced value for memory write & builtin deduction events
+	Value memory.MaybeRelocatable
+	// Position of the hint within the hints of its pc, for hint events
+	HintIndex int
+	// Name of the builtin, for builtin deduction events
+	Builtin string
+}
+
+// Receives the events of the execution, which generalizes the trace beyond the registers of each step
+// Unlike StepObserver, a tracer can't abort the execution
+type Tracer interface {
+	Trace(event TraceEvent)
+}
+
+// Forwards the memory changes to the vm's tracer
+type tracerMemoryObserver struct {
+	vm *VirtualMachine
+}
+
+func (o tracerMemoryObserver) MemoryWritten(addr memory.Relocatable, value memory.MaybeRelocatable) {
+	o.vm.Tracer.Trace(TraceEvent{Kind: MemoryWriteEvent, Step: o.vm.CurrentStep, Address: addr, Value: value})
+}
+
+func (o tracerMemoryObserver) SegmentAdded(base memory.Relocatable) {
+	o.vm.Tracer.Trace(TraceEvent{Kind: SegmentAddEvent, Step: o.vm.CurrentStep, Address: base})
+}
+
+// Sends the events of the execution to the tracer, replacing the current one
+// The vm's memory observer is replaced too, as it's used to report memory writes & segment additions
+func (v *VirtualMachine) SetTracer(tracer Tracer) {
+	v.Tracer = tracer
+	if tracer == nil {
+		v.Segments.Memory.Observer = nil
+		return
+	}
+	v.Segments.Memory.Observer = tracerMemoryObserver{vm: v}
+}
+
+// Tracer that writes each event as a line of JSON
+// Write errors don't interrupt the execution, the first one is returned by Flush
+type JsonlTracer struct {
+	writer *bufio.Writer
+	err    error
+}
+
+type jsonTraceEvent struct {
+	Kind      TraceEventKind `json:"kind"`
+	Step      uint           `json:"step"`
+	Pc        string         `json:"pc,omitempty"`
+	Ap        string         `json:"ap,omitempty"`
+	Fp        string         `json:"fp,omitempty"`
+	Address   string         `json:"address,omitempty"`
+	Value     string         `json:"value,omitempty"`
+	HintIndex *int           `json:"hint_index,omitempty"`
+	Builtin   string         `json:"builtin,omitempty"`
+}
+
+func NewJsonlTracer(dest io.Writer) *JsonlTracer {
+	return &JsonlTracer{writer: bufio.NewWriter(dest)}
+}
+
+func (t *JsonlTracer) Trace(event TraceEvent) {
+	if t.err != nil {
+		return
+	}
+	encoded := jsonTraceEvent{Kind: event.Kind, Step: event.Step}
+	switch event.Kind {
+	case StepEvent, HintExecutedEvent:
+		encoded.Pc = event.Registers.Pc.ToString()
+		encoded.Ap = event.Registers.Ap.ToString()
+		encoded.Fp = event.Registers.Fp.ToString()
+		if event.Kind == HintExecutedEvent {
+			encoded.HintIndex = &event.HintIndex
+		}
+	case MemoryWriteEvent, BuiltinDeductionEvent:
+		encoded.Address = event.Address.ToString()
+		encoded.Value = event.Value.ToString()
+		encoded.Builtin = event.Builtin
+	case SegmentAddEvent:
+		encoded.Address = event.Address.ToString()
+	}
+	line, err := json.Marshal(encoded)
+	if err != nil {
+		t.err = err
+		return
+	}
+	line = append(line, '\n')
+	if _, err := t.writer.Write(line); err != nil {
+		t.err = errors.Wrap(err, "Failed to write trace event")
+	}
+}
+
+// Writes the buffered events, returning the first error found while tracing
+func (t *JsonlTracer) Flush() error {
+	if t.err != nil {
+		return t.err
+	}
+	return t.writer.Flush()
+}
+
+// Tracer that keeps the last events in memory (ie: for the debugger)
+type RingBufferTracer struct {
+	events []TraceEvent
+	// Position the next event is stored at
+	next int
+	full bool
+}
+
+// Creates a tracer holding up to capacity events, which must be positive
+func NewRingBufferTracer(capacity int) *RingBufferTracer {
+	return &RingBufferTracer{events: make([]TraceEvent, capacity)}
+}
+
+func (t *RingBufferTracer) Trace(event TraceEvent) {
+	t.events[t.next] = event
+	t.next = (t.next + 1) % len(t.events)
+	if t.next == 0 {
+		t.full = true
+	}
+}
+
+// Returns the events held by the tracer, from the oldest to the most recent
+func (t *RingBufferTracer) Events() []TraceEvent {
+	if !t.full {
+		return append([]TraceEvent(nil), t.events[:t.next]...)
+	}
+	return append(append(make([]TraceEvent, 0, len(t.events)), t.events[t.next:]...), t.events[:t.next]...)
+}
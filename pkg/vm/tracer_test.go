@@ -0,0 +1,120 @@
+package vm_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func eventsOfKind(events []vm.TraceEvent, kind vm.TraceEventKind) []vm.TraceEvent {
+	var filtered []vm.TraceEvent
+	for _, event := range events {
+		if event.Kind == kind {
+			filtered = append(filtered, event)
+		}
+	}
+	return filtered
+}
+
+func TestTracerReceivesExecutionEvents(t *testing.T) {
+	tracer := vm.NewRingBufferTracer(100)
+	_, err := cairo_run.CairoRunProgram(programForObserverTest(), cairo_run.CairoRunConfig{Layout: "plain", Tracer: tracer})
+	if err != nil {
+		t.Fatalf("CairoRunProgram failed with error: %s", err)
+	}
+	events := tracer.Events()
+
+	expectedSteps := []vm.TraceEvent{
+		{Kind: vm.StepEvent, Step: 0, Registers: vm.TraceEntry{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 2), Fp: memory.NewRelocatable(1, 2)}},
+		{Kind: vm.StepEvent, Step: 1, Registers: vm.TraceEntry{Pc: memory.NewRelocatable(0, 2), Ap: memory.NewRelocatable(1, 3), Fp: memory.NewRelocatable(1, 2)}},
+	}
+	if steps := eventsOfKind(events, vm.StepEvent); !reflect.DeepEqual(steps, expectedSteps) {
+		t.Errorf("Wrong step events: %+v", steps)
+	}
+
+	// The execution segment is added once the vm is configured, the program segment is added before
+	if segments := eventsOfKind(events, vm.SegmentAddEvent); len(segments) == 0 {
+		t.Error("Expected segment add events")
+	}
+
+	expectedWrite := vm.TraceEvent{Kind: vm.MemoryWriteEvent, Step: 0, Address: memory.NewRelocatable(1, 2), Value: *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5))}
+	found := false
+	for _, write := range eventsOfKind(events, vm.MemoryWriteEvent) {
+		if reflect.DeepEqual(write, expectedWrite) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Missing the write of the instruction's result: %+v", events)
+	}
+}
+
+func TestTracerReceivesBuiltinDeductions(t *testing.T) {
+	tracer := vm.NewRingBufferTracer(10)
+	testVm := vm.New(vm.WithTracer(tracer))
+	bitwise := builtins.NewBitwiseBuiltinRunner(256)
+	bitwise.InitializeSegments(&testVm.Segments)
+	testVm.BuiltinRunners = append(testVm.BuiltinRunners, bitwise)
+	testVm.Segments.Memory.Insert(memory.NewRelocatable(0, 0), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(12)))
+	testVm.Segments.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(10)))
+
+	value, err := testVm.DeduceMemoryCell(memory.NewRelocatable(0, 2))
+	if err != nil || value == nil {
+		t.Fatalf("DeduceMemoryCell failed: %v, %v", value, err)
+	}
+
+	expected := []vm.TraceEvent{
+		{Kind: vm.SegmentAddEvent, Address: memory.NewRelocatable(0, 0)},
+		{Kind: vm.MemoryWriteEvent, Address: memory.NewRelocatable(0, 0), Value: *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(12))},
+		{Kind: vm.MemoryWriteEvent, Address: memory.NewRelocatable(0, 1), Value: *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(10))},
+		{Kind: vm.BuiltinDeductionEvent, Address: memory.NewRelocatable(0, 2), Value: *value, Builtin: "bitwise"},
+	}
+	if events := tracer.Events(); !reflect.DeepEqual(events, expected) {
+		t.Errorf("Wrong events, expected %+v, got %+v", expected, events)
+	}
+}
+
+func TestRingBufferTracerKeepsTheLastEvents(t *testing.T) {
+	tracer := vm.NewRingBufferTracer(3)
+	for step := uint(0); step < 5; step++ {
+		tracer.Trace(vm.TraceEvent{Kind: vm.StepEvent, Step: step})
+	}
+	events := tracer.Events()
+	if len(events) != 3 || events[0].Step != 2 || events[2].Step != 4 {
+		t.Errorf("Wrong events: %+v", events)
+	}
+}
+
+func TestJsonlTracerWritesOneEventPerLine(t *testing.T) {
+	var output bytes.Buffer
+	tracer := vm.NewJsonlTracer(&output)
+	tracer.Trace(vm.TraceEvent{Kind: vm.MemoryWriteEvent, Step: 3, Address: memory.NewRelocatable(1, 4), Value: *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(7))})
+	tracer.Trace(vm.TraceEvent{Kind: vm.HintExecutedEvent, Step: 4, Registers: vm.TraceEntry{Pc: memory.NewRelocatable(0, 1)}})
+	if err := tracer.Flush(); err != nil {
+		t.Fatalf("Flush failed with error: %s", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got:\n%s", output.String())
+	}
+	var write map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &write); err != nil {
+		t.Fatalf("Invalid JSON line %s: %s", lines[0], err)
+	}
+	expectedWrite := map[string]any{"kind": "memory_write", "step": float64(3), "address": "{1:4}", "value": "7"}
+	if !reflect.DeepEqual(write, expectedWrite) {
+		t.Errorf("Wrong memory write event: %v", write)
+	}
+	if !strings.Contains(lines[1], `"hint_index":0`) || !strings.Contains(lines[1], `"pc":"{0:1}"`) {
+		t.Errorf("Wrong hint event: %s", lines[1])
+	}
+}
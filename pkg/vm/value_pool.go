@@ -0,0 +1,66 @@
+package vm
+
+import "github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+
+// Amount of values held by each of the pool's chunks
+const valuePoolChunkSize = 64
+
+// Arena for the MaybeRelocatable values that only live while the operands of an instruction are used,
+// such as the operands deduced & the res computed while running an instruction
+// Values are handed out of reusable chunks instead of being allocated one by one, and are all
+// released at once when the pool is reset by the next operand computation
+// The chunks are never moved, so values handed out during a step remain valid until the next reset
+type ValuePool struct {
+	chunks [][]memory.MaybeRelocatable
+	// Index of the chunk values are currently handed out of, and amount of values taken from it
+	chunk int
+	used  int
+	stats ValuePoolStats
+	// Values handed out since the last reset
+	stepAllocations uint
+}
+
+type ValuePoolStats struct {
+	// Values handed out since the pool was created
+	Allocations uint
+	// Most values handed out between two resets
+	PeakStepAllocations uint
+	// Amount of values the pool can hold without allocating
+	Capacity uint
+	// Number of times the pool was reset, once per operand computation
+	Resets uint
+}
+
+// Returns a pointer to a copy of value that is valid until the pool is reset
+func (p *ValuePool) New(value memory.MaybeRelocatable) *memory.MaybeRelocatable {
+	if p.chunk == len(p.chunks) {
+		p.chunks = append(p.chunks, make([]memory.MaybeRelocatable, valuePoolChunkSize))
+		p.stats.Capacity += valuePoolChunkSize
+	}
+	ptr := &p.chunks[p.chunk][p.used]
+	*ptr = value
+	p.used++
+	if p.used == valuePoolChunkSize {
+		p.chunk++
+		p.used = 0
+	}
+	p.stats.Allocations++
+	p.stepAllocations++
+	if p.stepAllocations > p.stats.PeakStepAllocations {
+		p.stats.PeakStepAllocations = p.stepAllocations
+	}
+	return ptr
+}
+
+// Releases every value handed out so far, so that their memory is reused
+// Values previously handed out must not be used after calling it
+func (p *ValuePool) Reset() {
+	p.chunk = 0
+	p.used = 0
+	p.stepAllocations = 0
+	p.stats.Resets++
+}
+
+func (p *ValuePool) Stats() ValuePoolStats {
+	return p.stats
+}
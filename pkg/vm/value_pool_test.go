@@ -0,0 +1,110 @@
+package vm_test
+
+import (
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+)
+
+func TestValuePoolValuesRemainValidUntilReset(t *testing.T) {
+	var pool vm.ValuePool
+	values := make([]*memory.MaybeRelocatable, 0, 100)
+	for i := uint64(0); i < 100; i++ {
+		values = append(values, pool.New(*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(i))))
+	}
+	for i, value := range values {
+		if !value.IsEqual(memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(uint64(i)))) {
+			t.Errorf("Wrong value at %d: %s", i, value)
+		}
+	}
+	stats := pool.Stats()
+	if stats.Allocations != 100 || stats.PeakStepAllocations != 100 || stats.Capacity < 100 {
+		t.Errorf("Wrong stats: %+v", stats)
+	}
+}
+
+func TestValuePoolResetReusesMemory(t *testing.T) {
+	var pool vm.ValuePool
+	first := pool.New(*memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()))
+	pool.Reset()
+	second := pool.New(*memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 2)))
+	if first != second {
+		t.Error("Values handed out after a reset should reuse the pool's memory")
+	}
+	pool.New(*memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()))
+
+	expectedStats := vm.ValuePoolStats{Allocations: 3, PeakStepAllocations: 2, Capacity: pool.Stats().Capacity, Resets: 1}
+	if pool.Stats() != expectedStats {
+		t.Errorf("Wrong stats, expected %+v, got %+v", expectedStats, pool.Stats())
+	}
+}
+
+func TestStepResetsTemporaries(t *testing.T) {
+	runner, err := cairo_run.CairoRunProgram(programForStepBenchmark(10), cairo_run.CairoRunConfig{Layout: "plain"})
+	if err != nil {
+		t.Fatalf("Run failed with error: %s", err)
+	}
+	stats := runner.Vm.Temporaries.Stats()
+	if stats.Resets != runner.Vm.CurrentStep {
+		t.Errorf("Expected a reset per step (%d), got %d", runner.Vm.CurrentStep, stats.Resets)
+	}
+	if stats.Allocations == 0 || stats.PeakStepAllocations == 0 {
+		t.Errorf("Expected the steps to use the pool, got %+v", stats)
+	}
+}
+
+func TestComputeOperandsResetsTemporaries(t *testing.T) {
+	ret, _ := vm.DecodeInstruction(0x208b7fff7fff7ffe)
+	testVm := vmForRetTest(memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 0)), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 4)))
+	if _, _, err := testVm.ComputeOperands(ret); err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	capacity := testVm.Temporaries.Stats().Capacity
+	// Computing the operands outside of a step shouldn't grow the pool
+	for i := 0; i < 1000; i++ {
+		if _, _, err := testVm.ComputeOperands(ret); err != nil {
+			t.Fatalf("ComputeOperands failed with error: %s", err)
+		}
+	}
+	stats := testVm.Temporaries.Stats()
+	if stats.Resets != 1001 || stats.Capacity != capacity {
+		t.Errorf("Expected the pool to be reset by each computation without growing, got %+v", stats)
+	}
+}
+
+type resObserver struct {
+	results []*memory.MaybeRelocatable
+	values  []memory.MaybeRelocatable
+}
+
+func (o *resObserver) BeforeStep(pc memory.Relocatable, instruction *vm.Instruction) error {
+	return nil
+}
+
+func (o *resObserver) AfterStep(entry vm.TraceEntry, operands *vm.Operands) error {
+	if operands.Res != nil {
+		o.results = append(o.results, operands.Res)
+		o.values = append(o.values, *operands.Res)
+	}
+	return nil
+}
+
+func TestStepObserverCanKeepRes(t *testing.T) {
+	observer := resObserver{}
+	config := cairo_run.CairoRunConfig{Layout: "plain", StepObservers: []vm.StepObserver{&observer}}
+	if _, err := cairo_run.CairoRunProgram(programForStepBenchmark(10), config); err != nil {
+		t.Fatalf("Run failed with error: %s", err)
+	}
+	if len(observer.results) == 0 {
+		t.Fatal("Expected the observer to see res values")
+	}
+	// The res values kept by the observer aren't overwritten by the following steps
+	for i, res := range observer.results {
+		if !res.IsEqual(&observer.values[i]) {
+			t.Errorf("Res observed at step %d changed from %s to %s", i, observer.values[i].ToString(), res.ToString())
+		}
+	}
+}
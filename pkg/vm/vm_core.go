@@ -1,11 +1,12 @@
 package vm
 
 import (
-	"bytes"
-	"fmt"
+	"io"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/logging"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parallel"
 	"github.com/lambdaclass/cairo-vm.go/pkg/types"
 	"github.com/lambdaclass/cairo-vm.go/pkg/utils"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
@@ -14,12 +15,25 @@ import (
 
 const RC_OFFSET_BITS = 16
 
-type VirtualMachineError struct {
-	Msg string
+// Functions called around each step of the vm, used to observe or pause the execution (ie: by a debugger)
+// A nil hook is skipped, and an error returned by a hook aborts the step
+type StepHooks struct {
+	// Called before the hints & instruction at the current pc are executed
+	PreStep func(vm *VirtualMachine) error
+	// Called after the instruction has been executed
+	PostStep func(vm *VirtualMachine) error
 }
 
-func (e *VirtualMachineError) Error() string {
-	return fmt.Sprintf(e.Msg)
+// Receives notifications from the vm around the execution of each instruction
+// Allows building tools such as coverage trackers, profilers or gas meters on top of the vm
+// Unlike StepHooks, observers are notified after the hints at the current pc have been executed
+// Returning an error aborts the execution
+type StepObserver interface {
+	// Called once the instruction at pc has been decoded, before it is executed
+	BeforeStep(pc memory.Relocatable, instruction *Instruction) error
+	// Called once the instruction has been executed, with its trace entry & the operands it used
+	// The operands are copies that can be kept after the call
+	AfterStep(entry TraceEntry, operands *Operands) error
 }
 
 // VirtualMachine represents the Cairo VM.
@@ -31,11 +45,28 @@ type VirtualMachine struct {
 	BuiltinRunners  []builtins.BuiltinRunner
 	Trace           []TraceEntry
 	RelocatedTrace  []RelocatedTraceEntry
-	RelocatedMemory map[uint]lambdaworks.Felt
+	RelocatedMemory memory.RelocatedMemory
 	RunFinished     bool
 	RcLimitsMin     *int
 	RcLimitsMax     *int
 	RunResources    *RunResources
+	Hooks           StepHooks
+	StepObservers   []StepObserver
+	// If set, trace entries are written to it instead of being stored in Trace
+	StreamedTrace *StreamedTrace
+	// Number of workers used to relocate the trace & memory, a non-positive value uses one per available cpu
+	RelocationWorkers int
+	// Holds the values that only live while the operands of an instruction are used (ie: deduced operands & res)
+	// Reset each time the operands are computed
+	Temporaries ValuePool
+	// Receives the events of the execution if set, installed through SetTracer
+	Tracer Tracer
+	// Charges each step & builtin deduction if set, an error returned by it aborts the execution
+	Meter Meter
+	// Maximum size of the relocated memory (the sum of the segment sizes), checked before relocating, zero means no limit
+	// Writing to a huge offset is cheap during the run, but relocation allocates the whole address space up to it
+	MaxMemorySize   uint
+	relocationTable *memory.RelocationTable
 }
 
 func NewVirtualMachine() *VirtualMachine {
@@ -47,57 +78,138 @@ func NewVirtualMachine() *VirtualMachine {
 }
 
 func (v *VirtualMachine) Step(hintProcessor HintProcessor, hintDataMap *map[uint][]any, constants *map[string]lambdaworks.Felt, execScopes *types.ExecutionScopes) error {
+	if v.Hooks.PreStep != nil {
+		if err := v.Hooks.PreStep(v); err != nil {
+			return err
+		}
+	}
+
 	// Run Hint
 	hintDatas, ok := (*hintDataMap)[v.RunContext.Pc.Offset]
 	if ok {
+		extensiveProcessor, extensive := hintProcessor.(ExtensiveHintProcessor)
 		for i := 0; i < len(hintDatas); i++ {
-			err := hintProcessor.ExecuteHint(v, &hintDatas[i], constants, execScopes)
+			var extension HintExtension
+			var err error
+			if extensive {
+				extension, err = extensiveProcessor.ExecuteHintExtensive(v, &hintDatas[i], constants, execScopes)
+			} else {
+				err = hintProcessor.ExecuteHint(v, &hintDatas[i], constants, execScopes)
+			}
 			if err != nil {
-				return err
+				return newHintStepError(v.registers(), err, execScopes)
+			}
+			extension.Merge(*hintDataMap)
+			if v.Tracer != nil {
+				v.Tracer.Trace(TraceEvent{Kind: HintExecutedEvent, Step: v.CurrentStep, Registers: v.registers(), HintIndex: i})
 			}
 		}
 	}
 
 	// Run Instruction
-	encoded_instruction, err := v.Segments.Memory.Get(v.RunContext.Pc)
-	if err != nil {
-		return fmt.Errorf("Failed to fetch instruction at %+v", v.RunContext.Pc)
+	encoded_instruction, ok := v.Segments.Memory.GetValue(v.RunContext.Pc)
+	if !ok {
+		return newStepError(v.registers(), ErrInvalidInstructionEncoding, nil, nil)
 	}
 
 	encoded_instruction_felt, ok := encoded_instruction.GetFelt()
 	if !ok {
-		return errors.New("Wrong instruction encoding")
+		return newStepError(v.registers(), ErrInvalidInstructionEncoding, nil, nil)
 	}
 
 	encoded_instruction_uint, err := encoded_instruction_felt.ToU64()
 	if err != nil {
-		return err
+		return newStepError(v.registers(), err, nil, nil)
 	}
 
 	instruction, err := DecodeInstruction(encoded_instruction_uint)
+	if err != nil {
+		return newStepError(v.registers(), err, nil, nil)
+	}
+
+	if logger := logging.Default(); logger.Enabled(logging.LevelDebug) {
+		logger.Debug("Executing step",
+			logging.F("step", v.CurrentStep),
+			logging.F("pc", v.RunContext.Pc.ToString()),
+			logging.F("ap", v.RunContext.Ap.ToString()),
+			logging.F("fp", v.RunContext.Fp.ToString()),
+			logging.F("instruction", instruction.ToString()))
+	}
+
+	if v.Meter != nil {
+		if err := v.Meter.ChargeStep(v.RunContext.Pc, &instruction); err != nil {
+			return err
+		}
+	}
+
+	if len(v.StepObservers) > 0 {
+		// Observers get a copy of the instruction, so that it isn't moved to the heap when there are none
+		observedInstruction := instruction
+		for _, observer := range v.StepObservers {
+			if err := observer.BeforeStep(v.RunContext.Pc, &observedInstruction); err != nil {
+				return err
+			}
+		}
+	}
+
+	registers := v.registers()
+	err = v.RunInstruction(&instruction)
 	if err != nil {
 		return err
 	}
+	if v.Tracer != nil {
+		v.Tracer.Trace(TraceEvent{Kind: StepEvent, Step: v.CurrentStep - 1, Registers: registers})
+	}
 
-	return v.RunInstruction(&instruction)
+	if v.Hooks.PostStep != nil {
+		return v.Hooks.PostStep(v)
+	}
+	return nil
+}
+
+// Returns the current values of the registers
+func (v *VirtualMachine) registers() TraceEntry {
+	return TraceEntry{Pc: v.RunContext.Pc, Ap: v.RunContext.Ap, Fp: v.RunContext.Fp}
 }
 
 func (v *VirtualMachine) RunInstruction(instruction *Instruction) error {
+	traceEntry := v.registers()
 	operands, operandsAddresses, err := v.ComputeOperands(*instruction)
 	if err != nil {
-		return err
+		if operandsAddresses == (OperandsAddresses{}) {
+			return newStepError(traceEntry, err, instruction, nil)
+		}
+		return newStepError(traceEntry, err, instruction, &operandsAddresses)
 	}
 
 	err = v.OpcodeAssertions(*instruction, operands)
 	if err != nil {
-		return err
+		return newStepError(traceEntry, err, instruction, &operandsAddresses)
+	}
+
+	if logger := logging.Default(); logger.Enabled(logging.LevelDebug) {
+		res := "None"
+		if operands.Res != nil {
+			res = operands.Res.ToString()
+		}
+		logger.Debug("Computed operands",
+			logging.F("dst", operands.Dst.ToString()),
+			logging.F("op0", operands.Op0.ToString()),
+			logging.F("op1", operands.Op1.ToString()),
+			logging.F("res", res))
 	}
 
-	v.Trace = append(v.Trace, TraceEntry{Pc: v.RunContext.Pc, Ap: v.RunContext.Ap, Fp: v.RunContext.Fp})
+	if v.StreamedTrace != nil {
+		if err := v.StreamedTrace.Append(traceEntry); err != nil {
+			return err
+		}
+	} else {
+		v.Trace = append(v.Trace, traceEntry)
+	}
 
-	v.Segments.Memory.MarkAsAccessed(operandsAddresses.DstAddr)
-	v.Segments.Memory.MarkAsAccessed(operandsAddresses.Op0Addr)
-	v.Segments.Memory.MarkAsAccessed(operandsAddresses.Op1Addr)
+	v.MarkAddressAccessed(operandsAddresses.DstAddr)
+	v.MarkAddressAccessed(operandsAddresses.Op0Addr)
+	v.MarkAddressAccessed(operandsAddresses.Op1Addr)
 
 	var off0 int = instruction.Off0 + (1 << (RC_OFFSET_BITS - 1))
 	var off1 int = instruction.Off1 + (1 << (RC_OFFSET_BITS - 1))
@@ -106,64 +218,173 @@ func (v *VirtualMachine) RunInstruction(instruction *Instruction) error {
 	if v.RcLimitsMax == nil {
 		v.RcLimitsMax = new(int)
 		*v.RcLimitsMax = off0
-	} else {
-		var value int
-		value = utils.MaxInt(*v.RcLimitsMax, off0)
-		value = utils.MaxInt(value, off1)
-		value = utils.MaxInt(value, off2)
-		*v.RcLimitsMax = value
 	}
+	*v.RcLimitsMax = utils.MaxInt(utils.MaxInt(*v.RcLimitsMax, off0), utils.MaxInt(off1, off2))
 
 	if v.RcLimitsMin == nil {
 		v.RcLimitsMin = new(int)
 		*v.RcLimitsMin = off0
-	} else {
-		var value int
-		value = utils.MinInt(*v.RcLimitsMin, off0)
-		value = utils.MinInt(value, off1)
-		value = utils.MinInt(value, off2)
-		*v.RcLimitsMin = value
 	}
+	*v.RcLimitsMin = utils.MinInt(utils.MinInt(*v.RcLimitsMin, off0), utils.MinInt(off1, off2))
 
 	err = v.UpdateRegisters(instruction, &operands)
 	if err != nil {
-		return err
+		return newStepError(traceEntry, err, instruction, &operandsAddresses)
 	}
 
 	v.CurrentStep++
+
+	if len(v.StepObservers) > 0 {
+		observedOperands := operands
+		// res may point into the vm's temporaries, which are reused by the next step
+		if operands.Res != nil {
+			res := *operands.Res
+			observedOperands.Res = &res
+		}
+		for _, observer := range v.StepObservers {
+			if err := observer.AfterStep(traceEntry, &observedOperands); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
+// Registers an observer that will be notified around the execution of each instruction
+func (v *VirtualMachine) AddStepObserver(observer StepObserver) {
+	v.StepObservers = append(v.StepObservers, observer)
+}
+
 // Relocates the VM's trace, turning relocatable registers to numbered ones
-func (v *VirtualMachine) RelocateTrace(relocationTable *[]uint) error {
-	if len(*relocationTable) < 2 {
+func (v *VirtualMachine) RelocateTrace(relocationTable memory.RelocationTable) error {
+	if relocationTable.Len() < 2 {
 		return errors.New("No relocation found for execution segment")
 	}
 
-	for _, entry := range v.Trace {
-		v.RelocatedTrace = append(v.RelocatedTrace, RelocatedTraceEntry{
-			Pc: lambdaworks.FeltFromUint64(uint64(entry.Pc.RelocateAddress(relocationTable))),
-			Ap: lambdaworks.FeltFromUint64(uint64(entry.Ap.RelocateAddress(relocationTable))),
-			Fp: lambdaworks.FeltFromUint64(uint64(entry.Fp.RelocateAddress(relocationTable))),
-		})
+	v.relocationTable = &relocationTable
+	// A streamed trace is relocated on the fly when it is read
+	if v.StreamedTrace != nil {
+		return nil
+	}
+
+	relocatedTrace := make([]RelocatedTraceEntry, len(v.Trace))
+	err := parallel.ForEachChunk(len(v.Trace), v.RelocationWorkers, func(_ int, start int, end int) error {
+		for i := start; i < end; i++ {
+			entry, err := relocateTraceEntry(v.Trace[i], relocationTable)
+			if err != nil {
+				return err
+			}
+			relocatedTrace[i] = entry
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	v.RelocatedTrace = append(v.RelocatedTrace, relocatedTrace...)
 
 	return nil
 }
 
+func relocateTraceEntry(entry TraceEntry, relocationTable memory.RelocationTable) (RelocatedTraceEntry, error) {
+	pc, err := relocationTable.Relocate(entry.Pc)
+	if err != nil {
+		return RelocatedTraceEntry{}, err
+	}
+	ap, err := relocationTable.Relocate(entry.Ap)
+	if err != nil {
+		return RelocatedTraceEntry{}, err
+	}
+	fp, err := relocationTable.Relocate(entry.Fp)
+	if err != nil {
+		return RelocatedTraceEntry{}, err
+	}
+	return RelocatedTraceEntry{
+		Pc: lambdaworks.FeltFromUint64(uint64(pc)),
+		Ap: lambdaworks.FeltFromUint64(uint64(ap)),
+		Fp: lambdaworks.FeltFromUint64(uint64(fp)),
+	}, nil
+}
+
+// Returns the number of entries in the trace, whether it is streamed or held in memory
+func (v *VirtualMachine) TraceLen() int {
+	if v.StreamedTrace != nil {
+		return v.StreamedTrace.Len()
+	}
+	return len(v.Trace)
+}
+
+// Calls fn with each entry of the relocated trace, in order
+// Streamed traces are relocated entry by entry, so the relocated trace is never held in memory
+func (v *VirtualMachine) ForEachRelocatedTraceEntry(fn func(entry RelocatedTraceEntry) error) error {
+	if v.StreamedTrace == nil {
+		relocatedTrace, err := v.GetRelocatedTrace()
+		if err != nil {
+			return err
+		}
+		for _, entry := range relocatedTrace {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if v.relocationTable == nil {
+		return ErrTraceNotRelocated
+	}
+	return v.StreamedTrace.ForEach(func(_ int, entry TraceEntry) error {
+		relocated, err := relocateTraceEntry(entry, *v.relocationTable)
+		if err != nil {
+			return err
+		}
+		return fn(relocated)
+	})
+}
+
 func (v *VirtualMachine) GetRelocatedTrace() ([]RelocatedTraceEntry, error) {
 	if len(v.RelocatedTrace) > 0 {
 		return v.RelocatedTrace, nil
 	} else {
-		return nil, errors.New("Trace not relocated")
+		return nil, ErrTraceNotRelocated
+	}
+}
+
+// Returns the relocated address of the first cell of each segment
+func (v *VirtualMachine) GetRelocationTable() (memory.RelocationTable, error) {
+	if v.relocationTable == nil {
+		return memory.RelocationTable{}, ErrTraceNotRelocated
+	}
+	return *v.relocationTable, nil
+}
+
+// Returns the relocated addresses of the public memory cells, in segment order
+// Segments are added to the public memory by MemorySegmentManager.Finalize
+func (v *VirtualMachine) GetPublicMemoryAddresses() ([]uint, error) {
+	relocationTable, err := v.GetRelocationTable()
+	if err != nil {
+		return nil, err
+	}
+	addresses := make([]uint, 0)
+	for segmentIndex := uint(0); segmentIndex < v.Segments.Memory.NumSegments(); segmentIndex++ {
+		base, err := relocationTable.SegmentBase(int(segmentIndex))
+		if err != nil {
+			return nil, err
+		}
+		for _, offset := range v.Segments.PublicMemoryOffsets[segmentIndex] {
+			addresses = append(addresses, base+offset)
+		}
 	}
+	return addresses, nil
 }
 
 func (v *VirtualMachine) Relocate() error {
 	v.Segments.ComputeEffectiveSizes()
-	if len(v.Trace) == 0 {
+	if v.TraceLen() == 0 {
 		return nil
 	}
+	if err := v.checkMemorySize(); err != nil {
+		return err
+	}
 
 	relocationTable, err := v.Segments.RelocateSegments()
 	// This should be unreachable
@@ -171,16 +392,38 @@ func (v *VirtualMachine) Relocate() error {
 		return errors.New("ComputeEffectiveSizes called but RelocateSegments still returned error")
 	}
 
-	relocatedMemory, err := v.Segments.RelocateMemory(&relocationTable)
+	relocatedMemory, err := v.Segments.RelocateMemoryParallel(relocationTable, v.RelocationWorkers)
 	if err != nil {
 		return err
 	}
 
-	v.RelocateTrace(&relocationTable)
+	if err := v.RelocateTrace(relocationTable); err != nil {
+		return err
+	}
 	v.RelocatedMemory = relocatedMemory
 	return nil
 }
 
+// Fails if the relocated memory would be larger than MaxMemorySize
+func (v *VirtualMachine) checkMemorySize() error {
+	if v.MaxMemorySize == 0 {
+		return nil
+	}
+	size := uint(0)
+	for i := uint(0); i < v.Segments.Memory.NumSegments(); i++ {
+		segmentSize, err := v.Segments.GetSegmentSize(i)
+		if err != nil {
+			return err
+		}
+		// Compared this way so that huge segment sizes can't overflow the sum
+		if segmentSize > v.MaxMemorySize-size {
+			return MemoryLimitExceededError(v.MaxMemorySize)
+		}
+		size += segmentSize
+	}
+	return nil
+}
+
 // TODO: Add ExecScopes to this when it's done
 func (vm *VirtualMachine) EndRun() error {
 	err := vm.VerifyAutoDeductions()
@@ -204,9 +447,9 @@ func (vm *VirtualMachine) EndRun() error {
 // Makes sure that all assigned memory cells are consistent with their auto deduction rules.
 func (vm *VirtualMachine) VerifyAutoDeductions() error {
 	for _, builtin := range vm.BuiltinRunners {
-		var index = builtin.Base()
+		view := builtins.NewSegmentView(builtin, &vm.Segments.Memory)
 		for relocatableAddress, value := range vm.Segments.Memory.Data {
-			if relocatableAddress.SegmentIndex != index.SegmentIndex {
+			if !view.IsOutputCell(relocatableAddress) {
 				continue
 			}
 
@@ -220,7 +463,7 @@ func (vm *VirtualMachine) VerifyAutoDeductions() error {
 			}
 
 			if *deducedMemoryCell != value {
-				return &VirtualMachineError{fmt.Sprintf("InconsistentAutoDeduction: %s", builtin.Name())}
+				return InconsistentAutoDeductionError(builtin.Name(), value, *deducedMemoryCell)
 			}
 		}
 	}
@@ -245,23 +488,31 @@ func (vm *VirtualMachine) OpcodeAssertions(instruction Instruction, operands Ope
 	switch instruction.Opcode {
 	case AssertEq:
 		if operands.Res == nil {
-			return &VirtualMachineError{"UnconstrainedResAssertEq"}
+			return ErrUnconstrainedResAssertEq
 		}
 		if !operands.Res.IsEqual(&operands.Dst) {
-			return &VirtualMachineError{fmt.Sprintf("An ASSERT_EQ instruction failed: %s != %s.", operands.Res.ToString(), operands.Dst.ToString())}
+			return DiffAssertValuesError(*operands.Res, operands.Dst)
 		}
 	case Call:
 		new_rel := vm.RunContext.Pc.AddUint(instruction.Size())
 		returnPC := memory.NewMaybeRelocatableRelocatable(new_rel)
 
 		if !operands.Op0.IsEqual(returnPC) {
-			return &VirtualMachineError{"CantWriteReturnPc"}
+			return CantWriteReturnPcError(operands.Op0, *returnPC)
 		}
 
 		returnFP := vm.RunContext.Fp
 		dstRelocatable, _ := operands.Dst.GetRelocatable()
 		if !returnFP.IsEqual(&dstRelocatable) {
-			return &VirtualMachineError{"CantWriteReturnFp"}
+			return CantWriteReturnFpError(operands.Dst, *memory.NewMaybeRelocatableRelocatable(returnFP))
+		}
+	case Ret:
+		// res is the return-pc written by the call, the return-fp (dst) can be a felt when returning from an entrypoint
+		if operands.Res == nil {
+			return ErrUnconstrainedResJump
+		}
+		if _, ok := operands.Res.GetRelocatable(); !ok {
+			return RetInvalidReturnPcError(*operands.Res)
 		}
 	}
 
@@ -273,8 +524,10 @@ func (vm *VirtualMachine) DeduceDst(instruction Instruction, res *memory.MaybeRe
 	case AssertEq:
 		return res
 	case Call:
-		return memory.NewMaybeRelocatableRelocatable(vm.RunContext.Fp)
-
+		return vm.Temporaries.New(*memory.NewMaybeRelocatableRelocatable(vm.RunContext.Fp))
+	case Ret:
+		// dst is the return-fp written by the call, it can't be deduced
+		return nil
 	}
 	return nil
 }
@@ -286,7 +539,10 @@ func (vm *VirtualMachine) DeduceOp0(instruction *Instruction, dst *memory.MaybeR
 	case Call:
 		deduced_op0 := vm.RunContext.Pc
 		deduced_op0.Offset += instruction.Size()
-		return memory.NewMaybeRelocatableRelocatable(deduced_op0), nil, nil
+		return vm.Temporaries.New(*memory.NewMaybeRelocatableRelocatable(deduced_op0)), nil, nil
+	case Ret:
+		// op0 is the return-pc written by the call, it can't be deduced
+		return nil, nil, nil
 	case AssertEq:
 		switch instruction.ResLogic {
 		case ResAdd:
@@ -295,14 +551,14 @@ func (vm *VirtualMachine) DeduceOp0(instruction *Instruction, dst *memory.MaybeR
 				if err != nil {
 					return nil, nil, err
 				}
-				return &deduced_op0, dst, nil
+				return vm.Temporaries.New(deduced_op0), dst, nil
 			}
 		case ResMul:
 			if dst != nil && op1 != nil {
 				dst_felt, dst_is_felt := dst.GetFelt()
 				op1_felt, op1_is_felt := op1.GetFelt()
 				if dst_is_felt && op1_is_felt && !op1_felt.IsZero() {
-					return memory.NewMaybeRelocatableFelt(dst_felt.Div(op1_felt)), dst, nil
+					return vm.Temporaries.New(*memory.NewMaybeRelocatableFelt(dst_felt.Div(op1_felt))), dst, nil
 
 				}
 			}
@@ -322,15 +578,14 @@ func (vm *VirtualMachine) DeduceOp1(instruction *Instruction, dst *memory.MaybeR
 				if err != nil {
 					return nil, nil, err
 				}
-				return &dst_rel, dst, nil
+				return vm.Temporaries.New(dst_rel), dst, nil
 			}
 		case ResMul:
 			if op0 != nil && dst != nil {
 				dst_felt, dst_is_felt := dst.GetFelt()
 				op0_felt, op0_is_felt := op0.GetFelt()
 				if dst_is_felt && op0_is_felt && !op0_felt.IsZero() {
-					res := memory.NewMaybeRelocatableFelt(dst_felt.Div(op0_felt))
-					return res, dst, nil
+					return vm.Temporaries.New(*memory.NewMaybeRelocatableFelt(dst_felt.Div(op0_felt))), dst, nil
 				}
 			}
 		}
@@ -341,23 +596,28 @@ func (vm *VirtualMachine) DeduceOp1(instruction *Instruction, dst *memory.MaybeR
 func (vm *VirtualMachine) ComputeRes(instruction Instruction, op0 memory.MaybeRelocatable, op1 memory.MaybeRelocatable) (*memory.MaybeRelocatable, error) {
 	switch instruction.ResLogic {
 	case ResOp1:
-		return &op1, nil
+		return vm.Temporaries.New(op1), nil
 
 	case ResAdd:
+		// felt + felt is a felt, relocatable + felt (in any order) is a relocatable, ie: pointer arithmetic
+		_, op0_is_rel := op0.GetRelocatable()
+		_, op1_is_rel := op1.GetRelocatable()
+		if op0_is_rel && op1_is_rel {
+			return nil, ComputeResRelocatableAddError(op0, op1)
+		}
 		maybe_rel, err := op0.Add(op1)
 		if err != nil {
 			return nil, err
 		}
-		return &maybe_rel, nil
+		return vm.Temporaries.New(maybe_rel), nil
 
 	case ResMul:
 		num_op0, m_type := op0.GetFelt()
 		num_op1, other_type := op1.GetFelt()
 		if m_type && other_type {
-			result := memory.NewMaybeRelocatableFelt(num_op0.Mul(num_op1))
-			return result, nil
+			return vm.Temporaries.New(*memory.NewMaybeRelocatableFelt(num_op0.Mul(num_op1))), nil
 		} else {
-			return nil, errors.New("ComputeResRelocatableMul")
+			return nil, ComputeResRelocatableMulError(op0, op1)
 		}
 
 	case ResUnconstrained:
@@ -366,44 +626,52 @@ func (vm *VirtualMachine) ComputeRes(instruction Instruction, op0 memory.MaybeRe
 	return nil, nil
 }
 
+// Computes the operands of the instruction, deducing & inserting the missing ones
+// The returned res may point into vm.Temporaries, it remains valid until ComputeOperands is called again
 func (vm *VirtualMachine) ComputeOperands(instruction Instruction) (Operands, OperandsAddresses, error) {
+	vm.Temporaries.Reset()
 	var res *memory.MaybeRelocatable
 
 	dstAddr, err := vm.RunContext.ComputeDstAddr(instruction)
 	if err != nil {
-		return Operands{}, OperandsAddresses{}, errors.New("FailedToComputeDstAddr")
+		return Operands{}, OperandsAddresses{}, err
 	}
-	dst, _ := vm.Segments.Memory.Get(dstAddr)
+	dstValue, dstOk := vm.Segments.Memory.GetValue(dstAddr)
 
 	op0Addr, err := vm.RunContext.ComputeOp0Addr(instruction)
 	if err != nil {
-		return Operands{}, OperandsAddresses{}, fmt.Errorf("FailedToComputeOp0Addr: %s", err)
+		return Operands{}, OperandsAddresses{}, err
 	}
-	op0Op, _ := vm.Segments.Memory.Get(op0Addr)
+	op0Value, op0Ok := vm.Segments.Memory.GetValue(op0Addr)
 
-	op1Addr, err := vm.RunContext.ComputeOp1Addr(instruction, op0Op)
+	op1Addr, err := vm.RunContext.ComputeOp1Addr(instruction, vm.optionalOperand(op0Value, op0Ok))
 	if err != nil {
-		return Operands{}, OperandsAddresses{}, fmt.Errorf("FailedToComputeOp1Addr: %s", err)
+		return Operands{}, OperandsAddresses{}, err
 	}
-	op1Op, _ := vm.Segments.Memory.Get(op1Addr)
+	op1Value, op1Ok := vm.Segments.Memory.GetValue(op1Addr)
 
-	var op0 memory.MaybeRelocatable
-	if op0Op != nil {
-		op0 = *op0Op
-	} else {
-		op0, res, err = vm.ComputeOp0Deductions(op0Addr, &instruction, dst, op1Op)
+	// Returned along with the errors raised past this point, so that they can be reported
+	operandsAddresses := OperandsAddresses{
+		DstAddr: dstAddr,
+		Op0Addr: op0Addr,
+		Op1Addr: op1Addr,
+	}
+
+	// The values read from memory are only passed by pointer to the deductions, which are not run on most steps,
+	// so that they don't have to be allocated on the heap
+	op0 := op0Value
+	if !op0Ok {
+		op0, res, err = vm.ComputeOp0Deductions(op0Addr, &instruction, vm.optionalOperand(dstValue, dstOk), vm.optionalOperand(op1Value, op1Ok))
 		if err != nil {
-			return Operands{}, OperandsAddresses{}, err
+			return Operands{}, operandsAddresses, err
 		}
 	}
 
-	var op1 memory.MaybeRelocatable
-	if op1Op != nil {
-		op1 = *op1Op
-	} else {
-		op1, err = vm.ComputeOp1Deductions(op1Addr, &instruction, dst, op0Op, res)
+	op1 := op1Value
+	if !op1Ok {
+		op1, res, err = vm.ComputeOp1Deductions(op1Addr, &instruction, vm.optionalOperand(dstValue, dstOk), vm.optionalOperand(op0, true), res)
 		if err != nil {
-			return Operands{}, OperandsAddresses{}, err
+			return Operands{}, operandsAddresses, err
 		}
 	}
 
@@ -411,31 +679,40 @@ func (vm *VirtualMachine) ComputeOperands(instruction Instruction) (Operands, Op
 		res, err = vm.ComputeRes(instruction, op0, op1)
 
 		if err != nil {
-			return Operands{}, OperandsAddresses{}, err
+			return Operands{}, operandsAddresses, err
 		}
 	}
 
-	if dst == nil {
+	dst := dstValue
+	if !dstOk {
 		deducedDst := vm.DeduceDst(instruction, res)
-		dst = deducedDst
-		if dst != nil {
-			vm.Segments.Memory.Insert(dstAddr, dst)
+		if deducedDst == nil && instruction.Opcode == Ret {
+			return Operands{}, operandsAddresses, RetMissingReturnFpError(dstAddr)
+		}
+		if deducedDst == nil {
+			return Operands{}, operandsAddresses, FailedToComputeOperandsError("dst", dstAddr)
+		}
+		if err := vm.Segments.Memory.Insert(dstAddr, deducedDst); err != nil {
+			return Operands{}, operandsAddresses, err
 		}
+		dst = *deducedDst
 	}
 
 	operands := Operands{
-		Dst: *dst,
+		Dst: dst,
 		Op0: op0,
 		Op1: op1,
 		Res: res,
 	}
+	return operands, operandsAddresses, nil
+}
 
-	operandsAddresses := OperandsAddresses{
-		DstAddr: dstAddr,
-		Op0Addr: op0Addr,
-		Op1Addr: op1Addr,
+// Returns a pointer to a copy of an operand read from memory, or nil if it was missing
+func (vm *VirtualMachine) optionalOperand(value memory.MaybeRelocatable, ok bool) *memory.MaybeRelocatable {
+	if !ok {
+		return nil
 	}
-	return operands, operandsAddresses, nil
+	return vm.Temporaries.New(value)
 }
 
 // Runs deductions for Op0, first runs builtin deductions, if this fails, attempts to deduce it based on dst and op1
@@ -454,41 +731,62 @@ func (vm *VirtualMachine) ComputeOp0Deductions(op0_addr memory.Relocatable, inst
 		}
 	}
 	if op0 != nil {
-		vm.Segments.Memory.Insert(op0_addr, op0)
+		if err := vm.Segments.Memory.Insert(op0_addr, op0); err != nil {
+			return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, err
+		}
+	} else if instruction.Opcode == Ret {
+		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, RetMissingReturnPcError(op0_addr)
+	} else if isDivisionByZero(instruction, dst, op1) {
+		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, DividedByZeroDeductionError("op0", op0_addr)
 	} else {
-		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, errors.New("Failed to compute or deduce op0")
+		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, FailedToComputeOperandsError("op0", op0_addr)
 	}
 	return *op0, deduced_res, nil
 }
 
 // Runs deductions for Op1, first runs builtin deductions, if this fails, attempts to deduce it based on dst and op0
-// Also updates res if it was also deduced in the process
+// Also returns res if it wasn't known (nil) & was deduced in the process, otherwise returns the given res
 // Inserts the deduced operand
 // Fails if Op1 was not deduced or if an error arose in the process
-func (vm *VirtualMachine) ComputeOp1Deductions(op1_addr memory.Relocatable, instruction *Instruction, dst *memory.MaybeRelocatable, op0 *memory.MaybeRelocatable, res *memory.MaybeRelocatable) (memory.MaybeRelocatable, error) {
+func (vm *VirtualMachine) ComputeOp1Deductions(op1_addr memory.Relocatable, instruction *Instruction, dst *memory.MaybeRelocatable, op0 *memory.MaybeRelocatable, res *memory.MaybeRelocatable) (memory.MaybeRelocatable, *memory.MaybeRelocatable, error) {
 	op1, err := vm.DeduceMemoryCell(op1_addr)
 	if err != nil {
-		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), err
+		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, err
 	}
 	if op1 == nil {
 		var deducedRes *memory.MaybeRelocatable
 		op1, deducedRes, err = vm.DeduceOp1(instruction, dst, op0)
 		if err != nil {
-			return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), err
+			return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, err
 		}
 		if res == nil {
 			res = deducedRes
 		}
 	}
 	if op1 != nil {
-		vm.Segments.Memory.Insert(op1_addr, op1)
+		if err := vm.Segments.Memory.Insert(op1_addr, op1); err != nil {
+			return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, err
+		}
+	} else if isDivisionByZero(instruction, dst, op0) {
+		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, DividedByZeroDeductionError("op1", op1_addr)
 	} else {
-		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), errors.New("Failed to compute or deduce op1")
+		return *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), nil, FailedToComputeOperandsError("op1", op1_addr)
+	}
+	return *op1, res, nil
+}
+
+// Checks if deducing an operand of a multiplication would divide dst by a zero operand
+func isDivisionByZero(instruction *Instruction, dst *memory.MaybeRelocatable, operand *memory.MaybeRelocatable) bool {
+	if instruction.Opcode != AssertEq || instruction.ResLogic != ResMul || dst == nil || operand == nil {
+		return false
 	}
-	return *op1, nil
+	_, dstIsFelt := dst.GetFelt()
+	operandFelt, operandIsFelt := operand.GetFelt()
+	return dstIsFelt && operandIsFelt && operandFelt.IsZero()
 }
 
 // Updates the values of the RunContext's registers according to the executed instruction
+// The return-pc of a ret is validated by OpcodeAssertions, which runs before any register is updated
 func (vm *VirtualMachine) UpdateRegisters(instruction *Instruction, operands *Operands) error {
 	if err := vm.UpdateFp(instruction, operands); err != nil {
 		return err
@@ -506,20 +804,20 @@ func (vm *VirtualMachine) UpdatePc(instruction *Instruction, operands *Operands)
 		vm.RunContext.Pc.Offset += instruction.Size()
 	case PcUpdateJump:
 		if operands.Res == nil {
-			return errors.New("Res.UNCONSTRAINED cannot be used with PcUpdate.JUMP")
+			return ErrUnconstrainedResJump
 		}
 		res, ok := operands.Res.GetRelocatable()
 		if !ok {
-			return errors.New("An integer value as Res cannot be used with PcUpdate.JUMP")
+			return ErrJumpNotRelocatable
 		}
 		vm.RunContext.Pc = res
 	case PcUpdateJumpRel:
 		if operands.Res == nil {
-			return errors.New("Res.UNCONSTRAINED cannot be used with PcUpdate.JUMP_REL")
+			return ErrUnconstrainedResJumpRel
 		}
 		res, ok := operands.Res.GetFelt()
 		if !ok {
-			return errors.New("A relocatable value as Res cannot be used with PcUpdate.JUMP_REL")
+			return ErrJumpRelNotInt
 		}
 		new_pc, err := vm.RunContext.Pc.AddFelt(res)
 		if err != nil {
@@ -546,7 +844,7 @@ func (vm *VirtualMachine) UpdateAp(instruction *Instruction, operands *Operands)
 	switch instruction.ApUpdate {
 	case ApUpdateAdd:
 		if operands.Res == nil {
-			return errors.New("Res.UNCONSTRAINED cannot be used with ApUpdate.ADD")
+			return ErrUnconstrainedResAdd
 		}
 		new_ap, err := vm.RunContext.Ap.AddMaybeRelocatable(*operands.Res)
 		if err != nil {
@@ -590,44 +888,131 @@ func (vm *VirtualMachine) DeduceMemoryCell(addr memory.Relocatable) (*memory.May
 	}
 	for i := range vm.BuiltinRunners {
 		if vm.BuiltinRunners[i].Base().SegmentIndex == addr.SegmentIndex {
-			return vm.BuiltinRunners[i].DeduceMemoryCell(addr, &vm.Segments.Memory)
+			value, err := vm.BuiltinRunners[i].DeduceMemoryCell(addr, &vm.Segments.Memory)
+			if vm.Meter != nil && value != nil && err == nil {
+				if err := vm.Meter.ChargeBuiltin(vm.BuiltinRunners[i].Name(), addr); err != nil {
+					return nil, err
+				}
+			}
+			if vm.Tracer != nil && value != nil && err == nil {
+				vm.Tracer.Trace(TraceEvent{Kind: BuiltinDeductionEvent, Step: vm.CurrentStep, Address: addr, Value: *value, Builtin: vm.BuiltinRunners[i].Name()})
+			}
+			return value, err
 		}
 	}
 	return nil, nil
 }
 
-// Write the values hosted in the output builtin's segment.
+// Write the values hosted in the output builtin's segment, one per line.
+// Missing cells are written as <missing>.
 // Does nothing if the output builtin is not present in the program.
-func (vm *VirtualMachine) WriteOutput(writer *bytes.Buffer) {
-	for _, builtin := range vm.BuiltinRunners {
-		if builtin.Name() == builtins.OUTPUT_BUILTIN_NAME {
-			segmentUsedSizes := vm.Segments.ComputeEffectiveSizes()
-			segmentIndex := builtin.Base().SegmentIndex
-			outputSegmentSize := segmentUsedSizes[uint(segmentIndex)]
-
-			for i := 0; i < int(outputSegmentSize); i++ {
-				addr := memory.NewRelocatable(segmentIndex, uint(i))
-				formattedValue, err := vm.Segments.Memory.Get(addr)
-				if err != nil {
-					writer.WriteString("<missing>\n")
-				} else {
-					writer.WriteString(formattedValue.ToString())
-					writer.WriteString("\n")
-				}
-			}
-			break
+func (vm *VirtualMachine) WriteOutput(writer io.Writer) error {
+	outputBuiltin, err := vm.GetOutputBuiltin()
+	if err != nil {
+		return nil
+	}
+	segmentUsedSizes := vm.Segments.ComputeEffectiveSizes()
+	segmentIndex := outputBuiltin.Base().SegmentIndex
+	outputSegmentSize := segmentUsedSizes[uint(segmentIndex)]
+
+	for i := 0; i < int(outputSegmentSize); i++ {
+		addr := memory.NewRelocatable(segmentIndex, uint(i))
+		formattedValue := "<missing>"
+		value, err := vm.Segments.Memory.Get(addr)
+		if err == nil {
+			formattedValue = value.ToString()
+		}
+		if _, err := io.WriteString(writer, formattedValue+"\n"); err != nil {
+			return err
 		}
 	}
+	return nil
 }
 
-func (vm *VirtualMachine) GetBuiltinRunner(builtinName string) (*builtins.BuiltinRunner, error) {
+// Returns the values hosted in the output builtin's segment
+// Fails if a cell is missing or holds a relocatable value
+// Returns an empty slice if the output builtin is not present in the program.
+func (vm *VirtualMachine) GetOutputs() ([]lambdaworks.Felt, error) {
+	outputBuiltin, err := vm.GetOutputBuiltin()
+	if err != nil {
+		return []lambdaworks.Felt{}, nil
+	}
+	segmentUsedSizes := vm.Segments.ComputeEffectiveSizes()
+	segmentIndex := outputBuiltin.Base().SegmentIndex
+	outputSegmentSize := segmentUsedSizes[uint(segmentIndex)]
+
+	outputs := make([]lambdaworks.Felt, 0, outputSegmentSize)
+	for i := uint(0); i < outputSegmentSize; i++ {
+		addr := memory.NewRelocatable(segmentIndex, i)
+		value, err := vm.Segments.Memory.Get(addr)
+		if err != nil {
+			return nil, MissingOutputError(addr)
+		}
+		felt, ok := value.GetFelt()
+		if !ok {
+			rel, _ := value.GetRelocatable()
+			return nil, RelocatableOutputError(addr, rel)
+		}
+		outputs = append(outputs, felt)
+	}
+	return outputs, nil
+}
 
-	for _, builtin := range vm.BuiltinRunners {
-		if builtin.Name() == builtinName {
-			return &builtin, nil
+// Inserts value at ap + offset, as done by hints writing to memory[ap]
+func (v *VirtualMachine) InsertAtAp(offset int, value *memory.MaybeRelocatable) error {
+	addr, err := v.RunContext.Ap.AddInt(offset)
+	if err != nil {
+		return err
+	}
+	return v.Segments.Memory.Insert(addr, value)
+}
+
+// Inserts value at the dst address of the instruction, computed from the current registers
+func (v *VirtualMachine) InsertInDst(instruction Instruction, value *memory.MaybeRelocatable) error {
+	addr, err := v.RunContext.ComputeDstAddr(instruction)
+	if err != nil {
+		return err
+	}
+	return v.Segments.Memory.Insert(addr, value)
+}
+
+// Marks the cell at address as accessed
+// The accessed cells are the ones taken into account when counting memory holes
+func (v *VirtualMachine) MarkAddressAccessed(address memory.Relocatable) {
+	v.Segments.Memory.MarkAsAccessed(address)
+}
+
+// Marks the size cells starting at base as accessed
+func (v *VirtualMachine) MarkAddressRangeAccessed(base memory.Relocatable, size uint) {
+	v.Segments.Memory.MarkRangeAsAccessed(base, size)
+}
+
+// Returns the addresses of the cells of a segment that were accessed, sorted by offset
+func (v *VirtualMachine) GetAccessedAddresses(segmentIndex int) []memory.Relocatable {
+	return v.Segments.Memory.GetAccessedAddresses(segmentIndex)
+}
+
+// Returns the builtin runner with the given name, fails if the program doesn't use it
+func (vm *VirtualMachine) GetBuiltinRunner(builtinName string) (*builtins.BuiltinRunner, error) {
+	for i := range vm.BuiltinRunners {
+		if vm.BuiltinRunners[i].Name() == builtinName {
+			return &vm.BuiltinRunners[i], nil
 		}
 	}
-	return nil, &VirtualMachineError{"BuiltinNotFound"}
+	return nil, BuiltinNotFoundError(builtinName)
+}
+
+// Returns the output builtin runner, fails if the program doesn't use it
+func (vm *VirtualMachine) GetOutputBuiltin() (*builtins.OutputBuiltinRunner, error) {
+	builtin, err := vm.GetBuiltinRunner(builtins.OUTPUT_BUILTIN_NAME)
+	if err != nil {
+		return nil, err
+	}
+	outputBuiltin, ok := (*builtin).(*builtins.OutputBuiltinRunner)
+	if !ok {
+		return nil, errors.New("could not cast to OutputBuiltinRunner")
+	}
+	return outputBuiltin, nil
 }
 
 func (vm *VirtualMachine) GetRangeCheckBound() (lambdaworks.Felt, error) {
@@ -2,15 +2,20 @@ package vm_test
 
 import (
 	"bytes"
-
+	"errors"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/lambdaclass/cairo-vm.go/pkg/builtins"
 	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/parser"
+	"github.com/lambdaclass/cairo-vm.go/pkg/types"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/cairo_run"
 	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
 )
 
 func TestDeduceOp0OpcodeRet(t *testing.T) {
@@ -511,6 +516,181 @@ func TestComputeOperandsAddAp(t *testing.T) {
 	}
 }
 
+// Decodes the instruction & computes its operands with fp = ap = (1, 4), [fp - 3] = op0 & [fp - 4] = op1
+// The instruction's immediate (if any) is 1
+func computeOperandsOfEncoded(t *testing.T, encoded uint64, op0 memory.MaybeRelocatable, op1 memory.MaybeRelocatable) (vm.Operands, error) {
+	instruction, err := vm.DecodeInstruction(encoded)
+	if err != nil {
+		t.Fatalf("DecodeInstruction failed with error: %s", err)
+	}
+	vmachine := vm.NewVirtualMachine()
+	vmachine.Segments.AddSegment()
+	vmachine.Segments.AddSegment()
+	vmachine.Segments.AddSegment()
+	vmachine.RunContext = vm.RunContext{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 4), Fp: memory.NewRelocatable(1, 4)}
+	vmachine.Segments.Memory.Insert(memory.NewRelocatable(0, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne()))
+	vmachine.Segments.Memory.Insert(memory.NewRelocatable(1, 1), &op0)
+	vmachine.Segments.Memory.Insert(memory.NewRelocatable(1, 0), &op1)
+	operands, _, err := vmachine.ComputeOperands(instruction)
+	return operands, err
+}
+
+func TestComputeResAddRelocatableAndImmediate(t *testing.T) {
+	// [ap] = [fp - 3] + 1, ap++ (ie: ptr = ptr + 1 in an array loop)
+	operands, err := computeOperandsOfEncoded(t, 0x482680017ffd8000, *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 5)), *memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()))
+	if err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	expected := *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 6))
+	if *operands.Res != expected || operands.Dst != expected {
+		t.Errorf("Wrong res or dst: %s, %s", operands.Res.ToString(), operands.Dst.ToString())
+	}
+}
+
+func TestComputeResAddFeltAndRelocatable(t *testing.T) {
+	// [ap] = [fp - 3] + [fp - 4], ap++
+	operands, err := computeOperandsOfEncoded(t, 0x482a7ffc7ffd8000, *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(3)), *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 1)))
+	if err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	expected := *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 4))
+	if *operands.Res != expected || operands.Dst != expected {
+		t.Errorf("Wrong res or dst: %s, %s", operands.Res.ToString(), operands.Dst.ToString())
+	}
+}
+
+func TestComputeResAddFelts(t *testing.T) {
+	// [ap] = [fp - 3] + [fp - 4], ap++
+	operands, err := computeOperandsOfEncoded(t, 0x482a7ffc7ffd8000, *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(3)), *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(4)))
+	if err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	if *operands.Res != *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(7)) {
+		t.Errorf("Wrong res: %s", operands.Res.ToString())
+	}
+}
+
+func TestComputeResAddRelocatables(t *testing.T) {
+	// [ap] = [fp - 3] + [fp - 4], ap++
+	_, err := computeOperandsOfEncoded(t, 0x482a7ffc7ffd8000, *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 1)), *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 3)))
+	if !errors.Is(err, vm.ErrComputeResRelocatableAdd) {
+		t.Errorf("Expected ErrComputeResRelocatableAdd, got %v", err)
+	}
+}
+
+// Computes the operands of [ap] = [ap + 1] op [ap + 2] with ap = (1, 0), where the missing operands (nil) have to be deduced
+// Returns the vm, so that the deduced operands can be read back from memory
+func computeMissingOperands(t *testing.T, resLogic vm.ResLogic, dst, op0, op1 *memory.MaybeRelocatable) (*vm.VirtualMachine, vm.Operands) {
+	instruction := vm.Instruction{
+		Off0:     0,
+		Off1:     1,
+		Off2:     2,
+		DstReg:   vm.AP,
+		Op0Reg:   vm.AP,
+		Op1Addr:  vm.Op1SrcAP,
+		ResLogic: resLogic,
+		Opcode:   vm.AssertEq,
+	}
+	vmachine := vm.NewVirtualMachine()
+	vmachine.Segments.AddSegment()
+	vmachine.Segments.AddSegment()
+	vmachine.RunContext = vm.RunContext{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 0), Fp: memory.NewRelocatable(1, 0)}
+	for i, value := range []*memory.MaybeRelocatable{dst, op0, op1} {
+		if value != nil {
+			vmachine.Segments.Memory.Insert(memory.NewRelocatable(1, uint(i)), value)
+		}
+	}
+	operands, _, err := vmachine.ComputeOperands(instruction)
+	if err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	return vmachine, operands
+}
+
+// Checks that the operand was deduced & written back to memory
+func checkDeducedOperand(t *testing.T, vmachine *vm.VirtualMachine, name string, offset uint, operand memory.MaybeRelocatable, expected uint64) {
+	expectedValue := *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(expected))
+	if operand != expectedValue {
+		t.Errorf("Wrong deduced %s. Expected %d, got %s", name, expected, operand.ToString())
+	}
+	if value, ok := vmachine.Segments.Memory.GetValue(memory.NewRelocatable(1, offset)); !ok || value != expectedValue {
+		t.Errorf("The deduced %s wasn't inserted into memory", name)
+	}
+}
+
+func TestComputeOperandsDeducesOp0(t *testing.T) {
+	vmachine, operands := computeMissingOperands(t, vm.ResAdd, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5)), nil, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(3)))
+	checkDeducedOperand(t, vmachine, "op0", 1, operands.Op0, 2)
+	if operands.Res == nil || *operands.Res != operands.Dst {
+		t.Errorf("Res should be deduced along op0 as dst")
+	}
+}
+
+func TestComputeOperandsDeducesOp1(t *testing.T) {
+	vmachine, operands := computeMissingOperands(t, vm.ResMul, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(6)), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)), nil)
+	checkDeducedOperand(t, vmachine, "op1", 2, operands.Op1, 3)
+	if operands.Res == nil || *operands.Res != operands.Dst {
+		t.Errorf("Res should be deduced along op1 as dst")
+	}
+}
+
+func TestComputeOperandsDeducesDst(t *testing.T) {
+	vmachine, operands := computeMissingOperands(t, vm.ResAdd, nil, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(3)))
+	checkDeducedOperand(t, vmachine, "dst", 0, operands.Dst, 5)
+}
+
+func TestComputeOperandsDeducesOp0FromBuiltin(t *testing.T) {
+	// [ap] = [fp + 2] + [ap + 1], with fp pointing to a bitwise instance, whose x & y output is op0
+	instruction := vm.Instruction{
+		Off0:     0,
+		Off1:     2,
+		Off2:     1,
+		DstReg:   vm.AP,
+		Op0Reg:   vm.FP,
+		Op1Addr:  vm.Op1SrcAP,
+		ResLogic: vm.ResAdd,
+		Opcode:   vm.AssertEq,
+	}
+	vmachine := vm.NewVirtualMachine()
+	vmachine.Segments.AddSegment()
+	vmachine.Segments.AddSegment()
+	bitwise := builtins.NewBitwiseBuiltinRunner(256)
+	bitwise.InitializeSegments(&vmachine.Segments)
+	vmachine.BuiltinRunners = append(vmachine.BuiltinRunners, bitwise)
+	base := bitwise.Base()
+	vmachine.RunContext = vm.RunContext{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 0), Fp: base}
+	vmachine.Segments.Memory.Insert(base, memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(12)))
+	vmachine.Segments.Memory.Insert(base.AddUint(1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(10)))
+	vmachine.Segments.Memory.Insert(memory.NewRelocatable(1, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5)))
+
+	operands, _, err := vmachine.ComputeOperands(instruction)
+	if err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	expectedOp0 := *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(8))
+	if value, ok := vmachine.Segments.Memory.GetValue(base.AddUint(2)); operands.Op0 != expectedOp0 || !ok || value != expectedOp0 {
+		t.Errorf("Op0 should be deduced by the builtin & inserted into memory, got %s", operands.Op0.ToString())
+	}
+	checkDeducedOperand(t, vmachine, "dst", 0, operands.Dst, 13)
+}
+
+func TestComputeOperandsUndeducibleOp1(t *testing.T) {
+	// Neither dst nor op1 are known, so op1 can't be deduced
+	instruction := vm.Instruction{Off1: 1, Off2: 2, DstReg: vm.AP, Op0Reg: vm.AP, Op1Addr: vm.Op1SrcAP, ResLogic: vm.ResAdd, Opcode: vm.AssertEq}
+	vmachine := vm.NewVirtualMachine()
+	vmachine.Segments.AddSegment()
+	vmachine.Segments.AddSegment()
+	vmachine.RunContext = vm.RunContext{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 0), Fp: memory.NewRelocatable(1, 0)}
+	vmachine.Segments.Memory.Insert(memory.NewRelocatable(1, 1), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(2)))
+	_, addresses, err := vmachine.ComputeOperands(instruction)
+	if !errors.Is(err, vm.ErrFailedToComputeOperands) {
+		t.Errorf("Expected ErrFailedToComputeOperands, got %v", err)
+	}
+	if addresses.Op1Addr != memory.NewRelocatable(1, 2) {
+		t.Errorf("The operand addresses should be returned along the error, got %v", addresses)
+	}
+}
+
 func TestDeduceMemoryCellNoBuiltins(t *testing.T) {
 	vm := vm.NewVirtualMachine()
 	addr := memory.Relocatable{}
@@ -534,7 +714,7 @@ func TestRelocateTraceOneEntry(t *testing.T) {
 
 	virtualMachine.Segments.ComputeEffectiveSizes()
 	relocationTable, _ := virtualMachine.Segments.RelocateSegments()
-	err := virtualMachine.RelocateTrace(&relocationTable)
+	err := virtualMachine.RelocateTrace(relocationTable)
 	if err != nil {
 		t.Errorf("Trace relocation error failed with test: %s", err)
 	}
@@ -556,7 +736,7 @@ func TestWriteBinaryMemoryFile(t *testing.T) {
 	relocatedMemory[3] = lambdaworks.FeltFromUint64(30)
 
 	var actualMemoryBuffer bytes.Buffer
-	cairo_run.WriteEncodedMemory(relocatedMemory, &actualMemoryBuffer)
+	cairo_run.WriteEncodedMemory(memory.RelocatedMemoryFromMap(relocatedMemory), &actualMemoryBuffer)
 }
 
 func buildTestProgramMemory(virtualMachine *vm.VirtualMachine) {
@@ -594,7 +774,7 @@ func TestOpcodeAssertionsResUnconstrained(t *testing.T) {
 	testVm := vm.NewVirtualMachine()
 
 	err := testVm.OpcodeAssertions(instruction, operands)
-	if err.Error() != "UnconstrainedResAssertEq" {
+	if !errors.Is(err, vm.ErrUnconstrainedResAssertEq) {
 		t.Error("Assertion should error out with UnconstrainedResAssertEq")
 	}
 }
@@ -623,10 +803,16 @@ func TestOpcodeAssertionsInstructionFailed(t *testing.T) {
 
 	testVm := vm.NewVirtualMachine()
 	err := testVm.OpcodeAssertions(instruction, operands)
-	if err == nil {
+	if !errors.Is(err, vm.ErrDiffAssertValues) || !errors.Is(err, vm.ErrVirtualMachine) {
 		t.Error("Assertion should error out with DiffAssertValues")
 	}
-
+	var vmErr *vm.VirtualMachineError
+	if !errors.As(err, &vmErr) {
+		t.Error("DiffAssertValues should be a VirtualMachineError")
+	}
+	if err.Error() != "An ASSERT_EQ instruction failed: 8 != 9." {
+		t.Errorf("Wrong error message: %s", err)
+	}
 }
 
 func TestOpcodeAssertionsInstructionFailedRelocatables(t *testing.T) {
@@ -683,7 +869,7 @@ func TestOpcodeAssertionsInconsistentOp0(t *testing.T) {
 	testVm := vm.NewVirtualMachine()
 	testVm.RunContext.Pc = memory.NewRelocatable(0, 4)
 	err := testVm.OpcodeAssertions(instruction, operands)
-	if err.Error() != "CantWriteReturnPc" {
+	if !errors.Is(err, vm.ErrCantWriteReturnPc) {
 		t.Error("Assertion should error out with CantWriteReturnPc")
 	}
 }
@@ -713,7 +899,7 @@ func TestOpcodeAssertionsInconsistentDst(t *testing.T) {
 	testVm := vm.NewVirtualMachine()
 	testVm.RunContext.Fp = memory.NewRelocatable(1, 6)
 	err := testVm.OpcodeAssertions(instruction, operands)
-	if err.Error() != "CantWriteReturnFp" {
+	if !errors.Is(err, vm.ErrCantWriteReturnFp) {
 		t.Error("Assertion should error out with CantWriteReturnFp")
 	}
 }
@@ -855,6 +1041,32 @@ func TestDeduceOp1OpcodeAssertEqResMulNonZeroOp0(t *testing.T) {
 	}
 }
 
+func TestComputeOp1DeductionsResMulZeroOp0(t *testing.T) {
+	instruction := vm.Instruction{ResLogic: vm.ResMul, Opcode: vm.AssertEq}
+	virtualMachine := vm.NewVirtualMachine()
+	virtualMachine.Segments.AddSegment()
+
+	dst := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(4))
+	op0 := memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero())
+	_, _, err := virtualMachine.ComputeOp1Deductions(memory.NewRelocatable(0, 1), &instruction, dst, op0, nil)
+	if !errors.Is(err, lambdaworks.ErrDividedByZero) || !errors.Is(err, vm.ErrFailedToComputeOperands) {
+		t.Errorf("Expected a division by zero error, got: %v", err)
+	}
+}
+
+func TestComputeOp0DeductionsResMulZeroOp1(t *testing.T) {
+	instruction := vm.Instruction{ResLogic: vm.ResMul, Opcode: vm.AssertEq}
+	virtualMachine := vm.NewVirtualMachine()
+	virtualMachine.Segments.AddSegment()
+
+	dst := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(4))
+	op1 := memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero())
+	_, _, err := virtualMachine.ComputeOp0Deductions(memory.NewRelocatable(0, 1), &instruction, dst, op1)
+	if !errors.Is(err, lambdaworks.ErrDividedByZero) || !errors.Is(err, vm.ErrFailedToComputeOperands) {
+		t.Errorf("Expected a division by zero error, got: %v", err)
+	}
+}
+
 func TestDeduceOp1OpcodeAssertEqResMulZeroOp0(t *testing.T) {
 	instruction := vm.Instruction{
 		Off1:     1,
@@ -1089,6 +1301,30 @@ func TestGetFooBuiltinReturnsNilAndError(t *testing.T) {
 	}
 }
 
+func TestGetOutputBuiltin(t *testing.T) {
+	vm := vm.NewVirtualMachine()
+	output_builtin := builtins.NewOutputBuiltinRunner()
+	vm.BuiltinRunners = append(vm.BuiltinRunners, builtins.NewPedersenBuiltinRunner(256), output_builtin)
+
+	obtained_output, err := vm.GetOutputBuiltin()
+	if err != nil {
+		t.Errorf("GetOutputBuiltin failed with error: %s", err)
+	}
+	if obtained_output != output_builtin {
+		t.Error("GetOutputBuiltin didn't return the vm's output builtin")
+	}
+}
+
+func TestGetOutputBuiltinMissing(t *testing.T) {
+	vm := vm.NewVirtualMachine()
+	vm.BuiltinRunners = append(vm.BuiltinRunners, builtins.NewPedersenBuiltinRunner(256))
+
+	_, err := vm.GetOutputBuiltin()
+	if err == nil {
+		t.Error("GetOutputBuiltin should fail if the program doesn't use the output builtin")
+	}
+}
+
 func TestReadReturnValuesOk(t *testing.T) {
 	vm := vm.NewVirtualMachine()
 	vm.Segments.AddSegment()
@@ -1116,3 +1352,421 @@ func TestReadReturnValuesOk(t *testing.T) {
 		t.Errorf("Wrong return values.\n Expected: %+v, got: %+v", expectedReturnValues, returnValues)
 	}
 }
+
+type recordingObserver struct {
+	opcodes      []vm.Opcode
+	traceEntries []vm.TraceEntry
+	results      []string
+	failAtStep   int
+}
+
+func (o *recordingObserver) BeforeStep(pc memory.Relocatable, instruction *vm.Instruction) error {
+	if len(o.opcodes) == o.failAtStep {
+		return errors.New("gas exhausted")
+	}
+	o.opcodes = append(o.opcodes, instruction.Opcode)
+	return nil
+}
+
+func (o *recordingObserver) AfterStep(entry vm.TraceEntry, operands *vm.Operands) error {
+	o.traceEntries = append(o.traceEntries, entry)
+	o.results = append(o.results, operands.Dst.ToString())
+	return nil
+}
+
+// main:  [ap] = 5, ap++; ret
+func programForObserverTest() vm.Program {
+	return vmtest.Program("0x480680017fff8000", "5", "0x208b7fff7fff7ffe")
+}
+
+func TestStepObserverIsNotified(t *testing.T) {
+	observer := recordingObserver{failAtStep: -1}
+	config := cairo_run.CairoRunConfig{Layout: "plain", StepObservers: []vm.StepObserver{&observer}}
+	_, err := cairo_run.CairoRunProgram(programForObserverTest(), config)
+	if err != nil {
+		t.Errorf("CairoRunProgram failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(observer.opcodes, []vm.Opcode{vm.AssertEq, vm.Ret}) {
+		t.Errorf("Wrong opcodes observed: %v", observer.opcodes)
+	}
+	expectedEntries := []vm.TraceEntry{
+		{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 2), Fp: memory.NewRelocatable(1, 2)},
+		{Pc: memory.NewRelocatable(0, 2), Ap: memory.NewRelocatable(1, 3), Fp: memory.NewRelocatable(1, 2)},
+	}
+	if !reflect.DeepEqual(observer.traceEntries, expectedEntries) {
+		t.Errorf("Wrong trace entries observed: %v", observer.traceEntries)
+	}
+	if observer.results[0] != "5" {
+		t.Errorf("Wrong dst operand observed: %s", observer.results[0])
+	}
+}
+
+func TestStepObserverAbortsExecution(t *testing.T) {
+	observer := recordingObserver{failAtStep: 1}
+	config := cairo_run.CairoRunConfig{Layout: "plain", StepObservers: []vm.StepObserver{&observer}}
+	runner, err := cairo_run.CairoRunProgram(programForObserverTest(), config)
+	if err == nil || err.Error() != "gas exhausted" {
+		t.Errorf("Expected the observer's error, got: %v", err)
+	}
+	if runner.Vm.CurrentStep != 1 {
+		t.Errorf("Execution should stop after the first step, current step: %d", runner.Vm.CurrentStep)
+	}
+}
+
+func TestStepErrorHasContext(t *testing.T) {
+	// main: [ap] = 5; [ap] = 6; ret
+	program := vmtest.Program("0x400680017fff8000", "5", "0x400680017fff8000", "6", "0x208b7fff7fff7ffe")
+	_, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "plain"})
+	var stepErr *vm.StepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("Expected a StepError, got: %v", err)
+	}
+	if !errors.Is(err, vm.ErrDiffAssertValues) {
+		t.Errorf("StepError should wrap the cause, got: %v", err)
+	}
+	if stepErr.Pc != memory.NewRelocatable(0, 2) || stepErr.Ap != memory.NewRelocatable(1, 2) {
+		t.Errorf("Wrong registers in StepError: %+v", stepErr)
+	}
+	if stepErr.Instruction == nil || stepErr.Instruction.Opcode != vm.AssertEq {
+		t.Errorf("Wrong instruction in StepError: %+v", stepErr.Instruction)
+	}
+	if stepErr.OperandsAddresses == nil || stepErr.OperandsAddresses.DstAddr != memory.NewRelocatable(1, 2) {
+		t.Errorf("Wrong operand addresses in StepError: %+v", stepErr.OperandsAddresses)
+	}
+	expected := "Error at pc={0:2}: An ASSERT_EQ instruction failed: 6 != 5.\nap={1:2}, fp={1:2}\n"
+	if !strings.HasPrefix(err.Error(), expected) {
+		t.Errorf("Wrong error message: %s", err)
+	}
+}
+
+type failingHintProcessor struct{}
+
+func (p *failingHintProcessor) CompileHint(hintParams *parser.HintParams, referenceManager *parser.ReferenceManager) (any, error) {
+	return nil, nil
+}
+
+func (p *failingHintProcessor) ExecuteHint(v *vm.VirtualMachine, hintData *any, constants *map[string]lambdaworks.Felt, execScopes *types.ExecutionScopes) error {
+	_, err := execScopes.Get("__dict_manager")
+	return err
+}
+
+func TestHintStepErrorHasExecutionScopes(t *testing.T) {
+	testVm := vm.NewVirtualMachine()
+	testVm.Segments.AddSegment()
+	execScopes := types.NewExecutionScopes()
+	execScopes.EnterScope(map[string]interface{}{"n": uint64(3)})
+	hintDataMap := map[uint][]any{0: {nil}}
+
+	err := testVm.Step(&failingHintProcessor{}, &hintDataMap, nil, execScopes)
+	var stepErr *vm.StepError
+	if !errors.As(err, &stepErr) {
+		t.Fatalf("Expected a StepError, got: %v", err)
+	}
+	expectedScopes := [][]types.ScopeVariable{{}, {{Name: "n", Type: "uint64"}}}
+	if !reflect.DeepEqual(stepErr.ExecutionScopes, expectedScopes) {
+		t.Errorf("Wrong execution scopes in StepError: %+v", stepErr.ExecutionScopes)
+	}
+	if !strings.HasSuffix(err.Error(), "Execution scopes:\nscope 0: <empty>\nscope 1: n (uint64)") {
+		t.Errorf("Wrong error message: %s", err)
+	}
+}
+
+// Executes hints which's data is the pc offset of the hint it registers, or nil for hints that register nothing
+type extendingHintProcessor struct {
+	executed []any
+}
+
+func (p *extendingHintProcessor) CompileHint(hintParams *parser.HintParams, referenceManager *parser.ReferenceManager) (any, error) {
+	return uint(2), nil
+}
+
+func (p *extendingHintProcessor) ExecuteHint(v *vm.VirtualMachine, hintData *any, constants *map[string]lambdaworks.Felt, execScopes *types.ExecutionScopes) error {
+	return errors.New("ExecuteHint shouldn't be used by an ExtensiveHintProcessor")
+}
+
+func (p *extendingHintProcessor) ExecuteHintExtensive(v *vm.VirtualMachine, hintData *any, constants *map[string]lambdaworks.Felt, execScopes *types.ExecutionScopes) (vm.HintExtension, error) {
+	p.executed = append(p.executed, *hintData)
+	pc, ok := (*hintData).(uint)
+	if !ok {
+		return nil, nil
+	}
+	return vm.HintExtension{pc: {nil}}, nil
+}
+
+func TestHintExtensionIsExecuted(t *testing.T) {
+	program := programForObserverTest()
+	program.Hints = map[uint][]parser.HintParams{0: {{Code: "register"}}}
+	hintProcessor := extendingHintProcessor{}
+	runner, err := cairo_run.NewRunner(program, cairo_run.WithHintProcessor(&hintProcessor))
+	if err != nil {
+		t.Fatalf("NewRunner failed with error: %s", err)
+	}
+	if err := runner.Run(); err != nil {
+		t.Fatalf("Run failed with error: %s", err)
+	}
+	if !reflect.DeepEqual(hintProcessor.executed, []any{uint(2), nil}) {
+		t.Errorf("Wrong hints executed: %v", hintProcessor.executed)
+	}
+}
+
+func TestHintExtensionMerge(t *testing.T) {
+	hintDataMap := map[uint][]any{0: {"a"}, 3: {"b", "c"}}
+	extension := vm.HintExtension{3: {"d"}, 5: {"e"}, 7: {}}
+	extension.Merge(hintDataMap)
+
+	expected := map[uint][]any{0: {"a"}, 3: {"d"}, 5: {"e"}}
+	if !reflect.DeepEqual(hintDataMap, expected) {
+		t.Errorf("Wrong hint data map after merge: %v", hintDataMap)
+	}
+}
+
+func TestGetAccessedAddresses(t *testing.T) {
+	testVm := vm.NewVirtualMachine()
+	testVm.MarkAddressAccessed(memory.NewRelocatable(1, 7))
+	testVm.MarkAddressRangeAccessed(memory.NewRelocatable(1, 2), 3)
+	testVm.MarkAddressAccessed(memory.NewRelocatable(2, 0))
+	testVm.MarkAddressAccessed(memory.NewRelocatable(1, 3))
+
+	expected := []memory.Relocatable{
+		memory.NewRelocatable(1, 2),
+		memory.NewRelocatable(1, 3),
+		memory.NewRelocatable(1, 4),
+		memory.NewRelocatable(1, 7),
+	}
+	if accessed := testVm.GetAccessedAddresses(1); !reflect.DeepEqual(accessed, expected) {
+		t.Errorf("Wrong accessed addresses. Expected: %v, got: %v", expected, accessed)
+	}
+	if accessed := testVm.GetAccessedAddresses(0); len(accessed) != 0 {
+		t.Errorf("No address should be accessed in segment 0, got: %v", accessed)
+	}
+}
+
+// main: [ap] = n, ap++
+// loop: [ap] = [ap - 1] - 1, ap++; jmp loop if [ap - 1] != 0
+//
+//	ret
+func programForStepBenchmark(n uint64) vm.Program {
+	return vmtest.Program(
+		"0x480680017fff8000", strconv.FormatUint(n, 10),
+		"0x482480017fff8000", "-1",
+		"0x20680017fff7fff", "-2",
+		"0x208b7fff7fff7ffe",
+	)
+}
+
+func BenchmarkStepLoop(b *testing.B) {
+	const iterations = 10000
+	program := programForStepBenchmark(iterations)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		runner, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "plain"})
+		if err != nil {
+			b.Fatalf("Run failed with error: %s", err)
+		}
+		if runner.Vm.CurrentStep != 2*iterations+2 {
+			b.Fatalf("Wrong amount of steps: %d", runner.Vm.CurrentStep)
+		}
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	hooks := vm.StepHooks{PreStep: func(*vm.VirtualMachine) error { return nil }}
+	virtualMachine := vm.New(vm.WithHooks(hooks), vm.WithMaxSteps(5), vm.WithRelocationWorkers(2))
+	if virtualMachine.Hooks.PreStep == nil {
+		t.Error("Hooks were not installed")
+	}
+	if virtualMachine.RunResources == nil || !reflect.DeepEqual(*virtualMachine.RunResources, vm.NewRunResources(5)) {
+		t.Errorf("Wrong run resources: %v", virtualMachine.RunResources)
+	}
+	if virtualMachine.RelocationWorkers != 2 {
+		t.Errorf("Wrong number of relocation workers: %d", virtualMachine.RelocationWorkers)
+	}
+}
+
+func TestInsertAtAp(t *testing.T) {
+	virtualMachine := vm.NewVirtualMachine()
+	virtualMachine.Segments.AddSegment()
+	virtualMachine.Segments.AddSegment()
+	virtualMachine.RunContext.Ap = memory.NewRelocatable(1, 3)
+
+	value := memory.NewMaybeRelocatableFelt(lambdaworks.FeltOne())
+	if err := virtualMachine.InsertAtAp(0, value); err != nil {
+		t.Fatalf("InsertAtAp failed with error: %s", err)
+	}
+	if err := virtualMachine.InsertAtAp(-1, value); err != nil {
+		t.Fatalf("InsertAtAp failed with error: %s", err)
+	}
+	for _, addr := range []memory.Relocatable{memory.NewRelocatable(1, 3), memory.NewRelocatable(1, 2)} {
+		if stored, err := virtualMachine.Segments.Memory.Get(addr); err != nil || !stored.IsEqual(value) {
+			t.Errorf("Wrong value at %s: %v, err: %v", addr, stored, err)
+		}
+	}
+
+	// Inserts get the same overwrite & bounds checks as any other insert
+	if err := virtualMachine.InsertAtAp(0, memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero())); err == nil {
+		t.Error("Overwriting memory[ap] should fail")
+	}
+	if err := virtualMachine.InsertAtAp(-4, value); !errors.Is(err, memory.ErrRelocatableNegOffset) {
+		t.Errorf("Expected ErrRelocatableNegOffset, got: %v", err)
+	}
+}
+
+func TestInsertInDst(t *testing.T) {
+	virtualMachine := vm.NewVirtualMachine()
+	virtualMachine.Segments.AddSegment()
+	virtualMachine.Segments.AddSegment()
+	virtualMachine.RunContext.Ap = memory.NewRelocatable(1, 3)
+	virtualMachine.RunContext.Fp = memory.NewRelocatable(1, 1)
+
+	value := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5))
+	if err := virtualMachine.InsertInDst(vm.Instruction{DstReg: vm.FP, Off0: 2}, value); err != nil {
+		t.Fatalf("InsertInDst failed with error: %s", err)
+	}
+	if stored, err := virtualMachine.Segments.Memory.Get(memory.NewRelocatable(1, 3)); err != nil || !stored.IsEqual(value) {
+		t.Errorf("Wrong value at dst: %v, err: %v", stored, err)
+	}
+}
+
+func TestForkStreamedTrace(t *testing.T) {
+	streamedTrace, err := vm.NewStreamedTrace(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewStreamedTrace failed with error: %s", err)
+	}
+	defer streamedTrace.Close()
+	testVm := vm.New(vm.WithStreamedTrace(streamedTrace))
+	if _, err := testVm.Fork(); !errors.Is(err, vm.ErrForkStreamedTrace) {
+		t.Errorf("Expected ErrForkStreamedTrace, got: %v", err)
+	}
+}
+
+func TestForkDoesNotShareState(t *testing.T) {
+	maxSteps := uint(10)
+	testVm := vm.New(vm.WithMaxSteps(maxSteps), vm.WithTracer(vm.NewRingBufferTracer(1)))
+	testVm.Segments.AddSegment()
+	testVm.Trace = append(testVm.Trace, vm.TraceEntry{Pc: memory.NewRelocatable(0, 1)})
+
+	fork, err := testVm.Fork()
+	if err != nil {
+		t.Fatalf("Fork failed with error: %s", err)
+	}
+	fork.RunResources.ConsumeStep()
+	fork.Trace = append(fork.Trace[:0], vm.TraceEntry{Pc: memory.NewRelocatable(0, 2)})
+	fork.Segments.AddSegment()
+
+	if *testVm.RunResources.GetNSteps() != maxSteps {
+		t.Errorf("The fork's run resources should be independent, original steps left: %d", *testVm.RunResources.GetNSteps())
+	}
+	if testVm.Trace[0].Pc != memory.NewRelocatable(0, 1) {
+		t.Error("The fork's trace should be independent")
+	}
+	if testVm.Segments.Memory.NumSegments() != 1 || fork.Segments.Memory.NumSegments() != 2 {
+		t.Errorf("Wrong number of segments, original: %d, fork: %d", testVm.Segments.Memory.NumSegments(), fork.Segments.Memory.NumSegments())
+	}
+	if fork.Tracer != nil || fork.Segments.Memory.Observer != nil {
+		t.Error("The tracer shouldn't be carried over to the fork")
+	}
+}
+
+func TestForkClonesBuiltins(t *testing.T) {
+	testVm := vm.NewVirtualMachine()
+	testVm.Segments.AddSegment()
+	rangeCheck := builtins.NewRangeCheckBuiltinRunner(8)
+	rangeCheck.InitializeSegments(&testVm.Segments)
+	testVm.BuiltinRunners = append(testVm.BuiltinRunners, rangeCheck)
+	rangeCheck.AddValidationRule(&testVm.Segments.Memory)
+
+	fork, err := testVm.Fork()
+	if err != nil {
+		t.Fatalf("Fork failed with error: %s", err)
+	}
+	if fork.BuiltinRunners[0] == testVm.BuiltinRunners[0] {
+		t.Error("The fork's builtin runners should be clones")
+	}
+	// The validation rule still applies to the fork's range check segment
+	invalid := memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-1"))
+	if err := fork.Segments.Memory.Insert(rangeCheck.Base(), invalid); err == nil {
+		t.Error("The fork should validate the range check segment")
+	}
+}
+
+// main: call foo; ret
+// foo:  [ap] = 5, ap++; ret
+func TestCallRetRoundTrip(t *testing.T) {
+	program := vmtest.Program("0x1104800180018000", "3", "0x208b7fff7fff7ffe", "0x480680017fff8000", "5", "0x208b7fff7fff7ffe")
+	runner, err := cairo_run.CairoRunProgram(program, cairo_run.CairoRunConfig{Layout: "plain"})
+	if err != nil {
+		t.Fatalf("CairoRunProgram failed with error: %s", err)
+	}
+	expected := []vm.TraceEntry{
+		{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 2), Fp: memory.NewRelocatable(1, 2)},
+		{Pc: memory.NewRelocatable(0, 3), Ap: memory.NewRelocatable(1, 4), Fp: memory.NewRelocatable(1, 4)},
+		{Pc: memory.NewRelocatable(0, 5), Ap: memory.NewRelocatable(1, 5), Fp: memory.NewRelocatable(1, 4)},
+		// foo's ret restores main's frame, keeping ap
+		{Pc: memory.NewRelocatable(0, 2), Ap: memory.NewRelocatable(1, 5), Fp: memory.NewRelocatable(1, 2)},
+	}
+	if !reflect.DeepEqual(runner.Vm.Trace, expected) {
+		t.Errorf("Wrong trace, expected %+v, got %+v", expected, runner.Vm.Trace)
+	}
+}
+
+// Returns a vm about to execute a ret at (0, 0) with fp = (1, 2), the return-fp & return-pc are written if not nil
+func vmForRetTest(returnFp *memory.MaybeRelocatable, returnPc *memory.MaybeRelocatable) *vm.VirtualMachine {
+	testVm := vm.NewVirtualMachine()
+	testVm.Segments.AddSegment()
+	testVm.Segments.AddSegment()
+	testVm.RunContext = vm.RunContext{Pc: memory.NewRelocatable(0, 0), Ap: memory.NewRelocatable(1, 2), Fp: memory.NewRelocatable(1, 2)}
+	if returnFp != nil {
+		testVm.Segments.Memory.Insert(memory.NewRelocatable(1, 0), returnFp)
+	}
+	if returnPc != nil {
+		testVm.Segments.Memory.Insert(memory.NewRelocatable(1, 1), returnPc)
+	}
+	return testVm
+}
+
+func TestRetMissingReturnValues(t *testing.T) {
+	ret, _ := vm.DecodeInstruction(0x208b7fff7fff7ffe)
+	returnFp := memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 0))
+	returnPc := memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 4))
+
+	_, _, err := vmForRetTest(returnFp, nil).ComputeOperands(ret)
+	if !errors.Is(err, vm.ErrRetMissingReturnPc) {
+		t.Errorf("Expected ErrRetMissingReturnPc, got %v", err)
+	}
+	_, _, err = vmForRetTest(nil, returnPc).ComputeOperands(ret)
+	if !errors.Is(err, vm.ErrRetMissingReturnFp) {
+		t.Errorf("Expected ErrRetMissingReturnFp, got %v", err)
+	}
+}
+
+func TestRetInvalidReturnPc(t *testing.T) {
+	ret, _ := vm.DecodeInstruction(0x208b7fff7fff7ffe)
+	testVm := vmForRetTest(memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(1, 0)), memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(4)))
+	operands, _, err := testVm.ComputeOperands(ret)
+	if err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	if err := testVm.OpcodeAssertions(ret, operands); !errors.Is(err, vm.ErrRetInvalidReturnPc) {
+		t.Errorf("Expected ErrRetInvalidReturnPc, got %v", err)
+	}
+}
+
+func TestRetToEntrypointCaller(t *testing.T) {
+	// Entrypoint runs return to a felt return-fp, which is added to fp
+	ret, _ := vm.DecodeInstruction(0x208b7fff7fff7ffe)
+	testVm := vmForRetTest(memory.NewMaybeRelocatableFelt(lambdaworks.FeltZero()), memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(0, 4)))
+	operands, _, err := testVm.ComputeOperands(ret)
+	if err != nil {
+		t.Fatalf("ComputeOperands failed with error: %s", err)
+	}
+	if err := testVm.OpcodeAssertions(ret, operands); err != nil {
+		t.Errorf("OpcodeAssertions failed with error: %s", err)
+	}
+	if err := testVm.UpdateRegisters(&ret, &operands); err != nil {
+		t.Fatalf("UpdateRegisters failed with error: %s", err)
+	}
+	expected := vm.RunContext{Pc: memory.NewRelocatable(0, 4), Ap: memory.NewRelocatable(1, 2), Fp: memory.NewRelocatable(1, 2)}
+	if testVm.RunContext != expected {
+		t.Errorf("Wrong registers: %+v", testVm.RunContext)
+	}
+}
@@ -0,0 +1,225 @@
+// Fixtures for the tests of hints, builtins & the vm, similar to the vm!/memory! macros of the Rust vm
+//
+//	v := vmtest.NewVMWithSegments(2)
+//	vmtest.SetApFp(v, 3, 3)
+//	vmtest.MustLoadMemory(t, v, "[ (1,0) 5, (1,1) -1, (1,2) (2,0) ]")
+//	... run the hint ...
+//	vmtest.CheckMemory(t, v, "(1,3) 0x10")
+package vmtest
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/pkg/errors"
+)
+
+// Index of the execution segment, where SetApFp places the registers
+const ExecutionSegment = 1
+
+// A memory cell described by a memory string, see ParseMemory
+type Cell struct {
+	Addr  memory.Relocatable
+	Value memory.MaybeRelocatable
+}
+
+// Returns a new vm with n segments
+func NewVMWithSegments(n int) *vm.VirtualMachine {
+	v := vm.NewVirtualMachine()
+	for i := 0; i < n; i++ {
+		v.Segments.AddSegment()
+	}
+	return v
+}
+
+// Sets the registers of v
+func SetRegisters(v *vm.VirtualMachine, pc memory.Relocatable, ap memory.Relocatable, fp memory.Relocatable) {
+	v.RunContext = vm.NewRunContext(pc, ap, fp)
+}
+
+// Places ap & fp at the given offsets of the execution segment, & pc at the start of the program segment
+// This is the usual setup of the hint tests, whose ids are fp based
+func SetApFp(v *vm.VirtualMachine, ap uint, fp uint) {
+	SetRegisters(v, memory.NewRelocatable(0, 0), memory.NewRelocatable(ExecutionSegment, ap), memory.NewRelocatable(ExecutionSegment, fp))
+}
+
+/*
+Returns a program made of the given words, whose main function starts at pc 0
+Words are written as felts are in ParseMemory, usually instructions in hex followed by their immediates in decimal:
+
+	vmtest.Program("0x480680017fff8000", "5", "0x208b7fff7fff7ffe") // [ap] = 5, ap++; ret
+
+Panics if a word is not a felt
+*/
+func Program(words ...string) vm.Program {
+	program := vm.Program{Identifiers: map[string]vm.Identifier{"__main__.main": {PC: 0, Type: "function"}}}
+	for _, word := range words {
+		value, err := parseValue(word)
+		if _, ok := value.GetFelt(); err != nil || !ok {
+			panic(errors.Errorf("Invalid program word %s, expected a felt", word))
+		}
+		program.Data = append(program.Data, value)
+	}
+	return program
+}
+
+// Same as Program, for proof mode programs whose main function starts at mainPc & which run from pc 0 until end
+// (ie: the __start__ & __end__ of the proof mode entrypoint)
+func ProofModeProgram(mainPc uint, end uint, words ...string) vm.Program {
+	program := Program(words...)
+	program.Identifiers["__main__.main"] = vm.Identifier{PC: int(mainPc), Type: "function"}
+	program.Start = 0
+	program.End = end
+	return program
+}
+
+/*
+Parses a list of memory cells, each one written as its address followed by its value
+Addresses & relocatable values are written as (segment,offset), felts as decimal (optionally negative) or 0x prefixed
+hex numbers. Cells may be separated by commas & the list may be enclosed in brackets:
+
+	[ (0,0) 5, (1,2) (2,0), (1,3) -1, (1,4) 0x1f ]
+*/
+func ParseMemory(s string) ([]Cell, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimSuffix(strings.TrimPrefix(s, "["), "]")
+
+	tokens := make([]string, 0)
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == ' ' || c == '\t' || c == '\n' || c == ',':
+			i++
+		case c == '(':
+			end := strings.IndexByte(s[i:], ')')
+			if end == -1 {
+				return nil, errors.Errorf("Unclosed relocatable at %q", s[i:])
+			}
+			tokens = append(tokens, s[i:i+end+1])
+			i += end + 1
+		default:
+			end := strings.IndexAny(s[i:], " \t\n,(")
+			if end == -1 {
+				end = len(s) - i
+			}
+			tokens = append(tokens, s[i:i+end])
+			i += end
+		}
+	}
+	if len(tokens)%2 != 0 {
+		return nil, errors.Errorf("Missing the value of the last cell (%s)", tokens[len(tokens)-1])
+	}
+
+	cells := make([]Cell, 0, len(tokens)/2)
+	for i := 0; i < len(tokens); i += 2 {
+		addr, ok, err := parseRelocatable(tokens[i])
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, errors.Errorf("Expected an address, got %s", tokens[i])
+		}
+		value, err := parseValue(tokens[i+1])
+		if err != nil {
+			return nil, err
+		}
+		cells = append(cells, Cell{Addr: addr, Value: value})
+	}
+	return cells, nil
+}
+
+// Inserts the cells described by s (see ParseMemory) into the memory of v, adding the segments they need
+func LoadMemoryFromString(v *vm.VirtualMachine, s string) error {
+	cells, err := ParseMemory(s)
+	if err != nil {
+		return err
+	}
+	for _, cell := range cells {
+		if cell.Addr.SegmentIndex < 0 {
+			return errors.Errorf("Can't load a cell into the temporary segment %d", cell.Addr.SegmentIndex)
+		}
+		for int(v.Segments.Memory.NumSegments()) <= cell.Addr.SegmentIndex {
+			v.Segments.AddSegment()
+		}
+		value := cell.Value
+		if err := v.Segments.Memory.Insert(cell.Addr, &value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Same as LoadMemoryFromString, failing the test on error
+func MustLoadMemory(t testing.TB, v *vm.VirtualMachine, s string) {
+	t.Helper()
+	if err := LoadMemoryFromString(v, s); err != nil {
+		t.Fatalf("Failed to load memory: %s", err)
+	}
+}
+
+// Checks that the memory of v holds the cells described by s (see ParseMemory), other cells are not checked
+func CheckMemory(t testing.TB, v *vm.VirtualMachine, s string) {
+	t.Helper()
+	cells, err := ParseMemory(s)
+	if err != nil {
+		t.Fatalf("Failed to parse the expected memory: %s", err)
+	}
+	for _, cell := range cells {
+		value, err := v.Segments.Memory.Get(cell.Addr)
+		if err != nil {
+			t.Errorf("Expected %s at %s, got: %s", valueString(cell.Value), cell.Addr.ToString(), err)
+			continue
+		}
+		if *value != cell.Value {
+			t.Errorf("Expected %s at %s, got %s", valueString(cell.Value), cell.Addr.ToString(), valueString(*value))
+		}
+	}
+}
+
+// Parses a (segment,offset) token, returning false if the token is not a relocatable
+func parseRelocatable(token string) (memory.Relocatable, bool, error) {
+	if !strings.HasPrefix(token, "(") {
+		return memory.Relocatable{}, false, nil
+	}
+	segment, offset, ok := strings.Cut(strings.Trim(token, "()"), ",")
+	if !ok {
+		return memory.Relocatable{}, false, errors.Errorf("Invalid relocatable %s, expected (segment,offset)", token)
+	}
+	segmentIndex, err := strconv.Atoi(strings.TrimSpace(segment))
+	if err != nil {
+		return memory.Relocatable{}, false, errors.Errorf("Invalid segment index in %s", token)
+	}
+	offsetValue, err := strconv.ParseUint(strings.TrimSpace(offset), 10, 0)
+	if err != nil {
+		return memory.Relocatable{}, false, errors.Errorf("Invalid offset in %s", token)
+	}
+	return memory.NewRelocatable(segmentIndex, uint(offsetValue)), true, nil
+}
+
+func parseValue(token string) (memory.MaybeRelocatable, error) {
+	addr, ok, err := parseRelocatable(token)
+	if err != nil {
+		return memory.MaybeRelocatable{}, err
+	}
+	if ok {
+		return *memory.NewMaybeRelocatableRelocatable(addr), nil
+	}
+	n, ok := new(big.Int).SetString(token, 0)
+	if !ok {
+		return memory.MaybeRelocatable{}, errors.Errorf("Invalid value %s, expected a felt or a relocatable", token)
+	}
+	felt := lambdaworks.FeltFromBigInt(n.Mod(n, lambdaworks.Prime()))
+	return *memory.NewMaybeRelocatableFelt(felt), nil
+}
+
+func valueString(value memory.MaybeRelocatable) string {
+	if addr, ok := value.GetRelocatable(); ok {
+		return addr.ToString()
+	}
+	felt, _ := value.GetFelt()
+	return felt.ToSignedFeltString()
+}
@@ -0,0 +1,83 @@
+package vmtest_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lambdaclass/cairo-vm.go/pkg/lambdaworks"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/memory"
+	"github.com/lambdaclass/cairo-vm.go/pkg/vm/vmtest"
+)
+
+func TestParseMemory(t *testing.T) {
+	cells, err := vmtest.ParseMemory("[ (0,0) 5, (1,2) (2,0), (1, 3) -1,(1,4) 0x1f ]")
+	if err != nil {
+		t.Fatalf("ParseMemory failed with error: %s", err)
+	}
+	expected := []vmtest.Cell{
+		{memory.NewRelocatable(0, 0), *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(5))},
+		{memory.NewRelocatable(1, 2), *memory.NewMaybeRelocatableRelocatable(memory.NewRelocatable(2, 0))},
+		{memory.NewRelocatable(1, 3), *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-1"))},
+		{memory.NewRelocatable(1, 4), *memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(31))},
+	}
+	if len(cells) != len(expected) {
+		t.Fatalf("Expected %d cells, got %v", len(expected), cells)
+	}
+	for i := range cells {
+		if cells[i] != expected[i] {
+			t.Errorf("Wrong cell %d. Expected %v, got %v", i, expected[i], cells[i])
+		}
+	}
+}
+
+func TestParseMemoryErrors(t *testing.T) {
+	for _, s := range []string{"(0,0)", "5 5", "(0,0) abc", "(0,0 5", "(0) 5", "(0,-1) 5"} {
+		if _, err := vmtest.ParseMemory(s); err == nil {
+			t.Errorf("ParseMemory(%q) should have failed", s)
+		}
+	}
+}
+
+func TestLoadMemoryFromString(t *testing.T) {
+	v := vmtest.NewVMWithSegments(1)
+	vmtest.SetApFp(v, 3, 2)
+	// The segments of the loaded cells are added as needed
+	vmtest.MustLoadMemory(t, v, "[ (0,0) 5, (1,2) (3,0) ]")
+	if v.Segments.Memory.NumSegments() != 2 {
+		t.Errorf("Expected 2 segments, got %d", v.Segments.Memory.NumSegments())
+	}
+	vmtest.CheckMemory(t, v, "(0,0) 5 (1,2) (3,0)")
+
+	if v.RunContext.Ap != memory.NewRelocatable(1, 3) || v.RunContext.Fp != memory.NewRelocatable(1, 2) || v.RunContext.Pc != memory.NewRelocatable(0, 0) {
+		t.Errorf("Wrong registers: %+v", v.RunContext)
+	}
+
+	// Overwriting a cell with a different value fails
+	if err := vmtest.LoadMemoryFromString(v, "(0,0) 6"); err == nil {
+		t.Error("Loading a different value into (0,0) should have failed")
+	}
+}
+
+func TestProgram(t *testing.T) {
+	program := vmtest.ProofModeProgram(4, 2, "0x208b7fff7fff7ffe", "10", "-1")
+	expected := []memory.MaybeRelocatable{
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromHex("0x208b7fff7fff7ffe")),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromUint64(10)),
+		*memory.NewMaybeRelocatableFelt(lambdaworks.FeltFromDecString("-1")),
+	}
+	if !reflect.DeepEqual(program.Data, expected) {
+		t.Errorf("Wrong program data: %v", program.Data)
+	}
+	if main, err := program.GetLabelPc("__main__.main"); err != nil || main != 4 || program.Start != 0 || program.End != 2 {
+		t.Errorf("Wrong entrypoints, main: %d, start: %d, end: %d", main, program.Start, program.End)
+	}
+}
+
+func TestProgramInvalidWord(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Program should panic for words that aren't felts")
+		}
+	}()
+	vmtest.Program("(1,0)")
+}